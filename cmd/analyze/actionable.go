@@ -0,0 +1,59 @@
+package main
+
+import (
+	"path/filepath"
+	"time"
+)
+
+const (
+	// focusMinSize hides entries too small to be worth acting on in focus mode.
+	focusMinSize int64 = 10 << 20
+	// focusOversizedDir is the folder size focus mode calls oversized; files use the
+	// large file threshold.
+	focusOversizedDir int64 = 10 << 30
+)
+
+// focusContext is what focus mode knows beyond the entry itself.
+type focusContext struct {
+	Duplicates map[string]bool // Copies found by the duplicate finder and every folder holding one
+	Now        time.Time
+}
+
+// entryActions lists what can be done with an entry: cleanable, stale, duplicate or
+// oversized. System and tiny entries have none, whatever else applies.
+func entryActions(path string, isDir bool, size int64, touched time.Time, ctx focusContext) []string {
+	if size < focusMinSize || isSIPProtected(path) {
+		return nil
+	}
+	var actions []string
+	if _, ok := safeDeleteCategory(path); ok || isCleanableDir(path) || isHandledByMoClean(path) {
+		actions = append(actions, "cleanable")
+	}
+	if !touched.IsZero() && ctx.Now.Sub(touched) >= staleBuckets[len(staleBuckets)-1].Age {
+		actions = append(actions, "stale")
+	}
+	if ctx.Duplicates[path] {
+		actions = append(actions, "duplicate")
+	}
+	if (isDir && size >= focusOversizedDir) || (!isDir && size >= minLargeFileSize) {
+		actions = append(actions, "oversized")
+	}
+	return actions
+}
+
+// duplicateIndex marks every copy in groups and each folder above one, so focus mode
+// can keep a folder that holds duplicates.
+func duplicateIndex(groups []duplicateGroup) map[string]bool {
+	index := make(map[string]bool)
+	for _, group := range groups {
+		for _, path := range group.Paths {
+			for dir := path; !index[dir]; dir = filepath.Dir(dir) {
+				index[dir] = true
+				if dir == filepath.Dir(dir) {
+					break
+				}
+			}
+		}
+	}
+	return index
+}
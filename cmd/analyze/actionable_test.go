@@ -0,0 +1,46 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEntryActions(t *testing.T) {
+	now := time.Now()
+	ctx := focusContext{Duplicates: duplicateIndex([]duplicateGroup{{Size: 50 << 20, Paths: []string{"/Users/me/Movies/a/clip.mov", "/Users/me/Movies/b/clip.mov"}}}), Now: now}
+	old := now.Add(-2 * staleBuckets[len(staleBuckets)-1].Age)
+	cases := []struct {
+		path    string
+		isDir   bool
+		size    int64
+		touched time.Time
+		want    string
+	}{
+		{"/Users/me/code/app/node_modules", true, 200 << 20, now, "cleanable"},
+		{"/Users/me/Library/Caches/com.example", true, 200 << 20, now, "cleanable"},
+		{"/Users/me/Documents/thesis", true, 200 << 20, old, "stale"},
+		{"/Users/me/Movies", true, 200 << 20, now, "duplicate"},
+		{"/Users/me/Movies/film.mkv", false, 2 << 30, now, "oversized"},
+		{"/Users/me/Documents", true, 200 << 20, now, ""},
+		{"/Users/me/code/app/node_modules", true, 1 << 20, now, ""}, // Already minimal
+		{"/System/Library/Caches", true, 200 << 20, old, ""},        // System path
+	}
+	for _, c := range cases {
+		if got := strings.Join(entryActions(c.path, c.isDir, c.size, c.touched, ctx), ","); got != c.want {
+			t.Errorf("entryActions(%s) = %q, want %q", c.path, got, c.want)
+		}
+	}
+}
+
+func TestDuplicateIndexMarksAncestors(t *testing.T) {
+	index := duplicateIndex([]duplicateGroup{{Paths: []string{"/a/b/c.txt", "/a/d/c.txt"}}})
+	for _, path := range []string{"/a/b/c.txt", "/a/b", "/a/d", "/a", "/"} {
+		if !index[path] {
+			t.Errorf("%s not marked", path)
+		}
+	}
+	if index["/a/e"] {
+		t.Error("unrelated folder marked")
+	}
+}
@@ -0,0 +1,220 @@
+//go:build darwin
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// paletteAction is one entry in the ctrl+k action list. Running it replays Key,
+// so the palette and the key bindings can never disagree about what an action does.
+type paletteAction struct {
+	Group string
+	Title string
+	Key   string
+}
+
+// paletteActions lists every action reachable from the main view; add new bindings here too.
+var paletteActions = []paletteAction{
+	{Group: "Navigate", Title: "Open selected folder", Key: "enter"},
+	{Group: "Navigate", Title: "Look inside an app or other package", Key: "alt+enter"},
+	{Group: "Navigate", Title: "Go back to parent", Key: "b"},
+	{Group: "Navigate", Title: "Drill down to the largest item", Key: "g"},
+	{Group: "Navigate", Title: "Rescan current folder", Key: "r"},
+	{Group: "Navigate", Title: "Page down", Key: "pgdown"},
+	{Group: "Navigate", Title: "Page up", Key: "pgup"},
+	{Group: "Navigate", Title: "Jump to first row", Key: "home"},
+	{Group: "Navigate", Title: "Jump to last row", Key: "end"},
+	{Group: "View", Title: "Toggle large files list", Key: "t"},
+	{Group: "View", Title: "List every item instead of the largest ones", Key: "n"},
+	{Group: "View", Title: "Raise large files threshold", Key: "+"},
+	{Group: "View", Title: "Lower large files threshold", Key: "-"},
+	{Group: "View", Title: "Show all file types in the large files list, code and data included", Key: "V"},
+	{Group: "View", Title: "Hide recently created files from large and stale files", Key: "N"},
+	{Group: "View", Title: "Filter entries by name, size (over 100MB) or age (older:90d)", Key: "/"},
+	{Group: "View", Title: "Show exact byte sizes", Key: "x"},
+	{Group: "View", Title: "Show details of the selected item: dates, owner, permissions", Key: "tab"},
+	{Group: "View", Title: "Toggle scanner worker and last scan usage stats", Key: "D"},
+	{Group: "View", Title: "Focus: show only cleanable, stale, duplicate or oversized items", Key: "w"},
+	{Group: "View", Title: "Toggle shared bytes column (hard links, APFS clones)", Key: "H"},
+	{Group: "View", Title: "Toggle file and folder count columns", Key: "c"},
+	{Group: "View", Title: "Toggle apparent vs on-disk sizes (sparse files, VM disks)", Key: "A"},
+	{Group: "Sort", Title: "Cycle sort mode (size, name, files, accessed, weighted)", Key: "s"},
+	{Group: "Select", Title: "Toggle selection of current row", Key: " "},
+	{Group: "Select", Title: "Select all visible rows", Key: "a"},
+	{Group: "Select", Title: "Select every filter match", Key: "ctrl+a"},
+	{Group: "Select", Title: "Select app data whose app is uninstalled", Key: "u"},
+	{Group: "Select", Title: "Pin selected folder to the top", Key: "P"},
+	{Group: "Select", Title: "Stage selection for review later", Key: "z"},
+	{Group: "Select", Title: "Review staged items", Key: "Z"},
+	{Group: "Analyze", Title: "Explain what makes this big", Key: "?"},
+	{Group: "Analyze", Title: "Find duplicate files", Key: "d"},
+	{Group: "Analyze", Title: "Find older installer and disk image versions", Key: "v"},
+	{Group: "Analyze", Title: "Break down Time Machine backup by snapshot", Key: "B"},
+	{Group: "Analyze", Title: "Rank local APFS snapshots by space held", Key: "L"},
+	{Group: "Analyze", Title: "Compare with System Settings storage categories", Key: "C"},
+	{Group: "Analyze", Title: "Total each app's containers, groups and caches", Key: "K"},
+	{Group: "Analyze", Title: "Show which size provider measured each overview folder", Key: "I"},
+	{Group: "Analyze", Title: "Group data untouched for months by age", Key: "U"},
+	{Group: "Analyze", Title: "List folders the scan could not read", Key: "!"},
+	{Group: "Analyze", Title: "Show what grew or shrank since earlier scans", Key: "y"},
+	{Group: "Analyze", Title: "Plan a migration: what to copy, archive or recreate", Key: "M"},
+	{Group: "Settings", Title: "Edit disk usage alert thresholds", Key: "W"},
+	{Group: "Analyze", Title: "Run saved searches", Key: "m"},
+	{Group: "Export", Title: "Export listing as ncdu JSON", Key: "e"},
+	{Group: "Files", Title: "Open with default app", Key: "o"},
+	{Group: "Files", Title: "Preview with Quick Look", Key: "ctrl+y"},
+	{Group: "Files", Title: "Reveal in Finder", Key: "f"},
+	{Group: "Files", Title: "Delete selection", Key: "delete"},
+	{Group: "Files", Title: "Empty trash on selected volume", Key: "E"},
+	{Group: "Files", Title: "Evict from iCloud Drive: free the local copy, keep it in the cloud", Key: "J"},
+	{Group: "Files", Title: "Compress a folder: APFS compression in place, or a .tar.zst archive", Key: "%"},
+	{Group: "Files", Title: "Move to another volume: copy, check, then remove here", Key: ">"},
+	{Group: "Files", Title: "Preview and run mo clean by category", Key: "p"},
+	{Group: "Files", Title: "Show Homebrew's footprint and run brew cleanup", Key: "i"},
+	{Group: "Files", Title: "Clear npm, Yarn, pnpm, pip, Cargo, Go or Gradle caches", Key: "Y"},
+	{Group: "Files", Title: "Uninstall the selected app with its Library data", Key: "ctrl+u"},
+	{Group: "Files", Title: "Organize files with move rules", Key: "ctrl+o"},
+	{Group: "Settings", Title: "Decrease scan workers", Key: "["},
+	{Group: "Settings", Title: "Increase scan workers", Key: "]"},
+	{Group: "Settings", Title: "Open expert command prompt", Key: ":"},
+	{Group: "App", Title: "Quit", Key: "q"},
+}
+
+// actionPaletteState is the open ctrl+k palette.
+type actionPaletteState struct {
+	Query    string
+	Selected int
+}
+
+// fuzzyScore matches query as a case-insensitive subsequence of text. Contiguous
+// runs and word starts score higher; ok is false when query does not match.
+func fuzzyScore(query, text string) (score int, ok bool) {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return 0, true
+	}
+	q := []rune(query)
+	qi, run := 0, 0
+	prev := ' '
+	for _, r := range strings.ToLower(text) {
+		if qi < len(q) && r == q[qi] {
+			score++
+			if run > 0 {
+				score += 2 * run
+			}
+			if !unicode.IsLetter(prev) && !unicode.IsDigit(prev) {
+				score += 3
+			}
+			run++
+			qi++
+		} else {
+			run = 0
+		}
+		prev = r
+	}
+	return score, qi == len(q)
+}
+
+// matchActions ranks actions for query, keeping list order between equal scores.
+func matchActions(actions []paletteAction, query string) []paletteAction {
+	type scored struct {
+		action paletteAction
+		score  int
+	}
+	var matches []scored
+	for _, action := range actions {
+		if score, ok := fuzzyScore(query, action.Group+" "+action.Title); ok {
+			matches = append(matches, scored{action, score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+	result := make([]paletteAction, len(matches))
+	for i, match := range matches {
+		result[i] = match.action
+	}
+	return result
+}
+
+// keyLabel is how an action's key is shown in the palette.
+func keyLabel(key string) string {
+	switch key {
+	case " ":
+		return "Space"
+	case "delete":
+		return "⌫"
+	case "enter":
+		return "Enter"
+	case "tab":
+		return "Tab"
+	}
+	if rest, ok := strings.CutPrefix(key, "ctrl+"); ok {
+		return "^" + strings.ToUpper(rest)
+	}
+	return strings.ToUpper(key[:1]) + key[1:]
+}
+
+// updateActionPaletteKey handles keys while the ctrl+k palette is open.
+func (m model) updateActionPaletteKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	matches := matchActions(paletteActions, m.actionPalette.Query)
+	switch msg.Type {
+	case tea.KeyEsc, tea.KeyCtrlK:
+		m.actionPalette = nil
+	case tea.KeyCtrlC:
+		return m.quit()
+	case tea.KeyUp, tea.KeyCtrlP:
+		m.actionPalette.Selected = max(m.actionPalette.Selected-1, 0)
+	case tea.KeyDown, tea.KeyCtrlN:
+		m.actionPalette.Selected = min(m.actionPalette.Selected+1, max(len(matches)-1, 0))
+	case tea.KeyEnter:
+		selected := m.actionPalette.Selected
+		m.actionPalette = nil
+		if len(matches) == 0 {
+			m.status = "No matching action"
+			return m, nil
+		}
+		return m.updateKey(keyMsgFor(matches[min(selected, len(matches)-1)].Key))
+	case tea.KeyBackspace:
+		if runes := []rune(m.actionPalette.Query); len(runes) > 0 {
+			m.actionPalette.Query = string(runes[:len(runes)-1])
+			m.actionPalette.Selected = 0
+		}
+	case tea.KeySpace:
+		m.actionPalette.Query += " "
+		m.actionPalette.Selected = 0
+	case tea.KeyRunes:
+		m.actionPalette.Query += string(msg.Runes)
+		m.actionPalette.Selected = 0
+	}
+	return m, nil
+}
+
+// renderActionPalette draws the query line and the best matches.
+func renderActionPalette(state *actionPaletteState) string {
+	const maxShown = 8
+	var b strings.Builder
+	fmt.Fprintf(&b, "%sAction:%s %s▌  %s↑↓ choose | Enter run | Esc close%s\n",
+		colorCyan, colorReset, state.Query, colorGray, colorReset)
+	matches := matchActions(paletteActions, state.Query)
+	if len(matches) == 0 {
+		fmt.Fprintf(&b, "  %sNo matching action%s\n", colorGray, colorReset)
+		return b.String()
+	}
+	selected := min(state.Selected, len(matches)-1)
+	start := max(0, selected-maxShown+1)
+	for i := start; i < min(len(matches), start+maxShown); i++ {
+		action := matches[i]
+		line := fmt.Sprintf("%-9s %-48s %s", action.Group, action.Title, keyLabel(action.Key))
+		if i == selected {
+			fmt.Fprintf(&b, "%s▶ %s%s\n", colorCyan, line, colorReset)
+		} else {
+			fmt.Fprintf(&b, "  %s%s%s\n", colorGray, line, colorReset)
+		}
+	}
+	return b.String()
+}
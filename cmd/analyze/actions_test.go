@@ -0,0 +1,53 @@
+//go:build darwin
+
+package main
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestFuzzyScore(t *testing.T) {
+	if _, ok := fuzzyScore("xprt", "Export listing as ncdu JSON"); !ok {
+		t.Fatalf("subsequence should match")
+	}
+	if _, ok := fuzzyScore("zzz", "Export listing"); ok {
+		t.Fatalf("missing letters should not match")
+	}
+	contiguous, _ := fuzzyScore("sort", "Cycle sort mode")
+	scattered, _ := fuzzyScore("sort", "Show exact byte sizes or totals")
+	if contiguous <= scattered {
+		t.Fatalf("contiguous match should rank higher: %d <= %d", contiguous, scattered)
+	}
+}
+
+func TestMatchActionsRanksBestFirst(t *testing.T) {
+	matches := matchActions(paletteActions, "export")
+	if len(matches) == 0 || matches[0].Key != "e" {
+		t.Fatalf("expected export action first, got %+v", matches)
+	}
+	if got := matchActions(paletteActions, ""); len(got) != len(paletteActions) {
+		t.Fatalf("empty query should list every action")
+	}
+}
+
+func TestActionPaletteRunsSelectedAction(t *testing.T) {
+	m := newModel("/tmp/root", false)
+	m.entries = []dirEntry{{Name: "a", Path: "/tmp/root/a", Size: 1}}
+
+	next, _ := m.updateKey(tea.KeyMsg{Type: tea.KeyCtrlK})
+	m = next.(model)
+	if m.actionPalette == nil {
+		t.Fatalf("ctrl+k should open the palette")
+	}
+	for _, r := range "select all" {
+		next, _ = m.updateKey(keyMsgFor(string(r)))
+		m = next.(model)
+	}
+	next, _ = m.updateKey(tea.KeyMsg{Type: tea.KeyEnter})
+	m = next.(model)
+	if m.actionPalette != nil || !m.multiSelected["/tmp/root/a"] {
+		t.Fatalf("expected select-all to run, palette=%v selected=%v", m.actionPalette, m.multiSelected)
+	}
+}
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultAgentAddr     = ":7879"
+	agentInventoryPath   = "/v1/inventory"
+	agentMaxFailedPairs  = 5
+	agentPairingCodeSize = 6
+)
+
+// agentServer shares read-only inventories of root with clients holding the pairing code.
+type agentServer struct {
+	root   string
+	code   string
+	mu     sync.Mutex
+	failed int
+	locked chan struct{}
+}
+
+func newAgentServer(root, code string) *agentServer {
+	return &agentServer{root: root, code: code, locked: make(chan struct{})}
+}
+
+// newPairingCode returns a random numeric code shown on the agent's screen.
+func newPairingCode() (string, error) {
+	limit := big.NewInt(1)
+	for i := 0; i < agentPairingCodeSize; i++ {
+		limit.Mul(limit, big.NewInt(10))
+	}
+	n, err := rand.Int(rand.Reader, limit)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", agentPairingCodeSize, n), nil
+}
+
+// authorize checks the bearer code; too many wrong codes lock the agent for good.
+func (s *agentServer) authorize(r *http.Request) bool {
+	code := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failed >= agentMaxFailedPairs {
+		return false
+	}
+	if subtle.ConstantTimeCompare([]byte(code), []byte(s.code)) == 1 {
+		return true
+	}
+	s.failed++
+	if s.failed == agentMaxFailedPairs {
+		close(s.locked)
+	}
+	return false
+}
+
+// resolve maps a requested path onto the shared root, rejecting anything outside it.
+func (s *agentServer) resolve(requested string) (string, bool) {
+	if requested == "" {
+		return s.root, true
+	}
+	if !filepath.IsAbs(requested) {
+		requested = filepath.Join(s.root, requested)
+	}
+	clean := filepath.Clean(requested)
+	rel, err := filepath.Rel(s.root, clean)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return clean, true
+}
+
+func (s *agentServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != agentInventoryPath || r.Method != http.MethodGet {
+		http.NotFound(w, r)
+		return
+	}
+	if !s.authorize(r) {
+		http.Error(w, "invalid pairing code", http.StatusUnauthorized)
+		return
+	}
+	path, ok := s.resolve(r.URL.Query().Get("path"))
+	if !ok {
+		http.Error(w, "path is outside the shared folder", http.StatusForbidden)
+		return
+	}
+	if _, err := os.Stat(path); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "%s  %s browsed %s\n", time.Now().Format("15:04:05"), r.RemoteAddr, path)
+	w.Header().Set("Content-Type", "text/tab-separated-values")
+	fmt.Fprintf(w, "root\t%s\n", path)
+	_ = writeInventory(w, path)
+}
+
+// selfSignedCert creates an in-memory certificate that lives only as long as the agent.
+func selfSignedCert() (tls.Certificate, string, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, "", err
+	}
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		return tls.Certificate{}, "", err
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: "mole agent"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, "", err
+	}
+	cert := tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+	return cert, certFingerprint(der), nil
+}
+
+// certFingerprint is a short SHA-256 digest users can compare by eye.
+func certFingerprint(der []byte) string {
+	sum := sha256.Sum256(der)
+	hexed := strings.ToUpper(hex.EncodeToString(sum[:8]))
+	var parts []string
+	for i := 0; i < len(hexed); i += 4 {
+		parts = append(parts, hexed[i:i+4])
+	}
+	return strings.Join(parts, "-")
+}
+
+// runAgent serves root until interrupted or locked by repeated wrong codes.
+func runAgent(addr, root string) error {
+	cert, fingerprint, err := selfSignedCert()
+	if err != nil {
+		return err
+	}
+	code, err := newPairingCode()
+	if err != nil {
+		return err
+	}
+	listener, err := tls.Listen("tcp", addr, &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS13,
+	})
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+
+	agent := newAgentServer(root, code)
+	server := &http.Server{Handler: agent, ReadHeaderTimeout: 10 * time.Second}
+
+	fmt.Fprintf(os.Stderr, "Sharing a read-only size listing of %s\n", root)
+	fmt.Fprintf(os.Stderr, "Listening on %s\n", listener.Addr())
+	fmt.Fprintf(os.Stderr, "Pairing code: %s\n", code)
+	fmt.Fprintf(os.Stderr, "Fingerprint:  %s\n", fingerprint)
+	fmt.Fprintln(os.Stderr, "Only share the code with the person analyzing this Mac. Press Ctrl+C to stop.")
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- server.Serve(listener) }()
+	select {
+	case err := <-errCh:
+		return err
+	case <-agent.locked:
+		_ = server.Close()
+		return fmt.Errorf("stopped after %d wrong pairing codes", agentMaxFailedPairs)
+	}
+}
+
+// agentHostPort adds the default agent port when addr has none.
+func agentHostPort(addr string) string {
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		return net.JoinHostPort(addr, strings.TrimPrefix(defaultAgentAddr, ":"))
+	}
+	return addr
+}
+
+// agentTLSConfig accepts the agent's ephemeral certificate only when its fingerprint
+// matches; an empty fingerprint records what the agent shows into seen instead.
+func agentTLSConfig(fingerprint string, seen *string) *tls.Config {
+	return &tls.Config{
+		// The agent's certificate is self-signed; trust comes from the pinned fingerprint.
+		InsecureSkipVerify: true,
+		MinVersion:         tls.VersionTLS13,
+		VerifyConnection: func(state tls.ConnectionState) error {
+			if len(state.PeerCertificates) == 0 {
+				return fmt.Errorf("agent sent no certificate")
+			}
+			got := certFingerprint(state.PeerCertificates[0].Raw)
+			if seen != nil {
+				*seen = got
+			}
+			if fingerprint != "" && !strings.EqualFold(got, fingerprint) {
+				return fmt.Errorf("fingerprint mismatch: agent shows %s", got)
+			}
+			return nil
+		},
+	}
+}
+
+// probeAgentFingerprint completes a TLS handshake without sending anything and returns
+// the fingerprint of the certificate the agent presented.
+func probeAgentFingerprint(addr string) (string, error) {
+	var seen string
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	conn, err := tls.DialWithDialer(dialer, "tcp", agentHostPort(addr), agentTLSConfig("", &seen))
+	if err != nil {
+		return "", err
+	}
+	_ = conn.Close()
+	return seen, nil
+}
+
+// fetchAgentInventory downloads a listing from a paired agent. The pairing code is only
+// sent over a connection whose certificate matches fingerprint.
+func fetchAgentInventory(addr, code, fingerprint, path string) (*inventoryTree, error) {
+	if fingerprint == "" {
+		return nil, fmt.Errorf("the agent fingerprint is required before sending the pairing code")
+	}
+	addr = agentHostPort(addr)
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: agentTLSConfig(fingerprint, nil)}}
+
+	target := url.URL{Scheme: "https", Host: addr, Path: agentInventoryPath}
+	if path != "" {
+		target.RawQuery = url.Values{"path": {path}}.Encode()
+	}
+	req, err := http.NewRequest(http.MethodGet, target.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+code)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := bufio.NewReader(resp.Body).ReadString('\n')
+		return nil, fmt.Errorf("agent refused: %s", strings.TrimSpace(msg))
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	header, err := reader.ReadString('\n')
+	if err != nil || !strings.HasPrefix(header, "root\t") {
+		return nil, fmt.Errorf("unexpected response from agent %s", addr)
+	}
+	root := strings.TrimSuffix(strings.TrimPrefix(header, "root\t"), "\n")
+	return readInventory(reader, "agent://"+addr, root)
+}
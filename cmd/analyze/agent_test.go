@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bufio"
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func agentRequest(t *testing.T, s *agentServer, code, path string) *httptest.ResponseRecorder {
+	t.Helper()
+	target := agentInventoryPath
+	if path != "" {
+		target += "?path=" + path
+	}
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	req.Header.Set("Authorization", "Bearer "+code)
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAgentServesInventoryWithPairingCode(t *testing.T) {
+	root := t.TempDir()
+	writeFileWithSize(t, filepath.Join(root, "docs", "report.pdf"), 4096)
+
+	s := newAgentServer(root, "123456")
+	rec := agentRequest(t, s, "123456", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	reader := bufio.NewReader(rec.Body)
+	header, _ := reader.ReadString('\n')
+	if header != "root\t"+root+"\n" {
+		t.Fatalf("unexpected header %q", header)
+	}
+	tree, err := readInventory(reader, "agent://test", root)
+	if err != nil {
+		t.Fatalf("readInventory: %v", err)
+	}
+	result, err := tree.scanResultFor(root)
+	if err != nil {
+		t.Fatalf("scanResultFor: %v", err)
+	}
+	if len(result.Entries) != 1 || result.Entries[0].Name != "docs" {
+		t.Fatalf("expected docs entry, got %+v", result.Entries)
+	}
+}
+
+func TestAgentLocksAfterWrongCodes(t *testing.T) {
+	s := newAgentServer(t.TempDir(), "123456")
+	for i := 0; i < agentMaxFailedPairs; i++ {
+		if rec := agentRequest(t, s, "000000", ""); rec.Code != http.StatusUnauthorized {
+			t.Fatalf("attempt %d: expected 401, got %d", i, rec.Code)
+		}
+	}
+	select {
+	case <-s.locked:
+	default:
+		t.Fatal("expected agent to lock after repeated wrong codes")
+	}
+	if rec := agentRequest(t, s, "123456", ""); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected locked agent to refuse the right code, got %d", rec.Code)
+	}
+}
+
+func TestAgentRejectsPathsOutsideRoot(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "inside"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	s := newAgentServer(root, "123456")
+
+	if rec := agentRequest(t, s, "123456", filepath.Dir(root)); rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for parent dir, got %d", rec.Code)
+	}
+	if rec := agentRequest(t, s, "123456", "../"); rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for relative escape, got %d", rec.Code)
+	}
+	if rec := agentRequest(t, s, "123456", "inside"); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 for relative child, got %d", rec.Code)
+	}
+}
+
+func TestPairingCodeFormat(t *testing.T) {
+	code, err := newPairingCode()
+	if err != nil {
+		t.Fatalf("newPairingCode: %v", err)
+	}
+	if len(code) != agentPairingCodeSize || strings.Trim(code, "0123456789") != "" {
+		t.Fatalf("unexpected code %q", code)
+	}
+}
+
+func TestFetchAgentInventoryWithholdsCodeFromUnknownCertificate(t *testing.T) {
+	var gotCode atomic.Bool
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			gotCode.Store(true)
+		}
+		http.Error(w, "no", http.StatusUnauthorized)
+	}))
+	server.TLS = &tls.Config{MinVersion: tls.VersionTLS13}
+	server.StartTLS()
+	defer server.Close()
+	addr := strings.TrimPrefix(server.URL, "https://")
+
+	if _, err := fetchAgentInventory(addr, "123456", "", ""); err == nil {
+		t.Fatalf("expected an error without a fingerprint")
+	}
+	if _, err := fetchAgentInventory(addr, "123456", "00:11:22", ""); err == nil || !strings.Contains(err.Error(), "fingerprint mismatch") {
+		t.Fatalf("expected a fingerprint mismatch, got %v", err)
+	}
+	if gotCode.Load() {
+		t.Fatalf("pairing code reached a server with the wrong certificate")
+	}
+
+	seen, err := probeAgentFingerprint(addr)
+	if err != nil {
+		t.Fatalf("probe: %v", err)
+	}
+	if want := certFingerprint(server.Certificate().Raw); seen != want {
+		t.Fatalf("probe saw %s, want %s", seen, want)
+	}
+	if gotCode.Load() {
+		t.Fatalf("probing must not send the pairing code")
+	}
+}
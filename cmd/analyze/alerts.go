@@ -0,0 +1,346 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// alertsFileName holds disk usage alert thresholds, in the config.toml subset:
+//
+//	free_space_floor = "20GB"  # Warn when the startup volume has less free
+//	growth_per_day = "5GB"     # Warn when used space grows faster
+//
+//	[ceilings]                 # Warn when a folder grows past its size
+//	"~/Downloads" = "50GB"
+//
+// The "W" editor in mo analyze writes it; scheduled runs (--warm) check it.
+const alertsFileName = "alerts.toml"
+
+// alertStateFile remembers the last used-space reading so growth can be measured.
+const alertStateFile = "alert_state.json"
+
+// alertGrowthMinInterval keeps a quick second run from turning noise into a daily rate.
+const alertGrowthMinInterval = time.Hour
+
+// pathCeiling warns when Path grows past Max.
+type pathCeiling struct {
+	Path string
+	Max  int64
+}
+
+// alertRules are the thresholds; zero turns a rule off.
+type alertRules struct {
+	FreeFloor    int64
+	GrowthPerDay int64
+	Ceilings     []pathCeiling
+}
+
+// alertEvent is a rule that tripped.
+type alertEvent struct {
+	Title   string
+	Message string
+}
+
+// alertState is what the previous check saw.
+type alertState struct {
+	Used int64     `json:"used"`
+	At   time.Time `json:"at"`
+}
+
+// alertReadings are the numbers rules are checked against.
+type alertReadings struct {
+	Free  int64
+	Used  int64
+	Sizes map[string]int64 // Ceiling paths that could be measured
+}
+
+func getAlertsPath(name string) (string, error) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, name), nil
+}
+
+// parseAlertRules decodes alerts.toml, expanding ~ in ceiling paths against home.
+func parseAlertRules(data, home string) (alertRules, error) {
+	values, err := parseConfigTOML(data)
+	if err != nil {
+		return alertRules{}, err
+	}
+	size := func(key string, value configValue) (int64, error) {
+		switch v := value.(type) {
+		case int64:
+			return v, nil
+		case string:
+			if strings.EqualFold(v, "off") {
+				return 0, nil
+			}
+			return parseByteSize(v)
+		}
+		return 0, fmt.Errorf("%s expects a size such as \"20GB\"", key)
+	}
+	var rules alertRules
+	for key, value := range values {
+		switch {
+		case key == "free_space_floor":
+			rules.FreeFloor, err = size(key, value)
+		case key == "growth_per_day":
+			rules.GrowthPerDay, err = size(key, value)
+		case strings.HasPrefix(key, "ceilings."):
+			path := strings.TrimPrefix(key, "ceilings.")
+			if path == "~" || strings.HasPrefix(path, "~/") {
+				path = filepath.Join(home, strings.TrimPrefix(path, "~"))
+			}
+			if !filepath.IsAbs(path) {
+				return alertRules{}, fmt.Errorf("ceiling %q must be an absolute or ~ path", path)
+			}
+			var limit int64
+			if limit, err = size(key, value); err == nil && limit > 0 {
+				rules.Ceilings = append(rules.Ceilings, pathCeiling{Path: filepath.Clean(path), Max: limit})
+			}
+		default:
+			err = fmt.Errorf("unknown setting %q", key)
+		}
+		if err != nil {
+			return alertRules{}, err
+		}
+	}
+	sort.Slice(rules.Ceilings, func(a, b int) bool { return rules.Ceilings[a].Path < rules.Ceilings[b].Path })
+	return rules, nil
+}
+
+// formatSizeSetting writes size in the largest whole unit, so the file reads "20GB".
+func formatSizeSetting(size int64) string {
+	for _, unit := range []struct {
+		suffix string
+		scale  int64
+	}{{"TB", 1 << 40}, {"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}} {
+		if size >= unit.scale && size%unit.scale == 0 {
+			return fmt.Sprintf("%d%s", size/unit.scale, unit.suffix)
+		}
+	}
+	return strconv.FormatInt(size, 10)
+}
+
+// encodeAlertRules renders rules as alerts.toml, writing paths under home with ~.
+func encodeAlertRules(rules alertRules, home string) string {
+	setting := func(size int64) string {
+		if size <= 0 {
+			return `"off"`
+		}
+		return strconv.Quote(formatSizeSetting(size))
+	}
+	var b strings.Builder
+	b.WriteString("# Disk usage alerts, edited with \"W\" in mo analyze\n")
+	fmt.Fprintf(&b, "free_space_floor = %s\n", setting(rules.FreeFloor))
+	fmt.Fprintf(&b, "growth_per_day = %s\n", setting(rules.GrowthPerDay))
+	if len(rules.Ceilings) > 0 {
+		b.WriteString("\n[ceilings]\n")
+		for _, ceiling := range rules.Ceilings {
+			path := ceiling.Path
+			if home != "" && (path == home || strings.HasPrefix(path, home+"/")) {
+				path = "~" + strings.TrimPrefix(path, home)
+			}
+			fmt.Fprintf(&b, "%s = %s\n", strconv.Quote(path), setting(ceiling.Max))
+		}
+	}
+	return b.String()
+}
+
+func loadAlertRules() (alertRules, error) {
+	path, err := getAlertsPath(alertsFileName)
+	if err != nil {
+		return alertRules{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return alertRules{}, nil
+		}
+		return alertRules{}, err
+	}
+	rules, err := parseAlertRules(string(data), os.Getenv("HOME"))
+	if err != nil {
+		return alertRules{}, fmt.Errorf("%s: %v", displayPath(path), err)
+	}
+	return rules, nil
+}
+
+func saveAlertRules(rules alertRules) error {
+	path, err := getAlertsPath(alertsFileName)
+	if err != nil {
+		return err
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, []byte(encodeAlertRules(rules, os.Getenv("HOME"))), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// evaluateAlerts returns the rules readings trip. Growth needs a previous reading at
+// least alertGrowthMinInterval old; without one it stays quiet.
+func evaluateAlerts(rules alertRules, readings alertReadings, previous alertState, now time.Time) []alertEvent {
+	var events []alertEvent
+	if rules.FreeFloor > 0 && readings.Free < rules.FreeFloor {
+		events = append(events, alertEvent{
+			Title:   "Low disk space",
+			Message: fmt.Sprintf("%s free, below your %s floor", humanizeBytes(readings.Free), humanizeBytes(rules.FreeFloor)),
+		})
+	}
+	if elapsed := now.Sub(previous.At); rules.GrowthPerDay > 0 && !previous.At.IsZero() && elapsed >= alertGrowthMinInterval {
+		perDay := int64(float64(readings.Used-previous.Used) / elapsed.Hours() * 24)
+		if perDay > rules.GrowthPerDay {
+			events = append(events, alertEvent{
+				Title:   "Disk filling quickly",
+				Message: fmt.Sprintf("Used space is growing %s a day, above your %s limit", humanizeBytes(perDay), humanizeBytes(rules.GrowthPerDay)),
+			})
+		}
+	}
+	for _, ceiling := range rules.Ceilings {
+		if size, ok := readings.Sizes[ceiling.Path]; ok && size > ceiling.Max {
+			events = append(events, alertEvent{
+				Title:   "Folder over its limit",
+				Message: fmt.Sprintf("%s is %s, over your %s limit", displayPath(ceiling.Path), humanizeBytes(size), humanizeBytes(ceiling.Max)),
+			})
+		}
+	}
+	return events
+}
+
+// volumeAvailableBytes is the space available to this user on the volume holding path.
+func volumeAvailableBytes(path string) (int64, error) {
+	var fs syscall.Statfs_t
+	if err := syscall.Statfs(path, &fs); err != nil {
+		return 0, err
+	}
+	return int64(uint64(fs.Bavail) * uint64(fs.Bsize)), nil
+}
+
+// readAlertReadings measures what rules need, giving ceiling folders until ctx is done.
+// Stored overview sizes are used when fresh, du otherwise.
+func readAlertReadings(ctx context.Context, rules alertRules) alertReadings {
+	readings := alertReadings{Sizes: make(map[string]int64)}
+	readings.Free, _ = volumeAvailableBytes("/")
+	readings.Used, _ = volumeUsedBytes("/")
+	for _, ceiling := range rules.Ceilings {
+		if age, ok := overviewSnapshotAge(ceiling.Path); ok && age < warmRefreshAge {
+			if size, err := loadStoredOverviewSize(ceiling.Path); err == nil {
+				readings.Sizes[ceiling.Path] = size
+				continue
+			}
+		}
+		if ctx.Err() != nil {
+			continue
+		}
+		if size, err := getDirectorySizeFromDuContext(ctx, ceiling.Path, ""); err == nil {
+			readings.Sizes[ceiling.Path] = size
+		}
+	}
+	return readings
+}
+
+func loadAlertState() alertState {
+	var state alertState
+	path, err := getAlertsPath(alertStateFile)
+	if err != nil {
+		return state
+	}
+	if data, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(data, &state)
+	}
+	return state
+}
+
+func saveAlertState(state alertState) {
+	path, err := getAlertsPath(alertStateFile)
+	if err != nil {
+		return
+	}
+	if data, err := json.Marshal(state); err == nil {
+		_ = os.WriteFile(path, data, 0644)
+	}
+}
+
+// checkAlerts is the scheduled check: it measures, notifies about every tripped rule and
+// remembers used space for the next growth reading.
+func checkAlerts(ctx context.Context) []alertEvent {
+	rules, err := loadAlertRules()
+	if err != nil || (rules.FreeFloor == 0 && rules.GrowthPerDay == 0 && len(rules.Ceilings) == 0) {
+		return nil
+	}
+	readings := readAlertReadings(ctx, rules)
+	now := time.Now()
+	previous := loadAlertState()
+	events := evaluateAlerts(rules, readings, previous, now)
+	if readings.Used > 0 && (previous.At.IsZero() || now.Sub(previous.At) >= alertGrowthMinInterval) {
+		saveAlertState(alertState{Used: readings.Used, At: now})
+	}
+	for _, event := range events {
+		_ = postNotification(event)
+	}
+	return events
+}
+
+// postNotification shows event in Notification Center.
+func postNotification(event alertEvent) error {
+	script := fmt.Sprintf("display notification %s with title %s subtitle %s",
+		strconv.Quote(event.Message), strconv.Quote("Mole"), strconv.Quote(event.Title))
+	ctx, cancel := context.WithTimeout(context.Background(), openCommandTimeout)
+	defer cancel()
+	return exec.CommandContext(ctx, "osascript", "-e", script).Run()
+}
+
+// alertTestMsg reports a test notification sent from the editor.
+type alertTestMsg struct {
+	Event alertEvent
+	Err   error
+}
+
+// testAlertCmd checks only the rules in rules and always notifies: with the real alert
+// when one trips, otherwise with today's numbers so the user sees how close they are.
+func testAlertCmd(rules alertRules) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), duTimeout)
+		defer cancel()
+		readings := readAlertReadings(ctx, rules)
+		events := evaluateAlerts(rules, readings, loadAlertState(), time.Now())
+		event := alertEvent{Title: "Test alert", Message: describeAlertReadings(rules, readings)}
+		if len(events) > 0 {
+			event = events[0]
+			event.Title = "Test: " + event.Title
+		}
+		return alertTestMsg{Event: event, Err: postNotification(event)}
+	}
+}
+
+// describeAlertReadings says where readings stand against rules that did not trip.
+func describeAlertReadings(rules alertRules, readings alertReadings) string {
+	var parts []string
+	if rules.FreeFloor > 0 {
+		parts = append(parts, fmt.Sprintf("%s free, floor %s", humanizeBytes(readings.Free), humanizeBytes(rules.FreeFloor)))
+	}
+	if rules.GrowthPerDay > 0 {
+		parts = append(parts, fmt.Sprintf("%s used, growth is measured between scheduled checks", humanizeBytes(readings.Used)))
+	}
+	for _, ceiling := range rules.Ceilings {
+		if size, ok := readings.Sizes[ceiling.Path]; ok {
+			parts = append(parts, fmt.Sprintf("%s is %s of %s", displayPath(ceiling.Path), humanizeBytes(size), humanizeBytes(ceiling.Max)))
+		} else {
+			parts = append(parts, fmt.Sprintf("%s could not be measured", displayPath(ceiling.Path)))
+		}
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAlertRulesRoundTrip(t *testing.T) {
+	rules := alertRules{
+		FreeFloor: 20 << 30,
+		Ceilings: []pathCeiling{
+			{Path: "/Users/me/Downloads", Max: 50 << 30},
+			{Path: "/Volumes/Work/Renders", Max: 1536 << 20},
+		},
+	}
+	text := encodeAlertRules(rules, "/Users/me")
+	got, err := parseAlertRules(text, "/Users/me")
+	if err != nil {
+		t.Fatalf("parseAlertRules(%q): %v", text, err)
+	}
+	if !reflect.DeepEqual(got, rules) {
+		t.Fatalf("round trip = %+v, want %+v\n%s", got, rules, text)
+	}
+
+	if _, err := parseAlertRules("free_space_floor = \"lots\"\n", "/Users/me"); err == nil {
+		t.Fatalf("expected an invalid size to be rejected")
+	}
+	if _, err := parseAlertRules("[ceilings]\n\"Downloads\" = \"1GB\"\n", "/Users/me"); err == nil {
+		t.Fatalf("expected a relative ceiling path to be rejected")
+	}
+}
+
+func TestEvaluateAlerts(t *testing.T) {
+	now := time.Now()
+	rules := alertRules{FreeFloor: 10 << 30, GrowthPerDay: 5 << 30, Ceilings: []pathCeiling{{Path: "/data", Max: 1 << 30}, {Path: "/gone", Max: 1}}}
+	readings := alertReadings{Free: 8 << 30, Used: 112 << 30, Sizes: map[string]int64{"/data": 2 << 30}}
+
+	events := evaluateAlerts(rules, readings, alertState{Used: 100 << 30, At: now.Add(-24 * time.Hour)}, now)
+	var titles []string
+	for _, event := range events {
+		titles = append(titles, event.Title)
+	}
+	want := []string{"Low disk space", "Disk filling quickly", "Folder over its limit"}
+	if !reflect.DeepEqual(titles, want) {
+		t.Fatalf("events = %v, want %v", titles, want)
+	}
+
+	// A reading minutes old is too close to judge growth from.
+	events = evaluateAlerts(alertRules{GrowthPerDay: 1}, readings, alertState{Used: 0, At: now.Add(-time.Minute)}, now)
+	if len(events) != 0 {
+		t.Fatalf("growth should wait for an older reading: %+v", events)
+	}
+}
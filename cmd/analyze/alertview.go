@@ -0,0 +1,251 @@
+//go:build darwin
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// alertsView is the "W" editor for the thresholds scheduled runs alert on.
+type alertsView struct {
+	Rules    alertRules
+	Selected int // Free floor, growth, each ceiling, then "add a ceiling"
+	Editing  bool
+	Input    string
+	Err      error // alerts.toml could not be read; saving would overwrite it
+}
+
+const (
+	alertRowFree   = 0
+	alertRowGrowth = 1
+	alertRowFirst  = 2 // First ceiling
+)
+
+func (v *alertsView) rowCount() int {
+	return alertRowFirst + len(v.Rules.Ceilings) + 1
+}
+
+// ceiling returns the index of the ceiling under the cursor, or -1.
+func (v *alertsView) ceiling() int {
+	if i := v.Selected - alertRowFirst; i >= 0 && i < len(v.Rules.Ceilings) {
+		return i
+	}
+	return -1
+}
+
+// selectedRule is the rule under the cursor alone, for test-firing it.
+func (v *alertsView) selectedRule() alertRules {
+	switch v.Selected {
+	case alertRowFree:
+		return alertRules{FreeFloor: v.Rules.FreeFloor}
+	case alertRowGrowth:
+		return alertRules{GrowthPerDay: v.Rules.GrowthPerDay}
+	}
+	if i := v.ceiling(); i >= 0 {
+		return alertRules{Ceilings: []pathCeiling{v.Rules.Ceilings[i]}}
+	}
+	return alertRules{}
+}
+
+func (v *alertsView) selectedValue() int64 {
+	switch v.Selected {
+	case alertRowFree:
+		return v.Rules.FreeFloor
+	case alertRowGrowth:
+		return v.Rules.GrowthPerDay
+	}
+	if i := v.ceiling(); i >= 0 {
+		return v.Rules.Ceilings[i].Max
+	}
+	return 0
+}
+
+func (m model) openAlerts() (tea.Model, tea.Cmd) {
+	rules, err := loadAlertRules()
+	m.alerts = &alertsView{Rules: rules, Err: err}
+	if err != nil {
+		m.status = fmt.Sprintf("Unable to read alerts: %v", err)
+	}
+	return m, nil
+}
+
+// saveAlerts writes the rules and reports the outcome in the status line.
+func (m *model) saveAlerts(done string) {
+	if m.alerts.Err != nil {
+		m.status = "Fix alerts.toml by hand first; saving would overwrite it"
+		return
+	}
+	if err := saveAlertRules(m.alerts.Rules); err != nil {
+		m.status = fmt.Sprintf("Unable to save alerts: %v", err)
+		return
+	}
+	m.status = done
+}
+
+// updateAlertsKey handles keys while the alert editor is open.
+func (m model) updateAlertsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	v := m.alerts
+	if v.Editing {
+		return m.updateAlertInput(msg)
+	}
+	switch msg.String() {
+	case "q", "Q", "ctrl+c":
+		return m.quit()
+	case "esc", "b", "left", "h", "W":
+		m.alerts = nil
+	case "up", "k":
+		v.Selected = max(v.Selected-1, 0)
+	case "down", "j":
+		v.Selected = min(v.Selected+1, v.rowCount()-1)
+	case "enter", "right", "l":
+		if v.Selected == v.rowCount()-1 {
+			if m.inOverviewMode() || m.inventory != nil {
+				m.status = "Open the folder to watch first, then add its ceiling here"
+				return m, nil
+			}
+			for i, ceiling := range v.Rules.Ceilings {
+				if ceiling.Path == m.path {
+					v.Selected = alertRowFirst + i
+					m.status = "This folder already has a ceiling"
+					return m, nil
+				}
+			}
+			v.Rules.Ceilings = append(v.Rules.Ceilings, pathCeiling{Path: m.path})
+			v.Selected = alertRowFirst + len(v.Rules.Ceilings) - 1
+		}
+		v.Editing = true
+		v.Input = ""
+		if value := v.selectedValue(); value > 0 {
+			v.Input = formatSizeSetting(value)
+		}
+	case "delete", "backspace", "x":
+		if i := v.ceiling(); i >= 0 {
+			v.Rules.Ceilings = append(v.Rules.Ceilings[:i], v.Rules.Ceilings[i+1:]...)
+			v.Selected = min(v.Selected, v.rowCount()-1)
+			m.saveAlerts("Ceiling removed")
+		} else if v.Selected <= alertRowGrowth {
+			m.setAlertValue(0)
+		}
+	case "t":
+		rule := v.selectedRule()
+		if rule.FreeFloor == 0 && rule.GrowthPerDay == 0 && len(rule.Ceilings) == 0 {
+			m.status = "Set a threshold before test-firing it"
+			return m, nil
+		}
+		m.status = "Sending a test alert..."
+		return m, testAlertCmd(rule)
+	}
+	return m, nil
+}
+
+// updateAlertInput edits the size under the cursor; "off" or empty turns the rule off.
+func (m model) updateAlertInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	v := m.alerts
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m.quit()
+	case tea.KeyEsc:
+		v.Editing = false
+		// A ceiling just added with no size is dropped again.
+		if i := v.ceiling(); i >= 0 && v.Rules.Ceilings[i].Max == 0 {
+			v.Rules.Ceilings = append(v.Rules.Ceilings[:i], v.Rules.Ceilings[i+1:]...)
+		}
+	case tea.KeyEnter:
+		input := strings.TrimSpace(v.Input)
+		var size int64
+		if input != "" && !strings.EqualFold(input, "off") {
+			parsed, err := parseByteSize(input)
+			if err != nil || parsed <= 0 {
+				m.status = fmt.Sprintf("%q is not a size; try 20GB, 500MB or off", input)
+				return m, nil
+			}
+			size = parsed
+		}
+		v.Editing = false
+		m.setAlertValue(size)
+	case tea.KeyBackspace:
+		if runes := []rune(v.Input); len(runes) > 0 {
+			v.Input = string(runes[:len(runes)-1])
+		}
+	case tea.KeyRunes:
+		v.Input += string(msg.Runes)
+	}
+	return m, nil
+}
+
+// setAlertValue stores size for the rule under the cursor; zero turns it off, and a
+// ceiling with no size is removed.
+func (m *model) setAlertValue(size int64) {
+	v := m.alerts
+	switch v.Selected {
+	case alertRowFree:
+		v.Rules.FreeFloor = size
+	case alertRowGrowth:
+		v.Rules.GrowthPerDay = size
+	default:
+		i := v.ceiling()
+		if i < 0 {
+			return
+		}
+		if size == 0 {
+			v.Rules.Ceilings = append(v.Rules.Ceilings[:i], v.Rules.Ceilings[i+1:]...)
+			m.saveAlerts("Ceiling removed")
+			return
+		}
+		v.Rules.Ceilings[i].Max = size
+	}
+	if size == 0 {
+		m.saveAlerts("Alert turned off")
+		return
+	}
+	m.saveAlerts(fmt.Sprintf("Saved, alerts at %s", humanizeBytes(size)))
+}
+
+// renderAlerts lists every threshold with its value, editing one in place.
+func (m model) renderAlerts() string {
+	v := m.alerts
+	var b strings.Builder
+	fmt.Fprintf(&b, "%sDisk usage alerts:%s %schecked by mo analyze --warm from a login item or cron%s\n\n",
+		colorCyan, colorReset, colorGray, colorReset)
+
+	nameWidth := calculateNameWidth(m.width) + 10
+	row := func(i int, label, value string) {
+		prefix, color := "   ", ""
+		if i == v.Selected {
+			prefix, color = fmt.Sprintf(" %s%s▶%s ", colorCyan, colorBold, colorReset), colorCyan
+			if v.Editing {
+				value = fmt.Sprintf("%s%s▌%s", colorYellow, v.Input, colorReset)
+			}
+		}
+		fmt.Fprintf(&b, "%s%s%s%s %s\n", prefix, color, padName(truncateMiddle(label, nameWidth), nameWidth), colorReset, value)
+	}
+	setting := func(size int64) string {
+		if size <= 0 {
+			return fmt.Sprintf("%soff%s", colorGray, colorReset)
+		}
+		return humanizeBytes(size)
+	}
+
+	row(alertRowFree, "Free space below", setting(v.Rules.FreeFloor))
+	row(alertRowGrowth, "Used space growing faster than, per day", setting(v.Rules.GrowthPerDay))
+	fmt.Fprintf(&b, "\n   %sFolders larger than:%s\n", colorBold, colorReset)
+	for i, ceiling := range v.Rules.Ceilings {
+		row(alertRowFirst+i, displayPath(ceiling.Path), setting(ceiling.Max))
+	}
+	addLabel := "+ Add a ceiling for " + displayPath(m.path)
+	if m.inOverviewMode() {
+		addLabel = "+ Open a folder to add a ceiling for it"
+	}
+	row(v.rowCount()-1, addLabel, "")
+
+	fmt.Fprintln(&b)
+	if v.Editing {
+		fmt.Fprintf(&b, "%sType a size such as 20GB, or off | Enter Save | Esc Cancel%s\n", colorGray, colorReset)
+	} else {
+		fmt.Fprintf(&b, "%s↑↓ | Enter Edit | X Turn off | T Test alert | W/← Back | Q Quit%s\n", colorGray, colorReset)
+	}
+	return b.String()
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/gob"
 	"os"
 	"path/filepath"
@@ -47,7 +48,7 @@ func TestScanPathConcurrentBasic(t *testing.T) {
 	var filesScanned, dirsScanned, bytesScanned int64
 	current := ""
 
-	result, err := scanPathConcurrent(root, &filesScanned, &dirsScanned, &bytesScanned, &current)
+	result, err := scanPathConcurrent(context.Background(), root, &filesScanned, &dirsScanned, &bytesScanned, &current, nil)
 	if err != nil {
 		t.Fatalf("scanPathConcurrent returned error: %v", err)
 	}
@@ -348,7 +349,7 @@ func TestScanPathPermissionError(t *testing.T) {
 	current := ""
 
 	// Scanning the locked dir itself should fail.
-	_, err := scanPathConcurrent(lockedDir, &files, &dirs, &bytes, &current)
+	_, err := scanPathConcurrent(context.Background(), lockedDir, &files, &dirs, &bytes, &current, nil)
 	if err == nil {
 		t.Fatalf("expected error scanning locked directory, got nil")
 	}
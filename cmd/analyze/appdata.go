@@ -0,0 +1,198 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// appDataKind is one place macOS keeps a sandboxed app's data.
+type appDataKind struct {
+	Label string
+	Root  string // Home-relative folder whose children are named after the app
+	Group bool   // Children are app groups shared by several of one vendor's apps
+}
+
+// appDataKinds lists where each app's data lives, the container first. Only apps that
+// have a container are grouped; caches of unsandboxed apps stay in the regular listing.
+var appDataKinds = []appDataKind{
+	{Label: "container", Root: "Library/Containers"},
+	{Label: "group container", Root: "Library/Group Containers", Group: true},
+	{Label: "caches", Root: "Library/Caches"},
+	{Label: "web data", Root: "Library/HTTPStorages"},
+	{Label: "web data", Root: "Library/WebKit"},
+	{Label: "saved state", Root: "Library/Saved Application State"},
+	{Label: "scripts", Root: "Library/Application Scripts"},
+}
+
+// teamIDPrefix is the developer team ID group containers start with, as in "UBF8T346G9.Office".
+var teamIDPrefix = regexp.MustCompile(`^[A-Z0-9]{10}\.`)
+
+// appDataLocation is one folder counted toward an app.
+type appDataLocation struct {
+	Kind string
+	Path string
+	Size int64
+}
+
+// appData is everything one app keeps across its container, groups and caches.
+type appData struct {
+	ID        string // Bundle ID, or the app group when no installed container claims it
+	Size      int64
+	Locations []appDataLocation // Largest first
+}
+
+type appDataMsg struct {
+	Apps []appData
+	Err  error
+}
+
+// appDataOwner returns the app id belongs to: itself, or the app whose bundle ID it
+// extends, so "com.apple.Notes.SharingExtension" and its own container count toward
+// "com.apple.Notes". apps must be lower-case.
+func appDataOwner(id string, apps []string) (string, bool) {
+	id = strings.ToLower(id)
+	owner := ""
+	for _, app := range apps {
+		if (id == app || strings.HasPrefix(id, app+".")) && (owner == "" || len(app) < len(owner)) {
+			owner = app
+		}
+	}
+	return owner, owner != ""
+}
+
+// groupContainerOwner matches an app group to the app it belongs to. Groups are named
+// "group.<bundle ID>" or "<team ID>.<prefix>"; the app sharing the longest leading run
+// of name components owns it. Groups no container matches, such as a vendor-wide
+// "UBF8T346G9.Office", are returned as their own app.
+func groupContainerOwner(group string, apps []string) string {
+	name := strings.ToLower(teamIDPrefix.ReplaceAllString(group, ""))
+	name = strings.TrimPrefix(strings.TrimPrefix(name, "group."), "groups.")
+	if owner, ok := appDataOwner(name, apps); ok {
+		return owner
+	}
+	parts := strings.Split(name, ".")
+	best, bestShared := "", 0
+	for _, app := range apps {
+		appParts := strings.Split(app, ".")
+		shared := 0
+		for shared < len(parts) && shared < len(appParts) && parts[shared] == appParts[shared] {
+			shared++
+		}
+		// A shared vendor prefix alone ("com.apple") does not pin down an app.
+		if shared == len(parts) && shared > 2 && shared > bestShared {
+			best, bestShared = app, shared
+		}
+	}
+	if owner, ok := appDataOwner(best, apps); ok {
+		return owner
+	}
+	return group
+}
+
+// appDataLocations finds every per-app folder under home and the app it belongs to.
+func appDataLocations(home string) (map[string][]appDataLocation, error) {
+	containers, err := os.ReadDir(filepath.Join(home, appDataKinds[0].Root))
+	if err != nil {
+		return nil, err
+	}
+	var apps []string
+	names := make(map[string]string) // Bundle IDs as the container spells them
+	for _, container := range containers {
+		if container.IsDir() {
+			id := strings.ToLower(container.Name())
+			apps = append(apps, id)
+			names[id] = container.Name()
+		}
+	}
+
+	owned := make(map[string][]appDataLocation)
+	for _, kind := range appDataKinds {
+		children, err := os.ReadDir(filepath.Join(home, kind.Root))
+		if err != nil {
+			continue
+		}
+		for _, child := range children {
+			if !child.IsDir() || strings.HasPrefix(child.Name(), ".") {
+				continue
+			}
+			owner := ""
+			if kind.Group {
+				owner = groupContainerOwner(child.Name(), apps)
+			} else if id, ok := appDataOwner(child.Name(), apps); ok {
+				owner = id
+			} else {
+				continue
+			}
+			path := filepath.Join(home, kind.Root, child.Name())
+			if isExcludedPath(home, path) {
+				continue
+			}
+			if name, ok := names[owner]; ok {
+				owner = name
+			}
+			owned[owner] = append(owned[owner], appDataLocation{Kind: kind.Label, Path: path})
+		}
+	}
+	return owned, nil
+}
+
+// finishAppData totals each app and sorts apps and their folders largest first,
+// dropping apps whose folders are all empty.
+func finishAppData(owned map[string][]appDataLocation) []appData {
+	apps := make([]appData, 0, len(owned))
+	for id, locations := range owned {
+		app := appData{ID: id}
+		for _, location := range locations {
+			if location.Size > 0 {
+				app.Size += location.Size
+				app.Locations = append(app.Locations, location)
+			}
+		}
+		if app.Size == 0 {
+			continue
+		}
+		sort.Slice(app.Locations, func(a, b int) bool { return app.Locations[a].Size > app.Locations[b].Size })
+		apps = append(apps, app)
+	}
+	sort.Slice(apps, func(a, b int) bool {
+		if apps[a].Size != apps[b].Size {
+			return apps[a].Size > apps[b].Size
+		}
+		return apps[a].ID < apps[b].ID
+	})
+	return apps
+}
+
+// measureAppDataCmd sizes every app's folders with du, a few at a time.
+func measureAppDataCmd(home string) tea.Cmd {
+	return func() tea.Msg {
+		owned, err := appDataLocations(home)
+		if err != nil {
+			return appDataMsg{Err: err}
+		}
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, 4)
+		for _, locations := range owned {
+			for i := range locations {
+				wg.Add(1)
+				go func(location *appDataLocation) {
+					defer wg.Done()
+					sem <- struct{}{}
+					size, err := getDirectorySizeFromDu(location.Path)
+					<-sem
+					if err == nil {
+						location.Size = size
+					}
+				}(&locations[i])
+			}
+		}
+		wg.Wait()
+		return appDataMsg{Apps: finishAppData(owned)}
+	}
+}
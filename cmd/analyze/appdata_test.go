@@ -0,0 +1,61 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGroupContainerOwner(t *testing.T) {
+	apps := []string{"com.apple.notes", "com.apple.notes.sharingextension", "com.microsoft.word", "com.tinyspeck.slackmacgap"}
+	tests := map[string]string{
+		"group.com.apple.notes":                "com.apple.notes",
+		"243LU875E5.groups.com.apple.notes":    "com.apple.notes",
+		"BQR82RBBHL.com.tinyspeck.slackmacgap": "com.tinyspeck.slackmacgap",
+		"UBF8T346G9.Office":                    "UBF8T346G9.Office",
+		"group.com.apple.calendar":             "group.com.apple.calendar",
+	}
+	for group, want := range tests {
+		if got := groupContainerOwner(group, apps); got != want {
+			t.Errorf("groupContainerOwner(%q) = %q, want %q", group, got, want)
+		}
+	}
+}
+
+func TestAppDataRollsUpExtensionsAndCaches(t *testing.T) {
+	home := t.TempDir()
+	for _, dir := range []string{
+		"Library/Containers/com.apple.Notes",
+		"Library/Containers/com.apple.Notes.SharingExtension",
+		"Library/Containers/com.example.Editor",
+		"Library/Group Containers/group.com.apple.notes",
+		"Library/Caches/com.apple.Notes",
+		"Library/Caches/com.unsandboxed.App",
+		"Library/Saved Application State/com.example.Editor.savedState",
+	} {
+		if err := os.MkdirAll(filepath.Join(home, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	owned, err := appDataLocations(home)
+	if err != nil {
+		t.Fatalf("appDataLocations: %v", err)
+	}
+	if len(owned) != 2 || len(owned["com.apple.Notes"]) != 4 || len(owned["com.example.Editor"]) != 2 {
+		t.Fatalf("unexpected grouping: %+v", owned)
+	}
+
+	for id := range owned {
+		for i := range owned[id] {
+			owned[id][i].Size = int64(10 * (i + 1))
+		}
+	}
+	owned["com.example.Editor"][1].Size = 0
+	apps := finishAppData(owned)
+	if len(apps) != 2 || apps[0].ID != "com.apple.Notes" || apps[0].Size != 100 || apps[0].Locations[0].Size != 40 {
+		t.Fatalf("unexpected totals: %+v", apps)
+	}
+	if len(apps[1].Locations) != 1 {
+		t.Fatalf("empty folders should be dropped: %+v", apps[1])
+	}
+}
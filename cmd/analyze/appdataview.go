@@ -0,0 +1,150 @@
+//go:build darwin
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// appDataView is the "K" screen totalling each sandboxed app's data across its
+// container, app groups and caches.
+type appDataView struct {
+	Apps     []appData
+	Scanning bool
+	Err      error
+	Selected int  // Index into rows()
+	Expanded bool // Show every app's folders under it
+}
+
+// appDataRow is an app (Path empty) or one of its folders.
+type appDataRow struct {
+	App  int
+	Kind string
+	Path string
+	Size int64
+}
+
+func (v *appDataView) rows() []appDataRow {
+	var rows []appDataRow
+	for i, app := range v.Apps {
+		rows = append(rows, appDataRow{App: i, Size: app.Size})
+		if !v.Expanded {
+			continue
+		}
+		for _, location := range app.Locations {
+			rows = append(rows, appDataRow{App: i, Kind: location.Kind, Path: location.Path, Size: location.Size})
+		}
+	}
+	return rows
+}
+
+func (m model) openAppData() (tea.Model, tea.Cmd) {
+	if m.inventory != nil {
+		m.status = fmt.Sprintf("%s is a read-only listing", m.inventory.Source)
+		return m, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		m.status = "Unable to find your home folder"
+		return m, nil
+	}
+	m.appData = &appDataView{Scanning: true}
+	m.status = "Measuring app data..."
+	return m, tea.Batch(measureAppDataCmd(home), tickCmd())
+}
+
+// updateAppDataKey handles keys while the per-app breakdown is open.
+func (m model) updateAppDataKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	v := m.appData
+	rows := v.rows()
+	switch msg.String() {
+	case "q", "Q", "ctrl+c":
+		return m.quit()
+	case "esc", "b", "left", "h", "K":
+		m.appData = nil
+	case "up", "k":
+		v.Selected = max(v.Selected-1, 0)
+	case "down", "j":
+		v.Selected = min(v.Selected+1, max(len(rows)-1, 0))
+	case " ":
+		// Keep the cursor on the same app while its folders appear or disappear.
+		if v.Selected < len(rows) {
+			app := rows[v.Selected].App
+			v.Expanded = !v.Expanded
+			for i, row := range v.rows() {
+				if row.App == app && row.Path == "" {
+					v.Selected = i
+					break
+				}
+			}
+		}
+	case "enter", "right", "l":
+		// Browse the folder, or an app's largest folder, with the regular scanner.
+		if v.Scanning || v.Selected >= len(rows) {
+			return m, nil
+		}
+		row := rows[v.Selected]
+		path := row.Path
+		if path == "" {
+			path = v.Apps[row.App].Locations[0].Path
+		}
+		m.appData = nil
+		return m.openDir(path)
+	}
+	return m, nil
+}
+
+// renderAppData lists apps largest first, each with the folders it spans when expanded.
+func (m model) renderAppData() string {
+	v := m.appData
+	var b strings.Builder
+	if v.Scanning {
+		fmt.Fprintf(&b, "%s%s%s%s Measuring app data...\n",
+			colorCyan, colorBold, spinnerFrames[m.spinner], colorReset)
+		return b.String()
+	}
+	if v.Err != nil || len(v.Apps) == 0 {
+		fmt.Fprintf(&b, "  %sNo sandboxed app data found in ~/Library/Containers%s\n\n", colorGray, colorReset)
+		fmt.Fprintf(&b, "%sK/← Back | Q Quit%s\n", colorGray, colorReset)
+		return b.String()
+	}
+
+	var total int64
+	for _, app := range v.Apps {
+		total += app.Size
+	}
+	fmt.Fprintf(&b, "%sApp data:%s %s%s%s across %d apps\n\n",
+		colorCyan, colorReset, colorYellow, humanizeBytes(total), colorReset, len(v.Apps))
+
+	rows := v.rows()
+	viewport := calculateViewport(m.height, true)
+	nameWidth := calculateNameWidth(m.width) + 20
+	start := max(0, v.Selected-viewport+1)
+	for i := start; i < min(len(rows), start+viewport); i++ {
+		row := rows[i]
+		prefix, color := "   ", ""
+		if i == v.Selected {
+			prefix, color = fmt.Sprintf(" %s%s▶%s ", colorCyan, colorBold, colorReset), colorCyan
+		}
+		if row.Path == "" {
+			app := v.Apps[row.App]
+			percent := float64(row.Size) / float64(total) * 100
+			fmt.Fprintf(&b, "%s%s %5.1f%%  |  %s%s%s%s %10s  %s%d locations%s\n",
+				prefix, coloredProgressBar(row.Size, total, percent), percent,
+				color, colorBold, padName(trimNameWithWidth(app.ID, nameWidth), nameWidth), colorReset,
+				humanizeBytes(row.Size), colorGray, len(app.Locations), colorReset)
+			continue
+		}
+		label := fmt.Sprintf("%-16s %s", row.Kind, displayPath(row.Path))
+		fmt.Fprintf(&b, "%s      %s%s%s %s%10s%s\n",
+			prefix, color, padName(truncateMiddle(label, nameWidth), nameWidth), colorReset, colorGray, humanizeBytes(row.Size), colorReset)
+	}
+
+	fmt.Fprintln(&b)
+	fmt.Fprintf(&b, "%s↑↓ | Space Folders | Enter Browse | K/← Back | Q Quit%s\n", colorGray, colorReset)
+	return b.String()
+}
@@ -0,0 +1,339 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// appAttributionRoots are home folders whose children are named after the app that
+// wrote them, by bundle ID or by app name.
+var appAttributionRoots = []string{"Library/Caches", "Library/Application Support"}
+
+// applicationFolders are searched one level deep for installed app bundles.
+var applicationFolders = []string{
+	"/Applications", "/Applications/Utilities",
+	"/System/Applications", "/System/Applications/Utilities",
+	"Applications", // Under home
+}
+
+// bundleIDPattern matches reverse-DNS names such as "com.tinyspeck.slackmacgap".
+var bundleIDPattern = regexp.MustCompile(`^[A-Za-z0-9-]+(\.[A-Za-z0-9_-]+){2,}$`)
+
+// quarantineSamples bounds how many recently modified files are checked for the app
+// that wrote them when the folder name says nothing.
+const quarantineSamples = 5
+
+// installedApp is an app bundle found in the Applications folders.
+type installedApp struct {
+	BundleID string
+	Name     string
+	Icon     string // CFBundleIconFile or CFBundleIconName
+	Path     string
+}
+
+// appOwner is the app a cache or support folder belongs to.
+type appOwner struct {
+	BundleID  string
+	Name      string // App name, or the bundle ID when the app is not installed
+	Icon      string
+	Installed bool
+	System    bool // com.apple data with no app of its own, part of macOS
+	FromFiles bool // Found from file metadata rather than the folder name
+}
+
+// orphaned reports data whose app is no longer installed.
+func (o appOwner) orphaned() bool { return !o.Installed && !o.System }
+
+// describe is the owner as explain shows it: "Slack (com.tinyspeck.slackmacgap, icon
+// electron.icns), installed".
+func (o appOwner) describe() string {
+	var details []string
+	if o.BundleID != "" && o.BundleID != o.Name {
+		details = append(details, o.BundleID)
+	}
+	if o.Icon != "" {
+		details = append(details, "icon "+o.Icon)
+	}
+	text := o.Name
+	if len(details) > 0 {
+		text += " (" + strings.Join(details, ", ") + ")"
+	}
+	switch {
+	case o.System:
+		return text + ", part of macOS"
+	case o.Installed:
+		text += ", installed"
+	default:
+		text += ", not installed"
+	}
+	if o.FromFiles {
+		text += ", going by the files it wrote"
+	}
+	return text
+}
+
+// appIndex looks installed apps up by bundle ID and by name.
+type appIndex struct {
+	byID   map[string]installedApp // Lower-case bundle ID
+	byName map[string]installedApp // Lower-case app name
+	ids    []string
+}
+
+func newAppIndex(apps []installedApp) appIndex {
+	index := appIndex{byID: make(map[string]installedApp), byName: make(map[string]installedApp)}
+	for _, app := range apps {
+		id := strings.ToLower(app.BundleID)
+		if _, seen := index.byID[id]; !seen {
+			index.byID[id] = app
+			index.ids = append(index.ids, id)
+		}
+		if _, seen := index.byName[strings.ToLower(app.Name)]; !seen {
+			index.byName[strings.ToLower(app.Name)] = app
+		}
+	}
+	sort.Strings(index.ids)
+	return index
+}
+
+// parseInfoPlist reads the fields attribution needs from an Info.plist converted to JSON.
+func parseInfoPlist(data []byte, appPath string) (installedApp, bool) {
+	var info struct {
+		ID          string `json:"CFBundleIdentifier"`
+		Name        string `json:"CFBundleName"`
+		DisplayName string `json:"CFBundleDisplayName"`
+		IconFile    string `json:"CFBundleIconFile"`
+		IconName    string `json:"CFBundleIconName"`
+	}
+	if err := json.Unmarshal(data, &info); err != nil || info.ID == "" {
+		return installedApp{}, false
+	}
+	app := installedApp{BundleID: info.ID, Name: info.DisplayName, Icon: info.IconFile, Path: appPath}
+	if app.Name == "" {
+		app.Name = info.Name
+	}
+	if app.Name == "" {
+		app.Name = strings.TrimSuffix(filepath.Base(appPath), ".app")
+	}
+	if app.Icon == "" {
+		app.Icon = info.IconName
+	}
+	return app, true
+}
+
+func readInstalledApp(appPath string) (installedApp, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), mdlsTimeout)
+	defer cancel()
+	plist := filepath.Join(appPath, "Contents", "Info.plist")
+	out, err := exec.CommandContext(ctx, "plutil", "-convert", "json", "-o", "-", plist).Output()
+	if err != nil {
+		return installedApp{}, false
+	}
+	return parseInfoPlist(out, appPath)
+}
+
+// findInstalledApps reads every app bundle in the Applications folders, a few at a time.
+func findInstalledApps(home string) []installedApp {
+	var bundles []string
+	for _, folder := range applicationFolders {
+		if !filepath.IsAbs(folder) {
+			folder = filepath.Join(home, folder)
+		}
+		children, err := os.ReadDir(folder)
+		if err != nil {
+			continue
+		}
+		for _, child := range children {
+			if strings.HasSuffix(child.Name(), ".app") {
+				bundles = append(bundles, filepath.Join(folder, child.Name()))
+			}
+		}
+	}
+	apps := make([]installedApp, len(bundles))
+	found := make([]bool, len(bundles))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, 8)
+	for i, bundle := range bundles {
+		wg.Add(1)
+		go func(i int, bundle string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			apps[i], found[i] = readInstalledApp(bundle)
+			<-sem
+		}(i, bundle)
+	}
+	wg.Wait()
+	var installed []installedApp
+	for i, app := range apps {
+		if found[i] {
+			installed = append(installed, app)
+		}
+	}
+	return installed
+}
+
+var (
+	installedAppsOnce  sync.Once
+	installedAppsIndex appIndex
+)
+
+// loadInstalledApps reads the installed apps once per session.
+func loadInstalledApps(home string) appIndex {
+	installedAppsOnce.Do(func() {
+		installedAppsIndex = newAppIndex(findInstalledApps(home))
+	})
+	return installedAppsIndex
+}
+
+// isAppAttributionFolder reports whether dir lists per-app folders.
+func isAppAttributionFolder(dir, home string) bool {
+	for _, root := range appAttributionRoots {
+		if dir == filepath.Join(home, root) {
+			return true
+		}
+	}
+	return false
+}
+
+func ownerFromApp(app installedApp) appOwner {
+	return appOwner{BundleID: app.BundleID, Name: app.Name, Icon: app.Icon, Installed: true}
+}
+
+// ownerForID finds the installed app a bundle ID or one of its helpers belongs to.
+func (idx appIndex) ownerForID(id string) appOwner {
+	if owner, ok := appDataOwner(id, idx.ids); ok {
+		return ownerFromApp(idx.byID[owner])
+	}
+	if strings.HasPrefix(strings.ToLower(id), "com.apple.") {
+		return appOwner{BundleID: id, Name: "macOS", System: true}
+	}
+	return appOwner{BundleID: id, Name: id}
+}
+
+// attribute names the app behind path: by a bundle ID folder name, by an app name, or
+// else by the downloading app recorded on the files most recently written inside.
+func (idx appIndex) attribute(path string, agentOf func(string) string) (appOwner, bool) {
+	name := filepath.Base(path)
+	if bundleIDPattern.MatchString(name) {
+		return idx.ownerForID(name), true
+	}
+	if app, ok := idx.byName[strings.ToLower(name)]; ok {
+		return ownerFromApp(app), true
+	}
+	for _, file := range recentFiles(path, quarantineSamples) {
+		if app, ok := idx.byName[strings.ToLower(agentOf(file))]; ok {
+			owner := ownerFromApp(app)
+			owner.FromFiles = true
+			return owner, true
+		}
+	}
+	return appOwner{}, false
+}
+
+// recentFiles returns up to n of the most recently modified files directly inside
+// path or one folder down.
+func recentFiles(path string, n int) []string {
+	type candidate struct {
+		path    string
+		modTime int64
+	}
+	var files []candidate
+	var visit func(dir string, depth int)
+	visit = func(dir string, depth int) {
+		children, err := os.ReadDir(dir)
+		if err != nil {
+			return
+		}
+		for _, child := range children {
+			childPath := filepath.Join(dir, child.Name())
+			if child.IsDir() {
+				if depth > 0 {
+					visit(childPath, depth-1)
+				}
+				continue
+			}
+			if info, err := child.Info(); err == nil && info.Mode().IsRegular() {
+				files = append(files, candidate{childPath, info.ModTime().UnixNano()})
+			}
+		}
+	}
+	visit(path, 1)
+	sort.Slice(files, func(a, b int) bool { return files[a].modTime > files[b].modTime })
+	paths := make([]string, 0, min(n, len(files)))
+	for _, file := range files[:min(n, len(files))] {
+		paths = append(paths, file.path)
+	}
+	return paths
+}
+
+// quarantineAgent is the app that downloaded or wrote file, from the
+// "flags;timestamp;agent;uuid" quarantine attribute.
+func quarantineAgent(file string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), mdlsTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "xattr", "-p", "com.apple.quarantine", file).Output()
+	if err != nil {
+		return ""
+	}
+	fields := strings.Split(strings.TrimSpace(string(out)), ";")
+	if len(fields) < 3 {
+		return ""
+	}
+	return fields[2]
+}
+
+type appOwnersMsg struct {
+	Owners map[string]appOwner
+}
+
+// appOwnersCmd attributes each listed folder to an app in the background.
+func appOwnersCmd(home string, entries []dirEntry) tea.Cmd {
+	var paths []string
+	for _, entry := range entries {
+		if entry.IsDir {
+			paths = append(paths, entry.Path)
+		}
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		index := loadInstalledApps(home)
+		owners := make(map[string]appOwner)
+		for _, path := range paths {
+			if owner, ok := index.attribute(path, quarantineAgent); ok {
+				owners[path] = owner
+			}
+		}
+		return appOwnersMsg{Owners: owners}
+	}
+}
+
+// orphanedAppData lists the attributed folders whose app is gone, largest first.
+func orphanedAppData(entries []dirEntry, owners map[string]appOwner) []dirEntry {
+	var orphaned []dirEntry
+	for _, entry := range entries {
+		if owner, ok := owners[entry.Path]; ok && owner.orphaned() {
+			orphaned = append(orphaned, entry)
+		}
+	}
+	sort.SliceStable(orphaned, func(a, b int) bool { return orphaned[a].Size > orphaned[b].Size })
+	return orphaned
+}
+
+func formatOrphanedSummary(orphaned []dirEntry) string {
+	var total int64
+	for _, entry := range orphaned {
+		total += entry.Size
+	}
+	return fmt.Sprintf("%d folders (%s) belong to apps that are no longer installed", len(orphaned), humanizeBytes(total))
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseInfoPlist(t *testing.T) {
+	app, ok := parseInfoPlist([]byte(`{"CFBundleIdentifier":"com.tinyspeck.slackmacgap","CFBundleName":"Slack","CFBundleIconFile":"electron.icns"}`), "/Applications/Slack.app")
+	if !ok || app.Name != "Slack" || app.Icon != "electron.icns" {
+		t.Fatalf("parseInfoPlist = %+v, %v", app, ok)
+	}
+	app, ok = parseInfoPlist([]byte(`{"CFBundleIdentifier":"com.example.tool","CFBundleIconName":"AppIcon"}`), "/Applications/Tool.app")
+	if !ok || app.Name != "Tool" || app.Icon != "AppIcon" {
+		t.Fatalf("name should fall back to the bundle name: %+v", app)
+	}
+	if _, ok := parseInfoPlist([]byte(`{"CFBundleName":"NoID"}`), "/Applications/NoID.app"); ok {
+		t.Fatal("a bundle without an identifier should be skipped")
+	}
+}
+
+func TestAttributeAppOwner(t *testing.T) {
+	index := newAppIndex([]installedApp{
+		{BundleID: "com.tinyspeck.slackmacgap", Name: "Slack", Icon: "electron.icns"},
+		{BundleID: "com.google.Chrome", Name: "Google Chrome"},
+		{BundleID: "com.example.Editor", Name: "Editor"},
+	})
+	root := t.TempDir()
+	noAgent := func(string) string { return "" }
+
+	for _, tc := range []struct {
+		name                string
+		want                string
+		installed, orphaned bool
+	}{
+		{"com.tinyspeck.slackmacgap", "Slack", true, false},
+		{"com.google.Chrome.helper", "Google Chrome", true, false},
+		{"Google Chrome", "Google Chrome", true, false},
+		{"com.gone.App", "com.gone.App", false, true},
+		{"com.apple.Safari.SafeBrowsing", "macOS", false, false},
+	} {
+		owner, ok := index.attribute(filepath.Join(root, tc.name), noAgent)
+		if !ok || owner.Name != tc.want || owner.Installed != tc.installed || owner.orphaned() != tc.orphaned {
+			t.Fatalf("%s: got %+v, %v", tc.name, owner, ok)
+		}
+	}
+
+	// A folder named neither way is attributed through the app that wrote its newest files.
+	dir := filepath.Join(root, "scratch")
+	writeFileWithSize(t, filepath.Join(dir, "old"), 10)
+	writeFileWithSize(t, filepath.Join(dir, "sub", "new"), 10)
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(filepath.Join(dir, "old"), old, old); err != nil {
+		t.Fatal(err)
+	}
+	var asked []string
+	owner, ok := index.attribute(dir, func(file string) string {
+		asked = append(asked, filepath.Base(file))
+		if filepath.Base(file) == "new" {
+			return "Editor"
+		}
+		return ""
+	})
+	if !ok || owner.Name != "Editor" || !owner.FromFiles || asked[0] != "new" {
+		t.Fatalf("metadata attribution = %+v, %v, asked %v", owner, ok, asked)
+	}
+	if _, ok := index.attribute(filepath.Join(root, "Unknown"), noAgent); ok {
+		t.Fatal("an unrecognized folder should stay unattributed")
+	}
+	if !strings.Contains(owner.describe(), "installed, going by the files it wrote") {
+		t.Fatalf("unexpected description %q", owner.describe())
+	}
+}
+
+func TestOrphanedAppData(t *testing.T) {
+	entries := []dirEntry{
+		{Path: "/c/com.gone.small", Size: 10, IsDir: true},
+		{Path: "/c/com.kept", Size: 500, IsDir: true},
+		{Path: "/c/com.gone.big", Size: 300, IsDir: true},
+	}
+	owners := map[string]appOwner{
+		"/c/com.gone.small": {Name: "com.gone.small"},
+		"/c/com.kept":       {Name: "Kept", Installed: true},
+		"/c/com.gone.big":   {Name: "com.gone.big"},
+	}
+	orphaned := orphanedAppData(entries, owners)
+	if len(orphaned) != 2 || orphaned[0].Path != "/c/com.gone.big" {
+		t.Fatalf("orphanedAppData = %+v", orphaned)
+	}
+	if !isAppAttributionFolder("/Users/me/Library/Caches", "/Users/me") || isAppAttributionFolder("/Users/me/Library", "/Users/me") {
+		t.Fatal("only the cache and support folders list per-app data")
+	}
+}
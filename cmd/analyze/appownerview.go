@@ -0,0 +1,48 @@
+//go:build darwin
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// scheduleAppOwners attributes the listed folders to apps after scanning ~/Library/Caches
+// or ~/Library/Application Support.
+func (m *model) scheduleAppOwners() tea.Cmd {
+	home := os.Getenv("HOME")
+	if m.inventory != nil || m.inOverviewMode() || home == "" || !isAppAttributionFolder(m.path, home) {
+		return nil
+	}
+	return appOwnersCmd(home, m.entries)
+}
+
+// appOwnerHint names the app behind a row, flagging data whose app is gone.
+func (m model) appOwnerHint(entry dirEntry) string {
+	owner, ok := m.appOwners[entry.Path]
+	if !ok || owner.System {
+		return ""
+	}
+	if owner.orphaned() {
+		return fmt.Sprintf("%s⚠ %s not installed%s", colorYellow, owner.Name, colorReset)
+	}
+	return fmt.Sprintf("%s%s%s", colorGray, owner.Name, colorReset)
+}
+
+// markOrphanedAppData selects every listed folder whose app is no longer installed.
+func (m *model) markOrphanedAppData() {
+	orphaned := orphanedAppData(m.entries, m.appOwners)
+	if len(orphaned) == 0 {
+		m.status = "No app data here belongs to an uninstalled app"
+		return
+	}
+	if m.multiSelected == nil {
+		m.multiSelected = make(map[string]bool)
+	}
+	for _, entry := range orphaned {
+		m.multiSelected[entry.Path] = true
+	}
+	m.status = fmt.Sprintf("Selected %s", formatOrphanedSummary(orphaned))
+}
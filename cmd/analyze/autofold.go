@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// defaultAutoFoldChildren is how many immediate children make a folder fold on its own,
+// like the names in foldDirs: mail stores and cache shards are sized with du rather than
+// walked entry by entry.
+const defaultAutoFoldChildren = 50000
+
+var (
+	autoFoldChildren atomic.Int64 // Zero turns automatic folding off
+	autoFolded       sync.Map     // Folder path to its child count, for explain
+)
+
+func init() {
+	autoFoldChildren.Store(defaultAutoFoldChildren)
+}
+
+// autoFolds reports whether a folder with this many immediate children is folded, and
+// remembers it so explain can say why its contents were not counted.
+func autoFolds(path string, children int) bool {
+	limit := autoFoldChildren.Load()
+	if limit <= 0 || int64(children) <= limit || scanStrategyFor(path) == strategyWalk {
+		return false
+	}
+	autoFolded.Store(path, children)
+	return true
+}
+
+// autoFoldNote explains a folded folder, or returns "" when path was walked.
+func autoFoldNote(path string) string {
+	value, ok := autoFolded.Load(path)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("It holds %s entries directly inside, over the auto-fold limit of %s, so scans size it with du instead of walking it.",
+		formatGrouped(int64(value.(int))), formatGrouped(autoFoldChildren.Load()))
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestScanAutoFoldsCrowdedFolders(t *testing.T) {
+	defer autoFoldChildren.Store(defaultAutoFoldChildren)
+	autoFoldChildren.Store(20)
+
+	root := t.TempDir()
+	for i := 0; i < 25; i++ {
+		writeFileWithSize(t, filepath.Join(root, "shards", fmt.Sprintf("%02d", i)), 1024)
+	}
+	for i := 0; i < 5; i++ {
+		writeFileWithSize(t, filepath.Join(root, "docs", fmt.Sprintf("%d.txt", i)), 1024)
+	}
+	for i := 0; i < 25; i++ {
+		writeFileWithSize(t, filepath.Join(root, "mail", "inbox", fmt.Sprintf("%02d.eml", i)), 1024)
+	}
+
+	var files, dirs, bytes int64
+	var current string
+	result, err := scanPathConcurrent(context.Background(), root, &files, &dirs, &bytes, &current, nil)
+	if err != nil {
+		t.Fatalf("scanPathConcurrent: %v", err)
+	}
+	byName := make(map[string]dirEntry)
+	for _, entry := range result.Entries {
+		byName[entry.Name] = entry
+	}
+	if shards := byName["shards"]; shards.Files != -1 || shards.Size < 25*1024 {
+		t.Errorf("crowded folder not folded: %+v", shards)
+	}
+	if docs := byName["docs"]; docs.Files != 5 {
+		t.Errorf("small folder counted %d files, want 5", docs.Files)
+	}
+	// A crowded subfolder folds without spoiling its parent's counts.
+	if mail := byName["mail"]; mail.Files != 0 || mail.Dirs != 1 || mail.Size < 25*1024 {
+		t.Errorf("parent of a folded folder: %+v", mail)
+	}
+	if note := autoFoldNote(filepath.Join(root, "shards")); !strings.Contains(note, "25 entries") {
+		t.Errorf("autoFoldNote = %q", note)
+	}
+	if note := autoFoldNote(filepath.Join(root, "docs")); note != "" {
+		t.Errorf("walked folder has a fold note: %q", note)
+	}
+}
@@ -0,0 +1,112 @@
+//go:build darwin
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// backupView is the "B" screen breaking a Time Machine backup set down by snapshot.
+type backupView struct {
+	Root      string
+	Snapshots []backupSnapshot
+	Scanning  bool
+	Walked    *int64
+	Err       error
+	Selected  int
+}
+
+func (m model) openBackups() (tea.Model, tea.Cmd) {
+	if m.inventory != nil {
+		m.status = fmt.Sprintf("%s is a read-only listing", m.inventory.Source)
+		return m, nil
+	}
+	if m.inOverviewMode() {
+		m.status = "Open a Time Machine backup folder first"
+		return m, nil
+	}
+	root, ok := backupSetRoot(m.path)
+	if !ok {
+		m.status = fmt.Sprintf("No Time Machine snapshots found in %s", displayPath(m.path))
+		return m, nil
+	}
+	var walked int64
+	m.backups = &backupView{Root: root, Scanning: true, Walked: &walked}
+	m.status = fmt.Sprintf("Measuring snapshots in %s...", displayPath(root))
+	return m, tea.Batch(measureBackupSnapshotsCmd(root, &walked), tickCmd())
+}
+
+// updateBackupsKey handles keys while the snapshot breakdown is open.
+func (m model) updateBackupsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	v := m.backups
+	switch msg.String() {
+	case "q", "Q", "ctrl+c":
+		return m.quit()
+	case "esc", "b", "left", "h", "B":
+		m.backups = nil
+	case "up", "k":
+		v.Selected = max(v.Selected-1, 0)
+	case "down", "j":
+		v.Selected = min(v.Selected+1, max(len(v.Snapshots)-1, 0))
+	case "enter", "right", "l":
+		// Browse the snapshot with the regular scanner.
+		if v.Scanning || v.Selected >= len(v.Snapshots) {
+			return m, nil
+		}
+		path := v.Snapshots[v.Selected].Path
+		m.backups = nil
+		return m.openDir(path)
+	}
+	return m, nil
+}
+
+// renderBackups lists snapshots oldest first with what each one added.
+func (m model) renderBackups() string {
+	v := m.backups
+	var b strings.Builder
+	if v.Scanning {
+		fmt.Fprintf(&b, "%s%s%s%s Measuring snapshots: %s%s files%s walked\n",
+			colorCyan, colorBold, spinnerFrames[m.spinner], colorReset,
+			colorYellow, formatNumber(atomic.LoadInt64(v.Walked)), colorReset)
+		return b.String()
+	}
+	if v.Err != nil || len(v.Snapshots) == 0 {
+		fmt.Fprintf(&b, "  %sNo readable snapshots in %s%s\n\n", colorGray, displayPath(v.Root), colorReset)
+		fmt.Fprintf(&b, "%sB/← Back | Q Quit%s\n", colorGray, colorReset)
+		return b.String()
+	}
+
+	var unique, largest int64
+	for _, snapshot := range v.Snapshots {
+		unique += snapshot.Incremental
+		largest = max(largest, snapshot.Incremental)
+	}
+	fmt.Fprintf(&b, "%sBackup set:%s %s  %d snapshots, %s%s%s on disk\n\n",
+		colorCyan, colorReset, displayPath(v.Root), len(v.Snapshots), colorYellow, humanizeBytes(unique), colorReset)
+
+	viewport := calculateViewport(m.height, true)
+	start := max(0, v.Selected-viewport+1)
+	for i := start; i < min(len(v.Snapshots), start+viewport); i++ {
+		snapshot := v.Snapshots[i]
+		percent := 0.0
+		if unique > 0 {
+			percent = float64(snapshot.Incremental) / float64(unique) * 100
+		}
+		prefix, color := "   ", ""
+		if i == v.Selected {
+			prefix, color = fmt.Sprintf(" %s%s▶%s ", colorCyan, colorBold, colorReset), colorCyan
+		}
+		fmt.Fprintf(&b, "%s%s %5.1f%%  |  %s%-22s%s %10s added  %s%10s total%s\n",
+			prefix, coloredProgressBar(snapshot.Incremental, largest, percent), percent,
+			color, backupSnapshotLabel(snapshot), colorReset,
+			humanizeBytes(snapshot.Incremental), colorGray, humanizeBytes(snapshot.Total), colorReset)
+	}
+
+	fmt.Fprintln(&b)
+	fmt.Fprintf(&b, "%s↑↓ | Enter Browse snapshot | B/← Back | Q Quit%s\n", colorGray, colorReset)
+	return b.String()
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"os/exec"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// batteryPoolLimit caps scan workers while the Mac runs on battery.
+	batteryPoolLimit = backgroundPoolLimit
+	// powerPollInterval is how often the browser checks the power source.
+	powerPollInterval = 30 * time.Second
+)
+
+// batteryPower is set while scans are throttled for battery: fewer workers, and folded
+// folders are walked in-process instead of forking du for each.
+var batteryPower atomic.Bool
+
+// batteryRestore holds what to put back on AC power. Only Update touches it.
+var batteryRestore struct {
+	limit, ceiling int
+}
+
+// powerSourceMsg reports whether the Mac is drawing from its battery.
+type powerSourceMsg struct {
+	OnBattery bool
+	Err       error
+}
+
+// onBatteryPower asks pmset for the current power source.
+func onBatteryPower() (bool, error) {
+	out, err := exec.Command("pmset", "-g", "batt").Output()
+	if err != nil {
+		return false, err
+	}
+	return parsePowerSource(string(out)), nil
+}
+
+// parsePowerSource reads the "Now drawing from 'Battery Power'" line of pmset -g batt.
+func parsePowerSource(out string) bool {
+	first, _, _ := strings.Cut(out, "\n")
+	return strings.Contains(first, "'Battery Power'")
+}
+
+// checkPowerSource reads the power source now. Only the interactive browser throttles
+// for battery; daemon, check and export runs always scan exactly.
+func checkPowerSource() tea.Msg {
+	onBattery, err := onBatteryPower()
+	return powerSourceMsg{OnBattery: onBattery, Err: err}
+}
+
+func powerSourceCmd() tea.Cmd {
+	return tea.Tick(powerPollInterval, func(time.Time) tea.Msg {
+		return checkPowerSource()
+	})
+}
+
+// setBatteryThrottle switches scans to or from battery throttling and reports whether
+// anything changed.
+func setBatteryThrottle(onBattery bool) bool {
+	if batteryPower.Swap(onBattery) == onBattery {
+		return false
+	}
+	if onBattery {
+		batteryRestore.limit = scanPool.currentLimit()
+		batteryRestore.ceiling = scanPool.setCeiling(batteryPoolLimit)
+		return true
+	}
+	// Put back --nice's bound, if any, before the limit so the limit stays under it.
+	scanPool.setCeiling(batteryRestore.ceiling)
+	scanPool.setLimit(batteryRestore.limit)
+	return true
+}
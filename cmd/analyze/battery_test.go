@@ -0,0 +1,45 @@
+package main
+
+import "testing"
+
+func TestParsePowerSource(t *testing.T) {
+	battery := "Now drawing from 'Battery Power'\n -InternalBattery-0 (id=4653155)\t84%; discharging; 4:12 remaining present: true\n"
+	if !parsePowerSource(battery) {
+		t.Fatalf("expected battery power")
+	}
+	ac := "Now drawing from 'AC Power'\n -InternalBattery-0 (id=4653155)\t100%; charged; 0:00 remaining present: true\n"
+	if parsePowerSource(ac) {
+		t.Fatalf("expected AC power")
+	}
+	if parsePowerSource("") {
+		t.Fatalf("empty output should not read as battery")
+	}
+}
+
+func TestBatteryThrottleRestoresLimit(t *testing.T) {
+	saved := scanPool
+	scanPool = newWorkerPool(8)
+	defer func() {
+		scanPool = saved
+		batteryPower.Store(false)
+	}()
+
+	if !setBatteryThrottle(true) {
+		t.Fatalf("switching to battery should report a change")
+	}
+	if setBatteryThrottle(true) {
+		t.Fatalf("staying on battery should not report a change")
+	}
+	if got := scanPool.setLimit(16); got != batteryPoolLimit {
+		t.Fatalf("expected limit capped at %d on battery, got %d", batteryPoolLimit, got)
+	}
+	scanPool.setLimit(8)
+
+	setBatteryThrottle(false)
+	if got := scanPool.currentLimit(); got != 8 {
+		t.Fatalf("expected limit 8 back on AC power, got %d", got)
+	}
+	if got := scanPool.setLimit(16); got != 16 {
+		t.Fatalf("AC power should lift the cap, got %d", got)
+	}
+}
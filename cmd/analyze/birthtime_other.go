@@ -0,0 +1,13 @@
+//go:build !darwin
+
+package main
+
+import (
+	"io/fs"
+	"time"
+)
+
+// birthTime is unknown where stat(2) does not report it.
+func birthTime(fs.FileInfo) time.Time {
+	return time.Time{}
+}
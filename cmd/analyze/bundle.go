@@ -0,0 +1,33 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// bundleExtensions are folders macOS shows as a single file: apps, their plug-ins and
+// frameworks, and document packages such as photo libraries.
+var bundleExtensions = map[string]bool{
+	".app":           true,
+	".appex":         true,
+	".bundle":        true,
+	".framework":     true,
+	".kext":          true,
+	".photoslibrary": true,
+	".plugin":        true,
+	".xcappdata":     true,
+	".xcarchive":     true,
+}
+
+// hasBundleExtension reports whether the folder's name makes it a package.
+func hasBundleExtension(path string) bool {
+	name := filepath.Base(path)
+	ext := strings.ToLower(filepath.Ext(name))
+	return ext != name && bundleExtensions[ext]
+}
+
+// isBundle reports whether the folder at path is a package, by its extension or by the
+// bundle bit Finder sets on packages with other names.
+func isBundle(path string) bool {
+	return hasBundleExtension(path) || hasBundleBit(path)
+}
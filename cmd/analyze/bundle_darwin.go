@@ -0,0 +1,37 @@
+//go:build darwin
+
+package main
+
+import (
+	"encoding/binary"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	finderInfoXattr  = "com.apple.FinderInfo"
+	finderInfoLength = 32
+	finderFlagsAt    = 8      // FolderInfo.finderFlags, big-endian
+	kHasBundle       = 0x2000 // From <CarbonCore/Finder.h>
+	xattrNoFollow    = 0x0001
+)
+
+// hasBundleBit reports whether Finder marked the folder as a package.
+func hasBundleBit(path string) bool {
+	p, err := syscall.BytePtrFromString(path)
+	if err != nil {
+		return false
+	}
+	name, err := syscall.BytePtrFromString(finderInfoXattr)
+	if err != nil {
+		return false
+	}
+	var buf [finderInfoLength]byte
+	n, _, errno := syscall.Syscall6(syscall.SYS_GETXATTR,
+		uintptr(unsafe.Pointer(p)), uintptr(unsafe.Pointer(name)),
+		uintptr(unsafe.Pointer(&buf[0])), uintptr(len(buf)), 0, xattrNoFollow)
+	if errno != 0 || n < finderFlagsAt+2 {
+		return false
+	}
+	return binary.BigEndian.Uint16(buf[finderFlagsAt:])&kHasBundle != 0
+}
@@ -0,0 +1,8 @@
+//go:build !darwin
+
+package main
+
+// hasBundleBit is a Finder attribute; elsewhere only extensions mark packages.
+func hasBundleBit(string) bool {
+	return false
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestHasBundleExtension(t *testing.T) {
+	cases := map[string]bool{
+		"/Applications/Safari.app":                true,
+		"/Library/Frameworks/Mono.FRAMEWORK":      true,
+		"/Users/me/Pictures/Photos.photoslibrary": true,
+		"/Users/me/Library/Foo.xcappdata":         true,
+		"/Users/me/.bundle":                       false,
+		"/Users/me/app":                           false,
+		"/Users/me/notes.txt":                     false,
+	}
+	for path, want := range cases {
+		if got := hasBundleExtension(path); got != want {
+			t.Errorf("hasBundleExtension(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestScanShowsBundlesAsSingleEntries(t *testing.T) {
+	root := t.TempDir()
+	writeFileWithSize(t, filepath.Join(root, "Tool.app", "Contents", "MacOS", "Tool"), 4096)
+	writeFileWithSize(t, filepath.Join(root, "Tool.app", "Contents", "Info.plist"), 1024)
+	writeFileWithSize(t, filepath.Join(root, "docs", "a.txt"), 1024)
+
+	var files, dirs, bytes int64
+	var current string
+	result, err := scanPathConcurrent(context.Background(), root, &files, &dirs, &bytes, &current, nil)
+	if err != nil {
+		t.Fatalf("scanPathConcurrent: %v", err)
+	}
+	byName := make(map[string]dirEntry)
+	for _, entry := range result.Entries {
+		byName[entry.Name] = entry
+	}
+	if app := byName["Tool.app"]; !app.Bundle || !app.IsDir || app.Size < 5*1024 {
+		t.Errorf("app bundle entry: %+v", app)
+	}
+	if docs := byName["docs"]; docs.Bundle {
+		t.Errorf("plain folder marked as a package: %+v", docs)
+	}
+}
@@ -1,16 +1,20 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"encoding/gob"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/cespare/xxhash/v2"
+
+	"github.com/tw93/mole/internal/coordination"
 )
 
 type overviewSizeSnapshot struct {
@@ -25,11 +29,18 @@ var (
 )
 
 func snapshotFromModel(m model) historyEntry {
+	entries, largeFiles := m.entries, m.largeFiles
+	if m.filter != nil {
+		// Cache and history keep the full listing, not the filtered view.
+		entries, largeFiles = m.filter.entries, m.filter.largeFiles
+	}
 	return historyEntry{
 		Path:          m.path,
-		Entries:       cloneDirEntries(m.entries),
-		LargeFiles:    cloneFileEntries(m.largeFiles),
+		Entries:       cloneDirEntries(entries),
+		LargeFiles:    cloneFileEntries(largeFiles),
 		TotalSize:     m.totalSize,
+		Omitted:       m.omitted,
+		OmittedSize:   m.omittedSize,
 		Selected:      m.selected,
 		EntryOffset:   m.offset,
 		LargeSelected: m.largeSelected,
@@ -70,7 +81,7 @@ func ensureOverviewSnapshotCacheLocked() error {
 	if err != nil {
 		return err
 	}
-	data, err := os.ReadFile(storePath)
+	raw, err := os.ReadFile(storePath)
 	if err != nil {
 		if os.IsNotExist(err) {
 			overviewSnapshotCache = make(map[string]overviewSizeSnapshot)
@@ -79,7 +90,8 @@ func ensureOverviewSnapshotCacheLocked() error {
 		}
 		return err
 	}
-	if len(data) == 0 {
+	data, err := openCacheData(raw)
+	if err != nil || len(data) == 0 {
 		overviewSnapshotCache = make(map[string]overviewSizeSnapshot)
 		overviewSnapshotLoaded = true
 		return nil
@@ -126,6 +138,20 @@ func loadStoredOverviewSize(path string) (int64, error) {
 	return 0, fmt.Errorf("snapshot not found")
 }
 
+// overviewSnapshotAge is how long ago path's overview size was stored.
+func overviewSnapshotAge(path string) (time.Duration, bool) {
+	overviewSnapshotMu.Lock()
+	defer overviewSnapshotMu.Unlock()
+	if err := ensureOverviewSnapshotCacheLocked(); err != nil || overviewSnapshotCache == nil {
+		return 0, false
+	}
+	snapshot, ok := overviewSnapshotCache[path]
+	if !ok || snapshot.Size <= 0 {
+		return 0, false
+	}
+	return time.Since(snapshot.Updated), true
+}
+
 func storeOverviewSize(path string, size int64) error {
 	if path == "" || size <= 0 {
 		return fmt.Errorf("invalid overview size")
@@ -150,15 +176,11 @@ func persistOverviewSnapshotLocked() error {
 	if err != nil {
 		return err
 	}
-	tmpPath := storePath + ".tmp"
 	data, err := json.MarshalIndent(overviewSnapshotCache, "", "  ")
 	if err != nil {
 		return err
 	}
-	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
-		return err
-	}
-	return os.Rename(tmpPath, storePath)
+	return writeCacheFile(storePath, data)
 }
 
 func loadOverviewCachedSize(path string) (int64, error) {
@@ -188,6 +210,11 @@ func getCacheDir() (string, error) {
 	return cacheDir, nil
 }
 
+// getConfigDir returns ~/.config/mole, shared with the shell commands.
+func getConfigDir() (string, error) {
+	return coordination.Dir()
+}
+
 func getCachePath(path string) (string, error) {
 	cacheDir, err := getCacheDir()
 	if err != nil {
@@ -204,14 +231,13 @@ func loadCacheFromDisk(path string) (*cacheEntry, error) {
 		return nil, err
 	}
 
-	file, err := os.Open(cachePath)
+	data, err := readCacheFile(cachePath)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
 
 	var entry cacheEntry
-	decoder := gob.NewDecoder(file)
+	decoder := gob.NewDecoder(bytes.NewReader(data))
 	if err := decoder.Decode(&entry); err != nil {
 		return nil, err
 	}
@@ -247,21 +273,20 @@ func saveCacheToDisk(path string, result scanResult) error {
 	}
 
 	entry := cacheEntry{
-		Entries:    result.Entries,
-		LargeFiles: result.LargeFiles,
-		TotalSize:  result.TotalSize,
-		ModTime:    info.ModTime(),
-		ScanTime:   time.Now(),
+		Entries:     result.Entries,
+		LargeFiles:  result.LargeFiles,
+		TotalSize:   result.TotalSize,
+		Omitted:     result.Omitted,
+		OmittedSize: result.OmittedSize,
+		ModTime:     info.ModTime(),
+		ScanTime:    time.Now(),
 	}
 
-	file, err := os.Create(cachePath)
-	if err != nil {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(entry); err != nil {
 		return err
 	}
-	defer file.Close()
-
-	encoder := gob.NewEncoder(file)
-	return encoder.Encode(entry)
+	return writeCacheFile(cachePath, buf.Bytes())
 }
 
 func invalidateCache(path string) {
@@ -290,6 +315,25 @@ func removeOverviewSnapshot(path string) {
 	}
 }
 
+// removeOverviewSnapshotsUnder forgets the stored size of root and every folder inside it.
+func removeOverviewSnapshotsUnder(root string) {
+	overviewSnapshotMu.Lock()
+	defer overviewSnapshotMu.Unlock()
+	if err := ensureOverviewSnapshotCacheLocked(); err != nil || overviewSnapshotCache == nil {
+		return
+	}
+	removed := false
+	for path := range overviewSnapshotCache {
+		if path == root || strings.HasPrefix(path, strings.TrimSuffix(root, "/")+"/") {
+			delete(overviewSnapshotCache, path)
+			removed = true
+		}
+	}
+	if removed {
+		_ = persistOverviewSnapshotLocked()
+	}
+}
+
 // prefetchOverviewCache warms overview cache in background.
 func prefetchOverviewCache(ctx context.Context) {
 	entries := createOverviewEntries()
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// caseConflicts records directories whose children differ only by case.
+var (
+	caseConflictMu   sync.Mutex
+	caseConflictDirs = make(map[string]bool)
+)
+
+// isCaseSensitiveVolume probes a sibling name with swapped case.
+// Collisions can only exist on case-sensitive volumes, so the check is skipped elsewhere.
+func isCaseSensitiveVolume(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		swapped := swapCase(name)
+		if swapped == name {
+			continue
+		}
+		original, err := os.Lstat(filepath.Join(dir, name))
+		if err != nil {
+			continue
+		}
+		other, err := os.Lstat(filepath.Join(dir, swapped))
+		if err != nil {
+			return os.IsNotExist(err)
+		}
+		return !os.SameFile(original, other)
+	}
+	return false
+}
+
+func swapCase(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsUpper(r):
+			return unicode.ToLower(r)
+		case unicode.IsLower(r):
+			return unicode.ToUpper(r)
+		}
+		return r
+	}, s)
+}
+
+// findCaseCollisions returns names that would clash on a case-insensitive target.
+func findCaseCollisions(names []string) []string {
+	seen := make(map[string]string, len(names))
+	var collisions []string
+	reported := make(map[string]bool)
+	for _, name := range names {
+		key := strings.ToLower(name)
+		if first, ok := seen[key]; ok {
+			if !reported[first] {
+				collisions = append(collisions, first)
+				reported[first] = true
+			}
+			collisions = append(collisions, name)
+			continue
+		}
+		seen[key] = name
+	}
+	return collisions
+}
+
+func dirEntryNames(entries []os.DirEntry) []string {
+	names := make([]string, len(entries))
+	for i, entry := range entries {
+		names[i] = entry.Name()
+	}
+	return names
+}
+
+func recordCaseConflict(dir string) {
+	caseConflictMu.Lock()
+	caseConflictDirs[dir] = true
+	caseConflictMu.Unlock()
+}
+
+// hasCaseConflictUnder reports whether path or any descendant holds colliding names.
+func hasCaseConflictUnder(path string) bool {
+	caseConflictMu.Lock()
+	defer caseConflictMu.Unlock()
+	prefix := path + string(os.PathSeparator)
+	for dir := range caseConflictDirs {
+		if dir == path || strings.HasPrefix(dir, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func clearCaseConflictsUnder(path string) {
+	caseConflictMu.Lock()
+	defer caseConflictMu.Unlock()
+	prefix := path + string(os.PathSeparator)
+	for dir := range caseConflictDirs {
+		if dir == path || strings.HasPrefix(dir, prefix) {
+			delete(caseConflictDirs, dir)
+		}
+	}
+}
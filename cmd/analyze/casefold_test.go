@@ -0,0 +1,36 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFindCaseCollisions(t *testing.T) {
+	got := findCaseCollisions([]string{"Readme.md", "src", "README.md", "docs", "readme.MD"})
+	want := []string{"Readme.md", "README.md", "readme.MD"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("findCaseCollisions = %v, want %v", got, want)
+	}
+	if got := findCaseCollisions([]string{"a", "b"}); len(got) != 0 {
+		t.Fatalf("expected no collisions, got %v", got)
+	}
+}
+
+func TestCaseConflictTracking(t *testing.T) {
+	base := t.TempDir()
+	nested := filepath.Join(base, "project", "assets")
+	recordCaseConflict(nested)
+	t.Cleanup(func() { clearCaseConflictsUnder(base) })
+
+	if !hasCaseConflictUnder(filepath.Join(base, "project")) {
+		t.Fatalf("expected ancestor to report nested conflict")
+	}
+	if hasCaseConflictUnder(filepath.Join(base, "proj")) {
+		t.Fatalf("prefix match must respect path boundaries")
+	}
+	clearCaseConflictsUnder(base)
+	if hasCaseConflictUnder(base) {
+		t.Fatalf("expected conflicts to be cleared")
+	}
+}
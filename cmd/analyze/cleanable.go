@@ -2,7 +2,8 @@ package main
 
 import (
 	"path/filepath"
-	"strings"
+
+	"github.com/tw93/mole/internal/clean"
 )
 
 // isCleanableDir marks paths safe to delete manually (not handled by mo clean).
@@ -28,21 +29,7 @@ func isCleanableDir(path string) bool {
 
 // isHandledByMoClean checks if a path is cleaned by mo clean.
 func isHandledByMoClean(path string) bool {
-	cleanPaths := []string{
-		"/Library/Caches/",
-		"/Library/Logs/",
-		"/Library/Saved Application State/",
-		"/.Trash/",
-		"/Library/DiagnosticReports/",
-	}
-
-	for _, p := range cleanPaths {
-		if strings.Contains(path, p) {
-			return true
-		}
-	}
-
-	return false
+	return clean.Handles(path)
 }
 
 // Project dependency and build directories.
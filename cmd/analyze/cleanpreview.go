@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/tw93/mole/internal/clean"
+)
+
+// cleanPreviewMsg is what mo clean would recover from each of its categories.
+type cleanPreviewMsg struct {
+	Result clean.Result
+	Err    error
+}
+
+// cleanDoneMsg reports a clean run from the preview screen.
+type cleanDoneMsg struct {
+	Result clean.Result
+	Err    error
+}
+
+// cleanOptions loads the whitelist and protection lists mo clean uses. Without the
+// protection lists nothing is cleaned, not even in a preview that could be executed.
+func cleanOptions(home string, dryRun bool) (clean.Options, error) {
+	protection, err := clean.LoadInstalledProtection()
+	if err != nil {
+		return clean.Options{}, fmt.Errorf("unable to read protected apps: %v", err)
+	}
+	return clean.Options{
+		Home:       home,
+		DryRun:     dryRun,
+		Whitelist:  clean.LoadWhitelist(home),
+		Protection: protection,
+		Measure:    measureCleanPath,
+	}, nil
+}
+
+// measureCleanPath sizes a cleanable path as the scanner sizes overview folders.
+func measureCleanPath(path string) int64 {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return 0
+	}
+	if !info.IsDir() {
+		return getActualFileSize(path, info)
+	}
+	if size, err := getDirectorySizeFromDu(path); err == nil {
+		return size
+	}
+	size, _ := getDirectoryLogicalSizeWithExclude(path, "")
+	return size
+}
+
+// cleanPreviewCmd measures every mo clean category without removing anything.
+func cleanPreviewCmd(home string) tea.Cmd {
+	return func() tea.Msg {
+		opts, err := cleanOptions(home, true)
+		if err != nil {
+			return cleanPreviewMsg{Err: err}
+		}
+		return cleanPreviewMsg{Result: clean.Run(clean.UserCategories, opts)}
+	}
+}
+
+// runCleanCmd cleans the named categories, holding the analyzer's delete lock so a
+// concurrent mo clean waits, and reports the removed paths to other Mole processes.
+func runCleanCmd(home string, categories []string) tea.Cmd {
+	return func() tea.Msg {
+		if err := cleanRunningError(); err != nil {
+			return cleanDoneMsg{Err: err}
+		}
+		opts, err := cleanOptions(home, false)
+		if err != nil {
+			return cleanDoneMsg{Err: err}
+		}
+		if release, err := acquireLock(analyzeLockFile); err == nil {
+			defer release()
+		}
+		result := clean.Run(clean.Select(clean.UserCategories, categories), opts)
+		recordChanges(result.Paths())
+		return cleanDoneMsg{Result: result}
+	}
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestMeasureCleanPath(t *testing.T) {
+	root := t.TempDir()
+	writeFileWithSize(t, filepath.Join(root, "cache", "a.bin"), 64<<10)
+	writeFileWithSize(t, filepath.Join(root, "cache", "nested", "b.bin"), 64<<10)
+	writeFileWithSize(t, filepath.Join(root, "report.crash"), 8<<10)
+
+	if size := measureCleanPath(filepath.Join(root, "cache")); size < 128<<10 {
+		t.Errorf("folder measured %d, want at least 128 KB", size)
+	}
+	if size := measureCleanPath(filepath.Join(root, "report.crash")); size < 8<<10 {
+		t.Errorf("file measured %d, want at least 8 KB", size)
+	}
+	if size := measureCleanPath(filepath.Join(root, "missing")); size != 0 {
+		t.Errorf("missing path measured %d", size)
+	}
+}
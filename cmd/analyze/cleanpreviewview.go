@@ -0,0 +1,185 @@
+//go:build darwin
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/tw93/mole/internal/clean"
+)
+
+// cleanPreview is the "p" screen showing what mo clean would recover per category,
+// with categories toggled off before cleaning.
+type cleanPreview struct {
+	Result   clean.Result
+	Skip     map[string]bool // Category names toggled off
+	Selected int
+	Scanning bool
+	Cleaning bool
+	Confirm  bool // Enter pressed once; the next Enter cleans
+	Err      error
+}
+
+// chosen lists the categories left on and their combined size.
+func (v *cleanPreview) chosen() ([]string, int64) {
+	var names []string
+	var size int64
+	for _, category := range v.Result.Categories {
+		if !v.Skip[category.Name] {
+			names = append(names, category.Name)
+			size += category.Size
+		}
+	}
+	return names, size
+}
+
+func (m model) openCleanPreview() (tea.Model, tea.Cmd) {
+	if m.inventory != nil {
+		m.status = fmt.Sprintf("%s is a read-only listing", m.inventory.Source)
+		return m, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		m.status = "Unable to find your home folder"
+		return m, nil
+	}
+	m.cleanPreview = &cleanPreview{Skip: make(map[string]bool), Scanning: true}
+	m.status = "Measuring what mo clean would recover..."
+	return m, tea.Batch(cleanPreviewCmd(home), tickCmd())
+}
+
+// updateCleanPreviewKey handles keys while the clean preview is open.
+func (m model) updateCleanPreviewKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	v := m.cleanPreview
+	categories := v.Result.Categories
+	key := msg.String()
+	if key != "enter" {
+		v.Confirm = false
+	}
+	switch key {
+	case "q", "Q", "ctrl+c":
+		return m.quit()
+	case "esc", "b", "left", "h", "p":
+		if v.Cleaning {
+			m.status = "Cleaning, wait for it to finish"
+			return m, nil
+		}
+		m.cleanPreview = nil
+	case "up", "k":
+		v.Selected = max(v.Selected-1, 0)
+	case "down", "j":
+		v.Selected = min(v.Selected+1, max(len(categories)-1, 0))
+	case " ":
+		if v.Selected < len(categories) && !v.Cleaning {
+			name := categories[v.Selected].Name
+			v.Skip[name] = !v.Skip[name]
+		}
+	case "enter":
+		if v.Scanning || v.Cleaning {
+			return m, nil
+		}
+		names, size := v.chosen()
+		if len(names) == 0 {
+			m.status = "Nothing selected to clean"
+			return m, nil
+		}
+		if !v.Confirm {
+			v.Confirm = true
+			m.status = fmt.Sprintf("Press Enter again to clean %s from %d categories", humanizeBytes(size), len(names))
+			return m, nil
+		}
+		home, err := os.UserHomeDir()
+		if err != nil {
+			m.status = "Unable to find your home folder"
+			return m, nil
+		}
+		v.Confirm, v.Cleaning = false, true
+		m.status = "Cleaning..."
+		return m, tea.Batch(runCleanCmd(home, names), tickCmd())
+	}
+	return m, nil
+}
+
+// applyCleanDone shows what a clean run recovered and refreshes listings it changed.
+func (m model) applyCleanDone(msg cleanDoneMsg) (tea.Model, tea.Cmd) {
+	if m.cleanPreview != nil {
+		m.cleanPreview.Cleaning = false
+	}
+	if msg.Err != nil {
+		m.status = msg.Err.Error()
+		return m, nil
+	}
+	m.cleanPreview = nil
+	m.status = fmt.Sprintf("Freed %s from %d items", humanizeBytes(msg.Result.Size), msg.Result.Items)
+	if msg.Result.Failed > 0 {
+		m.status += fmt.Sprintf(", %d could not be removed", msg.Result.Failed)
+	}
+	status := m.status
+	next, cmd := m.applyExternalChanges(msg.Result.Paths())
+	if updated, ok := next.(model); ok {
+		updated.status = status
+		return updated, cmd
+	}
+	return next, cmd
+}
+
+// renderCleanPreview lists mo clean's categories with what each would recover.
+func (m model) renderCleanPreview() string {
+	v := m.cleanPreview
+	var b strings.Builder
+	if v.Scanning || v.Cleaning {
+		action := "Measuring what mo clean would recover"
+		if v.Cleaning {
+			action = "Cleaning"
+		}
+		fmt.Fprintf(&b, "%s%s%s%s %s...\n", colorCyan, colorBold, spinnerFrames[m.spinner], colorReset, action)
+		return b.String()
+	}
+	if v.Err != nil || len(v.Result.Categories) == 0 {
+		reason := "Nothing for mo clean to recover"
+		if v.Err != nil {
+			reason = v.Err.Error()
+		}
+		fmt.Fprintf(&b, "  %s%s%s\n\n", colorGray, reason, colorReset)
+		fmt.Fprintf(&b, "%sp/← Back | Q Quit%s\n", colorGray, colorReset)
+		return b.String()
+	}
+
+	_, chosen := v.chosen()
+	fmt.Fprintf(&b, "%sClean preview:%s %s%s%s of %s selected\n\n",
+		colorCyan, colorReset, colorYellow, humanizeBytes(chosen), colorReset, humanizeBytes(v.Result.Size))
+	for i, category := range v.Result.Categories {
+		prefix, color := "   ", ""
+		if i == v.Selected {
+			prefix, color = fmt.Sprintf(" %s%s▶%s ", colorCyan, colorBold, colorReset), colorCyan
+		}
+		check := colorGreen + "[✓]" + colorReset
+		if v.Skip[category.Name] {
+			check = colorGray + "[ ]" + colorReset
+		}
+		percent := 0.0
+		if v.Result.Size > 0 {
+			percent = float64(category.Size) / float64(v.Result.Size) * 100
+		}
+		fmt.Fprintf(&b, "%s%s %s %5.1f%%  %s%s%-22s%s %10s\n",
+			prefix, check, coloredProgressBar(category.Size, v.Result.Size, percent), percent,
+			color, colorBold, category.Name, colorReset, humanizeBytes(category.Size))
+		if i != v.Selected {
+			continue
+		}
+		for _, t := range category.Targets {
+			fmt.Fprintf(&b, "          %s%-34s %10s  %d items%s\n", colorGray, t.Label, humanizeBytes(t.Size), len(t.Items), colorReset)
+		}
+	}
+	if v.Result.Skipped > 0 {
+		fmt.Fprintf(&b, "\n  %s%d protected or whitelisted items are left alone%s\n", colorGray, v.Result.Skipped, colorReset)
+	}
+
+	fmt.Fprintln(&b)
+	fmt.Fprintf(&b, "%s↑↓ | Space Toggle | Enter Clean | p/← Back | Q Quit%s\n", colorGray, colorReset)
+	return b.String()
+}
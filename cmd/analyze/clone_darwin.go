@@ -0,0 +1,68 @@
+//go:build darwin
+
+package main
+
+import (
+	"encoding/binary"
+	"syscall"
+	"unsafe"
+)
+
+// getattrlist(2) constants from <sys/attr.h>.
+const (
+	attrBitMapCount        = 5
+	attrCmnReturnedAttrs   = 0x80000000
+	attrCmnExtPrivateSize  = 0x00000008
+	attrCmnExtCloneID      = 0x00000100
+	attrCmnExtExtFlags     = 0x00000200
+	extFlagMayShareBlocks  = 0x00000001
+	fsoptNoFollow          = 0x00000001
+	fsoptAttrCmnExtended   = 0x00000020
+	cloneAttrReplyHeader   = 4 + 5*4 // length + attribute_set_t
+	cloneAttrReplyLength   = cloneAttrReplyHeader + 3*8
+	cloneAttrReturnedFork  = 4 + 4*4 // offset of attribute_set_t.forkattr
+	cloneAttrWantedForkSet = attrCmnExtPrivateSize | attrCmnExtCloneID | attrCmnExtExtFlags
+)
+
+type attrList struct {
+	bitmapCount uint16
+	reserved    uint16
+	commonAttr  uint32
+	volAttr     uint32
+	dirAttr     uint32
+	fileAttr    uint32
+	forkAttr    uint32
+}
+
+// cloneStatOf asks APFS whether a file shares blocks with clones, and how many are its own.
+func cloneStatOf(path string) (cloneStat, bool) {
+	p, err := syscall.BytePtrFromString(path)
+	if err != nil {
+		return cloneStat{}, false
+	}
+	request := attrList{
+		bitmapCount: attrBitMapCount,
+		commonAttr:  attrCmnReturnedAttrs,
+		forkAttr:    cloneAttrWantedForkSet,
+	}
+	var buf [cloneAttrReplyLength]byte
+	_, _, errno := syscall.Syscall6(syscall.SYS_GETATTRLIST,
+		uintptr(unsafe.Pointer(p)), uintptr(unsafe.Pointer(&request)),
+		uintptr(unsafe.Pointer(&buf[0])), uintptr(len(buf)),
+		fsoptNoFollow|fsoptAttrCmnExtended, 0)
+	if errno != 0 {
+		return cloneStat{}, false
+	}
+	// Attributes come back in bit order, and only if the volume supports them.
+	if binary.LittleEndian.Uint32(buf[cloneAttrReturnedFork:])&cloneAttrWantedForkSet != cloneAttrWantedForkSet {
+		return cloneStat{}, false
+	}
+	reply := buf[cloneAttrReplyHeader:]
+	private := int64(binary.LittleEndian.Uint64(reply[0:]))
+	cloneID := binary.LittleEndian.Uint64(reply[8:])
+	flags := binary.LittleEndian.Uint64(reply[16:])
+	if flags&extFlagMayShareBlocks == 0 {
+		return cloneStat{}, false
+	}
+	return cloneStat{ID: cloneID, Private: private}, true
+}
@@ -0,0 +1,8 @@
+//go:build !darwin
+
+package main
+
+// cloneStatOf is only available on APFS.
+func cloneStatOf(string) (cloneStat, bool) {
+	return cloneStat{}, false
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+// flagDataless (SF_DATALESS) marks a file or folder whose contents live only in the cloud.
+const flagDataless = 0x40000000
+
+// cloudFolders hold File Provider data (iCloud Drive, Dropbox, OneDrive...). Anything
+// that reads or enumerates them may make the provider download placeholders.
+var cloudFolders = []string{
+	filepath.Join("Library", "Mobile Documents"),
+	filepath.Join("Library", "CloudStorage"),
+}
+
+// errDataless is returned instead of opening a file that would have to be downloaded.
+var errDataless = errors.New("contents are only in the cloud")
+
+// isDataless reports whether path is a cloud placeholder with no local contents.
+func isDataless(path string) bool {
+	flags, ok := fileFlags(path)
+	return ok && flags&flagDataless != 0
+}
+
+// datalessInfo is isDataless for a file already stat'ed.
+func datalessInfo(info fs.FileInfo) bool {
+	return infoFlags(info)&flagDataless != 0
+}
+
+// isCloudPath reports whether path is inside a cloud-synced folder.
+func isCloudPath(path string) bool {
+	for _, folder := range cloudFolders {
+		if underCloudFolder(path, folder) {
+			return true
+		}
+	}
+	return false
+}
+
+// underCloudFolder reports whether path is the home-relative folder, in any home, or inside it.
+func underCloudFolder(path, folder string) bool {
+	marker := string(filepath.Separator) + folder
+	if i := strings.Index(path, marker); i >= 0 {
+		rest := path[i+len(marker):]
+		return rest == "" || rest[0] == filepath.Separator
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIsCloudPath(t *testing.T) {
+	for path, want := range map[string]bool{
+		"/Users/me/Library/Mobile Documents":                       true,
+		"/Users/me/Library/Mobile Documents/com~apple~CloudDocs/a": true,
+		"/Users/me/Library/CloudStorage/Dropbox":                   true,
+		"/Users/me/Library/Mobile Documents Backup":                false,
+		"/Users/me/Documents":                                      false,
+	} {
+		if got := isCloudPath(path); got != want {
+			t.Fatalf("isCloudPath(%q) = %v; want %v", path, got, want)
+		}
+	}
+}
+
+// contentReaders may open files: they read Mole's own state, or (hashFile, readTextPreview)
+// check for cloud placeholders first. Anything else that opens files could download them.
+var contentReaders = map[string]bool{
+	"cache.go":        true,
+	"config.go":       true,
+	"alerts.go":       true,
+	"coordination.go": true,
+	"duplicates.go":   true,
+	"encryption.go":   true,
+	"ledger.go":       true,
+	"load.go":         true,
+	"moverules.go":    true,
+	"savedsearch.go":  true,
+	"pins.go":         true,
+	"quicklook.go":    true,
+	"pendingops.go":   true,
+	"sizeprovider.go": true,
+	"staging.go":      true,
+	"strategy.go":     true,
+}
+
+func TestOnlyAllowedFilesReadContents(t *testing.T) {
+	sources, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fset := token.NewFileSet()
+	for _, source := range sources {
+		if strings.HasSuffix(source, "_test.go") || contentReaders[source] {
+			continue
+		}
+		file, err := parser.ParseFile(fset, source, nil, 0)
+		if err != nil {
+			t.Fatalf("parse %s: %v", source, err)
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			if pkg, ok := sel.X.(*ast.Ident); ok && pkg.Name == "os" {
+				switch sel.Sel.Name {
+				case "Open", "OpenFile", "ReadFile":
+					t.Errorf("%s: os.%s may download cloud placeholders; check isDataless or add the file to contentReaders", fset.Position(call.Pos()), sel.Sel.Name)
+				}
+			}
+			return true
+		})
+	}
+}
+
+func TestHashFileReadsLocalFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "local.bin")
+	if err := os.WriteFile(path, []byte("data"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if isDataless(path) {
+		t.Fatal("a local file is not a cloud placeholder")
+	}
+	if _, err := hashFile(path, 4, false); err != nil {
+		t.Fatalf("local files should still hash: %v", err)
+	}
+}
@@ -0,0 +1,23 @@
+//go:build darwin
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEntryRowShowsCloudBadge(t *testing.T) {
+	for _, tc := range []struct {
+		entry dirEntry
+		want  string
+	}{
+		{dirEntry{Name: "Report.key", Path: "/Users/me/Library/Mobile Documents/Report.key", Dataless: true, Files: 1}, "in cloud, not local"},
+		{dirEntry{Name: "Drive", Path: "/Users/me/Library/CloudStorage/Drive", IsDir: true, Size: 1 << 20, Cloud: 3 << 30}, "3.0 GB in cloud"},
+	} {
+		row := renderEntryRow(entryRowKey{entry: tc.entry, maxSize: 1 << 20, totalSize: 1 << 20, nameWidth: 30})
+		if !strings.Contains(row, tc.want) {
+			t.Errorf("row for %s lacks %q: %q", tc.entry.Name, tc.want, row)
+		}
+	}
+}
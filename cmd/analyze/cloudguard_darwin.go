@@ -0,0 +1,32 @@
+//go:build darwin
+
+package main
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// iopolicysys(2) constants from <sys/resource.h>.
+const (
+	iopolCmdSet                          = 1
+	iopolScopeProcess                    = 0
+	iopolTypeVFSMaterializeDatalessFiles = 3
+	iopolMaterializeDatalessFilesOff     = 1
+)
+
+type iopolicyParam struct {
+	scope  int32
+	iotype int32
+	policy int32
+}
+
+// preventDatalessMaterialization stops this process, and the du and mdls children it
+// starts, from downloading cloud placeholders: touching one fails with EDEADLK instead.
+func preventDatalessMaterialization() error {
+	param := iopolicyParam{scope: iopolScopeProcess, iotype: iopolTypeVFSMaterializeDatalessFiles, policy: iopolMaterializeDatalessFilesOff}
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOPOLICYSYS, iopolCmdSet, uintptr(unsafe.Pointer(&param)), 0); errno != 0 {
+		return errno
+	}
+	return nil
+}
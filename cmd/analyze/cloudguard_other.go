@@ -0,0 +1,8 @@
+//go:build !darwin
+
+package main
+
+// preventDatalessMaterialization is a no-op where there are no cloud placeholders.
+func preventDatalessMaterialization() error {
+	return nil
+}
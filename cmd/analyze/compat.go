@@ -0,0 +1,33 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+)
+
+const (
+	// fat32MaxFileSize is the largest file FAT32 can store (4 GiB - 1).
+	fat32MaxFileSize = 1<<32 - 1
+	// windowsMaxPath is MAX_PATH including the "X:\" drive prefix and terminator.
+	windowsMaxPath = 260
+)
+
+// copyCompatIssues lists reasons a file may fail to copy to FAT32 media or Windows shares.
+// Paths are measured relative to root, as they would land on the target.
+func copyCompatIssues(path string, size int64, root string) []string {
+	var issues []string
+	if size > fat32MaxFileSize {
+		issues = append(issues, ">4GB FAT32")
+	}
+
+	rel, err := filepath.Rel(root, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		rel = filepath.Base(path)
+	}
+	// "X:\" prefix plus the NUL terminator count toward MAX_PATH.
+	if len(utf16.Encode([]rune(rel)))+4 > windowsMaxPath {
+		issues = append(issues, "long path")
+	}
+	return issues
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCopyCompatIssues(t *testing.T) {
+	root := "/Users/demo/Movies"
+	longPath := root + "/" + strings.Repeat("a", 300) + ".mov"
+
+	tests := []struct {
+		name string
+		path string
+		size int64
+		want []string
+	}{
+		{"small file", root + "/clip.mov", 1 << 30, nil},
+		{"exactly fat32 max", root + "/clip.mov", fat32MaxFileSize, nil},
+		{"over fat32 max", root + "/raw.mov", 5 << 30, []string{">4GB FAT32"}},
+		{"long path", longPath, 1 << 20, []string{"long path"}},
+		{"both", longPath, 5 << 30, []string{">4GB FAT32", "long path"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := copyCompatIssues(tt.path, tt.size, root); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("copyCompatIssues() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync/atomic"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// compressKind is how the "%" action shrinks a folder.
+type compressKind int
+
+const (
+	// compressTransparent rewrites files with APFS compression, like afsctool: the
+	// folder stays where it is and apps read it as before.
+	compressTransparent compressKind = iota
+	// compressArchive packs the folder into a .tar.zst beside it and removes the folder.
+	compressArchive
+)
+
+func (k compressKind) label() string {
+	if k == compressArchive {
+		return "archive to .tar.zst"
+	}
+	return "APFS compression"
+}
+
+// compressStagingSuffix names the copy or archive being written, so a crash never
+// leaves something that looks finished.
+const compressStagingSuffix = ".mole-compressing"
+
+type compressDoneMsg struct {
+	Path   string
+	Kind   compressKind
+	Output string // The archive, or Path again for APFS compression
+	Before int64  // Allocated bytes before
+	After  int64  // Allocated bytes of Output
+	Err    error
+}
+
+// zstdAvailable reports whether archives can be written; macOS does not ship zstd.
+func zstdAvailable() bool {
+	_, err := exec.LookPath("zstd")
+	return err == nil
+}
+
+// archivePathFor is where the archive of path goes, next to it.
+func archivePathFor(path string) string {
+	return path + ".tar.zst"
+}
+
+// compressCmd compresses path, adding each file handled to counter.
+func compressCmd(path string, kind compressKind, counter *int64) tea.Cmd {
+	return func() tea.Msg {
+		msg := compressDoneMsg{Path: path, Kind: kind}
+		if err := cleanRunningError(); err != nil {
+			msg.Err = err
+			return msg
+		}
+		if release, err := acquireLock(analyzeLockFile); err == nil {
+			defer release()
+		}
+		msg.Before, _ = getDirectorySizeFromDu(path)
+		if kind == compressArchive {
+			msg.Output, msg.Err = archiveFolder(path, counter)
+		} else {
+			msg.Output, msg.Err = compressFolderInPlace(path, counter)
+		}
+		if msg.Err != nil {
+			return msg
+		}
+		if info, err := os.Lstat(msg.Output); err == nil && !info.IsDir() {
+			msg.After = getActualFileSize(msg.Output, info)
+		} else {
+			msg.After, _ = getDirectorySizeFromDu(msg.Output)
+		}
+		recordChanges([]string{path})
+		appendLedger("SUCCESS", fmt.Sprintf("analyze compressed %s (%s): %s to %s",
+			path, kind.label(), humanizeBytes(msg.Before), humanizeBytes(msg.After)))
+		return msg
+	}
+}
+
+// compressFolderInPlace copies path with ditto --hfsCompression, then swaps the copy in.
+// The original is removed only once the compressed copy has been checked and has
+// taken its place.
+func compressFolderInPlace(path string, counter *int64) (string, error) {
+	staging := path + compressStagingSuffix
+	old := staging + "-old"
+	_ = os.RemoveAll(staging)
+	if err := runCounting(counter, "ditto", "-V", "--hfsCompression", path, staging); err != nil {
+		_ = os.RemoveAll(staging)
+		return "", err
+	}
+	if err := sameTree(path, staging); err != nil {
+		_ = os.RemoveAll(staging)
+		return "", err
+	}
+	if err := os.Rename(path, old); err != nil {
+		_ = os.RemoveAll(staging)
+		return "", err
+	}
+	if err := os.Rename(staging, path); err != nil {
+		_ = os.Rename(old, path)
+		_ = os.RemoveAll(staging)
+		return "", err
+	}
+	if _, err := deletePathWithProgress(old, nil); err != nil {
+		return path, fmt.Errorf("compressed, but the original copy is left at %s: %w", old, err)
+	}
+	return path, nil
+}
+
+// archiveFolder streams tar into zstd, checks the archive and then removes path.
+func archiveFolder(path string, counter *int64) (string, error) {
+	output := archivePathFor(path)
+	if _, err := os.Lstat(output); err == nil {
+		return "", fmt.Errorf("%s already exists", displayPath(output))
+	}
+	staging := output + compressStagingSuffix
+	tar := exec.Command("tar", "-c", "-v", "-f", "-", "-C", filepath.Dir(path), filepath.Base(path))
+	zstd := exec.Command("zstd", "-q", "-T0", "-f", "-o", staging)
+	pipe, err := tar.StdoutPipe()
+	if err != nil {
+		return "", err
+	}
+	zstd.Stdin = pipe
+	if err := zstd.Start(); err != nil {
+		return "", err
+	}
+	tarErr := runCountingCmd(counter, tar)
+	zstdErr := zstd.Wait()
+	if tarErr == nil {
+		tarErr = zstdErr
+	}
+	if tarErr == nil {
+		tarErr = exec.Command("zstd", "-q", "-t", staging).Run()
+	}
+	if tarErr == nil {
+		tarErr = os.Rename(staging, output)
+	}
+	if tarErr != nil {
+		_ = os.Remove(staging)
+		return "", tarErr
+	}
+	if _, err := deletePathWithProgress(path, nil); err != nil {
+		return output, fmt.Errorf("archived, but %s could not be removed: %w", displayPath(path), err)
+	}
+	return output, nil
+}
+
+// runCounting runs a command whose verbose output names one file per line on stderr.
+func runCounting(counter *int64, name string, args ...string) error {
+	return runCountingCmd(counter, exec.Command(name, args...))
+}
+
+func runCountingCmd(counter *int64, cmd *exec.Cmd) error {
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	var last string
+	scanner := bufio.NewScanner(stderr)
+	for scanner.Scan() {
+		last = scanner.Text()
+		if counter != nil {
+			atomic.AddInt64(counter, 1)
+		}
+	}
+	_, _ = io.Copy(io.Discard, stderr)
+	if err := cmd.Wait(); err != nil {
+		if last != "" {
+			return fmt.Errorf("%s: %v (%s)", filepath.Base(cmd.Path), err, last)
+		}
+		return fmt.Errorf("%s: %v", filepath.Base(cmd.Path), err)
+	}
+	return nil
+}
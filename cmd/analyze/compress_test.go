@@ -0,0 +1,100 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunCountingCmd(t *testing.T) {
+	var count int64
+	if err := runCountingCmd(&count, exec.Command("sh", "-c", "printf 'a\\nb\\nc\\n' >&2")); err != nil || count != 3 {
+		t.Fatalf("count = %d, %v", count, err)
+	}
+	err := runCountingCmd(nil, exec.Command("sh", "-c", "echo 'no space left' >&2; exit 1"))
+	if err == nil || !strings.Contains(err.Error(), "no space left") {
+		t.Fatalf("failure should carry the last line: %v", err)
+	}
+}
+
+func TestArchiveFolder(t *testing.T) {
+	if !zstdAvailable() {
+		t.Skip("zstd is not installed")
+	}
+	t.Setenv("HOME", t.TempDir())
+	dir := filepath.Join(t.TempDir(), "logs")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a.log", "b.log"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(strings.Repeat("line\n", 20000)), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var done int64
+	output, err := archiveFolder(dir, &done)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output != archivePathFor(dir) || done < 2 {
+		t.Errorf("output %s, %d files counted", output, done)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("the folder should be gone once archived: %v", err)
+	}
+	if _, err := os.Stat(output + compressStagingSuffix); !os.IsNotExist(err) {
+		t.Errorf("the staging file should be renamed: %v", err)
+	}
+	if _, err := archiveFolder(dir, nil); err == nil {
+		t.Error("an existing archive must not be overwritten")
+	}
+}
+
+func TestCompressFolderInPlaceChecksCopy(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	bin := t.TempDir()
+	ditto := filepath.Join(bin, "ditto")
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	dir := filepath.Join(t.TempDir(), "logs")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "a.log"), []byte("data"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// An empty copy must not replace the folder.
+	if err := os.WriteFile(ditto, []byte("#!/bin/sh\nmkdir -p \"$4\"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := compressFolderInPlace(dir, nil); err == nil {
+		t.Fatal("an incomplete copy should be refused")
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "a.log")); err != nil || string(data) != "data" {
+		t.Fatalf("original should be kept: %q, %v", data, err)
+	}
+
+	// A leftover staging folder from an earlier run is cleared before copying.
+	staging := dir + compressStagingSuffix
+	if err := os.MkdirAll(staging, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(staging, "stale"), []byte("old"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(ditto, []byte("#!/bin/sh\nmkdir -p \"$4\" && cp -R \"$3/.\" \"$4\"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := compressFolderInPlace(dir, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "stale")); !os.IsNotExist(err) {
+		t.Errorf("stale staging contents should not be merged in: %v", err)
+	}
+	if _, err := os.Stat(staging); !os.IsNotExist(err) {
+		t.Errorf("the staging folder should be gone: %v", err)
+	}
+}
@@ -0,0 +1,122 @@
+//go:build darwin
+
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync/atomic"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// compressJob is the "%" folder being confirmed, then compressed.
+type compressJob struct {
+	Entry   dirEntry
+	Kind    compressKind
+	Running bool
+	Done    *int64 // Files handled so far
+}
+
+// openCompress asks how to compress the selected folder.
+func (m model) openCompress() (tea.Model, tea.Cmd) {
+	if m.inventory != nil {
+		m.status = fmt.Sprintf("%s is a read-only listing", m.inventory.Source)
+		return m, nil
+	}
+	if m.compress != nil && m.compress.Running {
+		m.status = "Already compressing, wait for it to finish"
+		return m, nil
+	}
+	entry, ok := m.selectedEntry()
+	if !ok || m.inOverviewMode() || m.showLargeFiles || !entry.IsDir || entry.Dataless {
+		m.status = "Select a local folder to compress"
+		return m, nil
+	}
+	if isSIPProtected(entry.Path) {
+		m.status = fmt.Sprintf("%s is protected by macOS", displayPath(entry.Path))
+		return m, nil
+	}
+	m.compress = &compressJob{Entry: entry}
+	return m, nil
+}
+
+// updateCompressConfirmKey starts APFS compression on t or Enter, an archive on a,
+// and cancels on anything else.
+func (m model) updateCompressConfirmKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	job := m.compress
+	switch msg.String() {
+	case "t", "enter", "%":
+		job.Kind = compressTransparent
+	case "a":
+		if !zstdAvailable() {
+			m.status = "Archiving needs zstd: brew install zstd"
+			return m, nil
+		}
+		// Archiving removes the folder, which safe mode only allows for some folders.
+		if _, ok := safeDeleteCategory(job.Entry.Path); m.safeMode && !ok {
+			m.status = safeModeBlockReason(job.Entry.Path)
+			return m, nil
+		}
+		job.Kind = compressArchive
+	default:
+		m.compress = nil
+		m.status = "Cancelled"
+		return m, nil
+	}
+	var done int64
+	job.Running, job.Done = true, &done
+	m.status = fmt.Sprintf("Compressing %s (%s)...", displayName(job.Entry.Path, job.Entry.Name), job.Kind.label())
+	return m, tea.Batch(compressCmd(job.Entry.Path, job.Kind, job.Done), tickCmd())
+}
+
+// finishCompress reports the size change and rescans the folder.
+func (m model) finishCompress(msg compressDoneMsg) (tea.Model, tea.Cmd) {
+	m.compress = nil
+	name := sanitizeName(filepath.Base(msg.Path))
+	if msg.Err != nil && msg.Output == "" {
+		m.status = fmt.Sprintf("Could not compress %s: %v", name, msg.Err)
+		return m, nil
+	}
+	m.status = fmt.Sprintf("Compressed %s: %s → %s", name, humanizeBytes(msg.Before), humanizeBytes(msg.After))
+	if saved := msg.Before - msg.After; saved > 0 && msg.Before > 0 {
+		m.status += fmt.Sprintf(", saved %s (%d%%)", humanizeBytes(saved), saved*100/msg.Before)
+	} else {
+		m.status += ", already compact"
+	}
+	if msg.Err != nil {
+		m.status += fmt.Sprintf("; %v", msg.Err)
+	}
+	invalidateCache(msg.Path)
+	invalidateCache(m.path)
+	for i := range m.history {
+		m.history[i].Dirty = true
+	}
+	if m.inOverviewMode() || m.scanning {
+		return m, nil
+	}
+	m.scanning = true
+	atomic.StoreInt64(m.filesScanned, 0)
+	atomic.StoreInt64(m.dirsScanned, 0)
+	atomic.StoreInt64(m.bytesScanned, 0)
+	return m, tea.Batch(m.scanCmd(m.path), tickCmd())
+}
+
+// renderCompress is the footer asking how to compress, or the running job's progress.
+func (m model) renderCompress() string {
+	job := m.compress
+	if job.Running {
+		progress := formatNumber(atomic.LoadInt64(job.Done))
+		if job.Entry.Files > 0 {
+			progress += " of " + formatNumber(job.Entry.Files)
+		}
+		return fmt.Sprintf("%s%s Compressing:%s %s, %s files done (%s)\n",
+			colorYellow, spinnerFrames[m.spinner], colorReset, sanitizeName(job.Entry.Name), progress, job.Kind.label())
+	}
+	archive := "a archive to .tar.zst and remove it"
+	if !zstdAvailable() {
+		archive = "archive needs zstd"
+	}
+	return fmt.Sprintf("%sCompress:%s %s (%s)  %sT APFS compression, stays usable  |  %s  |  ESC cancel%s\n",
+		colorYellow, colorReset, sanitizeName(job.Entry.Name), humanizeBytes(job.Entry.Size), colorGray, archive, colorReset)
+}
@@ -0,0 +1,435 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// configFileName holds user settings in a small subset of TOML:
+//
+//	large_file_threshold = "500MB"
+//	max_entries = 50
+//	fold_dirs = ["Library", ".pnpm"]
+//	large_files_include = [".sql", ".csv"]  # Listed although usually hidden as code or data
+//	large_files_exclude = [".log"]          # Never listed as large files
+//	auto_fold_children = 50000   # Fold any folder with more entries, 0 turns it off
+//	exclude = ["~/Backups", "/Volumes/NAS/*"]
+//	theme = "mono"
+//	default_sort = "name"         # or "weighted" to rank by size and item count
+//	rank_item_weight = "256KB"    # What each file and folder adds to the weighted rank
+//	critical_free_space = "1GB"   # Below this, caches and exports stop writing to a volume
+//	hide_recent_files = "24h"     # Leave newer files out of large and stale files, or "off"
+//	unused_hint_after = "180d"   # or "off"
+//	unused_hint_unit = "months"  # auto, days, weeks, months or years
+//	unused_hint_style = "long"   # "short" (>6mo) or "long" (unused 6 months)
+//	daemon_roots = ["~", "~/Projects"]
+//	daemon_interval = "6h"
+//	roots = ["~", "/Volumes/Work"]  # What the overview lists instead of Home, Applications...
+//	safe_mode = true                # As --safe
+//	permanent_delete = false        # As --permanent
+//
+//	[keys]
+//	delete = "d"
+//
+// [workspace.<name>] tables hold settings for one context; see workspaceTable.
+const configFileName = "config.toml"
+
+// userConfig is the decoded config file; zero values keep the built-in defaults.
+type userConfig struct {
+	LargeFileThreshold int64
+	MaxEntries         int
+	FoldDirs           []string
+	LargeFilesInclude  []string // Normalized extensions
+	LargeFilesExclude  []string
+	AutoFoldChildren   int64 // Negative turns automatic folding off
+	Exclude            []string
+	Theme              string
+	DefaultSort        entrySortMode
+	RankItemWeight     int64
+	CriticalFreeSpace  int64
+	HideRecentFiles    time.Duration // Zero keeps recent files listed
+	HasDefaultSort     bool
+	UnusedHintAfter    time.Duration // Negative turns the unused hint off
+	UnusedHintUnit     ageUnit
+	UnusedHintLong     bool
+	KeyRemap           map[string]string // Bound key to the built-in key it stands for
+	DaemonRoots        []string          // Folders mo daemon keeps scanned, ~ not yet expanded
+	DaemonInterval     time.Duration
+	Roots              []string // Folders the overview lists instead of its own, ~ not yet expanded
+	SafeMode           bool
+	PermanentDelete    bool
+	Workspace          string // Workspace the settings came from, "" for the top-level ones
+}
+
+// configValue is a string, int64, bool or []string.
+type configValue any
+
+// parseConfigTOML reads key = value pairs, [table] headers and single- or multi-line
+// string arrays. Keys inside a table come back as "table.key".
+func parseConfigTOML(data string) (map[string]configValue, error) {
+	values := make(map[string]configValue)
+	table := ""
+	lines := strings.Split(data, "\n")
+	for i := 0; i < len(lines); i++ {
+		lineNo := i + 1
+		line := strings.TrimSpace(stripConfigComment(lines[i]))
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			table = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+		key, raw, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key = value", lineNo)
+		}
+		key, raw = strings.Trim(strings.TrimSpace(key), `"`), strings.TrimSpace(raw)
+		if strings.HasPrefix(raw, "[") {
+			for !strings.HasSuffix(raw, "]") && i+1 < len(lines) {
+				i++
+				raw += " " + strings.TrimSpace(stripConfigComment(lines[i]))
+			}
+		}
+		value, err := parseConfigValue(raw)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s: %v", lineNo, key, err)
+		}
+		if table != "" {
+			key = table + "." + key
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+// stripConfigComment drops a trailing # comment that is not inside a string.
+func stripConfigComment(line string) string {
+	inString := false
+	for i, r := range line {
+		switch {
+		case r == '"':
+			inString = !inString
+		case r == '#' && !inString:
+			return line[:i]
+		}
+	}
+	return line
+}
+
+func parseConfigValue(raw string) (configValue, error) {
+	switch {
+	case strings.HasPrefix(raw, "["):
+		if !strings.HasSuffix(raw, "]") {
+			return nil, fmt.Errorf("unterminated array")
+		}
+		var items []string
+		for _, item := range strings.Split(raw[1:len(raw)-1], ",") {
+			item = strings.TrimSpace(item)
+			if item == "" {
+				continue
+			}
+			s, err := strconv.Unquote(item)
+			if err != nil {
+				return nil, fmt.Errorf("array items must be quoted strings")
+			}
+			items = append(items, s)
+		}
+		return items, nil
+	case strings.HasPrefix(raw, `"`):
+		s, err := strconv.Unquote(raw)
+		if err != nil {
+			return nil, fmt.Errorf("bad string %s", raw)
+		}
+		return s, nil
+	case raw == "true" || raw == "false":
+		return raw == "true", nil
+	}
+	n, err := strconv.ParseInt(strings.ReplaceAll(raw, "_", ""), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported value %s", raw)
+	}
+	return n, nil
+}
+
+// parseByteSize reads sizes such as 500MB, 1.5G or a plain byte count.
+func parseByteSize(s string) (int64, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	units := []struct {
+		suffix string
+		scale  float64
+	}{{"TB", 1 << 40}, {"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"T", 1 << 40}, {"G", 1 << 30}, {"M", 1 << 20}, {"K", 1 << 10}, {"B", 1}}
+	scale := 1.0
+	for _, unit := range units {
+		if strings.HasSuffix(s, unit.suffix) {
+			s, scale = strings.TrimSpace(strings.TrimSuffix(s, unit.suffix)), unit.scale
+			break
+		}
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return int64(n * scale), nil
+}
+
+// decodeUserConfig checks types and ranges of the known settings.
+func decodeUserConfig(values map[string]configValue) (userConfig, error) {
+	var cfg userConfig
+	bindings := make(map[string][]string)
+	for key, value := range values {
+		var err error
+		switch key {
+		case "large_file_threshold":
+			switch v := value.(type) {
+			case int64:
+				cfg.LargeFileThreshold = v
+			case string:
+				cfg.LargeFileThreshold, err = parseByteSize(v)
+			default:
+				err = fmt.Errorf("expects a size such as \"500MB\"")
+			}
+			if err == nil && cfg.LargeFileThreshold <= 0 {
+				err = fmt.Errorf("must be positive")
+			}
+		case "rank_item_weight":
+			switch v := value.(type) {
+			case int64:
+				cfg.RankItemWeight = v
+			case string:
+				cfg.RankItemWeight, err = parseByteSize(v)
+			default:
+				err = fmt.Errorf("expects a size such as \"256KB\"")
+			}
+			if err == nil && cfg.RankItemWeight <= 0 {
+				err = fmt.Errorf("must be positive")
+			}
+		case "critical_free_space":
+			switch v := value.(type) {
+			case int64:
+				cfg.CriticalFreeSpace = v
+			case string:
+				cfg.CriticalFreeSpace, err = parseByteSize(v)
+			default:
+				err = fmt.Errorf("expects a size such as \"1GB\"")
+			}
+			if err == nil && cfg.CriticalFreeSpace <= 0 {
+				err = fmt.Errorf("must be positive")
+			}
+		case "auto_fold_children":
+			n, ok := value.(int64)
+			if !ok || n < 0 {
+				err = fmt.Errorf("expects a number of entries, 0 to turn it off")
+			}
+			cfg.AutoFoldChildren = n
+			if n == 0 {
+				cfg.AutoFoldChildren = -1
+			}
+		case "max_entries":
+			n, ok := value.(int64)
+			if !ok || n < 1 || n > 1000 {
+				err = fmt.Errorf("expects a number between 1 and 1000")
+			}
+			cfg.MaxEntries = int(n)
+		case "fold_dirs", "exclude", "daemon_roots", "roots":
+			items, ok := value.([]string)
+			switch {
+			case !ok:
+				err = fmt.Errorf("expects an array of strings")
+			case key == "fold_dirs":
+				cfg.FoldDirs = items
+			case key == "exclude":
+				cfg.Exclude = items
+			case key == "roots":
+				cfg.Roots = items
+			default:
+				cfg.DaemonRoots = items
+			}
+		case "safe_mode", "permanent_delete":
+			on, ok := value.(bool)
+			if !ok {
+				err = fmt.Errorf("expects true or false")
+			}
+			if key == "safe_mode" {
+				cfg.SafeMode = on
+			} else {
+				cfg.PermanentDelete = on
+			}
+		case "large_files_include", "large_files_exclude":
+			items, ok := value.([]string)
+			if !ok {
+				err = fmt.Errorf("expects an array of extensions such as \".sql\"")
+				break
+			}
+			exts := make([]string, 0, len(items))
+			for _, item := range items {
+				ext, extErr := normalizeExtension(item)
+				if extErr != nil {
+					err = extErr
+					break
+				}
+				exts = append(exts, ext)
+			}
+			if key == "large_files_include" {
+				cfg.LargeFilesInclude = exts
+			} else {
+				cfg.LargeFilesExclude = exts
+			}
+		case "daemon_interval":
+			interval, _ := value.(string)
+			if cfg.DaemonInterval, err = time.ParseDuration(interval); err != nil || cfg.DaemonInterval < minDaemonInterval {
+				err = fmt.Errorf("expects a duration of at least %v, such as \"6h\"", minDaemonInterval)
+			}
+		case "theme":
+			name, ok := value.(string)
+			if _, known := colorThemes[name]; !ok || !known {
+				err = fmt.Errorf("expects one of %s", strings.Join(colorThemeNames(), ", "))
+			}
+			cfg.Theme = name
+		case "default_sort":
+			name, _ := value.(string)
+			mode, ok := parseEntrySort(name)
+			if !ok {
+				err = fmt.Errorf("expects %s", entrySortNames())
+			}
+			cfg.DefaultSort, cfg.HasDefaultSort = mode, ok
+		case "unused_hint_after":
+			age, _ := value.(string)
+			if age == "off" {
+				cfg.UnusedHintAfter = -1
+			} else if cfg.UnusedHintAfter, err = parseSearchAge(age); err != nil {
+				err = fmt.Errorf("expects an age such as \"90d\", \"6m\" or \"off\"")
+			}
+		case "hide_recent_files":
+			age, _ := value.(string)
+			if age != "off" {
+				if cfg.HideRecentFiles, err = parseSearchAge(age); err != nil {
+					if cfg.HideRecentFiles, err = time.ParseDuration(age); err != nil || cfg.HideRecentFiles <= 0 {
+						err = fmt.Errorf("expects an age such as \"24h\", \"3d\" or \"off\"")
+					}
+				}
+			}
+		case "unused_hint_unit":
+			name, _ := value.(string)
+			unit, ok := parseAgeUnit(name)
+			if !ok {
+				err = fmt.Errorf("expects one of %s", strings.Join(ageUnitNames, ", "))
+			}
+			cfg.UnusedHintUnit = unit
+		case "unused_hint_style":
+			style, _ := value.(string)
+			if style != "short" && style != "long" {
+				err = fmt.Errorf("expects short or long")
+			}
+			cfg.UnusedHintLong = style == "long"
+		default:
+			action, ok := strings.CutPrefix(key, "keys.")
+			if !ok {
+				err = fmt.Errorf("unknown setting")
+				break
+			}
+			switch v := value.(type) {
+			case string:
+				bindings[action] = []string{v}
+			case []string:
+				bindings[action] = v
+			default:
+				err = fmt.Errorf("expects a key or an array of keys")
+			}
+		}
+		if err != nil {
+			return userConfig{}, fmt.Errorf("%s: %v", key, err)
+		}
+	}
+	remap, err := parseKeyBindings(bindings)
+	if err != nil {
+		return userConfig{}, err
+	}
+	cfg.KeyRemap = remap
+	return cfg, nil
+}
+
+// loadConfigValues reads config.toml from the Mole config directory; a missing file
+// holds no settings.
+func loadConfigValues() (map[string]configValue, error) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return nil, nil
+	}
+	path := filepath.Join(configDir, configFileName)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	values, err := parseConfigTOML(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s %v", displayPath(path), err)
+	}
+	return values, nil
+}
+
+// loadUserConfig decodes values with the named workspace laid over them, or the
+// default workspace when workspace is "".
+func loadUserConfig(values map[string]configValue, workspace string) (userConfig, error) {
+	if workspace == "" {
+		workspace = defaultWorkspace(values)
+	}
+	values, err := workspaceValues(values, workspace)
+	if err != nil {
+		return userConfig{}, err
+	}
+	cfg, err := decodeUserConfig(values)
+	if err != nil {
+		return userConfig{}, fmt.Errorf("%s: %v", configFileName, err)
+	}
+	cfg.Workspace = workspace
+	return cfg, nil
+}
+
+// applyUserConfig replaces the built-in defaults the scanner and view read.
+func applyUserConfig(cfg userConfig, home string) {
+	if cfg.LargeFileThreshold > 0 {
+		minLargeFileSize = cfg.LargeFileThreshold
+	}
+	if cfg.MaxEntries > 0 {
+		maxEntries = cfg.MaxEntries
+	}
+	if cfg.RankItemWeight > 0 {
+		itemWeight.Store(cfg.RankItemWeight)
+	}
+	if cfg.CriticalFreeSpace > 0 {
+		criticalFreeSpace.Store(cfg.CriticalFreeSpace)
+	}
+	if cfg.HideRecentFiles > 0 {
+		recentFileAge.Store(int64(cfg.HideRecentFiles))
+		hideRecentFiles.Store(true)
+	}
+	if cfg.HasDefaultSort {
+		weightedRanking.Store(cfg.DefaultSort == entrySortWeighted)
+	}
+	for _, name := range cfg.FoldDirs {
+		foldDirs[name] = true
+	}
+	applyFileTypeLists(cfg.LargeFilesInclude, cfg.LargeFilesExclude)
+	if cfg.AutoFoldChildren != 0 {
+		autoFoldChildren.Store(max(cfg.AutoFoldChildren, 0))
+	}
+	setExcludePatterns(cfg.Exclude, home)
+	setOverviewRoots(cfg.Roots, home)
+	activeWorkspace = cfg.Workspace
+	if cfg.Theme != "" {
+		applyColorTheme(cfg.Theme)
+	}
+	if cfg.UnusedHintAfter != 0 {
+		unusedHints.After = cfg.UnusedHintAfter
+	}
+	unusedHints.Unit, unusedHints.Long = cfg.UnusedHintUnit, cfg.UnusedHintLong
+	keyRemap = cfg.KeyRemap
+}
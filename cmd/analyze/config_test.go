@@ -0,0 +1,92 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sampleConfig = `# Mole analyzer settings
+large_file_threshold = "1.5GB"
+max_entries = 50
+fold_dirs = ["Library", ".pnpm"]   # also fold these
+auto_fold_children = 20000
+exclude = [
+  "~/Backups",
+  "/Volumes/NAS/*",
+]
+theme = "mono"
+default_sort = "name"
+unused_hint_after = "180d"
+unused_hint_unit = "weeks"
+unused_hint_style = "long"
+
+[keys]
+delete = "x"
+`
+
+func TestParseConfigTOML(t *testing.T) {
+	values, err := parseConfigTOML(sampleConfig)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if values["keys.delete"] != "x" {
+		t.Fatalf("table keys should be qualified: %v", values)
+	}
+	cfg, err := decodeUserConfig(values)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if cfg.LargeFileThreshold != 3<<29 || cfg.MaxEntries != 50 || cfg.Theme != "mono" {
+		t.Fatalf("unexpected config: %+v", cfg)
+	}
+	if !reflect.DeepEqual(cfg.Exclude, []string{"~/Backups", "/Volumes/NAS/*"}) || len(cfg.FoldDirs) != 2 || cfg.AutoFoldChildren != 20000 {
+		t.Fatalf("unexpected lists: %+v", cfg)
+	}
+	if !cfg.HasDefaultSort || cfg.DefaultSort != entrySortName {
+		t.Fatalf("default sort not decoded: %+v", cfg)
+	}
+	if cfg.UnusedHintAfter != 180*24*time.Hour || cfg.UnusedHintUnit != ageUnitWeeks || !cfg.UnusedHintLong {
+		t.Fatalf("unused hint settings not decoded: %+v", cfg)
+	}
+}
+
+func TestDecodeUserConfigRejectsBadValues(t *testing.T) {
+	for _, tc := range []struct{ config, want string }{
+		{`max_entries = "lots"`, "max_entries"},
+		{`theme = "neon"`, "theme"},
+		{`colour = "red"`, "unknown setting"},
+		{`large_file_threshold = "big"`, "invalid size"},
+		{`unused_hint_after = "soon"`, "unused_hint_after"},
+		{`unused_hint_unit = "decades"`, "unused_hint_unit"},
+		{`daemon_interval = "1m"`, "daemon_interval"},
+		{`auto_fold_children = -5`, "auto_fold_children"},
+		{`large_files_include = [".tar.gz"]`, "not a file extension"},
+	} {
+		values, err := parseConfigTOML(tc.config)
+		if err == nil {
+			_, err = decodeUserConfig(values)
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("%s: expected error mentioning %q, got %v", tc.config, tc.want, err)
+		}
+	}
+	if _, err := parseConfigTOML("just words"); err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Fatalf("expected a line number, got %v", err)
+	}
+}
+
+func TestApplyUserConfigExcludes(t *testing.T) {
+	defer func() {
+		strategiesMu.Lock()
+		configStrategyRules = nil
+		strategiesLoaded = false
+		strategiesMu.Unlock()
+	}()
+	t.Setenv("HOME", t.TempDir())
+	applyUserConfig(userConfig{Exclude: []string{"/data/huge"}}, "/Users/me")
+	if got := scanStrategyFor("/data/huge/sets"); got != strategySkip {
+		t.Fatalf("excluded path should be skipped, got %v", got)
+	}
+}
@@ -1,13 +1,22 @@
 package main
 
-import "time"
+import (
+	"sort"
+	"time"
+)
+
+// Defaults that config.toml can override at startup.
+var (
+	maxEntries             = 30
+	minLargeFileSize int64 = 100 << 20
+)
 
 const (
-	maxEntries            = 30
 	maxLargeFiles         = 30
 	barWidth              = 24
-	minLargeFileSize      = 100 << 20
 	defaultViewport       = 12
+	defaultPathWidth      = 50  // Path width before the first tea.WindowSizeMsg
+	maxNameWidth          = 120 // Keeps the size column within reach on very wide terminals
 	overviewCacheTTL      = 7 * 24 * time.Hour
 	overviewCacheFile     = "overview_sizes.json"
 	duTimeout             = 30 * time.Second
@@ -188,6 +197,8 @@ var defaultSkipDirs = map[string]bool{
 	"Permissions": true,
 }
 
+// skipExtensions are source, text and database files the large files view leaves out;
+// large_files_include and large_files_exclude in config.toml adjust the list.
 var skipExtensions = map[string]bool{
 	".go":     true,
 	".js":     true,
@@ -234,7 +245,7 @@ var skipExtensions = map[string]bool{
 
 var spinnerFrames = []string{"|", "/", "-", "\\", "|", "/", "-", "\\"}
 
-const (
+var (
 	colorPurple     = "\033[0;35m"
 	colorPurpleBold = "\033[1;35m"
 	colorGray       = "\033[0;90m"
@@ -246,3 +257,32 @@ const (
 	colorReset      = "\033[0m"
 	colorBold       = "\033[1m"
 )
+
+// colorThemes are the palettes config.toml can pick with theme = "<name>".
+var colorThemes = map[string]func(){
+	"default": func() {},
+	"mono": func() {
+		colorPurple, colorPurpleBold, colorGray, colorRed, colorYellow = "", colorBold, "", "", ""
+		colorGreen, colorBlue, colorCyan = "", "", ""
+	},
+	"bright": func() {
+		colorPurple, colorPurpleBold, colorGray = "\033[0;95m", "\033[1;95m", "\033[0;37m"
+		colorRed, colorYellow, colorGreen = "\033[0;91m", "\033[0;93m", "\033[0;92m"
+		colorBlue, colorCyan = "\033[0;94m", "\033[0;96m"
+	},
+}
+
+func applyColorTheme(name string) {
+	if apply, ok := colorThemes[name]; ok {
+		apply()
+	}
+}
+
+func colorThemeNames() []string {
+	names := make([]string, 0, len(colorThemes))
+	for name := range colorThemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
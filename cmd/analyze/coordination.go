@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/tw93/mole/internal/coordination"
+)
+
+// Lock and journal files shared with mo clean; see internal/coordination.
+const (
+	cleanLockFile   = coordination.CleanLock
+	analyzeLockFile = coordination.AnalyzeLock
+	changesFile     = coordination.ChangesFile
+)
+
+// changesPollInterval is how often the analyzer follows changes.log.
+const changesPollInterval = 2 * time.Second
+
+// stateChangesMsg carries paths other Mole processes removed since the last poll.
+type stateChangesMsg struct {
+	Paths        []string
+	Offset       int64 // Where the next poll resumes reading changes.log
+	CleanRunning bool
+	LowSpace     int64 // Free bytes on the state volume when critically low, else zero
+}
+
+func coordinationPath(name string) (string, error) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, name), nil
+}
+
+// lockHolder returns the process holding the named lock, or false when it is free or stale.
+func lockHolder(name string) (pid int, held bool) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return 0, false
+	}
+	return coordination.LockHolder(configDir, name)
+}
+
+// acquireLock takes the named lock for this process. The returned function releases it.
+func acquireLock(name string) (func(), error) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	return coordination.AcquireLock(configDir, name)
+}
+
+// cleanRunningError is returned instead of deleting while mo clean is at work.
+func cleanRunningError() error {
+	if pid, held := lockHolder(cleanLockFile); held {
+		return fmt.Errorf("mo clean is running (pid %d), try again when it finishes", pid)
+	}
+	return nil
+}
+
+// recordChanges appends removed paths to changes.log for other Mole processes.
+func recordChanges(paths []string) {
+	if configDir, err := getConfigDir(); err == nil {
+		coordination.RecordChanges(configDir, paths)
+	}
+}
+
+// changesEnd is where following changes.log starts: only later changes matter.
+func changesEnd() int64 {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return 0
+	}
+	return coordination.ChangesEnd(configDir)
+}
+
+// readChanges returns paths other processes recorded after offset and the offset to
+// resume from.
+func readChanges(offset int64) ([]string, int64) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return nil, offset
+	}
+	return coordination.ReadChanges(configDir, offset)
+}
+
+// pollChangesCmd waits one interval, then reports what changed and whether mo clean runs.
+func pollChangesCmd(offset int64) tea.Cmd {
+	return tea.Tick(changesPollInterval, func(time.Time) tea.Msg {
+		paths, next := readChanges(offset)
+		_, cleaning := lockHolder(cleanLockFile)
+		return stateChangesMsg{Paths: paths, Offset: next, CleanRunning: cleaning, LowSpace: lowSpaceFree()}
+	})
+}
+
+// pathsOverlap reports whether a and b are the same path or one contains the other.
+func pathsOverlap(a, b string) bool {
+	if a == b || a == "/" || b == "/" {
+		return true
+	}
+	return strings.HasPrefix(b, a+"/") || strings.HasPrefix(a, b+"/")
+}
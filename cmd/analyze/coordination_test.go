@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAcquireLockReplacesStaleHolder(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	release, err := acquireLock(analyzeLockFile)
+	if err != nil {
+		t.Fatalf("acquireLock: %v", err)
+	}
+	if pid, held := lockHolder(analyzeLockFile); !held || pid != os.Getpid() {
+		t.Fatalf("expected this process to hold the lock, got %d %v", pid, held)
+	}
+	if _, err := acquireLock(analyzeLockFile); err == nil {
+		t.Fatalf("a held lock should not be taken twice")
+	}
+	release()
+
+	// A lock left by an exited process is stale.
+	path, _ := coordinationPath(cleanLockFile)
+	if err := os.WriteFile(path, []byte("999999\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := cleanRunningError(); err != nil {
+		t.Fatalf("stale clean lock should not block deletes: %v", err)
+	}
+	release, err = acquireLock(cleanLockFile)
+	if err != nil {
+		t.Fatalf("stale lock should be replaced: %v", err)
+	}
+	defer release()
+	if err := cleanRunningError(); err == nil || !strings.Contains(err.Error(), "mo clean is running") {
+		t.Fatalf("expected a running clean to block deletes, got %v", err)
+	}
+}
+
+func TestReadChangesSkipsOwnAndResumes(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	start := changesEnd()
+
+	recordChanges([]string{"/Users/me/own"})
+	path, _ := coordinationPath(changesFile)
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fmt.Fprintf(file, "1\t/Users/me/Library/Caches/app\n1\trelative/ignored\n1\t/Users/me/partial")
+	file.Close()
+
+	paths, offset := readChanges(start)
+	if len(paths) != 1 || paths[0] != "/Users/me/Library/Caches/app" {
+		t.Fatalf("unexpected changes %v", paths)
+	}
+	if again, _ := readChanges(offset); len(again) != 0 {
+		t.Fatalf("changes should be read once, got %v", again)
+	}
+
+	// mo clean truncating the file starts reading over.
+	if err := os.WriteFile(path, []byte("1\t/tmp/x\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if paths, _ := readChanges(offset); len(paths) != 1 || paths[0] != filepath.Clean("/tmp/x") {
+		t.Fatalf("expected reading to restart after truncation, got %v", paths)
+	}
+}
+
+func TestPathsOverlap(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"/Users/me", "/Users/me/Library/Caches", true},
+		{"/Users/me/Library/Caches/app", "/Users/me/Library", true},
+		{"/Users/me/Lib", "/Users/me/Library", false},
+		{"/", "/anything", true},
+	}
+	for _, tc := range tests {
+		if got := pathsOverlap(tc.a, tc.b); got != tc.want {
+			t.Errorf("pathsOverlap(%q, %q) = %v, want %v", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
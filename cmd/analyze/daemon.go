@@ -0,0 +1,310 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// mo daemon rescans the configured roots every interval from a launchd agent, so the
+// analyzer opens on cached results and scan history gets regular data points.
+const (
+	daemonLabel           = "com.tw93.mole.daemon"
+	daemonLockFile        = "daemon.lock"
+	daemonLogFile         = "daemon.log"
+	defaultDaemonInterval = 6 * time.Hour
+	minDaemonInterval     = 15 * time.Minute
+	// daemonSubfolders is how many of a root's largest folders are scanned as well,
+	// the ones most likely to be opened next.
+	daemonSubfolders = 5
+	daemonWarmBudget = 2 * time.Minute
+)
+
+// daemonRoots expands the configured roots, defaulting to home.
+func daemonRoots(cfg userConfig, home string) []string {
+	if len(cfg.DaemonRoots) == 0 {
+		return []string{home}
+	}
+	var roots []string
+	seen := make(map[string]bool)
+	for _, root := range cfg.DaemonRoots {
+		if root == "~" || strings.HasPrefix(root, "~/") {
+			root = home + root[1:]
+		}
+		if !filepath.IsAbs(root) {
+			continue
+		}
+		root = filepath.Clean(root)
+		if !seen[root] {
+			seen[root] = true
+			roots = append(roots, root)
+		}
+	}
+	return roots
+}
+
+func daemonInterval(cfg userConfig) time.Duration {
+	if cfg.DaemonInterval > 0 {
+		return cfg.DaemonInterval
+	}
+	return defaultDaemonInterval
+}
+
+// daemonScan scans path as the analyzer would and stores the result in the scan cache
+// and scan history.
+func daemonScan(path string) (scanResult, error) {
+	var files, dirs, bytes int64
+	var current string
+	result, err := scanPathConcurrent(context.Background(), path, &files, &dirs, &bytes, &current, nil)
+	if err != nil {
+		return scanResult{}, err
+	}
+	if err := saveCacheToDisk(path, result); err != nil {
+		return result, err
+	}
+	return result, recordScanSnapshot(path, result)
+}
+
+// largestSubfolders returns up to n folders among entries, largest first.
+func largestSubfolders(entries []dirEntry, n int) []string {
+	var dirs []dirEntry
+	for _, entry := range entries {
+		if entry.IsDir && entry.Size > 0 && !isCloudPath(entry.Path) {
+			dirs = append(dirs, entry)
+		}
+	}
+	sort.SliceStable(dirs, func(a, b int) bool { return dirs[a].Size > dirs[b].Size })
+	paths := make([]string, 0, min(n, len(dirs)))
+	for _, entry := range dirs[:min(n, len(dirs))] {
+		paths = append(paths, entry.Path)
+	}
+	return paths
+}
+
+// daemonCycle scans every root and its largest subfolders, then refreshes overview
+// sizes and checks the alert thresholds. Progress goes to w, the daemon log.
+func daemonCycle(w io.Writer, roots []string) {
+	logf := func(format string, args ...any) {
+		fmt.Fprintf(w, "%s  %s\n", time.Now().Format("2006-01-02 15:04:05"), fmt.Sprintf(format, args...))
+	}
+	for _, root := range roots {
+		started := time.Now()
+		result, err := daemonScan(root)
+		if err != nil {
+			logf("%s: %v", root, err)
+			continue
+		}
+		scanned := 1
+		for _, path := range largestSubfolders(result.Entries, daemonSubfolders) {
+			if _, err := daemonScan(path); err != nil {
+				logf("%s: %v", path, err)
+				continue
+			}
+			scanned++
+		}
+		logf("%s: %s, %d folders scanned in %s", root, humanizeBytes(result.TotalSize), scanned, time.Since(started).Round(time.Second))
+	}
+	if refreshed := warmOverview(overviewPaths(), daemonWarmBudget); refreshed > 0 {
+		logf("refreshed %d overview sizes", refreshed)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	checkAlerts(ctx)
+}
+
+// runDaemonLoop runs a cycle now and then every interval, at throttled disk priority.
+// It returns only if another daemon already holds the lock.
+func runDaemonLoop(w io.Writer, roots []string, interval time.Duration) error {
+	release, err := acquireLock(daemonLockFile)
+	if err != nil {
+		return err
+	}
+	defer release()
+	_ = setDiskThrottled(true)
+	for {
+		daemonCycle(w, roots)
+		time.Sleep(interval)
+	}
+}
+
+func getDaemonPlistPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "Library", "LaunchAgents", daemonLabel+".plist"), nil
+}
+
+func xmlEscape(s string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
+// daemonPlist is the launch agent that starts `analyze-go --daemon run` at login and
+// restarts it if it crashes. Background process type lets macOS throttle it further.
+func daemonPlist(binary, logPath string) string {
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>%s</string>
+	<key>ProgramArguments</key>
+	<array>
+		<string>%s</string>
+		<string>--daemon</string>
+		<string>run</string>
+	</array>
+	<key>RunAtLoad</key>
+	<true/>
+	<key>KeepAlive</key>
+	<dict>
+		<key>SuccessfulExit</key>
+		<false/>
+	</dict>
+	<key>ProcessType</key>
+	<string>Background</string>
+	<key>LowPriorityIO</key>
+	<true/>
+	<key>Nice</key>
+	<integer>10</integer>
+	<key>StandardOutPath</key>
+	<string>%s</string>
+	<key>StandardErrorPath</key>
+	<string>%s</string>
+</dict>
+</plist>
+`, daemonLabel, xmlEscape(binary), xmlEscape(logPath), xmlEscape(logPath))
+}
+
+func launchctlDomain() string {
+	return fmt.Sprintf("gui/%d", os.Getuid())
+}
+
+// stableBinaryPath keeps the path the binary was started by rather than resolving
+// links, so the agent follows upgrades. A path that still points into a Homebrew keg
+// is moved to its opt link, which Homebrew repoints at each new version.
+func stableBinaryPath(binary string) string {
+	parts := strings.Split(binary, "/")
+	for i := 1; i+2 < len(parts)-1; i++ {
+		if parts[i] == "Cellar" {
+			stable := append(append([]string{}, parts[:i]...), "opt", parts[i+1])
+			return strings.Join(append(stable, parts[i+3:]...), "/")
+		}
+	}
+	return binary
+}
+
+// installDaemon writes the launch agent and loads it, replacing an older copy.
+func installDaemon() error {
+	binary, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	binary = stableBinaryPath(binary)
+	logPath, err := coordinationPath(daemonLogFile)
+	if err != nil {
+		return err
+	}
+	plistPath, err := getDaemonPlistPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(plistPath), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(plistPath, []byte(daemonPlist(binary, logPath)), 0644); err != nil {
+		return err
+	}
+	_ = exec.Command("launchctl", "bootout", launchctlDomain()+"/"+daemonLabel).Run()
+	if out, err := exec.Command("launchctl", "bootstrap", launchctlDomain(), plistPath).CombinedOutput(); err != nil {
+		return fmt.Errorf("launchctl bootstrap: %s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// uninstallDaemon unloads the launch agent and removes it; a missing agent is not an error.
+func uninstallDaemon() error {
+	plistPath, err := getDaemonPlistPath()
+	if err != nil {
+		return err
+	}
+	_ = exec.Command("launchctl", "bootout", launchctlDomain()+"/"+daemonLabel).Run()
+	if err := os.Remove(plistPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// writeDaemonStatus reports whether the agent is installed and running, and when each
+// root was last scanned.
+func writeDaemonStatus(w io.Writer, roots []string, interval time.Duration) {
+	installed := "not installed (mo daemon install)"
+	if plistPath, err := getDaemonPlistPath(); err == nil {
+		if _, err := os.Stat(plistPath); err == nil {
+			installed = "installed"
+		}
+	}
+	fmt.Fprintf(w, "Launch agent: %s\n", installed)
+	if pid, held := lockHolder(daemonLockFile); held {
+		fmt.Fprintf(w, "Running:      yes, process %d\n", pid)
+	} else {
+		fmt.Fprintln(w, "Running:      no")
+	}
+	fmt.Fprintf(w, "Interval:     %v\n", interval)
+	for _, root := range roots {
+		last := "never"
+		if history, err := loadScanHistory(root); err == nil && len(history) > 0 {
+			latest := history[len(history)-1]
+			last = fmt.Sprintf("%s, %s", latest.At.Format("2006-01-02 15:04"), humanizeBytes(latest.TotalSize))
+		}
+		fmt.Fprintf(w, "  %-30s last scan %s\n", displayPath(root), last)
+	}
+}
+
+// runDaemonCommand is `mo daemon [run|once|install|uninstall|status]`.
+func runDaemonCommand(args []string, cfg userConfig, home string) error {
+	action := "status"
+	if len(args) > 0 {
+		action = args[0]
+	}
+	roots, interval := daemonRoots(cfg, home), daemonInterval(cfg)
+	switch action {
+	case "run":
+		return runDaemonLoop(os.Stdout, roots, interval)
+	case "once":
+		release, err := acquireLock(daemonLockFile)
+		if err != nil {
+			return err
+		}
+		defer release()
+		daemonCycle(os.Stdout, roots)
+		return nil
+	case "install":
+		if err := installDaemon(); err != nil {
+			return err
+		}
+		fmt.Printf("Daemon installed, scanning %d folders every %v\n", len(roots), interval)
+		return nil
+	case "uninstall":
+		if err := uninstallDaemon(); err != nil {
+			return err
+		}
+		fmt.Println("Daemon removed")
+		return nil
+	case "status":
+		writeDaemonStatus(os.Stdout, roots, interval)
+		return nil
+	default:
+		return fmt.Errorf("unknown daemon action %q, expected run, once, install, uninstall or status", action)
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDaemonRootsAndInterval(t *testing.T) {
+	home := "/Users/me"
+	if got := daemonRoots(userConfig{}, home); !reflect.DeepEqual(got, []string{home}) {
+		t.Fatalf("no configured roots should scan home, got %v", got)
+	}
+	cfg := userConfig{DaemonRoots: []string{"~/Projects", "relative", "/Volumes/Work/", "~/Projects"}}
+	want := []string{"/Users/me/Projects", "/Volumes/Work"}
+	if got := daemonRoots(cfg, home); !reflect.DeepEqual(got, want) {
+		t.Fatalf("daemonRoots = %v, want %v", got, want)
+	}
+	if daemonInterval(userConfig{}) != defaultDaemonInterval || daemonInterval(userConfig{DaemonInterval: time.Hour}) != time.Hour {
+		t.Fatal("daemonInterval should fall back to the default only when unset")
+	}
+
+	values, err := parseConfigTOML(`daemon_roots = ["~/Projects"]` + "\n" + `daemon_interval = "2h"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = decodeUserConfig(values)
+	if err != nil || cfg.DaemonInterval != 2*time.Hour || len(cfg.DaemonRoots) != 1 {
+		t.Fatalf("daemon settings not decoded: %+v, %v", cfg, err)
+	}
+}
+
+func TestLargestSubfolders(t *testing.T) {
+	entries := []dirEntry{
+		{Path: "/r/small", Size: 10, IsDir: true},
+		{Path: "/r/file", Size: 500},
+		{Path: "/r/big", Size: 300, IsDir: true},
+		{Path: "/r/empty", IsDir: true},
+		{Path: "/r/mid", Size: 100, IsDir: true},
+	}
+	if got := largestSubfolders(entries, 2); !reflect.DeepEqual(got, []string{"/r/big", "/r/mid"}) {
+		t.Fatalf("largestSubfolders = %v", got)
+	}
+	if got := largestSubfolders(entries, 10); len(got) != 3 {
+		t.Fatalf("files and empty folders should be skipped, got %v", got)
+	}
+}
+
+func TestDaemonCycleCachesRootAndSubfolders(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	root := filepath.Join(home, "work")
+	writeFileWithSize(t, filepath.Join(root, "big", "data"), 64*1024)
+	writeFileWithSize(t, filepath.Join(root, "small", "data"), 1024)
+
+	var log bytes.Buffer
+	daemonCycle(&log, []string{root})
+	if !strings.Contains(log.String(), root+": ") || !strings.Contains(log.String(), "3 folders scanned") {
+		t.Fatalf("unexpected daemon log: %q", log.String())
+	}
+	for _, path := range []string{root, filepath.Join(root, "big"), filepath.Join(root, "small")} {
+		if _, err := loadCacheFromDisk(path); err != nil {
+			t.Fatalf("%s should be cached: %v", path, err)
+		}
+	}
+	if history, err := loadScanHistory(root); err != nil || len(history) != 1 {
+		t.Fatalf("the cycle should add a history snapshot: %d, %v", len(history), err)
+	}
+
+	var status bytes.Buffer
+	writeDaemonStatus(&status, []string{root}, time.Hour)
+	if !strings.Contains(status.String(), "not installed") || !strings.Contains(status.String(), "last scan 20") {
+		t.Fatalf("unexpected status: %q", status.String())
+	}
+}
+
+func TestDaemonPlistEscapesPaths(t *testing.T) {
+	plist := daemonPlist("/Apps/Tom & Jerry/analyze-go", "/logs/daemon.log")
+	if !strings.Contains(plist, "<string>/Apps/Tom &amp; Jerry/analyze-go</string>") || !strings.Contains(plist, daemonLabel) {
+		t.Fatalf("unexpected plist:\n%s", plist)
+	}
+}
+
+func TestStableBinaryPath(t *testing.T) {
+	cases := map[string]string{
+		"/opt/homebrew/Cellar/mole/1.2.3/libexec/bin/analyze-go": "/opt/homebrew/opt/mole/libexec/bin/analyze-go",
+		"/usr/local/Cellar/mole/1.2.3/bin/analyze-go":            "/usr/local/opt/mole/bin/analyze-go",
+		"/Users/me/.config/mole/bin/analyze-go":                  "/Users/me/.config/mole/bin/analyze-go",
+		"/opt/homebrew/Cellar/mole":                              "/opt/homebrew/Cellar/mole",
+	}
+	for binary, want := range cases {
+		if got := stableBinaryPath(binary); got != want {
+			t.Errorf("stableBinaryPath(%q) = %q, want %q", binary, got, want)
+		}
+	}
+}
@@ -1,119 +1,307 @@
 package main
 
 import (
-	"io/fs"
+	"errors"
 	"os"
 	"path/filepath"
+	"runtime"
 	"sort"
 	"strings"
+	"sync"
 	"sync/atomic"
+	"syscall"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
-func deletePathCmd(path string, counter *int64) tea.Cmd {
-	return func() tea.Msg {
-		count, err := deletePathWithProgress(path, counter)
-		return deleteProgressMsg{
-			done:  true,
-			err:   err,
-			count: count,
-			path:  path,
-		}
+const (
+	// deleteWorkersEnvVar sets the delete concurrency; 0 or unset picks it per volume.
+	deleteWorkersEnvVar = "MO_ANALYZE_DELETE_WORKERS"
+	// deleteProgressBatch is how many removed files are reported to the UI at once.
+	deleteProgressBatch = 64
+	// slowVolumeDeleteWorkers keeps external and network volumes from thrashing.
+	slowVolumeDeleteWorkers = 2
+)
+
+// deleteWorkers is the configured delete concurrency, 0 for automatic.
+var deleteWorkers atomic.Int64
+
+// deleteBatchProgress lets the view follow a batch delete item by item.
+type deleteBatchProgress struct {
+	Total     int
+	Permanent bool
+	index     atomic.Int64
+	current   atomic.Value // string
+}
+
+func (p *deleteBatchProgress) begin(index int, path string) {
+	if p == nil {
+		return
+	}
+	p.index.Store(int64(index))
+	p.current.Store(path)
+}
+
+// position returns the 1-based item being deleted and its path.
+func (p *deleteBatchProgress) position() (int, string) {
+	if p == nil {
+		return 0, ""
 	}
+	path, _ := p.current.Load().(string)
+	return int(p.index.Load()) + 1, path
 }
 
-// deleteMultiplePathsCmd deletes paths and aggregates results.
-func deleteMultiplePathsCmd(paths []string, counter *int64) tea.Cmd {
+// deletePathCmd removes paths as one batch and aggregates results. Unless permanent,
+// items are moved to the Trash so they can be recovered.
+func deletePathCmd(paths []string, counter *int64, progress *deleteBatchProgress, permanent bool) tea.Cmd {
 	return func() tea.Msg {
+		if err := cleanRunningError(); err != nil {
+			return deleteProgressMsg{done: true, err: err, requested: paths, trashed: !permanent}
+		}
+		if release, err := acquireLock(analyzeLockFile); err == nil {
+			defer release()
+		}
+
 		var totalCount int64
-		var errors []string
+		var failures deleteFailuresError
+		var deleted []string
 
-		// Delete deeper paths first to avoid parent/child conflicts.
+		// Delete deeper paths first to avoid parent/child conflicts; trash parents
+		// first instead so a marked child travels inside its parent.
 		pathsToDelete := append([]string(nil), paths...)
-		sort.Slice(pathsToDelete, func(i, j int) bool {
-			return strings.Count(pathsToDelete[i], string(filepath.Separator)) > strings.Count(pathsToDelete[j], string(filepath.Separator))
+		sort.SliceStable(pathsToDelete, func(i, j int) bool {
+			di := strings.Count(pathsToDelete[i], string(filepath.Separator))
+			dj := strings.Count(pathsToDelete[j], string(filepath.Separator))
+			if permanent {
+				return di > dj
+			}
+			return di < dj
 		})
 
-		for _, path := range pathsToDelete {
-			count, err := deletePathWithProgress(path, counter)
+		freeBefore := volumeFreeBytes(pathsToDelete)
+		var skipped []string
+		for i, path := range pathsToDelete {
+			if stopBatches.Load() {
+				skipped = pathsToDelete[i:]
+				break
+			}
+			progress.begin(i, path)
+			var count int64
+			var err error
+			if permanent {
+				count, err = deletePathWithProgress(path, counter)
+			} else if _, err = moveToTrash(path); err == nil {
+				count = 1
+				if counter != nil {
+					atomic.AddInt64(counter, 1)
+				}
+			}
 			totalCount += count
-			if err != nil {
-				if os.IsNotExist(err) {
-					continue
+			if err != nil && !os.IsNotExist(err) {
+				for _, failure := range deleteFailuresOf(path, err) {
+					failures.add(failure.Path, failure.Err)
 				}
-				errors = append(errors, err.Error())
+				continue
 			}
+			deleted = append(deleted, path)
 		}
 
+		recordChanges(deleted)
+
 		var resultErr error
-		if len(errors) > 0 {
-			resultErr = &multiDeleteError{errors: errors}
+		if len(failures.failures) > 0 {
+			resultErr = &failures
 		}
 
-		return deleteProgressMsg{
-			done:  true,
-			err:   resultErr,
-			count: totalCount,
-			path:  "",
+		msg := deleteProgressMsg{
+			done:      true,
+			err:       resultErr,
+			count:     totalCount,
+			requested: paths,
+			deleted:   deleted,
+			failures:  failures.failures,
+			skipped:   skipped,
+			trashed:   !permanent,
+			freed:     freedBetween(freeBefore, volumeFreeBytes(pathsToDelete)),
+		}
+		if len(paths) == 1 {
+			msg.path = paths[0]
 		}
+		return msg
 	}
 }
 
-// multiDeleteError holds multiple deletion errors.
-type multiDeleteError struct {
-	errors []string
-}
+// deletePathWithProgress removes root, adding each removed file to counter. Subdirectories
+// are removed in parallel, bounded by deleteWorkersFor.
+func deletePathWithProgress(root string, counter *int64) (int64, error) {
+	info, err := os.Lstat(root)
+	if err != nil {
+		return 0, err
+	}
+	if !info.IsDir() {
+		if err := removeKeepingParentMode(root); err != nil {
+			return 0, err
+		}
+		if counter != nil {
+			atomic.AddInt64(counter, 1)
+		}
+		return 1, nil
+	}
 
-func (e *multiDeleteError) Error() string {
-	if len(e.errors) == 1 {
-		return e.errors[0]
+	d := &treeDeleter{counter: counter, sem: make(chan struct{}, deleteWorkersFor(root))}
+	d.removeTree(root)
+	d.wg.Wait()
+
+	if len(d.failures.failures) == 0 {
+		if removeErr := os.RemoveAll(root); removeErr != nil {
+			d.fail(root, removeErr)
+		}
 	}
-	return strings.Join(e.errors[:min(3, len(e.errors))], "; ")
+	if len(d.failures.failures) > 0 {
+		return d.count.Load(), &d.failures
+	}
+	return d.count.Load(), nil
 }
 
-func deletePathWithProgress(root string, counter *int64) (int64, error) {
-	var count int64
-	var firstErr error
-
-	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			// Skip permission errors but continue.
-			if os.IsPermission(err) {
-				if firstErr == nil {
-					firstErr = err
-				}
-				return filepath.SkipDir
-			}
-			if firstErr == nil {
-				firstErr = err
-			}
-			return nil
-		}
+// treeDeleter removes a directory tree with a bounded number of goroutines.
+type treeDeleter struct {
+	counter  *int64
+	count    atomic.Int64
+	sem      chan struct{}
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+	failures deleteFailuresError // Paths skipped so the rest of the tree still goes
+}
 
-		if !d.IsDir() {
-			if removeErr := os.Remove(path); removeErr == nil {
-				count++
-				if counter != nil {
-					atomic.StoreInt64(counter, count)
-				}
-			} else if firstErr == nil {
-				firstErr = removeErr
+func (d *treeDeleter) fail(path string, err error) {
+	d.mu.Lock()
+	d.failures.add(path, err)
+	d.mu.Unlock()
+}
+
+// removeTree deletes dir's files, hands subdirectories to idle workers (or recurses
+// inline when all are busy) and reports progress in batches.
+func (d *treeDeleter) removeTree(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil && os.IsPermission(err) && makeWritable(dir) == nil {
+		entries, err = os.ReadDir(dir)
+	}
+	if err != nil {
+		// Skip unreadable folders but keep going.
+		d.fail(dir, err)
+		return
+	}
+
+	var pending int64
+	flush := func() {
+		if pending == 0 {
+			return
+		}
+		d.count.Add(pending)
+		if d.counter != nil {
+			atomic.AddInt64(d.counter, pending)
+		}
+		pending = 0
+	}
+	var children sync.WaitGroup
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if entry.IsDir() {
+			select {
+			case d.sem <- struct{}{}:
+				children.Add(1)
+				d.wg.Add(1)
+				go func() {
+					defer d.wg.Done()
+					defer children.Done()
+					defer func() { <-d.sem }()
+					d.removeTree(path)
+					d.removeDir(path)
+				}()
+			default:
+				d.removeTree(path)
+				d.removeDir(path)
 			}
+			continue
+		}
+		if err := removeWithRetry(path); err != nil {
+			d.fail(path, err)
+			continue
 		}
+		pending++
+		if pending >= deleteProgressBatch {
+			flush()
+		}
+	}
+	flush()
+	children.Wait()
+}
+
+func (d *treeDeleter) removeDir(dir string) {
+	if err := removeWithRetry(dir); err != nil && !os.IsNotExist(err) && !isNotEmpty(err) {
+		d.fail(dir, err)
+	}
+}
 
-		return nil
-	})
+// isNotEmpty reports a folder left behind because something inside it could not go.
+func isNotEmpty(err error) bool {
+	return errors.Is(err, syscall.ENOTEMPTY) || errors.Is(err, syscall.EEXIST)
+}
 
-	if err != nil && firstErr == nil {
-		firstErr = err
+// removeWithRetry removes path, making it and its folder writable once if that was the
+// problem. The folder is left writable, so path must be inside the tree being deleted.
+func removeWithRetry(path string) error {
+	err := os.Remove(path)
+	if err == nil || os.IsNotExist(err) || !os.IsPermission(err) {
+		return err
+	}
+	if makeWritable(filepath.Dir(path)) != nil {
+		return err
 	}
+	_ = makeWritable(path)
+	return os.Remove(path)
+}
 
-	if removeErr := os.RemoveAll(root); removeErr != nil {
-		if firstErr == nil {
-			firstErr = removeErr
+// removeKeepingParentMode is removeWithRetry for a path whose folder stays: the folder
+// gets its mode back if the retry changed it.
+func removeKeepingParentMode(path string) error {
+	parent := filepath.Dir(path)
+	info, err := os.Lstat(parent)
+	if err != nil {
+		return removeWithRetry(path)
+	}
+	defer func() {
+		if now, err := os.Lstat(parent); err == nil && now.Mode().Perm() != info.Mode().Perm() {
+			_ = os.Chmod(parent, info.Mode().Perm())
 		}
+	}()
+	return removeWithRetry(path)
+}
+
+// makeWritable adds owner write (and search, for folders) permission.
+func makeWritable(path string) error {
+	info, err := os.Lstat(path)
+	if err != nil || info.Mode()&os.ModeSymlink != 0 {
+		return err
+	}
+	mode := info.Mode().Perm() | 0o200
+	if info.IsDir() {
+		mode |= 0o700
+	}
+	if mode == info.Mode().Perm() {
+		return os.ErrPermission
 	}
+	return os.Chmod(path, mode)
+}
 
-	return count, firstErr
+// deleteWorkersFor picks the delete concurrency for root: the configured value, else a
+// CPU-based default that drops to a couple of workers on slow or network volumes.
+func deleteWorkersFor(root string) int {
+	if workers := int(deleteWorkers.Load()); workers > 0 {
+		return workers
+	}
+	if profile, ok := loadDeviceProfile(root); ok && profile.Scans > 0 && profile.DirsPerSec < slowVolumeDirsPerSec {
+		return slowVolumeDeleteWorkers
+	}
+	return min(runtime.NumCPU()*2, maxDirWorkers)
 }
@@ -1,12 +1,14 @@
 package main
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
 )
 
-func TestDeleteMultiplePathsCmdHandlesParentChild(t *testing.T) {
+func TestDeletePathCmdHandlesParentChild(t *testing.T) {
+	t.Setenv("HOME", t.TempDir()) // Keep changes.log and the delete lock out of the real config
 	base := t.TempDir()
 	parent := filepath.Join(base, "parent")
 	child := filepath.Join(parent, "child")
@@ -23,7 +25,7 @@ func TestDeleteMultiplePathsCmdHandlesParentChild(t *testing.T) {
 	}
 
 	var counter int64
-	msg := deleteMultiplePathsCmd([]string{parent, child}, &counter)()
+	msg := deletePathCmd([]string{parent, child}, &counter, nil, true)()
 	progress, ok := msg.(deleteProgressMsg)
 	if !ok {
 		t.Fatalf("expected deleteProgressMsg, got %T", msg)
@@ -34,6 +36,12 @@ func TestDeleteMultiplePathsCmdHandlesParentChild(t *testing.T) {
 	if progress.count != 2 {
 		t.Fatalf("expected 2 files deleted, got %d", progress.count)
 	}
+	if counter != 2 {
+		t.Fatalf("expected counter to accumulate across items, got %d", counter)
+	}
+	if len(progress.deleted) != 2 {
+		t.Fatalf("expected both paths reported deleted, got %v", progress.deleted)
+	}
 	if _, err := os.Stat(parent); !os.IsNotExist(err) {
 		t.Fatalf("expected parent to be removed, err=%v", err)
 	}
@@ -41,3 +49,96 @@ func TestDeleteMultiplePathsCmdHandlesParentChild(t *testing.T) {
 		t.Fatalf("expected child to be removed, err=%v", err)
 	}
 }
+
+func TestDeletePathCmdMovesToTrashByDefault(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	parent := filepath.Join(home, "parent")
+	child := filepath.Join(parent, "child")
+	if err := os.MkdirAll(child, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	var counter int64
+	msg := deletePathCmd([]string{child, parent}, &counter, nil, false)().(deleteProgressMsg)
+	if msg.err != nil || !msg.trashed {
+		t.Fatalf("unexpected result: %+v", msg)
+	}
+	if _, err := os.Stat(filepath.Join(home, ".Trash", "parent", "child")); err != nil {
+		t.Fatalf("marked child should travel inside its trashed parent: %v", err)
+	}
+	if len(msg.deleted) != 2 {
+		t.Fatalf("both paths should be reported gone, got %v", msg.deleted)
+	}
+}
+
+func TestDeletePathWithProgressParallelAndReadOnly(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "tree")
+	want := 0
+	for i := 0; i < 6; i++ {
+		dir := filepath.Join(root, fmt.Sprintf("d%d", i), "nested")
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		for j := 0; j < 100; j++ {
+			if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("f%d", j)), []byte("x"), 0o644); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+			want++
+		}
+	}
+	locked := filepath.Join(root, "locked")
+	if err := os.MkdirAll(locked, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(locked, "file"), []byte("x"), 0o444); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	want++
+	if err := os.Chmod(locked, 0o555); err != nil {
+		t.Fatalf("chmod: %v", err)
+	}
+
+	deleteWorkers.Store(3)
+	defer deleteWorkers.Store(0)
+	var counter int64
+	count, err := deletePathWithProgress(root, &counter)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count != int64(want) || counter != count {
+		t.Fatalf("count=%d counter=%d; want %d", count, counter, want)
+	}
+	if _, err := os.Stat(root); !os.IsNotExist(err) {
+		t.Fatalf("expected tree to be removed, err=%v", err)
+	}
+}
+
+func TestDeletePathWithProgressKeepsParentMode(t *testing.T) {
+	parent := filepath.Join(t.TempDir(), "parent")
+	if err := os.Mkdir(parent, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	file := filepath.Join(parent, "file")
+	if err := os.WriteFile(file, []byte("x"), 0o444); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := os.Chmod(parent, 0o555); err != nil {
+		t.Fatalf("chmod: %v", err)
+	}
+	defer os.Chmod(parent, 0o755)
+
+	if _, err := deletePathWithProgress(file, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Lstat(file); !os.IsNotExist(err) {
+		t.Fatalf("expected file to be removed, err=%v", err)
+	}
+	info, err := os.Stat(parent)
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	if info.Mode().Perm() != 0o555 {
+		t.Fatalf("parent mode = %o, want 555", info.Mode().Perm())
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// BSD file flags from <sys/stat.h>.
+const (
+	flagUserImmutable   = 0x00000002 // uchg
+	flagUserAppend      = 0x00000004 // uappnd
+	flagSystemImmutable = 0x00020000 // schg
+	flagSystemAppend    = 0x00040000 // sappnd
+	flagRestricted      = 0x00080000 // restricted, protected by SIP
+)
+
+// maxDeleteFailures caps how many failed paths one delete keeps for the report.
+const maxDeleteFailures = 200
+
+// sipProtectedRoots are trees System Integrity Protection keeps read-only.
+var sipProtectedRoots = []string{"/System", "/bin", "/sbin", "/usr", "/Library/Apple"}
+
+// deleteFailure is one path a delete could not remove.
+type deleteFailure struct {
+	Path string
+	Err  error
+}
+
+// deleteFailuresError collects the paths a delete skipped while it carried on.
+type deleteFailuresError struct {
+	failures []deleteFailure
+	dropped  int // Failures past maxDeleteFailures
+}
+
+func (e *deleteFailuresError) add(path string, err error) {
+	if len(e.failures) >= maxDeleteFailures {
+		e.dropped++
+		return
+	}
+	e.failures = append(e.failures, deleteFailure{Path: path, Err: err})
+}
+
+func (e *deleteFailuresError) Error() string {
+	messages := make([]string, 0, 3)
+	for _, failure := range e.failures[:min(3, len(e.failures))] {
+		messages = append(messages, failure.Err.Error())
+	}
+	if more := len(e.failures) + e.dropped - len(messages); more > 0 {
+		messages = append(messages, fmt.Sprintf("%d more", more))
+	}
+	return strings.Join(messages, "; ")
+}
+
+// deleteFailuresOf flattens err into per-path failures, attributing bare errors to path.
+func deleteFailuresOf(path string, err error) []deleteFailure {
+	var collected *deleteFailuresError
+	if errors.As(err, &collected) {
+		return collected.failures
+	}
+	var pathErr *os.PathError
+	if errors.As(err, &pathErr) {
+		return []deleteFailure{{Path: pathErr.Path, Err: err}}
+	}
+	return []deleteFailure{{Path: path, Err: err}}
+}
+
+// isSIPProtected reports whether path lies in a tree System Integrity Protection guards.
+func isSIPProtected(path string) bool {
+	if strings.HasPrefix(path, "/usr/local") {
+		return false
+	}
+	for _, root := range sipProtectedRoots {
+		if path == root || strings.HasPrefix(path, root+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// explainDeleteFailure turns a failed removal into a reason and a suggested fix.
+func explainDeleteFailure(failure deleteFailure) (reason, fix string) {
+	path := failure.Path
+	flags, _ := fileFlags(path)
+	parentFlags, _ := fileFlags(filepath.Dir(path))
+	switch {
+	case flags&flagRestricted != 0 || isSIPProtected(path):
+		return "Protected by System Integrity Protection", "macOS keeps this read-only; leave it in place"
+	case (flags|parentFlags)&(flagSystemImmutable|flagSystemAppend) != 0:
+		return "System immutable flag (schg) is set", "Clear it from Recovery: chflags -R noschg " + shellQuote(path)
+	case (flags|parentFlags)&(flagUserImmutable|flagUserAppend) != 0:
+		return "Locked in Finder (uchg flag)", "chflags -R nouchg " + shellQuote(path)
+	case errors.Is(failure.Err, syscall.EBUSY):
+		return "In use by another process", "Quit the app using it and try again"
+	case errors.Is(failure.Err, syscall.EROFS):
+		return "On a read-only volume", "Remount the volume read-write first"
+	}
+	if errors.Is(failure.Err, os.ErrPermission) {
+		if ownedByOther(path) {
+			return "Owned by another user or root", "sudo rm -rf " + shellQuote(path)
+		}
+		return "Permission denied", "Give your terminal Full Disk Access in System Settings > Privacy & Security"
+	}
+	return failure.Err.Error(), ""
+}
+
+// ownedByOther reports whether path or its folder belongs to someone other than the current user.
+func ownedByOther(path string) bool {
+	uid := uint32(os.Getuid())
+	for _, p := range []string{path, filepath.Dir(path)} {
+		var st syscall.Stat_t
+		if syscall.Lstat(p, &st) == nil && st.Uid != uid {
+			return true
+		}
+	}
+	return false
+}
+
+// renderDeleteFailures lists the paths a delete (verb "remove") or move left behind,
+// with why and what to try.
+func renderDeleteFailures(failures []deleteFailure, verb string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s! Could not %s %d items:%s  %sEsc dismiss%s\n", colorYellow, verb, len(failures), colorReset, colorGray, colorReset)
+	const shown = 5
+	for _, failure := range failures[:min(shown, len(failures))] {
+		reason, fix := explainDeleteFailure(failure)
+		fmt.Fprintf(&b, "  %s  %s%s%s\n", displayPath(failure.Path), colorGray, reason, colorReset)
+		if fix != "" {
+			fmt.Fprintf(&b, "    %s→ %s%s\n", colorGray, fix, colorReset)
+		}
+	}
+	if len(failures) > shown {
+		fmt.Fprintf(&b, "  %s… and %d more%s\n", colorGray, len(failures)-shown, colorReset)
+	}
+	return b.String()
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func TestDeleteFailuresOf(t *testing.T) {
+	var collected deleteFailuresError
+	collected.add("/a/one", errors.New("first"))
+	collected.add("/a/two", errors.New("second"))
+	if got := deleteFailuresOf("/a", fmt.Errorf("wrapped: %w", &collected)); len(got) != 2 || got[1].Path != "/a/two" {
+		t.Fatalf("collected failures not flattened: %+v", got)
+	}
+
+	pathErr := &os.PathError{Op: "remove", Path: "/a/locked", Err: syscall.EPERM}
+	if got := deleteFailuresOf("/a", pathErr); len(got) != 1 || got[0].Path != "/a/locked" {
+		t.Fatalf("path error should name its own path: %+v", got)
+	}
+	if got := deleteFailuresOf("/a", errors.New("boom")); len(got) != 1 || got[0].Path != "/a" {
+		t.Fatalf("bare error should fall back to the requested path: %+v", got)
+	}
+
+	for i := 0; i < maxDeleteFailures+5; i++ {
+		collected.add("/many", errors.New("x"))
+	}
+	if len(collected.failures) != maxDeleteFailures || !strings.Contains(collected.Error(), "more") {
+		t.Fatalf("failures should be capped: %d %q", len(collected.failures), collected.Error())
+	}
+}
+
+func TestExplainDeleteFailure(t *testing.T) {
+	reason, _ := explainDeleteFailure(deleteFailure{Path: "/System/Library/Fonts", Err: syscall.EPERM})
+	if !strings.Contains(reason, "System Integrity Protection") {
+		t.Fatalf("unexpected reason for SIP path: %q", reason)
+	}
+	if isSIPProtected("/usr/local/lib") || !isSIPProtected("/usr/lib") {
+		t.Fatal("/usr/local is writable, the rest of /usr is not")
+	}
+
+	dir := t.TempDir()
+	reason, fix := explainDeleteFailure(deleteFailure{Path: dir + "/busy", Err: &os.PathError{Op: "remove", Path: dir + "/busy", Err: syscall.EBUSY}})
+	if !strings.Contains(reason, "In use") || fix == "" {
+		t.Fatalf("unexpected busy explanation: %q %q", reason, fix)
+	}
+}
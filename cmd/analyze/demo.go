@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"path/filepath"
+	"sort"
+)
+
+const (
+	demoEnvVar = "MO_ANALYZE_DEMO"
+	demoRoot   = "/Users/demo"
+	demoSeed   = 20240601
+)
+
+// demoFolder describes one synthetic subtree: fixed large files plus seeded filler.
+type demoFolder struct {
+	Path   string
+	Files  map[string]int64
+	Filler int   // Number of random small files
+	MaxKB  int64 // Upper bound for filler file size
+}
+
+var demoLayout = []demoFolder{
+	{Path: "Movies/Final Cut Projects/Trip 2023", Files: map[string]int64{"Render Files.fcpbundle": 18 << 30, "Original Media.mov": 7 << 30}},
+	{Path: "Movies", Files: map[string]int64{"Screen Recording.mov": 2 << 30, "Keynote Talk.mp4": 900 << 20}},
+	{Path: "Library/Caches/com.apple.Safari", Filler: 400, MaxKB: 2048},
+	{Path: "Library/Caches/com.spotify.client/Data", Filler: 120, MaxKB: 40 * 1024},
+	{Path: "Library/Developer/Xcode/DerivedData/Demo-abcdef", Filler: 800, MaxKB: 8192},
+	{Path: "Library/Developer/CoreSimulator/Devices/1A2B3C", Files: map[string]int64{"data.img": 6 << 30}},
+	{Path: "Library/Application Support/Slack", Filler: 200, MaxKB: 4096},
+	{Path: "Library/Containers/com.docker.docker/Data/vms/0", Files: map[string]int64{"Docker.raw": 24 << 30}},
+	{Path: "Downloads", Files: map[string]int64{"macOS Installer.dmg": 13 << 30, "Dataset.zip": 3 << 30}, Filler: 60, MaxKB: 50 * 1024},
+	{Path: "Documents/Invoices", Filler: 150, MaxKB: 512},
+	{Path: "Documents/Photos Export", Filler: 300, MaxKB: 12 * 1024},
+	{Path: "Projects/webapp/node_modules", Filler: 2500, MaxKB: 256},
+	{Path: "Projects/webapp/src", Filler: 180, MaxKB: 64},
+	{Path: "Projects/ml-notebook/.venv/lib", Filler: 900, MaxKB: 2048},
+	{Path: "Projects/ml-notebook/data", Files: map[string]int64{"train.parquet": 4 << 30}},
+	{Path: "Pictures/Photos Library.photoslibrary", Files: map[string]int64{"database.sqlite": 1 << 30}, Filler: 600, MaxKB: 10 * 1024},
+	{Path: "Music", Filler: 250, MaxKB: 9 * 1024},
+	{Path: ".Trash", Files: map[string]int64{"Old Backup.sparsebundle": 5 << 30}},
+}
+
+// demoInventory builds the same synthetic home folder every run, for screenshots and repro.
+func demoInventory() *inventoryTree {
+	rng := rand.New(rand.NewSource(demoSeed))
+	tree := newInventoryTree("demo", demoRoot)
+	for _, folder := range demoLayout {
+		dir := filepath.Join(demoRoot, folder.Path)
+		tree.add(dir, 0, true)
+		names := make([]string, 0, len(folder.Files))
+		for name := range folder.Files {
+			names = append(names, name)
+		}
+		// Map order is random; sort so insertion is deterministic too.
+		sort.Strings(names)
+		for _, name := range names {
+			tree.add(filepath.Join(dir, name), folder.Files[name], false)
+		}
+		for i := 0; i < folder.Filler; i++ {
+			size := (rng.Int63n(max(folder.MaxKB, 1)) + 1) << 10
+			tree.add(filepath.Join(dir, fmt.Sprintf("item-%04d.dat", i)), size, false)
+		}
+	}
+	tree.finalize()
+	return tree
+}
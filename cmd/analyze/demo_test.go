@@ -0,0 +1,42 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestDemoInventoryIsDeterministic(t *testing.T) {
+	first, err := demoInventory().scanResultFor(demoRoot)
+	if err != nil {
+		t.Fatalf("scanResultFor: %v", err)
+	}
+	second, err := demoInventory().scanResultFor(demoRoot)
+	if err != nil {
+		t.Fatalf("scanResultFor: %v", err)
+	}
+	if first.TotalSize != second.TotalSize || len(first.Entries) != len(second.Entries) {
+		t.Fatalf("demo data differs between runs: %d vs %d", first.TotalSize, second.TotalSize)
+	}
+	for i := range first.Entries {
+		if first.Entries[i] != second.Entries[i] {
+			t.Fatalf("entry %d differs: %+v vs %+v", i, first.Entries[i], second.Entries[i])
+		}
+	}
+	if first.Entries[0].Name != "Library" {
+		t.Fatalf("expected Library to lead the demo home, got %s", first.Entries[0].Name)
+	}
+	if len(first.LargeFiles) == 0 {
+		t.Fatalf("expected demo data to include large files")
+	}
+}
+
+func TestDemoInventoryBrowsesSubfolders(t *testing.T) {
+	tree := demoInventory()
+	result, err := tree.scanResultFor(filepath.Join(demoRoot, "Projects", "webapp"))
+	if err != nil {
+		t.Fatalf("scanResultFor: %v", err)
+	}
+	if len(result.Entries) != 2 || result.Entries[0].Name != "node_modules" {
+		t.Fatalf("unexpected webapp entries %+v", result.Entries)
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+// deniedDirs holds what scans could not read, keyed by path. Their contents are
+// missing from every total above them, so the "!" view lists them.
+var (
+	deniedMu   sync.Mutex
+	deniedDirs = make(map[string]error)
+)
+
+// deniedPath is one unreadable path and the error the scan got for it.
+type deniedPath struct {
+	Path string
+	Err  error
+}
+
+// recordDenied notes path when err is EACCES or EPERM and reports whether it did.
+func recordDenied(path string, err error) bool {
+	if !errors.Is(err, os.ErrPermission) {
+		return false
+	}
+	deniedMu.Lock()
+	deniedDirs[path] = err
+	deniedMu.Unlock()
+	return true
+}
+
+// recordDuDenied records the paths du reported as unreadable on stderr and returns
+// how many there were. du still prints the size of everything it could read.
+func recordDuDenied(stderr string) int {
+	count := 0
+	for _, line := range strings.Split(stderr, "\n") {
+		rest, ok := strings.CutPrefix(line, "du: ")
+		if !ok {
+			continue
+		}
+		for _, reason := range []struct {
+			suffix string
+			errno  syscall.Errno
+		}{{": Permission denied", syscall.EACCES}, {": Operation not permitted", syscall.EPERM}} {
+			if path, found := strings.CutSuffix(rest, reason.suffix); found && filepath.IsAbs(path) {
+				recordDenied(path, &os.PathError{Op: "open", Path: path, Err: reason.errno})
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// clearDeniedUnder forgets what earlier scans of path could not read.
+func clearDeniedUnder(path string) {
+	deniedMu.Lock()
+	defer deniedMu.Unlock()
+	prefix := path + string(os.PathSeparator)
+	for dir := range deniedDirs {
+		if dir == path || strings.HasPrefix(dir, prefix) {
+			delete(deniedDirs, dir)
+		}
+	}
+}
+
+// deniedUnder lists the unreadable paths at or below path, sorted.
+func deniedUnder(path string) []deniedPath {
+	deniedMu.Lock()
+	defer deniedMu.Unlock()
+	prefix := strings.TrimSuffix(path, string(os.PathSeparator)) + string(os.PathSeparator)
+	var denied []deniedPath
+	for dir, err := range deniedDirs {
+		if dir == path || strings.HasPrefix(dir, prefix) {
+			denied = append(denied, deniedPath{Path: dir, Err: err})
+		}
+	}
+	sort.Slice(denied, func(i, j int) bool { return denied[i].Path < denied[j].Path })
+	return denied
+}
+
+// deniedImpact says why path was likely unreadable and what leaving it out means.
+func deniedImpact(path string) string {
+	home := os.Getenv("HOME")
+	switch {
+	case isSIPProtected(path):
+		return "Protected by System Integrity Protection; macOS manages it, nothing to reclaim"
+	case ownedByOther(path):
+		return "Owned by another user or root; run with sudo to count it"
+	case home != "" && strings.HasPrefix(path, filepath.Join(home, "Library")+string(os.PathSeparator)):
+		return "Privacy-protected app data; give your terminal Full Disk Access to count it"
+	default:
+		return "Not readable; its contents are missing from the sizes above it"
+	}
+}
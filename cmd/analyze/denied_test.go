@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRecordDenied(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "scan")
+	defer clearDeniedUnder(root)
+
+	if recordDenied(root+"/missing", os.ErrNotExist) {
+		t.Error("only permission errors should be recorded")
+	}
+	recordDenied(root+"/b", &os.PathError{Op: "open", Path: root + "/b", Err: os.ErrPermission})
+	stderr := "du: " + root + "/a: Permission denied\n" +
+		"du: " + root + "/c: Operation not permitted\n" +
+		"du: something else went wrong\n"
+	if got := recordDuDenied(stderr); got != 2 {
+		t.Fatalf("recordDuDenied = %d, want 2", got)
+	}
+
+	denied := deniedUnder(root)
+	if len(denied) != 3 || denied[0].Path != root+"/a" || denied[2].Path != root+"/c" {
+		t.Fatalf("deniedUnder = %+v", denied)
+	}
+	if !errors.Is(denied[0].Err, os.ErrPermission) {
+		t.Errorf("du denial should read as a permission error: %v", denied[0].Err)
+	}
+	if len(deniedUnder(root+"/a")) != 1 {
+		t.Error("deniedUnder should scope to the folder")
+	}
+
+	clearDeniedUnder(root)
+	if len(deniedUnder(root)) != 0 {
+		t.Error("clearDeniedUnder should forget earlier denials")
+	}
+}
+
+func TestScanRecordsUnreadableFolders(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("root can read every folder")
+	}
+	root := t.TempDir()
+	locked := filepath.Join(root, "app", "locked")
+	if err := os.MkdirAll(locked, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(locked, "data"), make([]byte, 4096), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(locked, 0); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chmod(locked, 0o755)
+	defer clearDeniedUnder(root)
+
+	var files, dirs, bytes int64
+	current := ""
+	if _, err := scanPathConcurrent(context.Background(), root, &files, &dirs, &bytes, &current, nil); err != nil {
+		t.Fatal(err)
+	}
+	denied := deniedUnder(root)
+	if len(denied) != 1 || denied[0].Path != locked {
+		t.Fatalf("deniedUnder = %+v", denied)
+	}
+}
@@ -0,0 +1,118 @@
+//go:build darwin
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// deniedView is the "!" screen listing what scans under Root could not read.
+type deniedView struct {
+	Root     string
+	Selected int
+}
+
+// deniedRoot is the folder whose unreadable paths apply to what is on screen.
+func (m model) deniedRoot() string {
+	if m.inOverviewMode() {
+		return "/"
+	}
+	return m.path
+}
+
+func (m model) openDenied() (tea.Model, tea.Cmd) {
+	if m.inventory != nil {
+		m.status = fmt.Sprintf("%s is a read-only listing", m.inventory.Source)
+		return m, nil
+	}
+	m.denied = &deniedView{Root: m.deniedRoot()}
+	return m, nil
+}
+
+// updateDeniedKey handles keys while the unreadable paths are listed.
+func (m model) updateDeniedKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	v := m.denied
+	items := deniedUnder(v.Root)
+	switch msg.String() {
+	case "q", "Q", "ctrl+c":
+		return m.quit()
+	case "esc", "b", "left", "h", "!":
+		m.denied = nil
+	case "up", "k":
+		v.Selected = max(v.Selected-1, 0)
+	case "down", "j":
+		v.Selected = min(v.Selected+1, max(len(items)-1, 0))
+	case "f", "F":
+		if v.Selected >= len(items) {
+			return m, nil
+		}
+		path := items[v.Selected].Path
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_ = exec.CommandContext(ctx, "open", "-R", path).Run()
+		}()
+		m.status = fmt.Sprintf("Showing %s in Finder", displayPath(path))
+	}
+	return m, nil
+}
+
+// renderDenied lists each unreadable path with the error and what missing it means.
+func (m model) renderDenied() string {
+	v := m.denied
+	items := deniedUnder(v.Root)
+	var b strings.Builder
+	if len(items) == 0 {
+		fmt.Fprintf(&b, "  %sEverything under %s was readable.%s\n\n", colorGray, displayPath(v.Root), colorReset)
+		fmt.Fprintf(&b, "%s!/← Back | Q Quit%s\n", colorGray, colorReset)
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "%sUnreadable:%s %d paths under %s, their sizes are not counted\n\n",
+		colorCyan, colorReset, len(items), displayPath(v.Root))
+	viewport := max(calculateViewport(m.height, true)/2, 1)
+	nameWidth := calculateNameWidth(m.width) + 20
+	start := max(0, v.Selected-viewport+1)
+	for i := start; i < min(len(items), start+viewport); i++ {
+		item := items[i]
+		prefix, color := "   ", ""
+		if i == v.Selected {
+			prefix, color = fmt.Sprintf(" %s%s▶%s ", colorCyan, colorBold, colorReset), colorCyan
+		}
+		fmt.Fprintf(&b, "%s%s%s%s  %s%s%s\n", prefix, color, truncateMiddle(displayPath(item.Path), nameWidth), colorReset,
+			colorGray, deniedReason(item.Err), colorReset)
+		fmt.Fprintf(&b, "     %s%s%s\n", colorYellow, deniedImpact(item.Path), colorReset)
+	}
+
+	fmt.Fprintln(&b)
+	fmt.Fprintf(&b, "%s↑↓ | F Show in Finder | !/← Back | Q Quit%s\n", colorGray, colorReset)
+	return b.String()
+}
+
+// deniedReason names the errno behind a permission error.
+func deniedReason(err error) string {
+	if errors.Is(err, syscall.EPERM) {
+		return "operation not permitted (EPERM)"
+	}
+	return "permission denied (EACCES)"
+}
+
+// deniedTag is the footer hint shown while paths under the open folder were unreadable.
+func (m model) deniedTag() string {
+	if m.inventory != nil {
+		return ""
+	}
+	count := len(deniedUnder(m.deniedRoot()))
+	if count == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s⚠ %d paths could not be read and are not counted, ! to list them%s\n", colorYellow, count, colorReset)
+}
@@ -0,0 +1,261 @@
+//go:build darwin
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	deviceProfileFile = "device_profiles.json"
+	// Volumes below this rate are treated as slow (network homes, spinning USB disks).
+	slowVolumeDirsPerSec = 300
+	// du is skipped on a folder where it kept timing out, until a week has passed since
+	// the last timeout.
+	maxDuFailures = 2
+	duFailureTTL  = 7 * 24 * time.Hour
+)
+
+// deviceProfile is the observed scan performance of one volume.
+type deviceProfile struct {
+	DirsPerSec  float64   `json:"dirs_per_sec"`
+	BytesPerSec float64   `json:"bytes_per_sec"`
+	BestWorkers int       `json:"best_workers"`
+	BestRate    float64   `json:"best_rate"`
+	Scans       int       `json:"scans"`
+	Updated     time.Time `json:"updated"`
+	// DuFailures holds du timeouts by the folder du was sizing.
+	DuFailures map[string]duFailure `json:"du_timeouts,omitempty"`
+}
+
+// duFailure counts du timeouts on one folder in a row.
+type duFailure struct {
+	Count int       `json:"count"`
+	Last  time.Time `json:"last"`
+}
+
+var (
+	deviceProfileMu     sync.Mutex
+	deviceProfiles      map[string]deviceProfile
+	deviceProfileLoaded bool
+	volumeIDCache       = make(map[string]string)
+	volumeUUIDPattern   = regexp.MustCompile(`<key>VolumeUUID</key>\s*<string>([^<]+)</string>`)
+)
+
+// mountPointOf walks up until the device ID changes, which marks the mount boundary.
+func mountPointOf(path string) (string, error) {
+	current := filepath.Clean(path)
+	dev, err := deviceOf(current)
+	if err != nil {
+		return "", err
+	}
+	for current != "/" {
+		parent := filepath.Dir(current)
+		parentDev, err := deviceOf(parent)
+		if err != nil || parentDev != dev {
+			return current, nil
+		}
+		current = parent
+	}
+	return "/", nil
+}
+
+func deviceOf(path string) (uint64, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, fmt.Errorf("no device info for %s", path)
+	}
+	return uint64(stat.Dev), nil
+}
+
+// volumeIDFor resolves the volume UUID for path, falling back to the mount point.
+func volumeIDFor(path string) string {
+	mount, err := mountPointOf(path)
+	if err != nil || mount == "" {
+		return ""
+	}
+
+	deviceProfileMu.Lock()
+	id, ok := volumeIDCache[mount]
+	deviceProfileMu.Unlock()
+	if ok {
+		return id
+	}
+
+	id = "mount:" + mount
+	ctx, cancel := context.WithTimeout(context.Background(), mdlsTimeout)
+	defer cancel()
+	if out, err := exec.CommandContext(ctx, "diskutil", "info", "-plist", mount).Output(); err == nil {
+		if match := volumeUUIDPattern.FindSubmatch(out); match != nil {
+			id = string(match[1])
+		}
+	}
+
+	deviceProfileMu.Lock()
+	volumeIDCache[mount] = id
+	deviceProfileMu.Unlock()
+	return id
+}
+
+func ensureDeviceProfilesLoadedLocked() {
+	if deviceProfileLoaded {
+		return
+	}
+	deviceProfileLoaded = true
+	deviceProfiles = make(map[string]deviceProfile)
+	cacheDir, err := getCacheDir()
+	if err != nil {
+		return
+	}
+	data, err := readCacheFile(filepath.Join(cacheDir, deviceProfileFile))
+	if err != nil {
+		return
+	}
+	var loaded map[string]deviceProfile
+	if json.Unmarshal(data, &loaded) == nil && loaded != nil {
+		deviceProfiles = loaded
+	}
+}
+
+func persistDeviceProfilesLocked() error {
+	cacheDir, err := getCacheDir()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(deviceProfiles, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeCacheFile(filepath.Join(cacheDir, deviceProfileFile), data)
+}
+
+func loadDeviceProfile(path string) (deviceProfile, bool) {
+	id := volumeIDFor(path)
+	if id == "" {
+		return deviceProfile{}, false
+	}
+	deviceProfileMu.Lock()
+	defer deviceProfileMu.Unlock()
+	ensureDeviceProfilesLoadedLocked()
+	profile, ok := deviceProfiles[id]
+	return profile, ok
+}
+
+func updateDeviceProfile(path string, update func(*deviceProfile)) {
+	id := volumeIDFor(path)
+	if id == "" {
+		return
+	}
+	deviceProfileMu.Lock()
+	defer deviceProfileMu.Unlock()
+	ensureDeviceProfilesLoadedLocked()
+	profile := deviceProfiles[id]
+	update(&profile)
+	profile.Updated = time.Now()
+	deviceProfiles[id] = profile
+	_ = persistDeviceProfilesLocked()
+}
+
+// applyDeviceProfile seeds the worker cap from the volume's best observed setting.
+func applyDeviceProfile(path string) {
+	profile, ok := loadDeviceProfile(path)
+	if !ok || profile.Scans == 0 {
+		return
+	}
+	workers := profile.BestWorkers
+	if workers <= 0 {
+		workers = defaultPoolLimit()
+		if profile.DirsPerSec < slowVolumeDirsPerSec {
+			workers = minWorkers / 2
+		}
+	}
+	scanPool.setLimit(workers)
+}
+
+// recordScanThroughput blends the latest scan into the profile (EWMA) and tracks the best cap.
+func recordScanThroughput(path string, elapsed time.Duration, dirs, bytes int64, workers int) {
+	seconds := elapsed.Seconds()
+	if seconds < 1 || dirs <= 0 {
+		return // Too short to be meaningful (mostly cache hits).
+	}
+	dirRate := float64(dirs) / seconds
+	byteRate := float64(bytes) / seconds
+	updateDeviceProfile(path, func(p *deviceProfile) {
+		p.DirsPerSec = blendRate(p.DirsPerSec, dirRate, p.Scans)
+		p.BytesPerSec = blendRate(p.BytesPerSec, byteRate, p.Scans)
+		if dirRate > p.BestRate {
+			p.BestRate = dirRate
+			p.BestWorkers = workers
+		}
+		p.Scans++
+	})
+}
+
+func blendRate(previous, current float64, samples int) float64 {
+	if samples == 0 {
+		return current
+	}
+	const alpha = 0.3
+	return previous*(1-alpha) + current*alpha
+}
+
+// duTimeoutFor gives slow volumes more time before falling back to a walk.
+func duTimeoutFor(path string) time.Duration {
+	if profile, ok := loadDeviceProfile(path); ok && profile.Scans > 0 && profile.DirsPerSec < slowVolumeDirsPerSec {
+		return duTimeout * 2
+	}
+	return duTimeout
+}
+
+// duDisabledFor reports whether du has timed out on path often enough, and recently
+// enough, to go straight to a walk.
+func duDisabledFor(path string) error {
+	profile, ok := loadDeviceProfile(path)
+	if !ok {
+		return nil
+	}
+	if failure, ok := profile.DuFailures[path]; ok && failure.Count >= maxDuFailures && time.Since(failure.Last) < duFailureTTL {
+		return fmt.Errorf("du disabled for this folder after %d timeouts", failure.Count)
+	}
+	return nil
+}
+
+// recordDuFailure counts a du timeout on path, dropping the volume's expired ones.
+func recordDuFailure(path string) {
+	now := time.Now()
+	updateDeviceProfile(path, func(p *deviceProfile) {
+		if p.DuFailures == nil {
+			p.DuFailures = make(map[string]duFailure)
+		}
+		for folder, failure := range p.DuFailures {
+			if now.Sub(failure.Last) >= duFailureTTL {
+				delete(p.DuFailures, folder)
+			}
+		}
+		failure := p.DuFailures[path]
+		failure.Count++
+		failure.Last = now
+		p.DuFailures[path] = failure
+	})
+}
+
+// recordDuSuccess clears path's timeouts once du finishes there again.
+func recordDuSuccess(path string) {
+	if profile, ok := loadDeviceProfile(path); !ok || profile.DuFailures[path].Count == 0 {
+		return
+	}
+	updateDeviceProfile(path, func(p *deviceProfile) { delete(p.DuFailures, path) })
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRecordScanThroughputTracksBestWorkers(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	deviceProfileMu.Lock()
+	deviceProfiles = nil
+	deviceProfileLoaded = false
+	deviceProfileMu.Unlock()
+
+	recordScanThroughput(home, 2*time.Second, 2000, 1<<30, 16)
+	recordScanThroughput(home, 2*time.Second, 4000, 1<<30, 32)
+	recordScanThroughput(home, 2*time.Second, 1000, 1<<30, 64)
+	recordScanThroughput(home, 10*time.Millisecond, 10, 10, 8) // too short, ignored
+
+	profile, ok := loadDeviceProfile(home)
+	if !ok {
+		t.Fatalf("expected a stored profile for the temp volume")
+	}
+	if profile.Scans != 3 {
+		t.Fatalf("expected 3 recorded scans, got %d", profile.Scans)
+	}
+	if profile.BestWorkers != 32 {
+		t.Fatalf("expected best worker cap 32, got %d", profile.BestWorkers)
+	}
+
+	// Reload from disk.
+	deviceProfileMu.Lock()
+	deviceProfiles = nil
+	deviceProfileLoaded = false
+	deviceProfileMu.Unlock()
+	if reloaded, ok := loadDeviceProfile(home); !ok || reloaded.Scans != 3 {
+		t.Fatalf("expected profile to persist, got %+v", reloaded)
+	}
+}
+
+func TestMountPointOfRoot(t *testing.T) {
+	mount, err := mountPointOf("/")
+	if err != nil || mount != "/" {
+		t.Fatalf("mountPointOf(/) = %q, %v", mount, err)
+	}
+}
+
+func TestDuFailuresAreKeptPerFolder(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	deviceProfileMu.Lock()
+	deviceProfiles = nil
+	deviceProfileLoaded = false
+	deviceProfileMu.Unlock()
+
+	slow, other := filepath.Join(home, "slow"), filepath.Join(home, "other")
+	for _, dir := range []string{slow, other} {
+		if err := os.Mkdir(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	recordDuFailure(slow)
+	if err := duDisabledFor(slow); err != nil {
+		t.Fatalf("expected du to stay on after one timeout, got %v", err)
+	}
+	recordDuFailure(slow)
+	if err := duDisabledFor(slow); err == nil {
+		t.Fatalf("expected du disabled for %s after %d timeouts", slow, maxDuFailures)
+	}
+	if err := duDisabledFor(other); err != nil {
+		t.Fatalf("expected du to stay on for a sibling folder, got %v", err)
+	}
+
+	recordDuSuccess(slow)
+	if err := duDisabledFor(slow); err != nil {
+		t.Fatalf("expected a successful du to clear the timeouts, got %v", err)
+	}
+
+	updateDeviceProfile(slow, func(p *deviceProfile) {
+		p.DuFailures = map[string]duFailure{slow: {Count: maxDuFailures, Last: time.Now().Add(-duFailureTTL)}}
+	})
+	if err := duDisabledFor(slow); err != nil {
+		t.Fatalf("expected old timeouts to expire, got %v", err)
+	}
+}
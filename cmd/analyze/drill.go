@@ -0,0 +1,46 @@
+//go:build darwin
+
+package main
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// largestEntryIndex ignores pin order so the drill follows real sizes.
+func largestEntryIndex(entries []dirEntry) int {
+	best := -1
+	for i, entry := range entries {
+		if best < 0 || entry.Size > entries[best].Size {
+			best = i
+		}
+	}
+	return best
+}
+
+// drillStep enters the largest child until a file, package or folded directory is reached.
+// Cached levels are walked synchronously; uncached ones resume from scanResultMsg.
+func (m model) drillStep() (tea.Model, tea.Cmd) {
+	for m.drilling {
+		idx := largestEntryIndex(m.entries)
+		if idx < 0 {
+			m.drilling = false
+			break
+		}
+		m.selected = idx
+		m.clampEntrySelection()
+		largest := m.entries[idx]
+		if !largest.IsDir || largest.Bundle || largest.Size <= 0 || shouldFoldDirWithPath(largest.Name, largest.Path) {
+			m.drilling = false
+			m.status = fmt.Sprintf("Largest: %s (%s)", displayPath(largest.Path), humanizeBytes(largest.Size))
+			break
+		}
+		next, cmd := m.enterSelectedDir()
+		m = next.(model)
+		if cmd != nil {
+			return m, cmd
+		}
+	}
+	return m, nil
+}
@@ -0,0 +1,19 @@
+//go:build darwin
+
+package main
+
+import "testing"
+
+func TestLargestEntryIndexIgnoresPinOrder(t *testing.T) {
+	entries := []dirEntry{
+		{Name: "pinned", Size: 10},
+		{Name: "big", Size: 500},
+		{Name: "mid", Size: 200},
+	}
+	if idx := largestEntryIndex(entries); idx != 1 {
+		t.Fatalf("expected index 1, got %d", idx)
+	}
+	if idx := largestEntryIndex(nil); idx != -1 {
+		t.Fatalf("expected -1 for empty list, got %d", idx)
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// driveHealthTimeout bounds diskutil and smartctl, which can stall on sleeping disks.
+const driveHealthTimeout = 10 * time.Second
+
+// smartctlPaths are where Homebrew installs smartmontools when it is not on PATH.
+var smartctlPaths = []string{"/opt/homebrew/bin/smartctl", "/usr/local/bin/smartctl"}
+
+// driveHealth is what diskutil, and smartctl when installed, report about the drive
+// behind a volume. Spare and Used are NVMe percentages, -1 when unknown.
+type driveHealth struct {
+	Disk     string // Whole disk, e.g. disk4
+	Media    string
+	Protocol string
+	Solid    bool
+	SMART    string // Verified, Failing, Not Supported...
+	Spare    int    // Available spare
+	Used     int    // Percentage of rated endurance used
+}
+
+type driveHealthMsg struct {
+	Volume string
+	Health driveHealth
+	Err    error
+}
+
+// parseDiskutilInfo reads the "Key: value" lines of `diskutil info`.
+func parseDiskutilInfo(output string) driveHealth {
+	health := driveHealth{Spare: -1, Used: -1}
+	for _, line := range strings.Split(output, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "Part of Whole":
+			health.Disk = value
+		case "Device / Media Name":
+			health.Media = value
+		case "Protocol":
+			health.Protocol = value
+		case "Solid State":
+			health.Solid = value == "Yes"
+		case "SMART Status":
+			health.SMART = value
+		}
+	}
+	return health
+}
+
+// parseSmartctlNVMe fills in wear from `smartctl -a` output for NVMe drives.
+func parseSmartctlNVMe(output string, health *driveHealth) {
+	percent := func(value string) int {
+		n, err := strconv.Atoi(strings.TrimSuffix(strings.TrimSpace(value), "%"))
+		if err != nil {
+			return -1
+		}
+		return n
+	}
+	for _, line := range strings.Split(output, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "Available Spare":
+			health.Spare = percent(value)
+		case "Percentage Used":
+			health.Used = percent(value)
+		}
+	}
+}
+
+// failing reports whether the drive has asked to be replaced.
+func (h driveHealth) failing() bool {
+	return h.SMART == "Failing" || (h.Spare >= 0 && h.Spare <= 10) || h.Used >= 90
+}
+
+// summary is the one-line condition shown for the selected volume.
+func (h driveHealth) summary() string {
+	var parts []string
+	if h.Media != "" {
+		kind := "HDD"
+		if h.Solid {
+			kind = "SSD"
+		}
+		parts = append(parts, fmt.Sprintf("%s (%s %s)", h.Media, strings.TrimSpace(h.Protocol), kind))
+	}
+	if h.SMART != "" {
+		parts = append(parts, "SMART "+h.SMART)
+	}
+	if h.Spare >= 0 {
+		parts = append(parts, fmt.Sprintf("%d%% spare", h.Spare))
+	}
+	if h.Used >= 0 {
+		parts = append(parts, fmt.Sprintf("%d%% worn", h.Used))
+	}
+	return strings.Join(parts, "  |  ")
+}
+
+func findSmartctl() string {
+	if path, err := exec.LookPath("smartctl"); err == nil {
+		return path
+	}
+	for _, path := range smartctlPaths {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// driveHealthCmd asks diskutil about the drive behind volume, then smartctl for wear.
+func driveHealthCmd(volume string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), driveHealthTimeout)
+		defer cancel()
+		output, err := exec.CommandContext(ctx, "diskutil", "info", volume).Output()
+		if err != nil {
+			return driveHealthMsg{Volume: volume, Err: err}
+		}
+		health := parseDiskutilInfo(string(output))
+		if smartctl := findSmartctl(); smartctl != "" && health.Disk != "" {
+			// smartctl exits non-zero for many warnings, so read its output regardless.
+			output, _ := exec.CommandContext(ctx, smartctl, "-a", health.Disk).Output()
+			parseSmartctlNVMe(string(output), &health)
+		}
+		return driveHealthMsg{Volume: volume, Health: health}
+	}
+}
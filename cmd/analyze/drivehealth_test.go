@@ -0,0 +1,55 @@
+package main
+
+import "testing"
+
+const sampleDiskutilInfo = `   Device Identifier:         disk4s1
+   Device Node:               /dev/disk4s1
+   Whole:                     No
+   Part of Whole:             disk4
+
+   Volume Name:               Backup
+   Mount Point:               /Volumes/Backup
+
+   Device / Media Name:       Samsung SSD 970 EVO
+
+   Protocol:                  USB
+   SMART Status:              Verified
+
+   Solid State:               Yes
+`
+
+const sampleSmartctlNVMe = `=== START OF SMART DATA SECTION ===
+SMART overall-health self-assessment test result: PASSED
+
+Critical Warning:                   0x00
+Available Spare:                    8%
+Available Spare Threshold:          10%
+Percentage Used:                    97%
+`
+
+func TestParseDiskutilInfo(t *testing.T) {
+	health := parseDiskutilInfo(sampleDiskutilInfo)
+	if health.Disk != "disk4" || health.Media != "Samsung SSD 970 EVO" || health.Protocol != "USB" || !health.Solid || health.SMART != "Verified" {
+		t.Fatalf("unexpected health: %+v", health)
+	}
+	if health.Spare != -1 || health.Used != -1 || health.failing() {
+		t.Fatalf("wear should be unknown without smartctl: %+v", health)
+	}
+	if got, want := health.summary(), "Samsung SSD 970 EVO (USB SSD)  |  SMART Verified"; got != want {
+		t.Fatalf("summary = %q, want %q", got, want)
+	}
+}
+
+func TestParseSmartctlNVMe(t *testing.T) {
+	health := parseDiskutilInfo(sampleDiskutilInfo)
+	parseSmartctlNVMe(sampleSmartctlNVMe, &health)
+	if health.Spare != 8 || health.Used != 97 {
+		t.Fatalf("unexpected wear: %+v", health)
+	}
+	if !health.failing() {
+		t.Fatal("low spare and high wear should read as failing")
+	}
+	if !(driveHealth{SMART: "Failing", Spare: -1, Used: -1}).failing() {
+		t.Fatal("SMART Failing should read as failing")
+	}
+}
@@ -0,0 +1,187 @@
+//go:build darwin
+
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// duplicateView is the "d" mode listing identical files under the current folder.
+type duplicateView struct {
+	Root     string
+	Groups   []duplicateGroup
+	Scanning bool
+	Hashed   *int64
+	Err      error
+	Selected int  // Index into the flattened file rows
+	Confirm  bool // ⌫ pressed once; the next ⌫ deletes the other copies
+}
+
+type duplicateRow struct {
+	Group int
+	Path  string
+}
+
+func (d *duplicateView) rows() []duplicateRow {
+	var rows []duplicateRow
+	for i, group := range d.Groups {
+		for _, path := range group.Paths {
+			rows = append(rows, duplicateRow{Group: i, Path: path})
+		}
+	}
+	return rows
+}
+
+func (d *duplicateView) reclaimable() int64 {
+	var total int64
+	for _, group := range d.Groups {
+		total += group.reclaimable()
+	}
+	return total
+}
+
+// extraCopies lists every file in the selected row's group except the selected one.
+func (d *duplicateView) extraCopies() ([]string, duplicateRow, bool) {
+	rows := d.rows()
+	if d.Selected < 0 || d.Selected >= len(rows) {
+		return nil, duplicateRow{}, false
+	}
+	keep := rows[d.Selected]
+	var paths []string
+	for _, path := range d.Groups[keep.Group].Paths {
+		if path != keep.Path {
+			paths = append(paths, path)
+		}
+	}
+	return paths, keep, true
+}
+
+func (m model) openDuplicates() (tea.Model, tea.Cmd) {
+	if m.inventory != nil {
+		m.status = fmt.Sprintf("%s is a read-only listing", m.inventory.Source)
+		return m, nil
+	}
+	if m.inOverviewMode() {
+		m.status = "Open a folder first to look for duplicates"
+		return m, nil
+	}
+	var hashed int64
+	m.dupes = &duplicateView{Root: m.path, Scanning: true, Hashed: &hashed}
+	m.status = fmt.Sprintf("Looking for duplicates in %s...", displayPath(m.path))
+	return m, tea.Batch(findDuplicatesCmd(m.path, &hashed), tickCmd())
+}
+
+// updateDuplicatesKey handles keys while the duplicates view is open.
+func (m model) updateDuplicatesKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	d := m.dupes
+	key := msg.String()
+	confirm := d.Confirm
+	d.Confirm = false
+	rows := d.rows()
+	if confirm && key == "esc" {
+		m.status = "Cancelled"
+		return m, nil
+	}
+	switch key {
+	case "q", "Q", "ctrl+c":
+		return m.quit()
+	case "esc", "b", "left", "h", "d":
+		m.dupes = nil
+		m.status = fmt.Sprintf("Scanned %s", humanizeBytes(m.totalSize))
+	case "up", "k":
+		d.Selected = max(d.Selected-1, 0)
+	case "down", "j":
+		d.Selected = min(d.Selected+1, max(len(rows)-1, 0))
+	case "delete", "backspace", "alt+delete", "alt+backspace":
+		paths, keep, ok := d.extraCopies()
+		if !ok || d.Scanning || m.deleting {
+			return m, nil
+		}
+		if m.safeMode {
+			for _, path := range paths {
+				if _, allowed := safeDeleteCategory(path); !allowed {
+					m.status = safeModeBlockReason(path)
+					return m, nil
+				}
+			}
+		}
+		if !confirm {
+			d.Confirm = true
+			m.deletePermanent = m.permanentDelete || strings.HasPrefix(key, "alt+")
+			return m, nil
+		}
+		permanent := m.deletePermanent
+		m.deletePermanent = false
+		m.deleting = true
+		var deleteCount int64
+		m.deleteCount = &deleteCount
+		m.deleteBatch = &deleteBatchProgress{Total: len(paths), Permanent: permanent}
+		m.status = fmt.Sprintf("Keeping %s, removing %d copies...", displayPath(keep.Path), len(paths))
+		return m, tea.Batch(deletePathCmd(paths, m.deleteCount, m.deleteBatch, permanent), tickCmd())
+	}
+	return m, nil
+}
+
+// renderDuplicates draws the groups with the selected file marked as the keeper.
+func (m model) renderDuplicates() string {
+	d := m.dupes
+	var b strings.Builder
+	if d.Scanning {
+		fmt.Fprintf(&b, "%s%s%s%s Hashing candidates: %s%s files%s checked\n",
+			colorCyan, colorBold, spinnerFrames[m.spinner], colorReset,
+			colorYellow, formatNumber(atomic.LoadInt64(d.Hashed)), colorReset)
+		return b.String()
+	}
+	if d.Err != nil {
+		fmt.Fprintf(&b, "  %sDuplicate search failed: %v%s\n", colorRed, d.Err, colorReset)
+		return b.String()
+	}
+	if len(d.Groups) == 0 {
+		fmt.Fprintf(&b, "  No duplicate files found (>= %s)\n\n", humanizeBytes(duplicateMinSize))
+		fmt.Fprintf(&b, "%sD/← Back | Q Quit%s\n", colorGray, colorReset)
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "%sDuplicates:%s %d groups, %s%s%s reclaimable\n\n",
+		colorCyan, colorReset, len(d.Groups), colorGreen, humanizeBytes(d.reclaimable()), colorReset)
+
+	rows := d.rows()
+	selected := min(d.Selected, len(rows)-1)
+	viewport := calculateViewport(m.height, true)
+	start := max(0, selected-viewport+1)
+	nameWidth := calculateNameWidth(m.width)
+	for i := start; i < min(len(rows), start+viewport); i++ {
+		row := rows[i]
+		group := d.Groups[row.Group]
+		if i == start || rows[i-1].Group != row.Group {
+			fmt.Fprintf(&b, "  %s%d copies × %s, %s reclaimable%s\n",
+				colorGray, len(group.Paths), humanizeBytes(group.Size), humanizeBytes(group.reclaimable()), colorReset)
+		}
+		name := trimNameWithWidth(displayPath(row.Path), nameWidth+20)
+		if i == selected {
+			fmt.Fprintf(&b, " %s%s▶%s %s%s%s  %skeep%s\n", colorCyan, colorBold, colorReset, colorCyan, name, colorReset, colorGreen, colorReset)
+		} else {
+			fmt.Fprintf(&b, "    %s\n", name)
+		}
+	}
+
+	fmt.Fprintln(&b)
+	if d.Confirm {
+		paths, keep, _ := d.extraCopies()
+		verb := "Move to Trash:"
+		if m.deletePermanent {
+			verb = "Delete permanently:"
+		}
+		fmt.Fprintf(&b, "%s%s%s %d copies of %s (%s)  %sPress ⌫ again  |  ESC cancel%s\n",
+			colorRed, verb, colorReset, len(paths), displayName(keep.Path, filepath.Base(keep.Path)),
+			humanizeBytes(d.Groups[keep.Group].Size*int64(len(paths))), colorGray, colorReset)
+	} else {
+		fmt.Fprintf(&b, "%s↑↓ Choose copy to keep | ⌫ Remove other copies | D/← Back | Q Quit%s\n", colorGray, colorReset)
+	}
+	return b.String()
+}
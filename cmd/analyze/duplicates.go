@@ -0,0 +1,220 @@
+package main
+
+import (
+	"crypto/sha256"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"syscall"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// duplicateMinSize ignores small files; hashing them costs more than they reclaim.
+	duplicateMinSize = 1 << 20
+	// duplicatePartialBytes is read from each end of a file for the partial hash.
+	duplicatePartialBytes = 64 << 10
+)
+
+// duplicateGroup is a set of files with identical content.
+type duplicateGroup struct {
+	Size  int64
+	Paths []string
+}
+
+// reclaimable is what deleting every copy but one would free.
+func (g duplicateGroup) reclaimable() int64 {
+	return g.Size * int64(len(g.Paths)-1)
+}
+
+type duplicatesMsg struct {
+	Root   string
+	Groups []duplicateGroup
+	Err    error
+}
+
+type fileIdentity struct {
+	dev, ino uint64
+}
+
+// collectDuplicateCandidates groups regular files by size, skipping hard links
+// to an already seen inode since deleting them frees nothing.
+func collectDuplicateCandidates(root string, minSize int64) (map[int64][]string, error) {
+	bySize := make(map[int64][]string)
+	seen := make(map[fileIdentity]bool)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if d != nil && d.IsDir() && path != root {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			if path != root && (defaultSkipDirs[d.Name()] || scanStrategyFor(path) == strategySkip) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil || info.Size() < minSize || isDataless(path) {
+			return nil
+		}
+		if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+			id := fileIdentity{dev: uint64(stat.Dev), ino: uint64(stat.Ino)}
+			if seen[id] {
+				return nil
+			}
+			seen[id] = true
+		}
+		bySize[info.Size()] = append(bySize[info.Size()], path)
+		return nil
+	})
+	for size, paths := range bySize {
+		if len(paths) < 2 {
+			delete(bySize, size)
+		}
+	}
+	return bySize, err
+}
+
+// hashFile hashes the first and last partial bytes when partial is set, else the whole file.
+// Cloud placeholders are refused rather than downloaded to be hashed.
+func hashFile(path string, size int64, partial bool) (string, error) {
+	if isDataless(path) {
+		return "", errDataless
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	h := sha256.New()
+	if partial && size > 2*duplicatePartialBytes {
+		if _, err := io.CopyN(h, file, duplicatePartialBytes); err != nil {
+			return "", err
+		}
+		if _, err := file.Seek(-duplicatePartialBytes, io.SeekEnd); err != nil {
+			return "", err
+		}
+	}
+	if _, err := io.Copy(h, file); err != nil {
+		return "", err
+	}
+	return string(h.Sum(nil)), nil
+}
+
+// splitByHash partitions same-size files by content hash, dropping singletons.
+func splitByHash(paths []string, size int64, partial bool, hashed *int64) [][]string {
+	type result struct {
+		path, sum string
+	}
+	results := make([]result, len(paths))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxDirWorkers)
+	for i, path := range paths {
+		wg.Add(1)
+		go func(i int, path string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			sum, err := hashFile(path, size, partial)
+			if err == nil {
+				results[i] = result{path, sum}
+			}
+			if hashed != nil {
+				atomic.AddInt64(hashed, 1)
+			}
+		}(i, path)
+	}
+	wg.Wait()
+
+	byHash := make(map[string][]string)
+	var order []string
+	for _, r := range results {
+		if r.path == "" {
+			continue
+		}
+		if _, ok := byHash[r.sum]; !ok {
+			order = append(order, r.sum)
+		}
+		byHash[r.sum] = append(byHash[r.sum], r.path)
+	}
+	var groups [][]string
+	for _, sum := range order {
+		if len(byHash[sum]) > 1 {
+			groups = append(groups, byHash[sum])
+		}
+	}
+	return groups
+}
+
+// findDuplicates narrows candidates by size, then partial hash, then full hash.
+// Groups are ordered by reclaimable space.
+func findDuplicates(root string, minSize int64, hashed *int64) ([]duplicateGroup, error) {
+	bySize, err := collectDuplicateCandidates(root, minSize)
+	if err != nil && len(bySize) == 0 {
+		return nil, err
+	}
+
+	var groups []duplicateGroup
+	for size, paths := range bySize {
+		for _, partialGroup := range splitByHash(paths, size, true, hashed) {
+			if size <= 2*duplicatePartialBytes {
+				// The partial hash already covered the whole file.
+				groups = append(groups, duplicateGroup{Size: size, Paths: partialGroup})
+				continue
+			}
+			for _, fullGroup := range splitByHash(partialGroup, size, false, hashed) {
+				groups = append(groups, duplicateGroup{Size: size, Paths: fullGroup})
+			}
+		}
+	}
+	for i := range groups {
+		sort.Strings(groups[i].Paths)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if a, b := groups[i].reclaimable(), groups[j].reclaimable(); a != b {
+			return a > b
+		}
+		return groups[i].Paths[0] < groups[j].Paths[0]
+	})
+	return groups, nil
+}
+
+func findDuplicatesCmd(root string, hashed *int64) tea.Cmd {
+	return func() tea.Msg {
+		groups, err := findDuplicates(root, duplicateMinSize, hashed)
+		return duplicatesMsg{Root: root, Groups: groups, Err: err}
+	}
+}
+
+// pruneDuplicateGroups drops removed paths and any group left with a single copy.
+func pruneDuplicateGroups(groups []duplicateGroup, removed []string) []duplicateGroup {
+	gone := make(map[string]bool, len(removed))
+	for _, path := range removed {
+		gone[path] = true
+	}
+	kept := groups[:0]
+	for _, group := range groups {
+		paths := group.Paths[:0]
+		for _, path := range group.Paths {
+			if !gone[path] {
+				paths = append(paths, path)
+			}
+		}
+		if len(paths) > 1 {
+			group.Paths = paths
+			kept = append(kept, group)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeDuplicateFixture(t *testing.T, path string, data []byte) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func TestFindDuplicatesGroupsIdenticalFiles(t *testing.T) {
+	root := t.TempDir()
+	content := bytes.Repeat([]byte("mole"), 100_000)
+	// Same size and same head and tail as content, different in the middle.
+	lookalike := append([]byte(nil), content...)
+	lookalike[len(lookalike)/2] = 'X'
+
+	writeDuplicateFixture(t, filepath.Join(root, "a", "movie.mov"), content)
+	writeDuplicateFixture(t, filepath.Join(root, "b", "movie copy.mov"), content)
+	writeDuplicateFixture(t, filepath.Join(root, "c", "edited.mov"), lookalike)
+	writeDuplicateFixture(t, filepath.Join(root, "small1"), []byte("tiny"))
+	writeDuplicateFixture(t, filepath.Join(root, "small2"), []byte("tiny"))
+	if err := os.Link(filepath.Join(root, "a", "movie.mov"), filepath.Join(root, "hardlink.mov")); err != nil {
+		t.Fatalf("link: %v", err)
+	}
+
+	var hashed int64
+	groups, err := findDuplicates(root, 1024, &hashed)
+	if err != nil {
+		t.Fatalf("findDuplicates: %v", err)
+	}
+	if len(groups) != 1 {
+		t.Fatalf("expected one group, got %+v", groups)
+	}
+	group := groups[0]
+	if len(group.Paths) != 2 || group.reclaimable() != int64(len(content)) {
+		t.Fatalf("unexpected group %+v", group)
+	}
+	for _, path := range group.Paths {
+		if filepath.Base(path) == "edited.mov" || filepath.Base(path) == "hardlink.mov" {
+			t.Fatalf("%s should not be grouped", path)
+		}
+	}
+	if hashed == 0 {
+		t.Fatalf("expected hash progress to be reported")
+	}
+}
+
+func TestPruneDuplicateGroups(t *testing.T) {
+	groups := []duplicateGroup{
+		{Size: 10, Paths: []string{"/a", "/b", "/c"}},
+		{Size: 5, Paths: []string{"/d", "/e"}},
+	}
+	groups = pruneDuplicateGroups(groups, []string{"/b", "/e"})
+	if len(groups) != 1 || len(groups[0].Paths) != 2 || groups[0].Paths[1] != "/c" {
+		t.Fatalf("unexpected groups after prune: %+v", groups)
+	}
+}
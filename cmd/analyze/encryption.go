@@ -0,0 +1,431 @@
+package main
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const (
+	encryptCacheFile   = "encrypt_cache" // Marker in ~/.config/mole, present when encryption is on
+	encryptCacheEnvVar = "MO_ANALYZE_ENCRYPT"
+	cacheKeyFile       = "cache.key" // Fallback when the login keychain is unavailable
+	cacheKeyService    = "mole-analyze-cache"
+	cacheKeyAccount    = "mole"
+	// keychainItemMissing is the exit status security(1) gives for errSecItemNotFound.
+	keychainItemMissing = 44
+)
+
+// exportOpenHint follows the path of an export written while encryption is on.
+const exportOpenHint = "encrypted, read it with mo cache open"
+
+// sealedMagic prefixes encrypted files so plaintext caches from older runs still load.
+var sealedMagic = []byte("MOLESEAL1\n")
+
+var (
+	cacheCryptoMu     sync.Mutex
+	cacheCryptoLoaded bool
+	cacheCryptoOn     bool
+	cacheCryptoKey    []byte
+
+	// cacheKeyLoader is swapped out in tests to keep the real keychain untouched.
+	cacheKeyLoader = loadOrCreateCacheKey
+)
+
+func resetCacheCryptoForTest() {
+	cacheCryptoMu.Lock()
+	cacheCryptoLoaded = false
+	cacheCryptoOn = false
+	cacheCryptoKey = nil
+	cacheCryptoMu.Unlock()
+}
+
+// cacheEncryptionEnabled reports the switch: env override first, then the config marker.
+func cacheEncryptionEnabled() bool {
+	cacheCryptoMu.Lock()
+	defer cacheCryptoMu.Unlock()
+	ensureCacheCryptoLoadedLocked()
+	return cacheCryptoOn
+}
+
+func ensureCacheCryptoLoadedLocked() {
+	if cacheCryptoLoaded {
+		return
+	}
+	cacheCryptoLoaded = true
+	switch strings.ToLower(strings.TrimSpace(os.Getenv(encryptCacheEnvVar))) {
+	case "1", "true", "on", "yes":
+		cacheCryptoOn = true
+		return
+	case "0", "false", "off", "no":
+		cacheCryptoOn = false
+		return
+	}
+	configDir, err := getConfigDir()
+	if err != nil {
+		return
+	}
+	_, err = os.Stat(filepath.Join(configDir, encryptCacheFile))
+	cacheCryptoOn = err == nil
+}
+
+// setCacheEncryption flips the persistent switch.
+func setCacheEncryption(on bool) error {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return err
+	}
+	marker := filepath.Join(configDir, encryptCacheFile)
+	if on {
+		if _, err := cacheKey(); err != nil {
+			return fmt.Errorf("cannot set up encryption key: %v", err)
+		}
+		err = os.WriteFile(marker, []byte("# Encrypt analyzer caches at rest\n"), 0644)
+	} else {
+		err = os.Remove(marker)
+		if os.IsNotExist(err) {
+			err = nil
+		}
+	}
+	if err != nil {
+		return err
+	}
+	cacheCryptoMu.Lock()
+	cacheCryptoLoaded = true
+	cacheCryptoOn = on
+	cacheCryptoMu.Unlock()
+	return nil
+}
+
+func cacheKey() ([]byte, error) {
+	cacheCryptoMu.Lock()
+	defer cacheCryptoMu.Unlock()
+	if cacheCryptoKey != nil {
+		return cacheCryptoKey, nil
+	}
+	key, err := cacheKeyLoader()
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("cache key has unexpected length %d", len(key))
+	}
+	cacheCryptoKey = key
+	return key, nil
+}
+
+// loadOrCreateCacheKey keeps a 256-bit key in the login keychain, or a 0600 file without one.
+// A new key is only made when the keychain has none: a locked keychain or a denied prompt
+// must not replace the key every sealed cache was written with.
+func loadOrCreateCacheKey() ([]byte, error) {
+	if _, err := exec.LookPath("security"); err == nil {
+		stored, err := readKeychainKey()
+		if err == nil {
+			return hex.DecodeString(stored)
+		}
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) || exitErr.ExitCode() != keychainItemMissing {
+			return nil, fmt.Errorf("cannot read the cache key from the keychain: %v", err)
+		}
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, err
+		}
+		if err := storeKeychainKey(hex.EncodeToString(key)); err == nil {
+			return key, nil
+		}
+	}
+
+	configDir, err := getConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	keyPath := filepath.Join(configDir, cacheKeyFile)
+	if data, err := os.ReadFile(keyPath); err == nil {
+		return hex.DecodeString(strings.TrimSpace(string(data)))
+	}
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(keyPath, []byte(hex.EncodeToString(key)+"\n"), 0600); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func readKeychainKey() (string, error) {
+	out, err := exec.Command("security", "find-generic-password",
+		"-s", cacheKeyService, "-a", cacheKeyAccount, "-w").Output()
+	return strings.TrimSpace(string(out)), err
+}
+
+// storeKeychainKey feeds the add command to security's interactive mode on stdin, so the
+// key never shows up in the argument list ps prints to every user. That mode exits 0
+// even when a command fails, so the stored key is read back to confirm it.
+func storeKeychainKey(encoded string) error {
+	cmd := exec.Command("security", "-i")
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -U -s %s -a %s -w %s\n",
+		cacheKeyService, cacheKeyAccount, encoded))
+	if err := cmd.Run(); err != nil {
+		return err
+	}
+	stored, err := readKeychainKey()
+	if err != nil {
+		return err
+	}
+	if stored != encoded {
+		return fmt.Errorf("keychain did not keep the cache key")
+	}
+	return nil
+}
+
+func sealWithKey(key, plain []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	out := append([]byte{}, sealedMagic...)
+	out = append(out, nonce...)
+	return gcm.Seal(out, nonce, plain, sealedMagic), nil
+}
+
+func openWithKey(key, sealed []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	body := sealed[len(sealedMagic):]
+	if len(body) < gcm.NonceSize() {
+		return nil, fmt.Errorf("sealed data truncated")
+	}
+	return gcm.Open(nil, body[:gcm.NonceSize()], body[gcm.NonceSize():], sealedMagic)
+}
+
+// sealCacheData encrypts data when the switch is on and returns it unchanged otherwise.
+func sealCacheData(data []byte) ([]byte, error) {
+	if !cacheEncryptionEnabled() {
+		return data, nil
+	}
+	key, err := cacheKey()
+	if err != nil {
+		return nil, err
+	}
+	return sealWithKey(key, data)
+}
+
+// openCacheData decrypts sealed data; plaintext passes through so old caches keep working.
+func openCacheData(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, sealedMagic) {
+		return data, nil
+	}
+	key, err := cacheKey()
+	if err != nil {
+		return nil, err
+	}
+	return openWithKey(key, data)
+}
+
+func readCacheFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return openCacheData(data)
+}
+
+// writeCacheFile seals data as configured and replaces path atomically. Nothing is
+// written while the volume is critically low on space; the old file stays in place.
+func writeCacheFile(path string, data []byte) error {
+	if err := checkWritableSpace(path); err != nil {
+		return err
+	}
+	sealed, err := sealCacheData(data)
+	if err != nil {
+		return err
+	}
+	perm := os.FileMode(0644)
+	if cacheEncryptionEnabled() {
+		perm = 0600
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, sealed, perm); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// writeExportFile writes an export meant for other tools. With encryption on it is sealed
+// like the caches, which means buffering it whole; `mo cache open` prints it back.
+func writeExportFile(dest string, perm os.FileMode, write func(io.Writer) error) error {
+	if !cacheEncryptionEnabled() {
+		file, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+		if err != nil {
+			return err
+		}
+		if err := write(file); err != nil {
+			_ = file.Close()
+			return err
+		}
+		return file.Close()
+	}
+	var buf bytes.Buffer
+	if err := write(&buf); err != nil {
+		return err
+	}
+	sealed, err := sealCacheData(buf.Bytes())
+	if err != nil {
+		return err
+	}
+	// A new file, so an older plaintext export at dest does not pass on its wider mode.
+	tmpPath := dest + ".tmp"
+	if err := os.WriteFile(tmpPath, sealed, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, dest)
+}
+
+// exportStatus describes a saved export for the status line.
+func exportStatus(what, path string) string {
+	status := fmt.Sprintf("%s to %s", what, displayPath(path))
+	if cacheEncryptionEnabled() {
+		status += " (" + exportOpenHint + ")"
+	}
+	return status
+}
+
+// analyzerStateFiles are the fixed-name files writeCacheFile keeps next to the hashed caches.
+var analyzerStateFiles = map[string]bool{overviewCacheFile: true, journalMarksFile: true, deviceProfileFile: true}
+
+// isAnalyzerCacheFile matches every file writeCacheFile writes, not shell-side state.
+func isAnalyzerCacheFile(name string) bool {
+	name = strings.TrimSuffix(strings.TrimSuffix(name, ".tmp"), ".corrupt")
+	return strings.HasSuffix(name, ".cache") || strings.HasSuffix(name, ".history") || analyzerStateFiles[name]
+}
+
+// wipeAnalyzerCaches overwrites cached inventories with zeros before removing them.
+// APFS is copy-on-write, so this is best-effort; encryption is the real protection.
+func wipeAnalyzerCaches() (int, error) {
+	cacheDir, err := getCacheDir()
+	if err != nil {
+		return 0, err
+	}
+	entries, err := os.ReadDir(cacheDir)
+	if err != nil {
+		return 0, err
+	}
+	removed := 0
+	var firstErr error
+	for _, entry := range entries {
+		if entry.IsDir() || !isAnalyzerCacheFile(entry.Name()) {
+			continue
+		}
+		path := filepath.Join(cacheDir, entry.Name())
+		if err := shredFile(path); err != nil && firstErr == nil {
+			firstErr = err
+			continue
+		}
+		removed++
+	}
+
+	overviewSnapshotMu.Lock()
+	overviewSnapshotLoaded = false
+	overviewSnapshotCache = nil
+	overviewSnapshotMu.Unlock()
+	deviceProfileMu.Lock()
+	deviceProfileLoaded = false
+	deviceProfileMu.Unlock()
+	return removed, firstErr
+}
+
+func shredFile(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if file, err := os.OpenFile(path, os.O_WRONLY, 0); err == nil {
+		zeros := make([]byte, 32*1024)
+		for remaining := info.Size(); remaining > 0; {
+			n := int64(len(zeros))
+			if remaining < n {
+				n = remaining
+			}
+			if _, err := file.Write(zeros[:n]); err != nil {
+				break
+			}
+			remaining -= n
+		}
+		_ = file.Sync()
+		_ = file.Close()
+	}
+	return os.Remove(path)
+}
+
+// runCacheCommand implements `mo cache <action>`.
+func runCacheCommand(args []string) error {
+	action := "status"
+	if len(args) > 0 {
+		action = args[0]
+	}
+	switch action {
+	case "status":
+		state := "off"
+		if cacheEncryptionEnabled() {
+			state = "on"
+		}
+		fmt.Printf("Cache encryption: %s\n", state)
+		return nil
+	case "encrypt":
+		if err := setCacheEncryption(true); err != nil {
+			return err
+		}
+		removed, err := wipeAnalyzerCaches()
+		fmt.Printf("Cache encryption enabled, wiped %d plaintext cache files\n", removed)
+		return err
+	case "decrypt":
+		if err := setCacheEncryption(false); err != nil {
+			return err
+		}
+		removed, err := wipeAnalyzerCaches()
+		fmt.Printf("Cache encryption disabled, wiped %d encrypted cache files\n", removed)
+		return err
+	case "wipe":
+		removed, err := wipeAnalyzerCaches()
+		fmt.Printf("Wiped %d cache files\n", removed)
+		return err
+	case "open":
+		if len(args) < 2 {
+			return fmt.Errorf("usage: mo cache open <file>")
+		}
+		data, err := readCacheFile(args[1])
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(data)
+		return err
+	default:
+		return fmt.Errorf("unknown cache action %q, expected status, encrypt, decrypt, wipe or open", action)
+	}
+}
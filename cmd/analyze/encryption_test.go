@@ -0,0 +1,196 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func withTestCacheKey(t *testing.T) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(encryptCacheEnvVar, "")
+	resetCacheCryptoForTest()
+	prev := cacheKeyLoader
+	cacheKeyLoader = func() ([]byte, error) { return bytes.Repeat([]byte{7}, 32), nil }
+	t.Cleanup(func() {
+		cacheKeyLoader = prev
+		resetCacheCryptoForTest()
+	})
+}
+
+func TestCacheRoundTripWhenEncrypted(t *testing.T) {
+	withTestCacheKey(t)
+	if err := setCacheEncryption(true); err != nil {
+		t.Fatalf("setCacheEncryption: %v", err)
+	}
+
+	target := t.TempDir()
+	writeFileWithSize(t, filepath.Join(target, "secret-project", "notes.txt"), 100)
+	result := scanResult{
+		Entries:   []dirEntry{{Name: "secret-project", Path: filepath.Join(target, "secret-project"), Size: 100, IsDir: true}},
+		TotalSize: 100,
+	}
+	if err := saveCacheToDisk(target, result); err != nil {
+		t.Fatalf("saveCacheToDisk: %v", err)
+	}
+
+	cachePath, _ := getCachePath(target)
+	raw, err := os.ReadFile(cachePath)
+	if err != nil {
+		t.Fatalf("read cache: %v", err)
+	}
+	if !bytes.HasPrefix(raw, sealedMagic) || bytes.Contains(raw, []byte("secret-project")) {
+		t.Fatalf("expected sealed cache without plaintext names")
+	}
+
+	entry, err := loadCacheFromDisk(target)
+	if err != nil {
+		t.Fatalf("loadCacheFromDisk: %v", err)
+	}
+	if entry.TotalSize != 100 || len(entry.Entries) != 1 || entry.Entries[0].Name != "secret-project" {
+		t.Fatalf("unexpected decrypted entry %+v", entry)
+	}
+}
+
+func TestPlaintextCacheStillLoadsWithEncryptionOn(t *testing.T) {
+	withTestCacheKey(t)
+	target := t.TempDir()
+	if err := saveCacheToDisk(target, scanResult{TotalSize: 42}); err != nil {
+		t.Fatalf("saveCacheToDisk: %v", err)
+	}
+	if err := setCacheEncryption(true); err != nil {
+		t.Fatalf("setCacheEncryption: %v", err)
+	}
+	entry, err := loadCacheFromDisk(target)
+	if err != nil || entry.TotalSize != 42 {
+		t.Fatalf("expected legacy plaintext cache to load, got %+v, %v", entry, err)
+	}
+}
+
+func TestWipeAnalyzerCachesKeepsShellState(t *testing.T) {
+	withTestCacheKey(t)
+	if err := saveCacheToDisk(t.TempDir(), scanResult{TotalSize: 1}); err != nil {
+		t.Fatalf("saveCacheToDisk: %v", err)
+	}
+	if err := storeOverviewSize("/Applications", 1024); err != nil {
+		t.Fatalf("storeOverviewSize: %v", err)
+	}
+	if err := saveJournalMarks(map[string]journalMark{"/Users": {Volume: "uuid", EventID: 1}}); err != nil {
+		t.Fatalf("saveJournalMarks: %v", err)
+	}
+	cacheDir, _ := getCacheDir()
+	if err := writeCacheFile(filepath.Join(cacheDir, deviceProfileFile), []byte("{}")); err != nil {
+		t.Fatalf("write device profiles: %v", err)
+	}
+	shellState := filepath.Join(cacheDir, "app_scan_cache")
+	if err := os.WriteFile(shellState, []byte("keep"), 0644); err != nil {
+		t.Fatalf("write shell state: %v", err)
+	}
+
+	removed, err := wipeAnalyzerCaches()
+	if err != nil {
+		t.Fatalf("wipeAnalyzerCaches: %v", err)
+	}
+	if removed != 4 {
+		t.Fatalf("expected 4 files wiped, got %d", removed)
+	}
+	if _, err := os.Stat(shellState); err != nil {
+		t.Fatalf("expected shell state to survive: %v", err)
+	}
+	if _, err := loadStoredOverviewSize("/Applications"); err == nil {
+		t.Fatalf("expected overview snapshot to be gone after wipe")
+	}
+}
+
+func TestLoadOrCreateCacheKeyKeepsKeyOffArgv(t *testing.T) {
+	bin := t.TempDir()
+	log := filepath.Join(bin, "args.log")
+	store := filepath.Join(bin, "stored")
+	// A stand-in for security(1): logs its arguments and keeps what -i is told to add.
+	fake := "#!/bin/sh\n" +
+		"echo \"$@\" >> " + log + "\n" +
+		"case \"$1\" in\n" +
+		"find-generic-password) cat " + store + " 2>/dev/null || exit 44 ;;\n" +
+		"-i) read line; echo \"${line##* -w }\" > " + store + " ;;\n" +
+		"esac\n"
+	if err := os.WriteFile(filepath.Join(bin, "security"), []byte(fake), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("HOME", t.TempDir())
+
+	key, err := loadOrCreateCacheKey()
+	if err != nil {
+		t.Fatalf("loadOrCreateCacheKey: %v", err)
+	}
+	again, err := loadOrCreateCacheKey()
+	if err != nil || !bytes.Equal(again, key) {
+		t.Fatalf("expected the stored key back, got %x, %v", again, err)
+	}
+	args, _ := os.ReadFile(log)
+	if strings.Contains(string(args), hex.EncodeToString(key)) {
+		t.Fatalf("cache key passed on the command line:\n%s", args)
+	}
+	configDir, _ := getConfigDir()
+	if _, err := os.Stat(filepath.Join(configDir, cacheKeyFile)); err == nil {
+		t.Fatalf("expected the keychain to hold the key, not the fallback file")
+	}
+}
+
+func TestLoadOrCreateCacheKeyKeepsKeyWhenKeychainLocked(t *testing.T) {
+	bin := t.TempDir()
+	log := filepath.Join(bin, "args.log")
+	// errSecInteractionNotAllowed: the keychain is locked, so the stored key cannot be read.
+	fake := "#!/bin/sh\necho \"$@\" >> " + log + "\nexit 36\n"
+	if err := os.WriteFile(filepath.Join(bin, "security"), []byte(fake), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("HOME", t.TempDir())
+
+	if _, err := loadOrCreateCacheKey(); err == nil {
+		t.Fatalf("expected an error from a locked keychain")
+	}
+	args, _ := os.ReadFile(log)
+	if strings.Contains(string(args), "-i") {
+		t.Fatalf("keychain item overwritten after a failed read:\n%s", args)
+	}
+	configDir, _ := getConfigDir()
+	if _, err := os.Stat(filepath.Join(configDir, cacheKeyFile)); err == nil {
+		t.Fatalf("expected no fallback key while the keychain holds one")
+	}
+}
+
+func TestExportsSealedWhenEncrypted(t *testing.T) {
+	withTestCacheKey(t)
+	inventory := demoInventory()
+	dest := filepath.Join(t.TempDir(), "out.json")
+	if err := exportNcduFile(inventory.Root.Path, inventory, dest); err != nil {
+		t.Fatalf("plaintext export: %v", err)
+	}
+	if raw, _ := os.ReadFile(dest); bytes.HasPrefix(raw, sealedMagic) {
+		t.Fatalf("export sealed with encryption off")
+	}
+
+	if err := setCacheEncryption(true); err != nil {
+		t.Fatalf("setCacheEncryption: %v", err)
+	}
+	if err := exportNcduFile(inventory.Root.Path, inventory, dest); err != nil {
+		t.Fatalf("sealed export: %v", err)
+	}
+	raw, err := os.ReadFile(dest)
+	if err != nil || !bytes.HasPrefix(raw, sealedMagic) || bytes.Contains(raw, []byte("Old Backup.sparsebundle")) {
+		t.Fatalf("expected a sealed export without plaintext names, got %v", err)
+	}
+	if info, _ := os.Stat(dest); info.Mode().Perm() != 0600 {
+		t.Fatalf("sealed export mode = %v, want 0600", info.Mode().Perm())
+	}
+	plain, err := readCacheFile(dest)
+	if err != nil || !bytes.HasPrefix(plain, []byte("[1,")) {
+		t.Fatalf("mo cache open would print %q, %v", plain, err)
+	}
+}
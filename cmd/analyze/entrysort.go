@@ -0,0 +1,87 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+// entrySortMode orders the directory listing; size is the default.
+type entrySortMode int
+
+const (
+	entrySortSize entrySortMode = iota
+	entrySortName
+	entrySortFiles
+	entrySortAccess
+	entrySortWeighted // Size plus a weight per file and folder, see weightedRank
+	entrySortModes
+)
+
+func (s entrySortMode) label() string {
+	switch s {
+	case entrySortName:
+		return "Name"
+	case entrySortFiles:
+		return "Files"
+	case entrySortAccess:
+		return "Accessed"
+	case entrySortWeighted:
+		return "Weighted"
+	default:
+		return "Size"
+	}
+}
+
+// parseEntrySort matches a mode by its label, ignoring case.
+func parseEntrySort(name string) (entrySortMode, bool) {
+	for mode := entrySortMode(0); mode < entrySortModes; mode++ {
+		if strings.EqualFold(mode.label(), name) {
+			return mode, true
+		}
+	}
+	return entrySortSize, false
+}
+
+// entrySortNames lists the modes as settings spell them.
+func entrySortNames() string {
+	names := make([]string, 0, entrySortModes)
+	for mode := entrySortMode(0); mode < entrySortModes; mode++ {
+		names = append(names, strings.ToLower(mode.label()))
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " or " + names[len(names)-1]
+}
+
+func (s entrySortMode) next() entrySortMode {
+	return (s + 1) % entrySortModes
+}
+
+// sortEntries reorders entries in place; unknown counts and times sink, ties fall back to size.
+func sortEntries(entries []dirEntry, mode entrySortMode) []dirEntry {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		switch mode {
+		case entrySortName:
+			if na, nb := strings.ToLower(a.Name), strings.ToLower(b.Name); na != nb {
+				return na < nb
+			}
+		case entrySortFiles:
+			if a.Files != b.Files {
+				return a.Files > b.Files
+			}
+		case entrySortWeighted:
+			if ra, rb := weightedRank(a), weightedRank(b); ra != rb {
+				return ra > rb
+			}
+		case entrySortAccess:
+			if !a.LastAccess.Equal(b.LastAccess) {
+				// Least recently used first: those are the cleanup candidates.
+				if a.LastAccess.IsZero() || b.LastAccess.IsZero() {
+					return b.LastAccess.IsZero()
+				}
+				return a.LastAccess.Before(b.LastAccess)
+			}
+		}
+		return a.Size > b.Size
+	})
+	return entries
+}
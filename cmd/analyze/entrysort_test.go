@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSortEntriesModes(t *testing.T) {
+	now := time.Now()
+	base := func() []dirEntry {
+		return []dirEntry{
+			{Name: "beta", Size: 300, Files: 10, LastAccess: now},
+			{Name: "Alpha", Size: 100, Files: 900, LastAccess: now.Add(-90 * 24 * time.Hour)},
+			{Name: "gamma", Size: 200, Files: -1},
+		}
+	}
+	cases := []struct {
+		mode entrySortMode
+		want []string
+	}{
+		{entrySortSize, []string{"beta", "gamma", "Alpha"}},
+		{entrySortName, []string{"Alpha", "beta", "gamma"}},
+		{entrySortFiles, []string{"Alpha", "beta", "gamma"}},
+		{entrySortAccess, []string{"Alpha", "beta", "gamma"}},
+		{entrySortWeighted, []string{"Alpha", "beta", "gamma"}},
+	}
+	for _, tc := range cases {
+		got := sortEntries(base(), tc.mode)
+		for i, name := range tc.want {
+			if got[i].Name != name {
+				t.Fatalf("%s: expected %v at %d, got %s", tc.mode.label(), tc.want, i, got[i].Name)
+			}
+		}
+	}
+}
+
+func TestScanCountsFilesAndDirsPerEntry(t *testing.T) {
+	root := t.TempDir()
+	for i, name := range []string{"a.txt", "b.txt", "nested/c.txt", "nested/deeper/d.txt"} {
+		writeFileWithSize(t, filepath.Join(root, "docs", name), 100*(i+1))
+	}
+	writeFileWithSize(t, filepath.Join(root, "single.bin"), 10)
+
+	var files, dirs, bytes int64
+	var current string
+	result, err := scanPathConcurrent(context.Background(), root, &files, &dirs, &bytes, &current, nil)
+	if err != nil {
+		t.Fatalf("scanPathConcurrent: %v", err)
+	}
+	counts, folders := map[string]int64{}, map[string]int64{}
+	for _, entry := range result.Entries {
+		counts[entry.Name] = entry.Files
+		folders[entry.Name] = entry.Dirs
+	}
+	if counts["docs"] != 4 || counts["single.bin"] != 1 {
+		t.Fatalf("unexpected file counts %v", counts)
+	}
+	if folders["docs"] != 2 || folders["single.bin"] != 0 {
+		t.Fatalf("unexpected folder counts %v", folders)
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// Sampling limits for the instant estimate pass.
+	estimateMaxDepth   = 3
+	estimateSampleDirs = 6
+)
+
+// scanEstimateMsg carries approximate child sizes shown while the exact scan runs.
+type scanEstimateMsg struct {
+	Path    string
+	Entries []dirEntry
+	Total   int64
+}
+
+// estimateScanCmd waits for the exact scan's cache lookup and only samples on a miss.
+func estimateScanCmd(path string, cacheHit <-chan bool) tea.Cmd {
+	return func() tea.Msg {
+		if <-cacheHit {
+			return nil
+		}
+		entries, total := estimateChildren(path)
+		return scanEstimateMsg{Path: path, Entries: entries, Total: total}
+	}
+}
+
+// estimateChildren sizes each direct child by sampling, largest first. It leaves out what
+// the exact scan does, so rows do not change when that lands.
+func estimateChildren(root string) ([]dirEntry, int64) {
+	children, err := os.ReadDir(root)
+	if err != nil {
+		return nil, 0
+	}
+
+	var entries []dirEntry
+	var total int64
+	for _, child := range children {
+		fullPath := filepath.Join(root, child.Name())
+		if child.Type()&os.ModeSymlink != 0 || isExcludedName(child.Name()) {
+			continue
+		}
+		var size int64
+		if child.IsDir() {
+			if defaultSkipDirs[child.Name()] || (root == "/" && skipSystemDirs[child.Name()]) {
+				continue
+			}
+			if scanStrategyFor(fullPath) == strategySkip {
+				entries = append(entries, dirEntry{Name: child.Name(), Path: fullPath, IsDir: true, Files: -1, Dirs: -1, Strategy: strategySkip})
+				continue
+			}
+			size = estimateDirSize(fullPath, estimateMaxDepth)
+		} else if info, err := child.Info(); err == nil {
+			size = getActualFileSize(fullPath, info)
+		}
+		if size <= 0 {
+			continue
+		}
+		total += size
+		entries = append(entries, dirEntry{Name: child.Name(), Path: fullPath, Size: size, IsDir: child.IsDir()})
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Size > entries[j].Size })
+	entries, _, _ = capEntries(entries)
+	return entries, total
+}
+
+// estimateDirSize reads a few evenly spaced subdirectories and extrapolates by fan-out.
+// Excluded names and folders set to skip count for nothing, as in the exact scan.
+func estimateDirSize(path string, depth int) int64 {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return 0
+	}
+
+	var size int64
+	var dirs []string
+	for _, entry := range entries {
+		if isExcludedName(entry.Name()) {
+			continue
+		}
+		if entry.IsDir() {
+			if dir := filepath.Join(path, entry.Name()); scanStrategyFor(dir) != strategySkip {
+				dirs = append(dirs, dir)
+			}
+			continue
+		}
+		if info, err := entry.Info(); err == nil {
+			size += getActualFileSize("", info)
+		}
+	}
+	if len(dirs) == 0 || depth <= 0 {
+		return size
+	}
+
+	sample := dirs
+	if len(dirs) > estimateSampleDirs {
+		sample = make([]string, 0, estimateSampleDirs)
+		step := float64(len(dirs)) / float64(estimateSampleDirs)
+		for i := 0; i < estimateSampleDirs; i++ {
+			sample = append(sample, dirs[int(float64(i)*step)])
+		}
+	}
+
+	var sampled int64
+	for _, dir := range sample {
+		sampled += estimateDirSize(dir, depth-1)
+	}
+	return size + sampled*int64(len(dirs))/int64(len(sample))
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEstimateDirSizeExactForSmallTrees(t *testing.T) {
+	base := t.TempDir()
+	writeFileWithSize(t, filepath.Join(base, "a.bin"), 4096)
+	writeFileWithSize(t, filepath.Join(base, "sub", "b.bin"), 8192)
+
+	got := estimateDirSize(base, estimateMaxDepth)
+	want, err := getDirectoryLogicalSizeWithExclude(base, "")
+	if err != nil {
+		t.Fatalf("logical size: %v", err)
+	}
+	if got != want {
+		t.Fatalf("expected exact estimate %d for unsampled tree, got %d", want, got)
+	}
+}
+
+func TestEstimateDirSizeExtrapolatesFanOut(t *testing.T) {
+	base := t.TempDir()
+	// Uniform shards: sampling a subset must extrapolate to the full total.
+	for i := 0; i < estimateSampleDirs*4; i++ {
+		writeFileWithSize(t, filepath.Join(base, fmt.Sprintf("shard%02d", i), "data.bin"), 4096)
+	}
+
+	got := estimateDirSize(base, estimateMaxDepth)
+	want, err := getDirectoryLogicalSizeWithExclude(base, "")
+	if err != nil {
+		t.Fatalf("logical size: %v", err)
+	}
+	if got != want {
+		t.Fatalf("expected extrapolated estimate %d, got %d", want, got)
+	}
+
+	entries, total := estimateChildren(base)
+	if len(entries) == 0 || total != want {
+		t.Fatalf("estimateChildren: %d entries, total %d (want %d)", len(entries), total, want)
+	}
+}
+
+func TestEstimateScanCmdSkipsCacheHits(t *testing.T) {
+	root := t.TempDir()
+	writeFileWithSize(t, filepath.Join(root, "a.bin"), 2048)
+
+	hit := make(chan bool, 1)
+	hit <- true
+	if msg := estimateScanCmd(root, hit)(); msg != nil {
+		t.Fatalf("expected no estimate for a cached folder, got %#v", msg)
+	}
+
+	miss := make(chan bool, 1)
+	miss <- false
+	msg, ok := estimateScanCmd(root, miss)().(scanEstimateMsg)
+	if !ok || msg.Path != root || len(msg.Entries) != 1 {
+		t.Fatalf("expected an estimate on a cache miss, got %#v", msg)
+	}
+}
+
+func TestEstimateHonorsExcludesAndStrategies(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	resetStrategiesForTest()
+	t.Cleanup(resetStrategiesForTest)
+	defer setExcludePatterns(nil, "")
+
+	root := filepath.Join(home, "data")
+	writeFileWithSize(t, filepath.Join(root, "kept", "a.bin"), 4096)
+	writeFileWithSize(t, filepath.Join(root, "kept", "Photos.photoslibrary", "db"), 1<<20)
+	writeFileWithSize(t, filepath.Join(root, "kept", "archive", "old.bin"), 1<<20)
+	writeFileWithSize(t, filepath.Join(root, "backups", "huge.bin"), 1<<20)
+	writeFileWithSize(t, filepath.Join(root, "ignored", "b.bin"), 1<<20)
+
+	var list excludeList
+	_ = list.Set("*.photoslibrary, " + filepath.Join(root, "backups"))
+	setExcludePatterns(list, "")
+	configDir, err := getConfigDir()
+	if err != nil {
+		t.Fatalf("config dir: %v", err)
+	}
+	rules := filepath.Join(root, "ignored") + " skip\n" + filepath.Join(root, "kept", "archive") + " skip\n"
+	if err := os.WriteFile(filepath.Join(configDir, strategiesFile), []byte(rules), 0o644); err != nil {
+		t.Fatalf("write rules: %v", err)
+	}
+
+	entries, total := estimateChildren(root)
+	if total >= 1<<20 {
+		t.Fatalf("excluded or skipped data estimated: total %d", total)
+	}
+	names := map[string]dirEntry{}
+	for _, entry := range entries {
+		names[entry.Name] = entry
+	}
+	// Path excludes are skip rules, so both are listed unsized like the exact scan lists them.
+	for _, name := range []string{"backups", "ignored"} {
+		if entry, ok := names[name]; !ok || entry.Size != 0 || entry.Strategy != strategySkip {
+			t.Fatalf("%s should be listed unsized, got %+v", name, entries)
+		}
+	}
+	if kept := names["kept"]; kept.Size <= 0 || kept.Size >= 1<<20 {
+		t.Fatalf("kept folder estimate %d should leave out the excluded and skipped subfolders", kept.Size)
+	}
+}
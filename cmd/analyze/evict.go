@@ -0,0 +1,63 @@
+package main
+
+import (
+	"os/exec"
+	"path/filepath"
+	"sort"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// iCloudDriveFolder holds iCloud Drive, the one cloud folder brctl can evict from.
+var iCloudDriveFolder = filepath.Join("Library", "Mobile Documents")
+
+// evictPlan is what eviction would free: items synced to iCloud Drive with local copies.
+type evictPlan struct {
+	Paths   []string
+	Size    int64 // Local bytes the copies take, given back once evicted
+	Skipped int   // Selected items outside iCloud Drive or already only in the cloud
+}
+
+type evictDoneMsg struct {
+	Evicted []string
+	Freed   int64
+	Failed  []string
+}
+
+// isICloudDrivePath reports whether path is inside iCloud Drive.
+func isICloudDrivePath(path string) bool {
+	return underCloudFolder(path, iCloudDriveFolder)
+}
+
+// planEviction picks the targets whose local copies can be evicted; entries sizes them
+// at their local size, so placeholders already count nothing.
+func planEviction(targets []string, entries map[string]dirEntry) evictPlan {
+	var plan evictPlan
+	for _, path := range targets {
+		entry := entries[path]
+		if !isICloudDrivePath(path) || entry.Dataless || entry.Size <= 0 {
+			plan.Skipped++
+			continue
+		}
+		plan.Paths = append(plan.Paths, path)
+		plan.Size += entry.Size
+	}
+	sort.Strings(plan.Paths)
+	return plan
+}
+
+// evictCmd asks iCloud to drop the local copies in plan, keeping them in the cloud.
+func evictCmd(plan evictPlan, entries map[string]dirEntry) tea.Cmd {
+	return func() tea.Msg {
+		var done evictDoneMsg
+		for _, path := range plan.Paths {
+			if err := exec.Command("brctl", "evict", path).Run(); err != nil {
+				done.Failed = append(done.Failed, path)
+				continue
+			}
+			done.Evicted = append(done.Evicted, path)
+			done.Freed += entries[path].Size
+		}
+		return done
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPlanEvictionKeepsLocalICloudDriveItems(t *testing.T) {
+	drive := "/Users/me/Library/Mobile Documents/com~apple~CloudDocs"
+	entries := map[string]dirEntry{
+		drive + "/Photos":                              {Path: drive + "/Photos", IsDir: true, Size: 4 << 30},
+		drive + "/movie.mov":                           {Path: drive + "/movie.mov", Size: 1 << 30},
+		drive + "/report.key":                          {Path: drive + "/report.key", Dataless: true},
+		"/Users/me/Library/CloudStorage/Dropbox/a.zip": {Size: 1 << 20},
+		"/Users/me/Downloads/b.dmg":                    {Size: 1 << 20},
+	}
+	var targets []string
+	for path := range entries {
+		targets = append(targets, path)
+	}
+	plan := planEviction(targets, entries)
+	if want := []string{drive + "/Photos", drive + "/movie.mov"}; !reflect.DeepEqual(plan.Paths, want) {
+		t.Fatalf("paths = %v, want %v", plan.Paths, want)
+	}
+	if plan.Size != 5<<30 || plan.Skipped != 3 {
+		t.Fatalf("plan = %+v", plan)
+	}
+}
+
+func TestIsICloudDrivePath(t *testing.T) {
+	for path, want := range map[string]bool{
+		"/Users/me/Library/Mobile Documents/com~apple~CloudDocs/a": true,
+		"/Users/me/Library/CloudStorage/Dropbox/a":                 false,
+		"/Users/me/Library/Mobile Documents Backup/a":              false,
+	} {
+		if got := isICloudDrivePath(path); got != want {
+			t.Errorf("isICloudDrivePath(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+//go:build darwin
+
+package main
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// evictTargets sizes the selection at the local sizes the listing shows.
+func (m model) evictTargets() map[string]dirEntry {
+	entries := make(map[string]dirEntry)
+	for _, entry := range m.entries {
+		entries[entry.Path] = entry
+	}
+	for _, file := range m.largeFiles {
+		entries[file.Path] = dirEntry{Path: file.Path, Size: file.Size}
+	}
+	return entries
+}
+
+// openEvict shows what evicting the selection from this Mac would free; J again evicts.
+func (m model) openEvict() (tea.Model, tea.Cmd) {
+	if m.inventory != nil {
+		m.status = fmt.Sprintf("%s is a read-only listing", m.inventory.Source)
+		return m, nil
+	}
+	plan := planEviction(m.actionTargets(), m.evictTargets())
+	if len(plan.Paths) == 0 {
+		m.status = "Select files stored in iCloud Drive that are downloaded to this Mac"
+		return m, nil
+	}
+	m.evictConfirm = &plan
+	return m, nil
+}
+
+// updateEvictConfirmKey evicts on J or Enter and cancels on anything else.
+func (m model) updateEvictConfirmKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	plan := *m.evictConfirm
+	m.evictConfirm = nil
+	if key := msg.String(); key != "J" && key != "enter" {
+		m.status = "Cancelled"
+		return m, nil
+	}
+	m.status = fmt.Sprintf("Evicting %d items from this Mac...", len(plan.Paths))
+	m.evicting = true
+	return m, tea.Batch(evictCmd(plan, m.evictTargets()), tickCmd())
+}
+
+// renderEvictConfirm is the footer line asking to confirm an eviction.
+func (m model) renderEvictConfirm() string {
+	plan := m.evictConfirm
+	skipped := ""
+	if plan.Skipped > 0 {
+		skipped = fmt.Sprintf(", %d not in iCloud Drive or not downloaded", plan.Skipped)
+	}
+	return fmt.Sprintf("%sEvict from this Mac:%s %d items, frees %s%s, iCloud keeps them  %sPress J again  |  ESC cancel%s\n",
+		colorYellow, colorReset, len(plan.Paths), humanizeBytes(plan.Size), skipped, colorGray, colorReset)
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// exactSizeMsg carries byte-precise sizes for a single path.
+type exactSizeMsg struct {
+	Path      string
+	Logical   int64
+	Allocated int64
+	Err       error
+}
+
+func exactSizeCmd(path string) tea.Cmd {
+	return func() tea.Msg {
+		logical, allocated, err := measureExactSize(path)
+		return exactSizeMsg{Path: path, Logical: logical, Allocated: allocated, Err: err}
+	}
+}
+
+// measureExactSize sums logical (st_size) and allocated (st_blocks) bytes without following symlinks.
+func measureExactSize(root string) (int64, int64, error) {
+	info, err := os.Lstat(root)
+	if err != nil {
+		return 0, 0, err
+	}
+	if !info.IsDir() {
+		return info.Size(), allocatedSize(info), nil
+	}
+
+	var logical, allocated int64
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsPermission(err) && d != nil && d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		if !d.IsDir() {
+			logical += info.Size()
+		}
+		allocated += allocatedSize(info)
+		return nil
+	})
+	return logical, allocated, err
+}
+
+func allocatedSize(info fs.FileInfo) int64 {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return info.Size()
+	}
+	return stat.Blocks * 512
+}
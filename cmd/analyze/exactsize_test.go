@@ -0,0 +1,28 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestMeasureExactSize(t *testing.T) {
+	base := t.TempDir()
+	writeFileWithSize(t, filepath.Join(base, "a.bin"), 1000)
+	writeFileWithSize(t, filepath.Join(base, "nested", "b.bin"), 2345)
+
+	logical, allocated, err := measureExactSize(base)
+	if err != nil {
+		t.Fatalf("measureExactSize error: %v", err)
+	}
+	if logical != 3345 {
+		t.Fatalf("expected logical 3345 bytes, got %d", logical)
+	}
+	if allocated <= 0 {
+		t.Fatalf("expected allocated bytes to be positive, got %d", allocated)
+	}
+
+	fileLogical, _, err := measureExactSize(filepath.Join(base, "a.bin"))
+	if err != nil || fileLogical != 1000 {
+		t.Fatalf("expected 1000 bytes for single file, got %d (err=%v)", fileLogical, err)
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// excludeEnvVar lists extra exclude globs, comma separated, like repeated --exclude flags.
+const excludeEnvVar = "MO_ANALYZE_EXCLUDE"
+
+var (
+	excludeMu    sync.RWMutex
+	excludeNames []string // Globs without a slash, matched against every entry's name
+)
+
+// excludeList collects repeated --exclude flags; each may hold several comma-separated globs.
+type excludeList []string
+
+func (l *excludeList) String() string { return strings.Join(*l, ",") }
+
+func (l *excludeList) Set(value string) error {
+	for _, pattern := range strings.Split(value, ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			*l = append(*l, pattern)
+		}
+	}
+	return nil
+}
+
+// setExcludePatterns installs the user's exclude globs. Patterns with a slash (or ~) are
+// path globs and exclude that subtree; bare names such as "*.photoslibrary" match anywhere.
+func setExcludePatterns(patterns []string, home string) {
+	var names []string
+	var rules []strategyRule
+	for _, pattern := range patterns {
+		if !strings.Contains(pattern, "/") && pattern != "~" {
+			if _, err := filepath.Match(pattern, pattern); err == nil {
+				names = append(names, pattern)
+			}
+			continue
+		}
+		if rule, ok := parseStrategyLine(pattern+" skip", home); ok {
+			rules = append(rules, rule)
+		}
+	}
+
+	excludeMu.Lock()
+	excludeNames = names
+	excludeMu.Unlock()
+
+	strategiesMu.Lock()
+	configStrategyRules = rules
+	strategiesLoaded = false
+	strategiesMu.Unlock()
+}
+
+// isExcludedName reports whether an entry called name is left out of scans and totals.
+func isExcludedName(name string) bool {
+	excludeMu.RLock()
+	defer excludeMu.RUnlock()
+	for _, pattern := range excludeNames {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// duExcludeArgs passes the name globs to du -I so folded folders honor them too.
+func duExcludeArgs() []string {
+	excludeMu.RLock()
+	defer excludeMu.RUnlock()
+	args := make([]string, 0, 2*len(excludeNames))
+	for _, pattern := range excludeNames {
+		args = append(args, "-I", pattern)
+	}
+	return args
+}
+
+// isExcludedPath reports whether path, found under root by a search that ignores the
+// scanner's rules (Spotlight), sits in an excluded folder or carries an excluded name.
+func isExcludedPath(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return false
+	}
+	dir := root
+	for _, part := range strings.Split(rel, string(filepath.Separator)) {
+		if isExcludedName(part) {
+			return true
+		}
+		dir = filepath.Join(dir, part)
+		if scanStrategyFor(dir) == strategySkip {
+			return true
+		}
+	}
+	return false
+}
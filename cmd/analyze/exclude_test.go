@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestExcludePatternsSkipScanAndTotals(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	defer setExcludePatterns(nil, "")
+
+	root := t.TempDir()
+	writeFileWithSize(t, filepath.Join(root, "keep", "a.bin"), 4096)
+	writeFileWithSize(t, filepath.Join(root, "keep", "Photos.photoslibrary", "db"), 1<<20)
+	writeFileWithSize(t, filepath.Join(root, "backups", "huge.bin"), 1<<20)
+
+	var list excludeList
+	_ = list.Set("*.photoslibrary, " + filepath.Join(root, "backups"))
+	setExcludePatterns(list, "")
+
+	var files, dirs, bytes int64
+	current := ""
+	result, err := scanPathConcurrent(context.Background(), root, &files, &dirs, &bytes, &current, nil)
+	if err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+	if result.TotalSize >= 1<<20 {
+		t.Fatalf("excluded data counted in total: %d", result.TotalSize)
+	}
+	for _, entry := range result.Entries {
+		if entry.Name == "backups" && entry.Size > 0 {
+			t.Fatalf("excluded folder has a size: %+v", entry)
+		}
+	}
+	if !isExcludedPath(root, filepath.Join(root, "keep", "Photos.photoslibrary", "db")) || isExcludedPath(root, filepath.Join(root, "keep", "a.bin")) {
+		t.Fatal("isExcludedPath should follow the name and path globs")
+	}
+}
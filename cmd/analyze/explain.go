@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// spaceRule names a well-known kind of space usage by path fragment, base name or extension.
+type spaceRule struct {
+	Label      string
+	Fragments  []string
+	Names      []string
+	Extensions []string
+}
+
+// spaceRules are checked in order, so specific locations go before generic ones.
+var spaceRules = []spaceRule{
+	{Label: "Docker's VM disk", Fragments: []string{"/Library/Containers/com.docker.docker/"}, Names: []string{"Docker.raw", "Docker.qcow2"}},
+	{Label: "iOS simulator devices and runtimes", Fragments: []string{"/Library/Developer/CoreSimulator/", "/Library/Developer/CoreSimulator"}},
+	{Label: "Xcode build products (DerivedData)", Fragments: []string{"/Library/Developer/Xcode/DerivedData"}},
+	{Label: "Xcode device support files", Fragments: []string{"/Library/Developer/Xcode/iOS DeviceSupport", "/Library/Developer/Xcode/watchOS DeviceSupport"}},
+	{Label: "Xcode archives", Fragments: []string{"/Library/Developer/Xcode/Archives"}},
+	{Label: "iPhone and iPad backups", Fragments: []string{"/Library/Application Support/MobileSync/Backup"}},
+	{Label: "the Photos library", Extensions: []string{".photoslibrary"}},
+	{Label: "Mail messages and attachments", Fragments: []string{"/Library/Mail/"}},
+	{Label: "virtual machines", Fragments: []string{"/Parallels/", "/Library/Containers/com.utmapp.UTM/"}, Extensions: []string{".vmdk", ".vdi", ".utm", ".pvm", ".vmwarevm", ".qcow2"}},
+	{Label: "app caches", Fragments: []string{"/Library/Caches/", "/.cache/"}},
+	{Label: "items in the Trash", Fragments: []string{"/.Trash/", "/.Trashes/"}},
+	{Label: "node_modules dependencies", Names: []string{"node_modules"}, Fragments: []string{"/node_modules/"}},
+	{Label: "Python virtual environments", Names: []string{".venv", "venv", "site-packages"}, Fragments: []string{"/.venv/", "/venv/", "/site-packages/"}},
+	{Label: "shared app data (Group Containers)", Fragments: []string{"/Library/Group Containers/"}},
+	{Label: "sandboxed app data (Containers)", Fragments: []string{"/Library/Containers/"}},
+	{Label: "disk images and installers", Extensions: []string{".dmg", ".pkg", ".iso", ".xip"}},
+	{Label: "videos", Extensions: []string{".mov", ".mp4", ".m4v", ".mkv", ".avi", ".fcpbundle"}},
+	{Label: "archives", Extensions: []string{".zip", ".tar", ".gz", ".tgz", ".7z", ".rar", ".sparsebundle"}},
+	{Label: "applications", Extensions: []string{".app"}},
+}
+
+// matchSpaceRule returns the first rule describing path, or nil.
+func matchSpaceRule(path string) *spaceRule {
+	name := filepath.Base(path)
+	ext := strings.ToLower(filepath.Ext(name))
+	for i := range spaceRules {
+		rule := &spaceRules[i]
+		for _, n := range rule.Names {
+			if name == n {
+				return rule
+			}
+		}
+		for _, e := range rule.Extensions {
+			if ext == e {
+				return rule
+			}
+		}
+		for _, fragment := range rule.Fragments {
+			if strings.Contains(path+"/", fragment) {
+				return rule
+			}
+		}
+	}
+	return nil
+}
+
+type explainMsg struct {
+	Path string
+	Text string
+	Err  error
+}
+
+// usageShare is one slice of the explanation, named by a rule or by the child itself.
+type usageShare struct {
+	Label   string
+	Example string
+	Size    int64
+}
+
+// explainUsage turns a scan result into a one-paragraph answer to "why is this big?".
+func explainUsage(path string, result scanResult) string {
+	total := result.TotalSize
+	if total <= 0 {
+		return fmt.Sprintf("%s is empty.", filepath.Base(path))
+	}
+
+	shares := make(map[string]*usageShare)
+	add := func(label, example string, size int64) {
+		if size <= 0 {
+			return
+		}
+		share, ok := shares[label]
+		if !ok {
+			share = &usageShare{Label: label, Example: example}
+			shares[label] = share
+		}
+		share.Size += size
+	}
+
+	// Inside a known location, children without a more specific rule inherit its label.
+	parentRule := matchSpaceRule(path)
+	for _, entry := range result.Entries {
+		rule := matchSpaceRule(entry.Path)
+		if rule == nil {
+			rule = parentRule
+		}
+		if rule != nil {
+			example := entry.Name
+			if rule == parentRule {
+				example = ""
+			}
+			add(rule.Label, example, entry.Size)
+			continue
+		}
+		// Large files deeper down can still explain an unlabelled child.
+		remaining := entry.Size
+		prefix := entry.Path + string(filepath.Separator)
+		for _, file := range result.LargeFiles {
+			if !strings.HasPrefix(file.Path, prefix) {
+				continue
+			}
+			if rule := matchSpaceRule(file.Path); rule != nil {
+				size := min(file.Size, remaining)
+				add(rule.Label, file.Name, size)
+				remaining -= size
+			}
+		}
+		add(entry.Name, "", remaining)
+	}
+
+	ordered := make([]*usageShare, 0, len(shares))
+	for _, share := range shares {
+		ordered = append(ordered, share)
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		if ordered[i].Size != ordered[j].Size {
+			return ordered[i].Size > ordered[j].Size
+		}
+		return ordered[i].Label < ordered[j].Label
+	})
+	if len(ordered) == 0 {
+		return fmt.Sprintf("%s holds %s with no dominant item.", filepath.Base(path), humanizeBytes(total))
+	}
+
+	describe := func(share *usageShare) string {
+		text := sanitizeName(share.Label)
+		if share.Example != "" && share.Example != share.Label {
+			text += " (" + sanitizeName(share.Example) + ")"
+		}
+		return text
+	}
+	percent := func(size int64) int {
+		return int(float64(size) * 100 / float64(total))
+	}
+
+	top := ordered[0]
+	var b strings.Builder
+	if percent(top.Size) >= 50 {
+		fmt.Fprintf(&b, "%d%% of %s is %s.", percent(top.Size), humanizeBytes(total), describe(top))
+	} else {
+		fmt.Fprintf(&b, "%s is spread out; the biggest part is %s at %d%%.", humanizeBytes(total), describe(top), percent(top.Size))
+	}
+	var rest []string
+	for _, share := range ordered[1:min(len(ordered), 3)] {
+		if p := percent(share.Size); p >= 5 {
+			rest = append(rest, fmt.Sprintf("%s %d%%", describe(share), p))
+		}
+	}
+	if len(rest) > 0 {
+		fmt.Fprintf(&b, " Next: %s.", strings.Join(rest, ", "))
+	}
+	if len(result.LargeFiles) > 0 {
+		biggest := result.LargeFiles[0]
+		for _, file := range result.LargeFiles[1:] {
+			if file.Size > biggest.Size {
+				biggest = file
+			}
+		}
+		fmt.Fprintf(&b, " Largest single file: %s (%s).", sanitizeName(biggest.Name), humanizeBytes(biggest.Size))
+	}
+	return b.String()
+}
+
+// explainCmd scans path when no cached listing is available and explains the result.
+func explainCmd(path string, cached *scanResult, inventory *inventoryTree) tea.Cmd {
+	return func() tea.Msg {
+		var result scanResult
+		var err error
+		switch {
+		case cached != nil:
+			result = *cached
+		case inventory != nil:
+			result, err = inventory.scanResultFor(path)
+		default:
+			var files, dirs, bytes int64
+			var current string
+			result, err = scanPathConcurrent(context.Background(), path, &files, &dirs, &bytes, &current, nil)
+		}
+		if err != nil {
+			return explainMsg{Path: path, Err: err}
+		}
+		text := explainUsage(path, result)
+		if note := autoFoldNote(path); note != "" {
+			text += " " + note
+		}
+		return explainMsg{Path: path, Text: text}
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMatchSpaceRule(t *testing.T) {
+	cases := map[string]string{
+		"/Users/a/Library/Containers/com.docker.docker/Data/vms/0/Docker.raw": "Docker's VM disk",
+		"/Users/a/Library/Developer/CoreSimulator/Devices":                    "iOS simulator devices and runtimes",
+		"/Users/a/Pictures/Photos Library.photoslibrary":                      "the Photos library",
+		"/Users/a/Projects/web/node_modules":                                  "node_modules dependencies",
+		"/Users/a/Library/Containers/com.apple.mail":                          "sandboxed app data (Containers)",
+	}
+	for path, want := range cases {
+		rule := matchSpaceRule(path)
+		if rule == nil || rule.Label != want {
+			t.Fatalf("%s: expected %q, got %+v", path, want, rule)
+		}
+	}
+	if rule := matchSpaceRule("/Users/a/Documents/notes"); rule != nil {
+		t.Fatalf("expected no rule for plain documents, got %q", rule.Label)
+	}
+}
+
+func TestExplainUsageNamesDominantCulprit(t *testing.T) {
+	tree := demoInventory()
+	path := filepath.Join(demoRoot, "Library", "Containers")
+	result, err := tree.scanResultFor(path)
+	if err != nil {
+		t.Fatalf("scanResultFor: %v", err)
+	}
+	text := explainUsage(path, result)
+	if !strings.Contains(text, "Docker's VM disk") || !strings.HasPrefix(text, "100%") {
+		t.Fatalf("expected Docker to explain Containers, got %q", text)
+	}
+}
+
+func TestExplainUsageUsesLargeFilesInsidePlainFolders(t *testing.T) {
+	path := "/Users/a/Work"
+	result := scanResult{
+		TotalSize: 1000,
+		Entries: []dirEntry{
+			{Name: "Client", Path: "/Users/a/Work/Client", Size: 900, IsDir: true},
+			{Name: "Notes", Path: "/Users/a/Work/Notes", Size: 100, IsDir: true},
+		},
+		LargeFiles: []fileEntry{{Name: "shoot.mov", Path: "/Users/a/Work/Client/raw/shoot.mov", Size: 800}},
+	}
+	text := explainUsage(path, result)
+	if !strings.HasPrefix(text, "80% of") || !strings.Contains(text, "videos (shoot.mov)") {
+		t.Fatalf("unexpected explanation %q", text)
+	}
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"syscall"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// treeNode is a fully retained scan tree; the interactive scanner only keeps top-N heaps.
+type treeNode struct {
+	Name      string
+	Apparent  int64
+	Disk      int64
+	Ino       uint64
+	IsDir     bool
+	ReadError bool
+	OtherFS   bool
+	Children  []*treeNode
+}
+
+type exportDoneMsg struct {
+	Path string
+	Err  error
+}
+
+// collectTree walks root keeping every node, staying on root's device like ncdu -x.
+func collectTree(root string) (*treeNode, error) {
+	info, err := os.Lstat(root)
+	if err != nil {
+		return nil, err
+	}
+	var dev uint64
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		dev = uint64(stat.Dev)
+	}
+	node := newTreeNode(root, info)
+	node.Name = root
+	if node.IsDir {
+		fillTree(node, root, dev)
+	}
+	return node, nil
+}
+
+func newTreeNode(name string, info os.FileInfo) *treeNode {
+	node := &treeNode{Name: filepath.Base(name), Apparent: info.Size(), Disk: allocatedSize(info), IsDir: info.IsDir()}
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		node.Ino = uint64(stat.Ino)
+	}
+	return node
+}
+
+func fillTree(node *treeNode, dir string, dev uint64) {
+	children, err := os.ReadDir(dir)
+	if err != nil {
+		node.ReadError = true
+		return
+	}
+	for _, child := range children {
+		path := filepath.Join(dir, child.Name())
+		info, err := os.Lstat(path)
+		if err != nil {
+			continue
+		}
+		childNode := newTreeNode(path, info)
+		node.Children = append(node.Children, childNode)
+		if !childNode.IsDir {
+			continue
+		}
+		if stat, ok := info.Sys().(*syscall.Stat_t); ok && dev != 0 && uint64(stat.Dev) != dev {
+			childNode.OtherFS = true
+			continue
+		}
+		fillTree(childNode, path, dev)
+	}
+}
+
+// treeFromInventory converts a listing (SSH, agent, demo) so it can be exported too.
+func treeFromInventory(node *inventoryNode, root bool) *treeNode {
+	out := &treeNode{Name: node.Name, Apparent: max(node.Size, 0), Disk: max(node.Size, 0), IsDir: node.IsDir}
+	if root {
+		out.Name = node.Path
+	}
+	if node.IsDir {
+		// Directory totals are rolled up by ncdu; only own size belongs here.
+		out.Apparent, out.Disk = 0, 0
+		names := make([]string, 0, len(node.Children))
+		for name := range node.Children {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			out.Children = append(out.Children, treeFromInventory(node.Children[name], false))
+		}
+	}
+	return out
+}
+
+// writeNcduExport emits ncdu's JSON dump format (major 1, minor 2).
+func writeNcduExport(w io.Writer, tree *treeNode) error {
+	out := bufio.NewWriter(w)
+	header, _ := json.Marshal(map[string]any{
+		"progname":  "mole",
+		"progver":   "analyze",
+		"timestamp": time.Now().Unix(),
+	})
+	fmt.Fprintf(out, "[1,2,%s,\n", header)
+	if err := writeNcduNode(out, tree, 0); err != nil {
+		return err
+	}
+	out.WriteString("]\n")
+	return out.Flush()
+}
+
+func writeNcduNode(out *bufio.Writer, node *treeNode, depth int) error {
+	name := node.Name
+	if activeRedactor != nil && depth > activeRedactor.depth {
+		name = activeRedactor.name(name)
+	}
+	info := map[string]any{"name": name, "asize": node.Apparent, "dsize": node.Disk}
+	if node.Ino != 0 {
+		info["ino"] = node.Ino
+	}
+	if node.ReadError {
+		info["read_error"] = true
+	}
+	if node.OtherFS {
+		info["excluded"] = "othfs"
+	}
+	data, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+	if !node.IsDir {
+		_, err = out.Write(data)
+		return err
+	}
+	out.WriteByte('[')
+	out.Write(data)
+	for _, child := range node.Children {
+		out.WriteString(",\n")
+		if err := writeNcduNode(out, child, depth+1); err != nil {
+			return err
+		}
+	}
+	_, err = out.WriteString("]")
+	return err
+}
+
+// exportNcduFile writes root (or the inventory subtree) to dest; "-" means stdout.
+func exportNcduFile(root string, inventory *inventoryTree, dest string) error {
+	var tree *treeNode
+	if inventory != nil {
+		node, ok := inventory.lookup(root)
+		if !ok {
+			return fmt.Errorf("%s is not in the listing", root)
+		}
+		tree = treeFromInventory(node, true)
+	} else {
+		var err error
+		if tree, err = collectTree(root); err != nil {
+			return err
+		}
+	}
+	if dest == "-" {
+		return writeNcduExport(os.Stdout, tree)
+	}
+	if err := checkWritableSpace(dest); err != nil {
+		return fmt.Errorf("%v; export to an external volume, or to - to stream it", err)
+	}
+	return writeExportFile(dest, 0666, func(w io.Writer) error { return writeNcduExport(w, tree) })
+}
+
+// defaultExportPath picks ~/Downloads/mole-<name>-<time>.json for exports started from the UI.
+func defaultExportPath(root string) (string, error) {
+	name := filepath.Base(root)
+	if name == "/" || name == "." {
+		name = "root"
+	}
+	return exportFilePath(name, "json")
+}
+
+// exportFilePath is ~/Downloads/mole-<name>-<time>.<ext>, or the temp folder without a home.
+// When that volume is critically low on space, the export goes to the top of the first
+// external volume with room instead.
+func exportFilePath(name, ext string) (string, error) {
+	dir, err := os.UserHomeDir()
+	if err == nil {
+		if downloads := filepath.Join(dir, "Downloads"); isDirectory(downloads) {
+			dir = downloads
+		}
+	} else {
+		dir = os.TempDir()
+	}
+	dir, err = pickExportDir(append([]string{dir}, checkedVolumes()[1:]...))
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, fmt.Sprintf("mole-%s-%s.%s", name, time.Now().Format("20060102-150405"), ext)), nil
+}
+
+func isDirectory(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+func exportNcduCmd(root string, inventory *inventoryTree) tea.Cmd {
+	return func() tea.Msg {
+		dest, err := defaultExportPath(root)
+		if err != nil {
+			return exportDoneMsg{Err: err}
+		}
+		return exportDoneMsg{Path: dest, Err: exportNcduFile(root, inventory, dest)}
+	}
+}
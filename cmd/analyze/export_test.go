@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+)
+
+func decodeNcdu(t *testing.T, data []byte) []any {
+	t.Helper()
+	var dump []any
+	if err := json.Unmarshal(data, &dump); err != nil {
+		t.Fatalf("export is not valid JSON: %v\n%s", err, data)
+	}
+	if len(dump) != 4 || dump[0] != float64(1) || dump[1] != float64(2) {
+		t.Fatalf("unexpected ncdu header: %v", dump[:min(len(dump), 3)])
+	}
+	root, ok := dump[3].([]any)
+	if !ok {
+		t.Fatalf("expected root directory array, got %T", dump[3])
+	}
+	return root
+}
+
+func TestNcduExportRetainsFullTree(t *testing.T) {
+	root := t.TempDir()
+	writeFileWithSize(t, filepath.Join(root, "a", "b", "deep.bin"), 3000)
+	writeFileWithSize(t, filepath.Join(root, "top.txt"), 10)
+
+	tree, err := collectTree(root)
+	if err != nil {
+		t.Fatalf("collectTree: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := writeNcduExport(&buf, tree); err != nil {
+		t.Fatalf("writeNcduExport: %v", err)
+	}
+
+	dir := decodeNcdu(t, buf.Bytes())
+	if info := dir[0].(map[string]any); info["name"] != root {
+		t.Fatalf("expected root name %s, got %v", root, info["name"])
+	}
+	// Walk a -> b -> deep.bin to prove nothing below the top level was dropped.
+	var found map[string]any
+	for _, name := range []string{"a", "b"} {
+		var next []any
+		for _, child := range dir[1:] {
+			if sub, ok := child.([]any); ok && sub[0].(map[string]any)["name"] == name {
+				next = sub
+			}
+		}
+		if next == nil {
+			t.Fatalf("missing directory %s", name)
+		}
+		dir = next
+	}
+	for _, child := range dir[1:] {
+		if file, ok := child.(map[string]any); ok && file["name"] == "deep.bin" {
+			found = file
+		}
+	}
+	if found == nil || found["asize"] != float64(3000) {
+		t.Fatalf("expected deep.bin with asize 3000, got %v", found)
+	}
+}
+
+func TestNcduExportFromInventory(t *testing.T) {
+	tree := demoInventory()
+	var buf bytes.Buffer
+	if err := writeNcduExport(&buf, treeFromInventory(tree.Root, true)); err != nil {
+		t.Fatalf("writeNcduExport: %v", err)
+	}
+	dir := decodeNcdu(t, buf.Bytes())
+	if info := dir[0].(map[string]any); info["name"] != demoRoot {
+		t.Fatalf("expected demo root, got %v", info["name"])
+	}
+	if len(dir) < 3 {
+		t.Fatalf("expected demo children in export")
+	}
+}
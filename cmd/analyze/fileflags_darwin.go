@@ -0,0 +1,30 @@
+//go:build darwin
+
+package main
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+// fileFlags returns the BSD file flags of path (see chflags(1)) without following symlinks.
+func fileFlags(path string) (uint32, bool) {
+	var st syscall.Stat_t
+	if err := syscall.Lstat(path, &st); err != nil {
+		return 0, false
+	}
+	return st.Flags, true
+}
+
+// infoFlags returns the BSD file flags already read into info.
+func infoFlags(info fs.FileInfo) uint32 {
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		return st.Flags
+	}
+	return 0
+}
+
+// setFileFlags replaces the BSD file flags of path.
+func setFileFlags(path string, flags uint32) error {
+	return syscall.Chflags(path, int(flags))
+}
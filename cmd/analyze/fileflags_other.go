@@ -0,0 +1,21 @@
+//go:build !darwin
+
+package main
+
+import (
+	"errors"
+	"io/fs"
+)
+
+// fileFlags is only meaningful where chflags(2) exists.
+func fileFlags(string) (uint32, bool) {
+	return 0, false
+}
+
+func infoFlags(fs.FileInfo) uint32 {
+	return 0
+}
+
+func setFileFlags(string, uint32) error {
+	return errors.ErrUnsupported
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"os/user"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// fileDetails is what the Tab info panel shows about one entry.
+type fileDetails struct {
+	Path      string
+	IsDir     bool
+	Apparent  int64 // Logical bytes
+	Allocated int64 // Bytes on disk
+	Created   time.Time
+	Modified  time.Time
+	Accessed  time.Time
+	Owner     string
+	Mode      fs.FileMode
+	Xattrs    int // -1 when they cannot be listed
+	SIP       bool
+}
+
+// readFileDetails stats path without following symlinks. Folders take their sizes from
+// the scan, since adding them up again would mean another walk.
+func readFileDetails(entry dirEntry) (fileDetails, error) {
+	info, err := os.Lstat(entry.Path)
+	if err != nil {
+		return fileDetails{}, err
+	}
+	d := fileDetails{
+		Path:     entry.Path,
+		IsDir:    info.IsDir(),
+		Created:  birthTime(info),
+		Modified: info.ModTime(),
+		Accessed: getLastAccessTimeFromInfo(info),
+		Mode:     info.Mode(),
+		Xattrs:   -1,
+	}
+	if d.IsDir {
+		d.Apparent, d.Allocated = entry.apparentSize(), entry.Size
+	} else {
+		d.Apparent, d.Allocated = info.Size(), getActualFileSize(entry.Path, info)
+	}
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		d.Owner = ownerName(st.Uid, st.Gid)
+	}
+	if names, err := xattrNames(entry.Path); err == nil {
+		d.Xattrs = len(names)
+	}
+	flags, _ := fileFlags(entry.Path)
+	d.SIP = flags&flagRestricted != 0 || isSIPProtected(entry.Path)
+	return d, nil
+}
+
+// ownerName prints a user and group by name, falling back to their IDs.
+func ownerName(uid, gid uint32) string {
+	owner := strconv.FormatUint(uint64(uid), 10)
+	if u, err := user.LookupId(owner); err == nil {
+		owner = u.Username
+	}
+	group := strconv.FormatUint(uint64(gid), 10)
+	if g, err := user.LookupGroupId(group); err == nil {
+		group = g.Name
+	}
+	return owner + ":" + group
+}
+
+// lines formats the details as label and value pairs.
+func (d fileDetails) lines() [][2]string {
+	stamp := func(t time.Time) string {
+		if t.IsZero() {
+			return "unknown"
+		}
+		return t.Format("2006-01-02 15:04:05")
+	}
+	xattrs := "unknown"
+	if d.Xattrs >= 0 {
+		xattrs = strconv.Itoa(d.Xattrs)
+	}
+	sip := "no"
+	if d.SIP {
+		sip = "yes, macOS will not let it be removed"
+	}
+	return [][2]string{
+		{"Path", sanitizeName(d.Path)},
+		{"Size", fmt.Sprintf("%s on disk, %s logical", humanizeBytes(d.Allocated), humanizeBytes(d.Apparent))},
+		{"Created", stamp(d.Created)},
+		{"Modified", stamp(d.Modified)},
+		{"Accessed", stamp(d.Accessed)},
+		{"Owner", d.Owner},
+		{"Permissions", fmt.Sprintf("%s (%04o)", d.Mode.String(), d.Mode.Perm())},
+		{"Extended attrs", xattrs},
+		{"SIP protected", sip},
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadFileDetails(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "report.txt")
+	if err := os.WriteFile(path, []byte(strings.Repeat("x", 5000)), 0o640); err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := readFileDetails(dirEntry{Name: "report.txt", Path: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.IsDir || d.Apparent != 5000 || d.Mode.Perm() != 0o640 || d.SIP {
+		t.Fatalf("details = %+v", d)
+	}
+	if d.Modified.IsZero() || d.Owner == "" {
+		t.Errorf("missing modified time or owner: %+v", d)
+	}
+
+	lines := d.lines()
+	if len(lines) != 9 || lines[0][1] != path {
+		t.Fatalf("lines = %v", lines)
+	}
+	if got := lines[6][1]; got != "-rw-r----- (0640)" {
+		t.Errorf("permissions = %q", got)
+	}
+
+	folder, err := readFileDetails(dirEntry{Name: "d", Path: dir, Size: 8192, IsDir: true})
+	if err != nil || !folder.IsDir || folder.Allocated != 8192 {
+		t.Errorf("folder details = %+v, %v", folder, err)
+	}
+
+	if _, err := readFileDetails(dirEntry{Path: filepath.Join(dir, "gone")}); err == nil {
+		t.Error("missing path should fail")
+	}
+}
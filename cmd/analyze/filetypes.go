@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+)
+
+// allFileTypes lists every file in the large files view, including the source, text and
+// database files skipExtensions normally hides. V toggles it for the session.
+var allFileTypes atomic.Bool
+
+// normalizeExtension turns "SQL", "sql" or ".sql" into ".sql".
+func normalizeExtension(ext string) (string, error) {
+	ext = strings.ToLower(strings.TrimSpace(ext))
+	if !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	if ext == "." || strings.ContainsAny(ext[1:], "./ ") {
+		return "", fmt.Errorf("%q is not a file extension", ext)
+	}
+	return ext, nil
+}
+
+// applyFileTypeLists lets config.toml list file types the large files view normally
+// hides (include) and hide ones it normally lists (exclude). Both hold normalized extensions.
+func applyFileTypeLists(include, exclude []string) {
+	for _, ext := range include {
+		delete(skipExtensions, ext)
+	}
+	for _, ext := range exclude {
+		skipExtensions[ext] = true
+	}
+}
+
+func shouldSkipFileForLargeTracking(path string) bool {
+	if allFileTypes.Load() {
+		return false
+	}
+	ext := strings.ToLower(filepath.Ext(path))
+	return skipExtensions[ext]
+}
+
+// fileTypesLabel names the large files view's current file type mode.
+func fileTypesLabel() string {
+	if allFileTypes.Load() {
+		return "all"
+	}
+	return "usual"
+}
+
+// filterFileTypes drops the files the large files view hides, leaving files untouched.
+func filterFileTypes(files []fileEntry) []fileEntry {
+	kept := make([]fileEntry, 0, len(files))
+	for _, file := range files {
+		if !shouldSkipFileForLargeTracking(file.Path) {
+			kept = append(kept, file)
+		}
+	}
+	return kept
+}
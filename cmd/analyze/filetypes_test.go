@@ -0,0 +1,46 @@
+package main
+
+import (
+	"maps"
+	"reflect"
+	"testing"
+)
+
+func TestFileTypeListsFromConfig(t *testing.T) {
+	saved := maps.Clone(skipExtensions)
+	defer func() { skipExtensions = saved }()
+
+	values, err := parseConfigTOML("large_files_include = [\"SQL\", \".csv\"]\nlarge_files_exclude = [\"log\"]\n")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	cfg, err := decodeUserConfig(values)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !reflect.DeepEqual(cfg.LargeFilesInclude, []string{".sql", ".csv"}) || !reflect.DeepEqual(cfg.LargeFilesExclude, []string{".log"}) {
+		t.Fatalf("extensions not normalized: %+v", cfg)
+	}
+	applyFileTypeLists(cfg.LargeFilesInclude, cfg.LargeFilesExclude)
+	if shouldSkipFileForLargeTracking("/data/dump.SQL") {
+		t.Error("included .sql files are still hidden")
+	}
+	if !shouldSkipFileForLargeTracking("/var/log/huge.log") {
+		t.Error("excluded .log files are still listed")
+	}
+	if !shouldSkipFileForLargeTracking("/src/main.go") {
+		t.Error("built-in skipped types should stay hidden")
+	}
+}
+
+func TestAllFileTypesShowsHiddenFiles(t *testing.T) {
+	defer allFileTypes.Store(false)
+	files := []fileEntry{{Path: "/src/main.go"}, {Path: "/movies/a.mov"}}
+	if got := filterFileTypes(files); len(got) != 1 || got[0].Path != "/movies/a.mov" {
+		t.Fatalf("filterFileTypes = %+v", got)
+	}
+	allFileTypes.Store(true)
+	if got := filterFileTypes(files); len(got) != 2 || fileTypesLabel() != "all" {
+		t.Fatalf("all file types should keep every file, got %+v", got)
+	}
+}
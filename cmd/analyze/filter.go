@@ -0,0 +1,276 @@
+//go:build darwin
+
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// listFilter narrows what the lists show while keeping the full scan underneath.
+type listFilter struct {
+	Query   string
+	Editing bool
+
+	entries    []dirEntry // Unfiltered listing
+	largeFiles []fileEntry
+}
+
+func isGlobQuery(query string) bool {
+	return strings.ContainsAny(query, "*?[")
+}
+
+// matchFilter matches case-insensitively: globs against the whole name, plain text as a substring.
+func matchFilter(query, name string) bool {
+	if query == "" {
+		return true
+	}
+	query, name = strings.ToLower(query), strings.ToLower(name)
+	if isGlobQuery(query) {
+		ok, err := filepath.Match(query, name)
+		return err == nil && ok
+	}
+	return strings.Contains(name, query)
+}
+
+// highlightMatch colors the first substring match; globs are not highlighted.
+func highlightMatch(text, query string) string {
+	if query == "" || isGlobQuery(query) {
+		return text
+	}
+	idx := strings.Index(strings.ToLower(text), strings.ToLower(query))
+	if idx < 0 {
+		return text
+	}
+	end := idx + len(query)
+	return text[:idx] + colorYellow + text[idx:end] + colorReset + text[end:]
+}
+
+// filterExpr is a "/" filter query: name words plus the size and age terms saved
+// searches use, as in "*.log over 100MB" or "node_modules older:90d".
+type filterExpr struct {
+	Pattern   string
+	MinSize   int64
+	OlderThan time.Duration
+}
+
+// parseFilterExpr reads a filter query as it is typed. A term that does not parse yet,
+// such as a half-typed ">10G", is ignored rather than matched against names.
+func parseFilterExpr(query string) filterExpr {
+	var expr filterExpr
+	var words []string
+	for _, field := range joinFilterTerms(strings.Fields(query)) {
+		switch {
+		case strings.HasPrefix(field, ">"), strings.HasPrefix(field, "over:"):
+			value := strings.TrimPrefix(strings.TrimPrefix(field, ">"), "over:")
+			if size, err := parseByteSize(value); err == nil {
+				expr.MinSize = size
+			}
+		case strings.HasPrefix(field, "older:"):
+			if age, err := parseSearchAge(strings.TrimPrefix(field, "older:")); err == nil {
+				expr.OlderThan = age
+			}
+		default:
+			words = append(words, field)
+		}
+	}
+	expr.Pattern = strings.Join(words, " ")
+	return expr
+}
+
+// matches reports whether an item passes every term; touched is its last use.
+func (f filterExpr) matches(name string, size int64, touched, now time.Time) bool {
+	if f.MinSize > 0 && size < f.MinSize {
+		return false
+	}
+	if f.OlderThan > 0 && (touched.IsZero() || now.Sub(touched) < f.OlderThan) {
+		return false
+	}
+	return matchFilter(f.Pattern, name)
+}
+
+func (m model) filterQuery() string {
+	if m.filter == nil {
+		return ""
+	}
+	return m.filter.Query
+}
+
+// filterPattern is the name part of the filter, the part rows highlight.
+func (m model) filterPattern() string {
+	return parseFilterExpr(m.filterQuery()).Pattern
+}
+
+// openFilter starts editing, capturing the current lists as the unfiltered layer.
+func (m *model) openFilter() {
+	if m.filter == nil {
+		m.filter = &listFilter{
+			entries:    cloneDirEntries(m.entries),
+			largeFiles: cloneFileEntries(m.largeFiles),
+		}
+	}
+	m.filter.Editing = true
+}
+
+// clearFilter restores the unfiltered lists, leaving focus mode too.
+func (m *model) clearFilter() {
+	if m.filter == nil {
+		return
+	}
+	m.focusMode = false
+	// The sort may have changed while filtered.
+	entries, largeFiles := m.filter.entries, m.filter.largeFiles
+	m.filter = nil
+	m.entries = m.orderEntries(entries)
+	m.largeFiles = sortLargeFiles(largeFiles, m.largeSort)
+	m.clampEntrySelection()
+	m.clampLargeSelection()
+}
+
+// refilter captures fresh scan results beneath an active filter and narrows them again.
+func (m *model) refilter(entries []dirEntry, largeFiles []fileEntry) {
+	if m.filter == nil {
+		if !m.focusMode {
+			return
+		}
+		m.filter = &listFilter{}
+	}
+	m.filter.entries = entries
+	m.filter.largeFiles = largeFiles
+	m.applyFilter()
+}
+
+func (m *model) applyFilter() {
+	if m.filter == nil {
+		return
+	}
+	expr, now := parseFilterExpr(m.filter.Query), time.Now()
+	focus := m.focusMode && !m.inOverviewMode()
+	ctx := focusContext{Duplicates: m.duplicates, Now: now}
+	m.entries = make([]dirEntry, 0, len(m.filter.entries))
+	for _, entry := range m.filter.entries {
+		touched := entry.LastAccess
+		if touched.IsZero() && expr.OlderThan > 0 {
+			touched = getLastAccessTime(entry.Path)
+		}
+		if focus && len(entryActions(entry.Path, entry.IsDir, entry.Size, touched, ctx)) == 0 {
+			continue
+		}
+		if expr.matches(entry.Name, entry.Size, touched, now) {
+			m.entries = append(m.entries, entry)
+		}
+	}
+	m.largeFiles = make([]fileEntry, 0, len(m.filter.largeFiles))
+	for _, file := range m.filter.largeFiles {
+		if focus && len(entryActions(file.Path, false, file.Size, file.ModTime, ctx)) == 0 {
+			continue
+		}
+		if expr.matches(file.Name, file.Size, file.ModTime, now) {
+			m.largeFiles = append(m.largeFiles, file)
+		}
+	}
+	m.clampEntrySelection()
+	m.clampLargeSelection()
+}
+
+// toggleFocus shows only entries with an action available, or everything again.
+func (m *model) toggleFocus() {
+	if m.focusMode {
+		m.focusMode = false
+		if m.filter != nil && m.filter.Query == "" && !m.filter.Editing {
+			m.clearFilter()
+		} else {
+			m.applyFilter()
+		}
+		m.status = "Focus mode off"
+		return
+	}
+	m.focusMode = true
+	m.refocus()
+	m.status = "Focus mode: only cleanable, stale, duplicate or oversized items"
+}
+
+// refocus puts focus mode's filter over lists shown without one, as after going back.
+func (m *model) refocus() {
+	if !m.focusMode {
+		return
+	}
+	if m.filter == nil {
+		m.filter = &listFilter{
+			entries:    cloneDirEntries(m.entries),
+			largeFiles: cloneFileEntries(m.largeFiles),
+		}
+	}
+	m.applyFilter()
+}
+
+// updateFilterKey handles keys while the filter prompt is open.
+func (m model) updateFilterKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.clearFilter()
+		m.status = "Filter cleared"
+	case tea.KeyEnter:
+		m.filter.Editing = false
+		if m.filter.Query == "" {
+			m.clearFilter()
+		}
+	case tea.KeyBackspace:
+		if runes := []rune(m.filter.Query); len(runes) > 0 {
+			m.filter.Query = string(runes[:len(runes)-1])
+			m.applyFilter()
+		}
+	case tea.KeyCtrlC:
+		return m.quit()
+	case tea.KeyCtrlS:
+		m.filter.Editing = false
+		return m.saveFilterAsSearch()
+	case tea.KeyCtrlA:
+		m.filter.Editing = false
+		m.selectFilterMatches()
+	case tea.KeySpace:
+		m.filter.Query += " "
+		m.applyFilter()
+	case tea.KeyRunes:
+		m.filter.Query += string(msg.Runes)
+		m.applyFilter()
+	}
+	return m, nil
+}
+
+// selectFilterMatches marks every row the filter lets through, adding to marks already
+// made, so a delete or stage acts on the whole filtered set.
+func (m *model) selectFilterMatches() {
+	if m.filter == nil || m.filter.Query == "" {
+		m.status = "Filter with / first, e.g. *.log over 100MB"
+		return
+	}
+	var count int
+	var total int64
+	if m.showLargeFiles {
+		if m.largeMultiSelected == nil {
+			m.largeMultiSelected = make(map[string]bool)
+		}
+		for _, file := range m.largeFiles {
+			m.largeMultiSelected[file.Path] = true
+			count, total = count+1, total+file.Size
+		}
+	} else {
+		if m.multiSelected == nil {
+			m.multiSelected = make(map[string]bool)
+		}
+		for _, entry := range m.entries {
+			m.multiSelected[entry.Path] = true
+			count, total = count+1, total+max(entry.Size, 0)
+		}
+	}
+	if count == 0 {
+		m.status = "Nothing matches the filter"
+		return
+	}
+	m.status = fmt.Sprintf("Selected %d matches (%s)", count, humanizeBytes(total))
+}
@@ -0,0 +1,135 @@
+//go:build darwin
+
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestMatchFilter(t *testing.T) {
+	cases := []struct {
+		query, name string
+		want        bool
+	}{
+		{"", "anything", true},
+		{"cache", "Caches", true},
+		{"cache", "Documents", false},
+		{"*.dmg", "Installer.DMG", true},
+		{"*.dmg", "Installer.dmg.part", false},
+		{"log?", "logs", true},
+	}
+	for _, tc := range cases {
+		if got := matchFilter(tc.query, tc.name); got != tc.want {
+			t.Fatalf("matchFilter(%q, %q) = %v, want %v", tc.query, tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestHighlightMatchWrapsFirstHit(t *testing.T) {
+	got := highlightMatch("Node Modules  ", "mod")
+	if !strings.Contains(got, colorYellow+"Mod"+colorReset) {
+		t.Fatalf("expected highlighted match, got %q", got)
+	}
+	if highlightMatch("a*b", "*") != "a*b" {
+		t.Fatal("expected globs to be left unhighlighted")
+	}
+}
+
+func TestFilterLayerKeepsUnderlyingEntries(t *testing.T) {
+	m := model{
+		entries:    []dirEntry{{Name: "Caches", Path: "/x/Caches", Size: 30}, {Name: "Documents", Path: "/x/Documents", Size: 20}},
+		largeFiles: []fileEntry{{Name: "cache.db", Path: "/x/Caches/cache.db", Size: 10}},
+	}
+	m.openFilter()
+	for _, r := range "cache" {
+		next, _ := m.updateFilterKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = next.(model)
+	}
+	if len(m.entries) != 1 || m.entries[0].Name != "Caches" || len(m.largeFiles) != 1 {
+		t.Fatalf("unexpected filtered lists %+v %+v", m.entries, m.largeFiles)
+	}
+	if snapshot := snapshotFromModel(m); len(snapshot.Entries) != 2 {
+		t.Fatalf("expected snapshots to keep the full listing, got %d entries", len(snapshot.Entries))
+	}
+
+	next, _ := m.updateFilterKey(tea.KeyMsg{Type: tea.KeyEsc})
+	m = next.(model)
+	if m.filter != nil || len(m.entries) != 2 {
+		t.Fatalf("expected Esc to restore the listing, got %+v", m.entries)
+	}
+}
+
+func TestParseFilterExpr(t *testing.T) {
+	expr := parseFilterExpr("*.log over 100MB older:90d")
+	if expr.Pattern != "*.log" || expr.MinSize != 100<<20 || expr.OlderThan != 90*24*time.Hour {
+		t.Fatalf("parseFilterExpr = %+v", expr)
+	}
+	// A half-typed size narrows nothing yet instead of matching no names.
+	if expr := parseFilterExpr("cache >1"); expr.Pattern != "cache" {
+		t.Fatalf("size terms should never become name words: %+v", expr)
+	}
+	now := time.Now()
+	if !expr.matches("app.log", 200<<20, now.Add(-100*24*time.Hour), now) ||
+		expr.matches("app.log", 1<<20, now.Add(-100*24*time.Hour), now) ||
+		expr.matches("app.log", 200<<20, now, now) ||
+		expr.matches("app.txt", 200<<20, now.Add(-100*24*time.Hour), now) {
+		t.Fatal("every term should have to match")
+	}
+}
+
+func TestSelectFilterMatches(t *testing.T) {
+	m := model{
+		entries: []dirEntry{
+			{Name: "a.log", Path: "/x/a.log", Size: 200 << 20},
+			{Name: "b.log", Path: "/x/b.log", Size: 1 << 20},
+			{Name: "c.txt", Path: "/x/c.txt", Size: 300 << 20},
+		},
+		multiSelected: map[string]bool{"/x/c.txt": true},
+	}
+	m.selectFilterMatches()
+	if len(m.multiSelected) != 1 || !strings.Contains(m.status, "Filter with /") {
+		t.Fatalf("without a filter nothing should be selected: %v %q", m.multiSelected, m.status)
+	}
+	m.openFilter()
+	for _, r := range "*.log over 100MB" {
+		next, _ := m.updateFilterKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = next.(model)
+	}
+	next, _ := m.updateFilterKey(tea.KeyMsg{Type: tea.KeyCtrlA})
+	m = next.(model)
+	if m.filter.Editing || !m.multiSelected["/x/a.log"] || m.multiSelected["/x/b.log"] || !m.multiSelected["/x/c.txt"] {
+		t.Fatalf("ctrl+a should add the filtered rows to the selection: %v", m.multiSelected)
+	}
+	if !strings.HasPrefix(m.status, "Selected 1 matches") {
+		t.Fatalf("unexpected status %q", m.status)
+	}
+}
+
+func TestFocusModeKeepsActionableEntriesAcrossRescans(t *testing.T) {
+	m := model{
+		path: "/Users/me/code",
+		entries: []dirEntry{
+			{Name: "node_modules", Path: "/Users/me/code/node_modules", Size: 300 << 20, IsDir: true},
+			{Name: "src", Path: "/Users/me/code/src", Size: 40 << 20, IsDir: true},
+		},
+	}
+	m.toggleFocus()
+	if len(m.entries) != 1 || m.entries[0].Name != "node_modules" {
+		t.Fatalf("focus entries = %+v", m.entries)
+	}
+
+	m.filter = nil // As after navigating elsewhere
+	m.refilter([]dirEntry{{Name: "src", Path: "/Users/me/code/src", Size: 40 << 20, IsDir: true}}, nil)
+	if m.filter == nil || len(m.entries) != 0 {
+		t.Fatalf("focus not applied to fresh results: %+v", m.entries)
+	}
+
+	m.toggleFocus()
+	if m.focusMode || m.filter != nil || len(m.entries) != 1 {
+		t.Fatalf("focus off left focusMode=%v filter=%v entries=%+v", m.focusMode, m.filter, m.entries)
+	}
+}
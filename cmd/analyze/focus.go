@@ -0,0 +1,22 @@
+package main
+
+// backgroundPoolLimit is the scan worker cap while the terminal is in the background.
+const backgroundPoolLimit = 2
+
+// throttleForBackground slows scanning to a trickle while the terminal is not focused
+// and returns the worker cap to restore on focus.
+func throttleForBackground() int {
+	previous := scanPool.currentLimit()
+	scanPool.setLimit(min(previous, backgroundPoolLimit))
+	_ = setDiskThrottled(true)
+	return previous
+}
+
+// restoreForeground puts back the worker cap and disk priority from before the blur.
+// Under --nice the disk stays throttled.
+func restoreForeground(limit int) {
+	scanPool.setLimit(limit)
+	if !niceMode {
+		_ = setDiskThrottled(false)
+	}
+}
@@ -0,0 +1,24 @@
+package main
+
+import "testing"
+
+func TestBackgroundThrottleRestoresWorkerCap(t *testing.T) {
+	original := scanPool.currentLimit()
+	defer scanPool.setLimit(original)
+
+	scanPool.setLimit(24)
+	previous := throttleForBackground()
+	if previous != 24 || scanPool.currentLimit() != backgroundPoolLimit {
+		t.Fatalf("throttle returned %d with cap %d, want 24 and %d", previous, scanPool.currentLimit(), backgroundPoolLimit)
+	}
+	restoreForeground(previous)
+	if scanPool.currentLimit() != 24 {
+		t.Fatalf("cap after focus = %d, want 24", scanPool.currentLimit())
+	}
+
+	scanPool.setLimit(1)
+	if previous := throttleForBackground(); previous != 1 || scanPool.currentLimit() != 1 {
+		t.Fatalf("throttle should never raise a lower cap, got %d", scanPool.currentLimit())
+	}
+	restoreForeground(1)
+}
@@ -4,10 +4,19 @@ import (
 	"fmt"
 	"os"
 	"strings"
-	"time"
+
+	"github.com/tw93/mole/internal/humanize"
 )
 
 func displayPath(path string) string {
+	shown := sanitizeName(homeRelativePath(path))
+	if activeRedactor != nil {
+		return activeRedactor.redactDisplay(shown)
+	}
+	return shown
+}
+
+func homeRelativePath(path string) string {
 	home, err := os.UserHomeDir()
 	if err != nil || home == "" {
 		return path
@@ -20,6 +29,7 @@ func displayPath(path string) string {
 
 // truncateMiddle trims the middle, keeping head and tail.
 func truncateMiddle(s string, maxWidth int) string {
+	s = sanitizeName(s)
 	runes := []rune(s)
 	currentWidth := displayWidth(s)
 
@@ -71,26 +81,26 @@ func formatNumber(n int64) string {
 		return fmt.Sprintf("%d", n)
 	}
 	if n < 1000000 {
-		return fmt.Sprintf("%.1fk", float64(n)/1000)
+		return formatDecimal(float64(n)/1000, 1) + "k"
 	}
-	return fmt.Sprintf("%.1fM", float64(n)/1000000)
+	return formatDecimal(float64(n)/1000000, 1) + "M"
 }
 
-func humanizeBytes(size int64) string {
-	if size < 0 {
-		return "0 B"
-	}
-	const unit = 1024
-	if size < unit {
-		return fmt.Sprintf("%d B", size)
+// formatCount is formatNumber for counts that are -1 when unknown, such as du-sized folders.
+func formatCount(n int64) string {
+	if n < 0 {
+		return "-"
 	}
-	div, exp := int64(unit), 0
-	for n := size / unit; n >= unit; n /= unit {
-		div *= unit
-		exp++
+	return formatNumber(n)
+}
+
+// humanizeBytes is humanize.Bytes with the locale's decimal mark.
+func humanizeBytes(size int64) string {
+	value, unit := humanize.Scale(size)
+	if unit == "B" {
+		return fmt.Sprintf("%d B", int64(value))
 	}
-	value := float64(size) / float64(div)
-	return fmt.Sprintf("%.1f %cB", value, "KMGTPE"[exp])
+	return fmt.Sprintf("%s %s", formatDecimal(value, 1), unit)
 }
 
 func coloredProgressBar(value, max int64, percent float64) string {
@@ -175,12 +185,20 @@ func calculateNameWidth(termWidth int) int {
 	if available < 24 {
 		return 24
 	}
-	if available > 60 {
-		return 60
+	if available > maxNameWidth {
+		return maxNameWidth
 	}
 	return available
 }
 
+// calculatePathWidth is the room for a path printed on a line of its own.
+func calculatePathWidth(termWidth int) int {
+	if termWidth <= 0 {
+		return defaultPathWidth
+	}
+	return max(termWidth-4, 20)
+}
+
 func trimName(name string) string {
 	return trimNameWithWidth(name, 45) // Default width for backward compatibility
 }
@@ -191,6 +209,7 @@ func trimNameWithWidth(name string, maxWidth int) string {
 		ellipsisWidth = 3
 	)
 
+	name = sanitizeName(name)
 	runes := []rune(name)
 	widths := make([]int, len(runes))
 	for i, r := range runes {
@@ -224,30 +243,3 @@ func padName(name string, targetWidth int) string {
 	}
 	return name + strings.Repeat(" ", targetWidth-currentWidth)
 }
-
-// formatUnusedTime formats time since last access.
-func formatUnusedTime(lastAccess time.Time) string {
-	if lastAccess.IsZero() {
-		return ""
-	}
-
-	duration := time.Since(lastAccess)
-	days := int(duration.Hours() / 24)
-
-	if days < 90 {
-		return ""
-	}
-
-	months := days / 30
-	years := days / 365
-
-	if years >= 2 {
-		return fmt.Sprintf(">%dyr", years)
-	} else if years >= 1 {
-		return ">1yr"
-	} else if months >= 3 {
-		return fmt.Sprintf(">%dmo", months)
-	}
-
-	return ""
-}
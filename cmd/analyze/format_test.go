@@ -292,11 +292,12 @@ func TestCalculateNameWidth(t *testing.T) {
 		wantMin   int
 		wantMax   int
 	}{
-		{80, 19, 60},  // 80 - 61 = 19
-		{120, 59, 60}, // 120 - 61 = 59
-		{200, 60, 60}, // Capped at 60
-		{70, 24, 60},  // Below minimum, use 24
-		{50, 24, 60},  // Very small, use minimum
+		{80, 19, 60},    // 80 - 61 = 19
+		{120, 59, 60},   // 120 - 61 = 59
+		{160, 99, 99},   // 160 - 61 = 99
+		{300, 120, 120}, // Capped at maxNameWidth
+		{70, 24, 60},    // Below minimum, use 24
+		{50, 24, 60},    // Very small, use minimum
 	}
 
 	for _, tt := range tests {
@@ -307,3 +308,30 @@ func TestCalculateNameWidth(t *testing.T) {
 		}
 	}
 }
+
+func TestCalculateViewportFollowsTerminalHeight(t *testing.T) {
+	if got := calculateViewport(0, false); got != defaultViewport {
+		t.Errorf("calculateViewport(0) = %d, want %d", got, defaultViewport)
+	}
+	if got := calculateViewport(80, false); got != 74 {
+		t.Errorf("calculateViewport(80) = %d, want 74 rows on a tall terminal", got)
+	}
+	if got := calculateViewport(80, true); got != 75 {
+		t.Errorf("calculateViewport(80, large) = %d, want 75", got)
+	}
+	if got := calculateViewport(3, false); got != 1 {
+		t.Errorf("calculateViewport(3) = %d, want 1", got)
+	}
+}
+
+func TestCalculatePathWidth(t *testing.T) {
+	if got := calculatePathWidth(0); got != defaultPathWidth {
+		t.Errorf("calculatePathWidth(0) = %d, want %d", got, defaultPathWidth)
+	}
+	if got := calculatePathWidth(200); got != 196 {
+		t.Errorf("calculatePathWidth(200) = %d, want 196", got)
+	}
+	if got := calculatePathWidth(10); got != 20 {
+		t.Errorf("calculatePathWidth(10) = %d, want 20", got)
+	}
+}
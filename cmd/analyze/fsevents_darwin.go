@@ -0,0 +1,167 @@
+//go:build darwin && cgo
+
+package main
+
+/*
+#cgo LDFLAGS: -framework CoreServices
+#include <stdlib.h>
+#include <CoreServices/CoreServices.h>
+#include <dispatch/dispatch.h>
+
+extern void moleJournalEvents(uintptr_t handle, size_t count, char **paths, FSEventStreamEventFlags *flags);
+
+static dispatch_queue_t moleJournalQueue;
+
+static void moleJournalCallback(ConstFSEventStreamRef stream, void *info, size_t count, void *paths,
+		const FSEventStreamEventFlags flags[], const FSEventStreamEventId ids[]) {
+	moleJournalEvents((uintptr_t)info, count, (char **)paths, (FSEventStreamEventFlags *)flags);
+}
+
+static void moleJournalNoop(void *context) {}
+
+// moleJournalVolume writes the FSEvents UUID of a device's journal into buf.
+static int moleJournalVolume(dev_t dev, char *buf, int size) {
+	CFUUIDRef uuid = FSEventsCopyUUIDForDevice(dev);
+	if (uuid == NULL) {
+		return 0;
+	}
+	CFStringRef text = CFUUIDCreateString(NULL, uuid);
+	CFRelease(uuid);
+	if (text == NULL) {
+		return 0;
+	}
+	Boolean ok = CFStringGetCString(text, buf, size, kCFStringEncodingUTF8);
+	CFRelease(text);
+	return ok;
+}
+
+// moleJournalStart replays root's history since an event ID on a private queue.
+static void *moleJournalStart(uintptr_t handle, const char *root, FSEventStreamEventId since) {
+	if (moleJournalQueue == NULL) {
+		moleJournalQueue = dispatch_queue_create("mole.journal", DISPATCH_QUEUE_SERIAL);
+	}
+	CFStringRef path = CFStringCreateWithCString(NULL, root, kCFStringEncodingUTF8);
+	if (path == NULL) {
+		return NULL;
+	}
+	CFArrayRef paths = CFArrayCreate(NULL, (const void **)&path, 1, &kCFTypeArrayCallBacks);
+	FSEventStreamContext context = {0, (void *)handle, NULL, NULL, NULL};
+	FSEventStreamRef stream = FSEventStreamCreate(NULL, moleJournalCallback, &context, paths, since, 0, kFSEventStreamCreateFlagNone);
+	CFRelease(paths);
+	CFRelease(path);
+	if (stream == NULL) {
+		return NULL;
+	}
+	FSEventStreamSetDispatchQueue(stream, moleJournalQueue);
+	if (!FSEventStreamStart(stream)) {
+		FSEventStreamInvalidate(stream);
+		FSEventStreamRelease(stream);
+		return NULL;
+	}
+	return stream;
+}
+
+// moleJournalStop ends a replay and waits out any callback still running.
+static void moleJournalStop(void *stream) {
+	FSEventStreamStop((FSEventStreamRef)stream);
+	FSEventStreamInvalidate((FSEventStreamRef)stream);
+	FSEventStreamRelease((FSEventStreamRef)stream);
+	dispatch_sync_f(moleJournalQueue, NULL, moleJournalNoop);
+}
+*/
+import "C"
+
+import (
+	"context"
+	"fmt"
+	"runtime/cgo"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+// journalResetFlags mean the journal cannot say exactly what changed.
+const journalResetFlags = C.kFSEventStreamEventFlagMustScanSubDirs | C.kFSEventStreamEventFlagUserDropped |
+	C.kFSEventStreamEventFlagKernelDropped | C.kFSEventStreamEventFlagEventIdsWrapped | C.kFSEventStreamEventFlagRootChanged
+
+// journalCollector gathers a replay's changed folders until the history is done.
+type journalCollector struct {
+	mu       sync.Mutex
+	dirs     map[string]bool
+	reset    bool
+	done     chan struct{}
+	finished bool
+}
+
+func (c *journalCollector) finish() {
+	if !c.finished {
+		c.finished = true
+		close(c.done)
+	}
+}
+
+//export moleJournalEvents
+func moleJournalEvents(handle C.uintptr_t, count C.size_t, paths **C.char, flags *C.FSEventStreamEventFlags) {
+	c := cgo.Handle(handle).Value().(*journalCollector)
+	pathList := unsafe.Slice(paths, int(count))
+	flagList := unsafe.Slice(flags, int(count))
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, flag := range flagList {
+		switch {
+		case flag&C.kFSEventStreamEventFlagHistoryDone != 0:
+			c.finish()
+		case flag&journalResetFlags != 0:
+			c.reset = true
+		default:
+			c.dirs[C.GoString(pathList[i])] = true
+		}
+		if c.reset || len(c.dirs) > journalMaxChanged {
+			c.reset = true
+			c.finish()
+		}
+	}
+}
+
+// journalPosition names the journal holding root and the newest event ID so far.
+func journalPosition(root string) (string, uint64, error) {
+	var st syscall.Stat_t
+	if err := syscall.Stat(root, &st); err != nil {
+		return "", 0, err
+	}
+	var buf [64]C.char
+	if C.moleJournalVolume(C.dev_t(st.Dev), &buf[0], C.int(len(buf))) == 0 {
+		return "", 0, errJournalUnavailable
+	}
+	return C.GoString(&buf[0]), uint64(C.FSEventsGetCurrentEventId()), nil
+}
+
+// journalChangesSince replays root's FSEvents history after since, stopping at the end
+// of the history or when ctx is done.
+func journalChangesSince(ctx context.Context, root string, since uint64) (journalReplay, error) {
+	c := &journalCollector{dirs: make(map[string]bool), done: make(chan struct{})}
+	handle := cgo.NewHandle(c)
+	defer handle.Delete()
+	path := C.CString(root)
+	defer C.free(unsafe.Pointer(path))
+
+	stream := C.moleJournalStart(C.uintptr_t(handle), path, C.FSEventStreamEventId(since))
+	if stream == nil {
+		return journalReplay{}, fmt.Errorf("cannot replay the FSEvents journal for %s", root)
+	}
+	var err error
+	select {
+	case <-c.done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	C.moleJournalStop(stream)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	replay := journalReplay{Reset: c.reset}
+	for dir := range c.dirs {
+		replay.Dirs = append(replay.Dirs, dir)
+	}
+	return replay, err
+}
@@ -0,0 +1,14 @@
+//go:build !darwin || !cgo
+
+package main
+
+import "context"
+
+// journalPosition needs FSEvents.
+func journalPosition(string) (string, uint64, error) {
+	return "", 0, errJournalUnavailable
+}
+
+func journalChangesSince(context.Context, string, uint64) (journalReplay, error) {
+	return journalReplay{}, errJournalUnavailable
+}
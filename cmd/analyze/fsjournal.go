@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// journalMarksFile keeps, per scanned root, where the FSEvents journal stood at the last launch.
+	journalMarksFile = "journal.json"
+	// journalReplayTimeout bounds the replay at launch; past it every cache is treated as stale.
+	journalReplayTimeout = 3 * time.Second
+	// journalMaxChanged is how many changed folders are invalidated one by one before it
+	// is cheaper to drop every folder cache.
+	journalMaxChanged = 20000
+)
+
+var errJournalUnavailable = errors.New("the FSEvents journal is not available")
+
+// journalMark is a root's last seen position in its volume's FSEvents journal.
+type journalMark struct {
+	Volume  string `json:"volume"` // Journal UUID; it changes when the volume's history is reset
+	EventID uint64 `json:"event_id"`
+}
+
+// journalReplay is what the journal reported since a mark.
+type journalReplay struct {
+	Dirs  []string // Folders whose entries changed
+	Reset bool     // Events were dropped or the history is gone, so anything may have changed
+}
+
+func journalMarksPath() (string, error) {
+	cacheDir, err := getCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, journalMarksFile), nil
+}
+
+func loadJournalMarks() map[string]journalMark {
+	marks := make(map[string]journalMark)
+	path, err := journalMarksPath()
+	if err != nil {
+		return marks
+	}
+	if data, err := readCacheFile(path); err == nil {
+		_ = json.Unmarshal(data, &marks)
+	}
+	return marks
+}
+
+func saveJournalMarks(marks map[string]journalMark) error {
+	path, err := journalMarksPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(marks)
+	if err != nil {
+		return err
+	}
+	return writeCacheFile(path, data)
+}
+
+// journalInvalidations lists the folders whose caches a replay makes stale: every changed
+// folder under root and each folder above it up to root, since their totals include it.
+func journalInvalidations(root string, dirs []string) []string {
+	seen := make(map[string]bool)
+	var stale []string
+	for _, dir := range dirs {
+		dir = filepath.Clean(dir)
+		for pathsOverlap(root, dir) && !seen[dir] {
+			seen[dir] = true
+			stale = append(stale, dir)
+			if dir == root || dir == filepath.Dir(dir) {
+				break
+			}
+			dir = filepath.Dir(dir)
+		}
+	}
+	return stale
+}
+
+// dropFolderCaches removes every folder cache and the overview sizes under root, for
+// when the journal cannot say what changed.
+func dropFolderCaches(root string) {
+	if cacheDir, err := getCacheDir(); err == nil {
+		if entries, err := os.ReadDir(cacheDir); err == nil {
+			for _, entry := range entries {
+				if strings.HasSuffix(entry.Name(), ".cache") {
+					_ = os.Remove(filepath.Join(cacheDir, entry.Name()))
+				}
+			}
+		}
+	}
+	removeOverviewSnapshotsUnder(root)
+}
+
+// applyJournalReplay invalidates what a replay reports and returns how many folders it touched.
+func applyJournalReplay(root string, replay journalReplay) int {
+	stale := journalInvalidations(root, replay.Dirs)
+	if replay.Reset || len(stale) > journalMaxChanged {
+		dropFolderCaches(root)
+		return -1
+	}
+	for _, dir := range stale {
+		invalidateCache(dir)
+	}
+	return len(stale)
+}
+
+// replayChangeJournals asks FSEvents what changed under each root since the last launch
+// and invalidates only those caches, then records where the journal stands now. Roots
+// seen for the first time keep relying on folder modification times. It returns how
+// many folders were invalidated, -1 when every folder cache had to go.
+func replayChangeJournals(ctx context.Context, roots []string) (int, error) {
+	marks := loadJournalMarks()
+	total := 0
+	for _, root := range roots {
+		volume, current, err := journalPosition(root)
+		if err != nil {
+			return total, err
+		}
+		if mark, seen := marks[root]; seen {
+			replay := journalReplay{Reset: true}
+			if mark.Volume == volume && mark.EventID <= current {
+				if replay, err = journalChangesSince(ctx, root, mark.EventID); err != nil {
+					replay.Reset = true
+				}
+			}
+			if n := applyJournalReplay(root, replay); n < 0 || total < 0 {
+				total = -1
+			} else {
+				total += n
+			}
+		}
+		marks[root] = journalMark{Volume: volume, EventID: current}
+	}
+	return total, saveJournalMarks(marks)
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+func TestJournalInvalidations(t *testing.T) {
+	got := journalInvalidations("/Users/me", []string{
+		"/Users/me/Projects/app/src/",
+		"/Users/me/Projects/app",
+		"/Users/me/Downloads",
+		"/private/var/tmp", // Outside the root
+	})
+	sort.Strings(got)
+	want := []string{"/Users/me", "/Users/me/Downloads", "/Users/me/Projects", "/Users/me/Projects/app", "/Users/me/Projects/app/src"}
+	if len(got) != len(want) {
+		t.Fatalf("invalidations = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("invalidations = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestApplyJournalReplay(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	root := filepath.Join(home, "data")
+	cacheFor := func(path string) string {
+		t.Helper()
+		cachePath, err := getCachePath(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(cachePath, []byte("cached"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return cachePath
+	}
+	changed := cacheFor(filepath.Join(root, "a", "b"))
+	parent := cacheFor(filepath.Join(root, "a"))
+	sibling := cacheFor(filepath.Join(root, "c"))
+
+	if n := applyJournalReplay(root, journalReplay{Dirs: []string{filepath.Join(root, "a", "b")}}); n != 3 {
+		t.Errorf("invalidated %d folders, want 3", n)
+	}
+	for path, kept := range map[string]bool{changed: false, parent: false, sibling: true} {
+		if _, err := os.Stat(path); (err == nil) != kept {
+			t.Errorf("%s kept = %v, want %v", path, err == nil, kept)
+		}
+	}
+
+	history := filepath.Join(filepath.Dir(sibling), "scans.history")
+	if err := os.WriteFile(history, []byte("history"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if n := applyJournalReplay(root, journalReplay{Reset: true}); n != -1 {
+		t.Errorf("reset invalidated %d folders, want -1", n)
+	}
+	if _, err := os.Stat(sibling); !os.IsNotExist(err) {
+		t.Errorf("folder cache survived a journal reset: %v", err)
+	}
+	if _, err := os.Stat(history); err != nil {
+		t.Errorf("scan history removed by a journal reset: %v", err)
+	}
+}
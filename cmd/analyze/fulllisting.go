@@ -0,0 +1,50 @@
+package main
+
+import (
+	"math"
+	"sync/atomic"
+)
+
+// fullListing keeps every child of a scanned folder instead of the largest maxEntries.
+// The scanner reads it from its collector goroutine, so it is atomic.
+var fullListing atomic.Bool
+
+// entryLimit is how many children a listing keeps.
+func entryLimit() int {
+	if fullListing.Load() {
+		return math.MaxInt
+	}
+	return maxEntries
+}
+
+// capEntries trims entries, sorted largest first, to the listing limit and reports
+// how many were dropped and their combined size.
+func capEntries(entries []dirEntry) ([]dirEntry, int, int64) {
+	limit := entryLimit()
+	if len(entries) <= limit {
+		return entries, 0, 0
+	}
+	var omittedSize int64
+	for _, entry := range entries[limit:] {
+		omittedSize += entry.Size
+	}
+	return entries[:limit], len(entries) - limit, omittedSize
+}
+
+// pageTarget returns the row a paging key moves the cursor to in a list of count rows.
+func pageTarget(key string, selected, count, viewport int) int {
+	if count == 0 {
+		return 0
+	}
+	switch key {
+	case "pgup":
+		selected -= max(viewport, 1)
+	case "pgdown":
+		selected += max(viewport, 1)
+	case "home":
+		selected = 0
+	case "end":
+		selected = count - 1
+	}
+	return min(max(selected, 0), count-1)
+}
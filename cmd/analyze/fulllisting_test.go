@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+func TestCapEntriesReportsOmitted(t *testing.T) {
+	defer fullListing.Store(false)
+	entries := make([]dirEntry, maxEntries+3)
+	for i := range entries {
+		entries[i] = dirEntry{Name: fmt.Sprint(i), Size: int64(len(entries) - i)}
+	}
+	kept, omitted, omittedSize := capEntries(entries)
+	if len(kept) != maxEntries || omitted != 3 || omittedSize != 3+2+1 {
+		t.Fatalf("capEntries kept %d, omitted %d totaling %d", len(kept), omitted, omittedSize)
+	}
+
+	fullListing.Store(true)
+	if kept, omitted, _ := capEntries(entries); len(kept) != len(entries) || omitted != 0 {
+		t.Fatalf("full listing should keep everything, kept %d omitted %d", len(kept), omitted)
+	}
+}
+
+func TestScanCountsEntriesBeyondLimit(t *testing.T) {
+	defer fullListing.Store(false)
+	root := t.TempDir()
+	for i := 0; i < maxEntries+2; i++ {
+		writeFileWithSize(t, filepath.Join(root, fmt.Sprintf("f%02d.bin", i)), 100*(i+1))
+	}
+
+	var files, dirs, bytes int64
+	var current string
+	result, err := scanPathConcurrent(context.Background(), root, &files, &dirs, &bytes, &current, nil)
+	if err != nil {
+		t.Fatalf("scanPathConcurrent: %v", err)
+	}
+	if len(result.Entries) != maxEntries || result.Omitted != 2 || result.OmittedSize <= 0 {
+		t.Fatalf("capped scan: %d entries, %d omitted totaling %d", len(result.Entries), result.Omitted, result.OmittedSize)
+	}
+
+	fullListing.Store(true)
+	result, err = scanPathConcurrent(context.Background(), root, &files, &dirs, &bytes, &current, nil)
+	if err != nil {
+		t.Fatalf("scanPathConcurrent: %v", err)
+	}
+	if len(result.Entries) != maxEntries+2 || result.Omitted != 0 {
+		t.Fatalf("full scan: %d entries, %d omitted", len(result.Entries), result.Omitted)
+	}
+}
+
+func TestPageTarget(t *testing.T) {
+	tests := []struct {
+		key      string
+		selected int
+		want     int
+	}{
+		{"pgdown", 0, 10},
+		{"pgdown", 45, 49},
+		{"pgup", 15, 5},
+		{"pgup", 3, 0},
+		{"home", 30, 0},
+		{"end", 3, 49},
+	}
+	for _, tc := range tests {
+		if got := pageTarget(tc.key, tc.selected, 50, 10); got != tc.want {
+			t.Errorf("pageTarget(%q, %d) = %d, want %d", tc.key, tc.selected, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"strings"
+	"sync"
+)
+
+// glossary.json is the contributor-editable knowledge base of known locations.
+//
+//go:embed glossary.json
+var glossaryData []byte
+
+// glossaryEntry explains one well-known location and how to shrink it safely.
+type glossaryEntry struct {
+	Match  string `json:"match"`  // Path suffix, e.g. "/Library/Developer/CoreSimulator"
+	Title  string `json:"title"`  // Short display name
+	About  string `json:"about"`  // What lives there
+	Safety string `json:"safety"` // "safe", "caution" or "keep"
+	Shrink string `json:"shrink"` // The official way to reclaim space
+}
+
+var (
+	glossaryOnce    sync.Once
+	glossaryEntries []glossaryEntry
+)
+
+func loadGlossary() []glossaryEntry {
+	glossaryOnce.Do(func() {
+		_ = json.Unmarshal(glossaryData, &glossaryEntries)
+	})
+	return glossaryEntries
+}
+
+// lookupGlossary returns the most specific entry whose match ends path.
+func lookupGlossary(path string) (glossaryEntry, bool) {
+	path = strings.TrimSuffix(path, "/")
+	var best glossaryEntry
+	found := false
+	for _, entry := range loadGlossary() {
+		if entry.Match == "" || !strings.HasSuffix(path, entry.Match) {
+			continue
+		}
+		if !found || len(entry.Match) > len(best.Match) {
+			best = entry
+			found = true
+		}
+	}
+	return best, found
+}
+
+// safetyLabel turns the safety keyword into the phrase shown in the UI.
+func (g glossaryEntry) safetyLabel() string {
+	switch g.Safety {
+	case "safe":
+		return "Safe to delete"
+	case "caution":
+		return "Delete with care"
+	default:
+		return "Do not delete"
+	}
+}
@@ -0,0 +1,34 @@
+package main
+
+import "testing"
+
+func TestGlossaryEntriesAreComplete(t *testing.T) {
+	entries := loadGlossary()
+	if len(entries) == 0 {
+		t.Fatal("expected embedded glossary entries")
+	}
+	for _, entry := range entries {
+		if entry.Match == "" || entry.Title == "" || entry.About == "" || entry.Shrink == "" {
+			t.Fatalf("incomplete glossary entry %+v", entry)
+		}
+		switch entry.Safety {
+		case "safe", "caution", "keep":
+		default:
+			t.Fatalf("%s: unknown safety %q", entry.Title, entry.Safety)
+		}
+	}
+}
+
+func TestLookupGlossaryPrefersMostSpecific(t *testing.T) {
+	entry, ok := lookupGlossary("/Users/a/Library/Containers/com.docker.docker")
+	if !ok || entry.Title != "Docker Desktop" {
+		t.Fatalf("expected Docker Desktop, got %+v", entry)
+	}
+	entry, ok = lookupGlossary("/Users/a/Library/Containers/")
+	if !ok || entry.Title != "App Containers" {
+		t.Fatalf("expected App Containers, got %+v", entry)
+	}
+	if _, ok := lookupGlossary("/Users/a/Library/Containers/com.apple.mail"); ok {
+		t.Fatal("expected no entry for an arbitrary container")
+	}
+}
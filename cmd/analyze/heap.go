@@ -1,24 +1,37 @@
 package main
 
-// entryHeap is a min-heap of dirEntry used to keep Top N largest entries.
-type entryHeap []dirEntry
+// entryHeap is a min-heap of dirEntry used to keep the Top N entries by score, the
+// size or the weighted rank of rankScorer.
+type entryHeap struct {
+	items []dirEntry
+	score func(dirEntry) int64
+}
+
+func newEntryHeap(score func(dirEntry) int64) *entryHeap {
+	return &entryHeap{score: score}
+}
 
-func (h entryHeap) Len() int           { return len(h) }
-func (h entryHeap) Less(i, j int) bool { return h[i].Size < h[j].Size }
-func (h entryHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h entryHeap) Len() int           { return len(h.items) }
+func (h entryHeap) Less(i, j int) bool { return h.score(h.items[i]) < h.score(h.items[j]) }
+func (h entryHeap) Swap(i, j int)      { h.items[i], h.items[j] = h.items[j], h.items[i] }
 
 func (h *entryHeap) Push(x interface{}) {
-	*h = append(*h, x.(dirEntry))
+	h.items = append(h.items, x.(dirEntry))
 }
 
 func (h *entryHeap) Pop() interface{} {
-	old := *h
+	old := h.items
 	n := len(old)
 	x := old[n-1]
-	*h = old[0 : n-1]
+	h.items = old[0 : n-1]
 	return x
 }
 
+// outranksMin reports whether entry scores above the lowest kept entry.
+func (h *entryHeap) outranksMin(entry dirEntry) bool {
+	return h.score(entry) > h.score(h.items[0])
+}
+
 // largeFileHeap is a min-heap for fileEntry.
 type largeFileHeap []fileEntry
 
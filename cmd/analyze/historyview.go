@@ -0,0 +1,137 @@
+//go:build darwin
+
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// historyDiffView is the "y" screen listing what grew or shrank since an earlier scan.
+type historyDiffView struct {
+	Path     string
+	History  []scanSnapshot // Oldest first; the last is the scan on screen
+	Baseline int            // Snapshot compared against the latest
+	Deltas   []growthDelta
+	ByChange bool // Sort by the size of the change either way instead of growth
+	Selected int
+}
+
+func (v *historyDiffView) compare() {
+	latest := v.History[len(v.History)-1]
+	v.Deltas = diffSnapshots(v.History[v.Baseline], latest)
+	sortGrowthDeltas(v.Deltas, v.ByChange)
+	v.Selected = min(v.Selected, max(len(v.Deltas)-1, 0))
+}
+
+func (m model) openHistoryDiff() (tea.Model, tea.Cmd) {
+	if m.inventory != nil || m.inOverviewMode() {
+		m.status = "Open a scanned folder to compare it with earlier scans"
+		return m, nil
+	}
+	history, err := loadScanHistory(m.path)
+	if err != nil || len(history) < 2 {
+		m.status = fmt.Sprintf("Only one scan of %s so far; rescan it later to see what changed", displayPath(m.path))
+		return m, nil
+	}
+	v := &historyDiffView{Path: m.path, History: history, Baseline: len(history) - 2}
+	v.compare()
+	m.historyDiff = v
+	return m, nil
+}
+
+// updateHistoryDiffKey handles keys while the growth diff is open.
+func (m model) updateHistoryDiffKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	v := m.historyDiff
+	switch msg.String() {
+	case "q", "Q", "ctrl+c":
+		return m.quit()
+	case "esc", "b", "left", "h", "y":
+		m.historyDiff = nil
+	case "up", "k":
+		v.Selected = max(v.Selected-1, 0)
+	case "down", "j":
+		v.Selected = min(v.Selected+1, max(len(v.Deltas)-1, 0))
+	case "[":
+		if v.Baseline > 0 {
+			v.Baseline--
+			v.compare()
+		}
+	case "]":
+		if v.Baseline < len(v.History)-2 {
+			v.Baseline++
+			v.compare()
+		}
+	case "s":
+		v.ByChange = !v.ByChange
+		sortGrowthDeltas(v.Deltas, v.ByChange)
+	case "enter", "right", "l":
+		if v.Selected >= len(v.Deltas) || !v.Deltas[v.Selected].IsDir || v.Deltas[v.Selected].After == 0 {
+			return m, nil
+		}
+		path := v.Deltas[v.Selected].Path
+		m.historyDiff = nil
+		return m.openDir(path)
+	}
+	return m, nil
+}
+
+// renderHistoryDiff shows the total change and a delta column per changed child.
+func (m model) renderHistoryDiff() string {
+	v := m.historyDiff
+	before, after := v.History[v.Baseline], v.History[len(v.History)-1]
+	var b strings.Builder
+	fmt.Fprintf(&b, "%sSince %s:%s %s → %s %s(%s)%s in %s\n",
+		colorCyan, before.At.Format("Jan 2 15:04"), colorReset,
+		humanizeBytes(before.TotalSize), humanizeBytes(after.TotalSize),
+		deltaColor(after.TotalSize-before.TotalSize), formatDelta(after.TotalSize-before.TotalSize), colorReset, displayPath(v.Path))
+	fmt.Fprintf(&b, "%sScan %d of %d, latest %s%s\n\n", colorGray, v.Baseline+1, len(v.History), after.At.Format("Jan 2 15:04"), colorReset)
+
+	if len(v.Deltas) == 0 {
+		fmt.Fprintf(&b, "  %sNothing changed between these scans%s\n", colorGray, colorReset)
+	}
+	viewport := calculateViewport(m.height, true)
+	nameWidth := calculateNameWidth(m.width)
+	start := max(0, v.Selected-viewport+1)
+	for i := start; i < min(len(v.Deltas), start+viewport); i++ {
+		delta := v.Deltas[i]
+		prefix, color := "   ", ""
+		if i == v.Selected {
+			prefix, color = fmt.Sprintf(" %s%s▶%s ", colorCyan, colorBold, colorReset), colorCyan
+		}
+		icon := "📄"
+		if delta.IsDir {
+			icon = "📁"
+		}
+		name := trimNameWithWidth(displayName(delta.Path, filepath.Base(delta.Path)), nameWidth)
+		note := ""
+		switch {
+		case delta.Before == 0:
+			note = "new"
+		case delta.After == 0:
+			note = "gone"
+		}
+		fmt.Fprintf(&b, "%s%s%11s%s  %s %s%s%s %10s → %-10s %s%s%s\n",
+			prefix, deltaColor(delta.Delta()), formatDelta(delta.Delta()), colorReset,
+			icon, color, padName(name, nameWidth), colorReset,
+			humanizeBytes(delta.Before), humanizeBytes(delta.After), colorGray, note, colorReset)
+	}
+
+	fmt.Fprintln(&b)
+	sortLabel := "largest change"
+	if v.ByChange {
+		sortLabel = "growth"
+	}
+	fmt.Fprintf(&b, "%s↑↓ | [ ] Older/newer scan | S Sort by %s | Enter Browse | Y/← Back | Q Quit%s\n", colorGray, sortLabel, colorReset)
+	return b.String()
+}
+
+func deltaColor(delta int64) string {
+	if delta > 0 {
+		return colorRed
+	}
+	return colorGreen
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// brewTimeout bounds each brew command; cleanup of a large cache can take a while.
+const brewTimeout = 5 * time.Minute
+
+// brewLocations are where Homebrew installs itself when brew is not on PATH, as from a
+// launch agent: Apple silicon, then Intel.
+var brewLocations = []string{"/opt/homebrew/bin/brew", "/usr/local/bin/brew"}
+
+// brewVersion is one installed version of a formula or cask.
+type brewVersion struct {
+	Version string
+	Path    string
+	Size    int64
+}
+
+// brewKeg is an installed formula or cask and every version kept of it.
+type brewKeg struct {
+	Name     string
+	Cask     bool
+	Current  string        // Version in use; the others are what brew cleanup removes
+	Versions []brewVersion // Current first, then as brew listed them
+}
+
+func (k brewKeg) size() int64 {
+	var total int64
+	for _, version := range k.Versions {
+		total += version.Size
+	}
+	return total
+}
+
+// oldSize is the space held by versions other than the current one.
+func (k brewKeg) oldSize() int64 {
+	var total int64
+	for _, version := range k.Versions {
+		if version.Version != k.Current {
+			total += version.Size
+		}
+	}
+	return total
+}
+
+// brewFootprint is Homebrew's disk use: downloads and every installed keg.
+type brewFootprint struct {
+	Cache     string
+	CacheSize int64
+	Kegs      []brewKeg // Most reclaimable first, then largest
+}
+
+func (f brewFootprint) size() int64 {
+	total := f.CacheSize
+	for _, keg := range f.Kegs {
+		total += keg.size()
+	}
+	return total
+}
+
+// reclaimable is what `brew cleanup --prune=all` would free: the cache and old versions.
+func (f brewFootprint) reclaimable() int64 {
+	total := f.CacheSize
+	for _, keg := range f.Kegs {
+		total += keg.oldSize()
+	}
+	return total
+}
+
+type brewFootprintMsg struct {
+	Footprint brewFootprint
+	Err       error
+}
+
+type brewCleanupMsg struct {
+	Formula string // Empty when everything was cleaned up
+	Output  string
+	Err     error
+}
+
+func findBrew() (string, error) {
+	if path, err := exec.LookPath("brew"); err == nil {
+		return path, nil
+	}
+	for _, path := range brewLocations {
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("Homebrew is not installed")
+}
+
+// runBrew runs a brew command without its auto-update and hints.
+func runBrew(brew string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), brewTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, brew, args...)
+	cmd.Env = append(os.Environ(), "HOMEBREW_NO_AUTO_UPDATE=1", "HOMEBREW_NO_ENV_HINTS=1", "HOMEBREW_NO_COLOR=1")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return string(out), fmt.Errorf("brew %s: %s", strings.Join(args, " "), firstLine(string(out), err))
+	}
+	return string(out), nil
+}
+
+func firstLine(out string, err error) string {
+	if line, _, _ := strings.Cut(strings.TrimSpace(out), "\n"); line != "" {
+		return line
+	}
+	return err.Error()
+}
+
+// parseBrewVersions reads `brew list --versions`: a name and its installed versions per line.
+func parseBrewVersions(out string, cask bool) []brewKeg {
+	var kegs []brewKeg
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		keg := brewKeg{Name: fields[0], Cask: cask}
+		for _, version := range fields[1:] {
+			keg.Versions = append(keg.Versions, brewVersion{Version: version})
+		}
+		kegs = append(kegs, keg)
+	}
+	return kegs
+}
+
+// settleBrewKeg fills in paths and the current version: the one opt/ links to, or for a
+// cask or an unlinked formula the last version brew listed.
+func settleBrewKeg(keg *brewKeg, prefix string) {
+	root := filepath.Join(prefix, "Cellar", keg.Name)
+	if keg.Cask {
+		root = filepath.Join(prefix, "Caskroom", keg.Name)
+	}
+	for i := range keg.Versions {
+		keg.Versions[i].Path = filepath.Join(root, keg.Versions[i].Version)
+	}
+	keg.Current = keg.Versions[len(keg.Versions)-1].Version
+	if !keg.Cask {
+		if target, err := os.Readlink(filepath.Join(prefix, "opt", keg.Name)); err == nil {
+			for _, version := range keg.Versions {
+				if filepath.Base(target) == version.Version {
+					keg.Current = version.Version
+				}
+			}
+		}
+	}
+	sort.SliceStable(keg.Versions, func(a, b int) bool {
+		return keg.Versions[a].Version == keg.Current && keg.Versions[b].Version != keg.Current
+	})
+}
+
+// sortBrewKegs puts kegs with old versions first, most reclaimable first, then the largest.
+func sortBrewKegs(kegs []brewKeg) {
+	sort.SliceStable(kegs, func(a, b int) bool {
+		if oa, ob := kegs[a].oldSize(), kegs[b].oldSize(); oa != ob {
+			return oa > ob
+		}
+		if sa, sb := kegs[a].size(), kegs[b].size(); sa != sb {
+			return sa > sb
+		}
+		return kegs[a].Name < kegs[b].Name
+	})
+}
+
+// measureBrewFootprint asks brew where its cache and kegs are and sizes them with du.
+func measureBrewFootprint() (brewFootprint, error) {
+	brew, err := findBrew()
+	if err != nil {
+		return brewFootprint{}, err
+	}
+	prefix, err := runBrew(brew, "--prefix")
+	if err != nil {
+		return brewFootprint{}, err
+	}
+	cache, err := runBrew(brew, "--cache")
+	if err != nil {
+		return brewFootprint{}, err
+	}
+	formulae, err := runBrew(brew, "list", "--formula", "--versions")
+	if err != nil {
+		return brewFootprint{}, err
+	}
+	casks, _ := runBrew(brew, "list", "--cask", "--versions") // Fails on installs without casks
+
+	footprint := brewFootprint{Cache: strings.TrimSpace(cache)}
+	footprint.Kegs = append(parseBrewVersions(formulae, false), parseBrewVersions(casks, true)...)
+	prefix = strings.TrimSpace(prefix)
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, 8)
+	measure := func(path string, size *int64) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			if measured, err := getDirectorySizeFromDu(path); err == nil {
+				*size = measured
+			}
+		}()
+	}
+	measure(footprint.Cache, &footprint.CacheSize)
+	for i := range footprint.Kegs {
+		keg := &footprint.Kegs[i]
+		settleBrewKeg(keg, prefix)
+		for j := range keg.Versions {
+			measure(keg.Versions[j].Path, &keg.Versions[j].Size)
+		}
+	}
+	wg.Wait()
+	sortBrewKegs(footprint.Kegs)
+	return footprint, nil
+}
+
+func measureBrewFootprintCmd() tea.Cmd {
+	return func() tea.Msg {
+		footprint, err := measureBrewFootprint()
+		return brewFootprintMsg{Footprint: footprint, Err: err}
+	}
+}
+
+// brewCleanupArgs is the cleanup for one keg's old versions, or with no formula the
+// cache and every keg's, as `brew cleanup --prune=all` does.
+func brewCleanupArgs(keg *brewKeg) []string {
+	if keg == nil {
+		return []string{"cleanup", "--prune=all"}
+	}
+	if keg.Cask {
+		return []string{"cleanup", "--cask", keg.Name}
+	}
+	return []string{"cleanup", keg.Name}
+}
+
+func brewCleanupCmd(keg *brewKeg) tea.Cmd {
+	return func() tea.Msg {
+		msg := brewCleanupMsg{}
+		if keg != nil {
+			msg.Formula = keg.Name
+		}
+		brew, err := findBrew()
+		if err != nil {
+			msg.Err = err
+			return msg
+		}
+		msg.Output, msg.Err = runBrew(brew, brewCleanupArgs(keg)...)
+		return msg
+	}
+}
+
+// brewCleanupSummary is brew's own "freed approximately" line, when it printed one.
+func brewCleanupSummary(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		if strings.Contains(lines[i], "freed approximately") {
+			return strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(lines[i]), "==>"))
+		}
+	}
+	return ""
+}
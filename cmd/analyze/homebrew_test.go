@@ -0,0 +1,79 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseBrewVersions(t *testing.T) {
+	out := "git 2.44.0\nnode 20.11.0 21.6.1\n\nbroken\n"
+	kegs := parseBrewVersions(out, false)
+	if len(kegs) != 2 {
+		t.Fatalf("parsed %d kegs, want 2", len(kegs))
+	}
+	if kegs[1].Name != "node" || len(kegs[1].Versions) != 2 || kegs[1].Versions[1].Version != "21.6.1" {
+		t.Errorf("node = %+v", kegs[1])
+	}
+}
+
+func TestSettleBrewKegFollowsOptLink(t *testing.T) {
+	prefix := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(prefix, "opt"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("../Cellar/node/20.11.0", filepath.Join(prefix, "opt", "node")); err != nil {
+		t.Fatal(err)
+	}
+
+	keg := parseBrewVersions("node 21.6.1 20.11.0", false)[0]
+	settleBrewKeg(&keg, prefix)
+	if keg.Current != "20.11.0" || keg.Versions[0].Version != "20.11.0" {
+		t.Errorf("current = %s, versions = %+v; want the linked 20.11.0 first", keg.Current, keg.Versions)
+	}
+	if want := filepath.Join(prefix, "Cellar", "node", "21.6.1"); keg.Versions[1].Path != want {
+		t.Errorf("path = %s, want %s", keg.Versions[1].Path, want)
+	}
+
+	cask := parseBrewVersions("firefox 122.0 123.0", true)[0]
+	settleBrewKeg(&cask, prefix)
+	if cask.Current != "123.0" || !strings.Contains(cask.Versions[0].Path, "Caskroom") {
+		t.Errorf("cask = %+v, want the last version listed, under Caskroom", cask)
+	}
+}
+
+func TestBrewFootprintReclaimable(t *testing.T) {
+	kegs := []brewKeg{
+		{Name: "git", Current: "2.44.0", Versions: []brewVersion{{Version: "2.44.0", Size: 50}}},
+		{Name: "node", Current: "21.6.1", Versions: []brewVersion{{Version: "21.6.1", Size: 100}, {Version: "20.11.0", Size: 90}}},
+	}
+	sortBrewKegs(kegs)
+	if kegs[0].Name != "node" {
+		t.Errorf("kegs not ordered by reclaimable space: %s first", kegs[0].Name)
+	}
+	f := brewFootprint{CacheSize: 30, Kegs: kegs}
+	if f.size() != 270 || f.reclaimable() != 120 {
+		t.Errorf("size = %d, reclaimable = %d; want 270 and 120", f.size(), f.reclaimable())
+	}
+}
+
+func TestBrewCleanupArgs(t *testing.T) {
+	cases := []struct {
+		keg  *brewKeg
+		want string
+	}{
+		{nil, "cleanup --prune=all"},
+		{&brewKeg{Name: "node"}, "cleanup node"},
+		{&brewKeg{Name: "firefox", Cask: true}, "cleanup --cask firefox"},
+	}
+	for _, c := range cases {
+		if got := strings.Join(brewCleanupArgs(c.keg), " "); got != c.want {
+			t.Errorf("brewCleanupArgs = %q, want %q", got, c.want)
+		}
+	}
+	out := "Removing: /opt/homebrew/Cellar/node/20.11.0... (2,000 files, 90MB)\n==> This operation has freed approximately 90MB of disk space.\n"
+	if got := brewCleanupSummary(out); got != "This operation has freed approximately 90MB of disk space." {
+		t.Errorf("summary = %q", got)
+	}
+}
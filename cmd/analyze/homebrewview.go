@@ -0,0 +1,178 @@
+//go:build darwin
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// homebrewView is the "i" screen showing Homebrew's download cache and installed kegs,
+// with brew cleanup for one keg or everything.
+type homebrewView struct {
+	Footprint brewFootprint
+	Selected  int // 0 is the cache row, then Footprint.Kegs
+	Scanning  bool
+	Cleaning  bool
+	Confirm   string // Cleanup pending a second keypress: "enter" or "x"
+	Refresh   bool   // Measuring again after a cleanup, whose result stays in the status line
+	Err       error
+}
+
+// rows is the cache row plus one per keg.
+func (v *homebrewView) rows() int {
+	return len(v.Footprint.Kegs) + 1
+}
+
+// selectedKeg is the keg under the cursor, nil on the cache row.
+func (v *homebrewView) selectedKeg() *brewKeg {
+	if v.Selected == 0 || v.Selected > len(v.Footprint.Kegs) {
+		return nil
+	}
+	return &v.Footprint.Kegs[v.Selected-1]
+}
+
+func (m model) openHomebrew() (tea.Model, tea.Cmd) {
+	m.homebrew = &homebrewView{Scanning: true}
+	m.status = "Measuring Homebrew's cache and kegs..."
+	return m, tea.Batch(measureBrewFootprintCmd(), tickCmd())
+}
+
+// updateHomebrewKey handles keys while the Homebrew screen is open.
+func (m model) updateHomebrewKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	v := m.homebrew
+	key := msg.String()
+	confirm := v.Confirm
+	v.Confirm = ""
+	switch key {
+	case "q", "Q", "ctrl+c":
+		return m.quit()
+	case "esc", "b", "left", "h", "i":
+		if v.Cleaning {
+			m.status = "brew cleanup is running, wait for it to finish"
+			return m, nil
+		}
+		m.homebrew = nil
+	case "up", "k":
+		v.Selected = max(v.Selected-1, 0)
+	case "down", "j":
+		v.Selected = min(v.Selected+1, v.rows()-1)
+	case "r", "R":
+		if v.Scanning || v.Cleaning {
+			return m, nil
+		}
+		return m.openHomebrew()
+	case "enter", "x":
+		if v.Scanning || v.Cleaning || v.Err != nil {
+			return m, nil
+		}
+		var keg *brewKeg
+		size, target := v.Footprint.reclaimable(), "the cache and every old version"
+		if key == "enter" {
+			if keg = v.selectedKeg(); keg == nil {
+				size, target = v.Footprint.CacheSize, "the download cache"
+			} else {
+				size, target = keg.oldSize(), keg.Name+"'s old versions"
+			}
+		}
+		if keg != nil && size == 0 {
+			m.status = fmt.Sprintf("%s has no old versions to clean up", keg.Name)
+			return m, nil
+		}
+		if confirm != key {
+			v.Confirm = key
+			m.status = fmt.Sprintf("Press %s again to clean up %s (%s)", strings.ToUpper(key[:1])+key[1:], target, humanizeBytes(size))
+			return m, nil
+		}
+		v.Cleaning = true
+		m.status = "Running brew cleanup..."
+		return m, tea.Batch(brewCleanupCmd(keg), tickCmd())
+	}
+	return m, nil
+}
+
+// applyBrewCleanup reports what brew freed and measures again.
+func (m model) applyBrewCleanup(msg brewCleanupMsg) (tea.Model, tea.Cmd) {
+	if m.homebrew == nil {
+		return m, nil
+	}
+	m.homebrew.Cleaning = false
+	if msg.Err != nil {
+		m.status = msg.Err.Error()
+		return m, nil
+	}
+	status := "brew cleanup finished"
+	if summary := brewCleanupSummary(msg.Output); summary != "" {
+		status = summary
+	}
+	next, cmd := m.openHomebrew()
+	if updated, ok := next.(model); ok {
+		updated.homebrew.Refresh = true
+		updated.status = status
+		return updated, cmd
+	}
+	return next, cmd
+}
+
+// renderHomebrew lists the cache and each keg with what brew cleanup would reclaim.
+func (m model) renderHomebrew() string {
+	v := m.homebrew
+	var b strings.Builder
+	if v.Scanning || v.Cleaning {
+		action := "Measuring Homebrew's cache and kegs"
+		if v.Cleaning {
+			action = "Running brew cleanup"
+		}
+		fmt.Fprintf(&b, "%s%s%s%s %s...\n", colorCyan, colorBold, spinnerFrames[m.spinner], colorReset, action)
+		return b.String()
+	}
+	if v.Err != nil {
+		fmt.Fprintf(&b, "  %s%s%s\n\n", colorGray, v.Err.Error(), colorReset)
+		fmt.Fprintf(&b, "%si/← Back | Q Quit%s\n", colorGray, colorReset)
+		return b.String()
+	}
+
+	f := v.Footprint
+	total := f.size()
+	fmt.Fprintf(&b, "%sHomebrew:%s %s in %d kegs and the cache, %s%s%s reclaimable\n\n",
+		colorCyan, colorReset, humanizeBytes(total), len(f.Kegs), colorYellow, humanizeBytes(f.reclaimable()), colorReset)
+
+	viewport := calculateViewport(m.height, true)
+	nameWidth := calculateNameWidth(m.width)
+	start := max(0, v.Selected-viewport+1)
+	for i := start; i < min(v.rows(), start+viewport); i++ {
+		prefix, color := "   ", ""
+		if i == v.Selected {
+			prefix, color = fmt.Sprintf(" %s%s▶%s ", colorCyan, colorBold, colorReset), colorCyan
+		}
+		name, size, old, detail := "Download cache", f.CacheSize, f.CacheSize, displayPath(f.Cache)
+		if i > 0 {
+			keg := f.Kegs[i-1]
+			name, size, old, detail = keg.Name, keg.size(), keg.oldSize(), keg.Current
+			if keg.Cask {
+				detail += " (cask)"
+			}
+			if extra := len(keg.Versions) - 1; extra > 0 {
+				detail += fmt.Sprintf(", %d old", extra)
+			}
+		}
+		percent := 0.0
+		if total > 0 {
+			percent = float64(size) / float64(total) * 100
+		}
+		reclaim := ""
+		if old > 0 {
+			reclaim = humanizeBytes(old) + " free"
+		}
+		fmt.Fprintf(&b, "%s%s %5.1f%%  |  %s%s%s%s %10s  %s%-14s%s %s%s%s\n",
+			prefix, coloredProgressBar(size, total, percent), percent,
+			color, colorBold, padName(trimNameWithWidth(name, nameWidth), nameWidth), colorReset, humanizeBytes(size),
+			colorYellow, reclaim, colorReset, colorGray, detail, colorReset)
+	}
+
+	fmt.Fprintln(&b)
+	fmt.Fprintf(&b, "%s↑↓ | Enter Clean up selected | X Clean up all | R Refresh | i/← Back | Q Quit%s\n", colorGray, colorReset)
+	return b.String()
+}
@@ -0,0 +1,289 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// reportMaxChildren keeps the largest children of each folder; the rest are summed.
+	reportMaxChildren = 60
+	// reportMaxDepth is how deep the report nests before folders become leaves.
+	reportMaxDepth = 12
+)
+
+// reportNode is a folder or file in the HTML report, with short keys to keep it small.
+type reportNode struct {
+	Name     string        `json:"n"`
+	Size     int64         `json:"s"`           // Allocated bytes, rolled up
+	Apparent int64         `json:"a"`           // Logical bytes, rolled up
+	Files    int64         `json:"f"`           // Files beneath, 1 for a file
+	Dir      bool          `json:"d,omitempty"` // Folder, even when its children were cut
+	Other    int64         `json:"o,omitempty"` // Bytes of children past reportMaxChildren
+	Locked   bool          `json:"e,omitempty"` // Could not be read
+	Children []*reportNode `json:"c,omitempty"`
+}
+
+// buildReportNode rolls tree up into report nodes, redacting names like the ncdu export.
+func buildReportNode(tree *treeNode, depth int) *reportNode {
+	node := &reportNode{Name: tree.Name, Size: tree.Disk, Apparent: tree.Apparent, Dir: tree.IsDir, Locked: tree.ReadError}
+	if depth == 0 {
+		node.Name = displayPath(tree.Name)
+	} else if activeRedactor != nil && depth > activeRedactor.depth {
+		node.Name = activeRedactor.name(node.Name)
+	}
+	if !tree.IsDir {
+		node.Files = 1
+		return node
+	}
+	children := make([]*reportNode, 0, len(tree.Children))
+	for _, child := range tree.Children {
+		sub := buildReportNode(child, depth+1)
+		node.Size += sub.Size
+		node.Apparent += sub.Apparent
+		node.Files += sub.Files
+		children = append(children, sub)
+	}
+	sort.Slice(children, func(i, j int) bool { return children[i].Size > children[j].Size })
+	if depth >= reportMaxDepth {
+		return node
+	}
+	if len(children) > reportMaxChildren {
+		for _, rest := range children[reportMaxChildren:] {
+			node.Other += rest.Size
+		}
+		children = children[:reportMaxChildren]
+	}
+	node.Children = children
+	return node
+}
+
+// writeHTMLReport writes a self-contained page with a treemap and a sortable table.
+func writeHTMLReport(w io.Writer, tree *treeNode, generated time.Time) error {
+	root := buildReportNode(tree, 0)
+	data, err := json.Marshal(map[string]any{
+		"root":      root,
+		"generated": generated.Format("2006-01-02 15:04"),
+	})
+	if err != nil {
+		return err
+	}
+	// json.Marshal escapes <, > and &, so the data cannot close the script tag.
+	page := strings.NewReplacer(
+		"__MOLE_TITLE__", html.EscapeString(root.Name),
+		"__MOLE_DATA__", string(data),
+	).Replace(htmlReportTemplate)
+	_, err = io.WriteString(w, page)
+	return err
+}
+
+// exportHTMLReport scans root (or the inventory subtree) and writes the report to dest.
+func exportHTMLReport(root string, inventory *inventoryTree, dest string) error {
+	var tree *treeNode
+	if inventory != nil {
+		node, ok := inventory.lookup(root)
+		if !ok {
+			return fmt.Errorf("%s is not in the listing", root)
+		}
+		tree = treeFromInventory(node, true)
+	} else {
+		var err error
+		if tree, err = collectTree(root); err != nil {
+			return err
+		}
+	}
+	if dest == "-" {
+		return writeHTMLReport(os.Stdout, tree, time.Now())
+	}
+	if err := checkWritableSpace(dest); err != nil {
+		return fmt.Errorf("%v; write the report to an external volume, or to - to stream it", err)
+	}
+	return writeExportFile(dest, 0666, func(w io.Writer) error { return writeHTMLReport(w, tree, time.Now()) })
+}
+
+const htmlReportTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Disk usage: __MOLE_TITLE__</title>
+<style>
+body { font: 14px -apple-system, BlinkMacSystemFont, "Helvetica Neue", sans-serif; margin: 24px; color: #222; background: #fafafa; }
+h1 { font-size: 20px; margin: 0 0 4px; }
+.meta { color: #777; margin-bottom: 16px; }
+.crumbs a { color: #06c; cursor: pointer; text-decoration: none; }
+.crumbs a:hover { text-decoration: underline; }
+#map { position: relative; height: 460px; margin: 12px 0 20px; background: #eee; border-radius: 4px; overflow: hidden; }
+.cell { position: absolute; box-sizing: border-box; border: 1px solid #fff; overflow: hidden; padding: 3px 5px; font-size: 12px; color: #fff; text-shadow: 0 1px 1px rgba(0,0,0,.4); }
+.cell.dir { cursor: pointer; }
+.cell:hover { filter: brightness(1.1); }
+table { border-collapse: collapse; width: 100%; background: #fff; }
+th, td { text-align: left; padding: 6px 10px; border-bottom: 1px solid #eee; }
+th { cursor: pointer; user-select: none; background: #f3f3f3; }
+td.num, th.num { text-align: right; font-variant-numeric: tabular-nums; }
+tr.dir td.name { color: #06c; cursor: pointer; }
+.bar { display: inline-block; height: 8px; background: #7aa6d8; border-radius: 2px; vertical-align: middle; }
+</style>
+</head>
+<body>
+<h1>Disk usage: __MOLE_TITLE__</h1>
+<div class="meta" id="meta"></div>
+<div class="crumbs" id="crumbs"></div>
+<div id="map"></div>
+<table>
+<thead><tr><th data-key="n">Name</th><th class="num" data-key="s">Size</th><th class="num" data-key="a">Logical</th><th class="num" data-key="f">Files</th><th data-key="s">Share</th></tr></thead>
+<tbody id="rows"></tbody>
+</table>
+<script>
+const report = __MOLE_DATA__;
+const stack = [report.root];
+let sortKey = "s", sortDesc = true;
+
+function human(bytes) {
+  if (bytes < 1024) return bytes + " B";
+  const units = "KMGTPE";
+  let value = bytes, i = -1;
+  do { value /= 1024; i++; } while (value >= 1024 && i < units.length - 1);
+  return value.toFixed(1) + " " + units[i] + "B";
+}
+
+function text(tag, value, cls) {
+  const el = document.createElement(tag);
+  el.textContent = value;
+  if (cls) el.className = cls;
+  return el;
+}
+
+function items(node) {
+  const list = (node.c || []).filter(c => c.s > 0);
+  if (node.o > 0) list.push({ n: "(smaller items)", s: node.o, a: node.o, f: 0 });
+  return list;
+}
+
+function worst(row, side, scale) {
+  let sum = 0, hi = 0, lo = Infinity;
+  for (const r of row) { const area = r.s * scale; sum += area; hi = Math.max(hi, area); lo = Math.min(lo, area); }
+  return Math.max(side * side * hi / (sum * sum), (sum * sum) / (side * side * lo));
+}
+
+function layoutRow(row, box, scale, out) {
+  const area = row.reduce((t, r) => t + r.s * scale, 0);
+  if (box.w >= box.h) {
+    const width = area / box.h;
+    let y = box.y;
+    for (const r of row) { const h = r.s * scale / width; out.push({ item: r, x: box.x, y, w: width, h }); y += h; }
+    return { x: box.x + width, y: box.y, w: box.w - width, h: box.h };
+  }
+  const height = area / box.w;
+  let x = box.x;
+  for (const r of row) { const w = r.s * scale / height; out.push({ item: r, x, y: box.y, w, h: height }); x += w; }
+  return { x: box.x, y: box.y + height, w: box.w, h: box.h - height };
+}
+
+// squarify lays items, largest first, into rows that keep cells close to square.
+function squarify(list, box) {
+  const out = [];
+  const total = list.reduce((t, r) => t + r.s, 0);
+  if (!total || box.w <= 0 || box.h <= 0) return out;
+  const scale = box.w * box.h / total;
+  let row = [];
+  for (let i = 0; i < list.length;) {
+    const side = Math.min(box.w, box.h);
+    if (row.length === 0 || worst(row.concat([list[i]]), side, scale) <= worst(row, side, scale)) {
+      row.push(list[i]);
+      i++;
+    } else {
+      box = layoutRow(row, box, scale, out);
+      row = [];
+    }
+  }
+  if (row.length) layoutRow(row, box, scale, out);
+  return out;
+}
+
+function open(node) {
+  if (node.c && node.c.length) { stack.push(node); render(); }
+}
+
+function renderCrumbs() {
+  const crumbs = document.getElementById("crumbs");
+  crumbs.textContent = "";
+  stack.forEach((node, i) => {
+    if (i > 0) crumbs.append(" / ");
+    const link = text("a", node.n);
+    link.onclick = () => { stack.length = i + 1; render(); };
+    crumbs.append(link);
+  });
+}
+
+function renderMap(node) {
+  const map = document.getElementById("map");
+  map.textContent = "";
+  const cells = squarify(items(node), { x: 0, y: 0, w: map.clientWidth, h: map.clientHeight });
+  cells.forEach((cell, i) => {
+    const el = text("div", cell.w > 60 && cell.h > 18 ? cell.item.n + " " + human(cell.item.s) : "", "cell");
+    el.style.left = cell.x + "px";
+    el.style.top = cell.y + "px";
+    el.style.width = cell.w + "px";
+    el.style.height = cell.h + "px";
+    el.style.background = "hsl(" + ((i * 47) % 360) + ", 45%, " + (cell.item.d ? 48 : 60) + "%)";
+    el.title = cell.item.n + "\n" + human(cell.item.s);
+    if (cell.item.c && cell.item.c.length) {
+      el.classList.add("dir");
+      el.onclick = () => open(cell.item);
+    }
+    map.append(el);
+  });
+}
+
+function renderTable(node) {
+  const rows = document.getElementById("rows");
+  rows.textContent = "";
+  const list = items(node).slice().sort((x, y) => {
+    const a = x[sortKey], b = y[sortKey];
+    const order = typeof a === "string" ? a.localeCompare(b) : a - b;
+    return sortDesc ? -order : order;
+  });
+  for (const item of list) {
+    const tr = document.createElement("tr");
+    const name = text("td", item.n + (item.e ? " (unreadable)" : ""), "name");
+    if (item.c && item.c.length) { tr.className = "dir"; name.onclick = () => open(item); }
+    const share = node.s ? item.s / node.s : 0;
+    const bar = document.createElement("td");
+    const fill = document.createElement("span");
+    fill.className = "bar";
+    fill.style.width = Math.round(share * 120) + "px";
+    bar.append(fill, " " + (share * 100).toFixed(1) + "%");
+    tr.append(name, text("td", human(item.s), "num"), text("td", human(item.a), "num"),
+      text("td", item.f ? item.f.toLocaleString() : "", "num"), bar);
+    rows.append(tr);
+  }
+}
+
+function render() {
+  const node = stack[stack.length - 1];
+  document.getElementById("meta").textContent = human(node.s) + " on disk, " + human(node.a) + " logical, " +
+    node.f.toLocaleString() + " files. Scanned " + report.generated + ".";
+  renderCrumbs();
+  renderMap(node);
+  renderTable(node);
+}
+
+document.querySelectorAll("th").forEach(th => th.onclick = () => {
+  const key = th.dataset.key;
+  sortDesc = key === sortKey ? !sortDesc : key !== "n";
+  sortKey = key;
+  renderTable(stack[stack.length - 1]);
+});
+window.onresize = () => renderMap(stack[stack.length - 1]);
+render();
+</script>
+</body>
+</html>
+`
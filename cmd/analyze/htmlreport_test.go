@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildReportNodeRollsUpAndCaps(t *testing.T) {
+	root := &treeNode{Name: "/data", IsDir: true}
+	for i := 0; i < reportMaxChildren+2; i++ {
+		root.Children = append(root.Children, &treeNode{Name: fmt.Sprintf("f%d", i), Disk: int64(1000 + i), Apparent: 10})
+	}
+	sub := &treeNode{Name: "sub", IsDir: true, Disk: 0, Children: []*treeNode{{Name: "big", Disk: 1 << 20, Apparent: 1 << 20}}}
+	root.Children = append(root.Children, sub)
+
+	node := buildReportNode(root, 0)
+	if node.Files != reportMaxChildren+3 {
+		t.Errorf("files = %d", node.Files)
+	}
+	if len(node.Children) != reportMaxChildren || node.Children[0].Name != "sub" || node.Children[0].Size != 1<<20 {
+		t.Fatalf("children = %d, first %+v", len(node.Children), node.Children[0])
+	}
+	// The three smallest files, f0 to f2, are summed past the cap.
+	if node.Other != 1000+1001+1002 {
+		t.Errorf("other = %d", node.Other)
+	}
+	var total int64
+	for _, child := range node.Children {
+		total += child.Size
+	}
+	if node.Size != total+node.Other {
+		t.Errorf("size %d should equal children %d plus other %d", node.Size, total, node.Other)
+	}
+}
+
+func TestWriteHTMLReportEscapesNames(t *testing.T) {
+	tree := &treeNode{Name: "/tmp/<b>", IsDir: true, Children: []*treeNode{{Name: "</script><script>alert(1)", Disk: 4096}}}
+	var out bytes.Buffer
+	if err := writeHTMLReport(&out, tree, time.Date(2026, 1, 2, 3, 4, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+	page := out.String()
+	if strings.Count(page, "</script>") != 1 {
+		t.Error("a file name must not be able to close the script tag")
+	}
+	if !strings.Contains(page, "<title>Disk usage: /tmp/&lt;b&gt;</title>") {
+		t.Error("the title should be HTML-escaped")
+	}
+	start := strings.Index(page, "const report = ") + len("const report = ")
+	end := strings.Index(page[start:], ";\n")
+	var data struct {
+		Root      reportNode `json:"root"`
+		Generated string     `json:"generated"`
+	}
+	if err := json.Unmarshal([]byte(page[start:start+end]), &data); err != nil {
+		t.Fatal(err)
+	}
+	if data.Root.Size != 4096 || data.Generated != "2026-01-02 03:04" {
+		t.Errorf("data = %+v", data)
+	}
+}
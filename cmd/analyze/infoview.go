@@ -0,0 +1,38 @@
+//go:build darwin
+
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// infoPanelLines is how many rows the Tab panel takes below the list.
+const infoPanelLines = 11
+
+// listHeight is the terminal height left for the list while the info panel is open.
+func (m model) listHeight() int {
+	if !m.showInfo || m.height <= 0 {
+		return m.height
+	}
+	return max(m.height-infoPanelLines, 1)
+}
+
+// renderInfoPanel shows the selected entry's details, read fresh on every frame.
+func (m model) renderInfoPanel() string {
+	entry, ok := m.selectedEntry()
+	if !ok || m.inOverviewMode() && entry.Size < 0 {
+		return ""
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n%sInfo%s %s(Tab to close)%s\n", colorCyan, colorReset, colorGray, colorReset)
+	details, err := readFileDetails(entry)
+	if err != nil {
+		fmt.Fprintf(&b, "  %s%v%s\n", colorGray, err, colorReset)
+		return b.String()
+	}
+	for _, line := range details.lines() {
+		fmt.Fprintf(&b, "  %s%-15s%s %s\n", colorGray, line[0], colorReset, line[1])
+	}
+	return b.String()
+}
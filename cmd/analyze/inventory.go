@@ -0,0 +1,225 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// inventoryNode is one path in a pre-generated listing (remote host, saved file).
+type inventoryNode struct {
+	Name     string
+	Path     string
+	Size     int64
+	IsDir    bool
+	Children map[string]*inventoryNode
+	Files    int64 // Files beneath a directory, filled in by finalize
+	Dirs     int64 // Folders beneath a directory, filled in by finalize
+}
+
+// inventoryTree lets the TUI browse a listing without touching the local disk.
+type inventoryTree struct {
+	Source string
+	Root   *inventoryNode
+	nodes  map[string]*inventoryNode
+}
+
+func newInventoryTree(source, root string) *inventoryTree {
+	root = cleanInventoryPath(root)
+	node := &inventoryNode{Name: filepath.Base(root), Path: root, IsDir: true, Size: -1, Children: make(map[string]*inventoryNode)}
+	return &inventoryTree{Source: source, Root: node, nodes: map[string]*inventoryNode{root: node}}
+}
+
+func cleanInventoryPath(path string) string {
+	if path == "" {
+		return "/"
+	}
+	return filepath.Clean(path)
+}
+
+// inventoryContains reports whether path is root or beneath it; a "." root holds
+// every relative path, as in `find . -ls` output.
+func inventoryContains(root, path string) bool {
+	if root == "." {
+		return !filepath.IsAbs(path) && path != ".." && !strings.HasPrefix(path, "../")
+	}
+	return path == root || strings.HasPrefix(path, strings.TrimSuffix(root, "/")+"/")
+}
+
+// add records a path, creating intermediate directories as needed.
+func (t *inventoryTree) add(path string, size int64, isDir bool) {
+	path = cleanInventoryPath(path)
+	if !inventoryContains(t.Root.Path, path) {
+		return
+	}
+	node := t.ensure(path, isDir)
+	if !isDir {
+		node.Size = size
+	}
+}
+
+func (t *inventoryTree) ensure(path string, isDir bool) *inventoryNode {
+	if node, ok := t.nodes[path]; ok {
+		if isDir && !node.IsDir {
+			node.IsDir = true
+			node.Children = make(map[string]*inventoryNode)
+		}
+		return node
+	}
+	parent := t.ensure(filepath.Dir(path), true)
+	node := &inventoryNode{Name: filepath.Base(path), Path: path, IsDir: isDir}
+	if isDir {
+		node.Children = make(map[string]*inventoryNode)
+	}
+	parent.Children[node.Name] = node
+	t.nodes[path] = node
+	return node
+}
+
+// finalize rolls file sizes up into directory totals.
+func (t *inventoryTree) finalize() {
+	var sum func(*inventoryNode) int64
+	sum = func(node *inventoryNode) int64 {
+		if !node.IsDir {
+			node.Files = 1
+			return max(node.Size, 0)
+		}
+		var total int64
+		node.Files, node.Dirs = 0, 0
+		for _, child := range node.Children {
+			total += sum(child)
+			node.Files += child.Files
+			if child.IsDir {
+				node.Dirs += child.Dirs + 1
+			}
+		}
+		node.Size = total
+		return total
+	}
+	sum(t.Root)
+}
+
+func (t *inventoryTree) lookup(path string) (*inventoryNode, bool) {
+	node, ok := t.nodes[cleanInventoryPath(path)]
+	return node, ok
+}
+
+// scanResultFor builds the same view data the disk scanner would produce.
+func (t *inventoryTree) scanResultFor(path string) (scanResult, error) {
+	node, ok := t.lookup(path)
+	if !ok || !node.IsDir {
+		return scanResult{}, fmt.Errorf("%s is not in the %s listing", path, t.Source)
+	}
+
+	entries := make([]dirEntry, 0, len(node.Children))
+	for _, child := range node.Children {
+		entries = append(entries, dirEntry{Name: child.Name, Path: child.Path, Size: child.Size, IsDir: child.IsDir, Files: child.Files, Dirs: child.Dirs})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Size > entries[j].Size })
+	entries, omitted, omittedSize := capEntries(entries)
+
+	var largeFiles []fileEntry
+	var collect func(*inventoryNode)
+	collect = func(n *inventoryNode) {
+		for _, child := range n.Children {
+			if child.IsDir {
+				collect(child)
+			} else if child.Size >= minLargeFileSize && !shouldSkipFileForLargeTracking(child.Path) {
+				largeFiles = append(largeFiles, fileEntry{Name: child.Name, Path: child.Path, Size: child.Size})
+			}
+		}
+	}
+	collect(node)
+	sort.Slice(largeFiles, func(i, j int) bool { return largeFiles[i].Size > largeFiles[j].Size })
+	if len(largeFiles) > maxLargeFiles {
+		largeFiles = largeFiles[:maxLargeFiles]
+	}
+
+	return scanResult{Entries: entries, LargeFiles: largeFiles, TotalSize: node.Size, Omitted: omitted, OmittedSize: omittedSize}, nil
+}
+
+// parseInventoryLine reads "size<TAB>blocks<TAB>type<TAB>path" as written by GNU find -printf,
+// BSD stat -f, or writeInventory.
+func parseInventoryLine(line string) (path string, size int64, isDir bool, ok bool) {
+	fields := strings.SplitN(line, "\t", 4)
+	if len(fields) != 4 || fields[3] == "" {
+		return "", 0, false, false
+	}
+	logical, err := strconv.ParseInt(strings.TrimSpace(fields[0]), 10, 64)
+	if err != nil {
+		return "", 0, false, false
+	}
+	size = logical
+	if blocks, err := strconv.ParseInt(strings.TrimSpace(fields[1]), 10, 64); err == nil && blocks*512 < logical {
+		// Same rule as getActualFileSize: sparse/cloud files count allocated bytes.
+		size = blocks * 512
+	}
+	kind := strings.TrimSpace(fields[2])
+	isDir = kind == "d" || kind == "Directory"
+	return unquoteInventoryPath(fields[3]), size, isDir, true
+}
+
+// readInventory builds a tree from a line-oriented inventory stream.
+func readInventory(r io.Reader, source, root string) (*inventoryTree, error) {
+	tree := newInventoryTree(source, root)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	lines := 0
+	for scanner.Scan() {
+		path, size, isDir, ok := parseInventoryLine(scanner.Text())
+		if !ok {
+			continue
+		}
+		tree.add(path, size, isDir)
+		lines++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if lines == 0 {
+		return nil, fmt.Errorf("no entries received from %s", source)
+	}
+	tree.finalize()
+	return tree, nil
+}
+
+// writeInventory emits the inventory format for root; used as the remote helper.
+func writeInventory(w io.Writer, root string) error {
+	out := bufio.NewWriter(w)
+	defer out.Flush()
+	return filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if d != nil && d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		var blocks int64
+		if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+			blocks = stat.Blocks
+		}
+		kind := "f"
+		switch {
+		case d.IsDir():
+			kind = "d"
+		case d.Type()&os.ModeSymlink != 0:
+			kind = "l"
+		}
+		if activeRedactor != nil {
+			p = activeRedactor.redactUnder(root, p)
+		}
+		_, err = fmt.Fprintf(out, "%d\t%d\t%s\t%s\n", info.Size(), blocks, kind, quoteInventoryPath(p))
+		return err
+	})
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadInventoryBuildsTree(t *testing.T) {
+	listing := strings.Join([]string{
+		"4096\t8\td\t/srv/data",
+		"4096\t8\td\t/srv/data/logs",
+		"1000\t8\tf\t/srv/data/logs/app.log",
+		"209715200\t409600\tRegular File\t/srv/data/backup.tar",
+		"2048\t0\tf\t/srv/data/sparse.img",
+		"garbage line",
+		"10\t8\tf\t/elsewhere/ignored",
+	}, "\n")
+
+	tree, err := readInventory(strings.NewReader(listing), "ssh://host", "/srv/data")
+	if err != nil {
+		t.Fatalf("readInventory: %v", err)
+	}
+
+	result, err := tree.scanResultFor("/srv/data")
+	if err != nil {
+		t.Fatalf("scanResultFor: %v", err)
+	}
+	if len(result.Entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(result.Entries))
+	}
+	if result.Entries[0].Name != "backup.tar" {
+		t.Fatalf("expected largest entry first, got %s", result.Entries[0].Name)
+	}
+	wantTotal := int64(209715200 + 1000)
+	if result.TotalSize != wantTotal {
+		t.Fatalf("expected total %d (sparse file counts 0 blocks), got %d", wantTotal, result.TotalSize)
+	}
+	if len(result.LargeFiles) != 1 {
+		t.Fatalf("expected 1 large file, got %d", len(result.LargeFiles))
+	}
+	if _, err := tree.scanResultFor("/srv/data/backup.tar"); err == nil {
+		t.Fatalf("expected error when browsing into a file")
+	}
+}
+
+func TestWriteInventoryRoundTrip(t *testing.T) {
+	base := t.TempDir()
+	writeFileWithSize(t, filepath.Join(base, "a", "one.bin"), 100)
+	writeFileWithSize(t, filepath.Join(base, "two.bin"), 200)
+
+	var buf bytes.Buffer
+	if err := writeInventory(&buf, base); err != nil {
+		t.Fatalf("writeInventory: %v", err)
+	}
+	tree, err := readInventory(&buf, "file", base)
+	if err != nil {
+		t.Fatalf("readInventory: %v", err)
+	}
+	want, _ := getDirectoryLogicalSizeWithExclude(base, "")
+	if tree.Root.Size != want {
+		t.Fatalf("expected root size %d, got %d", want, tree.Root.Size)
+	}
+}
+
+func TestParseSSHTargetAndQuote(t *testing.T) {
+	host, path, err := parseSSHTarget("me@box:/var/log")
+	if err != nil || host != "me@box" || path != "/var/log" {
+		t.Fatalf("parseSSHTarget = %q %q %v", host, path, err)
+	}
+	if _, path, _ := parseSSHTarget("box"); path != "." {
+		t.Fatalf("expected default path '.', got %q", path)
+	}
+	if _, _, err := parseSSHTarget(":/x"); err == nil {
+		t.Fatalf("expected error for missing host")
+	}
+	if _, _, err := parseSSHTarget("-oProxyCommand=touch /tmp/x:/"); err == nil {
+		t.Fatalf("expected error for a host that ssh would read as an option")
+	}
+	if got := shellQuote("it's"); got != `'it'"'"'s'` {
+		t.Fatalf("shellQuote = %s", got)
+	}
+}
+
+func TestRemoteInventoryScriptDropsFailedMoleListing(t *testing.T) {
+	base := t.TempDir()
+	writeFileWithSize(t, filepath.Join(base, "data", "one.bin"), 100)
+
+	run := func(mo string) string {
+		t.Helper()
+		bin := t.TempDir()
+		if err := os.WriteFile(filepath.Join(bin, "mo"), []byte("#!/bin/sh\n"+mo+"\n"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		cmd := exec.Command("sh", "-c", remoteInventoryScript(filepath.Join(base, "data")))
+		cmd.Env = append(os.Environ(), "PATH="+bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+		out, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("script failed: %v", err)
+		}
+		return string(out)
+	}
+
+	if out := run("echo partial; exit 1"); strings.Contains(out, "partial") || !strings.Contains(out, "one.bin") {
+		t.Fatalf("expected only the find listing after mo failed, got:\n%s", out)
+	}
+	if out := run("echo from-mole"); !strings.Contains(out, "from-mole") || strings.Contains(out, "one.bin") {
+		t.Fatalf("expected only the mo listing, got:\n%s", out)
+	}
+}
@@ -0,0 +1,29 @@
+//go:build darwin
+
+package main
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// Disk I/O policies from <sys/resource.h>.
+const (
+	iopolTypeDisk = 0
+	iopolDefault  = 0
+	iopolThrottle = 3
+)
+
+// setDiskThrottled moves this process's disk I/O to the throttled tier, the one Time
+// Machine and Spotlight use, or back to the default tier.
+func setDiskThrottled(throttled bool) error {
+	policy := int32(iopolDefault)
+	if throttled {
+		policy = iopolThrottle
+	}
+	param := iopolicyParam{scope: iopolScopeProcess, iotype: iopolTypeDisk, policy: policy}
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOPOLICYSYS, iopolCmdSet, uintptr(unsafe.Pointer(&param)), 0); errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,8 @@
+//go:build !darwin
+
+package main
+
+// setDiskThrottled is a no-op without Darwin's I/O policy tiers.
+func setDiskThrottled(throttled bool) error {
+	return nil
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// keyActions names the remappable actions and the built-in key each one stands for.
+// A [keys] table in config.toml binds extra keys to them:
+//
+//	[keys]
+//	up = "i"
+//	delete = ["d", "ctrl+d"]
+//	large_files = "L"
+var keyActions = map[string]string{
+	"up":               "up",
+	"down":             "down",
+	"enter":            "enter",
+	"enter_package":    "alt+enter",
+	"back":             "left",
+	"page_up":          "pgup",
+	"page_down":        "pgdown",
+	"first":            "home",
+	"last":             "end",
+	"quit":             "q",
+	"cancel":           "esc",
+	"delete":           "delete",
+	"delete_permanent": "alt+delete",
+	"open":             "o",
+	"quick_look":       "ctrl+y",
+	"info":             "tab",
+	"reveal":           "f",
+	"large_files":      "t",
+	"full_listing":     "n",
+	"larger_files":     "+",
+	"all_file_types":   "V",
+	"recent_files":     "N",
+	"smaller_files":    "-",
+	"refresh":          "r",
+	"select":           " ",
+	"select_all":       "a",
+	"select_matches":   "ctrl+a",
+	"select_orphaned":  "u",
+	"filter":           "/",
+	"focus":            "w",
+	"sort":             "s",
+	"explain":          "?",
+	"drill":            "g",
+	"exact_size":       "x",
+	"export":           "e",
+	"pin":              "P",
+	"duplicates":       "d",
+	"versions":         "v",
+	"backups":          "B",
+	"snapshots":        "L",
+	"storage":          "C",
+	"clean_preview":    "p",
+	"homebrew":         "i",
+	"package_caches":   "Y",
+	"uninstall":        "ctrl+u",
+	"evict":            "J",
+	"app_data":         "K",
+	"size_providers":   "I",
+	"stale":            "U",
+	"unreadable":       "!",
+	"compress":         "%",
+	"move_to_volume":   ">",
+	"alerts":           "W",
+	"history_diff":     "y",
+	"migration":        "M",
+	"saved_searches":   "m",
+	"organize":         "ctrl+o",
+	"stage":            "z",
+	"staging":          "Z",
+	"shared":           "H",
+	"counts":           "c",
+	"apparent_size":    "A",
+	"empty_trash":      "E",
+	"actions":          "ctrl+k",
+	"palette":          ":",
+}
+
+// keyRemap maps keys bound in config.toml to the built-in key of their action.
+var keyRemap map[string]string
+
+// namedKeyTypes resolves names such as "enter" or "ctrl+k" the way tea.KeyMsg prints them.
+var namedKeyTypes = func() map[string]tea.KeyType {
+	names := make(map[string]tea.KeyType)
+	for k := tea.KeyType(-128); k <= 127; k++ {
+		if name := k.String(); name != "" && k != tea.KeyRunes {
+			names[name] = k
+		}
+	}
+	return names
+}()
+
+// normalizeKeyName accepts a printable character, "space", a named key, or alt+ one of those.
+func normalizeKeyName(name string) (string, bool) {
+	if name == "space" {
+		return " ", true
+	}
+	if rest, ok := strings.CutPrefix(name, "alt+"); ok {
+		base, ok := normalizeKeyName(rest)
+		return "alt+" + base, ok
+	}
+	if len([]rune(name)) == 1 {
+		return name, true
+	}
+	_, ok := namedKeyTypes[name]
+	return name, ok
+}
+
+// parseKeyBindings validates a [keys] table: known actions, real keys, no key bound twice.
+func parseKeyBindings(bindings map[string][]string) (map[string]string, error) {
+	actions := make([]string, 0, len(bindings))
+	for action := range bindings {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+
+	remap := make(map[string]string)
+	owner := make(map[string]string)
+	for _, action := range actions {
+		target, ok := keyActions[action]
+		if !ok {
+			return nil, fmt.Errorf("keys.%s: unknown action", action)
+		}
+		for _, name := range bindings[action] {
+			key, ok := normalizeKeyName(name)
+			if !ok {
+				return nil, fmt.Errorf("keys.%s: %q is not a key", action, name)
+			}
+			if other, taken := owner[key]; taken && other != action {
+				return nil, fmt.Errorf("keys.%s: %q is already bound to %s", action, name, other)
+			}
+			owner[key] = action
+			if key != target {
+				remap[key] = target
+			}
+		}
+	}
+	return remap, nil
+}
+
+// keyMsgFor builds the key event the built-in handlers expect for key; action palette
+// entries and remapped keys replay through it.
+func keyMsgFor(key string) tea.KeyMsg {
+	alt := false
+	if rest, ok := strings.CutPrefix(key, "alt+"); ok {
+		key, alt = rest, true
+	}
+	if key == " " {
+		return tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}, Alt: alt}
+	}
+	if k, ok := namedKeyTypes[key]; ok {
+		return tea.KeyMsg{Type: k, Alt: alt}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key), Alt: alt}
+}
+
+// remapKey swaps a user-bound key for the built-in key of its action.
+func remapKey(msg tea.KeyMsg) tea.KeyMsg {
+	if target, ok := keyRemap[msg.String()]; ok {
+		return keyMsgFor(target)
+	}
+	return msg
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestParseKeyBindings(t *testing.T) {
+	remap, err := parseKeyBindings(map[string][]string{
+		"up":          {"i"},
+		"delete":      {"d", "ctrl+d"},
+		"select":      {"space"},
+		"large_files": {"alt+l"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if remap["i"] != "up" || remap["d"] != "delete" || remap["ctrl+d"] != "delete" || remap["alt+l"] != "t" {
+		t.Fatalf("unexpected remap: %v", remap)
+	}
+	if _, ok := remap[" "]; ok {
+		t.Fatalf("binding an action to its own key should not remap: %v", remap)
+	}
+
+	for _, bindings := range []map[string][]string{
+		{"launch": {"x"}},
+		{"up": {"hyper+q"}},
+		{"up": {"i"}, "down": {"i"}},
+	} {
+		if _, err := parseKeyBindings(bindings); err == nil {
+			t.Fatalf("expected %v to be rejected", bindings)
+		}
+	}
+}
+
+func TestRemapKey(t *testing.T) {
+	defer func() { keyRemap = nil }()
+	keyRemap = map[string]string{"i": "up", "d": "delete", "ctrl+d": "alt+delete", "S": " "}
+	for key, want := range map[string]string{"i": "up", "d": "delete", "S": " ", "q": "q"} {
+		if got := remapKey(keyMsgFor(key)).String(); got != want {
+			t.Fatalf("remapKey(%q) = %q; want %q", key, got, want)
+		}
+	}
+	if got := remapKey(tea.KeyMsg{Type: tea.KeyCtrlD}).String(); got != "alt+delete" {
+		t.Fatalf("ctrl+d should become alt+delete, got %q", got)
+	}
+	if _, err := decodeUserConfig(map[string]configValue{"keys.delete": int64(1)}); err == nil || !strings.Contains(err.Error(), "keys.delete") {
+		t.Fatalf("expected keys.delete type error, got %v", err)
+	}
+}
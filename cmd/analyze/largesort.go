@@ -0,0 +1,111 @@
+package main
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// largeSortMode orders the large-files list; size is the default.
+type largeSortMode int
+
+const (
+	largeSortSize largeSortMode = iota
+	largeSortAge
+	largeSortPath
+	largeSortType
+	largeSortModes
+)
+
+func (s largeSortMode) label() string {
+	switch s {
+	case largeSortAge:
+		return "Oldest"
+	case largeSortPath:
+		return "Path"
+	case largeSortType:
+		return "Type"
+	default:
+		return "Size"
+	}
+}
+
+func (s largeSortMode) next() largeSortMode {
+	return (s + 1) % largeSortModes
+}
+
+// fileType groups files by lowercase extension for the type sort.
+func fileType(name string) string {
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(name), "."))
+	if ext == "" {
+		return "other"
+	}
+	return ext
+}
+
+// sortLargeFiles reorders files in place; ties always fall back to size.
+func sortLargeFiles(files []fileEntry, mode largeSortMode) []fileEntry {
+	sort.SliceStable(files, func(i, j int) bool {
+		a, b := files[i], files[j]
+		switch mode {
+		case largeSortAge:
+			if !a.ModTime.Equal(b.ModTime) {
+				// Unknown times sink to the bottom.
+				if a.ModTime.IsZero() || b.ModTime.IsZero() {
+					return b.ModTime.IsZero()
+				}
+				return a.ModTime.Before(b.ModTime)
+			}
+		case largeSortPath:
+			if da, db := filepath.Dir(a.Path), filepath.Dir(b.Path); da != db {
+				return da < db
+			}
+		case largeSortType:
+			if ta, tb := fileType(a.Name), fileType(b.Name); ta != tb {
+				return ta < tb
+			}
+		}
+		return a.Size > b.Size
+	})
+	return files
+}
+
+// dirSubtotal sums the large files sharing a parent folder.
+type dirSubtotal struct {
+	Count int
+	Size  int64
+}
+
+func largeDirSubtotals(files []fileEntry) map[string]dirSubtotal {
+	totals := make(map[string]dirSubtotal)
+	for _, file := range files {
+		dir := filepath.Dir(file.Path)
+		total := totals[dir]
+		total.Count++
+		total.Size += file.Size
+		totals[dir] = total
+	}
+	return totals
+}
+
+// visibleLargeRange fits rows plus folder headers into viewport lines, keeping selected on screen.
+func visibleLargeRange(files []fileEntry, start, selected, viewport int, grouped bool) (int, int) {
+	start = max(min(start, selected), 0)
+	for {
+		end, lines := start, 0
+		for ; end < len(files); end++ {
+			need := 1
+			if grouped && (end == start || filepath.Dir(files[end].Path) != filepath.Dir(files[end-1].Path)) {
+				need++
+			}
+			if lines+need > viewport {
+				break
+			}
+			lines += need
+		}
+		if selected < end || start >= selected {
+			return start, max(end, start+1)
+		}
+		start++
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func sampleLargeFiles() []fileEntry {
+	now := time.Now()
+	return []fileEntry{
+		{Name: "movie.mov", Path: "/u/Movies/movie.mov", Size: 900, ModTime: now.Add(-time.Hour)},
+		{Name: "disk.img", Path: "/u/VMs/disk.img", Size: 800, ModTime: now.Add(-720 * time.Hour)},
+		{Name: "clip.mov", Path: "/u/Movies/clip.mov", Size: 300, ModTime: now.Add(-48 * time.Hour)},
+		{Name: "remote.bin", Path: "/u/Remote/remote.bin", Size: 500},
+	}
+}
+
+func largeNames(files []fileEntry) []string {
+	names := make([]string, len(files))
+	for i, f := range files {
+		names[i] = f.Name
+	}
+	return names
+}
+
+func TestSortLargeFilesModes(t *testing.T) {
+	cases := []struct {
+		mode largeSortMode
+		want []string
+	}{
+		{largeSortSize, []string{"movie.mov", "disk.img", "remote.bin", "clip.mov"}},
+		{largeSortAge, []string{"disk.img", "clip.mov", "movie.mov", "remote.bin"}},
+		{largeSortPath, []string{"movie.mov", "clip.mov", "remote.bin", "disk.img"}},
+		{largeSortType, []string{"remote.bin", "disk.img", "movie.mov", "clip.mov"}},
+	}
+	for _, tc := range cases {
+		got := largeNames(sortLargeFiles(sampleLargeFiles(), tc.mode))
+		for i := range tc.want {
+			if got[i] != tc.want[i] {
+				t.Fatalf("%s: expected %v, got %v", tc.mode.label(), tc.want, got)
+			}
+		}
+	}
+}
+
+func TestLargeDirSubtotals(t *testing.T) {
+	totals := largeDirSubtotals(sampleLargeFiles())
+	if movies := totals["/u/Movies"]; movies.Count != 2 || movies.Size != 1200 {
+		t.Fatalf("unexpected Movies subtotal %+v", movies)
+	}
+}
+
+func TestVisibleLargeRangeCountsHeaders(t *testing.T) {
+	files := sortLargeFiles(sampleLargeFiles(), largeSortPath)
+	// Movies header + 2 rows + Remote header = 4 lines, so only 3 files fit in 5.
+	start, end := visibleLargeRange(files, 0, 0, 5, true)
+	if start != 0 || end != 3 {
+		t.Fatalf("expected rows 0-3, got %d-%d", start, end)
+	}
+	start, end = visibleLargeRange(files, 0, 3, 5, true)
+	if start > 3 || end <= 3 {
+		t.Fatalf("expected selected row 3 visible, got %d-%d", start, end)
+	}
+}
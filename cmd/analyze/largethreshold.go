@@ -0,0 +1,54 @@
+package main
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// largeThresholdSteps are the sizes + and - step through in the large files list.
+var largeThresholdSteps = []int64{10 << 20, 25 << 20, 50 << 20, 100 << 20, 250 << 20, 500 << 20, 1 << 30, 2 << 30, 5 << 30, 10 << 30}
+
+// nextLargeThreshold returns the step above or below current, which need not be a step itself.
+func nextLargeThreshold(current int64, up bool) int64 {
+	if up {
+		for _, step := range largeThresholdSteps {
+			if step > current {
+				return step
+			}
+		}
+		return current
+	}
+	for i := len(largeThresholdSteps) - 1; i >= 0; i-- {
+		if step := largeThresholdSteps[i]; step < current {
+			return step
+		}
+	}
+	return current
+}
+
+// filterLargeFiles returns the files of at least minSize, less the ones the recent files
+// filter hides, leaving files untouched.
+func filterLargeFiles(files []fileEntry, minSize int64) []fileEntry {
+	kept := make([]fileEntry, 0, len(files))
+	for _, file := range files {
+		if file.Size >= minSize && !hiddenAsRecent(file.Created, file.ModTime) {
+			kept = append(kept, file)
+		}
+	}
+	return kept
+}
+
+// largeRequeryMsg carries large files found again after the threshold was lowered.
+// Files is nil when Spotlight had nothing, so only a rescan can find them.
+type largeRequeryMsg struct {
+	Path      string
+	Threshold int64
+	Files     []fileEntry
+}
+
+// requeryLargeFilesCmd asks Spotlight for files under root down to threshold; the scan
+// only kept files above the threshold it ran with.
+func requeryLargeFilesCmd(root string, threshold int64) tea.Cmd {
+	return func() tea.Msg {
+		return largeRequeryMsg{Path: root, Threshold: threshold, Files: findLargeFilesWithSpotlight(root, threshold)}
+	}
+}
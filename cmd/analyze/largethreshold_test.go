@@ -0,0 +1,34 @@
+package main
+
+import "testing"
+
+func TestNextLargeThreshold(t *testing.T) {
+	tests := []struct {
+		current int64
+		up      bool
+		want    int64
+	}{
+		{100 << 20, true, 250 << 20},
+		{100 << 20, false, 50 << 20},
+		{300 << 20, false, 250 << 20}, // A configured size between steps snaps to the next one
+		{300 << 20, true, 500 << 20},
+		{10 << 20, false, 10 << 20},
+		{10 << 30, true, 10 << 30},
+	}
+	for _, tc := range tests {
+		if got := nextLargeThreshold(tc.current, tc.up); got != tc.want {
+			t.Errorf("nextLargeThreshold(%d, %v) = %d, want %d", tc.current, tc.up, got, tc.want)
+		}
+	}
+}
+
+func TestFilterLargeFilesKeepsSource(t *testing.T) {
+	files := []fileEntry{{Name: "big", Size: 2 << 30}, {Name: "mid", Size: 300 << 20}, {Name: "small", Size: 120 << 20}}
+	kept := filterLargeFiles(files, 250<<20)
+	if len(kept) != 2 || kept[0].Name != "big" || kept[1].Name != "mid" {
+		t.Fatalf("unexpected files kept: %+v", kept)
+	}
+	if len(files) != 3 || files[2].Name != "small" {
+		t.Fatalf("source list should be left alone: %+v", files)
+	}
+}
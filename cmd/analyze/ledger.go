@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ledgerFile is the log shared with the shell commands (lib/core/log.sh).
+const ledgerFile = "mole.log"
+
+// appendLedger records one line in the shared log, in the shell's "[time] LEVEL: msg" format.
+func appendLedger(level, message string) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return
+	}
+	file, err := os.OpenFile(filepath.Join(configDir, ledgerFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+	fmt.Fprintf(file, "[%s] %s: %s\n", time.Now().Format("2006-01-02 15:04:05"), level, message)
+}
+
+// recordDeletions adds one ledger line per removed path.
+func recordDeletions(paths []string, trashed bool) {
+	verb := "deleted"
+	if trashed {
+		verb = "moved to Trash"
+	}
+	for _, path := range paths {
+		appendLedger("SUCCESS", fmt.Sprintf("analyze %s %s", verb, path))
+	}
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// inventoryRecord is one path read from a saved listing.
+type inventoryRecord struct {
+	Path  string
+	Size  int64
+	IsDir bool
+}
+
+// loadInventory reads a listing captured elsewhere: ncdu, gdu or mo --export-ncdu JSON,
+// mo --inventory lines, or `find -ls` output. The root is the deepest folder holding
+// every path, so the browser opens where the capture started.
+func loadInventory(r io.Reader, source string) (*inventoryTree, error) {
+	reader := bufio.NewReaderSize(r, 64*1024)
+	var records []inventoryRecord
+	var err error
+	if first, _ := firstNonSpace(reader); first == '[' {
+		records, err = readNcduRecords(reader)
+	} else {
+		records, err = readLineRecords(reader)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", source, err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("no entries found in %s", source)
+	}
+
+	root := cleanInventoryPath(records[0].Path)
+	if !records[0].IsDir {
+		root = filepath.Dir(root)
+	}
+	for _, record := range records[1:] {
+		path := cleanInventoryPath(record.Path)
+		for !inventoryContains(root, path) && root != filepath.Dir(root) {
+			root = filepath.Dir(root)
+		}
+	}
+	tree := newInventoryTree(source, root)
+	for _, record := range records {
+		tree.add(record.Path, record.Size, record.IsDir)
+	}
+	tree.finalize()
+	return tree, nil
+}
+
+func firstNonSpace(r *bufio.Reader) (byte, error) {
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		if b != ' ' && b != '\t' && b != '\n' && b != '\r' {
+			return b, r.UnreadByte()
+		}
+	}
+}
+
+// readLineRecords accepts mo --inventory lines and find -ls lines, mixed freely.
+func readLineRecords(r io.Reader) ([]inventoryRecord, error) {
+	var records []inventoryRecord
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if path, size, isDir, ok := parseInventoryLine(line); ok {
+			records = append(records, inventoryRecord{Path: path, Size: size, IsDir: isDir})
+		} else if record, ok := parseFindLsLine(line); ok {
+			records = append(records, record)
+		}
+	}
+	return records, scanner.Err()
+}
+
+// parseFindLsLine reads "inode blocks mode links owner group size month day time path",
+// the layout shared by GNU and BSD find -ls. Blocks are 1 KiB units.
+func parseFindLsLine(line string) (inventoryRecord, bool) {
+	fields, path := splitLeadingFields(line, 10)
+	if len(fields) < 10 || path == "" || len(fields[2]) < 10 {
+		return inventoryRecord{}, false
+	}
+	size, err := strconv.ParseInt(fields[6], 10, 64)
+	if err != nil {
+		return inventoryRecord{}, false
+	}
+	if blocks, err := strconv.ParseInt(fields[1], 10, 64); err == nil && blocks*1024 < size {
+		size = blocks * 1024
+	}
+	switch fields[2][0] {
+	case 'd':
+		return inventoryRecord{Path: path, IsDir: true}, true
+	case 'l':
+		if target := strings.Index(path, " -> "); target >= 0 {
+			path = path[:target]
+		}
+	}
+	return inventoryRecord{Path: path, Size: size}, true
+}
+
+// splitLeadingFields splits off n whitespace-separated fields and returns the rest
+// verbatim, so paths keep their inner spaces.
+func splitLeadingFields(line string, n int) ([]string, string) {
+	var fields []string
+	rest := line
+	for len(fields) < n {
+		rest = strings.TrimLeft(rest, " \t")
+		end := strings.IndexAny(rest, " \t")
+		if end < 0 {
+			if rest != "" {
+				fields = append(fields, rest)
+			}
+			return fields, ""
+		}
+		fields = append(fields, rest[:end])
+		rest = rest[end:]
+	}
+	return fields, strings.TrimLeft(rest, " \t")
+}
+
+// readNcduRecords walks an ncdu JSON export: [major, minor, meta, rootDir], where a
+// directory is [info, children...] and a file is an info object.
+func readNcduRecords(r io.Reader) ([]inventoryRecord, error) {
+	var dump []json.RawMessage
+	if err := json.NewDecoder(r).Decode(&dump); err != nil {
+		return nil, fmt.Errorf("not an ncdu export: %v", err)
+	}
+	if len(dump) < 4 {
+		return nil, fmt.Errorf("not an ncdu export: missing root directory")
+	}
+	var records []inventoryRecord
+	if err := walkNcduDir(dump[3], "", &records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+type ncduInfo struct {
+	Name  string `json:"name"`
+	Asize int64  `json:"asize"`
+	Dsize int64  `json:"dsize"`
+}
+
+func (info ncduInfo) size() int64 {
+	if info.Dsize > 0 {
+		return info.Dsize
+	}
+	return info.Asize
+}
+
+func walkNcduDir(raw json.RawMessage, parent string, records *[]inventoryRecord) error {
+	var items []json.RawMessage
+	if err := json.Unmarshal(raw, &items); err != nil || len(items) == 0 {
+		return fmt.Errorf("malformed directory entry")
+	}
+	var info ncduInfo
+	if err := json.Unmarshal(items[0], &info); err != nil {
+		return fmt.Errorf("malformed directory entry")
+	}
+	path := info.Name
+	if parent != "" {
+		path = filepath.Join(parent, info.Name)
+	}
+	*records = append(*records, inventoryRecord{Path: path, IsDir: true})
+	for _, item := range items[1:] {
+		if trimmed := bytes.TrimSpace(item); len(trimmed) > 0 && trimmed[0] == '[' {
+			if err := walkNcduDir(item, path, records); err != nil {
+				return err
+			}
+			continue
+		}
+		var file ncduInfo
+		if err := json.Unmarshal(item, &file); err != nil {
+			return fmt.Errorf("malformed file entry in %s", path)
+		}
+		*records = append(*records, inventoryRecord{Path: filepath.Join(path, file.Name), Size: file.size()})
+	}
+	return nil
+}
+
+// loadInventoryFile reads a listing from path, or from stdin when path is "-".
+func loadInventoryFile(path string) (*inventoryTree, error) {
+	if path == "-" {
+		return loadInventory(os.Stdin, "stdin")
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return loadInventory(file, filepath.Base(path))
+}
+
+// stdinIsPipe reports whether a listing is being piped in, as in `find ~ -ls | mo analyze`.
+func stdinIsPipe() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeNamedPipe != 0
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadInventoryFindLs(t *testing.T) {
+	listing := `  1001      0 drwxr-xr-x   4 me  staff      128 May  2 12:00 /data/proj
+  1002   2048 -rw-r--r--   1 me  staff  2097152 May  2 12:00 /data/proj/big file.bin
+  1003      0 drwxr-xr-x   3 me  staff       96 May  2 12:00 /data/proj/src
+  1004      4 -rw-r--r--   1 me  staff     4096 May  2 12:00 /data/proj/src/main.go
+  1005      0 lrwxr-xr-x   1 me  staff       11 May  2 12:00 /data/proj/link -> big file.bin
+  1006      8 -rw-r--r--   1 me  staff  1000000 May  2 12:00 /data/proj/sparse.img
+`
+	tree, err := loadInventory(strings.NewReader(listing), "find")
+	if err != nil {
+		t.Fatalf("loadInventory: %v", err)
+	}
+	if tree.Root.Path != "/data/proj" {
+		t.Fatalf("root = %s; want /data/proj", tree.Root.Path)
+	}
+	if node, ok := tree.lookup("/data/proj/big file.bin"); !ok || node.Size != 2097152 {
+		t.Fatalf("file with spaces not loaded: %+v", node)
+	}
+	if node, ok := tree.lookup("/data/proj/link"); !ok || node.IsDir {
+		t.Fatalf("symlink target should be stripped: %+v", node)
+	}
+	if node, _ := tree.lookup("/data/proj/sparse.img"); node.Size != 8*1024 {
+		t.Fatalf("sparse file should count allocated blocks, got %d", node.Size)
+	}
+	if tree.Root.Size != 2097152+4096+8192 {
+		t.Fatalf("unexpected total %d", tree.Root.Size)
+	}
+}
+
+func TestLoadInventoryNcduExport(t *testing.T) {
+	root := t.TempDir()
+	writeFileWithSize(t, filepath.Join(root, "a", "deep.bin"), 3000)
+	writeFileWithSize(t, filepath.Join(root, "top.txt"), 10)
+	collected, err := collectTree(root)
+	if err != nil {
+		t.Fatalf("collectTree: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := writeNcduExport(&buf, collected); err != nil {
+		t.Fatalf("writeNcduExport: %v", err)
+	}
+
+	tree, err := loadInventory(&buf, "export.json")
+	if err != nil {
+		t.Fatalf("loadInventory: %v", err)
+	}
+	if tree.Root.Path != root {
+		t.Fatalf("root = %s; want %s", tree.Root.Path, root)
+	}
+	result, err := tree.scanResultFor(root)
+	if err != nil || len(result.Entries) != 2 {
+		t.Fatalf("unexpected listing: %+v %v", result, err)
+	}
+	if node, ok := tree.lookup(filepath.Join(root, "a", "deep.bin")); !ok || node.Size <= 0 {
+		t.Fatalf("nested file missing: %+v", node)
+	}
+}
+
+func TestLoadInventoryRelativeFind(t *testing.T) {
+	listing := "1 0 drwxr-xr-x 2 me staff 64 May 2 12:00 .\n2 4 -rw-r--r-- 1 me staff 100 May 2 12:00 ./notes.txt\n"
+	tree, err := loadInventory(strings.NewReader(listing), "stdin")
+	if err != nil {
+		t.Fatalf("loadInventory: %v", err)
+	}
+	if tree.Root.Path != "." || tree.Root.Size != 100 {
+		t.Fatalf("unexpected relative tree: %+v", tree.Root)
+	}
+	if _, err := loadInventory(strings.NewReader("nothing useful\n"), "stdin"); err == nil {
+		t.Fatal("expected an error for an empty listing")
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+// localeEnvVar overrides the detected locale (e.g. "de-DE", "fr_FR.UTF-8").
+const localeEnvVar = "MO_ANALYZE_LOCALE"
+
+var (
+	numberLocaleMu sync.RWMutex
+	numberPrinter  = message.NewPrinter(language.English)
+)
+
+func init() {
+	setNumberLocale(detectNumberLocale())
+}
+
+// detectNumberLocale picks the locale used for separators, override first.
+func detectNumberLocale() string {
+	for _, key := range []string{localeEnvVar, "LC_ALL", "LC_NUMERIC", "LANG"} {
+		if value := strings.TrimSpace(os.Getenv(key)); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// parseLocaleTag converts POSIX style names (de_DE.UTF-8@euro) to a language tag.
+func parseLocaleTag(name string) language.Tag {
+	name = strings.TrimSpace(name)
+	if idx := strings.IndexAny(name, ".@"); idx >= 0 {
+		name = name[:idx]
+	}
+	if name == "" || name == "C" || name == "POSIX" {
+		return language.English
+	}
+	tag, err := language.Parse(strings.ReplaceAll(name, "_", "-"))
+	if err != nil {
+		return language.English
+	}
+	return tag
+}
+
+func setNumberLocale(name string) {
+	tag := parseLocaleTag(name)
+	numberLocaleMu.Lock()
+	numberPrinter = message.NewPrinter(tag)
+	numberLocaleMu.Unlock()
+}
+
+// formatDecimal renders v with fixed decimals and the locale's decimal mark, no grouping.
+func formatDecimal(v float64, decimals int) string {
+	numberLocaleMu.RLock()
+	p := numberPrinter
+	numberLocaleMu.RUnlock()
+	return p.Sprint(number.Decimal(v,
+		number.NoSeparator(),
+		number.MinFractionDigits(decimals),
+		number.MaxFractionDigits(decimals)))
+}
+
+// formatGrouped renders n with the locale's thousands separators.
+func formatGrouped(n int64) string {
+	numberLocaleMu.RLock()
+	p := numberPrinter
+	numberLocaleMu.RUnlock()
+	return p.Sprint(number.Decimal(n))
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	// Keep formatting expectations stable regardless of the developer's locale.
+	setNumberLocale("en_US.UTF-8")
+	setMessageLocale("en_US.UTF-8")
+	os.Exit(m.Run())
+}
+
+func TestLocaleNumberFormatting(t *testing.T) {
+	defer setNumberLocale("en_US.UTF-8")
+
+	setNumberLocale("de_DE.UTF-8")
+	if got := humanizeBytes(1536); got != "1,5 KB" {
+		t.Errorf("humanizeBytes de = %q, want %q", got, "1,5 KB")
+	}
+	if got := formatNumber(1500); got != "1,5k" {
+		t.Errorf("formatNumber de = %q, want %q", got, "1,5k")
+	}
+	if got := formatGrouped(1234567); got != "1.234.567" {
+		t.Errorf("formatGrouped de = %q, want %q", got, "1.234.567")
+	}
+
+	setNumberLocale("C")
+	if got := formatGrouped(1234567); got != "1,234,567" {
+		t.Errorf("formatGrouped C = %q, want %q", got, "1,234,567")
+	}
+}
+
+func TestParseLocaleTag(t *testing.T) {
+	tests := map[string]string{
+		"":                 "en",
+		"POSIX":            "en",
+		"fr_FR.UTF-8":      "fr-FR",
+		"de_DE.UTF-8@euro": "de-DE",
+		"not a locale!!":   "en",
+	}
+	for input, want := range tests {
+		if got := parseLocaleTag(input).String(); got != want {
+			t.Errorf("parseLocaleTag(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestFormatRelativeAge(t *testing.T) {
+	day := 24 * time.Hour
+	en, de := relativeTimeWords["en"], relativeTimeWords["de"]
+	tests := []struct {
+		age   time.Duration
+		unit  ageUnit
+		long  bool
+		words relativeWords
+		want  string
+	}{
+		{100 * day, ageUnitAuto, false, en, ">3mo"},
+		{800 * day, ageUnitAuto, false, en, ">2yr"},
+		{10 * day, ageUnitAuto, false, en, ">1wk"},
+		{800 * day, ageUnitDays, false, en, ">800d"},
+		{400 * day, ageUnitMonths, true, en, "unused 13 months"},
+		{400 * day, ageUnitYears, true, en, "unused 1 year"},
+		{100 * day, ageUnitYears, false, en, ""},
+		{800 * day, ageUnitAuto, true, de, "seit 2 Jahren ungenutzt"},
+	}
+	for _, tc := range tests {
+		if got := formatRelativeAge(tc.age, tc.unit, tc.long, tc.words); got != tc.want {
+			t.Errorf("formatRelativeAge(%v, %s, %v) = %q, want %q", tc.age, ageUnitNames[tc.unit], tc.long, got, tc.want)
+		}
+	}
+}
+
+func TestFormatUnusedTimeThreshold(t *testing.T) {
+	defer func(saved unusedHintSettings) { unusedHints = saved }(unusedHints)
+
+	if got := formatUnusedTime(time.Now().Add(-30 * 24 * time.Hour)); got != "" {
+		t.Fatalf("default threshold should hide a month-old hint, got %q", got)
+	}
+	unusedHints.After = 7 * 24 * time.Hour
+	if got := formatUnusedTime(time.Now().Add(-30 * 24 * time.Hour)); got != ">1mo" {
+		t.Fatalf("lowered threshold should show the hint, got %q", got)
+	}
+	unusedHints.After = -1
+	if got := formatUnusedTime(time.Now().Add(-800 * 24 * time.Hour)); got != "" {
+		t.Fatalf("hints turned off, got %q", got)
+	}
+	setMessageLocale("ja_JP.UTF-8")
+	defer setMessageLocale("en_US.UTF-8")
+	unusedHints.After = 0
+	if got := formatUnusedTime(time.Now().Add(-800 * 24 * time.Hour)); got != ">2年" {
+		t.Fatalf("expected Japanese units, got %q", got)
+	}
+}
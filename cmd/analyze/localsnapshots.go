@@ -0,0 +1,210 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// snapshotMountTimeout bounds mounting or unmounting one snapshot.
+	snapshotMountTimeout = 30 * time.Second
+	// snapshotThinDefault is asked of tmutil when the selected snapshot's size is unknown.
+	snapshotThinDefault = 10 << 30
+)
+
+var snapshotDatePattern = regexp.MustCompile(`(\d{4}-\d{2}-\d{2}-\d{6})`)
+
+// localSnapshot is one APFS snapshot on a volume, with the space it holds for the
+// folder being analyzed. Held and Unique are -1 until measured.
+type localSnapshot struct {
+	Name      string
+	XID       uint64
+	Purgeable bool
+	Limiting  bool // Blocks the container from shrinking
+	Time      time.Time
+	Held      int64 // Bytes deleted or changed since, still kept by this snapshot
+	Unique    int64 // Bytes only this snapshot keeps; deleting it frees them
+}
+
+// isTimeMachine reports whether tmutil manages the snapshot.
+func (s localSnapshot) isTimeMachine() bool {
+	return strings.HasPrefix(s.Name, "com.apple.TimeMachine.")
+}
+
+type localSnapshotsMsg struct {
+	Volume    string
+	Snapshots []localSnapshot
+	Measured  bool // False when snapshots could not be mounted (needs root)
+	Err       error
+}
+
+// parseSnapshotList reads `diskutil apfs listSnapshots` output, oldest first.
+func parseSnapshotList(output string) []localSnapshot {
+	var snapshots []localSnapshot
+	var current *localSnapshot
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimLeft(scanner.Text(), "|+- ")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "Name":
+			snapshots = append(snapshots, localSnapshot{Name: value, Held: -1, Unique: -1})
+			current = &snapshots[len(snapshots)-1]
+			if match := snapshotDatePattern.FindString(value); match != "" {
+				current.Time, _ = time.ParseInLocation(backupTimeLayout, match, time.Local)
+			}
+		case "XID":
+			if current != nil {
+				current.XID, _ = strconv.ParseUint(value, 10, 64)
+			}
+		case "Purgeable":
+			if current != nil {
+				current.Purgeable = strings.EqualFold(value, "yes")
+			}
+		case "NOTE":
+			if current != nil && strings.Contains(value, "limits the minimum size") {
+				current.Limiting = true
+			}
+		}
+	}
+	sort.SliceStable(snapshots, func(i, j int) bool { return snapshots[i].XID < snapshots[j].XID })
+	return snapshots
+}
+
+func listLocalSnapshots(volume string) ([]localSnapshot, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), mdlsTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "diskutil", "apfs", "listSnapshots", volume).Output()
+	if err != nil {
+		return nil, err
+	}
+	return parseSnapshotList(string(out)), nil
+}
+
+// collectFileKeys maps each file under root to its size, keyed so unchanged files
+// match across snapshots of the same volume.
+func collectFileKeys(root string) map[backupFileKey]int64 {
+	keys := make(map[backupFileKey]int64)
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || d.Type()&fs.ModeSymlink != 0 {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+			keys[backupFileKey{ino: uint64(stat.Ino), size: info.Size(), mtime: info.ModTime().UnixNano()}] = getActualFileSize(path, info)
+		}
+		return nil
+	})
+	return keys
+}
+
+// attributeSnapshotSpace fills Held and Unique from the files each snapshot has that
+// the live folder no longer does. snapshotFiles is parallel to snapshots.
+func attributeSnapshotSpace(snapshots []localSnapshot, live map[backupFileKey]int64, snapshotFiles []map[backupFileKey]int64) {
+	holders := make(map[backupFileKey]int)
+	for _, files := range snapshotFiles {
+		for key := range files {
+			if _, ok := live[key]; !ok {
+				holders[key]++
+			}
+		}
+	}
+	for i, files := range snapshotFiles {
+		var held, unique int64
+		for key, size := range files {
+			if _, ok := live[key]; ok {
+				continue
+			}
+			held += size
+			if holders[key] == 1 {
+				unique += size
+			}
+		}
+		snapshots[i].Held, snapshots[i].Unique = held, unique
+	}
+}
+
+// mountSnapshot mounts a snapshot read-only in a temporary folder.
+func mountSnapshot(volume, name string) (string, func(), error) {
+	dir, err := os.MkdirTemp("", "mole-snapshot-")
+	if err != nil {
+		return "", nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), snapshotMountTimeout)
+	defer cancel()
+	if out, err := exec.CommandContext(ctx, "mount_apfs", "-o", "rdonly,nobrowse", "-s", name, volume, dir).CombinedOutput(); err != nil {
+		_ = os.Remove(dir)
+		return "", nil, fmt.Errorf("%v: %s", err, strings.TrimSpace(string(out)))
+	}
+	unmount := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), snapshotMountTimeout)
+		defer cancel()
+		_ = exec.CommandContext(ctx, "umount", dir).Run()
+		_ = os.Remove(dir)
+	}
+	return dir, unmount, nil
+}
+
+// measureLocalSnapshots lists snapshots on volume and, when they can be mounted,
+// attributes the space they hold under rel (the analyzed folder relative to volume).
+func measureLocalSnapshots(volume, rel, live string) localSnapshotsMsg {
+	snapshots, err := listLocalSnapshots(volume)
+	if err != nil || len(snapshots) == 0 {
+		return localSnapshotsMsg{Volume: volume, Snapshots: snapshots, Err: err}
+	}
+	liveKeys := collectFileKeys(live)
+	files := make([]map[backupFileKey]int64, len(snapshots))
+	for i, snapshot := range snapshots {
+		dir, unmount, err := mountSnapshot(volume, snapshot.Name)
+		if err != nil {
+			return localSnapshotsMsg{Volume: volume, Snapshots: snapshots}
+		}
+		files[i] = collectFileKeys(filepath.Join(dir, rel))
+		unmount()
+	}
+	attributeSnapshotSpace(snapshots, liveKeys, files)
+	sort.SliceStable(snapshots, func(i, j int) bool { return snapshots[i].Unique > snapshots[j].Unique })
+	return localSnapshotsMsg{Volume: volume, Snapshots: snapshots, Measured: true}
+}
+
+func measureLocalSnapshotsCmd(volume, rel, live string) tea.Cmd {
+	return func() tea.Msg {
+		return measureLocalSnapshots(volume, rel, live)
+	}
+}
+
+// snapshotDeleteCommand removes one snapshot: tmutil for Time Machine's own, diskutil otherwise.
+func snapshotDeleteCommand(volume string, snapshot localSnapshot) []string {
+	if snapshot.isTimeMachine() && !snapshot.Time.IsZero() {
+		return []string{"tmutil", "deletelocalsnapshots", snapshot.Time.Format(backupTimeLayout)}
+	}
+	return []string{"diskutil", "apfs", "deleteSnapshot", volume, "-xid", strconv.FormatUint(snapshot.XID, 10)}
+}
+
+// snapshotThinCommand asks Time Machine to purge snapshots until bytes are free.
+func snapshotThinCommand(volume string, bytes int64) []string {
+	if bytes <= 0 {
+		bytes = snapshotThinDefault
+	}
+	return []string{"tmutil", "thinlocalsnapshots", volume, strconv.FormatInt(bytes, 10), "4"}
+}
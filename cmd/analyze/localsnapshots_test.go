@@ -0,0 +1,59 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+const sampleSnapshotList = `Snapshots for disk3s5 (2 found)
+|
++-- 7A1C2B3D-0000-0000-0000-000000000002
+|   Name:        com.apple.TimeMachine.2024-05-02-120000.local
+|   XID:         2200
+|   Purgeable:   Yes
+|
++-- 7A1C2B3D-0000-0000-0000-000000000001
+    Name:        com.apple.os.update-ABCDEF
+    XID:         1100
+    Purgeable:   No
+    NOTE:        This snapshot limits the minimum size of APFS Container disk3
+`
+
+func TestParseSnapshotList(t *testing.T) {
+	snapshots := parseSnapshotList(sampleSnapshotList)
+	if len(snapshots) != 2 {
+		t.Fatalf("expected 2 snapshots, got %+v", snapshots)
+	}
+	update, tm := snapshots[0], snapshots[1]
+	if update.XID != 1100 || update.Purgeable || !update.Limiting || update.isTimeMachine() || !update.Time.IsZero() {
+		t.Fatalf("unexpected update snapshot: %+v", update)
+	}
+	if tm.XID != 2200 || !tm.Purgeable || tm.Limiting || !tm.isTimeMachine() || tm.Time.Day() != 2 || tm.Unique != -1 {
+		t.Fatalf("unexpected Time Machine snapshot: %+v", tm)
+	}
+
+	if got := snapshotDeleteCommand("/System/Volumes/Data", tm); !reflect.DeepEqual(got, []string{"tmutil", "deletelocalsnapshots", "2024-05-02-120000"}) {
+		t.Fatalf("delete command for Time Machine snapshot: %v", got)
+	}
+	if got := snapshotDeleteCommand("/System/Volumes/Data", update); !reflect.DeepEqual(got, []string{"diskutil", "apfs", "deleteSnapshot", "/System/Volumes/Data", "-xid", "1100"}) {
+		t.Fatalf("delete command for other snapshot: %v", got)
+	}
+}
+
+func TestAttributeSnapshotSpace(t *testing.T) {
+	kept := backupFileKey{ino: 1, size: 10}
+	deletedEarly := backupFileKey{ino: 2, size: 100}
+	deletedLate := backupFileKey{ino: 3, size: 1000}
+	live := map[backupFileKey]int64{kept: 10}
+	snapshots := []localSnapshot{{Name: "old"}, {Name: "new"}}
+	attributeSnapshotSpace(snapshots, live, []map[backupFileKey]int64{
+		{kept: 10, deletedEarly: 100, deletedLate: 1000},
+		{kept: 10, deletedLate: 1000},
+	})
+	if snapshots[0].Held != 1100 || snapshots[0].Unique != 100 {
+		t.Fatalf("old snapshot = %+v; want held 1100, unique 100", snapshots[0])
+	}
+	if snapshots[1].Held != 1000 || snapshots[1].Unique != 0 {
+		t.Fatalf("new snapshot = %+v; want held 1000, unique 0", snapshots[1])
+	}
+}
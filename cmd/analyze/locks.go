@@ -0,0 +1,88 @@
+package main
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	userLockFlags   = flagUserImmutable | flagUserAppend
+	systemLockFlags = flagSystemImmutable | flagSystemAppend
+)
+
+// lockedTarget is a delete target carrying a Finder lock or an immutable flag.
+type lockedTarget struct {
+	Path   string
+	System bool // schg/sappnd: only root in single-user mode can clear it
+	Owned  bool // The user may clear uchg/uappnd (owner or root)
+}
+
+// unlockable reports whether the delete can clear the lock itself.
+func (l lockedTarget) unlockable() bool {
+	return !l.System && l.Owned
+}
+
+// lockedTargets lists the paths whose own flags would make the delete fail.
+func lockedTargets(paths []string) []lockedTarget {
+	var locked []lockedTarget
+	uid := os.Getuid()
+	for _, path := range paths {
+		flags, ok := fileFlags(path)
+		if !ok || flags&(userLockFlags|systemLockFlags) == 0 {
+			continue
+		}
+		var st syscall.Stat_t
+		owned := uid == 0 || (syscall.Lstat(path, &st) == nil && int(st.Uid) == uid)
+		locked = append(locked, lockedTarget{Path: path, System: flags&systemLockFlags != 0, Owned: owned})
+	}
+	return locked
+}
+
+// clearUserLocks removes uchg/uappnd from path and everything beneath it, like
+// chflags -R nouchg. It returns how many items were unlocked.
+func clearUserLocks(root string) (int, error) {
+	unlocked := 0
+	var firstErr error
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		flags, ok := fileFlags(path)
+		if !ok || flags&userLockFlags == 0 {
+			return nil
+		}
+		if err := setFileFlags(path, flags&^userLockFlags); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			return nil
+		}
+		unlocked++
+		return nil
+	})
+	return unlocked, firstErr
+}
+
+// unlockThenDeleteCmd clears user locks on paths, then runs the delete; anything
+// still locked shows up in the delete's failure report.
+func unlockThenDeleteCmd(paths []string, del tea.Cmd) tea.Cmd {
+	return func() tea.Msg {
+		for _, path := range paths {
+			_, _ = clearUserLocks(path)
+		}
+		return del()
+	}
+}
+
+func anyUnlockable(locked []lockedTarget) bool {
+	for _, l := range locked {
+		if l.unlockable() {
+			return true
+		}
+	}
+	return false
+}
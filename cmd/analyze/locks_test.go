@@ -0,0 +1,20 @@
+package main
+
+import "testing"
+
+func TestLockedTargetUnlockable(t *testing.T) {
+	locks := []lockedTarget{
+		{Path: "/a", System: true, Owned: true},
+		{Path: "/b", Owned: false},
+	}
+	if anyUnlockable(locks) {
+		t.Fatal("system flags and other users' locks cannot be cleared")
+	}
+	locks = append(locks, lockedTarget{Path: "/c", Owned: true})
+	if !anyUnlockable(locks) {
+		t.Fatal("an owned uchg lock should be unlockable")
+	}
+	if got := lockedTargets([]string{t.TempDir()}); len(got) != 0 {
+		t.Fatalf("plain folder reported locked: %+v", got)
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+)
+
+// defaultCriticalFreeSpace is the free space below which the analyzer stops writing to a
+// volume: caches, history and exports only make a nearly full disk worse.
+const defaultCriticalFreeSpace int64 = 1 << 30
+
+var criticalFreeSpace atomic.Int64
+
+func init() {
+	criticalFreeSpace.Store(defaultCriticalFreeSpace)
+}
+
+// lowSpaceError refuses a write to a volume that is critically short of space.
+type lowSpaceError struct {
+	Path string
+	Free int64
+}
+
+func (e *lowSpaceError) Error() string {
+	return fmt.Sprintf("only %s free on the volume holding %s; writes there are paused", humanizeBytes(e.Free), displayPath(e.Path))
+}
+
+// existingParent is path or its nearest ancestor that exists, so a file about to be
+// created can be checked against its volume.
+func existingParent(path string) string {
+	for {
+		if _, err := os.Stat(path); err == nil || path == filepath.Dir(path) {
+			return path
+		}
+		path = filepath.Dir(path)
+	}
+}
+
+// checkWritableSpace returns a lowSpaceError when path's volume has less than the
+// critical amount free. A volume that cannot be measured is not blocked.
+func checkWritableSpace(path string) error {
+	free, err := volumeAvailableBytes(existingParent(path))
+	if err != nil || free >= criticalFreeSpace.Load() {
+		return nil
+	}
+	return &lowSpaceError{Path: path, Free: free}
+}
+
+// lowSpaceFree is the free space on the analyzer's state volume when it is below the
+// critical amount, or zero when there is room.
+func lowSpaceFree() int64 {
+	dir, err := getConfigDir()
+	if err != nil {
+		return 0
+	}
+	if low, ok := checkWritableSpace(dir).(*lowSpaceError); ok {
+		return max(low.Free, 1)
+	}
+	return 0
+}
+
+// pickExportDir is the first candidate folder whose volume has room. With none, the
+// error points at external volumes and at streaming the export to stdout instead.
+func pickExportDir(candidates []string) (string, error) {
+	var first error
+	for _, dir := range candidates {
+		if !isDirectory(dir) {
+			continue
+		}
+		err := checkWritableSpace(dir)
+		if err == nil {
+			return dir, nil
+		}
+		if first == nil {
+			first = err
+		}
+	}
+	if first == nil {
+		return "", fmt.Errorf("no folder to export to")
+	}
+	return "", fmt.Errorf("%v; connect an external volume with room, or run mo analyze --export-ncdu - to stream the export", first)
+}
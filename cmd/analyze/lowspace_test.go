@@ -0,0 +1,66 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func setCriticalFreeSpace(t *testing.T, size int64) {
+	t.Helper()
+	old := criticalFreeSpace.Load()
+	criticalFreeSpace.Store(size)
+	t.Cleanup(func() { criticalFreeSpace.Store(old) })
+}
+
+func TestCheckWritableSpace(t *testing.T) {
+	dir := t.TempDir()
+	missing := filepath.Join(dir, "not", "yet", "created.json")
+
+	setCriticalFreeSpace(t, 1)
+	if err := checkWritableSpace(missing); err != nil {
+		t.Fatalf("checkWritableSpace with room = %v", err)
+	}
+
+	setCriticalFreeSpace(t, math.MaxInt64)
+	var low *lowSpaceError
+	if err := checkWritableSpace(missing); !errors.As(err, &low) || low.Path != missing {
+		t.Fatalf("checkWritableSpace on a full volume = %v, want a lowSpaceError for %s", err, missing)
+	}
+}
+
+func TestPickExportDir(t *testing.T) {
+	first, second := t.TempDir(), t.TempDir()
+	missing := filepath.Join(first, "missing")
+
+	setCriticalFreeSpace(t, 1)
+	if dir, err := pickExportDir([]string{missing, second, first}); err != nil || dir != second {
+		t.Errorf("pickExportDir = %q, %v, want %q", dir, err, second)
+	}
+
+	setCriticalFreeSpace(t, math.MaxInt64)
+	if _, err := pickExportDir([]string{first, second}); err == nil || !strings.Contains(err.Error(), "--export-ncdu -") {
+		t.Errorf("pickExportDir with no room = %v, want a hint to stream the export", err)
+	}
+}
+
+func TestWriteCacheFileKeepsOldFileWhenLow(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scan.cache")
+	if err := os.WriteFile(path, []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	setCriticalFreeSpace(t, math.MaxInt64)
+	if err := writeCacheFile(path, []byte("new")); err == nil {
+		t.Fatal("writeCacheFile wrote with no free space")
+	}
+	if data, _ := os.ReadFile(path); string(data) != "old" {
+		t.Errorf("cache file = %q, want the old contents kept", data)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temporary file left behind: %v", err)
+	}
+}
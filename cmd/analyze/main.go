@@ -3,13 +3,17 @@
 package main
 
 import (
+	"bufio"
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"sync/atomic"
 	"time"
@@ -18,31 +22,48 @@ import (
 )
 
 type dirEntry struct {
-	Name       string
-	Path       string
-	Size       int64
-	IsDir      bool
-	LastAccess time.Time
+	Name         string
+	Path         string
+	Size         int64
+	IsDir        bool
+	LastAccess   time.Time
+	CaseConflict bool         // Holds names that collide on case-insensitive volumes
+	Apparent     int64        // Logical bytes (st_size), 0 when only the allocated size is known
+	Files        int64        // Files counted beneath the entry, -1 when sized by du
+	Dirs         int64        // Folders counted beneath the entry, -1 when sized by du
+	Strategy     scanStrategy // Per-path override used to size the entry
+	Shared       int64        // Bytes also referenced by hard links or APFS clones, counted once per scan
+	Bundle       bool         // A package (.app, .framework...) shown as one item
+	Cloud        int64        // Logical bytes beneath the entry kept only in the cloud
+	Dataless     bool         // Cloud placeholder with no local contents, never enumerated
+	Privileged   bool         // Root-owned area sized through sudo, listed with --sudo only
 }
 
 type fileEntry struct {
-	Name string
-	Path string
-	Size int64
+	Name     string
+	Path     string
+	Size     int64
+	Apparent int64 // Logical bytes, 0 when unknown
+	ModTime  time.Time
+	Created  time.Time // Birth time, zero when unknown
 }
 
 type scanResult struct {
-	Entries    []dirEntry
-	LargeFiles []fileEntry
-	TotalSize  int64
+	Entries     []dirEntry
+	LargeFiles  []fileEntry
+	TotalSize   int64
+	Omitted     int   // Children dropped past the listing cap
+	OmittedSize int64 // Their combined size
 }
 
 type cacheEntry struct {
-	Entries    []dirEntry
-	LargeFiles []fileEntry
-	TotalSize  int64
-	ModTime    time.Time
-	ScanTime   time.Time
+	Entries     []dirEntry
+	LargeFiles  []fileEntry
+	TotalSize   int64
+	Omitted     int
+	OmittedSize int64
+	ModTime     time.Time
+	ScanTime    time.Time
 }
 
 type historyEntry struct {
@@ -50,6 +71,8 @@ type historyEntry struct {
 	Entries       []dirEntry
 	LargeFiles    []fileEntry
 	TotalSize     int64
+	Omitted       int
+	OmittedSize   int64
 	Selected      int
 	EntryOffset   int
 	LargeSelected int
@@ -60,6 +83,7 @@ type historyEntry struct {
 
 type scanResultMsg struct {
 	result scanResult
+	usage  *runUsage // Set when this scan walked the disk rather than reading a cache
 	err    error
 }
 
@@ -73,10 +97,16 @@ type overviewSizeMsg struct {
 type tickMsg time.Time
 
 type deleteProgressMsg struct {
-	done  bool
-	err   error
-	count int64
-	path  string
+	done      bool
+	err       error
+	count     int64
+	path      string
+	requested []string
+	deleted   []string        // Paths removed successfully, including ones already gone
+	trashed   bool            // Items were moved to the Trash rather than removed
+	freed     int64           // Free space gained on the affected volumes, -1 if unknown
+	failures  []deleteFailure // Paths that could not be removed; the rest still went
+	skipped   []string        // Paths not started because the batch was stopped
 }
 
 type model struct {
@@ -88,6 +118,8 @@ type model struct {
 	offset               int
 	status               string
 	totalSize            int64
+	omitted              int   // Children the listing cap dropped
+	omittedSize          int64 // Their combined size
 	scanning             bool
 	spinner              int
 	filesScanned         *int64
@@ -98,8 +130,15 @@ type model struct {
 	isOverview           bool
 	deleteConfirm        bool
 	deleteTarget         *dirEntry
+	reinstallHints       map[string]string // mas/brew notes for apps and installers being confirmed
+	deleteLocks          []lockedTarget    // Confirmed targets with uchg/schg flags set
+	verify               *verifyReport     // Reconciliation of the last large delete, Esc dismisses
+	deleteFailures       []deleteFailure   // Paths the last delete could not remove, Esc dismisses
+	deletePermanent      bool              // The open confirm bypasses the Trash
+	permanentDelete      bool              // --permanent: always bypass the Trash
 	deleting             bool
 	deleteCount          *int64
+	deleteBatch          *deleteBatchProgress
 	cache                map[string]historyEntry
 	largeSelected        int
 	largeOffset          int
@@ -109,21 +148,354 @@ type model struct {
 	overviewBytesScanned *int64
 	overviewCurrentPath  *string
 	overviewScanning     bool
-	overviewScanningSet  map[string]bool // Track which paths are currently being scanned
-	width                int             // Terminal width
-	height               int             // Terminal height
-	multiSelected        map[string]bool // Track multi-selected items by path (safer than index)
-	largeMultiSelected   map[string]bool // Track multi-selected large files by path (safer than index)
+	overviewScanningSet  map[string]bool        // Track which paths are currently being scanned
+	width                int                    // Terminal width
+	height               int                    // Terminal height
+	multiSelected        map[string]bool        // Track multi-selected items by path (safer than index)
+	largeMultiSelected   map[string]bool        // Track multi-selected large files by path (safer than index)
+	exactSize            *exactSizeMsg          // Byte-precise size of the selected entry, shown on demand
+	volumeTrash          map[string]int64       // Per-volume .Trashes sizes in the /Volumes view
+	appOwners            map[string]appOwner    // Apps behind ~/Library/Caches and Application Support folders
+	volumeHealth         map[string]driveHealth // Drive condition per volume in the /Volumes view
+	trashConfirm         string                 // Volume awaiting empty-trash confirmation
+	evictConfirm         *evictPlan             // "J" iCloud Drive items awaiting eviction
+	evicting             bool                   // An eviction is in flight
+	compress             *compressJob           // "%" folder being confirmed or compressed
+	relocate             *relocateView          // ">" volume picker, then the running move
+	exporting            int                    // Exports still being written
+	quitGuard            *quitGuard             // Quit pressed while operations were running
+	estimates            []dirEntry             // Sampled sizes shown until the exact scan finishes
+	partial              *partialScan           // Entries finished so far in the running scan
+	scanControl          *scanControl           // Cancels the running scan on Esc
+	scanPaused           bool                   // p paused the worker pool mid-scan
+	showPoolStats        bool                   // Debug panel with live worker pool stats
+	showInfo             bool                   // Tab panel with the selected entry's details
+	focusMode            bool                   // "w" lists only entries with an action available
+	duplicates           map[string]bool        // Last duplicate finder results, see duplicateIndex
+	lastUsage            *runUsage              // What the last disk scan cost, for the debug panel
+	rows                 *rowCache              // Formatted list rows reused between frames
+	backgroundLimit      int                    // Worker cap to restore when the terminal regains focus, 0 while focused
+	changesOffset        int64                  // Read position in the changes.log shared with mo clean
+	cleanRunning         bool                   // mo clean holds its lock; deletes wait for it
+	lowSpace             int64                  // Free bytes while critically low; caches and exports are paused
+	showShared           bool                   // Column with bytes shared through hard links or clones
+	showCounts           bool                   // Column with files and folders beneath each entry
+	showApparent         bool                   // Lead rows with logical sizes instead of allocated bytes
+	watch                bool                   // --watch: rescan the open folder every few seconds
+	watchScanning        bool                   // A watch rescan is in flight
+	watchChanges         map[string]int64       // Size change per entry in the last watch rescan
+	inventory            *inventoryTree         // Read-only listing (e.g. over SSH) used instead of disk scans
+	largeSort            largeSortMode          // Order of the large-files list
+	entrySort            entrySortMode          // Order of the directory listing, kept across navigation
+	safeMode             bool                   // Novice profile: only Trash, caches and build files can be deleted
+	filter               *listFilter            // "/" filter layered over the scanned lists
+	expertMode           bool                   // Enables the ":" command palette
+	palette              *paletteState          // Open ":" prompt
+	actionPalette        *actionPaletteState    // Open ctrl+k action list
+	dupes                *duplicateView         // "d" duplicate finder
+	staging              *stagingView           // "Z" review-later list
+	versions             *versionsView          // "v" superseded installers
+	backups              *backupView            // "B" Time Machine snapshot breakdown
+	snapshots            *snapshotsView         // "L" local APFS snapshots
+	storage              *storageView           // "C" System Settings storage categories
+	cleanPreview         *cleanPreview          // "p" what mo clean would recover per category
+	homebrew             *homebrewView          // "i" Homebrew cache and kegs
+	migration            *migrationView         // "M" migrate, archive and recreate plan
+	packageCaches        *packageCacheView      // "Y" package manager caches
+	uninstall            *uninstallView         // ctrl+u app bundle with its Library data
+	preview              *filePreview           // ctrl+y start of a small text file
+	appData              *appDataView           // "K" per-app container data
+	providers            *sizeProvidersView     // "I" size provider diagnostics
+	stale                *staleView             // "U" data untouched for months
+	denied               *deniedView            // "!" paths scans could not read
+	alerts               *alertsView            // "W" alert threshold editor
+	historyDiff          *historyDiffView       // "y" growth since earlier scans
+	searches             *searchesView          // "m" saved searches
+	organize             *organizeView          // ctrl+o move rules
+	stagedDue            int                    // Staged items waiting longer than stagingReviewAfter
+	stagedDueSize        int64
+	drilling             bool        // Following the largest child down after "g"
+	explanation          *explainMsg // "Why is this big?" answer for the selected entry
 }
 
 func (m model) inOverviewMode() bool {
 	return m.isOverview && m.path == "/"
 }
 
+// setEntrySort changes the listing order. The Weighted order also makes later scans keep
+// the top entries by weighted rank, so many-file folders are not left out by size.
+func (m *model) setEntrySort(mode entrySortMode) {
+	m.entrySort = mode
+	weightedRanking.Store(mode == entrySortWeighted)
+	m.entries = m.orderEntries(m.entries)
+}
+
+// orderEntries applies the chosen sort, then floats pins; the overview keeps its fixed order.
+func (m model) orderEntries(entries []dirEntry) []dirEntry {
+	if !m.inOverviewMode() {
+		sortEntries(entries, m.entrySort)
+	}
+	return applyPins(entries)
+}
+
 func main() {
+	sshTarget := flag.String("ssh", "", "analyze a remote path over SSH (user@host:/path)")
+	inventoryRoot := flag.String("inventory", "", "print a tab-separated inventory of `path` and exit")
+	loadFile := flag.String("load", "", "browse a saved listing from `file` (- for stdin): ncdu/gdu JSON, find -ls or --inventory output")
+	agentMode := flag.Bool("agent", false, "share a read-only listing of this Mac with a paired client")
+	agentAddr := flag.String("listen", defaultAgentAddr, "address the agent listens on")
+	connectAddr := flag.String("connect", "", "analyze a Mac running `mo agent` at host[:port]")
+	pairingCode := flag.String("code", "", "pairing code shown by the agent")
+	fingerprint := flag.String("fingerprint", "", "expected agent certificate fingerprint")
+	cacheMode := flag.Bool("cache", false, "manage cached scan results: status, encrypt, decrypt, wipe, or open <file> to read an encrypted export")
+	daemonMode := flag.Bool("daemon", false, "rescan configured folders in the background: run, once, install, uninstall or status")
+	redactDepth := flag.Int("redact", -1, "hide names deeper than `depth` levels (below ~ or /) for shared reports")
+	redactStyle := flag.String("redact-style", redactStyleHash, "how redacted names are shown: hash or truncate")
+	exportNcdu := flag.String("export-ncdu", "", "write the full scan tree as ncdu JSON to `file` (- for stdout) and exit; never encrypted, so ncdu can read it")
+	report := flag.String("report", "", "write a standalone HTML report with a treemap and sortable table to `file` and exit; never encrypted")
+	var excludes excludeList
+	_ = excludes.Set(os.Getenv(excludeEnvVar))
+	flag.Var(&excludes, "exclude", "leave `glob` out of scans and totals; a path, or a name such as *.photoslibrary (repeatable)")
+	permanent := flag.Bool("permanent", os.Getenv(permanentDeleteEnvVar) == "1", "delete permanently instead of moving items to the Trash")
+	expertMode := flag.Bool("expert", os.Getenv(expertModeEnvVar) == "1", "enable the : command palette (scripts, shell commands, runtime settings)")
+	safeMode := flag.Bool("safe", os.Getenv(safeModeEnvVar) == "1", "only allow deleting Trash, caches and project build files")
+	demoMode := flag.Bool("demo", os.Getenv(demoEnvVar) == "1", "browse a built-in synthetic home folder instead of the disk")
+	envDeleteWorkers, _ := strconv.Atoi(os.Getenv(deleteWorkersEnvVar))
+	watch := flag.Bool("watch", os.Getenv(watchEnvVar) == "1", "keep rescanning the open folder and mark what changed")
+	warm := flag.Bool("warm", false, "refresh overview sizes in the background and exit, for login items and cron")
+	warmBudget := flag.Duration("warm-budget", 20*time.Second, "stop --warm after `duration`, even mid-measurement")
+	allEntries := flag.Bool("all", false, "list every item in a folder instead of the largest ones")
+	minSize := flag.String("min-size", "", "list files of at least `size` (e.g. 500MB) as large files")
+	workers := flag.Int("delete-workers", envDeleteWorkers, "remove folders with `n` parallel workers (0 picks per volume)")
+	workspace := flag.String("workspace", os.Getenv(workspaceEnvVar), "use the settings of `name` from config.toml's workspaces")
+	sudo := flag.Bool("sudo", false, "measure overview folders as root and add system areas and other users' homes")
+	finish := flag.String("finish", "", "finish the deletes and moves a quitting session handed off in `file`")
+	nice := flag.Bool("nice", os.Getenv(niceEnvVar) == "1", "scan at low CPU and disk priority with fewer workers, for a Mac in use")
+	flag.Parse()
+
+	// Never let a scan download iCloud Drive or other cloud placeholders.
+	_ = preventDatalessMaterialization()
+	if *nice {
+		if err := enableNiceMode(); err != nil {
+			fmt.Fprintf(os.Stderr, "--nice: %v\n", err)
+		}
+	}
+	values, err := loadConfigValues()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid config: %v\n", err)
+		os.Exit(1)
+	}
+	// Ask for a workspace only when launching the browser from a terminal.
+	browsing := *finish == "" && !*cacheMode && !*daemonMode && !*warm && *inventoryRoot == "" && *exportNcdu == "" && *report == "" && !*agentMode && !*demoMode &&
+		*loadFile == "" && *sshTarget == "" && *connectAddr == "" && !stdinIsPipe() && flag.Arg(0) != "diff" && flag.Arg(0) != "check"
+	if names := workspaceNames(values); *workspace == "" && defaultWorkspace(values) == "" && len(names) > 0 && browsing {
+		*workspace = pickWorkspace(os.Stdin, os.Stderr, names)
+	}
+	config, err := loadUserConfig(values, *workspace)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid config: %v\n", err)
+		os.Exit(1)
+	}
+	config.Exclude = append(config.Exclude, excludes...)
+	applyUserConfig(config, os.Getenv("HOME"))
+	fullListing.Store(*allEntries)
+	if *minSize != "" {
+		threshold, err := parseByteSize(*minSize)
+		if err != nil || threshold <= 0 {
+			fmt.Fprintf(os.Stderr, "invalid --min-size %q: use a size such as 500MB\n", *minSize)
+			os.Exit(1)
+		}
+		minLargeFileSize = threshold
+	}
+	deleteWorkers.Store(int64(max(*workers, 0)))
+	if *redactDepth >= 0 {
+		activeRedactor = newRedactor(*redactDepth, *redactStyle)
+	}
+
+	if *finish != "" {
+		if err := runHandoff(os.Stdout, *finish); err != nil {
+			fmt.Fprintf(os.Stderr, "finish: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *cacheMode {
+		if err := runCacheCommand(flag.Args()); err != nil {
+			fmt.Fprintf(os.Stderr, "cache: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *daemonMode {
+		if err := runDaemonCommand(flag.Args(), config, os.Getenv("HOME")); err != nil {
+			fmt.Fprintf(os.Stderr, "daemon: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *warm {
+		runWarm(*warmBudget)
+		return
+	}
+
+	// "mo analyze diff [path]" compares the last two scans; a folder named diff still opens.
+	if flag.Arg(0) == "diff" && (flag.NArg() == 2 || (flag.NArg() == 1 && !isDirectory("diff"))) {
+		root := flag.Arg(1)
+		if root == "" {
+			root = "."
+		}
+		if err := runDiffCommand(os.Stdout, root); err != nil {
+			fmt.Fprintf(os.Stderr, "diff: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// "mo analyze check --warn 85 --crit 95" reports volume usage through its exit code.
+	if flag.Arg(0) == "check" && (flag.NArg() > 1 || !isDirectory("check")) {
+		os.Exit(runCheckCommand(os.Stdout, flag.Args()[1:]))
+	}
+
+	if *inventoryRoot != "" {
+		if err := writeInventory(os.Stdout, *inventoryRoot); err != nil {
+			fmt.Fprintf(os.Stderr, "inventory failed: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *exportNcdu != "" || *report != "" {
+		root := flag.Arg(0)
+		if root == "" {
+			root = "."
+		}
+		var inventory *inventoryTree
+		if *demoMode {
+			inventory = demoInventory()
+			root = inventory.Root.Path
+		}
+		abs, err := filepath.Abs(root)
+		if err == nil && *exportNcdu != "" {
+			err = exportNcduFile(abs, inventory, *exportNcdu)
+		}
+		if err == nil && *report != "" {
+			err = exportHTMLReport(abs, inventory, *report)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "export failed: %v\n", err)
+			os.Exit(1)
+		}
+		if cacheEncryptionEnabled() {
+			for _, dest := range []string{*exportNcdu, *report} {
+				if dest != "" && dest != "-" {
+					fmt.Fprintf(os.Stderr, "%s: %s\n", dest, exportOpenHint)
+				}
+			}
+		}
+		return
+	}
+
+	if *demoMode {
+		tree := demoInventory()
+		m := newModel(tree.Root.Path, false)
+		m.inventory = tree
+		runProgram(m)
+		return
+	}
+
+	if *agentMode {
+		root := flag.Arg(0)
+		if root == "" {
+			root, _ = os.UserHomeDir()
+		}
+		abs, err := filepath.Abs(root)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cannot resolve %q: %v\n", root, err)
+			os.Exit(1)
+		}
+		if err := runAgent(*agentAddr, abs); err != nil {
+			fmt.Fprintf(os.Stderr, "agent stopped: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *connectAddr != "" {
+		stdin := bufio.NewReader(os.Stdin)
+		pinned := strings.TrimSpace(*fingerprint)
+		if pinned == "" {
+			// Confirm the certificate before the pairing code leaves this Mac.
+			seen, err := probeAgentFingerprint(*connectAddr)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "cannot reach agent: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Fprintf(os.Stderr, "Agent fingerprint: %s\nDoes it match the fingerprint on the agent's screen? [y/N] ", seen)
+			answer, _ := stdin.ReadString('\n')
+			if !strings.EqualFold(strings.TrimSpace(answer), "y") {
+				fmt.Fprintln(os.Stderr, "Not connecting; the pairing code was not sent")
+				os.Exit(1)
+			}
+			pinned = seen
+		}
+		code := strings.TrimSpace(*pairingCode)
+		if code == "" {
+			fmt.Fprint(os.Stderr, "Pairing code: ")
+			line, _ := stdin.ReadString('\n')
+			code = strings.TrimSpace(line)
+		}
+		fmt.Fprintf(os.Stderr, "Fetching inventory from %s...\n", *connectAddr)
+		tree, err := fetchAgentInventory(*connectAddr, code, pinned, flag.Arg(0))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "remote analysis failed: %v\n", err)
+			os.Exit(1)
+		}
+		m := newModel(tree.Root.Path, false)
+		m.inventory = tree
+		runProgram(m)
+		return
+	}
+
+	if *sshTarget != "" {
+		host, remotePath, err := parseSSHTarget(*sshTarget)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "Fetching inventory from %s...\n", host)
+		tree, err := fetchRemoteInventory(host, remotePath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "remote analysis failed: %v\n", err)
+			os.Exit(1)
+		}
+		m := newModel(tree.Root.Path, false)
+		m.inventory = tree
+		runProgram(m)
+		return
+	}
+
+	if *loadFile == "" && flag.NArg() == 0 && os.Getenv("MO_ANALYZE_PATH") == "" && stdinIsPipe() {
+		*loadFile = "-"
+	}
+	if *loadFile != "" {
+		tree, err := loadInventoryFile(*loadFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "load failed: %v\n", err)
+			os.Exit(1)
+		}
+		m := newModel(tree.Root.Path, false)
+		m.inventory = tree
+		// Keys come from the terminal when the listing arrives on stdin.
+		runProgram(m, tea.WithInputTTY())
+		return
+	}
+
 	target := os.Getenv("MO_ANALYZE_PATH")
-	if target == "" && len(os.Args) > 1 {
-		target = os.Args[1]
+	if target == "" && flag.NArg() > 0 {
+		target = flag.Arg(0)
 	}
 
 	var abs string
@@ -142,12 +514,48 @@ func main() {
 		isOverview = false
 	}
 
+	if *sudo {
+		if err := startPrivileged(); err != nil {
+			fmt.Fprintf(os.Stderr, "--sudo: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Drop the caches of folders FSEvents saw change since the last launch, before
+	// anything reads them. ~/Library is inside the home root.
+	journalRoots := []string{abs}
+	if isOverview {
+		journalRoots = nil
+		for _, entry := range createOverviewEntries() {
+			if entry.Path != volumesRoot && filepath.Dir(entry.Path) != os.Getenv("HOME") {
+				journalRoots = append(journalRoots, entry.Path)
+			}
+		}
+	}
+	journalCtx, journalCancel := context.WithTimeout(context.Background(), journalReplayTimeout)
+	_, _ = replayChangeJournals(journalCtx, journalRoots)
+	journalCancel()
+
 	// Warm overview cache in background.
 	prefetchCtx, prefetchCancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer prefetchCancel()
 	go prefetchOverviewCache(prefetchCtx)
 
-	p := tea.NewProgram(newModel(abs, isOverview), tea.WithAltScreen())
+	m := newModel(abs, isOverview)
+	m.safeMode = *safeMode || config.SafeMode
+	m.permanentDelete = *permanent || config.PermanentDelete
+	m.watch = *watch
+	if config.HasDefaultSort {
+		m.entrySort = config.DefaultSort
+	}
+	m.stagedDue, m.stagedDueSize = stagingReminder(loadStaged(), time.Now())
+	// Safe mode wins: the palette can run arbitrary commands.
+	m.expertMode = *expertMode && !m.safeMode
+	runProgram(m)
+}
+
+func runProgram(m model, opts ...tea.ProgramOption) {
+	p := tea.NewProgram(m, append([]tea.ProgramOption{tea.WithAltScreen(), tea.WithReportFocus()}, opts...)...)
 	if err := p.Start(); err != nil {
 		fmt.Fprintf(os.Stderr, "analyzer error: %v\n", err)
 		os.Exit(1)
@@ -180,6 +588,12 @@ func newModel(path string, isOverview bool) model {
 		overviewScanningSet:  make(map[string]bool),
 		multiSelected:        make(map[string]bool),
 		largeMultiSelected:   make(map[string]bool),
+		volumeTrash:          make(map[string]int64),
+		volumeHealth:         make(map[string]driveHealth),
+		rows:                 newRowCache(),
+		changesOffset:        changesEnd(),
+		partial:              &partialScan{},
+		scanControl:          &scanControl{},
 	}
 
 	if isOverview {
@@ -202,6 +616,18 @@ func createOverviewEntries() []dirEntry {
 	home := os.Getenv("HOME")
 	entries := []dirEntry{}
 
+	// A workspace's roots stand in for the usual folders.
+	if len(overviewRoots) > 0 {
+		for _, root := range overviewRoots {
+			name := filepath.Base(root)
+			if root == home {
+				name = "Home"
+			}
+			entries = append(entries, dirEntry{Name: name, Path: root, IsDir: true, Size: -1})
+		}
+		return entries
+	}
+
 	// Separate Home and ~/Library to avoid double counting.
 	if home != "" {
 		entries = append(entries, dirEntry{Name: "Home", Path: home, IsDir: true, Size: -1})
@@ -222,6 +648,10 @@ func createOverviewEntries() []dirEntry {
 		entries = append(entries, dirEntry{Name: "Volumes", Path: "/Volumes", IsDir: true, Size: -1})
 	}
 
+	if privilegedMode.Load() {
+		entries = append(entries, privilegedOverviewEntries(home)...)
+	}
+
 	return entries
 }
 
@@ -318,7 +748,7 @@ func (m *model) scheduleOverviewScans() tea.Cmd {
 	if len(pendingIndices) > 0 {
 		firstEntry := m.entries[pendingIndices[0]]
 		if len(pendingIndices) == 1 {
-			m.status = fmt.Sprintf("Scanning %s... (%d left)", firstEntry.Name, remaining)
+			m.status = fmt.Sprintf("Scanning %s... (%d left)", displayName(firstEntry.Path, firstEntry.Name), remaining)
 		} else {
 			m.status = fmt.Sprintf("Scanning %d directories... (%d left)", len(pendingIndices), remaining)
 		}
@@ -342,25 +772,68 @@ func (m *model) getScanProgress() (files, dirs, bytes int64) {
 }
 
 func (m model) Init() tea.Cmd {
+	var watch tea.Cmd
+	if m.watch {
+		watch = watchTickCmd()
+	}
+	changes := pollChangesCmd(m.changesOffset)
 	if m.inOverviewMode() {
-		return m.scheduleOverviewScans()
+		return tea.Batch(m.scheduleOverviewScans(), watch, changes, checkPowerSource)
 	}
-	return tea.Batch(m.scanCmd(m.path), tickCmd())
+	return tea.Batch(m.scanCmd(m.path), tickCmd(), watch, changes, checkPowerSource)
 }
 
+// scanCmd runs the exact scan alongside a quick sampling estimate, which is
+// skipped when the exact scan is served from the cache.
 func (m model) scanCmd(path string) tea.Cmd {
+	if m.inventory != nil {
+		return m.exactScanCmd(path, nil)
+	}
+	cacheHit := make(chan bool, 1)
+	return tea.Batch(m.exactScanCmd(path, cacheHit), estimateScanCmd(path, cacheHit))
+}
+
+func (m model) exactScanCmd(path string, cacheHit chan<- bool) tea.Cmd {
+	if m.inventory != nil {
+		return func() tea.Msg {
+			result, err := m.inventory.scanResultFor(path)
+			return scanResultMsg{result: result, err: err}
+		}
+	}
+	// Registered now, inside Update, so a late result of a cancelled scan can tell
+	// that this one replaced it.
+	ctx, done := m.scanControl.start()
 	return func() tea.Msg {
-		if cached, err := loadCacheFromDisk(path); err == nil {
+		defer done()
+		cached, err := loadCacheFromDisk(path)
+		hit := err == nil && (cached.Omitted == 0 || !fullListing.Load())
+		if cacheHit != nil {
+			cacheHit <- hit
+		}
+		if hit {
 			result := scanResult{
-				Entries:    cached.Entries,
-				LargeFiles: cached.LargeFiles,
-				TotalSize:  cached.TotalSize,
+				Entries:     cached.Entries,
+				LargeFiles:  cached.LargeFiles,
+				TotalSize:   cached.TotalSize,
+				Omitted:     cached.Omitted,
+				OmittedSize: cached.OmittedSize,
 			}
 			return scanResultMsg{result: result, err: nil}
 		}
 
+		var usage *runUsage
+		// Battery scans estimate folded folders; keep them out of the cache and history.
+		estimated := batteryPower.Load()
 		v, err, _ := scanGroup.Do(path, func() (interface{}, error) {
-			return scanPathConcurrent(path, m.filesScanned, m.dirsScanned, m.bytesScanned, m.currentPath)
+			applyDeviceProfile(path)
+			sampler := startUsageSampler()
+			result, err := scanPathConcurrent(ctx, path, m.filesScanned, m.dirsScanned, m.bytesScanned, m.currentPath, m.partial)
+			run := sampler.finish(atomic.LoadInt64(m.dirsScanned))
+			if err == nil {
+				recordScanThroughput(path, run.Wall, run.Dirs, atomic.LoadInt64(m.bytesScanned), scanPool.stats().Limit)
+				usage = &run
+			}
+			return result, err
 		})
 
 		if err != nil {
@@ -368,17 +841,73 @@ func (m model) scanCmd(path string) tea.Cmd {
 		}
 
 		result := v.(scanResult)
+		if estimated || batteryPower.Load() {
+			return scanResultMsg{result: result, usage: usage}
+		}
 
 		go func(p string, r scanResult) {
 			if err := saveCacheToDisk(p, r); err != nil {
 				_ = err // Cache save failure is not critical
 			}
+			_ = recordScanSnapshot(p, r)
 		}(path, result)
 
-		return scanResultMsg{result: result, err: nil}
+		return scanResultMsg{result: result, usage: usage}
 	}
 }
 
+// cancelScan stops the running scan; its result arrives as context.Canceled.
+func (m model) cancelScan() (model, bool) {
+	// Waiters blocked on a paused pool must wake to see the cancellation.
+	if m.scanPaused {
+		m.scanPaused = false
+		scanPool.setPaused(false)
+	}
+	if !m.scanControl.stop() {
+		return m, false
+	}
+	// A rescan must start afresh instead of joining the call that is winding down.
+	scanGroup.Forget(m.path)
+	m.status = "Cancelling scan..."
+	return m, true
+}
+
+// toggleScanPause holds or releases the worker pool while a scan runs.
+func (m model) toggleScanPause() model {
+	m.scanPaused = !m.scanPaused
+	scanPool.setPaused(m.scanPaused)
+	if m.scanPaused {
+		m.status = "Scan paused: no new folders are read, du runs already started still finish; p resumes, Esc cancels"
+	} else {
+		m.status = "Scan resumed"
+	}
+	return m
+}
+
+// showCancelledScan lists the entries that finished before the scan was cancelled.
+// The listing is incomplete, so it is neither cached nor recorded.
+func (m model) showCancelledScan() model {
+	m.drilling = false
+	var entries []dirEntry
+	var total int64
+	for _, e := range m.partial.snapshot(m.path) {
+		if e.Size > 0 {
+			entries = append(entries, e)
+			total += e.Size
+		}
+	}
+	m.entries = m.orderEntries(entries)
+	m.largeFiles = nil
+	m.refilter(m.entries, m.largeFiles)
+	m.totalSize = total
+	m.omitted, m.omittedSize = 0, 0
+	m.watchChanges = nil
+	m.status = fmt.Sprintf("Scan cancelled, showing %d finished entries (%s), r rescans", len(m.entries), humanizeBytes(total))
+	m.clampEntrySelection()
+	m.clampLargeSelection()
+	return m
+}
+
 func tickCmd() tea.Cmd {
 	return tea.Tick(time.Millisecond*80, func(t time.Time) tea.Msg {
 		return tickMsg(t)
@@ -392,43 +921,151 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		// Keep the cursor on screen when the terminal shrinks.
+		m.clampEntrySelection()
+		m.clampLargeSelection()
+		return m, nil
+	case tea.BlurMsg:
+		if m.backgroundLimit == 0 {
+			m.backgroundLimit = throttleForBackground()
+		}
+		return m, nil
+	case tea.FocusMsg:
+		if m.backgroundLimit > 0 {
+			restoreForeground(m.backgroundLimit)
+			m.backgroundLimit = 0
+		}
 		return m, nil
 	case deleteProgressMsg:
 		if msg.done {
+			m.quitGuard.holdDeletes(msg)
 			m.deleting = false
+			m.deleteBatch = nil
 			m.multiSelected = make(map[string]bool)
 			m.largeMultiSelected = make(map[string]bool)
-			if msg.err != nil {
+			recordDeletions(msg.deleted, msg.trashed)
+			var verify tea.Cmd
+			if check := m.deletionCheck(msg); check.Expected >= verifyMinBytes {
+				verify = verifyDeletionCmd(check)
+			}
+			m.deleteFailures = msg.failures
+			if msg.err != nil && msg.count == 0 && len(msg.deleted) == 0 {
 				m.status = fmt.Sprintf("Failed to delete: %v", msg.err)
+				return m, verify
+			}
+			for _, path := range msg.deleted {
+				m.removePathFromView(path)
+			}
+			for _, path := range msg.requested {
+				invalidateCache(path)
+			}
+			if m.dupes != nil {
+				m.dupes.Groups = pruneDuplicateGroups(m.dupes.Groups, msg.deleted)
+				m.dupes.Selected = 0
+			}
+			if m.versions != nil {
+				m.versions.prune(msg.deleted)
+			}
+			uninstalled := ""
+			if m.uninstall != nil {
+				uninstalled = m.uninstall.Plan.App.Name
+				m.uninstall = nil
+			}
+			_ = unstagePaths(msg.deleted)
+			if m.staging != nil {
+				m.staging.remove(msg.deleted)
+			}
+			invalidateCache(m.path)
+			if msg.trashed {
+				m.status = fmt.Sprintf("Moved %d items to Trash", msg.count)
 			} else {
-				if msg.path != "" {
-					m.removePathFromView(msg.path)
-					invalidateCache(msg.path)
-				}
-				invalidateCache(m.path)
 				m.status = fmt.Sprintf("Deleted %d items", msg.count)
-				for i := range m.history {
-					m.history[i].Dirty = true
-				}
-				for path := range m.cache {
-					entry := m.cache[path]
-					entry.Dirty = true
-					m.cache[path] = entry
-				}
-				m.scanning = true
-				atomic.StoreInt64(m.filesScanned, 0)
-				atomic.StoreInt64(m.dirsScanned, 0)
-				atomic.StoreInt64(m.bytesScanned, 0)
-				if m.currentPath != nil {
-					*m.currentPath = ""
-				}
-				return m, tea.Batch(m.scanCmd(m.path), tickCmd())
 			}
+			if len(msg.failures) > 0 {
+				m.status += fmt.Sprintf(", %d could not be removed", len(msg.failures))
+			}
+			if uninstalled != "" {
+				m.status = fmt.Sprintf("Uninstalled %s: %s", uninstalled, m.status)
+			}
+			for i := range m.history {
+				m.history[i].Dirty = true
+			}
+			for path := range m.cache {
+				entry := m.cache[path]
+				entry.Dirty = true
+				m.cache[path] = entry
+			}
+			m.scanning = true
+			atomic.StoreInt64(m.filesScanned, 0)
+			atomic.StoreInt64(m.dirsScanned, 0)
+			atomic.StoreInt64(m.bytesScanned, 0)
+			if m.currentPath != nil {
+				*m.currentPath = ""
+			}
+			return m, tea.Batch(m.scanCmd(m.path), tickCmd(), verify)
+		}
+		return m, nil
+	case quickLookMsg:
+		if msg.Err != nil {
+			m.status = fmt.Sprintf("Cannot preview %s: %v", displayName(msg.Path, filepath.Base(msg.Path)), msg.Err)
+		} else if strings.HasPrefix(m.status, "Previewing ") {
+			m.status = ""
+		}
+		return m, nil
+	case compressDoneMsg:
+		return m.finishCompress(msg)
+	case relocateDoneMsg:
+		m.quitGuard.holdRelocations(msg.Skipped)
+		return m.finishRelocate(msg)
+	case evictDoneMsg:
+		m.evicting = false
+		m.status = fmt.Sprintf("Evicted %d items, %s freed on this Mac", len(msg.Evicted), humanizeBytes(msg.Freed))
+		if len(msg.Failed) > 0 {
+			m.status += fmt.Sprintf(", %d could not be evicted", len(msg.Failed))
+		}
+		if len(msg.Evicted) == 0 {
+			return m, nil
+		}
+		for _, path := range msg.Evicted {
+			invalidateCache(path)
+		}
+		invalidateCache(m.path)
+		for i := range m.history {
+			m.history[i].Dirty = true
+		}
+		m.multiSelected = make(map[string]bool)
+		m.largeMultiSelected = make(map[string]bool)
+		if m.inOverviewMode() {
+			return m, nil
+		}
+		m.scanning = true
+		atomic.StoreInt64(m.filesScanned, 0)
+		atomic.StoreInt64(m.dirsScanned, 0)
+		atomic.StoreInt64(m.bytesScanned, 0)
+		return m, tea.Batch(m.scanCmd(m.path), tickCmd())
+	case verifyMsg:
+		m.verify = &msg.Report
+		return m, nil
+	case scanEstimateMsg:
+		if m.scanning && msg.Path == m.path {
+			m.estimates = msg.Entries
 		}
 		return m, nil
 	case scanResultMsg:
+		if errors.Is(msg.err, context.Canceled) && m.scanControl.running() {
+			return m, nil // A rescan replaced the cancelled scan and is still going
+		}
 		m.scanning = false
+		m.estimates = nil
+		if m.scanPaused {
+			m.scanPaused = false
+			scanPool.setPaused(false)
+		}
+		if errors.Is(msg.err, context.Canceled) {
+			return m.showCancelledScan(), nil
+		}
 		if msg.err != nil {
+			m.drilling = false
 			m.status = fmt.Sprintf("Scan failed: %v", msg.err)
 			return m, nil
 		}
@@ -438,14 +1075,21 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				filteredEntries = append(filteredEntries, e)
 			}
 		}
-		m.entries = filteredEntries
-		m.largeFiles = msg.result.LargeFiles
+		m.entries = m.orderEntries(filteredEntries)
+		m.largeFiles = sortLargeFiles(filterLargeFiles(msg.result.LargeFiles, minLargeFileSize), m.largeSort)
+		m.refilter(m.entries, m.largeFiles)
 		m.totalSize = msg.result.TotalSize
+		m.omitted, m.omittedSize = msg.result.Omitted, msg.result.OmittedSize
+		m.watchChanges = nil
 		m.status = fmt.Sprintf("Scanned %s", humanizeBytes(m.totalSize))
+		if msg.usage != nil {
+			m.lastUsage = msg.usage
+			m.status += " in " + msg.usage.Wall.Round(100*time.Millisecond).String()
+		}
 		m.clampEntrySelection()
 		m.clampLargeSelection()
 		m.cache[m.path] = cacheSnapshot(m)
-		if m.totalSize > 0 {
+		if m.totalSize > 0 && m.inventory == nil {
 			if m.overviewSizeCache == nil {
 				m.overviewSizeCache = make(map[string]int64)
 			}
@@ -454,9 +1098,61 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				_ = storeOverviewSize(path, size)
 			}(m.path, m.totalSize)
 		}
+		if m.drilling {
+			return m.drillStep()
+		}
+		return m, tea.Batch(m.scheduleVolumeTrashScans(), m.scheduleDriveHealthChecks(), m.scheduleAppOwners())
+	case appOwnersMsg:
+		if m.appOwners == nil {
+			m.appOwners = make(map[string]appOwner)
+		}
+		for path, owner := range msg.Owners {
+			m.appOwners[path] = owner
+		}
+		if orphaned := orphanedAppData(m.entries, m.appOwners); len(orphaned) > 0 && !m.scanning {
+			m.status = formatOrphanedSummary(orphaned) + "; u selects them"
+		}
 		return m, nil
+	case powerSourceMsg:
+		if msg.Err != nil {
+			return m, nil // No pmset: stop polling
+		}
+		if setBatteryThrottle(msg.OnBattery) {
+			if msg.OnBattery {
+				m.status = "On battery: scanning with fewer workers and estimating folded folders"
+			} else {
+				m.status = "On AC power: scanning at full speed again"
+			}
+		}
+		return m, powerSourceCmd()
+	case stateChangesMsg:
+		m.changesOffset = msg.Offset
+		m.cleanRunning = msg.CleanRunning
+		if msg.LowSpace > 0 && m.lowSpace == 0 {
+			m.status = fmt.Sprintf("Only %s free: caches, history and exports are paused until space is freed", humanizeBytes(msg.LowSpace))
+		}
+		m.lowSpace = msg.LowSpace
+		return m.applyExternalChanges(msg.Paths)
+	case watchTickMsg:
+		if m.watchScanning || m.scanning || m.deleting || m.inOverviewMode() || m.inventory != nil {
+			return m, watchTickCmd()
+		}
+		m.watchScanning = true
+		return m, tea.Batch(watchScanCmd(m.path), watchTickCmd())
+	case watchScanMsg:
+		m.watchScanning = false
+		if msg.Err != nil || msg.Path != m.path || m.scanning || m.deleting {
+			return m, nil
+		}
+		return m.applyWatchScan(msg.Result), nil
 	case overviewSizeMsg:
 		delete(m.overviewScanningSet, msg.Path)
+		if m.providers != nil {
+			m.providers.Measurements = recentSizeMeasurements()
+			if msg.Err == nil {
+				m.status = fmt.Sprintf("%s is %s", displayPath(msg.Path), humanizeBytes(msg.Size))
+			}
+		}
 
 		if msg.Err == nil {
 			if m.overviewSizeCache == nil {
@@ -486,7 +1182,242 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, cmd
 		}
 		return m, nil
+	case volumeTrashMsg:
+		if m.volumeTrash == nil {
+			m.volumeTrash = make(map[string]int64)
+		}
+		m.volumeTrash[msg.Volume] = msg.Size
+		return m, nil
+	case driveHealthMsg:
+		if msg.Err == nil && m.volumeHealth != nil {
+			m.volumeHealth[msg.Volume] = msg.Health
+		}
+		return m, nil
+	case volumeTrashEmptiedMsg:
+		m.deleting = false
+		delete(m.volumeTrash, msg.Volume)
+		switch {
+		case msg.Err != nil:
+			m.status = fmt.Sprintf("Failed to empty trash on %s: %v", filepath.Base(msg.Volume), msg.Err)
+		case msg.Skipped > 0:
+			m.status = fmt.Sprintf("Removed %d items, skipped %d other users' trash (needs sudo)", msg.Count, msg.Skipped)
+		default:
+			m.status = fmt.Sprintf("Emptied trash on %s (%d items)", filepath.Base(msg.Volume), msg.Count)
+		}
+		invalidateCache(msg.Volume)
+		return m, volumeTrashCmd(msg.Volume)
+	case explainMsg:
+		if msg.Err != nil {
+			m.status = fmt.Sprintf("Unable to explain %s: %v", displayPath(msg.Path), msg.Err)
+			return m, nil
+		}
+		if owner, ok := m.appOwners[msg.Path]; ok {
+			msg.Text += " Written by " + owner.describe() + "."
+		}
+		m.explanation = &msg
+		m.status = "Ready"
+		return m, nil
+	case reinstallHintsMsg:
+		if m.deleteConfirm {
+			m.reinstallHints = msg.Hints
+		}
+		return m, nil
+	case backupSnapshotsMsg:
+		if m.backups != nil && m.backups.Root == msg.Root {
+			m.backups.Scanning = false
+			m.backups.Snapshots = msg.Snapshots
+			m.backups.Err = msg.Err
+			m.status = fmt.Sprintf("%d snapshots in %s", len(msg.Snapshots), displayPath(msg.Root))
+		}
+		return m, nil
+	case storageCategoriesMsg:
+		if m.storage != nil {
+			m.storage.Scanning = false
+			m.storage.Categories = msg.Categories
+			m.storage.Used = msg.Used
+			m.storage.Err = msg.Err
+			m.status = fmt.Sprintf("%s used on the startup volume", humanizeBytes(msg.Used))
+		}
+		return m, nil
+	case cleanPreviewMsg:
+		if m.cleanPreview != nil {
+			m.cleanPreview.Scanning = false
+			m.cleanPreview.Result = msg.Result
+			m.cleanPreview.Err = msg.Err
+			m.status = fmt.Sprintf("mo clean would recover %s", humanizeBytes(msg.Result.Size))
+		}
+		return m, nil
+	case cleanDoneMsg:
+		return m.applyCleanDone(msg)
+	case brewFootprintMsg:
+		if m.homebrew != nil {
+			m.homebrew.Scanning = false
+			m.homebrew.Footprint = msg.Footprint
+			m.homebrew.Err = msg.Err
+			m.homebrew.Selected = min(m.homebrew.Selected, m.homebrew.rows()-1)
+			if msg.Err == nil && !m.homebrew.Refresh {
+				m.status = fmt.Sprintf("brew cleanup would reclaim %s", humanizeBytes(msg.Footprint.reclaimable()))
+			}
+		}
+		return m, nil
+	case brewCleanupMsg:
+		return m.applyBrewCleanup(msg)
+	case migrationPlanMsg:
+		if m.migration != nil {
+			m.migration.Scanning = false
+			m.migration.Plan = msg.Plan
+			m.migration.Err = msg.Err
+			if msg.Err == nil {
+				m.status = fmt.Sprintf("%s to migrate, %s to archive, %s to recreate",
+					humanizeBytes(msg.Plan.Totals[bucketMigrate]), humanizeBytes(msg.Plan.Totals[bucketArchive]), humanizeBytes(msg.Plan.Totals[bucketRecreate]))
+			}
+		}
+		return m, nil
+	case packageCachesMsg:
+		if m.packageCaches != nil {
+			m.packageCaches.Scanning = false
+			m.packageCaches.Caches = msg.Caches
+			m.packageCaches.Selected = min(m.packageCaches.Selected, max(len(msg.Caches)-1, 0))
+			var total int64
+			for _, cache := range msg.Caches {
+				total += cache.Size
+			}
+			if !m.packageCaches.Refresh {
+				m.status = fmt.Sprintf("%s in %d package manager caches", humanizeBytes(total), len(msg.Caches))
+			}
+		}
+		return m, nil
+	case packageCacheCleanMsg:
+		return m.applyPackageCacheClean(msg)
+	case uninstallPlanMsg:
+		if m.uninstall != nil {
+			m.uninstall.Scanning = false
+			m.uninstall.Plan = msg.Plan
+			m.uninstall.Err = msg.Err
+			if msg.Err == nil {
+				m.status = fmt.Sprintf("%s with its data is %s in %d items", msg.Plan.App.Name, humanizeBytes(msg.Plan.Size), len(msg.Plan.Items))
+			}
+		}
+		return m, nil
+	case migrationExportMsg:
+		m.exporting--
+		if msg.Err != nil {
+			m.status = fmt.Sprintf("Export failed: %v", msg.Err)
+		} else {
+			m.status = exportStatus("Saved migration checklist", msg.Path)
+		}
+		return m, nil
+	case appDataMsg:
+		if m.appData != nil {
+			m.appData.Scanning = false
+			m.appData.Apps = msg.Apps
+			m.appData.Err = msg.Err
+			m.status = fmt.Sprintf("%d apps keep data in containers", len(msg.Apps))
+		}
+		return m, nil
+	case alertTestMsg:
+		if msg.Err != nil {
+			m.status = fmt.Sprintf("Unable to show a notification: %v", msg.Err)
+		} else {
+			m.status = fmt.Sprintf("Sent \"%s: %s\"", msg.Event.Title, msg.Event.Message)
+		}
+		return m, nil
+	case staleItemsMsg:
+		if m.stale != nil && m.stale.Root == msg.Root && m.stale.ByModified == msg.ByModified {
+			m.stale.Scanning = false
+			m.stale.Items = msg.Items
+			m.stale.Totals = msg.Totals
+			m.stale.Scanned = msg.At
+			m.stale.Err = msg.Err
+			m.stale.Selected = 0
+			m.status = fmt.Sprintf("%d stale items in %s", len(msg.Items), displayPath(msg.Root))
+		}
+		return m, nil
+	case savedSearchResultsMsg:
+		if m.searches != nil {
+			m.searches.Running = false
+			m.searches.Results = msg.Results
+			m.status = fmt.Sprintf("%d saved searches", len(m.searches.Searches))
+		}
+		return m, nil
+	case movePlansMsg:
+		if m.organize != nil {
+			m.organize.Planning = false
+			m.organize.Plans = msg.Plans
+		}
+		return m, nil
+	case moveProgressMsg:
+		m.quitGuard.holdMoves(msg.Skipped)
+		return m.finishMoves(msg)
+	case largeRequeryMsg:
+		if msg.Path != m.path || msg.Threshold != minLargeFileSize {
+			return m, nil
+		}
+		if msg.Files == nil {
+			m.status = fmt.Sprintf("Spotlight found no files of %s and up, press R to rescan", humanizeBytes(msg.Threshold))
+			return m, nil
+		}
+		m.replaceLargeFiles(msg.Files)
+		m.status = fmt.Sprintf("Showing %d files of %s and up", len(m.largeFiles), humanizeBytes(msg.Threshold))
+		return m, nil
+	case localSnapshotsMsg:
+		if m.snapshots != nil && m.snapshots.Volume == msg.Volume {
+			m.snapshots.Scanning = false
+			m.snapshots.Snapshots = msg.Snapshots
+			m.snapshots.Measured = msg.Measured
+			m.snapshots.Err = msg.Err
+			m.snapshots.Selected = min(m.snapshots.Selected, max(len(msg.Snapshots)-1, 0))
+			m.status = fmt.Sprintf("%d local snapshots on %s", len(msg.Snapshots), msg.Volume)
+		}
+		return m, nil
+	case snapshotActionMsg:
+		m.status = msg.Status
+		if m.snapshots != nil {
+			m.snapshots.Scanning = true
+			return m, tea.Batch(measureLocalSnapshotsCmd(m.snapshots.Volume, m.snapshots.Rel, m.snapshots.Live), tickCmd())
+		}
+		return m, nil
+	case installerFamiliesMsg:
+		if m.versions != nil {
+			m.versions.Scanning = false
+			m.versions.Families = msg.Families
+			m.status = fmt.Sprintf("%d installer families with older versions", len(msg.Families))
+		}
+		return m, nil
+	case duplicatesMsg:
+		if m.dupes != nil && m.dupes.Root == msg.Root {
+			m.dupes.Scanning = false
+			m.dupes.Groups = msg.Groups
+			m.dupes.Err = msg.Err
+			m.duplicates = duplicateIndex(msg.Groups)
+			var reclaimable int64
+			for _, group := range msg.Groups {
+				reclaimable += group.reclaimable()
+			}
+			m.status = fmt.Sprintf("%d duplicate groups, %s reclaimable", len(msg.Groups), humanizeBytes(reclaimable))
+		}
+		return m, nil
+	case paletteDoneMsg:
+		m.status = msg.Status
+		return m, nil
+	case exportDoneMsg:
+		m.exporting--
+		if msg.Err != nil {
+			m.status = fmt.Sprintf("Export failed: %v", msg.Err)
+		} else {
+			m.status = exportStatus("Exported ncdu JSON", msg.Path)
+		}
+		return m, nil
+	case exactSizeMsg:
+		m.exactSize = &msg
+		if msg.Err != nil {
+			m.status = fmt.Sprintf("Unable to measure %s: %v", displayPath(msg.Path), msg.Err)
+		}
+		return m, nil
 	case tickMsg:
+		if m.quitGuard != nil && m.quitGuard.Mode != quitAsk {
+			return m.tickQuitGuard()
+		}
 		hasPending := false
 		if m.inOverviewMode() {
 			for _, entry := range m.entries {
@@ -496,11 +1427,14 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 		}
-		if m.scanning || m.deleting || (m.inOverviewMode() && (m.overviewScanning || hasPending)) {
+		if m.scanning || m.deleting || (m.dupes != nil && m.dupes.Scanning) || (m.versions != nil && m.versions.Scanning) ||
+			(m.backups != nil && m.backups.Scanning) || (m.snapshots != nil && m.snapshots.Scanning) || (m.storage != nil && m.storage.Scanning) || (m.cleanPreview != nil && (m.cleanPreview.Scanning || m.cleanPreview.Cleaning)) || (m.homebrew != nil && (m.homebrew.Scanning || m.homebrew.Cleaning)) || (m.migration != nil && m.migration.Scanning) || (m.packageCaches != nil && (m.packageCaches.Scanning || m.packageCaches.Cleaning)) || (m.uninstall != nil && m.uninstall.Scanning) || (m.appData != nil && m.appData.Scanning) || (m.stale != nil && m.stale.Scanning) || (m.searches != nil && m.searches.Running) || (m.organize != nil && (m.organize.Planning || m.organize.Moving != nil)) || (m.compress != nil && m.compress.Running) || (m.relocate != nil && m.relocate.Running) || (m.inOverviewMode() && (m.overviewScanning || hasPending)) {
 			m.spinner = (m.spinner + 1) % len(spinnerFrames)
 			if m.deleting && m.deleteCount != nil {
 				count := atomic.LoadInt64(m.deleteCount)
-				if count > 0 {
+				if count > 0 && m.deleteBatch != nil && !m.deleteBatch.Permanent {
+					m.status = fmt.Sprintf("Moving to Trash... %s items moved", formatNumber(count))
+				} else if count > 0 {
 					m.status = fmt.Sprintf("Deleting... %s items removed", formatNumber(count))
 				}
 			}
@@ -512,12 +1446,61 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 }
 
-func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// Any key stops an in-progress drill; the running scan still completes.
+	m.drilling = false
+
+	if m.quitGuard != nil {
+		return m.updateQuitGuardKey(msg)
+	}
+	if m.filter != nil && m.filter.Editing {
+		return m.updateFilterKey(msg)
+	}
+	if m.palette != nil {
+		return m.updatePaletteKey(msg)
+	}
+	if m.actionPalette != nil {
+		return m.updateActionPaletteKey(msg)
+	}
+	msg = remapKey(msg)
+
+	if m.evictConfirm != nil {
+		return m.updateEvictConfirmKey(msg)
+	}
+	if m.compress != nil && !m.compress.Running {
+		return m.updateCompressConfirmKey(msg)
+	}
+	if m.relocate != nil && !m.relocate.Running {
+		return m.updateRelocateKey(msg)
+	}
+
+	// Volume trash confirm flow.
+	if m.trashConfirm != "" {
+		volume := m.trashConfirm
+		m.trashConfirm = ""
+		if msg.String() == "E" || msg.String() == "enter" {
+			m.deleting = true
+			m.deleteBatch = nil
+			var deleteCount int64
+			m.deleteCount = &deleteCount
+			m.status = fmt.Sprintf("Emptying trash on %s...", filepath.Base(volume))
+			return m, tea.Batch(emptyVolumeTrashCmd(volume, m.deleteCount), tickCmd())
+		}
+		m.status = "Cancelled"
+		return m, nil
+	}
+
 	// Delete confirm flow.
 	if m.deleteConfirm {
 		switch msg.String() {
-		case "delete", "backspace":
+		case "delete", "backspace", "alt+delete", "alt+backspace", "u", "U":
+			unlock := strings.EqualFold(msg.String(), "u")
+			if unlock && !anyUnlockable(m.deleteLocks) {
+				return m, nil
+			}
+			permanent := m.deletePermanent || strings.HasPrefix(msg.String(), "alt+")
 			m.deleteConfirm = false
+			m.deletePermanent = false
 			m.deleting = true
 			var deleteCount int64
 			m.deleteCount = &deleteCount
@@ -549,17 +1532,25 @@ func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				return m, nil
 			}
 
+			m.deleteBatch = &deleteBatchProgress{Total: len(pathsToDelete), Permanent: permanent}
+			verb := "Moving to Trash"
+			if permanent {
+				verb = "Deleting"
+			}
 			if len(pathsToDelete) == 1 {
-				targetPath := pathsToDelete[0]
-				m.status = fmt.Sprintf("Deleting %s...", filepath.Base(targetPath))
-				return m, tea.Batch(deletePathCmd(targetPath, m.deleteCount), tickCmd())
+				m.status = fmt.Sprintf("%s %s...", verb, displayName(pathsToDelete[0], filepath.Base(pathsToDelete[0])))
+			} else {
+				m.status = fmt.Sprintf("%s %d items...", verb, len(pathsToDelete))
 			}
-
-			m.status = fmt.Sprintf("Deleting %d items...", len(pathsToDelete))
-			return m, tea.Batch(deleteMultiplePathsCmd(pathsToDelete, m.deleteCount), tickCmd())
+			del := deletePathCmd(pathsToDelete, m.deleteCount, m.deleteBatch, permanent)
+			if unlock {
+				del = unlockThenDeleteCmd(pathsToDelete, del)
+			}
+			return m, tea.Batch(del, tickCmd())
 		case "esc", "q":
 			m.status = "Cancelled"
 			m.deleteConfirm = false
+			m.deletePermanent = false
 			m.deleteTarget = nil
 			return m, nil
 		default:
@@ -567,15 +1558,130 @@ func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 	}
 
+	if m.dupes != nil {
+		return m.updateDuplicatesKey(msg)
+	}
+	if m.staging != nil {
+		return m.updateStagingKey(msg)
+	}
+	if m.versions != nil {
+		return m.updateVersionsKey(msg)
+	}
+	if m.backups != nil {
+		return m.updateBackupsKey(msg)
+	}
+	if m.snapshots != nil {
+		return m.updateSnapshotsKey(msg)
+	}
+	if m.storage != nil {
+		return m.updateStorageKey(msg)
+	}
+	if m.cleanPreview != nil {
+		return m.updateCleanPreviewKey(msg)
+	}
+	if m.homebrew != nil {
+		return m.updateHomebrewKey(msg)
+	}
+	if m.migration != nil {
+		return m.updateMigrationKey(msg)
+	}
+	if m.packageCaches != nil {
+		return m.updatePackageCacheKey(msg)
+	}
+	if m.uninstall != nil {
+		return m.updateUninstallKey(msg)
+	}
+	if m.preview != nil {
+		return m.updatePreviewKey(msg)
+	}
+	if m.appData != nil {
+		return m.updateAppDataKey(msg)
+	}
+	if m.providers != nil {
+		return m.updateSizeProvidersKey(msg)
+	}
+	if m.stale != nil {
+		return m.updateStaleKey(msg)
+	}
+	if m.denied != nil {
+		return m.updateDeniedKey(msg)
+	}
+	if m.alerts != nil {
+		return m.updateAlertsKey(msg)
+	}
+	if m.historyDiff != nil {
+		return m.updateHistoryDiffKey(msg)
+	}
+	if m.searches != nil {
+		return m.updateSearchesKey(msg)
+	}
+	if m.organize != nil {
+		return m.updateOrganizeKey(msg)
+	}
+
+	// Listings from another machine cannot be acted on locally.
+	if m.inventory != nil {
+		switch msg.String() {
+		case "delete", "backspace", "alt+delete", "alt+backspace", "o", "f", "F", "x", "X", "E":
+			m.status = fmt.Sprintf("%s is a read-only listing", m.inventory.Source)
+			return m, nil
+		}
+	}
+
+	if m.safeMode && isDeleteKey(msg.String()) {
+		for _, path := range m.actionTargets() {
+			if _, ok := safeDeleteCategory(path); !ok {
+				m.status = safeModeBlockReason(path)
+				return m, nil
+			}
+		}
+	}
+
 	switch msg.String() {
-	case "q", "ctrl+c":
-		return m, tea.Quit
+	case "q", "Q", "ctrl+c":
+		return m.quit()
 	case "esc":
+		if m.scanning {
+			if cancelled, ok := m.cancelScan(); ok {
+				return cancelled, nil
+			}
+		}
+		if m.deleteFailures != nil {
+			m.deleteFailures = nil
+			return m, nil
+		}
+		if m.verify != nil {
+			m.verify = nil
+			return m, nil
+		}
+		if m.filter != nil {
+			m.clearFilter()
+			m.status = "Filter cleared"
+			return m, nil
+		}
 		if m.showLargeFiles {
 			m.showLargeFiles = false
 			return m, nil
 		}
-		return m, tea.Quit
+		return m.quit()
+	case "ctrl+k":
+		m.actionPalette = &actionPaletteState{}
+		return m, nil
+	case ":":
+		if !m.expertMode {
+			m.status = "Start with --expert to use the command palette"
+			return m, nil
+		}
+		m.palette = &paletteState{}
+		return m, nil
+	case "/":
+		if m.inOverviewMode() || m.scanning {
+			return m, nil
+		}
+		m.openFilter()
+		return m, nil
+	case "ctrl+a":
+		m.selectFilterMatches()
 	case "up", "k":
 		if m.showLargeFiles {
 			if m.largeSelected > 0 {
@@ -594,36 +1700,57 @@ func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if m.showLargeFiles {
 			if m.largeSelected < len(m.largeFiles)-1 {
 				m.largeSelected++
-				viewport := calculateViewport(m.height, true)
+				viewport := calculateViewport(m.listHeight(), true)
 				if m.largeSelected >= m.largeOffset+viewport {
 					m.largeOffset = m.largeSelected - viewport + 1
 				}
 			}
 		} else if len(m.entries) > 0 && m.selected < len(m.entries)-1 {
 			m.selected++
-			viewport := calculateViewport(m.height, false)
+			viewport := calculateViewport(m.listHeight(), false)
 			if m.selected >= m.offset+viewport {
 				m.offset = m.selected - viewport + 1
 			}
 		}
+	case "pgup", "pgdown", "home", "end":
+		if m.showLargeFiles {
+			m.largeSelected = pageTarget(msg.String(), m.largeSelected, len(m.largeFiles), calculateViewport(m.listHeight(), true))
+			m.clampLargeSelection()
+		} else {
+			m.selected = pageTarget(msg.String(), m.selected, len(m.entries), calculateViewport(m.listHeight(), false))
+			m.clampEntrySelection()
+		}
+	case "N":
+		return m.toggleRecentFiles()
+	case "n":
+		if m.showLargeFiles || m.inOverviewMode() {
+			return m, nil
+		}
+		return m.toggleFullListing()
 	case "enter", "right", "l":
 		if m.showLargeFiles {
 			return m, nil
 		}
 		return m.enterSelectedDir()
+	case "alt+enter":
+		if m.showLargeFiles || len(m.entries) == 0 || !m.entries[m.selected].IsDir {
+			return m, nil
+		}
+		return m.openDir(m.entries[m.selected].Path)
 	case "b", "left", "h":
 		if m.showLargeFiles {
 			m.showLargeFiles = false
 			return m, nil
 		}
 		if len(m.history) == 0 {
-			if !m.inOverviewMode() {
+			if !m.inOverviewMode() && m.inventory == nil {
 				return m, m.switchToOverviewMode()
 			}
 			return m, nil
 		}
 		last := m.history[len(m.history)-1]
 		m.history = m.history[:len(m.history)-1]
+		m.filter = nil
 		m.path = last.Path
 		m.selected = last.Selected
 		m.offset = last.EntryOffset
@@ -647,9 +1774,10 @@ func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.scanning = true
 			return m, tea.Batch(m.scanCmd(m.path), tickCmd())
 		}
-		m.entries = last.Entries
-		m.largeFiles = last.LargeFiles
+		m.entries = m.orderEntries(last.Entries)
+		m.largeFiles = sortLargeFiles(filterLargeFiles(last.LargeFiles, minLargeFileSize), m.largeSort)
 		m.totalSize = last.TotalSize
+		m.omitted, m.omittedSize = last.Omitted, last.OmittedSize
 		m.clampEntrySelection()
 		m.clampLargeSelection()
 		if len(m.entries) == 0 {
@@ -662,8 +1790,9 @@ func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		m.status = fmt.Sprintf("Scanned %s", humanizeBytes(m.totalSize))
 		m.scanning = false
+		m.refocus()
 		return m, nil
-	case "r":
+	case "r", "R":
 		m.multiSelected = make(map[string]bool)
 		m.largeMultiSelected = make(map[string]bool)
 
@@ -692,6 +1821,14 @@ func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			*m.currentPath = ""
 		}
 		return m, tea.Batch(m.scanCmd(m.path), tickCmd())
+	case "+", "=", "-", "_":
+		if m.showLargeFiles {
+			return m.adjustLargeThreshold(msg.String() == "+" || msg.String() == "=")
+		}
+	case "V":
+		if m.showLargeFiles {
+			return m.toggleAllFileTypes()
+		}
 	case "t", "T":
 		if !m.inOverviewMode() {
 			m.showLargeFiles = !m.showLargeFiles
@@ -704,7 +1841,213 @@ func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 			m.status = fmt.Sprintf("Scanned %s", humanizeBytes(m.totalSize))
 		}
-	case "o":
+	case "?":
+		// Explain what makes the selected directory big.
+		selected, ok := m.selectedEntry()
+		if !ok || !selected.IsDir {
+			return m, nil
+		}
+		if m.explanation != nil && m.explanation.Path == selected.Path {
+			m.explanation = nil
+			return m, nil
+		}
+		m.explanation = nil
+		var cached *scanResult
+		if snapshot, ok := m.cache[selected.Path]; ok && !snapshot.Dirty {
+			cached = &scanResult{Entries: snapshot.Entries, LargeFiles: snapshot.LargeFiles, TotalSize: snapshot.TotalSize}
+		}
+		m.status = fmt.Sprintf("Looking into %s...", displayName(selected.Path, selected.Name))
+		return m, explainCmd(selected.Path, cached, m.inventory)
+	case "g", "G":
+		// Drill down the chain of largest children to the actual space hog.
+		if m.showLargeFiles || m.scanning || len(m.entries) == 0 {
+			return m, nil
+		}
+		m.drilling = true
+		return m.drillStep()
+	case "s", "S":
+		if !m.showLargeFiles {
+			// Cycle the listing order, keeping the cursor on the same entry.
+			if m.inOverviewMode() || len(m.entries) == 0 {
+				return m, nil
+			}
+			current := m.entries[min(m.selected, len(m.entries)-1)].Path
+			m.setEntrySort(m.entrySort.next())
+			for i, entry := range m.entries {
+				if entry.Path == current {
+					m.selected = i
+					break
+				}
+			}
+			m.clampEntrySelection()
+			m.status = fmt.Sprintf("Sorted by %s", strings.ToLower(m.entrySort.label()))
+			if m.entrySort == entrySortWeighted && m.omitted > 0 {
+				m.status += fmt.Sprintf(", R to rescan and rank the %d items left out by size", m.omitted)
+			}
+			return m, nil
+		}
+		// Cycle the large-files order, keeping the cursor on the same file.
+		if len(m.largeFiles) == 0 {
+			return m, nil
+		}
+		current := m.largeFiles[min(m.largeSelected, len(m.largeFiles)-1)].Path
+		m.largeSort = m.largeSort.next()
+		sortLargeFiles(m.largeFiles, m.largeSort)
+		for i, file := range m.largeFiles {
+			if file.Path == current {
+				m.largeSelected = i
+				break
+			}
+		}
+		m.clampLargeSelection()
+		m.status = fmt.Sprintf("Large files sorted by %s", strings.ToLower(m.largeSort.label()))
+		return m, nil
+	case "e":
+		// Export the current directory tree in ncdu's JSON format.
+		if m.inOverviewMode() || m.scanning {
+			return m, nil
+		}
+		m.status = fmt.Sprintf("Exporting %s...", displayPath(m.path))
+		m.exporting++
+		return m, exportNcduCmd(m.path, m.inventory)
+	case "x", "X":
+		// Toggle exact byte sizes for the selection.
+		selected, ok := m.selectedEntry()
+		if !ok {
+			return m, nil
+		}
+		if m.exactSize != nil && m.exactSize.Path == selected.Path {
+			m.exactSize = nil
+			return m, nil
+		}
+		m.exactSize = nil
+		m.status = fmt.Sprintf("Measuring %s...", displayName(selected.Path, selected.Name))
+		return m, exactSizeCmd(selected.Path)
+	case "d":
+		return m.openDuplicates()
+	case "v":
+		return m.openVersions()
+	case "B":
+		return m.openBackups()
+	case "L":
+		return m.openSnapshots()
+	case "C":
+		return m.openStorageCategories()
+	case "p":
+		if m.scanning {
+			return m.toggleScanPause(), nil
+		}
+		return m.openCleanPreview()
+	case "i":
+		return m.openHomebrew()
+	case "M":
+		return m.openMigration()
+	case "Y":
+		return m.openPackageCaches()
+	case "ctrl+u":
+		return m.openUninstall()
+	case "J":
+		return m.openEvict()
+	case "ctrl+y":
+		return m.openQuickLook()
+	case "tab":
+		m.showInfo = !m.showInfo
+	case "K":
+		return m.openAppData()
+	case "I":
+		return m.openSizeProviders()
+	case "U":
+		return m.openStale()
+	case "!":
+		return m.openDenied()
+	case "%":
+		return m.openCompress()
+	case ">":
+		return m.openRelocate()
+	case "W":
+		return m.openAlerts()
+	case "y":
+		return m.openHistoryDiff()
+	case "m":
+		return m.openSavedSearches()
+	case "ctrl+o":
+		return m.openOrganize()
+	case "z":
+		return m.stageSelection()
+	case "Z":
+		return m.openStaging()
+	case "D":
+		m.showPoolStats = !m.showPoolStats
+	case "w":
+		m.toggleFocus()
+	case "H":
+		m.showShared = !m.showShared
+		if m.showShared {
+			m.status = "Showing bytes shared with hard links and APFS clones"
+		} else {
+			m.status = "Hid shared bytes column"
+		}
+	case "A":
+		m.showApparent = !m.showApparent
+		if m.showApparent {
+			m.status = "Showing apparent (logical) sizes; bars still show disk use"
+		} else {
+			m.status = "Showing allocated sizes on disk"
+		}
+	case "c":
+		m.showCounts = !m.showCounts
+		if m.showCounts {
+			m.status = "Showing file and folder counts"
+		} else {
+			m.status = "Hid file and folder counts"
+		}
+	case "[", "]":
+		// Live-tune the scan concurrency cap.
+		limit := scanPool.stats().Limit
+		step := max(limit/4, 1)
+		if msg.String() == "[" {
+			limit = scanPool.setLimit(limit - step)
+		} else {
+			limit = scanPool.setLimit(limit + step)
+		}
+		m.status = fmt.Sprintf("Scan workers: %d", limit)
+	case "E":
+		// Empty the selected volume's trash.
+		if !m.inVolumesView() || m.selected >= len(m.entries) {
+			return m, nil
+		}
+		volume := m.entries[m.selected].Path
+		if m.volumeTrash[volume] <= 0 {
+			m.status = fmt.Sprintf("Trash on %s is empty", displayName(m.entries[m.selected].Path, m.entries[m.selected].Name))
+			return m, nil
+		}
+		m.trashConfirm = volume
+	case "P":
+		// Pin the selection to the top of its directory listing.
+		if m.showLargeFiles || m.inOverviewMode() || m.selected >= len(m.entries) {
+			return m, nil
+		}
+		selected := m.entries[m.selected]
+		pinned, err := togglePin(selected.Path)
+		if err != nil {
+			m.status = fmt.Sprintf("Failed to save pin: %v", err)
+			return m, nil
+		}
+		m.entries = m.orderEntries(m.entries)
+		for i := range m.entries {
+			if m.entries[i].Path == selected.Path {
+				m.selected = i
+				break
+			}
+		}
+		m.clampEntrySelection()
+		m.cache[m.path] = cacheSnapshot(m)
+		if pinned {
+			m.status = fmt.Sprintf("Pinned %s", displayName(selected.Path, selected.Name))
+		} else {
+			m.status = fmt.Sprintf("Unpinned %s", displayName(selected.Path, selected.Name))
+		}
+	case "o", "O":
 		// Open selected entries (multi-select aware).
 		const maxBatchOpen = 20
 		if m.showLargeFiles {
@@ -730,7 +2073,7 @@ func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 						defer cancel()
 						_ = exec.CommandContext(ctx, "open", path).Run()
 					}(selected.Path)
-					m.status = fmt.Sprintf("Opening %s...", selected.Name)
+					m.status = fmt.Sprintf("Opening %s...", displayName(selected.Path, selected.Name))
 				}
 			}
 		} else if len(m.entries) > 0 {
@@ -755,7 +2098,7 @@ func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 					defer cancel()
 					_ = exec.CommandContext(ctx, "open", path).Run()
 				}(selected.Path)
-				m.status = fmt.Sprintf("Opening %s...", selected.Name)
+				m.status = fmt.Sprintf("Opening %s...", displayName(selected.Path, selected.Name))
 			}
 		}
 	case "f", "F":
@@ -784,7 +2127,7 @@ func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 						defer cancel()
 						_ = exec.CommandContext(ctx, "open", "-R", path).Run()
 					}(selected.Path)
-					m.status = fmt.Sprintf("Showing %s in Finder...", selected.Name)
+					m.status = fmt.Sprintf("Showing %s in Finder...", displayName(selected.Path, selected.Name))
 				}
 			}
 		} else if len(m.entries) > 0 {
@@ -809,7 +2152,7 @@ func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 					defer cancel()
 					_ = exec.CommandContext(ctx, "open", "-R", path).Run()
 				}(selected.Path)
-				m.status = fmt.Sprintf("Showing %s in Finder...", selected.Name)
+				m.status = fmt.Sprintf("Showing %s in Finder...", displayName(selected.Path, selected.Name))
 			}
 		}
 	case " ":
@@ -867,7 +2210,15 @@ func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.status = fmt.Sprintf("Scanned %s", humanizeBytes(m.totalSize))
 			}
 		}
-	case "delete", "backspace":
+	case "a":
+		m.toggleSelectAll()
+	case "u":
+		if !m.showLargeFiles && !m.inOverviewMode() {
+			m.markOrphanedAppData()
+		}
+	case "delete", "backspace", "alt+delete", "alt+backspace":
+		// Alt+⌫ skips the Trash for this delete only.
+		m.deletePermanent = m.permanentDelete || strings.HasPrefix(msg.String(), "alt+")
 		if m.showLargeFiles {
 			if len(m.largeFiles) > 0 {
 				if len(m.largeMultiSelected) > 0 {
@@ -916,10 +2267,74 @@ func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.deleteTarget = &selected
 			}
 		}
+		if m.deleteConfirm {
+			m.reinstallHints = nil
+			m.deleteLocks = lockedTargets(m.actionTargets())
+			return m, reinstallHintsCmd(m.actionTargets())
+		}
 	}
 	return m, nil
 }
 
+// isDeleteKey matches ⌫ and the Alt+⌫ permanent variant.
+func isDeleteKey(key string) bool {
+	switch key {
+	case "delete", "backspace", "alt+delete", "alt+backspace":
+		return true
+	}
+	return false
+}
+
+// toggleSelectAll marks every visible row, or clears the marks when all are already marked.
+func (m *model) toggleSelectAll() {
+	sizes := make(map[string]int64)
+	selected := m.multiSelected
+	if m.showLargeFiles {
+		for _, file := range m.largeFiles {
+			sizes[file.Path] = file.Size
+		}
+		if m.largeMultiSelected == nil {
+			m.largeMultiSelected = make(map[string]bool)
+		}
+		selected = m.largeMultiSelected
+	} else if !m.inOverviewMode() {
+		for _, entry := range m.entries {
+			sizes[entry.Path] = entry.Size
+		}
+		if m.multiSelected == nil {
+			m.multiSelected = make(map[string]bool)
+		}
+		selected = m.multiSelected
+	}
+	if len(sizes) == 0 {
+		return
+	}
+
+	allSelected := true
+	for path := range sizes {
+		if !selected[path] {
+			allSelected = false
+			break
+		}
+	}
+	var totalSize int64
+	for path, size := range sizes {
+		if allSelected {
+			delete(selected, path)
+			continue
+		}
+		selected[path] = true
+		if size > 0 {
+			totalSize += size
+		}
+	}
+	if allSelected {
+		m.status = fmt.Sprintf("Scanned %s", humanizeBytes(m.totalSize))
+		return
+	}
+	m.status = fmt.Sprintf("%d selected (%s)", len(sizes), humanizeBytes(totalSize))
+}
+
 func (m *model) switchToOverviewMode() tea.Cmd {
 	m.isOverview = true
 	m.path = "/"
@@ -946,42 +2361,54 @@ func (m model) enterSelectedDir() (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 	selected := m.entries[m.selected]
+	if selected.Bundle {
+		m.status = fmt.Sprintf("%s is a package (%s); alt+enter looks inside", displayName(selected.Path, selected.Name), humanizeBytes(selected.Size))
+		return m, nil
+	}
 	if selected.IsDir {
-		m.history = append(m.history, snapshotFromModel(m))
-		m.path = selected.Path
-		m.selected = 0
-		m.offset = 0
-		m.status = "Scanning..."
-		m.scanning = true
-		m.isOverview = false
-		m.multiSelected = make(map[string]bool)
-		m.largeMultiSelected = make(map[string]bool)
+		return m.openDir(selected.Path)
+	}
+	m.status = fmt.Sprintf("File: %s (%s)", displayName(selected.Path, selected.Name), humanizeBytes(selected.Size))
+	return m, nil
+}
 
-		atomic.StoreInt64(m.filesScanned, 0)
-		atomic.StoreInt64(m.dirsScanned, 0)
-		atomic.StoreInt64(m.bytesScanned, 0)
-		if m.currentPath != nil {
-			*m.currentPath = ""
-		}
+// openDir pushes the current view onto the history and shows path, from cache when fresh.
+func (m model) openDir(path string) (tea.Model, tea.Cmd) {
+	m.history = append(m.history, snapshotFromModel(m))
+	m.filter = nil
+	m.path = path
+	m.selected = 0
+	m.offset = 0
+	m.status = "Scanning..."
+	m.scanning = true
+	m.isOverview = false
+	m.multiSelected = make(map[string]bool)
+	m.largeMultiSelected = make(map[string]bool)
+
+	atomic.StoreInt64(m.filesScanned, 0)
+	atomic.StoreInt64(m.dirsScanned, 0)
+	atomic.StoreInt64(m.bytesScanned, 0)
+	if m.currentPath != nil {
+		*m.currentPath = ""
+	}
 
-		if cached, ok := m.cache[m.path]; ok && !cached.Dirty {
-			m.entries = cloneDirEntries(cached.Entries)
-			m.largeFiles = cloneFileEntries(cached.LargeFiles)
-			m.totalSize = cached.TotalSize
-			m.selected = cached.Selected
-			m.offset = cached.EntryOffset
-			m.largeSelected = cached.LargeSelected
-			m.largeOffset = cached.LargeOffset
-			m.clampEntrySelection()
-			m.clampLargeSelection()
-			m.status = fmt.Sprintf("Cached view for %s", displayPath(m.path))
-			m.scanning = false
-			return m, nil
-		}
-		return m, tea.Batch(m.scanCmd(m.path), tickCmd())
+	if cached, ok := m.cache[m.path]; ok && !cached.Dirty && (cached.Omitted == 0 || !fullListing.Load()) {
+		m.entries = m.orderEntries(cloneDirEntries(cached.Entries))
+		m.largeFiles = sortLargeFiles(filterLargeFiles(cached.LargeFiles, minLargeFileSize), m.largeSort)
+		m.totalSize = cached.TotalSize
+		m.omitted, m.omittedSize = cached.Omitted, cached.OmittedSize
+		m.selected = cached.Selected
+		m.offset = cached.EntryOffset
+		m.largeSelected = cached.LargeSelected
+		m.largeOffset = cached.LargeOffset
+		m.clampEntrySelection()
+		m.clampLargeSelection()
+		m.refocus()
+		m.status = fmt.Sprintf("Cached view for %s", displayPath(m.path))
+		m.scanning = false
+		return m, nil
 	}
-	m.status = fmt.Sprintf("File: %s (%s)", selected.Name, humanizeBytes(selected.Size))
-	return m, nil
+	return m, tea.Batch(m.scanCmd(m.path), tickCmd())
 }
 
 func (m *model) clampEntrySelection() {
@@ -996,7 +2423,7 @@ func (m *model) clampEntrySelection() {
 	if m.selected < 0 {
 		m.selected = 0
 	}
-	viewport := calculateViewport(m.height, false)
+	viewport := calculateViewport(m.listHeight(), false)
 	maxOffset := len(m.entries) - viewport
 	if maxOffset < 0 {
 		maxOffset = 0
@@ -1012,6 +2439,207 @@ func (m *model) clampEntrySelection() {
 	}
 }
 
+// toggleFullListing switches between the largest maxEntries children and all of them.
+// Children the cap dropped were never kept, so listing them all rescans the folder.
+func (m model) toggleFullListing() (tea.Model, tea.Cmd) {
+	on := !fullListing.Load()
+	fullListing.Store(on)
+	if !on {
+		source := m.entries
+		if m.filter != nil {
+			source = m.filter.entries
+		}
+		kept, omitted, omittedSize := capEntries(sortEntries(cloneDirEntries(source), rankingSort()))
+		m.omitted, m.omittedSize = m.omitted+omitted, m.omittedSize+omittedSize
+		if m.filter != nil {
+			m.refilter(m.orderEntries(kept), m.filter.largeFiles)
+		} else {
+			m.entries = m.orderEntries(kept)
+		}
+		m.clampEntrySelection()
+		m.status = fmt.Sprintf("Listing the largest %d items", maxEntries)
+		return m, nil
+	}
+	if m.omitted == 0 || m.inventory != nil && m.scanning {
+		m.status = "Listing every item"
+		return m, nil
+	}
+	if entry, ok := m.cache[m.path]; ok {
+		entry.Dirty = true
+		m.cache[m.path] = entry
+	}
+	m.status = "Listing every item, rescanning..."
+	m.scanning = true
+	atomic.StoreInt64(m.filesScanned, 0)
+	atomic.StoreInt64(m.dirsScanned, 0)
+	atomic.StoreInt64(m.bytesScanned, 0)
+	if m.currentPath != nil {
+		*m.currentPath = ""
+	}
+	return m, tea.Batch(m.scanCmd(m.path), tickCmd())
+}
+
+// adjustLargeThreshold steps the large files threshold. Raising it filters the list in
+// place; lowering it needs files the scan did not keep, so Spotlight is asked again.
+func (m model) adjustLargeThreshold(up bool) (tea.Model, tea.Cmd) {
+	threshold := nextLargeThreshold(minLargeFileSize, up)
+	if threshold == minLargeFileSize {
+		m.status = fmt.Sprintf("Large files threshold stays at %s", humanizeBytes(threshold))
+		return m, nil
+	}
+	lowered := threshold < minLargeFileSize
+	minLargeFileSize = threshold
+	if !lowered {
+		source := m.largeFiles
+		if m.filter != nil {
+			source = m.filter.largeFiles
+		}
+		m.replaceLargeFiles(filterLargeFiles(source, threshold))
+		m.status = fmt.Sprintf("Showing %d files of %s and up", len(m.largeFiles), humanizeBytes(threshold))
+		return m, nil
+	}
+	if m.inventory != nil {
+		if result, err := m.inventory.scanResultFor(m.path); err == nil {
+			m.replaceLargeFiles(result.LargeFiles)
+		}
+		m.status = fmt.Sprintf("Showing %d files of %s and up", len(m.largeFiles), humanizeBytes(threshold))
+		return m, nil
+	}
+	m.status = fmt.Sprintf("Looking for files of %s and up...", humanizeBytes(threshold))
+	return m, requeryLargeFilesCmd(m.path, threshold)
+}
+
+// toggleRecentFiles hides or shows files created within the recent window. Hiding
+// filters the list in place; showing asks Spotlight again, since they were dropped.
+func (m model) toggleRecentFiles() (tea.Model, tea.Cmd) {
+	hide := !hideRecentFiles.Load()
+	hideRecentFiles.Store(hide)
+	age := formatRecentAge(time.Duration(recentFileAge.Load()))
+	if hide {
+		source := m.largeFiles
+		if m.filter != nil {
+			source = m.filter.largeFiles
+		}
+		m.replaceLargeFiles(filterLargeFiles(source, minLargeFileSize))
+		m.status = fmt.Sprintf("Showing %d files, those from the last %s hidden", len(m.largeFiles), age)
+		return m, nil
+	}
+	if m.inOverviewMode() {
+		m.status = "Recent files will be listed again"
+		return m, nil
+	}
+	if m.inventory != nil {
+		if result, err := m.inventory.scanResultFor(m.path); err == nil {
+			m.replaceLargeFiles(result.LargeFiles)
+		}
+		m.status = fmt.Sprintf("Showing %d files, recent ones included", len(m.largeFiles))
+		return m, nil
+	}
+	m.status = fmt.Sprintf("Looking for files from the last %s too...", age)
+	return m, requeryLargeFilesCmd(m.path, minLargeFileSize)
+}
+
+// toggleAllFileTypes shows or hides the source, text and database files the large files
+// list leaves out. Hiding filters the list in place; showing asks Spotlight again, since
+// the scan never kept them.
+func (m model) toggleAllFileTypes() (tea.Model, tea.Cmd) {
+	showAll := !allFileTypes.Load()
+	allFileTypes.Store(showAll)
+	if !showAll {
+		source := m.largeFiles
+		if m.filter != nil {
+			source = m.filter.largeFiles
+		}
+		m.replaceLargeFiles(filterFileTypes(source))
+		m.status = fmt.Sprintf("Showing %d files, code and data files hidden", len(m.largeFiles))
+		return m, nil
+	}
+	if m.inventory != nil {
+		if result, err := m.inventory.scanResultFor(m.path); err == nil {
+			m.replaceLargeFiles(result.LargeFiles)
+		}
+		m.status = fmt.Sprintf("Showing %d files of all types", len(m.largeFiles))
+		return m, nil
+	}
+	m.status = "Looking for large files of all types..."
+	return m, requeryLargeFilesCmd(m.path, minLargeFileSize)
+}
+
+// replaceLargeFiles swaps in a large files list for the current folder, keeping the
+// cursor on the same file and the cached view in step.
+func (m *model) replaceLargeFiles(files []fileEntry) {
+	var current string
+	if m.largeSelected < len(m.largeFiles) {
+		current = m.largeFiles[m.largeSelected].Path
+	}
+	m.largeFiles = sortLargeFiles(files, m.largeSort)
+	if m.filter != nil {
+		m.refilter(m.filter.entries, m.largeFiles)
+	}
+	for i, file := range m.largeFiles {
+		if file.Path == current {
+			m.largeSelected = i
+			break
+		}
+	}
+	m.clampLargeSelection()
+	if snapshot, ok := m.cache[m.path]; ok {
+		snapshot.LargeFiles = cloneFileEntries(files)
+		m.cache[m.path] = snapshot
+	}
+}
+
+// applyExternalChanges marks cached views of paths another Mole process removed as
+// stale, and rescans the open folder when it is affected and nothing else is running.
+func (m model) applyExternalChanges(paths []string) (tea.Model, tea.Cmd) {
+	next := pollChangesCmd(m.changesOffset)
+	if len(paths) == 0 || m.inventory != nil {
+		return m, next
+	}
+	stale := make(map[string]bool)
+	affected := false
+	for _, changed := range paths {
+		for dir := changed; !stale[dir]; dir = filepath.Dir(dir) {
+			stale[dir] = true
+			invalidateCache(dir)
+		}
+		affected = affected || pathsOverlap(m.path, changed)
+	}
+	for path, entry := range m.cache {
+		for _, changed := range paths {
+			if pathsOverlap(path, changed) {
+				entry.Dirty = true
+				m.cache[path] = entry
+				break
+			}
+		}
+	}
+	for i := range m.history {
+		for _, changed := range paths {
+			if pathsOverlap(m.history[i].Path, changed) {
+				m.history[i].Dirty = true
+				break
+			}
+		}
+	}
+	if !affected || m.inOverviewMode() {
+		return m, next
+	}
+	if m.scanning || m.deleting {
+		m.status = fmt.Sprintf("%d items were removed by another Mole command, press R to refresh", len(paths))
+		return m, next
+	}
+	m.status = fmt.Sprintf("%d items were removed by another Mole command, refreshing...", len(paths))
+	m.scanning = true
+	atomic.StoreInt64(m.filesScanned, 0)
+	atomic.StoreInt64(m.dirsScanned, 0)
+	atomic.StoreInt64(m.bytesScanned, 0)
+	if m.currentPath != nil {
+		*m.currentPath = ""
+	}
+	return m, tea.Batch(m.scanCmd(m.path), tickCmd(), next)
+}
+
 func (m *model) clampLargeSelection() {
 	if len(m.largeFiles) == 0 {
 		m.largeSelected = 0
@@ -1024,7 +2652,7 @@ func (m *model) clampLargeSelection() {
 	if m.largeSelected < 0 {
 		m.largeSelected = 0
 	}
-	viewport := calculateViewport(m.height, true)
+	viewport := calculateViewport(m.listHeight(), true)
 	maxOffset := len(m.largeFiles) - viewport
 	if maxOffset < 0 {
 		maxOffset = 0
@@ -1040,6 +2668,42 @@ func (m *model) clampLargeSelection() {
 	}
 }
 
+// actionTargets lists what delete and palette actions target: the marked items, else the cursor row.
+func (m model) actionTargets() []string {
+	var paths []string
+	if m.showLargeFiles {
+		for path := range m.largeMultiSelected {
+			paths = append(paths, path)
+		}
+	} else {
+		for path := range m.multiSelected {
+			paths = append(paths, path)
+		}
+	}
+	if len(paths) == 0 && !m.inOverviewMode() {
+		if selected, ok := m.selectedEntry(); ok {
+			paths = append(paths, selected.Path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// selectedEntry returns the row under the cursor in the active list.
+func (m model) selectedEntry() (dirEntry, bool) {
+	if m.showLargeFiles {
+		if m.largeSelected < 0 || m.largeSelected >= len(m.largeFiles) {
+			return dirEntry{}, false
+		}
+		file := m.largeFiles[m.largeSelected]
+		return dirEntry{Name: file.Name, Path: file.Path, Size: file.Size}, true
+	}
+	if m.selected < 0 || m.selected >= len(m.entries) {
+		return dirEntry{}, false
+	}
+	return m.entries[m.selected], true
+}
+
 func sumKnownEntrySizes(entries []dirEntry) int64 {
 	var total int64
 	for _, entry := range entries {
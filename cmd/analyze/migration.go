@@ -0,0 +1,287 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// migrationBucket is what to do with data when moving to a new Mac or a bigger disk.
+type migrationBucket int
+
+const (
+	bucketMigrate  migrationBucket = iota // Copy it over
+	bucketArchive                         // Untouched for a year; move it to external storage
+	bucketRecreate                        // Rebuilt, downloaded or reinstalled on the new Mac
+	migrationBucketCount
+)
+
+var migrationBuckets = [migrationBucketCount]struct{ Name, Advice string }{
+	{Name: "Migrate", Advice: "Copy these to the new Mac."},
+	{Name: "Archive", Advice: "Untouched for over a year: move these to external storage instead."},
+	{Name: "Recreate", Advice: "Leave these behind: they are rebuilt, downloaded again or reinstalled."},
+}
+
+// migrationArchiveAge is how long a folder must sit untouched before it is suggested for archiving.
+const migrationArchiveAge = 365 * 24 * time.Hour
+
+// migrationRecreateRoots are home folders rebuilt on demand, with why they can be left behind.
+var migrationRecreateRoots = map[string]string{
+	"Library/Caches":                      "apps rebuild their caches",
+	".cache":                              "tools rebuild their caches",
+	".Trash":                              "empty the Trash instead of copying it",
+	"Library/Developer/Xcode/DerivedData": "Xcode rebuilds it",
+	"Library/Developer/Xcode/iOS DeviceSupport": "downloaded again when a device connects",
+	"Library/Developer/CoreSimulator":           "Xcode downloads simulators again",
+	"Library/Containers/com.docker.docker":      "Docker pulls images again",
+	".npm":                                      "package downloads are fetched again",
+	"Library/pnpm":                              "package downloads are fetched again",
+	".gradle/caches":                            "package downloads are fetched again",
+	".m2/repository":                            "package downloads are fetched again",
+	"go/pkg/mod":                                "package downloads are fetched again",
+	".cargo/registry":                           "package downloads are fetched again",
+}
+
+// migrationAppFolders hold apps, checked for an App Store or Homebrew source.
+var migrationAppFolders = []string{"/Applications", "Applications"}
+
+// migrationItem is one checklist line.
+type migrationItem struct {
+	Path   string
+	Size   int64
+	Bucket migrationBucket
+	Reason string
+}
+
+// migrationPlan sorts a home folder and its apps into buckets, each largest first.
+type migrationPlan struct {
+	Home   string
+	Items  [migrationBucketCount][]migrationItem
+	Totals [migrationBucketCount]int64
+	At     time.Time
+}
+
+type migrationPlanMsg struct {
+	Plan migrationPlan
+	Err  error
+}
+
+func (p *migrationPlan) add(item migrationItem) {
+	if item.Size <= 0 {
+		return
+	}
+	p.Items[item.Bucket] = append(p.Items[item.Bucket], item)
+	p.Totals[item.Bucket] += item.Size
+}
+
+// migrationWalker walks home once, pulling out what to recreate or archive; the rest
+// is migrated and totalled per top-level folder.
+type migrationWalker struct {
+	home   string
+	cutoff time.Time
+	now    time.Time
+}
+
+// walk returns dir's size, when anything in it was last modified, and the recreate and
+// archive items found inside. A folder whose every file predates the cutoff is one
+// archive item, the same way the stale data view reports old folders; one holding
+// nothing but recreatable folders has no time and is never archived.
+func (w migrationWalker) walk(dir string, archivable bool) (int64, time.Time, []migrationItem) {
+	children, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, w.now, nil
+	}
+	var size int64
+	var newest time.Time
+	var items []migrationItem
+	for _, child := range children {
+		path := filepath.Join(dir, child.Name())
+		if child.Type()&os.ModeSymlink != 0 || isExcludedPath(w.home, path) {
+			continue
+		}
+		if reason, ok := w.recreateReason(path, child.IsDir()); ok {
+			// Left out of newest so an old project with node_modules can still be archived whole.
+			childSize, _ := getDirectorySizeFromDu(path)
+			size += childSize
+			items = append(items, migrationItem{Path: path, Size: childSize, Bucket: bucketRecreate, Reason: reason})
+			continue
+		}
+		if child.IsDir() {
+			childSize, touched, childItems := w.walk(path, archivable)
+			size += childSize
+			if touched.After(newest) {
+				newest = touched
+			}
+			if archivable && !touched.IsZero() && touched.Before(w.cutoff) && childSize >= staleMinSize {
+				items = append(items, migrationItem{Path: path, Size: childSize, Bucket: bucketArchive, Reason: "last modified " + touched.Format("Jan 2006")})
+			} else {
+				items = append(items, childItems...)
+			}
+			continue
+		}
+		info, err := child.Info()
+		if err != nil {
+			continue
+		}
+		fileSize := getActualFileSize(path, info)
+		size += fileSize
+		if info.ModTime().After(newest) {
+			newest = info.ModTime()
+		}
+		if archivable && info.ModTime().Before(w.cutoff) && fileSize >= staleMinSize {
+			items = append(items, migrationItem{Path: path, Size: fileSize, Bucket: bucketArchive, Reason: "last modified " + info.ModTime().Format("Jan 2006")})
+		}
+	}
+	return size, newest, items
+}
+
+// recreateReason says why path can be left behind, if it can.
+func (w migrationWalker) recreateReason(path string, isDir bool) (string, bool) {
+	rel, err := filepath.Rel(w.home, path)
+	if err != nil {
+		return "", false
+	}
+	if reason, ok := migrationRecreateRoots[filepath.ToSlash(rel)]; ok {
+		return reason, true
+	}
+	for _, folder := range cloudFolders {
+		if rel == folder {
+			return "synced down again from the cloud", true
+		}
+	}
+	if isDir && projectDependencyDirs[filepath.Base(path)] && !strings.HasPrefix(rel, "Library/") {
+		return safeDevArtifact.Label + ", recreated by the project's install or build step", true
+	}
+	return "", false
+}
+
+// planHome buckets everything in home. Library is never archived: an app's data
+// is only useful next to the app.
+func planHome(plan *migrationPlan, home string, now time.Time) error {
+	children, err := os.ReadDir(home)
+	if err != nil {
+		return err
+	}
+	w := migrationWalker{home: home, cutoff: now.Add(-migrationArchiveAge), now: now}
+	var looseSize int64
+	for _, child := range children {
+		path := filepath.Join(home, child.Name())
+		if child.Type()&os.ModeSymlink != 0 || child.Name() == "Applications" || isExcludedPath(home, path) {
+			continue
+		}
+		if reason, ok := w.recreateReason(path, child.IsDir()); ok {
+			size, _ := getDirectorySizeFromDu(path)
+			plan.add(migrationItem{Path: path, Size: size, Bucket: bucketRecreate, Reason: reason})
+			continue
+		}
+		if !child.IsDir() {
+			if info, err := child.Info(); err == nil {
+				looseSize += getActualFileSize(path, info)
+			}
+			continue
+		}
+		size, _, items := w.walk(path, child.Name() != "Library")
+		for _, item := range items {
+			plan.add(item)
+			size -= item.Size
+		}
+		reason := "personal files"
+		if child.Name() == "Library" {
+			reason = "app settings, mail, messages and app data"
+		}
+		plan.add(migrationItem{Path: path, Size: size, Bucket: bucketMigrate, Reason: reason})
+	}
+	plan.add(migrationItem{Path: home, Size: looseSize, Bucket: bucketMigrate, Reason: "files loose in the home folder"})
+	return nil
+}
+
+// planApps sorts the apps in folders into reinstallable ones and ones that must be copied.
+func planApps(plan *migrationPlan, folders []string, sources reinstallSources) {
+	lookups := 0
+	for _, folder := range folders {
+		apps, _ := filepath.Glob(filepath.Join(folder, "*.app"))
+		for _, app := range apps {
+			size, err := getDirectorySizeFromDu(app)
+			if err != nil {
+				continue
+			}
+			if hint := sources.reinstallHint(app, &lookups); hint != "" {
+				plan.add(migrationItem{Path: app, Size: size, Bucket: bucketRecreate, Reason: hint})
+				continue
+			}
+			plan.add(migrationItem{Path: app, Size: size, Bucket: bucketMigrate, Reason: "no App Store or Homebrew source found"})
+		}
+	}
+}
+
+func buildMigrationPlan(home string, sources reinstallSources, now time.Time) (migrationPlan, error) {
+	plan := migrationPlan{Home: home, At: now}
+	if err := planHome(&plan, home, now); err != nil {
+		return plan, err
+	}
+	var folders []string
+	for _, folder := range migrationAppFolders {
+		if !filepath.IsAbs(folder) {
+			folder = filepath.Join(home, folder)
+		}
+		folders = append(folders, folder)
+	}
+	planApps(&plan, folders, sources)
+	for bucket := range plan.Items {
+		items := plan.Items[bucket]
+		sort.SliceStable(items, func(a, b int) bool { return items[a].Size > items[b].Size })
+	}
+	return plan, nil
+}
+
+func migrationPlanCmd(home string) tea.Cmd {
+	return func() tea.Msg {
+		plan, err := buildMigrationPlan(home, localReinstallSources(), time.Now())
+		return migrationPlanMsg{Plan: plan, Err: err}
+	}
+}
+
+// writeMigrationChecklist writes the plan as a Markdown checklist.
+func writeMigrationChecklist(w io.Writer, plan migrationPlan) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Migration checklist for %s\n\nPlanned %s.\n\n", plan.Home, plan.At.Format("2006-01-02 15:04"))
+	var totals []string
+	for bucket, info := range migrationBuckets {
+		totals = append(totals, fmt.Sprintf("%s %s", info.Name, humanizeBytes(plan.Totals[bucket])))
+	}
+	fmt.Fprintf(&b, "%s\n", strings.Join(totals, " | "))
+	for bucket, info := range migrationBuckets {
+		if len(plan.Items[bucket]) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n## %s (%s)\n\n%s\n\n", info.Name, humanizeBytes(plan.Totals[bucket]), info.Advice)
+		for _, item := range plan.Items[bucket] {
+			fmt.Fprintf(&b, "- [ ] %s, %s: %s\n", displayPath(item.Path), humanizeBytes(item.Size), item.Reason)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+type migrationExportMsg struct {
+	Path string
+	Err  error
+}
+
+// exportMigrationChecklistCmd saves the checklist next to the analyzer's other exports.
+func exportMigrationChecklistCmd(plan migrationPlan) tea.Cmd {
+	return func() tea.Msg {
+		dest, err := exportFilePath("migration", "md")
+		if err != nil {
+			return migrationExportMsg{Err: err}
+		}
+		err = writeExportFile(dest, 0666, func(w io.Writer) error { return writeMigrationChecklist(w, plan) })
+		return migrationExportMsg{Path: dest, Err: err}
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func migrationItemFor(plan migrationPlan, path string) (migrationItem, bool) {
+	for _, items := range plan.Items {
+		for _, item := range items {
+			if item.Path == path {
+				return item, true
+			}
+		}
+	}
+	return migrationItem{}, false
+}
+
+func TestPlanHomeBuckets(t *testing.T) {
+	home := t.TempDir()
+	now := time.Now()
+	writeFileWithSize(t, filepath.Join(home, "Documents", "taxes.pdf"), 2<<20)
+	writeFileWithSize(t, filepath.Join(home, "Documents", "old-project", "video.mov"), 4<<20)
+	writeFileWithSize(t, filepath.Join(home, "Documents", "old-project", "node_modules", "dep.js"), 1<<20)
+	writeFileWithSize(t, filepath.Join(home, "Library", "Caches", "com.example", "blob"), 1<<20)
+	writeFileWithSize(t, filepath.Join(home, "Library", "Application Support", "Old", "data"), 2<<20)
+	writeFileWithSize(t, filepath.Join(home, "code", "app", "node_modules", "dep.js"), 1<<20)
+	writeFileWithSize(t, filepath.Join(home, "notes.txt"), 4096)
+
+	old := now.Add(-2 * migrationArchiveAge)
+	for _, path := range []string{"Documents/old-project/video.mov", "Library/Application Support/Old/data"} {
+		if err := os.Chtimes(filepath.Join(home, path), old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	plan := migrationPlan{Home: home}
+	if err := planHome(&plan, home, now); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]migrationBucket{
+		"Library/Caches":                     bucketRecreate,
+		"code/app/node_modules":              bucketRecreate,
+		"Documents/old-project":              bucketArchive, // node_modules inside does not make it recent
+		"Documents":                          bucketMigrate,
+		"Library":                            bucketMigrate, // Never archived
+		"":                                   bucketMigrate, // Loose files
+		"Documents/old-project/node_modules": migrationBucketCount,
+	}
+	for rel, bucket := range want {
+		item, ok := migrationItemFor(plan, filepath.Join(home, rel))
+		if bucket == migrationBucketCount {
+			if ok {
+				t.Errorf("%s listed on its own inside an archived folder", rel)
+			}
+			continue
+		}
+		if !ok || item.Bucket != bucket {
+			t.Errorf("%s = %+v (found %v), want bucket %d", rel, item, ok, bucket)
+		}
+	}
+	documents, _ := migrationItemFor(plan, filepath.Join(home, "Documents"))
+	if documents.Size >= 4<<20 {
+		t.Errorf("Documents = %d, still counts the archived project", documents.Size)
+	}
+}
+
+func TestPlanAppsByReinstallSource(t *testing.T) {
+	folder := t.TempDir()
+	writeFileWithSize(t, filepath.Join(folder, "Keynote.app", "Contents", "Info.plist"), 4096)
+	writeFileWithSize(t, filepath.Join(folder, "Homemade.app", "Contents", "Info.plist"), 4096)
+
+	plan := migrationPlan{}
+	planApps(&plan, []string{folder}, reinstallSources{MasApps: map[string]string{"keynote": "409183694"}})
+	if len(plan.Items[bucketRecreate]) != 1 || !strings.HasSuffix(plan.Items[bucketRecreate][0].Path, "Keynote.app") {
+		t.Errorf("recreate = %+v, want Keynote", plan.Items[bucketRecreate])
+	}
+	if len(plan.Items[bucketMigrate]) != 1 || !strings.HasSuffix(plan.Items[bucketMigrate][0].Path, "Homemade.app") {
+		t.Errorf("migrate = %+v, want Homemade", plan.Items[bucketMigrate])
+	}
+}
+
+func TestWriteMigrationChecklist(t *testing.T) {
+	plan := migrationPlan{Home: "/Users/me", At: time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)}
+	plan.add(migrationItem{Path: "/Users/me/Documents", Size: 2 << 30, Bucket: bucketMigrate, Reason: "personal files"})
+	plan.add(migrationItem{Path: "/Users/me/Library/Caches", Size: 1 << 30, Bucket: bucketRecreate, Reason: "apps rebuild their caches"})
+	plan.add(migrationItem{Path: "/Users/me/empty", Bucket: bucketArchive})
+
+	var b strings.Builder
+	if err := writeMigrationChecklist(&b, plan); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, want := range []string{"Migrate 2.0 GB | Archive 0 B | Recreate 1.0 GB", "## Recreate (1.0 GB)", "- [ ] ", "apps rebuild their caches"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("checklist missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "## Archive") {
+		t.Errorf("empty bucket written:\n%s", out)
+	}
+}
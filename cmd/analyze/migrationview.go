@@ -0,0 +1,138 @@
+//go:build darwin
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// migrationView is the "M" screen planning a move to a new Mac or a bigger disk.
+type migrationView struct {
+	Plan     migrationPlan
+	Bucket   migrationBucket
+	Selected int
+	Scanning bool
+	Err      error
+}
+
+func (v *migrationView) items() []migrationItem {
+	return v.Plan.Items[v.Bucket]
+}
+
+func (m model) openMigration() (tea.Model, tea.Cmd) {
+	if m.inventory != nil {
+		m.status = fmt.Sprintf("%s is a read-only listing", m.inventory.Source)
+		return m, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		m.status = "Unable to find your home folder"
+		return m, nil
+	}
+	m.migration = &migrationView{Scanning: true}
+	m.status = "Planning a migration of your home folder and apps..."
+	return m, tea.Batch(migrationPlanCmd(home), tickCmd())
+}
+
+// updateMigrationKey handles keys while the migration plan is open.
+func (m model) updateMigrationKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	v := m.migration
+	switch msg.String() {
+	case "q", "Q", "ctrl+c":
+		return m.quit()
+	case "esc", "b", "left", "h", "M":
+		m.migration = nil
+	case "up", "k":
+		v.Selected = max(v.Selected-1, 0)
+	case "down", "j":
+		v.Selected = min(v.Selected+1, max(len(v.items())-1, 0))
+	case "tab", "right", "l":
+		v.Bucket = (v.Bucket + 1) % migrationBucketCount
+		v.Selected = 0
+	case "shift+tab":
+		v.Bucket = (v.Bucket + migrationBucketCount - 1) % migrationBucketCount
+		v.Selected = 0
+	case "e":
+		if v.Scanning || v.Err != nil {
+			return m, nil
+		}
+		m.status = "Exporting migration checklist..."
+		m.exporting++
+		return m, exportMigrationChecklistCmd(v.Plan)
+	case "enter":
+		// Browse the folder, or the folder holding a file, with the regular scanner.
+		items := v.items()
+		if v.Scanning || v.Selected >= len(items) {
+			return m, nil
+		}
+		path := items[v.Selected].Path
+		if !isDirectory(path) {
+			path = filepath.Dir(path)
+		}
+		m.migration = nil
+		return m.openDir(path)
+	}
+	return m, nil
+}
+
+// renderMigration shows the bucket totals and the selected bucket's checklist.
+func (m model) renderMigration() string {
+	v := m.migration
+	var b strings.Builder
+	if v.Scanning {
+		fmt.Fprintf(&b, "%s%s%s%s Planning a migration of your home folder and apps...\n",
+			colorCyan, colorBold, spinnerFrames[m.spinner], colorReset)
+		return b.String()
+	}
+	if v.Err != nil {
+		fmt.Fprintf(&b, "  %s%s%s\n\n", colorGray, v.Err.Error(), colorReset)
+		fmt.Fprintf(&b, "%sM/← Back | Q Quit%s\n", colorGray, colorReset)
+		return b.String()
+	}
+
+	var total int64
+	for _, size := range v.Plan.Totals {
+		total += size
+	}
+	fmt.Fprintf(&b, "%sMigration plan:%s %s%s%s to copy of %s\n\n",
+		colorCyan, colorReset, colorYellow, humanizeBytes(v.Plan.Totals[bucketMigrate]), colorReset, humanizeBytes(total))
+	for bucket, info := range migrationBuckets {
+		label := fmt.Sprintf(" %s %s ", info.Name, humanizeBytes(v.Plan.Totals[bucket]))
+		if migrationBucket(bucket) == v.Bucket {
+			fmt.Fprintf(&b, "%s%s[%s]%s ", colorCyan, colorBold, label, colorReset)
+		} else {
+			fmt.Fprintf(&b, "%s %s %s ", colorGray, label, colorReset)
+		}
+	}
+	fmt.Fprintf(&b, "\n  %s%s%s\n\n", colorGray, migrationBuckets[v.Bucket].Advice, colorReset)
+
+	items := v.items()
+	if len(items) == 0 {
+		fmt.Fprintf(&b, "  %sNothing in this bucket%s\n", colorGray, colorReset)
+	}
+	bucketTotal := max(v.Plan.Totals[v.Bucket], 1)
+	viewport := calculateViewport(m.height, true) - 2
+	nameWidth := calculateNameWidth(m.width)
+	start := max(0, v.Selected-viewport+1)
+	for i := start; i < min(len(items), start+viewport); i++ {
+		item := items[i]
+		prefix, color := "   ", ""
+		if i == v.Selected {
+			prefix, color = fmt.Sprintf(" %s%s▶%s ", colorCyan, colorBold, colorReset), colorCyan
+		}
+		percent := float64(item.Size) / float64(bucketTotal) * 100
+		fmt.Fprintf(&b, "%s%s %5.1f%%  |  %s%s%s%s %10s  %s%s%s\n",
+			prefix, coloredProgressBar(item.Size, bucketTotal, percent), percent,
+			color, colorBold, padName(trimNameWithWidth(displayPath(item.Path), nameWidth), nameWidth), colorReset,
+			humanizeBytes(item.Size), colorGray, item.Reason, colorReset)
+	}
+
+	fmt.Fprintln(&b)
+	fmt.Fprintf(&b, "%s↑↓ | Tab Bucket | Enter Browse | E Export checklist | M/← Back | Q Quit%s\n", colorGray, colorReset)
+	return b.String()
+}
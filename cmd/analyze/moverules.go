@@ -0,0 +1,217 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// moveRulesFile holds one organizing rule per line: a saved-search query, "->",
+// and the folder matching files move into:
+//
+//	*.dmg older:30d in:~/Downloads -> ~/Archive/Installers
+//	*.mov >1GB in:~/Desktop -> /Volumes/Media/Clips
+const moveRulesFile = "analyze_moves"
+
+// moveRule files everything its search matches into Dest.
+type moveRule struct {
+	Line   string
+	Search savedSearch
+	Dest   string
+}
+
+// movePlanItem is one planned rename.
+type movePlanItem struct {
+	From string
+	To   string
+	Size int64
+}
+
+// movePlan is what a rule would move right now.
+type movePlan struct {
+	Items  []movePlanItem
+	Total  int64
+	Capped bool // More files matched than one run moves
+	Source string
+	Err    error
+}
+
+type movePlansMsg struct {
+	Plans []movePlan // Parallel to the rules
+}
+
+type moveProgressMsg struct {
+	Moved    []movePlanItem
+	Failures []deleteFailure
+	Skipped  []movePlanItem // Not started because the batch was stopped
+}
+
+// parseMoveRules reads the rules file, skipping lines that do not parse.
+func parseMoveRules(data, home string) []moveRule {
+	var rules []moveRule
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if rule, err := parseMoveRule(line, home); err == nil {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+func parseMoveRule(line, home string) (moveRule, error) {
+	query, dest, ok := strings.Cut(line, "->")
+	if !ok {
+		return moveRule{}, fmt.Errorf("%s: expected <query> -> <folder>", line)
+	}
+	query, dest = strings.TrimSpace(query), strings.TrimSpace(dest)
+	search, err := parseSavedSearch(line, query, home)
+	if err != nil {
+		return moveRule{}, err
+	}
+	if dest == "~" || strings.HasPrefix(dest, "~/") {
+		dest = home + dest[1:]
+	}
+	if !filepath.IsAbs(dest) {
+		return moveRule{}, fmt.Errorf("%s: destination needs an absolute or ~ path", line)
+	}
+	return moveRule{Line: line, Search: search, Dest: filepath.Clean(dest)}, nil
+}
+
+func getMoveRulesPath() (string, error) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, moveRulesFile), nil
+}
+
+// loadMoveRules returns the user's organizing rules; a missing file means none.
+func loadMoveRules(home string) []moveRule {
+	path, err := getMoveRulesPath()
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	return parseMoveRules(string(data), home)
+}
+
+// planMoves maps a rule's matches to free names in Dest. Files already inside Dest
+// stay put, and a clash gets Finder's "name 2.ext" treatment.
+func planMoves(rule moveRule, result savedSearchResult) movePlan {
+	plan := movePlan{Source: result.Source, Err: result.Err, Capped: result.Count > len(result.Files)}
+	taken := make(map[string]bool)
+	for _, file := range result.Files {
+		if file.Path == rule.Dest || strings.HasPrefix(file.Path, rule.Dest+"/") {
+			continue
+		}
+		to := freeMoveTarget(filepath.Join(rule.Dest, file.Name), taken)
+		taken[to] = true
+		plan.Items = append(plan.Items, movePlanItem{From: file.Path, To: to, Size: file.Size})
+		plan.Total += file.Size
+	}
+	return plan
+}
+
+// freeMoveTarget returns path, or "name 2.ext", "name 3.ext"... when it is taken.
+func freeMoveTarget(path string, taken map[string]bool) string {
+	free := func(p string) bool {
+		if taken[p] {
+			return false
+		}
+		_, err := os.Lstat(p)
+		return os.IsNotExist(err)
+	}
+	if free(path) {
+		return path
+	}
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+	for n := 2; ; n++ {
+		if candidate := fmt.Sprintf("%s %d%s", base, n, ext); free(candidate) {
+			return candidate
+		}
+	}
+}
+
+// planMovesCmd previews every rule from Spotlight or the scan cache.
+func planMovesCmd(rules []moveRule) tea.Cmd {
+	return func() tea.Msg {
+		now := time.Now()
+		plans := make([]movePlan, len(rules))
+		for i, rule := range rules {
+			plans[i] = planMoves(rule, runSavedSearch(rule.Search, now))
+		}
+		return movePlansMsg{Plans: plans}
+	}
+}
+
+// moveFile renames from to to, falling back to mv across volumes. Neither side is
+// overwritten: a target that appeared since the preview is reported instead.
+func moveFile(from, to string) error {
+	if _, err := os.Lstat(to); err == nil {
+		return &os.PathError{Op: "move", Path: from, Err: os.ErrExist}
+	}
+	if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+		return err
+	}
+	err := os.Rename(from, to)
+	if !errors.Is(err, syscall.EXDEV) {
+		return err
+	}
+	if output, err := exec.Command("mv", "-n", "--", from, to).CombinedOutput(); err != nil {
+		return &os.PathError{Op: "move", Path: from, Err: errors.New(strings.TrimSpace(string(output)))}
+	}
+	if _, err := os.Lstat(from); err == nil {
+		return &os.PathError{Op: "move", Path: from, Err: os.ErrExist}
+	}
+	return nil
+}
+
+// runMovesCmd carries out a plan, journaling each move in the shared log.
+func runMovesCmd(items []movePlanItem, counter *int64) tea.Cmd {
+	return func() tea.Msg {
+		var msg moveProgressMsg
+		var failures deleteFailuresError
+		if err := cleanRunningError(); err != nil {
+			for _, item := range items {
+				failures.add(item.From, err)
+			}
+			msg.Failures = failures.failures
+			return msg
+		}
+		var moved []string
+		for i, item := range items {
+			if stopBatches.Load() {
+				msg.Skipped = items[i:]
+				break
+			}
+			if err := moveFile(item.From, item.To); err != nil {
+				failures.add(item.From, err)
+				continue
+			}
+			appendLedger("SUCCESS", fmt.Sprintf("analyze moved %s to %s", item.From, item.To))
+			msg.Moved = append(msg.Moved, item)
+			moved = append(moved, item.From)
+			atomic.AddInt64(counter, 1)
+		}
+		recordChanges(moved)
+		msg.Failures = failures.failures
+		return msg
+	}
+}
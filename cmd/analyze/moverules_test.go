@@ -0,0 +1,65 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseMoveRules(t *testing.T) {
+	data := "# rules\n*.dmg older:30d in:~/Downloads -> ~/Archive/Installers\nno arrow here\n*.mov -> relative/dir\n"
+	rules := parseMoveRules(data, "/Users/me")
+	if len(rules) != 1 {
+		t.Fatalf("expected 1 rule, got %+v", rules)
+	}
+	rule := rules[0]
+	if rule.Dest != "/Users/me/Archive/Installers" || rule.Search.Root != "/Users/me/Downloads" || rule.Search.Pattern != "*.dmg" {
+		t.Fatalf("unexpected rule: %+v", rule)
+	}
+}
+
+func TestPlanMovesSkipsDestinationAndAvoidsClashes(t *testing.T) {
+	dest := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dest, "setup.dmg"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	rule := moveRule{Dest: dest}
+	result := savedSearchResult{Source: "scan cache", Count: 4, Files: []fileEntry{
+		{Name: "setup.dmg", Path: "/Users/me/Downloads/setup.dmg", Size: 10},
+		{Name: "setup.dmg", Path: "/Users/me/Desktop/setup.dmg", Size: 20},
+		{Name: "other.dmg", Path: filepath.Join(dest, "other.dmg"), Size: 30},
+	}}
+	plan := planMoves(rule, result)
+	if len(plan.Items) != 2 || plan.Total != 30 || !plan.Capped {
+		t.Fatalf("unexpected plan: %+v", plan)
+	}
+	if plan.Items[0].To != filepath.Join(dest, "setup 2.dmg") || plan.Items[1].To != filepath.Join(dest, "setup 3.dmg") {
+		t.Fatalf("unexpected targets: %s, %s", plan.Items[0].To, plan.Items[1].To)
+	}
+}
+
+func TestMoveFileNeverOverwrites(t *testing.T) {
+	dir := t.TempDir()
+	from := filepath.Join(dir, "a.txt")
+	to := filepath.Join(dir, "nested", "a.txt")
+	if err := os.WriteFile(from, []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := moveFile(from, to); err != nil {
+		t.Fatalf("move failed: %v", err)
+	}
+	if _, err := os.Stat(to); err != nil {
+		t.Fatalf("target missing: %v", err)
+	}
+
+	if err := os.WriteFile(from, []byte("b"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := moveFile(from, to); !errors.Is(err, os.ErrExist) {
+		t.Fatalf("expected ErrExist, got %v", err)
+	}
+	if data, _ := os.ReadFile(to); string(data) != "a" {
+		t.Fatalf("target was overwritten: %q", data)
+	}
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"runtime"
+	"syscall"
+)
+
+const (
+	niceEnvVar = "MO_ANALYZE_NICE"
+	// nicePoolLimit caps scan workers under --nice, as when the terminal is in the background.
+	nicePoolLimit = backgroundPoolLimit
+	// niceCPUPriority is the nice value --nice runs at; du and other helpers inherit it.
+	niceCPUPriority = 10
+)
+
+// niceMode is --nice, set once at startup before any scan runs.
+var niceMode bool
+
+// enableNiceMode lowers CPU and disk priority for the process and caps scan workers
+// for the rest of the run, so a long scan stays out of the way of interactive work.
+func enableNiceMode() error {
+	niceMode = true
+	scanPool.setCeiling(nicePoolLimit)
+	runtime.GOMAXPROCS(max(runtime.NumCPU()/2, 1))
+	if err := syscall.Setpriority(syscall.PRIO_PROCESS, 0, niceCPUPriority); err != nil {
+		return err
+	}
+	return setDiskThrottled(true)
+}
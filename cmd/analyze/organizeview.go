@@ -0,0 +1,184 @@
+//go:build darwin
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// organizeView is the ctrl+o screen previewing and running move rules.
+type organizeView struct {
+	Rules    []moveRule
+	Plans    []movePlan
+	Planning bool
+	Selected int
+	Confirm  bool            // Enter pressed once; the next Enter moves the files
+	Moving   *int64          // Files moved so far, nil when idle
+	Failures []deleteFailure // Files the last run could not move
+}
+
+func (v *organizeView) selectedPlan() (movePlan, bool) {
+	if v.Planning || v.Selected >= len(v.Plans) {
+		return movePlan{}, false
+	}
+	return v.Plans[v.Selected], true
+}
+
+func (m model) openOrganize() (tea.Model, tea.Cmd) {
+	if m.inventory != nil {
+		m.status = fmt.Sprintf("%s is a read-only listing", m.inventory.Source)
+		return m, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		m.status = "Unable to find your home folder"
+		return m, nil
+	}
+	rules := loadMoveRules(home)
+	if len(rules) == 0 {
+		path, _ := getMoveRulesPath()
+		m.status = fmt.Sprintf("No move rules yet, add lines like \"*.dmg older:30d -> ~/Archive/Installers\" to %s", displayPath(path))
+		return m, nil
+	}
+	m.organize = &organizeView{Rules: rules, Planning: true}
+	m.status = fmt.Sprintf("Previewing %d move rules...", len(rules))
+	return m, tea.Batch(planMovesCmd(rules), tickCmd())
+}
+
+// updateOrganizeKey handles keys while the move rules screen is open.
+func (m model) updateOrganizeKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	v := m.organize
+	key := msg.String()
+	confirm := v.Confirm
+	v.Confirm = false
+	if v.Moving != nil {
+		if key == "ctrl+c" {
+			return m.quit()
+		}
+		return m, nil
+	}
+	if confirm && key == "esc" {
+		m.status = "Cancelled"
+		return m, nil
+	}
+	switch key {
+	case "q", "Q", "ctrl+c":
+		return m.quit()
+	case "esc", "b", "left", "h", "ctrl+o":
+		m.organize = nil
+	case "up", "k":
+		v.Selected = max(v.Selected-1, 0)
+	case "down", "j":
+		v.Selected = min(v.Selected+1, max(len(v.Rules)-1, 0))
+	case "r", "R":
+		if !v.Planning {
+			v.Planning = true
+			return m, tea.Batch(planMovesCmd(v.Rules), tickCmd())
+		}
+	case "enter":
+		plan, ok := v.selectedPlan()
+		if !ok || len(plan.Items) == 0 {
+			return m, nil
+		}
+		if !confirm {
+			v.Confirm = true
+			return m, nil
+		}
+		var moved int64
+		v.Moving = &moved
+		m.status = fmt.Sprintf("Moving %d files to %s...", len(plan.Items), displayPath(v.Rules[v.Selected].Dest))
+		return m, tea.Batch(runMovesCmd(plan.Items, v.Moving), tickCmd())
+	}
+	return m, nil
+}
+
+// finishMoves reports a finished rule, refreshes its preview and marks cached listings stale.
+func (m model) finishMoves(msg moveProgressMsg) (tea.Model, tea.Cmd) {
+	var moved int64
+	for _, item := range msg.Moved {
+		moved += item.Size
+		invalidateCache(filepath.Dir(item.From))
+	}
+	m.status = fmt.Sprintf("Moved %d files (%s)", len(msg.Moved), humanizeBytes(moved))
+	if len(msg.Failures) > 0 {
+		m.status += fmt.Sprintf(", %d could not be moved", len(msg.Failures))
+	}
+	for i := range m.history {
+		m.history[i].Dirty = true
+	}
+	for path := range m.cache {
+		entry := m.cache[path]
+		entry.Dirty = true
+		m.cache[path] = entry
+	}
+	if m.organize == nil {
+		return m, nil
+	}
+	m.organize.Moving = nil
+	m.organize.Failures = msg.Failures
+	m.organize.Planning = true
+	return m, tea.Batch(planMovesCmd(m.organize.Rules), tickCmd())
+}
+
+// renderOrganize lists rules with what each would move, previewing the selected one.
+func (m model) renderOrganize() string {
+	v := m.organize
+	var b strings.Builder
+	if v.Moving != nil {
+		fmt.Fprintf(&b, "%s%s%s%s Moving: %s%s files%s moved, please wait...\n",
+			colorCyan, colorBold, spinnerFrames[m.spinner], colorReset,
+			colorYellow, formatNumber(atomic.LoadInt64(v.Moving)), colorReset)
+		return b.String()
+	}
+	if v.Planning {
+		fmt.Fprintf(&b, "%s%s%s%s Previewing %d move rules...\n",
+			colorCyan, colorBold, spinnerFrames[m.spinner], colorReset, len(v.Rules))
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "%sMove rules:%s %d\n\n", colorCyan, colorReset, len(v.Rules))
+	for i, rule := range v.Rules {
+		plan := v.Plans[i]
+		prefix, color := "   ", ""
+		if i == v.Selected {
+			prefix, color = fmt.Sprintf(" %s%s▶%s ", colorCyan, colorBold, colorReset), colorCyan
+		}
+		summary := fmt.Sprintf("%s%6s files%s %10s", colorYellow, formatNumber(int64(len(plan.Items))), colorReset, humanizeBytes(plan.Total))
+		if plan.Err != nil {
+			summary = colorGray + plan.Err.Error() + colorReset
+		}
+		fmt.Fprintf(&b, "%s%s%s%s  %s\n", prefix, color, rule.Line, colorReset, summary)
+	}
+
+	plan, _ := v.selectedPlan()
+	if len(plan.Items) > 0 {
+		fmt.Fprintf(&b, "\n%sPreview%s %s(%s)%s\n", colorCyan, colorReset, colorGray, plan.Source, colorReset)
+		nameWidth := calculateNameWidth(m.width)
+		limit := max(calculateViewport(m.height, true)-len(v.Rules)-3, 3)
+		for _, item := range plan.Items[:min(limit, len(plan.Items))] {
+			fmt.Fprintf(&b, "    %s  %s→ %s%s\n",
+				padName(truncateMiddle(displayPath(item.From), nameWidth), nameWidth), colorGray, displayPath(item.To), colorReset)
+		}
+		if rest := len(plan.Items) - limit; rest > 0 {
+			fmt.Fprintf(&b, "    %s… and %d more%s\n", colorGray, rest, colorReset)
+		}
+		if plan.Capped {
+			fmt.Fprintf(&b, "    %sMore files match; run the rule again to move the rest%s\n", colorGray, colorReset)
+		}
+	}
+
+	fmt.Fprintln(&b)
+	if v.Confirm {
+		fmt.Fprintf(&b, "%sMove:%s %d files (%s) to %s  %sPress Enter again  |  ESC cancel%s\n",
+			colorYellow, colorReset, len(plan.Items), humanizeBytes(plan.Total), displayPath(v.Rules[v.Selected].Dest), colorGray, colorReset)
+	} else {
+		fmt.Fprintf(&b, "%s↑↓ | Enter Move | R Refresh | ^O/← Back | Q Quit%s\n", colorGray, colorReset)
+	}
+	return b.String()
+}
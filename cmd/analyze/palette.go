@@ -0,0 +1,276 @@
+//go:build darwin
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const expertModeEnvVar = "MO_ANALYZE_EXPERT"
+
+// paletteState is the ":" prompt available in expert mode.
+type paletteState struct {
+	Input string
+}
+
+type paletteDoneMsg struct {
+	Status string
+}
+
+// scannerKnob is a runtime setting reachable through ":set".
+type scannerKnob struct {
+	get func(m model) string
+	set func(m *model, value string) error
+}
+
+var scannerKnobs = map[string]scannerKnob{
+	"workers": {
+		get: func(model) string { return strconv.Itoa(scanPool.stats().Limit) },
+		set: func(_ *model, value string) error {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("workers expects a number")
+			}
+			scanPool.setLimit(n)
+			return nil
+		},
+	},
+	"delete-workers": {
+		get: func(model) string { return strconv.FormatInt(deleteWorkers.Load(), 10) },
+		set: func(_ *model, value string) error {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return fmt.Errorf("delete-workers expects a number, 0 for automatic")
+			}
+			deleteWorkers.Store(int64(n))
+			return nil
+		},
+	},
+	"sort": {
+		get: func(m model) string { return strings.ToLower(m.entrySort.label()) },
+		set: func(m *model, value string) error {
+			mode, ok := parseEntrySort(value)
+			if !ok {
+				return fmt.Errorf("sort expects %s", entrySortNames())
+			}
+			m.setEntrySort(mode)
+			return nil
+		},
+	},
+	"large-sort": {
+		get: func(m model) string { return strings.ToLower(m.largeSort.label()) },
+		set: func(m *model, value string) error {
+			for mode := largeSortMode(0); mode < largeSortModes; mode++ {
+				if strings.EqualFold(mode.label(), value) {
+					m.largeSort = mode
+					sortLargeFiles(m.largeFiles, mode)
+					return nil
+				}
+			}
+			return fmt.Errorf("large-sort expects size, oldest, path or type")
+		},
+	},
+	"pool-stats": {
+		get: func(m model) string { return strconv.FormatBool(m.showPoolStats) },
+		set: func(m *model, value string) error {
+			on, err := strconv.ParseBool(value)
+			if err != nil {
+				return err
+			}
+			m.showPoolStats = on
+			return nil
+		},
+	},
+	"redact": {
+		get: func(model) string {
+			if activeRedactor == nil {
+				return "-1"
+			}
+			return strconv.Itoa(activeRedactor.depth)
+		},
+		set: func(_ *model, value string) error {
+			depth, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("redact expects a depth, -1 to turn it off")
+			}
+			if depth < 0 {
+				activeRedactor = nil
+			} else {
+				activeRedactor = newRedactor(depth, redactStyleHash)
+			}
+			return nil
+		},
+	},
+	"locale": {
+		get: func(model) string { return detectNumberLocale() },
+		set: func(m *model, value string) error {
+			setNumberLocale(value)
+			setMessageLocale(value)
+			m.rows = newRowCache() // Rows formatted under the old locale
+			return nil
+		},
+	},
+}
+
+// buildScript renders an rm or rsync script for paths; rsync needs a destination.
+func buildScript(kind string, paths []string, dest string) (string, error) {
+	var b strings.Builder
+	b.WriteString("#!/bin/sh\n")
+	fmt.Fprintf(&b, "# Generated by mo analyze on %s. Review before running.\n", time.Now().Format("2006-01-02 15:04"))
+	b.WriteString("set -e\n")
+	switch kind {
+	case "rm":
+		for _, path := range paths {
+			fmt.Fprintf(&b, "rm -rf -- %s\n", shellQuote(path))
+		}
+	case "rsync":
+		if dest == "" {
+			return "", fmt.Errorf("usage: script rsync <destination>")
+		}
+		for _, path := range paths {
+			fmt.Fprintf(&b, "rsync -a -- %s %s\n", shellQuote(path), shellQuote(strings.TrimSuffix(dest, "/")+"/"))
+		}
+	default:
+		return "", fmt.Errorf("unknown script type %q, expected rm or rsync", kind)
+	}
+	return b.String(), nil
+}
+
+func writeScript(kind, body string) (string, error) {
+	dest, err := exportFilePath(kind, "sh")
+	if err != nil {
+		return "", err
+	}
+	if err := writeExportFile(dest, 0644, func(w io.Writer) error {
+		_, err := io.WriteString(w, body)
+		return err
+	}); err != nil {
+		return "", err
+	}
+	return dest, nil
+}
+
+// runWithSelection suspends the UI and runs command with the targets appended as arguments.
+func runWithSelection(command string, paths []string) tea.Cmd {
+	script := command + ` "$@"; status=$?; printf '\n[exit %d] Press Enter to return to mo analyze ' "$status"; read _`
+	args := append([]string{"-c", script, "mo"}, paths...)
+	return tea.ExecProcess(exec.Command("/bin/sh", args...), func(err error) tea.Msg {
+		if err != nil {
+			return paletteDoneMsg{Status: fmt.Sprintf("Command failed: %v", err)}
+		}
+		return paletteDoneMsg{Status: fmt.Sprintf("Ran %s on %d items", strings.Fields(command)[0], len(paths))}
+	})
+}
+
+// runPaletteCommand executes one ":" line.
+func (m model) runPaletteCommand(line string) (tea.Model, tea.Cmd) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return m, nil
+	}
+	targets := m.actionTargets()
+	switch fields[0] {
+	case "script":
+		if m.inventory != nil {
+			m.status = fmt.Sprintf("%s is a read-only listing", m.inventory.Source)
+			return m, nil
+		}
+		if len(fields) < 2 {
+			m.status = "Usage: script rm | script rsync <destination>"
+			return m, nil
+		}
+		if len(targets) == 0 {
+			m.status = "Nothing selected"
+			return m, nil
+		}
+		dest := ""
+		if len(fields) > 2 {
+			dest = strings.Join(fields[2:], " ")
+		}
+		body, err := buildScript(fields[1], targets, dest)
+		if err == nil {
+			var path string
+			if path, err = writeScript(fields[1], body); err == nil {
+				m.status = exportStatus(fmt.Sprintf("Wrote %s script for %d items", fields[1], len(targets)), path)
+				return m, nil
+			}
+		}
+		m.status = err.Error()
+	case "run", "!":
+		if m.inventory != nil {
+			m.status = fmt.Sprintf("%s is a read-only listing", m.inventory.Source)
+			return m, nil
+		}
+		if len(fields) < 2 {
+			m.status = "Usage: run <command>, the selection is appended as arguments"
+			return m, nil
+		}
+		if len(targets) == 0 {
+			m.status = "Nothing selected"
+			return m, nil
+		}
+		return m, runWithSelection(strings.Join(fields[1:], " "), targets)
+	case "set":
+		if len(fields) == 1 {
+			names := make([]string, 0, len(scannerKnobs))
+			for name := range scannerKnobs {
+				names = append(names, fmt.Sprintf("%s=%s", name, scannerKnobs[name].get(m)))
+			}
+			sort.Strings(names)
+			m.status = strings.Join(names, "  ")
+			return m, nil
+		}
+		knob, ok := scannerKnobs[fields[1]]
+		if !ok {
+			m.status = fmt.Sprintf("Unknown setting %q", fields[1])
+			return m, nil
+		}
+		if len(fields) < 3 {
+			m.status = fmt.Sprintf("%s=%s", fields[1], knob.get(m))
+			return m, nil
+		}
+		if err := knob.set(&m, fields[2]); err != nil {
+			m.status = err.Error()
+			return m, nil
+		}
+		m.status = fmt.Sprintf("%s=%s", fields[1], knob.get(m))
+	default:
+		m.status = fmt.Sprintf("Unknown command %q, try script, run or set", fields[0])
+	}
+	return m, nil
+}
+
+// updatePaletteKey handles keys while the ":" prompt is open.
+func (m model) updatePaletteKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.palette = nil
+	case tea.KeyCtrlC:
+		return m.quit()
+	case tea.KeyEnter:
+		line := m.palette.Input
+		m.palette = nil
+		// "!cmd" is shorthand for "run cmd".
+		if strings.HasPrefix(line, "!") {
+			line = "! " + strings.TrimPrefix(line, "!")
+		}
+		return m.runPaletteCommand(line)
+	case tea.KeyBackspace:
+		if runes := []rune(m.palette.Input); len(runes) > 0 {
+			m.palette.Input = string(runes[:len(runes)-1])
+		}
+	case tea.KeySpace:
+		m.palette.Input += " "
+	case tea.KeyRunes:
+		m.palette.Input += string(msg.Runes)
+	}
+	return m, nil
+}
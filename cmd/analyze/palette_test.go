@@ -0,0 +1,92 @@
+//go:build darwin
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestBuildScriptQuotesPaths(t *testing.T) {
+	body, err := buildScript("rm", []string{"/tmp/it's here", "/tmp/b"}, "")
+	if err != nil {
+		t.Fatalf("buildScript rm: %v", err)
+	}
+	if !strings.Contains(body, `rm -rf -- '/tmp/it'"'"'s here'`) {
+		t.Fatalf("rm script did not quote path:\n%s", body)
+	}
+
+	body, err = buildScript("rsync", []string{"/tmp/a"}, "/Volumes/Backup/")
+	if err != nil {
+		t.Fatalf("buildScript rsync: %v", err)
+	}
+	if !strings.Contains(body, "rsync -a -- '/tmp/a' '/Volumes/Backup/'") {
+		t.Fatalf("unexpected rsync script:\n%s", body)
+	}
+
+	if _, err := buildScript("rsync", []string{"/tmp/a"}, ""); err == nil {
+		t.Fatalf("rsync without destination should fail")
+	}
+	if _, err := buildScript("tar", nil, ""); err == nil {
+		t.Fatalf("unknown script type should fail")
+	}
+}
+
+func TestPaletteSetKnob(t *testing.T) {
+	m := newModel("/tmp", false)
+	m.entries = []dirEntry{{Name: "b", Size: 1}, {Name: "a", Size: 2}}
+
+	next, _ := m.runPaletteCommand("set sort name")
+	got := next.(model)
+	if got.entrySort != entrySortName || got.entries[0].Name != "a" {
+		t.Fatalf("set sort name did not apply: %v %+v", got.entrySort, got.entries)
+	}
+
+	next, _ = got.runPaletteCommand("set sort bogus")
+	if got = next.(model); got.entrySort != entrySortName || !strings.Contains(got.status, "sort expects") {
+		t.Fatalf("invalid value should keep sort and report, got %v %q", got.entrySort, got.status)
+	}
+
+	next, _ = got.runPaletteCommand("set nope 1")
+	if got = next.(model); !strings.Contains(got.status, "Unknown setting") {
+		t.Fatalf("unexpected status %q", got.status)
+	}
+}
+
+func TestPaletteRefusesReadOnlyListing(t *testing.T) {
+	m := newModel("/tmp", false)
+	m.inventory = &inventoryTree{Source: "demo"}
+	m.multiSelected = map[string]bool{"/tmp/a": true}
+
+	for _, line := range []string{"script rm", "run ls", "! ls"} {
+		next, cmd := m.runPaletteCommand(line)
+		if got := next.(model); cmd != nil || !strings.Contains(got.status, "read-only listing") {
+			t.Fatalf("%q should be refused on a listing, got %q", line, got.status)
+		}
+	}
+}
+
+func TestPaletteSetPoolStatsKeepsValueOnError(t *testing.T) {
+	m := newModel("/tmp", false)
+	m.showPoolStats = true
+	next, _ := m.runPaletteCommand("set pool-stats junk")
+	if got := next.(model); !got.showPoolStats {
+		t.Fatalf("invalid pool-stats value turned stats off")
+	}
+}
+
+func TestPaletteRequiresExpertMode(t *testing.T) {
+	m := newModel("/tmp", false)
+	next, _ := m.updateKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{':'}})
+	if got := next.(model); got.palette != nil {
+		t.Fatalf("palette opened without expert mode")
+	}
+
+	m.expertMode = true
+	next, _ = m.updateKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{':'}})
+	if got := next.(model); got.palette == nil {
+		t.Fatalf("palette did not open in expert mode")
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"io/fs"
+	"sort"
+	"sync"
+	"syscall"
+)
+
+// partialScan collects finished top-level entries so the UI can render before the scan ends.
+type partialScan struct {
+	mu      sync.Mutex
+	root    string
+	entries []dirEntry
+}
+
+func (p *partialScan) reset(root string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	p.root = root
+	p.entries = nil
+	p.mu.Unlock()
+}
+
+func (p *partialScan) add(entry dirEntry) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	p.entries = append(p.entries, entry)
+	p.mu.Unlock()
+}
+
+// snapshot returns finished entries for root, largest first.
+func (p *partialScan) snapshot(root string) []dirEntry {
+	if p == nil {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.root != root {
+		return nil
+	}
+	entries := cloneDirEntries(p.entries)
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Size > entries[j].Size })
+	return entries
+}
+
+// progressRow is one line of the in-progress listing.
+type progressRow struct {
+	Entry dirEntry
+	Exact bool
+}
+
+// mergeProgressRows prefers exact sizes over estimates, largest first.
+func mergeProgressRows(estimates, exact []dirEntry) []progressRow {
+	rows := make([]progressRow, 0, len(estimates)+len(exact))
+	done := make(map[string]bool, len(exact))
+	for _, entry := range exact {
+		done[entry.Path] = true
+		rows = append(rows, progressRow{Entry: entry, Exact: true})
+	}
+	for _, entry := range estimates {
+		if !done[entry.Path] {
+			rows = append(rows, progressRow{Entry: entry})
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].Entry.Size > rows[j].Entry.Size })
+	return rows
+}
+
+// scanCost approximates child count from the directory link count (cheap, no ReadDir).
+func scanCost(child fs.DirEntry) uint64 {
+	if !child.IsDir() {
+		return 0
+	}
+	info, err := child.Info()
+	if err != nil {
+		return 0
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0
+	}
+	return uint64(stat.Nlink)
+}
+
+// orderByScanCost puts files and small directories first so they stream early.
+func orderByScanCost(children []fs.DirEntry) {
+	costs := make(map[string]uint64, len(children))
+	for _, child := range children {
+		costs[child.Name()] = scanCost(child)
+	}
+	sort.SliceStable(children, func(i, j int) bool {
+		return costs[children[i].Name()] < costs[children[j].Name()]
+	})
+}
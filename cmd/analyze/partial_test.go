@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMergeProgressRowsPrefersExact(t *testing.T) {
+	estimates := []dirEntry{
+		{Name: "big", Path: "/r/big", Size: 900},
+		{Name: "small", Path: "/r/small", Size: 10},
+	}
+	exact := []dirEntry{{Name: "small", Path: "/r/small", Size: 12}}
+
+	rows := mergeProgressRows(estimates, exact)
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(rows))
+	}
+	if rows[0].Entry.Name != "big" || rows[0].Exact {
+		t.Fatalf("expected estimated big first, got %+v", rows[0])
+	}
+	if rows[1].Entry.Size != 12 || !rows[1].Exact {
+		t.Fatalf("expected exact small row, got %+v", rows[1])
+	}
+}
+
+func TestScanPathConcurrentStreamsPartialEntries(t *testing.T) {
+	root := t.TempDir()
+	writeFileWithSize(t, filepath.Join(root, "tiny", "a"), 10)
+	for i := 0; i < 5; i++ {
+		if err := os.MkdirAll(filepath.Join(root, "wide", string(rune('a'+i))), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+	}
+	writeFileWithSize(t, filepath.Join(root, "wide", "a", "b"), 10)
+
+	children, err := os.ReadDir(root)
+	if err != nil {
+		t.Fatalf("readdir: %v", err)
+	}
+	orderByScanCost(children)
+	if children[0].Name() != "tiny" {
+		t.Fatalf("expected smaller directory scheduled first, got %s", children[0].Name())
+	}
+
+	// node_modules is folded and sized by du, which this fake holds until released.
+	if err := os.MkdirAll(filepath.Join(root, "node_modules"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	bin := t.TempDir()
+	release := filepath.Join(bin, "release")
+	fake := "#!/bin/sh\nwhile [ ! -e " + release + " ]; do sleep 0.01; done\necho 4\n"
+	if err := os.WriteFile(filepath.Join(bin, "du"), []byte(fake), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Cleanup(func() { _ = os.WriteFile(release, nil, 0o644) })
+
+	var files, dirs, bytes int64
+	current := ""
+	partial := &partialScan{}
+	done := make(chan error, 1)
+	go func() {
+		_, err := scanPathConcurrent(context.Background(), root, &files, &dirs, &bytes, &current, partial)
+		done <- err
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(partial.snapshot(root)) < 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("walked folders not streamed while node_modules was still sizing")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	for _, entry := range partial.snapshot(root) {
+		if entry.Name == "node_modules" {
+			t.Fatalf("node_modules streamed before du finished")
+		}
+	}
+	select {
+	case <-done:
+		t.Fatalf("scan finished before node_modules was released")
+	default:
+	}
+
+	if err := os.WriteFile(release, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+	if got := partial.snapshot(root); len(got) != 3 {
+		t.Fatalf("expected 3 streamed entries, got %d", len(got))
+	}
+	if got := partial.snapshot("/elsewhere"); got != nil {
+		t.Fatalf("snapshot for another root must be empty")
+	}
+}
+
+func TestScanPathConcurrentStopsWhenCancelled(t *testing.T) {
+	root := t.TempDir()
+	writeFileWithSize(t, filepath.Join(root, "a", "f"), 10)
+	writeFileWithSize(t, filepath.Join(root, "b", "f"), 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var files, dirs, bytes int64
+	current := ""
+	partial := &partialScan{}
+	if _, err := scanPathConcurrent(ctx, root, &files, &dirs, &bytes, &current, partial); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if got := partial.snapshot(root); len(got) != 0 {
+		t.Fatalf("a cancelled scan should not stream unfinished folders, got %d", len(got))
+	}
+}
+
+func TestScanControlFinishKeepsNewerScan(t *testing.T) {
+	control := &scanControl{}
+	first, finishFirst := control.start()
+	second, finishSecond := control.start()
+	defer finishSecond()
+
+	finishFirst()
+	if first.Err() == nil {
+		t.Fatalf("finish should release the first scan's context")
+	}
+	if second.Err() != nil {
+		t.Fatalf("finishing an older scan must not cancel the newer one")
+	}
+	if !control.stop() || second.Err() == nil {
+		t.Fatalf("stop should cancel the newer scan")
+	}
+	if control.stop() {
+		t.Fatalf("stop with nothing running should report false")
+	}
+}
+
+func TestScanControlRunningTracksReplacement(t *testing.T) {
+	control := &scanControl{}
+	_, finishFirst := control.start()
+	control.stop()
+	if control.running() {
+		t.Fatalf("a stopped scan is not running")
+	}
+	_, finishSecond := control.start()
+	finishFirst()
+	if !control.running() {
+		t.Fatalf("the cancelled scan finishing must not hide the rescan that replaced it")
+	}
+	finishSecond()
+	if control.running() {
+		t.Fatalf("expected nothing running once the rescan finished")
+	}
+}
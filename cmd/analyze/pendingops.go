@@ -0,0 +1,122 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// stopBatches asks running deletes and moves to stop before their next item. The
+// items they have not started come back in their done messages.
+var stopBatches atomic.Bool
+
+// handoff is batch work a quitting session passes to a detached helper.
+type handoff struct {
+	Trash    []string       `json:"trash,omitempty"`
+	Delete   []string       `json:"delete,omitempty"`
+	Moves    []movePlanItem `json:"moves,omitempty"`
+	Relocate []movePlanItem `json:"relocate,omitempty"` // Copied, checked, then removed like ">"
+}
+
+func (h handoff) empty() bool {
+	return len(h.Trash) == 0 && len(h.Delete) == 0 && len(h.Moves) == 0 && len(h.Relocate) == 0
+}
+
+func (h handoff) count() int {
+	return len(h.Trash) + len(h.Delete) + len(h.Moves) + len(h.Relocate)
+}
+
+// writeHandoff saves h under the cache dir for the helper to pick up.
+func writeHandoff(h handoff) (string, error) {
+	cacheDir, err := getCacheDir()
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(h)
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(cacheDir, fmt.Sprintf("handoff-%d.json", time.Now().UnixNano()))
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// startHandoffHelper runs "analyze --finish file" in its own session so it outlives
+// the terminal. Its results go to the shared ledger.
+func startHandoffHelper(file string) error {
+	binary, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command(binary, "--finish", file)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	return cmd.Process.Release()
+}
+
+// runHandoff carries out a handoff file written by a quitting session, then removes it.
+func runHandoff(w io.Writer, file string) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	var h handoff
+	if err := json.Unmarshal(data, &h); err != nil {
+		return err
+	}
+	// Drop the file first so a crash cannot replay deletes on a later run.
+	if err := os.Remove(file); err != nil {
+		return err
+	}
+	appendLedger("INFO", fmt.Sprintf("analyze helper finishing %d items from a closed session", h.count()))
+
+	failed := 0
+	for _, batch := range []struct {
+		paths     []string
+		permanent bool
+	}{{h.Trash, false}, {h.Delete, true}} {
+		if len(batch.paths) == 0 {
+			continue
+		}
+		msg := deletePathCmd(batch.paths, nil, nil, batch.permanent)().(deleteProgressMsg)
+		recordDeletions(msg.deleted, msg.trashed)
+		for _, path := range batch.paths {
+			invalidateCache(path)
+		}
+		failed += len(msg.failures)
+		fmt.Fprintf(w, "Removed %d of %d items\n", len(msg.deleted), len(batch.paths))
+	}
+	if len(h.Moves) > 0 {
+		var moved int64
+		msg := runMovesCmd(h.Moves, &moved)().(moveProgressMsg)
+		for _, item := range msg.Moved {
+			invalidateCache(filepath.Dir(item.From))
+		}
+		failed += len(msg.Failures)
+		fmt.Fprintf(w, "Moved %d of %d files\n", len(msg.Moved), len(h.Moves))
+	}
+	if len(h.Relocate) > 0 {
+		var copied int64
+		msg := relocateCmd("", h.Relocate, &copied)().(relocateDoneMsg)
+		for _, item := range msg.Moved {
+			invalidateCache(filepath.Dir(item.From))
+		}
+		failed += len(msg.Failures)
+		fmt.Fprintf(w, "Moved %d of %d items to other volumes\n", len(msg.Moved), len(h.Relocate))
+	}
+	if failed > 0 {
+		appendLedger("WARNING", fmt.Sprintf("analyze helper could not finish %d items", failed))
+		return fmt.Errorf("%d items could not be finished", failed)
+	}
+	return nil
+}
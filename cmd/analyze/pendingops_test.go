@@ -0,0 +1,110 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStoppedBatchesReportSkippedItems(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	base := t.TempDir()
+	var paths []string
+	for _, name := range []string{"a", "b"} {
+		path := filepath.Join(base, name)
+		if err := os.WriteFile(path, []byte(name), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+
+	stopBatches.Store(true)
+	defer stopBatches.Store(false)
+	msg := deletePathCmd(paths, nil, nil, true)().(deleteProgressMsg)
+	if len(msg.deleted) != 0 || len(msg.skipped) != 2 {
+		t.Fatalf("deleted %v, skipped %v", msg.deleted, msg.skipped)
+	}
+	moves := []movePlanItem{{From: paths[0], To: filepath.Join(base, "moved")}}
+	var moved int64
+	if got := runMovesCmd(moves, &moved)().(moveProgressMsg); len(got.Skipped) != 1 || len(got.Moved) != 0 {
+		t.Fatalf("move result = %+v", got)
+	}
+	for _, path := range paths {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("%s should be untouched: %v", path, err)
+		}
+	}
+}
+
+func TestRunHandoff(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	base := t.TempDir()
+	doomed := filepath.Join(base, "doomed")
+	keep := filepath.Join(base, "keep")
+	for _, path := range []string{doomed, keep} {
+		if err := os.WriteFile(path, []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dest := filepath.Join(base, "archive", "keep")
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := writeHandoff(handoff{Delete: []string{doomed}, Moves: []movePlanItem{{From: keep, To: dest, Size: 1}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := runHandoff(io.Discard, file); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(doomed); !os.IsNotExist(err) {
+		t.Errorf("doomed should be deleted: %v", err)
+	}
+	if _, err := os.Stat(dest); err != nil {
+		t.Errorf("keep should be moved: %v", err)
+	}
+	if _, err := os.Stat(file); !os.IsNotExist(err) {
+		t.Errorf("handoff file should be removed: %v", err)
+	}
+}
+
+func TestRunHandoffRelocatesWithCheckedCopy(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	// A ditto that leaves the copy empty, so only the copy check can stop the move.
+	bin := t.TempDir()
+	if err := os.WriteFile(filepath.Join(bin, "ditto"), []byte("#!/bin/sh\nmkdir -p \"$3\"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	base := t.TempDir()
+	from := filepath.Join(base, "project")
+	if err := os.MkdirAll(from, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(from, "file"), []byte("data"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	to := filepath.Join(base, "volume", "project")
+	if err := os.MkdirAll(filepath.Dir(to), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := writeHandoff(handoff{Relocate: []movePlanItem{{From: from, To: to, Size: 4}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := runHandoff(io.Discard, file); err == nil {
+		t.Fatal("incomplete copy should be reported")
+	}
+	if data, err := os.ReadFile(filepath.Join(from, "file")); err != nil || string(data) != "data" {
+		t.Errorf("original should be kept: %q, %v", data, err)
+	}
+	for _, path := range []string{to, to + relocateCopySuffix} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s should not exist: %v", path, err)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// pinsFile lists pinned paths, one per line (same format as the clean whitelist).
+const pinsFile = "analyze_pins"
+
+var (
+	pinsMu     sync.Mutex
+	pinnedSet  map[string]bool
+	pinsLoaded bool
+)
+
+func getPinsPath() (string, error) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return "", err
+	}
+	if activeWorkspace != "" {
+		return filepath.Join(configDir, pinsFile+"."+workspaceSlug(activeWorkspace)), nil
+	}
+	return filepath.Join(configDir, pinsFile), nil
+}
+
+func ensurePinsLoadedLocked() {
+	if pinsLoaded {
+		return
+	}
+	pinsLoaded = true
+	pinnedSet = make(map[string]bool)
+
+	pinsPath, err := getPinsPath()
+	if err != nil {
+		return
+	}
+	file, err := os.Open(pinsPath)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		pinnedSet[filepath.Clean(line)] = true
+	}
+}
+
+func isPinned(path string) bool {
+	pinsMu.Lock()
+	defer pinsMu.Unlock()
+	ensurePinsLoadedLocked()
+	return pinnedSet[path]
+}
+
+// togglePin flips the pin state of path and persists the list.
+func togglePin(path string) (bool, error) {
+	pinsMu.Lock()
+	defer pinsMu.Unlock()
+	ensurePinsLoadedLocked()
+
+	pinned := !pinnedSet[path]
+	if pinned {
+		pinnedSet[path] = true
+	} else {
+		delete(pinnedSet, path)
+	}
+	return pinned, persistPinsLocked()
+}
+
+func persistPinsLocked() error {
+	pinsPath, err := getPinsPath()
+	if err != nil {
+		return err
+	}
+	paths := make([]string, 0, len(pinnedSet))
+	for path := range pinnedSet {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var b strings.Builder
+	b.WriteString("# Mole analyze pinned entries\n")
+	for _, path := range paths {
+		b.WriteString(path)
+		b.WriteString("\n")
+	}
+	tmpPath := pinsPath + ".tmp"
+	if err := os.WriteFile(tmpPath, []byte(b.String()), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, pinsPath)
+}
+
+// applyPins moves pinned entries to the top, keeping relative order otherwise. Callers
+// sort first (see orderEntries), so an unpinned entry drops back to its sorted place.
+func applyPins(entries []dirEntry) []dirEntry {
+	if len(entries) == 0 {
+		return entries
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return isPinned(entries[i].Path) && !isPinned(entries[j].Path)
+	})
+	return entries
+}
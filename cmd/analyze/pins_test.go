@@ -0,0 +1,51 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func resetPinsForTest() {
+	pinsMu.Lock()
+	pinnedSet = nil
+	pinsLoaded = false
+	pinsMu.Unlock()
+}
+
+func TestTogglePinPersistsAndReorders(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	resetPinsForTest()
+	t.Cleanup(resetPinsForTest)
+
+	build := filepath.Join(home, "project", "build")
+	if pinned, err := togglePin(build); err != nil || !pinned {
+		t.Fatalf("togglePin: pinned=%v err=%v", pinned, err)
+	}
+
+	resetPinsForTest()
+	if !isPinned(build) {
+		t.Fatalf("expected pin to persist across reloads")
+	}
+	if _, err := os.Stat(filepath.Join(home, ".config", "mole", pinsFile)); err != nil {
+		t.Fatalf("expected pins file: %v", err)
+	}
+
+	entries := applyPins([]dirEntry{
+		{Name: "src", Path: filepath.Join(home, "project", "src"), Size: 300},
+		{Name: "docs", Path: filepath.Join(home, "project", "docs"), Size: 200},
+		{Name: "build", Path: build, Size: 100},
+	})
+	if entries[0].Path != build || entries[1].Name != "src" || entries[2].Name != "docs" {
+		t.Fatalf("unexpected order after applyPins: %+v", entries)
+	}
+
+	if pinned, err := togglePin(build); err != nil || pinned {
+		t.Fatalf("unpin: pinned=%v err=%v", pinned, err)
+	}
+	resetPinsForTest()
+	if isPinned(build) {
+		t.Fatalf("expected pin removal to persist")
+	}
+}
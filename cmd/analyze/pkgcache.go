@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// packageCacheTimeout bounds a tool's own cleanup command.
+	packageCacheTimeout = 5 * time.Minute
+	// packageCacheUseDepth is how deep last-use dates look; caches add folders near the top.
+	packageCacheUseDepth = 3
+)
+
+// packageCacheTool is one package manager's download cache and how to clear it safely:
+// with the tool's own command when it has one, otherwise by removing the folders while
+// the tool is not running.
+type packageCacheTool struct {
+	Name    string
+	Paths   []string // Home-relative
+	Command []string // Cleanup command; the first element is looked up on PATH
+	Busy    []string // pgrep -f patterns that must not match before folders are removed
+}
+
+var packageCacheTools = []packageCacheTool{
+	{Name: "npm", Paths: []string{".npm/_cacache"}, Command: []string{"npm", "cache", "clean", "--force"}},
+	{Name: "Yarn", Paths: []string{"Library/Caches/Yarn", ".yarn/berry/cache", ".cache/yarn"}, Command: []string{"yarn", "cache", "clean"}},
+	{Name: "pnpm", Paths: []string{"Library/pnpm/store", ".local/share/pnpm/store", ".pnpm-store"}, Command: []string{"pnpm", "store", "prune"}},
+	{Name: "pip", Paths: []string{"Library/Caches/pip", ".cache/pip"}, Command: []string{"python3", "-m", "pip", "cache", "purge"}},
+	{Name: "Cargo", Paths: []string{".cargo/registry/cache", ".cargo/registry/src"}, Busy: []string{"cargo", "rustc"}},
+	{Name: "Go modules", Paths: []string{"go/pkg/mod"}, Command: []string{"go", "clean", "-modcache"}},
+	{Name: "Gradle", Paths: []string{".gradle/caches"}, Busy: []string{"GradleDaemon", "GradleWrapperMain"}},
+}
+
+// packageCache is what one tool's cache holds on this Mac.
+type packageCache struct {
+	Tool    packageCacheTool
+	Paths   []string // Existing folders
+	Size    int64
+	LastUse time.Time // Newest change near the top of the cache
+}
+
+type packageCachesMsg struct {
+	Caches []packageCache
+	Err    error
+}
+
+type packageCacheCleanMsg struct {
+	Tool   string
+	Output string
+	Paths  []string // Folders removed directly, for other Mole processes
+	Err    error
+}
+
+// cleanupLabel describes how a cache will be cleared.
+func (c packageCache) cleanupLabel() string {
+	if len(c.Tool.Command) > 0 {
+		return strings.Join(c.Tool.Command, " ")
+	}
+	return "remove the folders while " + c.Tool.Name + " is not running"
+}
+
+// safeCategory is the safe mode category of the cache's folders. When safe mode does not
+// allow one of them, it returns that folder instead.
+func (c packageCache) safeCategory() (safeCategory, string) {
+	var category safeCategory
+	for _, path := range c.Paths {
+		found, ok := safeDeleteCategory(path)
+		if !ok {
+			return safeCategory{}, path
+		}
+		category = found
+	}
+	return category, ""
+}
+
+// lastUseUnder is the newest modification time of root and what lies within depth levels of it.
+func lastUseUnder(root string, depth int) time.Time {
+	var newest time.Time
+	base := strings.Count(root, string(filepath.Separator))
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info, err := d.Info(); err == nil && info.ModTime().After(newest) {
+			newest = info.ModTime()
+		}
+		if d.IsDir() && strings.Count(path, string(filepath.Separator))-base >= depth {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	return newest
+}
+
+// findPackageCaches sizes every tool's cache under home, largest first.
+func findPackageCaches(home string, tools []packageCacheTool) []packageCache {
+	var caches []packageCache
+	for _, tool := range tools {
+		cache := packageCache{Tool: tool}
+		for _, rel := range tool.Paths {
+			path := filepath.Join(home, rel)
+			if info, err := os.Lstat(path); err == nil && info.IsDir() {
+				cache.Paths = append(cache.Paths, path)
+			}
+		}
+		if len(cache.Paths) > 0 {
+			caches = append(caches, cache)
+		}
+	}
+	var wg sync.WaitGroup
+	for i := range caches {
+		wg.Add(1)
+		go func(cache *packageCache) {
+			defer wg.Done()
+			for _, path := range cache.Paths {
+				cache.Size += measureCleanPath(path)
+				if used := lastUseUnder(path, packageCacheUseDepth); used.After(cache.LastUse) {
+					cache.LastUse = used
+				}
+			}
+		}(&caches[i])
+	}
+	wg.Wait()
+	sort.SliceStable(caches, func(a, b int) bool { return caches[a].Size > caches[b].Size })
+	return caches
+}
+
+func findPackageCachesCmd(home string) tea.Cmd {
+	return func() tea.Msg {
+		return packageCachesMsg{Caches: findPackageCaches(home, packageCacheTools)}
+	}
+}
+
+// packageToolBusy names the first Busy pattern with a running process.
+func packageToolBusy(patterns []string) string {
+	for _, pattern := range patterns {
+		if exec.Command("pgrep", "-f", pattern).Run() == nil {
+			return pattern
+		}
+	}
+	return ""
+}
+
+// cleanPackageCacheCmd clears one cache with its tool's command, or removes its folders
+// under the analyzer's delete lock, as a clean run does.
+func cleanPackageCacheCmd(cache packageCache) tea.Cmd {
+	return func() tea.Msg {
+		msg := packageCacheCleanMsg{Tool: cache.Tool.Name}
+		if len(cache.Tool.Command) > 0 {
+			tool, err := exec.LookPath(cache.Tool.Command[0])
+			if err != nil {
+				msg.Err = fmt.Errorf("%s is not installed, so its cache is left alone", cache.Tool.Command[0])
+				return msg
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), packageCacheTimeout)
+			defer cancel()
+			out, err := exec.CommandContext(ctx, tool, cache.Tool.Command[1:]...).CombinedOutput()
+			msg.Output = strings.TrimSpace(string(out))
+			if err != nil {
+				msg.Err = fmt.Errorf("%s: %s", strings.Join(cache.Tool.Command, " "), firstLine(msg.Output, err))
+			}
+			return msg
+		}
+		if busy := packageToolBusy(cache.Tool.Busy); busy != "" {
+			msg.Err = fmt.Errorf("%s is running; quit it before clearing the %s cache", busy, cache.Tool.Name)
+			return msg
+		}
+		if err := cleanRunningError(); err != nil {
+			msg.Err = err
+			return msg
+		}
+		if release, err := acquireLock(analyzeLockFile); err == nil {
+			defer release()
+		}
+		for _, path := range cache.Paths {
+			if err := os.RemoveAll(path); err != nil {
+				msg.Err = err
+				break
+			}
+			msg.Paths = append(msg.Paths, path)
+		}
+		recordChanges(msg.Paths)
+		return msg
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFindPackageCaches(t *testing.T) {
+	home := t.TempDir()
+	writeFileWithSize(t, filepath.Join(home, ".npm", "_cacache", "content-v2", "sha512", "ab", "blob"), 256<<10)
+	writeFileWithSize(t, filepath.Join(home, ".cargo", "registry", "cache", "index", "serde.crate"), 64<<10)
+	writeFileWithSize(t, filepath.Join(home, ".cargo", "registry", "src", "index", "serde", "lib.rs"), 64<<10)
+	writeFileWithSize(t, filepath.Join(home, ".pnpm-store"), 4096) // A file, not a store
+
+	old := time.Now().Add(-48 * time.Hour)
+	for _, rel := range []string{".npm/_cacache", ".npm/_cacache/content-v2", ".npm/_cacache/content-v2/sha512"} {
+		if err := os.Chtimes(filepath.Join(home, rel), old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	caches := findPackageCaches(home, packageCacheTools)
+	if len(caches) != 2 || caches[0].Tool.Name != "npm" || caches[1].Tool.Name != "Cargo" {
+		t.Fatalf("caches = %+v, want npm then Cargo", caches)
+	}
+	if len(caches[1].Paths) != 2 || caches[1].Size < 128<<10 {
+		t.Errorf("Cargo = %+v, want both registry folders measured", caches[1])
+	}
+	// The newest change within three levels is the blob's "ab" folder, created just now.
+	if time.Since(caches[0].LastUse) > time.Hour {
+		t.Errorf("npm last use = %s", caches[0].LastUse)
+	}
+}
+
+func TestLastUseUnderStopsAtDepth(t *testing.T) {
+	root := t.TempDir()
+	writeFileWithSize(t, filepath.Join(root, "a", "b", "c", "d", "deep.bin"), 1024)
+	old := time.Now().Add(-72 * time.Hour)
+	for _, rel := range []string{"", "a", "a/b", "a/b/c"} {
+		if err := os.Chtimes(filepath.Join(root, rel), old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := lastUseUnder(root, 3); !got.Equal(old) {
+		t.Errorf("lastUseUnder = %s, want %s from the levels it reads", got, old)
+	}
+}
+
+func TestCleanPackageCacheRemovesFoldersWhenIdle(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	path := filepath.Join(home, ".gradle", "caches")
+	writeFileWithSize(t, filepath.Join(path, "modules-2", "files.bin"), 4096)
+
+	cache := packageCache{
+		Tool:  packageCacheTool{Name: "Gradle"},
+		Paths: []string{path},
+	}
+	if !strings.Contains(cache.cleanupLabel(), "not running") {
+		t.Errorf("label = %q", cache.cleanupLabel())
+	}
+	msg := cleanPackageCacheCmd(cache)().(packageCacheCleanMsg)
+	if msg.Err != nil || len(msg.Paths) != 1 {
+		t.Fatalf("clean = %+v", msg)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("cache folder still there")
+	}
+}
+
+func TestPackageCacheSafeCategory(t *testing.T) {
+	yarn := packageCache{Paths: []string{"/Users/a/Library/Caches/Yarn", "/Users/a/.cache/yarn"}}
+	if category, blocked := yarn.safeCategory(); blocked != "" || category != safeCache {
+		t.Errorf("Yarn = %+v, %q, want the cache category", category, blocked)
+	}
+	gradle := packageCache{Paths: []string{"/Users/a/Library/Caches/Gradle", "/Users/a/.gradle/caches"}}
+	if _, blocked := gradle.safeCategory(); blocked != "/Users/a/.gradle/caches" {
+		t.Errorf("Gradle blocked = %q, want the folder outside the caches", blocked)
+	}
+}
@@ -0,0 +1,170 @@
+//go:build darwin
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// packageCacheView is the "Y" screen listing package manager caches with a cleanup for each.
+type packageCacheView struct {
+	Caches   []packageCache
+	Selected int
+	Scanning bool
+	Cleaning bool
+	Confirm  bool // Enter pressed once; the next Enter clears the selected cache
+	Refresh  bool // Measuring again after a cleanup, whose result stays in the status line
+}
+
+func (m model) openPackageCaches() (tea.Model, tea.Cmd) {
+	if m.inventory != nil {
+		m.status = fmt.Sprintf("%s is a read-only listing", m.inventory.Source)
+		return m, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		m.status = "Unable to find your home folder"
+		return m, nil
+	}
+	m.packageCaches = &packageCacheView{Scanning: true}
+	m.status = "Measuring package manager caches..."
+	return m, tea.Batch(findPackageCachesCmd(home), tickCmd())
+}
+
+// updatePackageCacheKey handles keys while the package cache list is open.
+func (m model) updatePackageCacheKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	v := m.packageCaches
+	key := msg.String()
+	if key != "enter" {
+		v.Confirm = false
+	}
+	switch key {
+	case "q", "Q", "ctrl+c":
+		return m.quit()
+	case "esc", "b", "left", "h", "Y":
+		if v.Cleaning {
+			m.status = "Clearing a cache, wait for it to finish"
+			return m, nil
+		}
+		m.packageCaches = nil
+	case "up", "k":
+		v.Selected = max(v.Selected-1, 0)
+	case "down", "j":
+		v.Selected = min(v.Selected+1, max(len(v.Caches)-1, 0))
+	case "enter":
+		if v.Scanning || v.Cleaning || v.Selected >= len(v.Caches) {
+			return m, nil
+		}
+		cache := v.Caches[v.Selected]
+		// The tools' own cleanups remove the same folders, so safe mode checks them all.
+		if _, blocked := cache.safeCategory(); m.safeMode && blocked != "" {
+			v.Confirm = false
+			m.status = safeModeBlockReason(blocked)
+			return m, nil
+		}
+		if !v.Confirm {
+			v.Confirm = true
+			m.status = fmt.Sprintf("Press Enter again to clear %s from the %s cache: %s", humanizeBytes(cache.Size), cache.Tool.Name, cache.cleanupLabel())
+			return m, nil
+		}
+		v.Confirm, v.Cleaning = false, true
+		m.status = fmt.Sprintf("Clearing the %s cache...", cache.Tool.Name)
+		return m, tea.Batch(cleanPackageCacheCmd(cache), tickCmd())
+	}
+	return m, nil
+}
+
+// applyPackageCacheClean reports a cleanup and measures the caches again.
+func (m model) applyPackageCacheClean(msg packageCacheCleanMsg) (tea.Model, tea.Cmd) {
+	if m.packageCaches == nil {
+		return m, nil
+	}
+	m.packageCaches.Cleaning = false
+	if msg.Err != nil {
+		m.status = msg.Err.Error()
+		return m, nil
+	}
+	selected := m.packageCaches.Selected
+	next, cmd := m.openPackageCaches()
+	updated, ok := next.(model)
+	if !ok {
+		return next, cmd
+	}
+	updated.packageCaches.Selected = selected
+	updated.packageCaches.Refresh = true
+	updated.status = fmt.Sprintf("Cleared the %s cache", msg.Tool)
+	if len(msg.Paths) > 0 {
+		changed, changeCmd := updated.applyExternalChanges(msg.Paths)
+		if refreshed, ok := changed.(model); ok {
+			refreshed.status = updated.status
+			return refreshed, tea.Batch(cmd, changeCmd)
+		}
+	}
+	return updated, cmd
+}
+
+// renderPackageCaches lists each tool's cache with its size and when it was last used.
+func (m model) renderPackageCaches() string {
+	v := m.packageCaches
+	var b strings.Builder
+	if v.Scanning || v.Cleaning {
+		action := "Measuring package manager caches"
+		if v.Cleaning {
+			action = "Clearing the cache"
+		}
+		fmt.Fprintf(&b, "%s%s%s%s %s...\n", colorCyan, colorBold, spinnerFrames[m.spinner], colorReset, action)
+		return b.String()
+	}
+	if len(v.Caches) == 0 {
+		fmt.Fprintf(&b, "  %sNo npm, Yarn, pnpm, pip, Cargo, Go or Gradle caches found%s\n\n", colorGray, colorReset)
+		fmt.Fprintf(&b, "%sY/← Back | Q Quit%s\n", colorGray, colorReset)
+		return b.String()
+	}
+
+	var total int64
+	for _, cache := range v.Caches {
+		total += cache.Size
+	}
+	fmt.Fprintf(&b, "%sPackage caches:%s %s%s%s across %d tools\n\n",
+		colorCyan, colorReset, colorYellow, humanizeBytes(total), colorReset, len(v.Caches))
+	for i, cache := range v.Caches {
+		prefix, color := "   ", ""
+		if i == v.Selected {
+			prefix, color = fmt.Sprintf(" %s%s▶%s ", colorCyan, colorBold, colorReset), colorCyan
+		}
+		percent := 0.0
+		if total > 0 {
+			percent = float64(cache.Size) / float64(total) * 100
+		}
+		used := "unknown"
+		if !cache.LastUse.IsZero() {
+			used = cache.LastUse.Format("2006-01-02")
+		}
+		fmt.Fprintf(&b, "%s%s %5.1f%%  |  %s%s%-12s%s %10s  %slast used %s%s\n",
+			prefix, coloredProgressBar(cache.Size, total, percent), percent,
+			color, colorBold, cache.Tool.Name, colorReset, humanizeBytes(cache.Size), colorGray, used, colorReset)
+		if i != v.Selected {
+			continue
+		}
+		for _, path := range cache.Paths {
+			fmt.Fprintf(&b, "          %s%s%s\n", colorGray, displayPath(path), colorReset)
+		}
+		fmt.Fprintf(&b, "          %sCleanup: %s%s\n", colorGray, cache.cleanupLabel(), colorReset)
+		if !m.safeMode {
+			continue
+		}
+		if category, blocked := cache.safeCategory(); blocked == "" {
+			fmt.Fprintf(&b, "          %s%s:%s %s\n", colorGreen, category.Label, colorReset, category.Explain)
+		} else {
+			fmt.Fprintf(&b, "          %s%s%s\n", colorYellow, safeModeBlockReason(blocked), colorReset)
+		}
+	}
+
+	fmt.Fprintln(&b)
+	fmt.Fprintf(&b, "%s↑↓ | Enter Clear cache | Y/← Back | Q Quit%s\n", colorGray, colorReset)
+	return b.String()
+}
@@ -0,0 +1,38 @@
+//go:build darwin
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestPackageCacheCleanupBlockedInSafeMode(t *testing.T) {
+	m := newModel("/tmp/root", false)
+	m.safeMode = true
+	m.packageCaches = &packageCacheView{Caches: []packageCache{
+		{Tool: packageCacheTool{Name: "npm"}, Paths: []string{"/Users/a/.npm/_cacache"}},
+		{Tool: packageCacheTool{Name: "pip"}, Paths: []string{"/Users/a/Library/Caches/pip"}},
+	}}
+
+	next, cmd := m.updatePackageCacheKey(tea.KeyMsg{Type: tea.KeyEnter})
+	m = next.(model)
+	if cmd != nil || m.packageCaches.Confirm || !strings.HasPrefix(m.status, "Safe mode:") {
+		t.Fatalf("npm cleanup should be refused in safe mode, status %q", m.status)
+	}
+	if view := m.renderPackageCaches(); !strings.Contains(view, "_cacache may be personal files") {
+		t.Errorf("the refusal should be shown under the cache:\n%s", view)
+	}
+
+	m.packageCaches.Selected = 1
+	next, _ = m.updatePackageCacheKey(tea.KeyMsg{Type: tea.KeyEnter})
+	m = next.(model)
+	if !m.packageCaches.Confirm {
+		t.Fatalf("pip cache should be allowed in safe mode, status %q", m.status)
+	}
+	if view := m.renderPackageCaches(); !strings.Contains(view, safeCache.Explain) {
+		t.Errorf("the cache should be explained in safe mode:\n%s", view)
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"os"
+	"runtime"
+	"sync"
+)
+
+// workerPool bounds concurrent directory reads across the whole scan.
+// The limit can change while workers are running.
+type workerPool struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	limit   int
+	active  int
+	waiting int
+	peak    int
+	ceiling int // Upper bound for setLimit, 0 for none
+	paused  bool
+}
+
+// poolStats is a point-in-time view of the pool for the debug panel.
+type poolStats struct {
+	Limit      int
+	Active     int
+	Waiting    int
+	Peak       int
+	OpenFDs    int
+	Goroutines int
+}
+
+var scanPool = newWorkerPool(defaultPoolLimit())
+
+func newWorkerPool(limit int) *workerPool {
+	p := &workerPool{limit: max(limit, 1)}
+	p.cond = sync.NewCond(&p.mu)
+	return p
+}
+
+func defaultPoolLimit() int {
+	return min(max(runtime.NumCPU()*cpuMultiplier, minWorkers), maxWorkers)
+}
+
+func (p *workerPool) acquire() {
+	p.mu.Lock()
+	p.waiting++
+	for p.paused || p.active >= p.limit {
+		p.cond.Wait()
+	}
+	p.waiting--
+	p.active++
+	if p.active > p.peak {
+		p.peak = p.active
+	}
+	p.mu.Unlock()
+}
+
+func (p *workerPool) release() {
+	p.mu.Lock()
+	p.active--
+	p.mu.Unlock()
+	p.cond.Signal()
+}
+
+// setLimit clamps and applies a new cap; waiters wake if the cap grew.
+func (p *workerPool) setLimit(limit int) int {
+	limit = min(max(limit, 1), maxWorkers*4)
+	p.mu.Lock()
+	if p.ceiling > 0 {
+		limit = min(limit, p.ceiling)
+	}
+	p.limit = limit
+	p.mu.Unlock()
+	p.cond.Broadcast()
+	return limit
+}
+
+// setCeiling bounds the current and every later limit, e.g. for --nice, and returns
+// the previous ceiling. 0 removes the bound without raising the current limit.
+func (p *workerPool) setCeiling(ceiling int) int {
+	p.mu.Lock()
+	previous := p.ceiling
+	p.ceiling = ceiling
+	if ceiling > 0 {
+		p.limit = min(p.limit, ceiling)
+	}
+	p.mu.Unlock()
+	return previous
+}
+
+// setPaused holds new directory reads until resumed; reads already running finish.
+func (p *workerPool) setPaused(paused bool) {
+	p.mu.Lock()
+	p.paused = paused
+	p.mu.Unlock()
+	p.cond.Broadcast()
+}
+
+func (p *workerPool) isPaused() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.paused
+}
+
+func (p *workerPool) currentLimit() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.limit
+}
+
+func (p *workerPool) stats() poolStats {
+	p.mu.Lock()
+	stats := poolStats{Limit: p.limit, Active: p.active, Waiting: p.waiting, Peak: p.peak}
+	p.mu.Unlock()
+	stats.OpenFDs = countOpenFDs()
+	stats.Goroutines = runtime.NumGoroutine()
+	return stats
+}
+
+// countOpenFDs lists /dev/fd; returns -1 when unavailable.
+func countOpenFDs() int {
+	entries, err := os.ReadDir("/dev/fd")
+	if err != nil {
+		return -1
+	}
+	// ReadDir itself holds one descriptor while listing.
+	return len(entries) - 1
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWorkerPoolRespectsLimit(t *testing.T) {
+	pool := newWorkerPool(2)
+	var running, maxSeen int64
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pool.acquire()
+			defer pool.release()
+			n := atomic.AddInt64(&running, 1)
+			for {
+				seen := atomic.LoadInt64(&maxSeen)
+				if n <= seen || atomic.CompareAndSwapInt64(&maxSeen, seen, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt64(&running, -1)
+		}()
+	}
+	wg.Wait()
+
+	if maxSeen > 2 {
+		t.Fatalf("expected at most 2 concurrent workers, saw %d", maxSeen)
+	}
+	if stats := pool.stats(); stats.Active != 0 || stats.Peak != 2 {
+		t.Fatalf("unexpected stats after drain: %+v", stats)
+	}
+}
+
+func TestWorkerPoolSetLimitWakesWaiters(t *testing.T) {
+	pool := newWorkerPool(1)
+	pool.acquire()
+
+	done := make(chan struct{})
+	go func() {
+		pool.acquire()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		t.Fatalf("second acquire should block at limit 1")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	if got := pool.setLimit(2); got != 2 {
+		t.Fatalf("setLimit returned %d", got)
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("raising the limit should wake the waiter")
+	}
+	if got := pool.setLimit(0); got != 1 {
+		t.Fatalf("expected limit clamped to 1, got %d", got)
+	}
+}
+
+func TestWorkerPoolPauseHoldsNewWork(t *testing.T) {
+	pool := newWorkerPool(4)
+	pool.setPaused(true)
+
+	done := make(chan struct{})
+	go func() {
+		pool.acquire()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		t.Fatalf("acquire should block while paused")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	pool.setPaused(false)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("resuming did not wake the waiter")
+	}
+	pool.release()
+}
+
+func TestWorkerPoolCeilingBoundsLaterLimits(t *testing.T) {
+	pool := newWorkerPool(8)
+	pool.setCeiling(2)
+	if got := pool.currentLimit(); got != 2 {
+		t.Fatalf("ceiling should lower the current limit, got %d", got)
+	}
+	if got := pool.setLimit(16); got != 2 {
+		t.Fatalf("setLimit should stay under the ceiling, got %d", got)
+	}
+	if got := pool.setLimit(1); got != 1 {
+		t.Fatalf("setLimit below the ceiling should apply, got %d", got)
+	}
+}
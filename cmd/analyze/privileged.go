@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// privilegedMode is --sudo: overview folders are measured by du running as root, and
+// the overview adds the root-owned areas Finder counts as System Data.
+var privilegedMode atomic.Bool
+
+// sudoRefreshInterval keeps sudo's cached credential from expiring mid-session.
+const sudoRefreshInterval = 4 * time.Minute
+
+// privilegedAreas are the root-owned locations listed only in privileged mode.
+var privilegedAreas = []struct {
+	Name string
+	Path string
+}{
+	{"System Data: /private/var", "/private/var"},
+	{"System Data: Spotlight index", "/System/Volumes/Data/.Spotlight-V100"},
+	{"System Data: file versions", "/System/Volumes/Data/.DocumentRevisions-V100"},
+	{"System Data: file system events", "/System/Volumes/Data/.fseventsd"},
+}
+
+// privilegedOverviewEntries lists the system areas that exist and every other user's home.
+func privilegedOverviewEntries(home string) []dirEntry {
+	var entries []dirEntry
+	for _, area := range privilegedAreas {
+		if _, err := os.Lstat(area.Path); err == nil {
+			entries = append(entries, dirEntry{Name: area.Name, Path: area.Path, IsDir: true, Size: -1, Privileged: true})
+		}
+	}
+	users, _ := os.ReadDir("/Users")
+	for _, user := range users {
+		path := filepath.Join("/Users", user.Name())
+		if !user.IsDir() || strings.HasPrefix(user.Name(), ".") || user.Name() == "Shared" || path == home {
+			continue
+		}
+		entries = append(entries, dirEntry{Name: "User: " + user.Name(), Path: path, IsDir: true, Size: -1, Privileged: true})
+	}
+	return entries
+}
+
+// startPrivileged asks for the sudo password before the UI takes over the terminal,
+// then keeps the credential fresh for the rest of the session.
+func startPrivileged() error {
+	if os.Geteuid() != 0 {
+		cmd := exec.Command("sudo", "-v", "-p", "Password to measure system areas as root: ")
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("sudo: %w", err)
+		}
+		go func() {
+			for range time.Tick(sudoRefreshInterval) {
+				_ = exec.Command("sudo", "-n", "-v").Run()
+			}
+		}()
+	}
+	privilegedMode.Store(true)
+	return nil
+}
+
+// sudoDuSize measures path with du as root, minus excludePath when set.
+func sudoDuSize(ctx context.Context, path, excludePath string) (int64, error) {
+	if !privilegedMode.Load() {
+		return 0, fmt.Errorf("needs --sudo")
+	}
+	size, err := sudoDu(ctx, path)
+	if err != nil || excludePath == "" {
+		return size, err
+	}
+	excluded, err := sudoDu(ctx, excludePath)
+	if err != nil || excluded > size {
+		excluded = 0
+	}
+	return size - excluded, nil
+}
+
+// sudoDu runs du -sk through sudo -n, which fails rather than prompting. Paths even
+// root cannot read (SIP) make du exit 1, but the total it printed still stands.
+func sudoDu(ctx context.Context, path string) (int64, error) {
+	args := append(append([]string{"du", "-sk"}, duExcludeArgs()...), path)
+	if os.Geteuid() != 0 {
+		args = append([]string{"sudo", "-n"}, args...)
+	}
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	runErr := cmd.Run()
+	fields := strings.Fields(stdout.String())
+	if len(fields) == 0 {
+		if runErr != nil {
+			return 0, fmt.Errorf("sudo du failed: %v (%s)", runErr, strings.TrimSpace(stderr.String()))
+		}
+		return 0, fmt.Errorf("du output empty")
+	}
+	kb, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse du output: %v", err)
+	}
+	return kb * 1024, nil
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSudoDuSizeNeedsPrivilegedMode(t *testing.T) {
+	if _, err := sudoDuSize(context.Background(), t.TempDir(), ""); err == nil {
+		t.Fatal("sudo-du should step aside without --sudo")
+	}
+}
+
+func TestSudoDuSizeAsRoot(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("needs root to run du without a sudo prompt")
+	}
+	privilegedMode.Store(true)
+	defer privilegedMode.Store(false)
+
+	root := t.TempDir()
+	sub := filepath.Join(root, "sub")
+	if err := os.MkdirAll(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{filepath.Join(root, "a"), filepath.Join(sub, "b")} {
+		if err := os.WriteFile(path, make([]byte, 64*1024), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	total, err := sudoDuSize(context.Background(), root, "")
+	if err != nil || total < 128*1024 {
+		t.Fatalf("total = %d, %v", total, err)
+	}
+	rest, err := sudoDuSize(context.Background(), root, sub)
+	if err != nil || rest >= total {
+		t.Fatalf("size without sub = %d of %d, %v", rest, total, err)
+	}
+}
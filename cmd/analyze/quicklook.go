@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	inlinePreviewMaxSize = 256 << 10 // Text files up to this size are shown in the terminal
+	inlinePreviewLines   = 20
+)
+
+// filePreview is the start of a small text file, shown instead of a Quick Look window.
+type filePreview struct {
+	Path      string
+	Size      int64
+	Lines     []string
+	Truncated bool // The file goes on past Lines
+}
+
+type quickLookMsg struct {
+	Path string
+	Err  error
+}
+
+// readTextPreview returns the first lines of a small text file. Large or binary files,
+// and cloud placeholders that reading would download, return false.
+func readTextPreview(path string, maxLines int) (filePreview, bool) {
+	info, err := os.Lstat(path)
+	if err != nil || !info.Mode().IsRegular() || info.Size() > inlinePreviewMaxSize || datalessInfo(info) {
+		return filePreview{}, false
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return filePreview{}, false
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil || bytes.IndexByte(data, 0) >= 0 || !utf8.Valid(data) {
+		return filePreview{}, false
+	}
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	preview := filePreview{Path: path, Size: info.Size(), Lines: lines}
+	if len(lines) > maxLines {
+		preview.Lines, preview.Truncated = lines[:maxLines], true
+	}
+	return preview, true
+}
+
+// quickLookCmd shows path in a Quick Look window; qlmanage returns once it is closed.
+func quickLookCmd(path string) tea.Cmd {
+	return func() tea.Msg {
+		if isDataless(path) {
+			return quickLookMsg{Path: path, Err: fmt.Errorf("%w; previewing it would download it", errDataless)}
+		}
+		return quickLookMsg{Path: path, Err: exec.Command("qlmanage", "-p", path).Run()}
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadTextPreview(t *testing.T) {
+	dir := t.TempDir()
+	text := filepath.Join(dir, "notes.txt")
+	var lines []string
+	for i := 0; i < 30; i++ {
+		lines = append(lines, "line")
+	}
+	if err := os.WriteFile(text, []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	preview, ok := readTextPreview(text, 20)
+	if !ok || len(preview.Lines) != 20 || !preview.Truncated {
+		t.Fatalf("text preview = %+v, %v", preview, ok)
+	}
+
+	binary := filepath.Join(dir, "blob.bin")
+	if err := os.WriteFile(binary, []byte{0x7f, 'E', 'L', 'F', 0, 1}, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := readTextPreview(binary, 20); ok {
+		t.Error("binary files should go to Quick Look")
+	}
+	large := filepath.Join(dir, "big.log")
+	writeFileWithSize(t, large, inlinePreviewMaxSize+1)
+	if _, ok := readTextPreview(large, 20); ok {
+		t.Error("large files should go to Quick Look")
+	}
+}
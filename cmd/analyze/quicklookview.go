@@ -0,0 +1,58 @@
+//go:build darwin
+
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// openQuickLook previews the file under the cursor: small text files right here, anything
+// else in a Quick Look window.
+func (m model) openQuickLook() (tea.Model, tea.Cmd) {
+	entry, ok := m.selectedEntry()
+	if !ok || entry.IsDir {
+		m.status = "Select a file to preview"
+		return m, nil
+	}
+	if preview, ok := readTextPreview(entry.Path, inlinePreviewLines); ok {
+		m.preview = &preview
+		return m, nil
+	}
+	m.status = fmt.Sprintf("Previewing %s...", displayName(entry.Path, filepath.Base(entry.Path)))
+	return m, quickLookCmd(entry.Path)
+}
+
+// updatePreviewKey opens Quick Look on Enter or ctrl+y; any other key closes the preview.
+func (m model) updatePreviewKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	path := m.preview.Path
+	m.preview = nil
+	switch msg.String() {
+	case "ctrl+c":
+		return m.quit()
+	case "enter", "ctrl+y":
+		m.status = fmt.Sprintf("Previewing %s...", displayName(path, filepath.Base(path)))
+		return m, quickLookCmd(path)
+	}
+	return m, nil
+}
+
+// renderPreview shows the start of a text file.
+func (m model) renderPreview() string {
+	p := m.preview
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s%s%s %s(%s)%s\n\n", colorCyan, displayPath(p.Path), colorReset, colorGray, humanizeBytes(p.Size), colorReset)
+	width := max(m.width-4, 20)
+	for _, line := range p.Lines {
+		fmt.Fprintf(&b, "  %s\n", trimNameWithWidth(sanitizeName(strings.ReplaceAll(line, "\t", "    ")), width))
+	}
+	if p.Truncated {
+		fmt.Fprintf(&b, "  %s...%s\n", colorGray, colorReset)
+	}
+	fmt.Fprintln(&b)
+	fmt.Fprintf(&b, "%sEnter/^Y Quick Look | Any key Close%s\n", colorGray, colorReset)
+	return b.String()
+}
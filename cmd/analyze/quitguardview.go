@@ -0,0 +1,185 @@
+//go:build darwin
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// quitMode is what the quit guard does once it has been answered.
+type quitMode int
+
+const (
+	quitAsk    quitMode = iota // Listing running operations, waiting for a choice
+	quitWait                   // Quit once everything has finished
+	quitCancel                 // Batches stop before their next item, then quit
+	quitDetach                 // Batches stop and a helper finishes their remaining items
+)
+
+// quitGuard holds quitting until running operations finish, stop or move to a helper.
+type quitGuard struct {
+	Mode    quitMode
+	Handoff handoff // Items stopped batches left for the helper
+}
+
+// pendingOps describes the background operations quitting now would abandon.
+func (m model) pendingOps() []string {
+	var ops []string
+	if m.deleting {
+		switch {
+		case m.deleteBatch == nil:
+			ops = append(ops, "Emptying a volume's Trash")
+		case m.deleteBatch.Permanent:
+			current, _ := m.deleteBatch.position()
+			ops = append(ops, fmt.Sprintf("Deleting item %d of %d", current, m.deleteBatch.Total))
+		default:
+			current, _ := m.deleteBatch.position()
+			ops = append(ops, fmt.Sprintf("Moving item %d of %d to the Trash", current, m.deleteBatch.Total))
+		}
+	}
+	if m.organize != nil && m.organize.Moving != nil {
+		ops = append(ops, fmt.Sprintf("Moving files by rule, %s moved so far", formatNumber(atomic.LoadInt64(m.organize.Moving))))
+	}
+	if m.compress != nil && m.compress.Running {
+		ops = append(ops, "Compressing "+sanitizeName(m.compress.Entry.Name))
+	}
+	if m.relocate != nil && m.relocate.Running {
+		ops = append(ops, fmt.Sprintf("Moving %d items to %s", len(m.relocate.Paths), displayPath(m.relocate.Targets[m.relocate.Selected].Path)))
+	}
+	if m.evicting {
+		ops = append(ops, "Evicting iCloud Drive items")
+	}
+	if m.cleanPreview != nil && m.cleanPreview.Cleaning {
+		ops = append(ops, "Running mo clean")
+	}
+	if m.homebrew != nil && m.homebrew.Cleaning {
+		ops = append(ops, "Running brew cleanup")
+	}
+	if m.packageCaches != nil && m.packageCaches.Cleaning {
+		ops = append(ops, "Cleaning a package cache")
+	}
+	if m.exporting > 0 {
+		ops = append(ops, fmt.Sprintf("Writing %d exports", m.exporting))
+	}
+	return ops
+}
+
+// quit exits right away when nothing is running, and otherwise opens the guard.
+func (m model) quit() (tea.Model, tea.Cmd) {
+	if len(m.pendingOps()) == 0 {
+		return m, tea.Quit
+	}
+	m.quitGuard = &quitGuard{}
+	return m, nil
+}
+
+// updateQuitGuardKey waits on w, cancels on c, detaches on d and returns on Esc.
+// ctrl+c still quits at once.
+func (m model) updateQuitGuardKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	g := m.quitGuard
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "n":
+		if g.Mode == quitAsk || g.Mode == quitWait {
+			m.quitGuard = nil
+			m.status = "Ready"
+		}
+		return m, nil
+	}
+	if g.Mode != quitAsk {
+		return m, nil
+	}
+	switch msg.String() {
+	case "w", "enter":
+		g.Mode = quitWait
+		m.status = "Quitting when running operations finish..."
+	case "c":
+		g.Mode = quitCancel
+		stopBatches.Store(true)
+		m.status = "Stopping deletes and moves after their current item..."
+	case "d":
+		g.Mode = quitDetach
+		stopBatches.Store(true)
+		m.status = "Handing remaining items to a background helper..."
+	default:
+		return m, nil
+	}
+	return m, tickCmd()
+}
+
+// holdDeletes keeps the items a stopped delete did not reach for the helper.
+func (g *quitGuard) holdDeletes(msg deleteProgressMsg) {
+	if g == nil || g.Mode != quitDetach {
+		return
+	}
+	if msg.trashed {
+		g.Handoff.Trash = append(g.Handoff.Trash, msg.skipped...)
+	} else {
+		g.Handoff.Delete = append(g.Handoff.Delete, msg.skipped...)
+	}
+}
+
+// holdMoves keeps the items a stopped move batch did not reach for the helper.
+func (g *quitGuard) holdMoves(skipped []movePlanItem) {
+	if g != nil && g.Mode == quitDetach {
+		g.Handoff.Moves = append(g.Handoff.Moves, skipped...)
+	}
+}
+
+// holdRelocations keeps the items a stopped relocation did not reach, so the helper
+// copies and checks them instead of moving them with mv.
+func (g *quitGuard) holdRelocations(skipped []movePlanItem) {
+	if g != nil && g.Mode == quitDetach {
+		g.Handoff.Relocate = append(g.Handoff.Relocate, skipped...)
+	}
+}
+
+// tickQuitGuard quits once nothing is running, starting the helper first when detaching.
+func (m model) tickQuitGuard() (tea.Model, tea.Cmd) {
+	if len(m.pendingOps()) > 0 {
+		m.spinner = (m.spinner + 1) % len(spinnerFrames)
+		return m, tickCmd()
+	}
+	g := m.quitGuard
+	if g.Mode == quitDetach && !g.Handoff.empty() {
+		file, err := writeHandoff(g.Handoff)
+		if err == nil {
+			err = startHandoffHelper(file)
+		}
+		if err != nil {
+			// Nothing was lost: the stopped items are still on disk, so stay open.
+			stopBatches.Store(false)
+			m.quitGuard = nil
+			m.status = fmt.Sprintf("Could not start the helper (%v), %d items were left as they were", err, g.Handoff.count())
+			return m, nil
+		}
+	}
+	return m, tea.Quit
+}
+
+// renderQuitGuard lists what is still running and the ways to quit.
+func (m model) renderQuitGuard() string {
+	var b strings.Builder
+	g := m.quitGuard
+	ops := m.pendingOps()
+	fmt.Fprintf(&b, "%sStill running:%s\n", colorYellow, colorReset)
+	for _, op := range ops {
+		fmt.Fprintf(&b, "  %s %s\n", spinnerFrames[m.spinner], op)
+	}
+	switch g.Mode {
+	case quitAsk:
+		fmt.Fprintf(&b, "%sw wait and quit  |  c cancel all  |  d finish in background  |  ESC keep working%s\n", colorGray, colorReset)
+	case quitWait:
+		fmt.Fprintf(&b, "%sQuitting when these finish  |  ESC keep working  |  ctrl+c quit now%s\n", colorGray, colorReset)
+	case quitCancel:
+		fmt.Fprintf(&b, "%sStopping after the current items, cleanups, compression and exports run to the end%s\n", colorGray, colorReset)
+	case quitDetach:
+		fmt.Fprintf(&b, "%sStopping here, a background helper will finish the rest%s\n", colorGray, colorReset)
+	}
+	return b.String()
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"math"
+	"sync/atomic"
+)
+
+// defaultItemWeight is what one file or folder adds to an entry's weighted rank:
+// 100,000 small files rank like 25 GB, roughly what they cost backups and Spotlight.
+const defaultItemWeight = 256 << 10
+
+var (
+	// weightedRanking makes the scanner keep the top entries by weighted rank instead
+	// of by size; it follows the Weighted sort of the listing.
+	weightedRanking atomic.Bool
+	itemWeight      atomic.Int64
+)
+
+func init() {
+	itemWeight.Store(defaultItemWeight)
+}
+
+// weightedRank scores an entry by its size plus a fixed weight per file and folder
+// beneath it, so folders of many small files rank with folders of a few large ones.
+// Entries sized by du have no counts and rank by size alone.
+func weightedRank(entry dirEntry) int64 {
+	items := max(entry.Files, 0) + max(entry.Dirs, 0)
+	weight := itemWeight.Load()
+	if items == 0 || weight <= 0 {
+		return entry.Size
+	}
+	if items > (math.MaxInt64-max(entry.Size, 0))/weight {
+		return math.MaxInt64
+	}
+	return entry.Size + items*weight
+}
+
+func sizeRank(entry dirEntry) int64 { return entry.Size }
+
+// rankScorer is what the scanner ranks entries by when it keeps only the top ones.
+func rankScorer() func(dirEntry) int64 {
+	if weightedRanking.Load() {
+		return weightedRank
+	}
+	return sizeRank
+}
+
+// rankingSort is the listing order matching rankScorer, used to cap an uncapped listing.
+func rankingSort() entrySortMode {
+	if weightedRanking.Load() {
+		return entrySortWeighted
+	}
+	return entrySortSize
+}
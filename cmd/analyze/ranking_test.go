@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"path/filepath"
+	"testing"
+)
+
+func TestWeightedRank(t *testing.T) {
+	if got := weightedRank(dirEntry{Size: 100, Files: 3, Dirs: 1}); got != 100+4*defaultItemWeight {
+		t.Errorf("weightedRank = %d", got)
+	}
+	if got := weightedRank(dirEntry{Size: 100, Files: -1, Dirs: -1}); got != 100 {
+		t.Errorf("du-sized entry ranked %d, want its size", got)
+	}
+	if got := weightedRank(dirEntry{Size: 1, Files: math.MaxInt64 / 2}); got != math.MaxInt64 {
+		t.Errorf("overflow not saturated: %d", got)
+	}
+}
+
+func TestWeightedRankingKeepsManyFileFolders(t *testing.T) {
+	defer weightedRanking.Store(false)
+	defer func(limit int) { maxEntries = limit }(maxEntries)
+	maxEntries = 3
+
+	root := t.TempDir()
+	for i := 0; i < 3; i++ {
+		writeFileWithSize(t, filepath.Join(root, fmt.Sprintf("big%d.bin", i)), 2<<20)
+	}
+	for i := 0; i < 50; i++ {
+		writeFileWithSize(t, filepath.Join(root, "sessions", fmt.Sprintf("s%03d", i)), 1)
+	}
+
+	scan := func() map[string]bool {
+		var files, dirs, bytes int64
+		var current string
+		result, err := scanPathConcurrent(context.Background(), root, &files, &dirs, &bytes, &current, nil)
+		if err != nil {
+			t.Fatalf("scanPathConcurrent: %v", err)
+		}
+		kept := make(map[string]bool)
+		for _, entry := range result.Entries {
+			kept[entry.Name] = true
+		}
+		return kept
+	}
+	if scan()["sessions"] {
+		t.Fatal("size ranking kept the small many-file folder")
+	}
+	weightedRanking.Store(true)
+	if !scan()["sessions"] {
+		t.Fatal("weighted ranking left out the many-file folder")
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// defaultRecentFileAge is how new a file is while it still counts as active work.
+const defaultRecentFileAge = 24 * time.Hour
+
+var (
+	// recentFileAge is the window hideRecentFiles applies, set with hide_recent_files.
+	recentFileAge atomic.Int64
+	// hideRecentFiles leaves files created within recentFileAge out of the large files
+	// and stale data views. N toggles it.
+	hideRecentFiles atomic.Bool
+)
+
+func init() {
+	recentFileAge.Store(int64(defaultRecentFileAge))
+}
+
+// isRecentFile reports whether a file was created within the recent window, judged by
+// its modification time where the birth time is unknown.
+func isRecentFile(created, modified, now time.Time) bool {
+	if created.IsZero() {
+		created = modified
+	}
+	return now.Sub(created) < time.Duration(recentFileAge.Load())
+}
+
+// hiddenAsRecent reports whether the recent files filter hides a file right now.
+func hiddenAsRecent(created, modified time.Time) bool {
+	return hideRecentFiles.Load() && isRecentFile(created, modified, time.Now())
+}
+
+// recentFilesTag names the filter in headers while it is on.
+func recentFilesTag() string {
+	if !hideRecentFiles.Load() {
+		return ""
+	}
+	return fmt.Sprintf("  %s[Hiding files from the last %s]%s", colorGray, formatRecentAge(time.Duration(recentFileAge.Load())), colorReset)
+}
+
+// recentFilesLabel names the filter's state in footers.
+func recentFilesLabel() string {
+	if hideRecentFiles.Load() {
+		return "hidden"
+	}
+	return "shown"
+}
+
+// formatRecentAge prints the window as "24 hours" or "3 days".
+func formatRecentAge(age time.Duration) string {
+	if age > 24*time.Hour && age%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%d days", age/(24*time.Hour))
+	}
+	return fmt.Sprintf("%g hours", age.Hours())
+}
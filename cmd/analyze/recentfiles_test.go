@@ -0,0 +1,50 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIsRecentFilePrefersBirthTime(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	old := now.AddDate(-1, 0, 0)
+	if !isRecentFile(now.Add(-time.Hour), old, now) {
+		t.Error("a file created an hour ago with an old modification date should be recent")
+	}
+	if isRecentFile(time.Time{}, old, now) {
+		t.Error("an old file should not be recent")
+	}
+	if !isRecentFile(time.Time{}, now.Add(-2*time.Hour), now) {
+		t.Error("without a birth time the modification time should decide")
+	}
+}
+
+func TestFilterLargeFilesHidesRecentFiles(t *testing.T) {
+	defer hideRecentFiles.Store(false)
+	files := []fileEntry{
+		{Path: "/work/render.mov", Size: 2 << 30, Created: time.Now().Add(-time.Hour)},
+		{Path: "/old/backup.zip", Size: 1 << 30, ModTime: time.Now().AddDate(0, -6, 0)},
+	}
+	if kept := filterLargeFiles(files, 1<<20); len(kept) != 2 {
+		t.Fatalf("filter off kept %d files, want 2", len(kept))
+	}
+	hideRecentFiles.Store(true)
+	if kept := filterLargeFiles(files, 1<<20); len(kept) != 1 || kept[0].Path != "/old/backup.zip" {
+		t.Fatalf("filter on kept %+v", kept)
+	}
+}
+
+func TestHideRecentFilesConfig(t *testing.T) {
+	for age, want := range map[string]time.Duration{"24h": 24 * time.Hour, "3d": 72 * time.Hour, "off": 0} {
+		cfg, err := decodeUserConfig(map[string]configValue{"hide_recent_files": age})
+		if err != nil || cfg.HideRecentFiles != want {
+			t.Errorf("hide_recent_files = %q: got %v, %v", age, cfg.HideRecentFiles, err)
+		}
+	}
+	if _, err := decodeUserConfig(map[string]configValue{"hide_recent_files": "soon"}); err == nil {
+		t.Error("expected an error for an unparsable age")
+	}
+	if got := formatRecentAge(24 * time.Hour); got != "24 hours" {
+		t.Errorf("formatRecentAge(24h) = %q", got)
+	}
+}
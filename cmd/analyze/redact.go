@@ -0,0 +1,111 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const (
+	redactStyleHash     = "hash"
+	redactStyleTruncate = "truncate"
+)
+
+// redactor hides names deeper than depth while keeping sizes and tree shape intact.
+type redactor struct {
+	depth int
+	style string
+	salt  []byte
+
+	mu    sync.Mutex
+	names map[string]string
+}
+
+// activeRedactor is set from --redact; nil shows real names.
+var activeRedactor *redactor
+
+func newRedactor(depth int, style string) *redactor {
+	salt := make([]byte, 16)
+	_, _ = rand.Read(salt)
+	if style != redactStyleTruncate {
+		style = redactStyleHash
+	}
+	return &redactor{depth: depth, style: style, salt: salt, names: make(map[string]string)}
+}
+
+// name masks one component; the same input maps to the same output within a run.
+func (r *redactor) name(component string) string {
+	if component == "" || component == "~" {
+		return component
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if masked, ok := r.names[component]; ok {
+		return masked
+	}
+
+	ext := strings.ToLower(filepath.Ext(component))
+	if len(ext) > 6 || ext == component {
+		ext = ""
+	}
+	var masked string
+	if r.style == redactStyleTruncate {
+		runes := []rune(strings.TrimSuffix(component, filepath.Ext(component)))
+		if len(runes) == 0 {
+			runes = []rune(component)
+		}
+		masked = string(runes[0]) + "…" + ext
+	} else {
+		sum := sha256.Sum256(append(append([]byte{}, r.salt...), component...))
+		masked = hex.EncodeToString(sum[:4]) + ext
+	}
+	r.names[component] = masked
+	return masked
+}
+
+// redactBelow masks every component of rel past the first depth levels.
+func (r *redactor) redactBelow(rel string) string {
+	parts := strings.Split(rel, "/")
+	for i := r.depth; i < len(parts); i++ {
+		parts[i] = r.name(parts[i])
+	}
+	return strings.Join(parts, "/")
+}
+
+// redactDisplay masks a displayPath result, counting depth from ~ or /.
+func (r *redactor) redactDisplay(shown string) string {
+	switch {
+	case shown == "~" || shown == "/":
+		return shown
+	case strings.HasPrefix(shown, "~/"):
+		return "~/" + r.redactBelow(strings.TrimPrefix(shown, "~/"))
+	case strings.HasPrefix(shown, "/"):
+		return "/" + r.redactBelow(strings.TrimPrefix(shown, "/"))
+	}
+	return shown
+}
+
+// redactUnder masks a path for exports, counting depth from the exported root.
+func (r *redactor) redactUnder(root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return path
+	}
+	return filepath.Join(root, r.redactBelow(rel))
+}
+
+// displayName returns the on-screen name for an entry at path; labels above the depth stay as-is.
+func displayName(path, name string) string {
+	if activeRedactor == nil || path == "" {
+		return sanitizeName(name)
+	}
+	shown := homeRelativePath(path)
+	masked := activeRedactor.redactDisplay(shown)
+	if masked == shown {
+		return sanitizeName(name)
+	}
+	return filepath.Base(masked)
+}
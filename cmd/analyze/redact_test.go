@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func withRedactor(t *testing.T, depth int, style string) *redactor {
+	t.Helper()
+	prev := activeRedactor
+	activeRedactor = newRedactor(depth, style)
+	t.Cleanup(func() { activeRedactor = prev })
+	return activeRedactor
+}
+
+func TestDisplayPathRedactsBelowDepth(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	r := withRedactor(t, 1, redactStyleHash)
+
+	shown := displayPath(filepath.Join(home, "Documents", "Taxes 2025", "return.pdf"))
+	parts := strings.Split(shown, "/")
+	if len(parts) != 4 || parts[0] != "~" || parts[1] != "Documents" {
+		t.Fatalf("expected depth-1 folders kept, got %q", shown)
+	}
+	if strings.Contains(shown, "Taxes") || strings.Contains(shown, "return") {
+		t.Fatalf("expected personal names hidden, got %q", shown)
+	}
+	if !strings.HasSuffix(parts[3], ".pdf") {
+		t.Fatalf("expected extension kept for context, got %q", parts[3])
+	}
+	if r.name("Taxes 2025") != parts[2] {
+		t.Fatalf("expected stable masking within a run")
+	}
+}
+
+func TestDisplayNameKeepsLabelsAboveDepth(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	withRedactor(t, 1, redactStyleTruncate)
+
+	if got := displayName(filepath.Join(home, "Library"), "Library"); got != "Library" {
+		t.Fatalf("expected top-level name kept, got %q", got)
+	}
+	if got := displayName(filepath.Join(home, "Library", "Secret.app"), "Secret.app"); got != "S….app" {
+		t.Fatalf("expected truncated name, got %q", got)
+	}
+	if got := displayName(filepath.Join(home, "Bad\nName"), "Bad\nName"); got != `Bad\nName` {
+		t.Fatalf("expected an unmasked name still escaped, got %q", got)
+	}
+}
+
+func TestWriteInventoryRedactsButPreservesSizes(t *testing.T) {
+	root := t.TempDir()
+	writeFileWithSize(t, filepath.Join(root, "alice-private", "diary.txt"), 2048)
+	withRedactor(t, 0, redactStyleHash)
+
+	var buf bytes.Buffer
+	if err := writeInventory(&buf, root); err != nil {
+		t.Fatalf("writeInventory: %v", err)
+	}
+	if strings.Contains(buf.String(), "alice") || strings.Contains(buf.String(), "diary") {
+		t.Fatalf("expected names redacted, got:\n%s", buf.String())
+	}
+
+	tree, err := readInventory(&buf, "test", root)
+	if err != nil {
+		t.Fatalf("readInventory: %v", err)
+	}
+	result, err := tree.scanResultFor(root)
+	if err != nil {
+		t.Fatalf("scanResultFor: %v", err)
+	}
+	if len(result.Entries) != 1 || !result.Entries[0].IsDir {
+		t.Fatalf("expected one redacted directory, got %+v", result.Entries)
+	}
+	if result.TotalSize < 2048 {
+		t.Fatalf("expected size preserved, got %d", result.TotalSize)
+	}
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxReinstallLookups bounds the brew queries made for one confirmation.
+const maxReinstallLookups = 5
+
+var (
+	// homebrewCaskrooms hold one folder per installed cask on Apple silicon and Intel.
+	homebrewCaskrooms = []string{"/opt/homebrew/Caskroom", "/usr/local/Caskroom"}
+
+	masListPattern   = regexp.MustCompile(`^\s*(\d+)\s+(.+?)\s+\(.*\)\s*$`)
+	caskTokenUnsafe  = regexp.MustCompile(`[^a-z0-9+.-]+`)
+	caskTokenDashRun = regexp.MustCompile(`-{2,}`)
+)
+
+// reinstallHintsMsg maps delete candidates to how they can be installed again.
+type reinstallHintsMsg struct {
+	Hints map[string]string
+}
+
+// reinstallSources is what is known about installed apps, gathered once per confirmation.
+type reinstallSources struct {
+	MasApps   map[string]string // Lowercased app name to App Store id
+	Caskrooms []string
+	// CaskExists reports whether brew knows a cask token; nil skips the lookup.
+	CaskExists func(token string) bool
+}
+
+// isReinstallCandidate reports whether a path is an app bundle or an installer.
+func isReinstallCandidate(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".app" || installerExtensions[ext]
+}
+
+// reinstallAppName is the product name behind an app bundle or versioned installer.
+func reinstallAppName(path string) string {
+	base := filepath.Base(path)
+	if strings.EqualFold(filepath.Ext(base), ".app") {
+		return strings.TrimSuffix(base, filepath.Ext(base))
+	}
+	if _, display, _, ok := parseVersionedName(base); ok {
+		return display
+	}
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// caskToken guesses the Homebrew cask token for an app name ("Visual Studio Code" -> "visual-studio-code").
+func caskToken(name string) string {
+	token := strings.ToLower(strings.TrimSpace(name))
+	token = strings.NewReplacer(" ", "-", "_", "-").Replace(token)
+	token = caskTokenUnsafe.ReplaceAllString(token, "")
+	token = caskTokenDashRun.ReplaceAllString(token, "-")
+	return strings.Trim(token, "-.")
+}
+
+// parseMasList reads `mas list` output ("497799835  Xcode  (15.2)") into name -> id.
+func parseMasList(output string) map[string]string {
+	apps := make(map[string]string)
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		if match := masListPattern.FindStringSubmatch(scanner.Text()); match != nil {
+			apps[strings.ToLower(strings.TrimSpace(match[2]))] = match[1]
+		}
+	}
+	return apps
+}
+
+// reinstallHint describes how path could be installed again, or "" when unknown.
+// The App Store wins over Homebrew since it restores the purchased copy.
+func (s reinstallSources) reinstallHint(path string, lookups *int) string {
+	name := reinstallAppName(path)
+	if id, ok := s.MasApps[strings.ToLower(name)]; ok {
+		return "reinstallable via mas install " + id
+	}
+	if strings.EqualFold(filepath.Ext(path), ".app") {
+		if _, err := os.Stat(filepath.Join(path, "Contents", "_MASReceipt", "receipt")); err == nil {
+			return "reinstallable from the App Store"
+		}
+	}
+	token := caskToken(name)
+	if token == "" {
+		return ""
+	}
+	for _, room := range s.Caskrooms {
+		if info, err := os.Stat(filepath.Join(room, token)); err == nil && info.IsDir() {
+			return "reinstallable via brew install --cask " + token
+		}
+	}
+	if s.CaskExists != nil && *lookups < maxReinstallLookups {
+		*lookups++
+		if s.CaskExists(token) {
+			return "reinstallable via brew install --cask " + token
+		}
+	}
+	return ""
+}
+
+// reinstallHints annotates every app or installer among paths.
+func (s reinstallSources) reinstallHints(paths []string) map[string]string {
+	hints := make(map[string]string)
+	lookups := 0
+	for _, path := range paths {
+		if !isReinstallCandidate(path) {
+			continue
+		}
+		if hint := s.reinstallHint(path, &lookups); hint != "" {
+			hints[path] = hint
+		}
+	}
+	return hints
+}
+
+// localReinstallSources asks mas and brew, when installed, what they can restore.
+func localReinstallSources() reinstallSources {
+	sources := reinstallSources{MasApps: map[string]string{}, Caskrooms: homebrewCaskrooms}
+	if masPath, err := exec.LookPath("mas"); err == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), mdlsTimeout)
+		defer cancel()
+		if out, err := exec.CommandContext(ctx, masPath, "list").Output(); err == nil {
+			sources.MasApps = parseMasList(string(out))
+		}
+	}
+	if brewPath, err := exec.LookPath("brew"); err == nil {
+		sources.CaskExists = func(token string) bool {
+			ctx, cancel := context.WithTimeout(context.Background(), mdlsTimeout)
+			defer cancel()
+			cmd := exec.CommandContext(ctx, brewPath, "info", "--cask", "--json=v2", token)
+			cmd.Env = append(os.Environ(), "HOMEBREW_NO_AUTO_UPDATE=1", "HOMEBREW_NO_ANALYTICS=1")
+			return cmd.Run() == nil
+		}
+	}
+	return sources
+}
+
+// reinstallHintsCmd looks up reinstall sources in the background while the user confirms.
+func reinstallHintsCmd(paths []string) tea.Cmd {
+	var candidates []string
+	for _, path := range paths {
+		if isReinstallCandidate(path) {
+			candidates = append(candidates, path)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		return reinstallHintsMsg{Hints: localReinstallSources().reinstallHints(candidates)}
+	}
+}
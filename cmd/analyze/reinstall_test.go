@@ -0,0 +1,79 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCaskToken(t *testing.T) {
+	tests := map[string]string{
+		"Visual Studio Code": "visual-studio-code",
+		"Docker":             "docker",
+		"Google Chrome.":     "google-chrome",
+		"Foo_Bar (Beta)":     "foo-bar-beta",
+	}
+	for name, want := range tests {
+		if got := caskToken(name); got != want {
+			t.Errorf("caskToken(%q) = %q; want %q", name, got, want)
+		}
+	}
+}
+
+func TestParseMasList(t *testing.T) {
+	apps := parseMasList("497799835  Xcode          (15.2)\n409183694 Keynote (14.0)\nNo installed apps\n")
+	if apps["xcode"] != "497799835" || apps["keynote"] != "409183694" || len(apps) != 2 {
+		t.Fatalf("unexpected mas apps: %v", apps)
+	}
+}
+
+func TestReinstallHints(t *testing.T) {
+	dir := t.TempDir()
+	caskroom := filepath.Join(dir, "Caskroom")
+	if err := os.MkdirAll(filepath.Join(caskroom, "visual-studio-code"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	storeApp := filepath.Join(dir, "Pages.app")
+	if err := os.MkdirAll(filepath.Join(storeApp, "Contents", "_MASReceipt"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(storeApp, "Contents", "_MASReceipt", "receipt"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var queried []string
+	sources := reinstallSources{
+		MasApps:   map[string]string{"xcode": "497799835"},
+		Caskrooms: []string{caskroom},
+		CaskExists: func(token string) bool {
+			queried = append(queried, token)
+			return token == "docker"
+		},
+	}
+	hints := sources.reinstallHints([]string{
+		"/Applications/Xcode.app",
+		"/Applications/Visual Studio Code.app",
+		storeApp,
+		"/Users/me/Downloads/Docker-4.30.0.dmg",
+		"/Users/me/Downloads/Unknown-1.0.pkg",
+		"/Users/me/notes.txt",
+	})
+
+	want := map[string]string{
+		"/Applications/Xcode.app":               "reinstallable via mas install 497799835",
+		"/Applications/Visual Studio Code.app":  "reinstallable via brew install --cask visual-studio-code",
+		storeApp:                                "reinstallable from the App Store",
+		"/Users/me/Downloads/Docker-4.30.0.dmg": "reinstallable via brew install --cask docker",
+	}
+	if len(hints) != len(want) {
+		t.Fatalf("unexpected hints: %v", hints)
+	}
+	for path, hint := range want {
+		if hints[path] != hint {
+			t.Errorf("hint for %s = %q; want %q", path, hints[path], hint)
+		}
+	}
+	if len(queried) != 2 {
+		t.Fatalf("brew should only be asked about unresolved candidates, asked %v", queried)
+	}
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ageUnit is the unit an "unused" hint counts in; ageUnitAuto picks the largest that fits.
+type ageUnit int
+
+const (
+	ageUnitAuto ageUnit = iota
+	ageUnitDays
+	ageUnitWeeks
+	ageUnitMonths
+	ageUnitYears
+)
+
+var ageUnitNames = []string{"auto", "days", "weeks", "months", "years"}
+
+// daysPerAgeUnit is how many days one of each unit spans, matching parseSearchAge.
+var daysPerAgeUnit = map[ageUnit]int{ageUnitDays: 1, ageUnitWeeks: 7, ageUnitMonths: 30, ageUnitYears: 365}
+
+func parseAgeUnit(name string) (ageUnit, bool) {
+	for i, known := range ageUnitNames {
+		if strings.EqualFold(name, known) {
+			return ageUnit(i), true
+		}
+	}
+	return ageUnitAuto, false
+}
+
+// unusedHintSettings decide when the "not opened in a while" hint appears beside a row
+// and how it reads. Hints wait for at least one whole unit, so years-only hints skip
+// anything opened within the last year.
+type unusedHintSettings struct {
+	After time.Duration // Age before a hint shows, negative to never show
+	Unit  ageUnit
+	Long  bool // "unused 2 years" instead of ">2yr"
+}
+
+var unusedHints = unusedHintSettings{After: 90 * 24 * time.Hour}
+
+// relativeWords are one language's unit names, indexed days, weeks, months, years. Short
+// suffixes follow a ">" and the count; the long form fills its format with count and word.
+type relativeWords struct {
+	Short    [4]string
+	Singular [4]string
+	Plural   [4]string
+	Long     string
+}
+
+// relativeTimeWords is keyed by base language; anything missing falls back to English.
+var relativeTimeWords = map[string]relativeWords{
+	"en": {
+		Short:    [4]string{"d", "wk", "mo", "yr"},
+		Singular: [4]string{"day", "week", "month", "year"},
+		Plural:   [4]string{"days", "weeks", "months", "years"},
+		Long:     "unused %s %s",
+	},
+	"de": {
+		Short:    [4]string{"T", "Wo", "Mon", "J"},
+		Singular: [4]string{"Tag", "Woche", "Monat", "Jahr"},
+		Plural:   [4]string{"Tagen", "Wochen", "Monaten", "Jahren"},
+		Long:     "seit %s %s ungenutzt",
+	},
+	"fr": {
+		Short:    [4]string{"j", "sem", "mois", "an"},
+		Singular: [4]string{"jour", "semaine", "mois", "an"},
+		Plural:   [4]string{"jours", "semaines", "mois", "ans"},
+		Long:     "inutilisé depuis %s %s",
+	},
+	"es": {
+		Short:    [4]string{"d", "sem", "m", "a"},
+		Singular: [4]string{"día", "semana", "mes", "año"},
+		Plural:   [4]string{"días", "semanas", "meses", "años"},
+		Long:     "sin usar desde hace %s %s",
+	},
+	"ja": {
+		Short:    [4]string{"日", "週", "か月", "年"},
+		Singular: [4]string{"日", "週間", "か月", "年"},
+		Plural:   [4]string{"日", "週間", "か月", "年"},
+		Long:     "%s%s未使用",
+	},
+	"zh": {
+		Short:    [4]string{"天", "周", "月", "年"},
+		Singular: [4]string{"天", "周", "个月", "年"},
+		Plural:   [4]string{"天", "周", "个月", "年"},
+		Long:     "%s%s未使用",
+	},
+}
+
+var (
+	messageLocaleMu sync.RWMutex
+	messageWords    = relativeTimeWords["en"]
+)
+
+func init() {
+	setMessageLocale(detectMessageLocale())
+}
+
+// detectMessageLocale picks the language for words, which may differ from LC_NUMERIC.
+func detectMessageLocale() string {
+	for _, key := range []string{localeEnvVar, "LC_ALL", "LC_MESSAGES", "LANG"} {
+		if value := strings.TrimSpace(os.Getenv(key)); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func setMessageLocale(name string) {
+	base, _ := parseLocaleTag(name).Base()
+	words, ok := relativeTimeWords[base.String()]
+	if !ok {
+		words = relativeTimeWords["en"]
+	}
+	messageLocaleMu.Lock()
+	messageWords = words
+	messageLocaleMu.Unlock()
+}
+
+// currentMessageWords are the unit names for the message locale.
+func currentMessageWords() relativeWords {
+	messageLocaleMu.RLock()
+	defer messageLocaleMu.RUnlock()
+	return messageWords
+}
+
+// formatRelativeAge renders age in the given unit, or "" when it is under one unit.
+func formatRelativeAge(age time.Duration, unit ageUnit, long bool, words relativeWords) string {
+	days := int(age.Hours() / 24)
+	if unit == ageUnitAuto {
+		unit = ageUnitDays
+		for _, candidate := range []ageUnit{ageUnitYears, ageUnitMonths, ageUnitWeeks} {
+			if days >= daysPerAgeUnit[candidate] {
+				unit = candidate
+				break
+			}
+		}
+	}
+	count := days / daysPerAgeUnit[unit]
+	if count < 1 {
+		return ""
+	}
+	i := int(unit) - 1
+	if !long {
+		return fmt.Sprintf(">%s%s", formatGrouped(int64(count)), words.Short[i])
+	}
+	word := words.Plural[i]
+	if count == 1 {
+		word = words.Singular[i]
+	}
+	return fmt.Sprintf(words.Long, formatGrouped(int64(count)), word)
+}
+
+// formatUnusedTime labels how long ago lastAccess was, once that passes the configured threshold.
+func formatUnusedTime(lastAccess time.Time) string {
+	if lastAccess.IsZero() || unusedHints.After < 0 {
+		return ""
+	}
+	age := time.Since(lastAccess)
+	if age < unusedHints.After {
+		return ""
+	}
+	return formatRelativeAge(age, unusedHints.Unit, unusedHints.Long, currentMessageWords())
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// relocateCopySuffix marks a copy still being written or checked on the target volume.
+const relocateCopySuffix = ".mole-copying"
+
+// relocateTarget is a mounted volume the ">" action can move items to.
+type relocateTarget struct {
+	Path string
+	Free int64
+}
+
+type relocateDoneMsg struct {
+	Volume   string
+	Moved    []movePlanItem
+	Failures []deleteFailure
+	Skipped  []movePlanItem // Not started because the batch was stopped
+}
+
+// relocateTargets lists the writable volumes other than the one holding source.
+func relocateTargets(source string) []relocateTarget {
+	sourceDev, err := deviceOf(source)
+	if err != nil {
+		return nil
+	}
+	var targets []relocateTarget
+	for _, volume := range checkedVolumes() {
+		dev, err := deviceOf(volume)
+		if err != nil || dev == sourceDev || syscall.Access(volume, 2) != nil {
+			continue
+		}
+		free, err := volumeAvailableBytes(volume)
+		if err != nil {
+			continue
+		}
+		targets = append(targets, relocateTarget{Path: volume, Free: free})
+	}
+	return targets
+}
+
+// planRelocation maps each path to the same name at the root of volume.
+func planRelocation(paths []string, volume string, sizes map[string]dirEntry) []movePlanItem {
+	items := make([]movePlanItem, 0, len(paths))
+	for _, path := range paths {
+		items = append(items, movePlanItem{From: path, To: filepath.Join(volume, filepath.Base(path)), Size: sizes[path].Size})
+	}
+	return items
+}
+
+// relocateCmd copies each item to its volume, checks the copy and only then removes
+// the original. counter follows the files copied.
+func relocateCmd(volume string, items []movePlanItem, counter *int64) tea.Cmd {
+	return func() tea.Msg {
+		msg := relocateDoneMsg{Volume: volume}
+		var failures deleteFailuresError
+		if err := cleanRunningError(); err != nil {
+			for _, item := range items {
+				failures.add(item.From, err)
+			}
+			msg.Failures = failures.failures
+			return msg
+		}
+		if release, err := acquireLock(analyzeLockFile); err == nil {
+			defer release()
+		}
+		var moved []string
+		for i, item := range items {
+			if stopBatches.Load() {
+				msg.Skipped = items[i:]
+				break
+			}
+			if err := relocateItem(item, counter); err != nil {
+				failures.add(item.From, err)
+				continue
+			}
+			appendLedger("SUCCESS", fmt.Sprintf("analyze moved %s to %s", item.From, item.To))
+			msg.Moved = append(msg.Moved, item)
+			moved = append(moved, item.From)
+		}
+		recordChanges(moved)
+		msg.Failures = failures.failures
+		return msg
+	}
+}
+
+// relocateItem copies with ditto, which keeps metadata, compares the copy with the
+// original and then deletes the original.
+func relocateItem(item movePlanItem, counter *int64) error {
+	if _, err := os.Lstat(item.To); err == nil {
+		return &os.PathError{Op: "move", Path: item.To, Err: os.ErrExist}
+	}
+	staging := item.To + relocateCopySuffix
+	_ = os.RemoveAll(staging)
+	if err := runCounting(counter, "ditto", "-V", item.From, staging); err != nil {
+		_ = os.RemoveAll(staging)
+		return err
+	}
+	if err := sameTree(item.From, staging); err != nil {
+		_ = os.RemoveAll(staging)
+		return err
+	}
+	if err := os.Rename(staging, item.To); err != nil {
+		_ = os.RemoveAll(staging)
+		return err
+	}
+	if _, err := deletePathWithProgress(item.From, nil); err != nil {
+		return fmt.Errorf("copied to %s, but the original could not be removed: %w", displayPath(item.To), err)
+	}
+	return nil
+}
+
+// sameTree checks that copy holds as many files and bytes as original.
+func sameTree(original, copy string) error {
+	files, bytes, err := treeTotals(original)
+	if err != nil {
+		return err
+	}
+	copyFiles, copyBytes, err := treeTotals(copy)
+	if err != nil {
+		return err
+	}
+	if files != copyFiles || bytes != copyBytes {
+		return fmt.Errorf("copy does not match: %d files, %s copied of %d files, %s",
+			copyFiles, humanizeBytes(copyBytes), files, humanizeBytes(bytes))
+	}
+	return nil
+}
+
+// treeTotals counts the entries under root and the logical bytes of its regular files.
+func treeTotals(root string) (int64, int64, error) {
+	var files, bytes int64
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		files++
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			bytes += info.Size()
+		}
+		return nil
+	})
+	return files, bytes, err
+}
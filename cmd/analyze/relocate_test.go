@@ -0,0 +1,67 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestSameTree(t *testing.T) {
+	base := t.TempDir()
+	original := filepath.Join(base, "original")
+	copy := filepath.Join(base, "copy")
+	for _, root := range []string{original, copy} {
+		if err := os.MkdirAll(filepath.Join(root, "sub"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(root, "sub", "data"), []byte("12345"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := sameTree(original, copy); err != nil {
+		t.Fatalf("identical trees: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(copy, "sub", "data"), []byte("123"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := sameTree(original, copy); err == nil {
+		t.Error("a short copy should fail the check")
+	}
+}
+
+func TestPlanRelocation(t *testing.T) {
+	sizes := map[string]dirEntry{"/Users/me/Movies/raw": {Size: 100}}
+	items := planRelocation([]string{"/Users/me/Movies/raw"}, "/Volumes/Archive", sizes)
+	if len(items) != 1 || items[0].To != "/Volumes/Archive/raw" || items[0].Size != 100 {
+		t.Fatalf("items = %+v", items)
+	}
+}
+
+func TestRelocateItem(t *testing.T) {
+	if _, err := exec.LookPath("ditto"); err != nil {
+		t.Skip("ditto is macOS only")
+	}
+	base := t.TempDir()
+	from := filepath.Join(base, "src", "project")
+	if err := os.MkdirAll(from, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(from, "file"), []byte("data"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	to := filepath.Join(base, "dest", "project")
+	if err := os.MkdirAll(filepath.Dir(to), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	var copied int64
+	if err := relocateItem(movePlanItem{From: from, To: to}, &copied); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(from); !os.IsNotExist(err) {
+		t.Errorf("original should be removed: %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(to, "file")); err != nil || string(data) != "data" {
+		t.Errorf("copy = %q, %v", data, err)
+	}
+}
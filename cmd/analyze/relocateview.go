@@ -0,0 +1,146 @@
+//go:build darwin
+
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// relocateView is the ">" volume picker, then the running move.
+type relocateView struct {
+	Paths    []string
+	Size     int64
+	Files    int64 // Files beneath the selection, 0 when unknown
+	Targets  []relocateTarget
+	Selected int
+	Running  bool
+	Done     *int64 // Files copied so far
+}
+
+// openRelocate lists the volumes the selection can move to.
+func (m model) openRelocate() (tea.Model, tea.Cmd) {
+	if m.inventory != nil {
+		m.status = fmt.Sprintf("%s is a read-only listing", m.inventory.Source)
+		return m, nil
+	}
+	if m.relocate != nil && m.relocate.Running {
+		m.status = "Already moving to another volume, wait for it to finish"
+		return m, nil
+	}
+	paths := m.actionTargets()
+	if len(paths) == 0 || m.inOverviewMode() {
+		m.status = "Select files or folders to move to another volume"
+		return m, nil
+	}
+	sizes := m.evictTargets()
+	v := &relocateView{Paths: paths}
+	for _, path := range paths {
+		if isSIPProtected(path) {
+			m.status = fmt.Sprintf("%s is protected by macOS", displayPath(path))
+			return m, nil
+		}
+		v.Size += sizes[path].Size
+		v.Files += max(sizes[path].Files, 0)
+	}
+	v.Targets = relocateTargets(paths[0])
+	if len(v.Targets) == 0 {
+		m.status = "No other writable volume is mounted"
+		return m, nil
+	}
+	m.relocate = v
+	return m, nil
+}
+
+// updateRelocateKey moves through the volumes and starts the move on Enter.
+func (m model) updateRelocateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	v := m.relocate
+	switch msg.String() {
+	case "up", "k":
+		v.Selected = max(v.Selected-1, 0)
+	case "down", "j":
+		v.Selected = min(v.Selected+1, len(v.Targets)-1)
+	case "enter", ">":
+		target := v.Targets[v.Selected]
+		if v.Size > target.Free {
+			m.status = fmt.Sprintf("%s has %s free, %s needed", displayPath(target.Path), humanizeBytes(target.Free), humanizeBytes(v.Size))
+			return m, nil
+		}
+		var done int64
+		v.Running, v.Done = true, &done
+		m.status = fmt.Sprintf("Moving %d items to %s...", len(v.Paths), displayPath(target.Path))
+		items := planRelocation(v.Paths, target.Path, m.evictTargets())
+		return m, tea.Batch(relocateCmd(target.Path, items, v.Done), tickCmd())
+	default:
+		m.relocate = nil
+		m.status = "Cancelled"
+	}
+	return m, nil
+}
+
+// finishRelocate reports the move and rescans the folder the items left.
+func (m model) finishRelocate(msg relocateDoneMsg) (tea.Model, tea.Cmd) {
+	m.relocate = nil
+	var moved int64
+	for _, item := range msg.Moved {
+		moved += item.Size
+		invalidateCache(filepath.Dir(item.From))
+	}
+	m.status = fmt.Sprintf("Moved %d items (%s) to %s", len(msg.Moved), humanizeBytes(moved), displayPath(msg.Volume))
+	m.deleteFailures = msg.Failures
+	if len(msg.Failures) > 0 {
+		m.status += fmt.Sprintf(", %d could not be moved", len(msg.Failures))
+	}
+	if len(msg.Moved) == 0 {
+		return m, nil
+	}
+	invalidateCache(msg.Volume)
+	for i := range m.history {
+		m.history[i].Dirty = true
+	}
+	m.multiSelected = make(map[string]bool)
+	m.largeMultiSelected = make(map[string]bool)
+	if m.inOverviewMode() || m.scanning {
+		return m, nil
+	}
+	m.scanning = true
+	atomic.StoreInt64(m.filesScanned, 0)
+	atomic.StoreInt64(m.dirsScanned, 0)
+	atomic.StoreInt64(m.bytesScanned, 0)
+	return m, tea.Batch(m.scanCmd(m.path), tickCmd())
+}
+
+// renderRelocate is the volume picker, or the running move's progress.
+func (m model) renderRelocate() string {
+	v := m.relocate
+	var b strings.Builder
+	if v.Running {
+		progress := formatNumber(atomic.LoadInt64(v.Done))
+		if v.Files > 0 {
+			progress += " of " + formatNumber(v.Files)
+		}
+		fmt.Fprintf(&b, "%s%s Moving:%s %d items (%s) to %s, %s files copied, originals go once checked\n",
+			colorYellow, spinnerFrames[m.spinner], colorReset, len(v.Paths), humanizeBytes(v.Size),
+			displayPath(v.Targets[v.Selected].Path), progress)
+		return b.String()
+	}
+	fmt.Fprintf(&b, "%sMove to:%s %d items, %s  %scopied, checked, then removed here%s\n",
+		colorYellow, colorReset, len(v.Paths), humanizeBytes(v.Size), colorGray, colorReset)
+	for i, target := range v.Targets {
+		prefix, color := "   ", ""
+		if i == v.Selected {
+			prefix, color = fmt.Sprintf(" %s%s▶%s ", colorCyan, colorBold, colorReset), colorCyan
+		}
+		note := ""
+		if v.Size > target.Free {
+			note = fmt.Sprintf("  %snot enough space%s", colorRed, colorReset)
+		}
+		fmt.Fprintf(&b, "%s%s%s%s  %s free%s\n", prefix, color, displayPath(target.Path), colorReset, humanizeBytes(target.Free), note)
+	}
+	fmt.Fprintf(&b, "%s↑↓ | Enter Move | ESC cancel%s\n", colorGray, colorReset)
+	return b.String()
+}
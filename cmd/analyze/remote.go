@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// parseSSHTarget splits "user@host:/path"; the path defaults to the remote home.
+func parseSSHTarget(target string) (host, path string, err error) {
+	host, path, found := strings.Cut(target, ":")
+	if host == "" {
+		return "", "", fmt.Errorf("invalid ssh target %q, expected user@host:/path", target)
+	}
+	// ssh would read a leading dash as one of its own options.
+	if strings.HasPrefix(host, "-") {
+		return "", "", fmt.Errorf("invalid ssh host %q: must not start with -", host)
+	}
+	if !found || path == "" {
+		path = "."
+	}
+	return host, path, nil
+}
+
+// remoteInventoryScript prefers a remote Mole install, then GNU find, then BSD find+stat.
+// Mole writes to a temp file first, so a run that fails partway leaves nothing for the
+// find fallback to add a second listing to.
+func remoteInventoryScript(path string) string {
+	return strings.Join([]string{
+		"p=" + shellQuote(path),
+		`case "$p" in /*) ;; *) p="$(cd "$p" && pwd)" || exit 1 ;; esac`,
+		`echo "root	$p"`,
+		`if command -v mo >/dev/null 2>&1 && f="$(mktemp 2>/dev/null)"; then`,
+		`  if mo analyze --inventory "$p" >"$f" 2>/dev/null; then cat "$f"; s=$?; rm -f "$f"; exit $s; fi`,
+		`  rm -f "$f"`,
+		`fi`,
+		`t="$(printf '\t')"`,
+		`if find --version >/dev/null 2>&1; then find "$p" -xdev -printf '%s\t%b\t%y\t%p\n' 2>/dev/null; exit 0; fi`,
+		`find "$p" -xdev -exec stat -f "%z${t}%b${t}%HT${t}%N" {} + 2>/dev/null`,
+		`exit 0`,
+	}, "\n")
+}
+
+// fetchRemoteInventory streams a listing over SSH and builds a browsable tree.
+func fetchRemoteInventory(host, path string) (*inventoryTree, error) {
+	cmd := exec.Command("ssh", "-o", "BatchMode=yes", "--", host, remoteInventoryScript(path))
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("ssh: %v", err)
+	}
+
+	// First line reports the resolved absolute root.
+	reader := bufio.NewReader(stdout)
+	header, err := reader.ReadString('\n')
+	if err != nil || !strings.HasPrefix(header, "root\t") {
+		_ = cmd.Wait()
+		return nil, fmt.Errorf("ssh %s failed: %s", host, strings.TrimSpace(stderr.String()))
+	}
+	root := strings.TrimSuffix(strings.TrimPrefix(header, "root\t"), "\n")
+
+	tree, readErr := readInventory(reader, "ssh://"+host, root)
+	waitErr := cmd.Wait()
+	if readErr != nil {
+		return nil, readErr
+	}
+	// The script exits 0 whenever it finishes a listing, so any other status means
+	// ssh or the listing broke off and the tree is incomplete.
+	if waitErr != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("ssh %s: %v: %s", host, waitErr, msg)
+		}
+		return nil, fmt.Errorf("ssh %s: %v", host, waitErr)
+	}
+	return tree, nil
+}
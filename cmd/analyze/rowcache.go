@@ -0,0 +1,55 @@
+package main
+
+import "sync"
+
+// rowCache memoizes formatted list rows between frames. A row's key holds every input
+// that shows on screen, so a spinner tick that changes nothing re-formats nothing, and
+// the stat calls behind hints only run for rows that actually changed.
+//
+// Rows live for two frames: those drawn this frame, and those drawn last frame that
+// have not been asked for yet. Everything older is dropped, which bounds the cache to
+// roughly two screens however long the list is.
+type rowCache struct {
+	mu       sync.Mutex
+	current  map[any]string
+	previous map[any]string
+}
+
+func newRowCache() *rowCache {
+	return &rowCache{current: make(map[any]string), previous: make(map[any]string)}
+}
+
+// row returns the memoized row for key, formatting it with render on a miss. A nil
+// cache formats every time. Keys must be comparable.
+func (c *rowCache) row(key any, render func() string) string {
+	if c == nil {
+		return render()
+	}
+	c.mu.Lock()
+	if row, ok := c.current[key]; ok {
+		c.mu.Unlock()
+		return row
+	}
+	if row, ok := c.previous[key]; ok {
+		c.current[key] = row
+		c.mu.Unlock()
+		return row
+	}
+	c.mu.Unlock()
+
+	row := render()
+	c.mu.Lock()
+	c.current[key] = row
+	c.mu.Unlock()
+	return row
+}
+
+// endFrame retires rows not drawn in the last two frames.
+func (c *rowCache) endFrame() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	c.previous, c.current = c.current, make(map[any]string, len(c.current))
+	c.mu.Unlock()
+}
@@ -0,0 +1,40 @@
+package main
+
+import "testing"
+
+func TestRowCacheReusesRowsForTwoFrames(t *testing.T) {
+	cache := newRowCache()
+	renders := 0
+	render := func() string { renders++; return "row" }
+	type key struct{ index int }
+
+	cache.row(key{1}, render)
+	cache.row(key{1}, render)
+	if renders != 1 {
+		t.Fatalf("renders = %d, want 1 within a frame", renders)
+	}
+
+	cache.endFrame()
+	cache.row(key{1}, render)
+	if renders != 1 {
+		t.Fatalf("renders = %d, a row drawn last frame should be reused", renders)
+	}
+
+	// key{1} is drawn every frame and survives; key{2} misses two frames and is dropped.
+	cache.row(key{2}, render)
+	cache.endFrame()
+	cache.row(key{1}, render)
+	cache.endFrame()
+	cache.row(key{1}, render)
+	cache.row(key{2}, render)
+	if renders != 3 {
+		t.Fatalf("renders = %d, want 3 after key{2} aged out", renders)
+	}
+
+	var nilCache *rowCache
+	nilCache.row(key{1}, render)
+	nilCache.endFrame()
+	if renders != 4 {
+		t.Fatalf("a nil cache should render every time")
+	}
+}
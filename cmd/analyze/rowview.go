@@ -0,0 +1,192 @@
+//go:build darwin
+
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// entryRowKey is everything one directory listing row shows; equal keys render equal rows.
+type entryRowKey struct {
+	entry      dirEntry
+	index      int
+	selected   bool
+	marked     bool
+	pinned     bool
+	maxSize    int64
+	totalSize  int64
+	nameWidth  int
+	query      string
+	showShared bool
+	showCounts bool
+	apparent   bool   // Lead with logical sizes
+	marker     string // Watch delta
+	trashHint  string // Volume trash or drive warning in the /Volumes view
+	ownerHint  string // App behind a cache or support folder
+}
+
+// largeRowKey is everything one large-files row shows.
+type largeRowKey struct {
+	file      fileEntry
+	index     int
+	selected  bool
+	marked    bool
+	maxSize   int64
+	nameWidth int
+	query     string
+	scanPath  string // Copy compatibility is judged against the scanned volume
+	apparent  bool
+}
+
+func renderEntryRow(key entryRowKey) string {
+	entry := key.entry
+	icon := "📄"
+	if entry.Bundle {
+		icon = "📦"
+	} else if entry.IsDir {
+		icon = "📁"
+	}
+	if key.pinned {
+		icon = "📌"
+	}
+	size, otherSize := sizeLabels(entry.Size, entry.apparentSize(), key.apparent)
+	name := trimNameWithWidth(displayName(entry.Path, entry.Name), key.nameWidth)
+	paddedName := highlightMatch(padName(name, key.nameWidth), key.query)
+
+	percent := float64(entry.Size) / float64(key.totalSize) * 100
+	percentStr := fmt.Sprintf("%5.1f%%", percent)
+
+	bar := coloredProgressBar(entry.Size, key.maxSize, percent)
+
+	var sizeColor string
+	if percent >= 50 {
+		sizeColor = colorRed
+	} else if percent >= 20 {
+		sizeColor = colorYellow
+	} else if percent >= 5 {
+		sizeColor = colorBlue
+	} else {
+		sizeColor = colorGray
+	}
+
+	selectIcon := "○"
+	nameColor := ""
+	if key.marked {
+		selectIcon = fmt.Sprintf("%s●%s", colorGreen, colorReset)
+		nameColor = colorGreen
+	}
+
+	entryPrefix := "   "
+	nameSegment := fmt.Sprintf("%s %s", icon, paddedName)
+	if nameColor != "" {
+		nameSegment = fmt.Sprintf("%s%s %s%s", nameColor, icon, paddedName, colorReset)
+	}
+	numColor := ""
+	percentColor := ""
+	if key.selected {
+		entryPrefix = fmt.Sprintf(" %s%s▶%s ", colorCyan, colorBold, colorReset)
+		if !key.marked {
+			nameSegment = fmt.Sprintf("%s%s %s%s", colorCyan, icon, paddedName, colorReset)
+		}
+		numColor = colorCyan
+		percentColor = colorCyan
+		sizeColor = colorCyan
+	}
+
+	displayIndex := key.index + 1
+
+	var hintLabel string
+	if entry.CaseConflict {
+		hintLabel = fmt.Sprintf("%sAa clash%s", colorYellow, colorReset)
+	} else if entry.Strategy == strategySkip {
+		hintLabel = fmt.Sprintf("%snot scanned%s", colorGray, colorReset)
+	} else if entry.Strategy == strategyEstimate {
+		hintLabel = fmt.Sprintf("%s≈ estimate%s", colorGray, colorReset)
+	} else if entry.Privileged {
+		hintLabel = fmt.Sprintf("%s🔐 measured as root%s", colorYellow, colorReset)
+	} else if entry.Dataless {
+		hintLabel = fmt.Sprintf("%s☁ in cloud, not local%s", colorBlue, colorReset)
+	} else if entry.Cloud > 0 {
+		hintLabel = fmt.Sprintf("%s☁ %s in cloud%s", colorBlue, humanizeBytes(entry.Cloud), colorReset)
+	} else if entry.IsDir && isCleanableDir(entry.Path) {
+		hintLabel = fmt.Sprintf("%s🧹%s", colorYellow, colorReset)
+	} else {
+		lastAccess := entry.LastAccess
+		if lastAccess.IsZero() && entry.Path != "" {
+			lastAccess = getLastAccessTime(entry.Path)
+		}
+		if unusedTime := formatUnusedTime(lastAccess); unusedTime != "" {
+			hintLabel = fmt.Sprintf("%s%s%s", colorGray, unusedTime, colorReset)
+		}
+	}
+
+	if key.trashHint != "" {
+		hintLabel = key.trashHint
+	}
+	if key.ownerHint != "" {
+		hintLabel = strings.TrimSpace(key.ownerHint + "  " + hintLabel)
+	}
+
+	sizeColumn := fmt.Sprintf("%s%10s%s", sizeColor, size, colorReset)
+	if otherSize != "" {
+		sizeColumn += fmt.Sprintf(" %s(%s)%s", colorGray, otherSize, colorReset)
+	}
+	if key.showShared {
+		shared := "-"
+		if entry.Shared > 0 {
+			shared = humanizeBytes(entry.Shared)
+		}
+		sizeColumn += fmt.Sprintf("  %s🔗%9s%s", colorGray, shared, colorReset)
+	}
+	if key.showCounts {
+		sizeColumn += fmt.Sprintf("  %s%8s files %7s dirs%s", colorGray, formatCount(entry.Files), formatCount(entry.Dirs), colorReset)
+	}
+	sizeColumn += key.marker
+
+	if hintLabel == "" {
+		return fmt.Sprintf("%s%s %s%2d.%s %s %s%s%s  |  %s %s\n",
+			entryPrefix, selectIcon, numColor, displayIndex, colorReset, bar, percentColor, percentStr, colorReset,
+			nameSegment, sizeColumn)
+	}
+	return fmt.Sprintf("%s%s %s%2d.%s %s %s%s%s  |  %s %s  %s\n",
+		entryPrefix, selectIcon, numColor, displayIndex, colorReset, bar, percentColor, percentStr, colorReset,
+		nameSegment, sizeColumn, hintLabel)
+}
+
+func renderLargeRow(key largeRowKey) string {
+	file := key.file
+	shortPath := displayPath(file.Path)
+	shortPath = truncateMiddle(shortPath, key.nameWidth)
+	paddedPath := highlightMatch(padName(shortPath, key.nameWidth), key.query)
+	entryPrefix := "   "
+	nameColor := ""
+	sizeColor := colorGray
+	numColor := ""
+
+	selectIcon := "○"
+	if key.marked {
+		selectIcon = fmt.Sprintf("%s●%s", colorGreen, colorReset)
+		nameColor = colorGreen
+	}
+
+	if key.selected {
+		entryPrefix = fmt.Sprintf(" %s%s▶%s ", colorCyan, colorBold, colorReset)
+		if !key.marked {
+			nameColor = colorCyan
+		}
+		sizeColor = colorCyan
+		numColor = colorCyan
+	}
+	size, otherSize := sizeLabels(file.Size, file.apparentSize(), key.apparent)
+	if otherSize != "" {
+		otherSize = fmt.Sprintf(" %s(%s)%s", colorGray, otherSize, colorReset)
+	}
+	bar := coloredProgressBar(file.Size, key.maxSize, 0)
+	var compatLabel string
+	if issues := copyCompatIssues(file.Path, file.Size, key.scanPath); len(issues) > 0 {
+		compatLabel = fmt.Sprintf("  %s⚠ %s%s", colorYellow, strings.Join(issues, ", "), colorReset)
+	}
+	return fmt.Sprintf("%s%s %s%2d.%s %s  |  📄 %s%s%s  %s%10s%s%s%s\n",
+		entryPrefix, selectIcon, numColor, key.index+1, colorReset, bar, nameColor, paddedPath, colorReset, sizeColor, size, colorReset, otherSize, compatLabel)
+}
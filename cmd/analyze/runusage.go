@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// usageSampleInterval is how often a running scan's descriptors and goroutines are counted.
+const usageSampleInterval = 100 * time.Millisecond
+
+// runUsage is what one scan cost the process, for the D panel once the scan ends.
+type runUsage struct {
+	Wall            time.Duration
+	User            time.Duration
+	System          time.Duration
+	PeakRSS         int64 // Process high-water mark, which may predate this run
+	PeakFDs         int   // -1 when /dev/fd cannot be listed
+	PeakGoroutines  int
+	ContextSwitches int64
+	BlockReads      int64 // Reads that went to disk rather than the cache
+	Dirs            int64
+}
+
+// usageSampler tracks a scan from start to finish.
+type usageSampler struct {
+	started time.Time
+	before  syscall.Rusage
+	stop    chan struct{}
+	done    sync.WaitGroup
+
+	mu             sync.Mutex
+	peakFDs        int
+	peakGoroutines int
+}
+
+// startUsageSampler notes the process's usage now and samples peaks until finish.
+func startUsageSampler() *usageSampler {
+	s := &usageSampler{started: time.Now(), stop: make(chan struct{}), peakFDs: -1}
+	_ = syscall.Getrusage(syscall.RUSAGE_SELF, &s.before)
+	s.sample()
+	s.done.Add(1)
+	go func() {
+		defer s.done.Done()
+		ticker := time.NewTicker(usageSampleInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+				s.sample()
+			}
+		}
+	}()
+	return s
+}
+
+func (s *usageSampler) sample() {
+	fds, goroutines := countOpenFDs(), runtime.NumGoroutine()
+	s.mu.Lock()
+	s.peakFDs = max(s.peakFDs, fds)
+	s.peakGoroutines = max(s.peakGoroutines, goroutines)
+	s.mu.Unlock()
+}
+
+// finish stops sampling and returns the usage since start.
+func (s *usageSampler) finish(dirs int64) runUsage {
+	close(s.stop)
+	s.done.Wait()
+	s.sample()
+	var after syscall.Rusage
+	_ = syscall.Getrusage(syscall.RUSAGE_SELF, &after)
+	return runUsage{
+		Wall:            time.Since(s.started),
+		User:            time.Duration(after.Utime.Nano() - s.before.Utime.Nano()),
+		System:          time.Duration(after.Stime.Nano() - s.before.Stime.Nano()),
+		PeakRSS:         maxRSSBytes(after.Maxrss),
+		PeakFDs:         s.peakFDs,
+		PeakGoroutines:  s.peakGoroutines,
+		ContextSwitches: int64(after.Nvcsw+after.Nivcsw) - int64(s.before.Nvcsw+s.before.Nivcsw),
+		BlockReads:      int64(after.Inblock) - int64(s.before.Inblock),
+		Dirs:            dirs,
+	}
+}
+
+// maxRSSBytes converts ru_maxrss, which macOS reports in bytes and Linux in kilobytes.
+func maxRSSBytes(maxrss int64) int64 {
+	if runtime.GOOS == "darwin" {
+		return maxrss
+	}
+	return maxrss * 1024
+}
+
+// renderRunUsage formats the last scan's usage for the D panel.
+func renderRunUsage(u runUsage) string {
+	cpu := u.User + u.System
+	parts := []string{
+		"Last scan " + u.Wall.Round(time.Millisecond).String(),
+		fmt.Sprintf("CPU %s (%s user, %s sys)", cpu.Round(time.Millisecond), u.User.Round(time.Millisecond), u.System.Round(time.Millisecond)),
+		"Peak RSS " + humanizeBytes(u.PeakRSS),
+	}
+	if u.PeakFDs >= 0 {
+		parts = append(parts, fmt.Sprintf("Peak FDs %d", u.PeakFDs))
+	}
+	parts = append(parts, fmt.Sprintf("Peak goroutines %d", u.PeakGoroutines))
+	if u.Wall > 0 && u.Dirs > 0 {
+		parts = append(parts, fmt.Sprintf("%s dirs/s", formatGrouped(int64(float64(u.Dirs)/u.Wall.Seconds()))))
+	}
+	parts = append(parts, fmt.Sprintf("Ctx switches %s", formatGrouped(u.ContextSwitches)), fmt.Sprintf("Disk reads %s", formatGrouped(u.BlockReads)))
+	return fmt.Sprintf("%s%s%s\n", colorGray, strings.Join(parts, "  |  "), colorReset)
+}
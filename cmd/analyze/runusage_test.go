@@ -0,0 +1,45 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUsageSamplerMeasuresRun(t *testing.T) {
+	sampler := startUsageSampler()
+	done := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		go func() { <-done }()
+	}
+	deadline := time.Now().Add(30 * time.Millisecond)
+	for time.Now().Before(deadline) {
+	}
+	usage := sampler.finish(120)
+	close(done)
+
+	if usage.Wall < 30*time.Millisecond {
+		t.Errorf("wall = %s, want at least 30ms", usage.Wall)
+	}
+	if usage.User+usage.System <= 0 {
+		t.Errorf("no CPU time recorded for a busy loop: %+v", usage)
+	}
+	if usage.PeakGoroutines < 5 {
+		t.Errorf("peak goroutines = %d, want the 4 extra ones counted", usage.PeakGoroutines)
+	}
+	if usage.PeakRSS <= 0 || usage.Dirs != 120 {
+		t.Errorf("usage = %+v", usage)
+	}
+}
+
+func TestRenderRunUsage(t *testing.T) {
+	line := renderRunUsage(runUsage{Wall: 2 * time.Second, User: 1500 * time.Millisecond, System: 500 * time.Millisecond, PeakRSS: 64 << 20, PeakFDs: -1, PeakGoroutines: 40, Dirs: 10000})
+	for _, want := range []string{"Last scan 2s", "CPU 2s", "Peak RSS 64.0 MB", "5,000 dirs/s"} {
+		if !strings.Contains(line, want) {
+			t.Errorf("%q missing %q", line, want)
+		}
+	}
+	if strings.Contains(line, "FDs") {
+		t.Errorf("%q shows descriptors that could not be counted", line)
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+const safeModeEnvVar = "MO_ANALYZE_SAFE"
+
+// safeCategory is a kind of data safe mode lets novices delete, with a plain-language reason.
+type safeCategory struct {
+	Label   string
+	Explain string
+}
+
+var (
+	safeTrash = safeCategory{
+		Label:   "Trash",
+		Explain: "These items are already in the Trash. Deleting them frees the space for good.",
+	}
+	safeCache = safeCategory{
+		Label:   "Cache",
+		Explain: "Apps keep caches to load faster and rebuild them when needed. Apps may be a little slower the next time they open.",
+	}
+	safeDevArtifact = safeCategory{
+		Label:   "Project build files",
+		Explain: "Downloaded dependencies or build output of a software project. They are recreated by the project's install or build step.",
+	}
+)
+
+// safeDeleteCategory reports whether path lives in a category safe mode allows deleting.
+// Only items inside a category qualify, never the category folder itself.
+func safeDeleteCategory(path string) (safeCategory, bool) {
+	if path == "" {
+		return safeCategory{}, false
+	}
+	for _, root := range []string{"/.Trash/", "/.Trashes/"} {
+		if strings.Contains(path, root) {
+			return safeTrash, true
+		}
+	}
+	for _, root := range []string{"/Library/Caches/", "/.cache/", "/Library/Developer/Xcode/DerivedData/"} {
+		if strings.Contains(path, root) {
+			return safeCache, true
+		}
+	}
+	if projectDependencyDirs[filepath.Base(path)] {
+		return safeDevArtifact, true
+	}
+	return safeCategory{}, false
+}
+
+// safeModeBlockReason explains in plain words why safe mode refuses to delete path.
+func safeModeBlockReason(path string) string {
+	return fmt.Sprintf("Safe mode: %s may be personal files or part of an app, so it can't be deleted here. Only Trash, caches and project build files can.",
+		filepath.Base(path))
+}
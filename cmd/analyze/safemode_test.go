@@ -0,0 +1,29 @@
+package main
+
+import "testing"
+
+func TestSafeDeleteCategory(t *testing.T) {
+	allowed := map[string]string{
+		"/Users/a/.Trash/old.dmg":                                "Trash",
+		"/Volumes/USB/.Trashes/501/photo.jpg":                    "Trash",
+		"/Users/a/Library/Caches/com.spotify.client":             "Cache",
+		"/Users/a/Library/Developer/Xcode/DerivedData/App-abcde": "Cache",
+		"/Users/a/Projects/web/node_modules":                     "Project build files",
+	}
+	for path, want := range allowed {
+		category, ok := safeDeleteCategory(path)
+		if !ok || category.Label != want {
+			t.Fatalf("%s: expected %s, got %+v (%v)", path, want, category, ok)
+		}
+	}
+	for _, path := range []string{
+		"/Users/a/Documents/Taxes",
+		"/Users/a/Library/Caches",
+		"/Applications/Safari.app",
+		"/Users/a/Pictures/Photos Library.photoslibrary",
+	} {
+		if _, ok := safeDeleteCategory(path); ok {
+			t.Fatalf("%s: expected safe mode to refuse", path)
+		}
+	}
+}
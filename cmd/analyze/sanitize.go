@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// A file name may hold any byte but '/' and NUL: newlines, escape sequences that restyle
+// the terminal, bidi overrides that reorder the text around them, or bytes that are not
+// UTF-8 at all. Text drawn for a person goes through sanitizeName; text written for a
+// program is quoted so the name round-trips.
+
+// unsafeRune reports characters that must never reach the terminal as they are.
+func unsafeRune(r rune) bool {
+	switch {
+	case unicode.IsControl(r):
+		return true
+	case r == '\u200e' || r == '\u200f': // Left-to-right and right-to-left marks
+		return true
+	case r >= '\u202a' && r <= '\u202e', r >= '\u2066' && r <= '\u2069': // Bidi embeddings and isolates
+		return true
+	}
+	return false
+}
+
+// needsEscaping reports whether s holds an unsafe character or invalid UTF-8.
+func needsEscaping(s string) bool {
+	for i, r := range s {
+		if unsafeRune(r) {
+			return true
+		}
+		if r == utf8.RuneError {
+			if _, size := utf8.DecodeRuneInString(s[i:]); size == 1 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sanitizeName makes s safe to print on one row: control characters and invalid bytes
+// become visible escapes such as \n, \x1b and \u202e. Ordinary names come back unchanged.
+func sanitizeName(s string) string {
+	if !needsEscaping(s) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			fmt.Fprintf(&b, `\x%02x`, s[i])
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case unsafeRune(r) && r < 0x100:
+			fmt.Fprintf(&b, `\x%02x`, r)
+		case unsafeRune(r):
+			fmt.Fprintf(&b, `\u%04x`, r)
+		default:
+			b.WriteString(s[i : i+size])
+		}
+		i += size
+	}
+	return b.String()
+}
+
+// shellQuote wraps s in single quotes for a POSIX shell. Names that need escaping use
+// $'...' instead, so a generated script keeps each command on one line and shows what
+// it will run; bash (the macOS /bin/sh), zsh and dash all read it.
+func shellQuote(s string) string {
+	if !needsEscaping(s) {
+		return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+	}
+	var b strings.Builder
+	b.WriteString("$'")
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == '\\' || r == '\'':
+			b.WriteByte('\\')
+			b.WriteByte(s[i])
+		case (r == utf8.RuneError && size == 1) || unsafeRune(r):
+			for _, c := range []byte(s[i : i+size]) {
+				fmt.Fprintf(&b, `\x%02x`, c)
+			}
+		default:
+			b.WriteString(s[i : i+size])
+		}
+		i += size
+	}
+	b.WriteByte('\'')
+	return b.String()
+}
+
+// quoteInventoryPath writes a path into a tab-separated inventory line. Paths that would
+// split the line, or are not UTF-8, are written as a Go string literal; a real path never
+// starts with a double quote once made absolute, so readers can tell the two apart.
+func quoteInventoryPath(path string) string {
+	if strings.HasPrefix(path, `"`) || needsEscaping(path) {
+		return strconv.Quote(path)
+	}
+	return path
+}
+
+// unquoteInventoryPath reverses quoteInventoryPath.
+func unquoteInventoryPath(field string) string {
+	if strings.HasPrefix(field, `"`) {
+		if path, err := strconv.Unquote(field); err == nil {
+			return path
+		}
+	}
+	return field
+}
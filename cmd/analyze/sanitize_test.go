@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// hostileNames are file names a scan can meet that used to break rows and scripts.
+var hostileNames = []string{
+	"line\nbreak",
+	"tab\there",
+	"\x1b[31mred\x1b[0m",
+	"bad\xffutf8",
+	"evil\u202etxt.exe",
+	"quote's \\ back",
+}
+
+func TestSanitizeNameKeepsRowsOnOneLine(t *testing.T) {
+	if got := sanitizeName("plain name ✓.txt"); got != "plain name ✓.txt" {
+		t.Fatalf("ordinary names should not change, got %q", got)
+	}
+	for name, want := range map[string]string{
+		"line\nbreak":         `line\nbreak`,
+		"\x1b[31mred":         `\x1b[31mred`,
+		"bad\xffutf8":         `bad\xffutf8`,
+		"evil\u202etxt.exe":   `evil\u202etxt.exe`,
+		"tab\there\r":         `tab\there\r`,
+		"replacement\ufffdok": "replacement\ufffdok",
+	} {
+		if got := sanitizeName(name); got != want {
+			t.Fatalf("sanitizeName(%q) = %q, want %q", name, got, want)
+		}
+	}
+	for _, name := range hostileNames {
+		for _, shown := range []string{trimNameWithWidth(name, 12), truncateMiddle("/tmp/"+name, 12), displayPath("/tmp/" + name)} {
+			if needsEscaping(shown) {
+				t.Fatalf("%q rendered unsafely as %q", name, shown)
+			}
+		}
+	}
+}
+
+func TestShellQuoteRoundTripsHostileNames(t *testing.T) {
+	for _, name := range hostileNames {
+		quoted := shellQuote(name)
+		if strings.ContainsAny(quoted, "\n\r\x1b") {
+			t.Fatalf("shellQuote(%q) = %q spans lines or carries escapes", name, quoted)
+		}
+	}
+	script, err := buildScript("rm", []string{"/tmp/a\nb", "/tmp/c"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(script, "rm -rf") != 2 || strings.Count(script, "\n") != 5 {
+		t.Fatalf("every path should stay on its own line:\n%s", script)
+	}
+
+	bash, err := exec.LookPath("bash")
+	if err != nil {
+		t.Skip("bash not available")
+	}
+	for _, name := range hostileNames {
+		out, err := exec.Command(bash, "-c", "printf %s "+shellQuote(name)).Output()
+		if err != nil || string(out) != name {
+			t.Fatalf("bash read %q back as %q (%v)", name, out, err)
+		}
+	}
+}
+
+func TestInventoryRoundTripsHostileNames(t *testing.T) {
+	base := t.TempDir()
+	var created []string
+	for _, name := range hostileNames {
+		if err := os.WriteFile(filepath.Join(base, name), []byte("data"), 0644); err != nil {
+			continue // The filesystem may refuse names that are not UTF-8
+		}
+		created = append(created, name)
+	}
+	var buf bytes.Buffer
+	if err := writeInventory(&buf, base); err != nil {
+		t.Fatalf("writeInventory: %v", err)
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != len(created)+1 {
+		t.Fatalf("expected %d inventory lines, got %d:\n%s", len(created)+1, lines, buf.String())
+	}
+	tree, err := readInventory(&buf, "file", base)
+	if err != nil {
+		t.Fatalf("readInventory: %v", err)
+	}
+	for _, name := range created {
+		if _, ok := tree.lookup(filepath.Join(base, name)); !ok {
+			t.Fatalf("%q did not survive the inventory round trip", name)
+		}
+	}
+	if got := unquoteInventoryPath("/plain/path"); got != "/plain/path" {
+		t.Fatalf("plain paths should be read as they are, got %q", got)
+	}
+}
@@ -0,0 +1,324 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// savedSearchesFile holds "name = query" lines; a bare query names itself:
+//
+//	Big movies = *.mov >1GB in:~/Movies
+//	in:~/Downloads older:90d
+const savedSearchesFile = "analyze_searches"
+
+// maxSavedSearchResults caps the files kept per search; the count and total cover all matches.
+const maxSavedSearchResults = 500
+
+// savedSearch is one parsed definition. The words left after the in:, >size and
+// older: terms are a name filter matched like the "/" filter.
+type savedSearch struct {
+	Name      string
+	Query     string
+	Pattern   string
+	Root      string
+	MinSize   int64
+	OlderThan time.Duration
+}
+
+// savedSearchResult is what one search currently matches.
+type savedSearchResult struct {
+	Files  []fileEntry // Largest first, at most maxSavedSearchResults
+	Count  int
+	Total  int64
+	Source string // "Spotlight" or "scan cache"
+	Err    error
+}
+
+type savedSearchResultsMsg struct {
+	Results map[string]savedSearchResult // Keyed by search name
+}
+
+// joinFilterTerms turns the spelled-out "over 100MB" and "older 90d" into over:100MB
+// and older:90d.
+func joinFilterTerms(fields []string) []string {
+	joined := make([]string, 0, len(fields))
+	for i := 0; i < len(fields); i++ {
+		if (fields[i] == "over" || fields[i] == "older") && i+1 < len(fields) {
+			joined = append(joined, fields[i]+":"+fields[i+1])
+			i++
+			continue
+		}
+		joined = append(joined, fields[i])
+	}
+	return joined
+}
+
+// parseSearchAge reads ages such as 90d, 6w, 3m or 1y.
+func parseSearchAge(s string) (time.Duration, error) {
+	units := map[byte]time.Duration{'d': 24 * time.Hour, 'w': 7 * 24 * time.Hour, 'm': 30 * 24 * time.Hour, 'y': 365 * 24 * time.Hour}
+	if len(s) < 2 {
+		return 0, fmt.Errorf("invalid age %q", s)
+	}
+	unit, ok := units[s[len(s)-1]]
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if !ok || err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid age %q, use a count of d, w, m or y", s)
+	}
+	return time.Duration(n) * unit, nil
+}
+
+// parseSavedSearch reads a query such as "*.mov >1GB in:~/Movies". Without in:
+// the search covers home.
+func parseSavedSearch(name, query, home string) (savedSearch, error) {
+	search := savedSearch{Name: name, Query: query, Root: home}
+	var words []string
+	for _, field := range joinFilterTerms(strings.Fields(query)) {
+		var err error
+		switch {
+		case strings.HasPrefix(field, "in:"):
+			root := strings.TrimPrefix(field, "in:")
+			if root == "~" || strings.HasPrefix(root, "~/") {
+				root = home + root[1:]
+			}
+			if !filepath.IsAbs(root) {
+				return savedSearch{}, fmt.Errorf("%s: in: needs an absolute or ~ path", name)
+			}
+			search.Root = filepath.Clean(root)
+		case strings.HasPrefix(field, ">"):
+			search.MinSize, err = parseByteSize(strings.TrimPrefix(field, ">"))
+		case strings.HasPrefix(field, "over:"):
+			search.MinSize, err = parseByteSize(strings.TrimPrefix(field, "over:"))
+		case strings.HasPrefix(field, "older:"):
+			search.OlderThan, err = parseSearchAge(strings.TrimPrefix(field, "older:"))
+		default:
+			words = append(words, field)
+		}
+		if err != nil {
+			return savedSearch{}, fmt.Errorf("%s: %v", name, err)
+		}
+	}
+	search.Pattern = strings.Join(words, " ")
+	if search.Pattern == "" && search.MinSize == 0 && search.OlderThan == 0 {
+		return savedSearch{}, fmt.Errorf("%s: needs a name pattern, >size or older: term", name)
+	}
+	return search, nil
+}
+
+// parseSavedSearches reads the saved searches file, skipping lines that do not parse.
+func parseSavedSearches(data, home string) []savedSearch {
+	var searches []savedSearch
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, query, ok := strings.Cut(line, " = ")
+		if !ok {
+			name, query = line, line
+		}
+		name, query = strings.TrimSpace(name), strings.TrimSpace(query)
+		search, err := parseSavedSearch(name, query, home)
+		if err != nil || seen[name] {
+			continue
+		}
+		seen[name] = true
+		searches = append(searches, search)
+	}
+	return searches
+}
+
+func getSavedSearchesPath() (string, error) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, savedSearchesFile), nil
+}
+
+// loadSavedSearches returns the user's saved searches; a missing file means none.
+func loadSavedSearches(home string) []savedSearch {
+	path, err := getSavedSearchesPath()
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	return parseSavedSearches(string(data), home)
+}
+
+// writeSavedSearches persists searches in file order.
+func writeSavedSearches(searches []savedSearch) error {
+	path, err := getSavedSearchesPath()
+	if err != nil {
+		return err
+	}
+	var b strings.Builder
+	b.WriteString("# Mole analyze saved searches: name = query\n")
+	for _, search := range searches {
+		if search.Name == search.Query {
+			fmt.Fprintf(&b, "%s\n", search.Query)
+		} else {
+			fmt.Fprintf(&b, "%s = %s\n", search.Name, search.Query)
+		}
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, []byte(b.String()), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// spotlightQuery turns a search into an mdfind query, matching names case-insensitively.
+func (s savedSearch) spotlightQuery(now time.Time) string {
+	var terms []string
+	if s.Pattern != "" {
+		pattern := strings.ReplaceAll(s.Pattern, `"`, `\"`)
+		if !isGlobQuery(pattern) {
+			pattern = "*" + pattern + "*"
+		}
+		terms = append(terms, fmt.Sprintf(`kMDItemFSName == "%s"c`, pattern))
+	}
+	if s.MinSize > 0 {
+		terms = append(terms, fmt.Sprintf("kMDItemFSSize >= %d", s.MinSize))
+	}
+	if s.OlderThan > 0 {
+		terms = append(terms, fmt.Sprintf(`kMDItemFSContentChangeDate < $time.iso(%s)`, now.Add(-s.OlderThan).UTC().Format(time.RFC3339)))
+	}
+	terms = append(terms, `kMDItemContentTypeTree != "public.folder"`)
+	return strings.Join(terms, " && ")
+}
+
+// matches re-checks a candidate against the definition, since Spotlight and the
+// cache can both lag behind the disk.
+func (s savedSearch) matches(file fileEntry, now time.Time) bool {
+	if file.Path != s.Root && !strings.HasPrefix(file.Path, s.Root+"/") {
+		return false
+	}
+	if s.Pattern != "" && !matchFilter(s.Pattern, file.Name) {
+		return false
+	}
+	if file.Size < s.MinSize {
+		return false
+	}
+	return s.OlderThan == 0 || now.Sub(file.ModTime) >= s.OlderThan
+}
+
+// collect keeps matching candidates, largest first.
+func (s savedSearch) collect(candidates []fileEntry, source string, now time.Time) savedSearchResult {
+	result := savedSearchResult{Source: source}
+	for _, file := range candidates {
+		if !s.matches(file, now) {
+			continue
+		}
+		result.Count++
+		result.Total += file.Size
+		result.Files = append(result.Files, file)
+	}
+	sort.Slice(result.Files, func(i, j int) bool { return result.Files[i].Size > result.Files[j].Size })
+	if len(result.Files) > maxSavedSearchResults {
+		result.Files = result.Files[:maxSavedSearchResults]
+	}
+	return result
+}
+
+// spotlightCandidates asks Spotlight for files that may match; ok is false when it cannot answer.
+func (s savedSearch) spotlightCandidates(now time.Time) ([]fileEntry, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), mdlsTimeout)
+	defer cancel()
+	output, err := exec.CommandContext(ctx, "mdfind", "-onlyin", s.Root, s.spotlightQuery(now)).Output()
+	if err != nil {
+		return nil, false
+	}
+	var files []fileEntry
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line == "" || isInFoldedDir(line) || isExcludedPath(s.Root, line) {
+			continue
+		}
+		info, err := os.Lstat(line)
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		files = append(files, fileEntry{Name: filepath.Base(line), Path: line, Size: getActualFileSize(line, info), ModTime: info.ModTime()})
+	}
+	return files, true
+}
+
+// cachedCandidates falls back to the files the last scan of the root listed, re-read
+// from disk so deleted files drop out and ages are current.
+func (s savedSearch) cachedCandidates() ([]fileEntry, error) {
+	entry, err := loadCacheFromDisk(s.Root)
+	if err != nil {
+		return nil, fmt.Errorf("no Spotlight index or recent scan of %s", displayPath(s.Root))
+	}
+	paths := make([]string, 0, len(entry.LargeFiles)+len(entry.Entries))
+	for _, file := range entry.LargeFiles {
+		paths = append(paths, file.Path)
+	}
+	for _, dir := range entry.Entries {
+		if !dir.IsDir {
+			paths = append(paths, dir.Path)
+		}
+	}
+	seen := make(map[string]bool, len(paths))
+	var files []fileEntry
+	for _, path := range paths {
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+		info, err := os.Lstat(path)
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		files = append(files, fileEntry{Name: filepath.Base(path), Path: path, Size: getActualFileSize(path, info), ModTime: info.ModTime()})
+	}
+	return files, nil
+}
+
+func runSavedSearch(search savedSearch, now time.Time) savedSearchResult {
+	if files, ok := search.spotlightCandidates(now); ok {
+		return search.collect(files, "Spotlight", now)
+	}
+	files, err := search.cachedCandidates()
+	if err != nil {
+		return savedSearchResult{Err: err}
+	}
+	return search.collect(files, "scan cache", now)
+}
+
+// runSavedSearchesCmd refreshes every search's count and results in parallel.
+func runSavedSearchesCmd(searches []savedSearch) tea.Cmd {
+	return func() tea.Msg {
+		now := time.Now()
+		results := make(map[string]savedSearchResult, len(searches))
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		for _, search := range searches {
+			wg.Add(1)
+			go func(search savedSearch) {
+				defer wg.Done()
+				result := runSavedSearch(search, now)
+				mu.Lock()
+				results[search.Name] = result
+				mu.Unlock()
+			}(search)
+		}
+		wg.Wait()
+		return savedSearchResultsMsg{Results: results}
+	}
+}
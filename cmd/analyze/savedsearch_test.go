@@ -0,0 +1,77 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSavedSearch(t *testing.T) {
+	search, err := parseSavedSearch("Big movies", "*.mov >1GB in:~/Movies", "/Users/me")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if search.Pattern != "*.mov" || search.MinSize != 1<<30 || search.Root != "/Users/me/Movies" || search.OlderThan != 0 {
+		t.Fatalf("unexpected search: %+v", search)
+	}
+
+	search, err = parseSavedSearch("old", "in:~/Downloads older:90d", "/Users/me")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if search.Pattern != "" || search.Root != "/Users/me/Downloads" || search.OlderThan != 90*24*time.Hour {
+		t.Fatalf("unexpected search: %+v", search)
+	}
+
+	// A filter saved as typed spells its terms out.
+	search, err = parseSavedSearch("logs", "*.log over 100MB", "/Users/me")
+	if err != nil || search.Pattern != "*.log" || search.MinSize != 100<<20 {
+		t.Fatalf("unexpected search: %+v, %v", search, err)
+	}
+
+	for _, query := range []string{"in:~/Downloads", "x in:Downloads", "x >big", "x older:soon"} {
+		if _, err := parseSavedSearch(query, query, "/Users/me"); err == nil {
+			t.Fatalf("expected %q to be rejected", query)
+		}
+	}
+}
+
+func TestParseSavedSearchesFile(t *testing.T) {
+	data := "# comment\nBig movies = *.mov >1GB in:~/Movies\nin:~/Downloads older:90d\nbroken = in:nowhere\nBig movies = *.mkv\n"
+	searches := parseSavedSearches(data, "/Users/me")
+	if len(searches) != 2 {
+		t.Fatalf("expected 2 searches, got %+v", searches)
+	}
+	if searches[0].Name != "Big movies" || searches[1].Name != "in:~/Downloads older:90d" {
+		t.Fatalf("unexpected names: %q, %q", searches[0].Name, searches[1].Name)
+	}
+}
+
+func TestSavedSearchSpotlightQuery(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	search := savedSearch{Pattern: "report", MinSize: 1024, OlderThan: 24 * time.Hour}
+	query := search.spotlightQuery(now)
+	for _, want := range []string{`kMDItemFSName == "*report*"c`, "kMDItemFSSize >= 1024", "$time.iso(2024-05-31T00:00:00Z)"} {
+		if !strings.Contains(query, want) {
+			t.Fatalf("query %q missing %q", query, want)
+		}
+	}
+}
+
+func TestSavedSearchCollect(t *testing.T) {
+	now := time.Now()
+	search := savedSearch{Root: "/Users/me/Movies", Pattern: "*.mov", MinSize: 100, OlderThan: 48 * time.Hour}
+	old := now.Add(-72 * time.Hour)
+	candidates := []fileEntry{
+		{Name: "a.mov", Path: "/Users/me/Movies/a.mov", Size: 200, ModTime: old},
+		{Name: "B.MOV", Path: "/Users/me/Movies/x/B.MOV", Size: 500, ModTime: old},
+		{Name: "small.mov", Path: "/Users/me/Movies/small.mov", Size: 10, ModTime: old},
+		{Name: "new.mov", Path: "/Users/me/Movies/new.mov", Size: 900, ModTime: now},
+		{Name: "c.mov", Path: "/Users/me/MoviesOld/c.mov", Size: 900, ModTime: old},
+		{Name: "d.mp4", Path: "/Users/me/Movies/d.mp4", Size: 900, ModTime: old},
+	}
+	result := search.collect(candidates, "scan cache", now)
+	if result.Count != 2 || result.Total != 700 || result.Files[0].Name != "B.MOV" {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+}
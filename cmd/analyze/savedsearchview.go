@@ -0,0 +1,208 @@
+//go:build darwin
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// searchesView is the "m" panel of saved searches with their current match counts.
+type searchesView struct {
+	Searches []savedSearch
+	Results  map[string]savedSearchResult
+	Running  bool
+	Selected int
+	Open     bool // Showing the selected search's files
+	File     int  // Selected file while Open
+}
+
+func (v *searchesView) selectedResult() (savedSearchResult, bool) {
+	if v.Selected >= len(v.Searches) {
+		return savedSearchResult{}, false
+	}
+	result, ok := v.Results[v.Searches[v.Selected].Name]
+	return result, ok
+}
+
+func (m model) openSavedSearches() (tea.Model, tea.Cmd) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		m.status = "Unable to find your home folder"
+		return m, nil
+	}
+	searches := loadSavedSearches(home)
+	if len(searches) == 0 {
+		path, _ := getSavedSearchesPath()
+		m.status = fmt.Sprintf("No saved searches yet: filter with / and press ctrl+s, or edit %s", displayPath(path))
+		return m, nil
+	}
+	m.searches = &searchesView{Searches: searches, Running: true}
+	m.status = fmt.Sprintf("Running %d saved searches...", len(searches))
+	return m, tea.Batch(runSavedSearchesCmd(searches), tickCmd())
+}
+
+// saveFilterAsSearch stores the "/" filter, scoped to the current folder, as a saved search.
+func (m model) saveFilterAsSearch() (tea.Model, tea.Cmd) {
+	query := strings.TrimSpace(m.filterQuery())
+	if query == "" || m.inventory != nil || m.inOverviewMode() {
+		m.status = "Type a filter inside a folder to save it as a search"
+		return m, nil
+	}
+	// Query terms are split on spaces, so in: cannot hold one.
+	if strings.Contains(m.path, " ") {
+		m.status = "Saved searches cannot cover folders with spaces in their path"
+		return m, nil
+	}
+	home, _ := os.UserHomeDir()
+	query = fmt.Sprintf("%s in:%s", query, displayPath(m.path))
+	search, err := parseSavedSearch(query, query, home)
+	if err != nil {
+		m.status = err.Error()
+		return m, nil
+	}
+	searches := loadSavedSearches(home)
+	for _, saved := range searches {
+		if saved.Query == query {
+			m.status = fmt.Sprintf("Already saved as %q", saved.Name)
+			return m, nil
+		}
+	}
+	if err := writeSavedSearches(append(searches, search)); err != nil {
+		m.status = fmt.Sprintf("Unable to save search: %v", err)
+		return m, nil
+	}
+	m.status = fmt.Sprintf("Saved search %q, press m to list saved searches", query)
+	return m, nil
+}
+
+// updateSearchesKey handles keys while the saved searches panel is open.
+func (m model) updateSearchesKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	v := m.searches
+	result, hasResult := v.selectedResult()
+	switch msg.String() {
+	case "q", "Q", "ctrl+c":
+		return m.quit()
+	case "esc", "b", "left", "h", "m":
+		if v.Open {
+			v.Open = false
+		} else {
+			m.searches = nil
+		}
+	case "up", "k":
+		if v.Open {
+			v.File = max(v.File-1, 0)
+		} else {
+			v.Selected = max(v.Selected-1, 0)
+		}
+	case "down", "j":
+		if v.Open {
+			v.File = min(v.File+1, max(len(result.Files)-1, 0))
+		} else {
+			v.Selected = min(v.Selected+1, max(len(v.Searches)-1, 0))
+		}
+	case "r", "R":
+		if !v.Running {
+			v.Running, v.Open = true, false
+			return m, tea.Batch(runSavedSearchesCmd(v.Searches), tickCmd())
+		}
+	case "x", "delete", "backspace":
+		// Forget the selected search; its files are left alone.
+		if v.Open || v.Selected >= len(v.Searches) {
+			return m, nil
+		}
+		name := v.Searches[v.Selected].Name
+		searches := append(append([]savedSearch{}, v.Searches[:v.Selected]...), v.Searches[v.Selected+1:]...)
+		if err := writeSavedSearches(searches); err != nil {
+			m.status = fmt.Sprintf("Unable to remove search: %v", err)
+			return m, nil
+		}
+		v.Searches = searches
+		v.Selected = min(v.Selected, max(len(searches)-1, 0))
+		m.status = fmt.Sprintf("Removed saved search %q", name)
+		if len(searches) == 0 {
+			m.searches = nil
+		}
+	case "enter", "right", "l":
+		if v.Running || !hasResult {
+			return m, nil
+		}
+		if !v.Open {
+			if len(result.Files) > 0 {
+				v.Open, v.File = true, 0
+			}
+			return m, nil
+		}
+		// Jump to the folder holding the file.
+		file := result.Files[min(v.File, len(result.Files)-1)]
+		m.searches = nil
+		return m.openDir(filepath.Dir(file.Path))
+	}
+	return m, nil
+}
+
+// renderSearches lists saved searches with live counts, or the open search's files.
+func (m model) renderSearches() string {
+	v := m.searches
+	var b strings.Builder
+	if v.Running {
+		fmt.Fprintf(&b, "%s%s%s%s Running %d saved searches...\n",
+			colorCyan, colorBold, spinnerFrames[m.spinner], colorReset, len(v.Searches))
+		return b.String()
+	}
+	viewport := calculateViewport(m.height, true)
+
+	if result, ok := v.selectedResult(); ok && v.Open {
+		search := v.Searches[v.Selected]
+		fmt.Fprintf(&b, "%s%s:%s %d files, %s%s%s  %s(%s)%s\n\n",
+			colorCyan, search.Name, colorReset, result.Count, colorYellow, humanizeBytes(result.Total), colorReset,
+			colorGray, result.Source, colorReset)
+		nameWidth := calculateNameWidth(m.width) + 20
+		start := max(0, v.File-viewport+1)
+		for i := start; i < min(len(result.Files), start+viewport); i++ {
+			file := result.Files[i]
+			prefix, color := "   ", ""
+			if i == v.File {
+				prefix, color = fmt.Sprintf(" %s%s▶%s ", colorCyan, colorBold, colorReset), colorCyan
+			}
+			fmt.Fprintf(&b, "%s%s%s%s  %10s  %s%s%s\n",
+				prefix, color, padName(trimNameWithWidth(displayPath(file.Path), nameWidth), nameWidth), colorReset,
+				humanizeBytes(file.Size), colorGray, file.ModTime.Format("2006-01-02"), colorReset)
+		}
+		if result.Count > len(result.Files) {
+			fmt.Fprintf(&b, "   %s… %d smaller matches not listed%s\n", colorGray, result.Count-len(result.Files), colorReset)
+		}
+		fmt.Fprintln(&b)
+		fmt.Fprintf(&b, "%s↑↓ | Enter Show in folder | M/← Back | Q Quit%s\n", colorGray, colorReset)
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "%sSaved searches:%s %d\n\n", colorCyan, colorReset, len(v.Searches))
+	start := max(0, v.Selected-viewport+1)
+	for i := start; i < min(len(v.Searches), start+viewport); i++ {
+		search := v.Searches[i]
+		prefix, color := "   ", ""
+		if i == v.Selected {
+			prefix, color = fmt.Sprintf(" %s%s▶%s ", colorCyan, colorBold, colorReset), colorCyan
+		}
+		summary := colorGray + "not run" + colorReset
+		if result, ok := v.Results[search.Name]; ok && result.Err != nil {
+			summary = fmt.Sprintf("%s%v%s", colorGray, result.Err, colorReset)
+		} else if ok {
+			summary = fmt.Sprintf("%s%6s files%s %10s", colorYellow, formatNumber(int64(result.Count)), colorReset, humanizeBytes(result.Total))
+		}
+		query := ""
+		if search.Name != search.Query {
+			query = colorGray + search.Query + colorReset
+		}
+		fmt.Fprintf(&b, "%s%s%-24s%s %s  %s\n", prefix, color, search.Name, colorReset, summary, query)
+	}
+
+	fmt.Fprintln(&b)
+	fmt.Fprintf(&b, "%s↑↓ | Enter Results | R Refresh | X Forget | M/← Back | Q Quit%s\n", colorGray, colorReset)
+	return b.String()
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"sync"
+)
+
+// scanControl holds the cancel func of the scan in flight, shared across model copies.
+type scanControl struct {
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	gen    uint64
+}
+
+// start returns a context for a new scan and a func to call once it returns.
+func (c *scanControl) start() (context.Context, func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	if c == nil {
+		return ctx, cancel
+	}
+	c.mu.Lock()
+	c.gen++
+	gen := c.gen
+	c.cancel = cancel
+	c.mu.Unlock()
+	return ctx, func() {
+		cancel()
+		c.mu.Lock()
+		// A newer scan may have replaced this one; leave its cancel in place.
+		if c.gen == gen {
+			c.cancel = nil
+		}
+		c.mu.Unlock()
+	}
+}
+
+// running reports whether a scan started since the last stop is still in flight.
+func (c *scanControl) running() bool {
+	if c == nil {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.cancel != nil
+}
+
+// stop cancels the scan in flight, reporting whether there was one.
+func (c *scanControl) stop() bool {
+	if c == nil {
+		return false
+	}
+	c.mu.Lock()
+	cancel := c.cancel
+	c.cancel = nil
+	c.mu.Unlock()
+	if cancel == nil {
+		return false
+	}
+	cancel()
+	return true
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+const (
+	// scanHistoryLimit is how many snapshots of one folder are kept.
+	scanHistoryLimit = 30
+	// scanHistoryMinGap folds rescans closer together than this into one snapshot, so a
+	// burst of refreshes does not push last week's snapshot out.
+	scanHistoryMinGap = time.Hour
+)
+
+// scanSnapshot is the size of a folder and each listed child at one point in time.
+type scanSnapshot struct {
+	At        time.Time
+	TotalSize int64
+	Sizes     map[string]int64 // Child path to size
+	Dirs      map[string]bool  // Child paths that are folders
+}
+
+// growthDelta is how much one child changed between two snapshots.
+type growthDelta struct {
+	Path   string
+	IsDir  bool
+	Before int64 // Zero when the child is new
+	After  int64 // Zero when the child is gone
+}
+
+func (d growthDelta) Delta() int64 { return d.After - d.Before }
+
+func snapshotFromResult(result scanResult, at time.Time) scanSnapshot {
+	snapshot := scanSnapshot{At: at, TotalSize: result.TotalSize, Sizes: make(map[string]int64), Dirs: make(map[string]bool)}
+	for _, entry := range result.Entries {
+		snapshot.Sizes[entry.Path] = entry.Size
+		if entry.IsDir {
+			snapshot.Dirs[entry.Path] = true
+		}
+	}
+	return snapshot
+}
+
+func getHistoryPath(path string) (string, error) {
+	cacheDir, err := getCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, fmt.Sprintf("%x.history", xxhash.Sum64String(path))), nil
+}
+
+// loadScanHistory returns the snapshots of path, oldest first.
+func loadScanHistory(path string) ([]scanSnapshot, error) {
+	historyPath, err := getHistoryPath(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := readCacheFile(historyPath)
+	if err != nil {
+		return nil, err
+	}
+	var snapshots []scanSnapshot
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&snapshots); err != nil {
+		return nil, err
+	}
+	return snapshots, nil
+}
+
+// appendSnapshot adds snapshot to history, replacing the latest when it is too recent
+// to be worth keeping apart, and drops the oldest past scanHistoryLimit.
+func appendSnapshot(history []scanSnapshot, snapshot scanSnapshot) []scanSnapshot {
+	if n := len(history); n > 0 && snapshot.At.Sub(history[n-1].At) < scanHistoryMinGap {
+		history = history[:n-1]
+	}
+	history = append(history, snapshot)
+	if len(history) > scanHistoryLimit {
+		history = history[len(history)-scanHistoryLimit:]
+	}
+	return history
+}
+
+// recordScanSnapshot adds a finished scan of path to its history.
+func recordScanSnapshot(path string, result scanResult) error {
+	history, _ := loadScanHistory(path)
+	return saveScanHistory(path, appendSnapshot(history, snapshotFromResult(result, time.Now())))
+}
+
+func saveScanHistory(path string, history []scanSnapshot) error {
+	historyPath, err := getHistoryPath(path)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(history); err != nil {
+		return err
+	}
+	return writeCacheFile(historyPath, buf.Bytes())
+}
+
+// diffSnapshots lists every child that changed between before and after, largest growth
+// first and largest shrink last. Snapshots hold only the listed children, so a child
+// missing from after counts as gone only when it is no longer on disk.
+func diffSnapshots(before, after scanSnapshot) []growthDelta {
+	var deltas []growthDelta
+	for path, size := range after.Sizes {
+		if old := before.Sizes[path]; old != size {
+			deltas = append(deltas, growthDelta{Path: path, IsDir: after.Dirs[path], Before: old, After: size})
+		}
+	}
+	for path, size := range before.Sizes {
+		if _, ok := after.Sizes[path]; ok || size == 0 {
+			continue
+		}
+		if _, err := os.Lstat(path); os.IsNotExist(err) {
+			deltas = append(deltas, growthDelta{Path: path, IsDir: before.Dirs[path], Before: size})
+		}
+	}
+	sortGrowthDeltas(deltas, false)
+	return deltas
+}
+
+// sortGrowthDeltas orders by growth, or by size of the change either way when byChange is set.
+func sortGrowthDeltas(deltas []growthDelta, byChange bool) {
+	sort.Slice(deltas, func(a, b int) bool {
+		da, db := deltas[a].Delta(), deltas[b].Delta()
+		if byChange {
+			da, db = max(da, -da), max(db, -db)
+		}
+		if da != db {
+			return da > db
+		}
+		return deltas[a].Path < deltas[b].Path
+	})
+}
+
+// formatDelta signs a size change: "+1.2 GB", "-300 MB".
+func formatDelta(delta int64) string {
+	if delta < 0 {
+		return "-" + humanizeBytes(-delta)
+	}
+	return "+" + humanizeBytes(delta)
+}
+
+// runDiffCommand is `mo analyze diff <path>`: what changed between the last two scans.
+func runDiffCommand(w io.Writer, path string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	history, err := loadScanHistory(abs)
+	if err != nil || len(history) < 2 {
+		return fmt.Errorf("%s needs two scans to compare; open it in mo analyze again later", displayPath(abs))
+	}
+	before, after := history[len(history)-2], history[len(history)-1]
+	fmt.Fprintf(w, "%s: %s → %s (%s) between %s and %s\n", displayPath(abs),
+		humanizeBytes(before.TotalSize), humanizeBytes(after.TotalSize), formatDelta(after.TotalSize-before.TotalSize),
+		before.At.Format("2006-01-02 15:04"), after.At.Format("2006-01-02 15:04"))
+	deltas := diffSnapshots(before, after)
+	if len(deltas) == 0 {
+		fmt.Fprintln(w, "Nothing changed")
+		return nil
+	}
+	fmt.Fprintf(w, "%11s %10s %10s  %s\n", "CHANGE", "BEFORE", "AFTER", "NAME")
+	for _, delta := range deltas {
+		name := sanitizeName(filepath.Base(delta.Path))
+		if delta.IsDir {
+			name += "/"
+		}
+		fmt.Fprintf(w, "%11s %10s %10s  %s\n", formatDelta(delta.Delta()), humanizeBytes(delta.Before), humanizeBytes(delta.After), name)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAppendSnapshotFoldsRecentScans(t *testing.T) {
+	start := time.Now()
+	var history []scanSnapshot
+	history = appendSnapshot(history, scanSnapshot{At: start, TotalSize: 1})
+	history = appendSnapshot(history, scanSnapshot{At: start.Add(10 * time.Minute), TotalSize: 2})
+	if len(history) != 1 || history[0].TotalSize != 2 {
+		t.Fatalf("a rescan minutes later should replace the latest: %+v", history)
+	}
+	for i := 0; i < scanHistoryLimit+5; i++ {
+		history = appendSnapshot(history, scanSnapshot{At: start.Add(time.Duration(i+1) * 2 * scanHistoryMinGap), TotalSize: int64(i)})
+	}
+	if len(history) != scanHistoryLimit || history[len(history)-1].TotalSize != scanHistoryLimit+4 {
+		t.Fatalf("history should keep the newest %d snapshots, got %d", scanHistoryLimit, len(history))
+	}
+}
+
+func TestDiffSnapshotsAndCommand(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	root := filepath.Join(home, "work")
+	kept := filepath.Join(root, "kept")
+	writeFileWithSize(t, filepath.Join(kept, "a"), 10)
+	grown, gone, fresh := filepath.Join(root, "grown"), filepath.Join(root, "gone"), filepath.Join(root, "fresh")
+
+	before := scanResult{TotalSize: 300, Entries: []dirEntry{
+		{Path: grown, Size: 100, IsDir: true}, {Path: gone, Size: 150}, {Path: kept, Size: 50, IsDir: true},
+	}}
+	// kept drops out of the listing but is still on disk, so it is not reported gone.
+	after := scanResult{TotalSize: 500, Entries: []dirEntry{
+		{Path: grown, Size: 400, IsDir: true}, {Path: fresh, Size: 20},
+	}}
+	deltas := diffSnapshots(snapshotFromResult(before, time.Now()), snapshotFromResult(after, time.Now()))
+	if len(deltas) != 3 || deltas[0].Path != grown || deltas[1].Path != fresh || deltas[2].Path != gone || deltas[2].Delta() != -150 {
+		t.Fatalf("unexpected deltas: %+v", deltas)
+	}
+
+	var out bytes.Buffer
+	if err := runDiffCommand(&out, root); err == nil {
+		t.Fatalf("a folder without two scans should not diff")
+	}
+	// An earlier scan two days ago, then today's.
+	if err := saveScanHistory(root, []scanSnapshot{snapshotFromResult(before, time.Now().Add(-48*time.Hour))}); err != nil {
+		t.Fatalf("saveScanHistory: %v", err)
+	}
+	if err := recordScanSnapshot(root, after); err != nil {
+		t.Fatalf("recordScanSnapshot: %v", err)
+	}
+	if loaded, err := loadScanHistory(root); err != nil || len(loaded) != 2 {
+		t.Fatalf("loadScanHistory = %d snapshots, %v", len(loaded), err)
+	}
+	if err := runDiffCommand(&out, root); err != nil {
+		t.Fatalf("runDiffCommand: %v", err)
+	}
+	if text := out.String(); !strings.Contains(text, "grown/") || !strings.Contains(text, "gone") || !strings.Contains(text, "+") {
+		t.Fatalf("unexpected diff output:\n%s", text)
+	}
+}
@@ -23,16 +23,20 @@ import (
 
 var scanGroup singleflight.Group
 
-func scanPathConcurrent(root string, filesScanned, dirsScanned, bytesScanned *int64, currentPath *string) (scanResult, error) {
+func scanPathConcurrent(ctx context.Context, root string, filesScanned, dirsScanned, bytesScanned *int64, currentPath *string, partial *partialScan) (scanResult, error) {
 	children, err := os.ReadDir(root)
 	if err != nil {
 		return scanResult{}, err
 	}
+	partial.reset(root)
+	clearDeniedUnder(root)
+	// Small children first: they finish quickly and stream into the UI.
+	orderByScanCost(children)
 
 	var total int64
 
 	// Keep Top N heaps.
-	entriesHeap := &entryHeap{}
+	entriesHeap := newEntryHeap(rankScorer())
 	heap.Init(entriesHeap)
 
 	largeFilesHeap := &largeFileHeap{}
@@ -46,6 +50,9 @@ func scanPathConcurrent(root string, filesScanned, dirsScanned, bytesScanned *in
 	if numWorkers > maxWorkers {
 		numWorkers = maxWorkers
 	}
+	if niceMode {
+		numWorkers = min(numWorkers, nicePoolLimit)
+	}
 	if numWorkers > len(children) {
 		numWorkers = len(children)
 	}
@@ -60,16 +67,25 @@ func scanPathConcurrent(root string, filesScanned, dirsScanned, bytesScanned *in
 	largeFileChan := make(chan fileEntry, maxLargeFiles*2)
 
 	var collectorWg sync.WaitGroup
+	var omitted int
+	var omittedSize int64
 	collectorWg.Add(2)
 	go func() {
 		defer collectorWg.Done()
+		limit := entryLimit()
 		for entry := range entryChan {
-			if entriesHeap.Len() < maxEntries {
+			partial.add(entry)
+			if entriesHeap.Len() < limit {
 				heap.Push(entriesHeap, entry)
-			} else if entry.Size > (*entriesHeap)[0].Size {
-				heap.Pop(entriesHeap)
+				continue
+			}
+			dropped := entry
+			if entriesHeap.outranksMin(entry) {
+				dropped = heap.Pop(entriesHeap).(dirEntry)
 				heap.Push(entriesHeap, entry)
 			}
+			omitted++
+			omittedSize += dropped.Size
 		}
 	}()
 	go func() {
@@ -84,12 +100,32 @@ func scanPathConcurrent(root string, filesScanned, dirsScanned, bytesScanned *in
 		}
 	}()
 
+	// Case collisions only matter on case-sensitive volumes.
+	checkCase := isCaseSensitiveVolume(root)
+	rootCollisions := make(map[string]bool)
+	if checkCase {
+		clearCaseConflictsUnder(root)
+		for _, name := range findCaseCollisions(dirEntryNames(children)) {
+			rootCollisions[name] = true
+		}
+	}
+
+	// Hard links and APFS clones are counted once per scan, wherever the first copy is found.
+	links := newSharedSet()
+
 	isRootDir := root == "/"
 	home := os.Getenv("HOME")
 	isHomeDir := home != "" && root == home
 
 	for _, child := range children {
+		// Cancelled: launch nothing new, let running children wind down.
+		if ctx.Err() != nil {
+			break
+		}
 		fullPath := filepath.Join(root, child.Name())
+		if isExcludedName(child.Name()) {
+			continue
+		}
 
 		// Skip symlinks to avoid following unexpected targets.
 		if child.Type()&fs.ModeSymlink != 0 {
@@ -102,6 +138,7 @@ func scanPathConcurrent(root string, filesScanned, dirsScanned, bytesScanned *in
 			// Count link size only to avoid double-counting targets.
 			info, err := child.Info()
 			if err != nil {
+				recordDenied(fullPath, err)
 				continue
 			}
 			size := getActualFileSize(fullPath, info)
@@ -127,6 +164,35 @@ func scanPathConcurrent(root string, filesScanned, dirsScanned, bytesScanned *in
 				continue
 			}
 
+			strategy := scanStrategyFor(fullPath)
+			switch strategy {
+			case strategySkip:
+				entryChan <- dirEntry{Name: child.Name(), Path: fullPath, IsDir: true, Files: -1, Dirs: -1, Strategy: strategy}
+				continue
+			case strategyDu, strategyEstimate:
+				wg.Add(1)
+				go func(name, path string) {
+					defer wg.Done()
+					sem <- struct{}{}
+					defer func() { <-sem }()
+
+					var size int64
+					if strategy == strategyEstimate {
+						size = estimateDirSize(path, estimateMaxDepth)
+					} else if duSize, err := getDirectorySizeFromDuContext(ctx, path, ""); err == nil {
+						size = duSize
+					}
+					if ctx.Err() != nil {
+						return
+					}
+					atomic.AddInt64(&total, size)
+					atomic.AddInt64(dirsScanned, 1)
+
+					entryChan <- dirEntry{Name: name, Path: path, Size: size, IsDir: true, Files: -1, Dirs: -1, Strategy: strategy}
+				}(child.Name(), fullPath)
+				continue
+			}
+
 			// ~/Library is scanned separately; reuse cache when possible.
 			if isHomeDir && child.Name() == "Library" {
 				wg.Add(1)
@@ -135,39 +201,57 @@ func scanPathConcurrent(root string, filesScanned, dirsScanned, bytesScanned *in
 					sem <- struct{}{}
 					defer func() { <-sem }()
 
-					var size int64
+					totals := dirTotals{Files: -1, Dirs: -1}
 					if cached, err := loadStoredOverviewSize(path); err == nil && cached > 0 {
-						size = cached
+						totals.Size = cached
 					} else if cached, err := loadCacheFromDisk(path); err == nil {
-						size = cached.TotalSize
+						totals.Size = cached.TotalSize
 					} else {
-						size = calculateDirSizeConcurrent(path, largeFileChan, filesScanned, dirsScanned, bytesScanned, currentPath)
+						totals = calculateDirSizeConcurrent(ctx, path, checkCase, links, largeFileChan, filesScanned, dirsScanned, bytesScanned, currentPath)
 					}
-					atomic.AddInt64(&total, size)
+					if ctx.Err() != nil {
+						return
+					}
+					atomic.AddInt64(&total, totals.Size)
 					atomic.AddInt64(dirsScanned, 1)
 
 					entryChan <- dirEntry{
 						Name:       name,
 						Path:       path,
-						Size:       size,
+						Size:       totals.Size,
+						Apparent:   totals.Apparent,
 						IsDir:      true,
 						LastAccess: time.Time{},
+						Files:      totals.Files,
+						Dirs:       totals.Dirs,
+						Shared:     totals.Shared,
 					}
 				}(child.Name(), fullPath)
 				continue
 			}
 
 			// Folded dirs: fast size without expanding.
-			if shouldFoldDirWithPath(child.Name(), fullPath) {
+			if strategy != strategyWalk && shouldFoldDirWithPath(child.Name(), fullPath) {
 				wg.Add(1)
 				go func(name, path string) {
 					defer wg.Done()
 					sem <- struct{}{}
 					defer func() { <-sem }()
 
-					size, err := getDirectorySizeFromDu(path)
-					if err != nil || size <= 0 {
-						size = calculateDirSizeFast(path, filesScanned, dirsScanned, bytesScanned, currentPath)
+					var size int64
+					sizing := strategyDefault
+					if batteryPower.Load() {
+						// On battery, sample instead of forking du.
+						size, sizing = estimateDirSize(path, estimateMaxDepth), strategyEstimate
+					} else {
+						var err error
+						size, err = getDirectorySizeFromDuContext(ctx, path, "")
+						if ctx.Err() != nil {
+							return
+						}
+						if err != nil || size <= 0 {
+							size = calculateDirSizeFast(path, filesScanned, dirsScanned, bytesScanned, currentPath)
+						}
 					}
 					atomic.AddInt64(&total, size)
 					atomic.AddInt64(dirsScanned, 1)
@@ -178,6 +262,10 @@ func scanPathConcurrent(root string, filesScanned, dirsScanned, bytesScanned *in
 						Size:       size,
 						IsDir:      true,
 						LastAccess: time.Time{},
+						Files:      -1,
+						Dirs:       -1,
+						Bundle:     isBundle(path),
+						Strategy:   sizing,
 					}
 				}(child.Name(), fullPath)
 				continue
@@ -189,16 +277,28 @@ func scanPathConcurrent(root string, filesScanned, dirsScanned, bytesScanned *in
 				sem <- struct{}{}
 				defer func() { <-sem }()
 
-				size := calculateDirSizeConcurrent(path, largeFileChan, filesScanned, dirsScanned, bytesScanned, currentPath)
-				atomic.AddInt64(&total, size)
+				totals := calculateDirSizeConcurrent(ctx, path, checkCase, links, largeFileChan, filesScanned, dirsScanned, bytesScanned, currentPath)
+				// A cancelled folder is incomplete; keep it out of the partial results.
+				if ctx.Err() != nil {
+					return
+				}
+				atomic.AddInt64(&total, totals.Size)
 				atomic.AddInt64(dirsScanned, 1)
 
 				entryChan <- dirEntry{
-					Name:       name,
-					Path:       path,
-					Size:       size,
-					IsDir:      true,
-					LastAccess: time.Time{},
+					Name:         name,
+					Path:         path,
+					Size:         totals.Size,
+					Apparent:     totals.Apparent,
+					IsDir:        true,
+					LastAccess:   time.Time{},
+					CaseConflict: rootCollisions[name] || (checkCase && hasCaseConflictUnder(path)),
+					Files:        totals.Files,
+					Dirs:         totals.Dirs,
+					Shared:       totals.Shared,
+					Bundle:       isBundle(path),
+					Cloud:        totals.Cloud,
+					Dataless:     totals.Dataless,
 				}
 			}(child.Name(), fullPath)
 			continue
@@ -206,24 +306,36 @@ func scanPathConcurrent(root string, filesScanned, dirsScanned, bytesScanned *in
 
 		info, err := child.Info()
 		if err != nil {
+			recordDenied(fullPath, err)
 			continue
 		}
-		// Actual disk usage for sparse/cloud files.
-		size := getActualFileSize(fullPath, info)
+		// Actual disk usage for sparse/cloud files, minus blocks already counted via links or clones.
+		size, shared, counted := links.account(fullPath, info)
 		atomic.AddInt64(&total, size)
 		atomic.AddInt64(filesScanned, 1)
 		atomic.AddInt64(bytesScanned, size)
 
+		var cloud int64
+		dataless := datalessInfo(info)
+		if dataless {
+			cloud, counted = info.Size(), false
+		}
 		entryChan <- dirEntry{
-			Name:       child.Name(),
-			Path:       fullPath,
-			Size:       size,
-			IsDir:      false,
-			LastAccess: getLastAccessTimeFromInfo(info),
+			Name:         child.Name(),
+			Path:         fullPath,
+			Size:         size,
+			Apparent:     apparentFileSize(info, counted),
+			IsDir:        false,
+			LastAccess:   getLastAccessTimeFromInfo(info),
+			CaseConflict: rootCollisions[child.Name()],
+			Files:        1,
+			Shared:       shared,
+			Cloud:        cloud,
+			Dataless:     dataless,
 		}
 		// Track large files only.
-		if !shouldSkipFileForLargeTracking(fullPath) && size >= minLargeFileSize {
-			largeFileChan <- fileEntry{Name: child.Name(), Path: fullPath, Size: size}
+		if counted && !shouldSkipFileForLargeTracking(fullPath) && size >= minLargeFileSize {
+			largeFileChan <- fileEntry{Name: child.Name(), Path: fullPath, Size: size, Apparent: info.Size(), ModTime: info.ModTime(), Created: birthTime(info)}
 		}
 	}
 
@@ -233,6 +345,9 @@ func scanPathConcurrent(root string, filesScanned, dirsScanned, bytesScanned *in
 	close(entryChan)
 	close(largeFileChan)
 	collectorWg.Wait()
+	if err := ctx.Err(); err != nil {
+		return scanResult{}, err
+	}
 
 	// Convert heaps to sorted slices (descending).
 	entries := make([]dirEntry, entriesHeap.Len())
@@ -251,9 +366,11 @@ func scanPathConcurrent(root string, filesScanned, dirsScanned, bytesScanned *in
 	}
 
 	return scanResult{
-		Entries:    entries,
-		LargeFiles: largeFiles,
-		TotalSize:  total,
+		Entries:     entries,
+		LargeFiles:  largeFiles,
+		TotalSize:   total,
+		Omitted:     omitted,
+		OmittedSize: omittedSize,
 	}, nil
 }
 
@@ -276,11 +393,6 @@ func shouldFoldDirWithPath(name, path string) bool {
 	return false
 }
 
-func shouldSkipFileForLargeTracking(path string) bool {
-	ext := strings.ToLower(filepath.Ext(path))
-	return skipExtensions[ext]
-}
-
 // calculateDirSizeFast performs concurrent dir sizing using os.ReadDir.
 func calculateDirSizeFast(root string, filesScanned, dirsScanned, bytesScanned *int64, currentPath *string) int64 {
 	var total int64
@@ -307,14 +419,21 @@ func calculateDirSizeFast(root string, filesScanned, dirsScanned, bytesScanned *
 			*currentPath = dirPath
 		}
 
+		if isDataless(dirPath) {
+			return
+		}
 		entries, err := os.ReadDir(dirPath)
 		if err != nil {
+			recordDenied(dirPath, err)
 			return
 		}
 
 		var localBytes, localFiles int64
 
 		for _, entry := range entries {
+			if isExcludedName(entry.Name()) {
+				continue
+			}
 			if entry.IsDir() {
 				wg.Add(1)
 				subDir := filepath.Join(dirPath, entry.Name())
@@ -381,21 +500,29 @@ func findLargeFilesWithSpotlight(root string, minSize int64) []fileEntry {
 			continue
 		}
 
+		if isExcludedPath(root, line) {
+			continue
+		}
+
 		info, err := os.Lstat(line)
 		if err != nil {
 			continue
 		}
 
-		if info.IsDir() || info.Mode()&os.ModeSymlink != 0 {
+		// Spotlight sizes cloud placeholders by their logical length; they take no space here.
+		if info.IsDir() || info.Mode()&os.ModeSymlink != 0 || datalessInfo(info) {
 			continue
 		}
 
 		// Actual disk usage for sparse/cloud files.
 		actualSize := getActualFileSize(line, info)
 		files = append(files, fileEntry{
-			Name: filepath.Base(line),
-			Path: line,
-			Size: actualSize,
+			Name:     filepath.Base(line),
+			Path:     line,
+			Size:     actualSize,
+			Apparent: info.Size(),
+			ModTime:  info.ModTime(),
+			Created:  birthTime(info),
 		})
 	}
 
@@ -422,13 +549,64 @@ func isInFoldedDir(path string) bool {
 	return false
 }
 
-func calculateDirSizeConcurrent(root string, largeFileChan chan<- fileEntry, filesScanned, dirsScanned, bytesScanned *int64, currentPath *string) int64 {
+// dirTotals is what calculateDirSizeConcurrent measures beneath a directory.
+type dirTotals struct {
+	Size     int64 // Allocated bytes, the scan's notion of size
+	Apparent int64 // Logical bytes; du-sized subfolders add their allocated size
+	Files    int64
+	Dirs     int64
+	Shared   int64 // Bytes also referenced by hard links or APFS clones
+	Cloud    int64 // Logical bytes of dataless files, which take no local space
+	Dataless bool  // Root itself is a cloud placeholder and was not enumerated
+}
+
+// calculateDirSizeConcurrent measures root: its size, the number of files and folders counted
+// under it and the bytes it shares with other files through hard links or APFS clones. Folded
+// subdirectories are sized with du, so their contents are not part of the counts; so is root
+// itself when it has more children than the auto-fold limit, with -1 counts. Shared blocks
+// are sized only at the first copy recorded in links. Cloud placeholders are measured from
+// metadata and never opened, since listing a dataless folder downloads it.
+func calculateDirSizeConcurrent(ctx context.Context, root string, checkCase bool, links *sharedSet, largeFileChan chan<- fileEntry, filesScanned, dirsScanned, bytesScanned *int64, currentPath *string) dirTotals {
+	if isDataless(root) {
+		return dirTotals{Files: -1, Dirs: -1, Dataless: true}
+	}
+	// Hold a pool slot only for this directory's own I/O, never while waiting on children.
+	scanPool.acquire()
+	if ctx.Err() != nil {
+		scanPool.release()
+		return dirTotals{}
+	}
 	children, err := os.ReadDir(root)
 	if err != nil {
-		return 0
+		scanPool.release()
+		recordDenied(root, err)
+		return dirTotals{}
 	}
 
-	var total int64
+	if autoFolds(root, len(children)) {
+		scanPool.release()
+		if batteryPower.Load() {
+			size := estimateDirSize(root, estimateMaxDepth)
+			atomic.AddInt64(bytesScanned, size)
+			return dirTotals{Size: size, Apparent: size, Files: -1, Dirs: -1}
+		}
+		size, err := getDirectorySizeFromDuContext(ctx, root, "")
+		if ctx.Err() != nil {
+			return dirTotals{}
+		}
+		if err != nil || size <= 0 {
+			size = calculateDirSizeFast(root, filesScanned, dirsScanned, bytesScanned, currentPath)
+		} else {
+			atomic.AddInt64(bytesScanned, size)
+		}
+		return dirTotals{Size: size, Apparent: size, Files: -1, Dirs: -1}
+	}
+
+	if checkCase && len(findCaseCollisions(dirEntryNames(children))) > 0 {
+		recordCaseConflict(root)
+	}
+
+	var total, apparent, files, dirs, shared, cloud int64
 	var wg sync.WaitGroup
 
 	// Limit concurrent subdirectory scans.
@@ -439,28 +617,56 @@ func calculateDirSizeConcurrent(root string, largeFileChan chan<- fileEntry, fil
 	sem := make(chan struct{}, maxConcurrent)
 
 	for _, child := range children {
+		if ctx.Err() != nil {
+			break
+		}
 		fullPath := filepath.Join(root, child.Name())
+		if isExcludedName(child.Name()) {
+			continue
+		}
 
 		if child.Type()&fs.ModeSymlink != 0 {
 			info, err := child.Info()
 			if err != nil {
+				recordDenied(fullPath, err)
 				continue
 			}
 			size := getActualFileSize(fullPath, info)
 			total += size
+			atomic.AddInt64(&apparent, size)
+			atomic.AddInt64(&files, 1)
 			atomic.AddInt64(filesScanned, 1)
 			atomic.AddInt64(bytesScanned, size)
 			continue
 		}
 
 		if child.IsDir() {
-			if shouldFoldDirWithPath(child.Name(), fullPath) {
+			strategy := scanStrategyFor(fullPath)
+			if strategy == strategySkip {
+				continue
+			}
+			atomic.AddInt64(&dirs, 1)
+			fold := strategy != strategyWalk && shouldFoldDirWithPath(child.Name(), fullPath)
+			if strategy == strategyEstimate || (fold && batteryPower.Load()) {
 				wg.Add(1)
 				go func(path string) {
 					defer wg.Done()
-					size, err := getDirectorySizeFromDu(path)
+					size := estimateDirSize(path, estimateMaxDepth)
+					atomic.AddInt64(&total, size)
+					atomic.AddInt64(&apparent, size)
+					atomic.AddInt64(bytesScanned, size)
+					atomic.AddInt64(dirsScanned, 1)
+				}(fullPath)
+				continue
+			}
+			if strategy == strategyDu || fold {
+				wg.Add(1)
+				go func(path string) {
+					defer wg.Done()
+					size, err := getDirectorySizeFromDuContext(ctx, path, "")
 					if err == nil && size > 0 {
 						atomic.AddInt64(&total, size)
+						atomic.AddInt64(&apparent, size)
 						atomic.AddInt64(bytesScanned, size)
 						atomic.AddInt64(dirsScanned, 1)
 					}
@@ -474,8 +680,13 @@ func calculateDirSizeConcurrent(root string, largeFileChan chan<- fileEntry, fil
 				sem <- struct{}{}
 				defer func() { <-sem }()
 
-				size := calculateDirSizeConcurrent(path, largeFileChan, filesScanned, dirsScanned, bytesScanned, currentPath)
-				atomic.AddInt64(&total, size)
+				sub := calculateDirSizeConcurrent(ctx, path, checkCase, links, largeFileChan, filesScanned, dirsScanned, bytesScanned, currentPath)
+				atomic.AddInt64(&total, sub.Size)
+				atomic.AddInt64(&apparent, sub.Apparent)
+				atomic.AddInt64(&files, max(sub.Files, 0)) // -1 when the folder folded itself
+				atomic.AddInt64(&dirs, max(sub.Dirs, 0))
+				atomic.AddInt64(&shared, sub.Shared)
+				atomic.AddInt64(&cloud, sub.Cloud)
 				atomic.AddInt64(dirsScanned, 1)
 			}(fullPath)
 			continue
@@ -483,16 +694,24 @@ func calculateDirSizeConcurrent(root string, largeFileChan chan<- fileEntry, fil
 
 		info, err := child.Info()
 		if err != nil {
+			recordDenied(fullPath, err)
 			continue
 		}
 
-		size := getActualFileSize(fullPath, info)
+		size, linked, counted := links.account(fullPath, info)
+		atomic.AddInt64(&shared, linked)
 		total += size
+		if datalessInfo(info) {
+			atomic.AddInt64(&cloud, info.Size())
+			counted = false
+		}
+		atomic.AddInt64(&apparent, apparentFileSize(info, counted))
+		atomic.AddInt64(&files, 1)
 		atomic.AddInt64(filesScanned, 1)
 		atomic.AddInt64(bytesScanned, size)
 
-		if !shouldSkipFileForLargeTracking(fullPath) && size >= minLargeFileSize {
-			largeFileChan <- fileEntry{Name: child.Name(), Path: fullPath, Size: size}
+		if counted && !shouldSkipFileForLargeTracking(fullPath) && size >= minLargeFileSize {
+			largeFileChan <- fileEntry{Name: child.Name(), Path: fullPath, Size: size, Apparent: info.Size(), ModTime: info.ModTime(), Created: birthTime(info)}
 		}
 
 		// Update current path occasionally to prevent UI jitter.
@@ -501,11 +720,12 @@ func calculateDirSizeConcurrent(root string, largeFileChan chan<- fileEntry, fil
 		}
 	}
 
+	scanPool.release()
 	wg.Wait()
-	return total
+	return dirTotals{Size: total, Apparent: apparent, Files: files, Dirs: dirs, Shared: shared, Cloud: cloud}
 }
 
-// measureOverviewSize calculates the size of a directory using multiple strategies.
+// measureOverviewSize calculates the size of a directory with the sizeProviders chain.
 // When scanning Home, it excludes ~/Library to avoid duplicate counting.
 func measureOverviewSize(path string) (int64, error) {
 	if path == "" {
@@ -521,33 +741,15 @@ func measureOverviewSize(path string) (int64, error) {
 		return 0, fmt.Errorf("cannot access path: %v", err)
 	}
 
-	// Determine if we should exclude ~/Library (when scanning Home)
-	home := os.Getenv("HOME")
-	excludePath := ""
-	if home != "" && path == home {
-		excludePath = filepath.Join(home, "Library")
-	}
-
-	if cached, err := loadStoredOverviewSize(path); err == nil && cached > 0 {
-		return cached, nil
-	}
-
-	if duSize, err := getDirectorySizeFromDuWithExclude(path, excludePath); err == nil && duSize > 0 {
-		_ = storeOverviewSize(path, duSize)
-		return duSize, nil
-	}
-
-	if logicalSize, err := getDirectoryLogicalSizeWithExclude(path, excludePath); err == nil && logicalSize > 0 {
-		_ = storeOverviewSize(path, logicalSize)
-		return logicalSize, nil
-	}
+	return measureWithProviders(path, overviewExcludePath(path))
+}
 
-	if cached, err := loadCacheFromDisk(path); err == nil {
-		_ = storeOverviewSize(path, cached.TotalSize)
-		return cached.TotalSize, nil
+// overviewExcludePath is ~/Library when measuring Home, which the overview lists on its own.
+func overviewExcludePath(path string) string {
+	if home := os.Getenv("HOME"); home != "" && path == home {
+		return filepath.Join(home, "Library")
 	}
-
-	return 0, fmt.Errorf("unable to measure directory size with fast methods")
+	return ""
 }
 
 func getDirectorySizeFromDu(path string) (int64, error) {
@@ -555,22 +757,48 @@ func getDirectorySizeFromDu(path string) (int64, error) {
 }
 
 func getDirectorySizeFromDuWithExclude(path string, excludePath string) (int64, error) {
+	return getDirectorySizeFromDuContext(context.Background(), path, excludePath)
+}
+
+// getDirectorySizeFromDuContext runs du like getDirectorySizeFromDuWithExclude, killing it
+// once parent is done. Only du's own timeout counts against the folder's du record.
+func getDirectorySizeFromDuContext(parent context.Context, path string, excludePath string) (int64, error) {
 	runDuSize := func(target string) (int64, error) {
 		if _, err := os.Stat(target); err != nil {
 			return 0, err
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), duTimeout)
+		if err := duDisabledFor(target); err != nil {
+			return 0, err
+		}
+		// A cloud placeholder takes no local space, and du would download it.
+		if isDataless(target) {
+			return 0, nil
+		}
+
+		// Size cloud folders in-process from metadata so du never enumerates them.
+		if isCloudPath(target) {
+			var files, dirs, bytes int64
+			return calculateDirSizeFast(target, &files, &dirs, &bytes, nil), nil
+		}
+
+		timeout := duTimeoutFor(target)
+		ctx, cancel := context.WithTimeout(parent, timeout)
 		defer cancel()
 
-		cmd := exec.CommandContext(ctx, "du", "-sk", target)
+		cmd := exec.CommandContext(ctx, "du", append(append([]string{"-sk"}, duExcludeArgs()...), target)...)
 		var stdout, stderr bytes.Buffer
 		cmd.Stdout = &stdout
 		cmd.Stderr = &stderr
 
-		if err := cmd.Run(); err != nil {
+		// Unreadable folders make du exit 1 after printing the size of the rest.
+		if err := cmd.Run(); err != nil && (ctx.Err() != nil || recordDuDenied(stderr.String()) == 0) {
+			if parent.Err() != nil {
+				return 0, fmt.Errorf("du stopped: %v", parent.Err())
+			}
 			if ctx.Err() == context.DeadlineExceeded {
-				return 0, fmt.Errorf("du timeout after %v", duTimeout)
+				recordDuFailure(target)
+				return 0, fmt.Errorf("du timeout after %v", timeout)
 			}
 			if stderr.Len() > 0 {
 				return 0, fmt.Errorf("du failed: %v (%s)", err, stderr.String())
@@ -588,6 +816,7 @@ func getDirectorySizeFromDuWithExclude(path string, excludePath string) (int64,
 		if kb <= 0 {
 			return 0, fmt.Errorf("du size invalid: %d", kb)
 		}
+		recordDuSuccess(target)
 		return kb * 1024, nil
 	}
 
@@ -614,10 +843,19 @@ func getDirectorySizeFromDuWithExclude(path string, excludePath string) (int64,
 }
 
 func getDirectoryLogicalSizeWithExclude(path string, excludePath string) (int64, error) {
+	return getDirectoryLogicalSizeContext(context.Background(), path, excludePath)
+}
+
+// getDirectoryLogicalSizeContext walks path like getDirectoryLogicalSizeWithExclude,
+// stopping early once ctx is done.
+func getDirectoryLogicalSizeContext(ctx context.Context, path string, excludePath string) (int64, error) {
 	var total int64
 	err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
 		if err != nil {
-			if os.IsPermission(err) {
+			if recordDenied(p, err) {
 				return filepath.SkipDir
 			}
 			return nil
@@ -655,6 +893,15 @@ func getActualFileSize(_ string, info fs.FileInfo) int64 {
 	return info.Size()
 }
 
+// apparentFileSize is the logical length of a file, what Finder and ls report. Like the
+// allocated size, a hard link after the first adds nothing.
+func apparentFileSize(info fs.FileInfo, counted bool) int64 {
+	if !counted {
+		return 0
+	}
+	return info.Size()
+}
+
 func getLastAccessTime(path string) time.Time {
 	info, err := os.Stat(path)
 	if err != nil {
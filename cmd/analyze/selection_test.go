@@ -0,0 +1,68 @@
+//go:build darwin
+
+package main
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSelectAllTogglesVisibleEntries(t *testing.T) {
+	m := newModel("/tmp/root", false)
+	m.entries = []dirEntry{
+		{Name: "a", Path: "/tmp/root/a", Size: 100},
+		{Name: "b", Path: "/tmp/root/b", Size: 200},
+	}
+
+	next, _ := m.updateKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	got := next.(model)
+	if len(got.multiSelected) != 2 {
+		t.Fatalf("expected both entries selected, got %v", got.multiSelected)
+	}
+	if got.status != "2 selected (300 B)" {
+		t.Fatalf("unexpected status %q", got.status)
+	}
+
+	next, _ = got.updateKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	if got = next.(model); len(got.multiSelected) != 0 {
+		t.Fatalf("second press should clear the selection, got %v", got.multiSelected)
+	}
+}
+
+func TestSelectAllLargeFiles(t *testing.T) {
+	m := newModel("/tmp/root", false)
+	m.showLargeFiles = true
+	m.largeFiles = []fileEntry{{Name: "x.dmg", Path: "/tmp/root/x.dmg", Size: 10}}
+
+	next, _ := m.updateKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	if got := next.(model); !got.largeMultiSelected["/tmp/root/x.dmg"] || len(got.multiSelected) != 0 {
+		t.Fatalf("expected only the large file selected, got %v / %v", got.largeMultiSelected, got.multiSelected)
+	}
+}
+
+func TestPinKeyMovesEntryAndUnpinRestoresSortOrder(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	resetPinsForTest()
+	t.Cleanup(resetPinsForTest)
+
+	m := newModel("/tmp/root", false)
+	m.entries = []dirEntry{
+		{Name: "src", Path: "/tmp/root/src", Size: 300},
+		{Name: "docs", Path: "/tmp/root/docs", Size: 200},
+		{Name: "build", Path: "/tmp/root/build", Size: 100},
+	}
+	m.selected = 2
+
+	next, _ := m.updateKey(keyMsgFor("P"))
+	m = next.(model)
+	if m.entries[0].Name != "build" || m.selected != 0 {
+		t.Fatalf("pinned entry should move to the top with the cursor, got %s at %d", m.entries[0].Name, m.selected)
+	}
+
+	next, _ = m.updateKey(keyMsgFor("P"))
+	m = next.(model)
+	if m.entries[0].Name != "src" || m.entries[2].Name != "build" || m.selected != 2 {
+		t.Fatalf("unpinned entry should return to its size position, got %+v at %d", m.entries, m.selected)
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"io/fs"
+	"sync"
+	"syscall"
+)
+
+// cloneCheckMinSize skips the clone lookup for files too small to matter.
+const cloneCheckMinSize = 32 << 10
+
+// cloneStat describes a file whose blocks may be shared with APFS clones.
+type cloneStat struct {
+	ID      uint64 // Clone stream shared by every copy
+	Private int64  // Bytes not shared with any other file
+}
+
+// sharedSet remembers multi-linked inodes and APFS clone streams seen during one scan,
+// so blocks referenced by several files are sized only once.
+type sharedSet struct {
+	mu     sync.Mutex
+	seen   map[fileIdentity]bool
+	clones map[fileIdentity]bool
+}
+
+func newSharedSet() *sharedSet {
+	return &sharedSet{seen: make(map[fileIdentity]bool), clones: make(map[fileIdentity]bool)}
+}
+
+// claim reports whether the file's bytes should be counted and whether it has other links.
+// Only the first link to an inode is counted; a nil set counts everything.
+func (s *sharedSet) claim(info fs.FileInfo) (counted, shared bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok || stat.Nlink < 2 {
+		return true, false
+	}
+	if s == nil {
+		return true, true
+	}
+	id := fileIdentity{dev: uint64(stat.Dev), ino: uint64(stat.Ino)}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.seen[id] {
+		return false, true
+	}
+	s.seen[id] = true
+	return true, true
+}
+
+// claimClone reports whether this is the first file seen with the given clone stream.
+func (s *sharedSet) claimClone(dev, cloneID uint64) bool {
+	if s == nil {
+		return true
+	}
+	id := fileIdentity{dev: dev, ino: cloneID}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.clones[id] {
+		return false
+	}
+	s.clones[id] = true
+	return true
+}
+
+// account returns the bytes a file adds to the scan total and the bytes it shares with
+// other files. A hard link after the first adds nothing; an APFS clone after the first
+// adds only its private (unshared) blocks.
+func (s *sharedSet) account(path string, info fs.FileInfo) (size, shared int64, counted bool) {
+	size = getActualFileSize(path, info)
+	counted, linked := s.claim(info)
+	if linked {
+		shared = size
+	}
+	if !counted {
+		return 0, shared, false
+	}
+	if size < cloneCheckMinSize {
+		return size, shared, true
+	}
+	clone, ok := cloneStatOf(path)
+	if !ok || clone.Private >= size {
+		return size, shared, true
+	}
+	shared = max(shared, size-clone.Private)
+	var dev uint64
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		dev = uint64(stat.Dev)
+	}
+	if !s.claimClone(dev, clone.ID) {
+		size = clone.Private
+	}
+	return size, shared, true
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestScanCountsHardLinksOnce(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	resetStrategiesForTest()
+	t.Cleanup(resetStrategiesForTest)
+
+	root := filepath.Join(home, "data")
+	original := filepath.Join(root, "first", "blob.bin")
+	writeFileWithSize(t, original, 64*1024)
+	writeFileWithSize(t, filepath.Join(root, "second", "own.bin"), 4096)
+	if err := os.Link(original, filepath.Join(root, "second", "blob.bin")); err != nil {
+		t.Skipf("hard links unsupported: %v", err)
+	}
+	if err := os.Link(original, filepath.Join(root, "top.bin")); err != nil {
+		t.Fatalf("link: %v", err)
+	}
+
+	info, err := os.Stat(original)
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	blob := getActualFileSize(original, info)
+	own, err := os.Stat(filepath.Join(root, "second", "own.bin"))
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+
+	var files, dirs, bytes int64
+	var current string
+	result, err := scanPathConcurrent(context.Background(), root, &files, &dirs, &bytes, &current, nil)
+	if err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+	if want := blob + getActualFileSize("", own); result.TotalSize != want {
+		t.Fatalf("total = %d; want %d with the linked blob counted once", result.TotalSize, want)
+	}
+	var sized int64
+	for _, entry := range result.Entries {
+		if entry.Shared != blob {
+			t.Fatalf("%s shared = %d; want %d", entry.Name, entry.Shared, blob)
+		}
+		sized += entry.Size
+	}
+	if sized != result.TotalSize {
+		t.Fatalf("entry sizes add up to %d, total is %d", sized, result.TotalSize)
+	}
+}
+
+func TestHardlinkSetClaim(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a")
+	writeFileWithSize(t, path, 10)
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	links := newSharedSet()
+	if counted, shared := links.claim(info); !counted || shared {
+		t.Fatalf("single link: counted=%v shared=%v", counted, shared)
+	}
+
+	if err := os.Link(path, filepath.Join(dir, "b")); err != nil {
+		t.Skipf("hard links unsupported: %v", err)
+	}
+	if info, err = os.Stat(path); err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	if counted, shared := links.claim(info); !counted || !shared {
+		t.Fatalf("first link: counted=%v shared=%v", counted, shared)
+	}
+	if counted, shared := links.claim(info); counted || !shared {
+		t.Fatalf("second link: counted=%v shared=%v", counted, shared)
+	}
+	var none *sharedSet
+	if counted, _ := none.claim(info); !counted {
+		t.Fatal("nil set should count every link")
+	}
+}
+
+func TestSharedSetClaimClone(t *testing.T) {
+	set := newSharedSet()
+	if !set.claimClone(1, 42) {
+		t.Fatal("first copy of a clone stream should be counted in full")
+	}
+	if set.claimClone(1, 42) {
+		t.Fatal("later copies should only count their private bytes")
+	}
+	if !set.claimClone(2, 42) {
+		t.Fatal("clone ids are per volume")
+	}
+}
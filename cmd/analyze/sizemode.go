@@ -0,0 +1,26 @@
+package main
+
+// apparentSize is the entry's logical size. Entries sized by du or loaded from older caches
+// only know their allocated size, and a logical size is never smaller than that.
+func (e dirEntry) apparentSize() int64 {
+	return max(e.Apparent, e.Size)
+}
+
+func (f fileEntry) apparentSize() int64 {
+	return max(f.Apparent, f.Size)
+}
+
+// sizeLabels formats the size a row leads with and, when it reads differently, the other one:
+// a 64 GB Docker.raw holding 12 GB of data shows "12.0 GB" with "64.0 GB logical".
+func sizeLabels(allocated, apparent int64, showApparent bool) (primary, other string) {
+	onDisk, logical := humanizeBytes(allocated), humanizeBytes(max(apparent, allocated))
+	if showApparent {
+		primary, other = logical, onDisk+" on disk"
+	} else {
+		primary, other = onDisk, logical+" logical"
+	}
+	if onDisk == logical {
+		other = ""
+	}
+	return primary, other
+}
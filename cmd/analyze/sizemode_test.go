@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestScanRecordsApparentSizeOfSparseFiles(t *testing.T) {
+	root := t.TempDir()
+	disk := filepath.Join(root, "vm", "Docker.raw")
+	if err := os.MkdirAll(filepath.Dir(disk), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	f, err := os.Create(disk)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	const logical = 64 << 20
+	if err := f.Truncate(logical); err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+	f.Close()
+	writeFileWithSize(t, filepath.Join(root, "notes.txt"), 100)
+
+	var files, dirs, bytes int64
+	var current string
+	result, err := scanPathConcurrent(context.Background(), root, &files, &dirs, &bytes, &current, nil)
+	if err != nil {
+		t.Fatalf("scanPathConcurrent: %v", err)
+	}
+	for _, entry := range result.Entries {
+		switch entry.Name {
+		case "vm":
+			if entry.apparentSize() != logical {
+				t.Fatalf("expected vm to be %d bytes apparent, got %d", logical, entry.Apparent)
+			}
+			if entry.Size >= logical {
+				t.Skipf("filesystem allocated the sparse file (%d bytes)", entry.Size)
+			}
+		case "notes.txt":
+			if entry.Size != 100 || entry.apparentSize() != 100 {
+				t.Fatalf("expected a plain file to be 100 bytes both ways, got %d/%d", entry.Size, entry.Apparent)
+			}
+		}
+	}
+}
+
+func TestSizeLabels(t *testing.T) {
+	primary, other := sizeLabels(12<<30, 64<<30, false)
+	if primary != humanizeBytes(12<<30) || other != humanizeBytes(64<<30)+" logical" {
+		t.Fatalf("allocated mode: got %q, %q", primary, other)
+	}
+	primary, other = sizeLabels(12<<30, 64<<30, true)
+	if primary != humanizeBytes(64<<30) || other != humanizeBytes(12<<30)+" on disk" {
+		t.Fatalf("apparent mode: got %q, %q", primary, other)
+	}
+	// Unknown apparent sizes (du-sized folders, old caches) fall back to the allocated size.
+	if _, other := sizeLabels(5<<20, 0, true); other != "" {
+		t.Fatalf("expected no second size when only the allocated size is known, got %q", other)
+	}
+}
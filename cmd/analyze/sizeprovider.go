@@ -0,0 +1,270 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// sizeProvider is one way of measuring an overview folder. measureOverviewSize tries
+// them in order and keeps the first number one produces.
+type sizeProvider struct {
+	Name    string
+	Timeout time.Duration // Zero leaves the limit to the provider, as du does
+	Store   bool          // Remember the result as the folder's overview size
+	Measure func(ctx context.Context, path, excludePath string) (int64, error)
+}
+
+var sizeProviders = []sizeProvider{
+	{Name: "cache", Timeout: time.Second, Measure: func(_ context.Context, path, _ string) (int64, error) {
+		return loadStoredOverviewSize(path)
+	}},
+	{Name: "sudo-du", Timeout: 5 * time.Minute, Store: true, Measure: func(ctx context.Context, path, excludePath string) (int64, error) {
+		return nonZeroSize(sudoDuSize(ctx, path, excludePath))
+	}},
+	{Name: "du", Store: true, Measure: func(ctx context.Context, path, excludePath string) (int64, error) {
+		return nonZeroSize(getDirectorySizeFromDuContext(ctx, path, excludePath))
+	}},
+	{Name: "walk", Timeout: 2 * time.Minute, Store: true, Measure: func(ctx context.Context, path, excludePath string) (int64, error) {
+		return nonZeroSize(getDirectoryLogicalSizeContext(ctx, path, excludePath))
+	}},
+	{Name: "scan-cache", Timeout: 5 * time.Second, Store: true, Measure: func(_ context.Context, path, _ string) (int64, error) {
+		cached, err := loadCacheFromDisk(path)
+		if err != nil {
+			return 0, err
+		}
+		return cached.TotalSize, nil
+	}},
+}
+
+func sizeProviderNames() []string {
+	names := make([]string, len(sizeProviders))
+	for i, provider := range sizeProviders {
+		names[i] = provider.Name
+	}
+	return names
+}
+
+// nonZeroSize treats an empty measurement as no answer, so the next provider gets a turn.
+func nonZeroSize(size int64, err error) (int64, error) {
+	if err == nil && size <= 0 {
+		return 0, fmt.Errorf("measured 0 bytes")
+	}
+	return size, err
+}
+
+// sizeAttempt is how one provider fared on one folder.
+type sizeAttempt struct {
+	Provider string
+	Size     int64
+	Took     time.Duration
+	Err      string
+	Disabled bool
+}
+
+// sizeMeasurement is the latest provider chain run for a folder.
+type sizeMeasurement struct {
+	Path     string
+	Size     int64
+	Provider string // Empty when every provider failed
+	Attempts []sizeAttempt
+	At       time.Time
+}
+
+var (
+	sizeDiagnosticsMu sync.Mutex
+	sizeDiagnostics   = make(map[string]sizeMeasurement)
+)
+
+// recentSizeMeasurements returns the latest measurement of every folder, by path.
+func recentSizeMeasurements() []sizeMeasurement {
+	sizeDiagnosticsMu.Lock()
+	defer sizeDiagnosticsMu.Unlock()
+	measurements := make([]sizeMeasurement, 0, len(sizeDiagnostics))
+	for _, measurement := range sizeDiagnostics {
+		measurements = append(measurements, measurement)
+	}
+	sort.Slice(measurements, func(a, b int) bool { return measurements[a].Path < measurements[b].Path })
+	return measurements
+}
+
+// runSizeProvider measures path with provider, giving up once its timeout passes.
+func runSizeProvider(provider sizeProvider, path, excludePath string) (int64, error) {
+	ctx := context.Background()
+	if provider.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, provider.Timeout)
+		defer cancel()
+	}
+	type result struct {
+		size int64
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		size, err := provider.Measure(ctx, path, excludePath)
+		done <- result{size, err}
+	}()
+	select {
+	case r := <-done:
+		return r.size, r.err
+	case <-ctx.Done():
+		return 0, fmt.Errorf("timed out after %v", provider.Timeout)
+	}
+}
+
+// measureWithProviders runs the provider chain on path and records how each provider did.
+func measureWithProviders(path, excludePath string) (int64, error) {
+	record := sizeMeasurement{Path: path, At: time.Now()}
+	defer func() {
+		sizeDiagnosticsMu.Lock()
+		sizeDiagnostics[path] = record
+		sizeDiagnosticsMu.Unlock()
+	}()
+	for _, provider := range sizeProviders {
+		attempt := sizeAttempt{Provider: provider.Name}
+		if sizeProviderDisabled(provider.Name, path) {
+			attempt.Disabled = true
+			record.Attempts = append(record.Attempts, attempt)
+			continue
+		}
+		start := time.Now()
+		size, err := runSizeProvider(provider, path, excludePath)
+		attempt.Took = time.Since(start)
+		if err != nil {
+			attempt.Err = err.Error()
+			record.Attempts = append(record.Attempts, attempt)
+			continue
+		}
+		attempt.Size = size
+		record.Attempts = append(record.Attempts, attempt)
+		if provider.Store {
+			_ = storeOverviewSize(path, size)
+		}
+		record.Size, record.Provider = size, provider.Name
+		return size, nil
+	}
+	return 0, fmt.Errorf("unable to measure directory size with fast methods")
+}
+
+// sizeProvidersFile lists providers switched off for a folder and everything under it,
+// one "<provider> <path>" per line.
+const sizeProvidersFile = "analyze_size_providers"
+
+var (
+	disabledProvidersMu     sync.Mutex
+	disabledProviders       map[string]map[string]bool // Provider name to paths
+	disabledProvidersLoaded bool
+)
+
+func getSizeProvidersPath() (string, error) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, sizeProvidersFile), nil
+}
+
+func ensureDisabledProvidersLoadedLocked() {
+	if disabledProvidersLoaded {
+		return
+	}
+	disabledProvidersLoaded = true
+	disabledProviders = make(map[string]map[string]bool)
+
+	storePath, err := getSizeProvidersPath()
+	if err != nil {
+		return
+	}
+	file, err := os.Open(storePath)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, path, ok := strings.Cut(line, " ")
+		if !ok || !filepath.IsAbs(path) {
+			continue
+		}
+		if disabledProviders[name] == nil {
+			disabledProviders[name] = make(map[string]bool)
+		}
+		disabledProviders[name][filepath.Clean(path)] = true
+	}
+}
+
+// sizeProviderDisabled reports whether name is switched off for path or a folder above it.
+func sizeProviderDisabled(name, path string) bool {
+	disabledProvidersMu.Lock()
+	defer disabledProvidersMu.Unlock()
+	ensureDisabledProvidersLoadedLocked()
+	for disabled := range disabledProviders[name] {
+		if path == disabled || strings.HasPrefix(path, disabled+"/") || disabled == "/" {
+			return true
+		}
+	}
+	return false
+}
+
+// toggleSizeProvider switches name off or back on for path and persists the list.
+// Turning a provider off forgets the remembered size, which it may have produced, so
+// the next measurement comes from the rest of the chain.
+func toggleSizeProvider(name, path string) (bool, error) {
+	disabledProvidersMu.Lock()
+	ensureDisabledProvidersLoadedLocked()
+	disabled := !disabledProviders[name][path]
+	if disabled {
+		if disabledProviders[name] == nil {
+			disabledProviders[name] = make(map[string]bool)
+		}
+		disabledProviders[name][path] = true
+	} else {
+		delete(disabledProviders[name], path)
+	}
+	err := persistDisabledProvidersLocked()
+	disabledProvidersMu.Unlock()
+
+	if disabled {
+		removeOverviewSnapshot(path)
+	}
+	return disabled, err
+}
+
+func persistDisabledProvidersLocked() error {
+	storePath, err := getSizeProvidersPath()
+	if err != nil {
+		return err
+	}
+	var lines []string
+	for name, paths := range disabledProviders {
+		for path := range paths {
+			lines = append(lines, name+" "+path)
+		}
+	}
+	sort.Strings(lines)
+	if err := os.MkdirAll(filepath.Dir(storePath), 0755); err != nil {
+		return err
+	}
+	var b strings.Builder
+	b.WriteString("# Size providers mo analyze skips for these folders\n")
+	for _, line := range lines {
+		b.WriteString(line + "\n")
+	}
+	tmpPath := storePath + ".tmp"
+	if err := os.WriteFile(tmpPath, []byte(b.String()), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, storePath)
+}
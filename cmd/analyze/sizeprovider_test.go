@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func withSizeProviders(t *testing.T, providers []sizeProvider) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	saved := sizeProviders
+	sizeProviders = providers
+	disabledProvidersMu.Lock()
+	disabledProvidersLoaded = false
+	disabledProvidersMu.Unlock()
+	t.Cleanup(func() {
+		sizeProviders = saved
+		disabledProvidersMu.Lock()
+		disabledProvidersLoaded = false
+		disabledProvidersMu.Unlock()
+	})
+}
+
+func TestSizeProviderChainRecordsEachAttempt(t *testing.T) {
+	withSizeProviders(t, []sizeProvider{
+		{Name: "broken", Measure: func(context.Context, string, string) (int64, error) { return 0, errors.New("no answer") }},
+		{Name: "slow", Timeout: 10 * time.Millisecond, Measure: func(ctx context.Context, _, _ string) (int64, error) {
+			<-ctx.Done()
+			time.Sleep(50 * time.Millisecond) // Ignores cancellation a little longer, like du
+			return 1, nil
+		}},
+		{Name: "wrong", Measure: func(context.Context, string, string) (int64, error) { return 999, nil }},
+		{Name: "good", Measure: func(context.Context, string, string) (int64, error) { return 42, nil }},
+	})
+	path := "/provider/test"
+	if _, err := toggleSizeProvider("wrong", "/provider"); err != nil {
+		t.Fatalf("toggleSizeProvider: %v", err)
+	}
+
+	size, err := measureWithProviders(path, "")
+	if err != nil || size != 42 {
+		t.Fatalf("measureWithProviders = %d, %v; want 42", size, err)
+	}
+	var measured sizeMeasurement
+	for _, m := range recentSizeMeasurements() {
+		if m.Path == path {
+			measured = m
+		}
+	}
+	if measured.Provider != "good" || len(measured.Attempts) != 4 {
+		t.Fatalf("unexpected measurement: %+v", measured)
+	}
+	if a := measured.Attempts; a[0].Err == "" || a[1].Err == "" || !a[2].Disabled || a[3].Size != 42 {
+		t.Fatalf("unexpected attempts: %+v", a)
+	}
+
+	// The list persists: a fresh load still skips the provider under /provider.
+	disabledProvidersMu.Lock()
+	disabledProvidersLoaded = false
+	disabledProvidersMu.Unlock()
+	if !sizeProviderDisabled("wrong", path) || sizeProviderDisabled("wrong", "/providers") {
+		t.Fatalf("disabled provider not scoped to /provider after reload")
+	}
+	if disabled, _ := toggleSizeProvider("wrong", "/provider"); disabled || sizeProviderDisabled("wrong", path) {
+		t.Fatalf("provider should be back on")
+	}
+}
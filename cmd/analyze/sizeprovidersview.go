@@ -0,0 +1,154 @@
+//go:build darwin
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// sizeProvidersView is the "I" screen showing which provider produced each overview
+// size and how long every provider in the chain took.
+type sizeProvidersView struct {
+	Measurements []sizeMeasurement
+	Selected     int // Index into rows()
+}
+
+// sizeProviderRow is a measured folder (Provider empty) or one provider's turn at it.
+type sizeProviderRow struct {
+	Measurement int
+	Provider    string
+}
+
+func (v *sizeProvidersView) rows() []sizeProviderRow {
+	var rows []sizeProviderRow
+	for i := range v.Measurements {
+		rows = append(rows, sizeProviderRow{Measurement: i})
+		for _, provider := range sizeProviders {
+			rows = append(rows, sizeProviderRow{Measurement: i, Provider: provider.Name})
+		}
+	}
+	return rows
+}
+
+func (m model) openSizeProviders() (tea.Model, tea.Cmd) {
+	m.providers = &sizeProvidersView{Measurements: recentSizeMeasurements()}
+	return m, nil
+}
+
+// updateSizeProvidersKey handles keys while the provider diagnostics are open.
+func (m model) updateSizeProvidersKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	v := m.providers
+	rows := v.rows()
+	switch msg.String() {
+	case "q", "Q", "ctrl+c":
+		return m.quit()
+	case "esc", "b", "left", "h", "I":
+		m.providers = nil
+	case "up", "k":
+		v.Selected = max(v.Selected-1, 0)
+	case "down", "j":
+		v.Selected = min(v.Selected+1, max(len(rows)-1, 0))
+	case " ", "x":
+		if v.Selected >= len(rows) || rows[v.Selected].Provider == "" {
+			return m, nil
+		}
+		row := rows[v.Selected]
+		path := v.Measurements[row.Measurement].Path
+		disabled, err := toggleSizeProvider(row.Provider, path)
+		switch {
+		case err != nil:
+			m.status = fmt.Sprintf("Unable to save provider settings: %v", err)
+		case disabled:
+			m.status = fmt.Sprintf("%s off for %s, R to measure again", row.Provider, displayPath(path))
+		default:
+			m.status = fmt.Sprintf("%s back on for %s", row.Provider, displayPath(path))
+		}
+	case "r", "R":
+		if v.Selected >= len(rows) {
+			return m, nil
+		}
+		path := v.Measurements[rows[v.Selected].Measurement].Path
+		m.status = fmt.Sprintf("Measuring %s...", displayPath(path))
+		return m, scanOverviewPathCmd(path, 0)
+	}
+	return m, nil
+}
+
+// renderSizeProviders lists measured folders, each followed by every provider's attempt.
+func (m model) renderSizeProviders() string {
+	v := m.providers
+	var b strings.Builder
+	if len(v.Measurements) == 0 {
+		fmt.Fprintf(&b, "  %sNo overview sizes measured yet. Open the overview to measure its folders.%s\n\n", colorGray, colorReset)
+		fmt.Fprintf(&b, "%sI/← Back | Q Quit%s\n", colorGray, colorReset)
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "%sSize providers:%s tried in order %s%s%s\n\n",
+		colorCyan, colorReset, colorGray, strings.Join(sizeProviderNames(), " → "), colorReset)
+
+	rows := v.rows()
+	viewport := calculateViewport(m.height, true)
+	nameWidth := calculateNameWidth(m.width) + 20
+	start := max(0, v.Selected-viewport+1)
+	for i := start; i < min(len(rows), start+viewport); i++ {
+		row := rows[i]
+		measurement := v.Measurements[row.Measurement]
+		prefix, color := "   ", ""
+		if i == v.Selected {
+			prefix, color = fmt.Sprintf(" %s%s▶%s ", colorCyan, colorBold, colorReset), colorCyan
+		}
+		if row.Provider == "" {
+			size, source := "unknown", "every provider failed"
+			if measurement.Provider != "" {
+				size, source = humanizeBytes(measurement.Size), "from "+measurement.Provider
+			}
+			fmt.Fprintf(&b, "%s%s%s%s%s %10s  %s%s%s\n",
+				prefix, color, colorBold, padName(truncateMiddle(displayPath(measurement.Path), nameWidth), nameWidth), colorReset,
+				size, colorGray, source, colorReset)
+			continue
+		}
+		fmt.Fprintf(&b, "%s      %s%-12s%s %s\n", prefix, color, row.Provider, colorReset, describeSizeAttempt(measurement, row.Provider))
+	}
+
+	fmt.Fprintln(&b)
+	fmt.Fprintf(&b, "%s↑↓ | Space Turn provider off/on here | R Measure again | I/← Back | Q Quit%s\n", colorGray, colorReset)
+	return b.String()
+}
+
+// describeSizeAttempt summarizes how provider fared on a measurement.
+func describeSizeAttempt(measurement sizeMeasurement, provider string) string {
+	disabled := sizeProviderDisabled(provider, measurement.Path)
+	for _, attempt := range measurement.Attempts {
+		if attempt.Provider != provider {
+			continue
+		}
+		switch {
+		case attempt.Disabled:
+			return fmt.Sprintf("%soff for this folder%s", colorYellow, colorReset)
+		case attempt.Err != "":
+			return fmt.Sprintf("%sfailed in %s: %s%s", colorGray, formatTook(attempt.Took), attempt.Err, colorReset)
+		default:
+			note := ""
+			if disabled {
+				note = fmt.Sprintf("  %s(now off)%s", colorYellow, colorReset)
+			}
+			return fmt.Sprintf("%s%s%s in %s%s", colorGreen, humanizeBytes(attempt.Size), colorReset, formatTook(attempt.Took), note)
+		}
+	}
+	if disabled {
+		return fmt.Sprintf("%soff for this folder%s", colorYellow, colorReset)
+	}
+	return fmt.Sprintf("%snot needed%s", colorGray, colorReset)
+}
+
+func formatTook(d time.Duration) string {
+	if d < time.Millisecond {
+		return "<1ms"
+	}
+	return d.Round(time.Millisecond).String()
+}
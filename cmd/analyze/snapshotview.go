@@ -0,0 +1,215 @@
+//go:build darwin
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// dataVolume is where firmlinked folders such as /Users and /Applications live.
+const dataVolume = "/System/Volumes/Data"
+
+// snapshotsView is the "L" screen ranking local APFS snapshots by the space they hold.
+type snapshotsView struct {
+	Volume    string
+	Rel       string // Analyzed folder relative to Volume
+	Live      string
+	Snapshots []localSnapshot
+	Scanning  bool
+	Measured  bool
+	Err       error
+	Selected  int
+	Confirm   string // "delete" or "thin" once pressed; the same key again runs it
+}
+
+type snapshotActionMsg struct {
+	Status string
+}
+
+// snapshotVolumeFor resolves the APFS volume holding path and path's place inside it.
+func snapshotVolumeFor(path string) (string, string) {
+	mount, err := mountPointOf(path)
+	if err != nil {
+		mount = "/"
+	}
+	if mount != "/" && strings.HasPrefix(mount, "/Volumes/") {
+		rel, _ := filepath.Rel(mount, path)
+		return mount, rel
+	}
+	// The sealed system volume has no user data; firmlinks point into the Data volume.
+	if _, err := os.Stat(dataVolume); err == nil {
+		rel := strings.TrimPrefix(strings.TrimPrefix(path, dataVolume), "/")
+		return dataVolume, rel
+	}
+	rel, _ := filepath.Rel(mount, path)
+	return mount, rel
+}
+
+func (m model) openSnapshots() (tea.Model, tea.Cmd) {
+	if m.inventory != nil {
+		m.status = fmt.Sprintf("%s is a read-only listing", m.inventory.Source)
+		return m, nil
+	}
+	live := m.path
+	if m.inOverviewMode() {
+		live = os.Getenv("HOME")
+	}
+	volume, rel := snapshotVolumeFor(live)
+	m.snapshots = &snapshotsView{Volume: volume, Rel: rel, Live: live, Scanning: true}
+	m.status = fmt.Sprintf("Measuring local snapshots of %s...", displayPath(live))
+	return m, tea.Batch(measureLocalSnapshotsCmd(volume, rel, live), tickCmd())
+}
+
+// runSnapshotCommand runs tmutil or diskutil in the terminal so admin prompts show.
+func runSnapshotCommand(args []string, done string) tea.Cmd {
+	script := `"$@"; status=$?; printf '\n[exit %d] Press Enter to return to mo analyze ' "$status"; read _`
+	cmd := exec.Command("/bin/sh", append([]string{"-c", script, "mo"}, args...)...)
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		if err != nil {
+			return snapshotActionMsg{Status: fmt.Sprintf("%s failed: %v", args[0], err)}
+		}
+		return snapshotActionMsg{Status: done}
+	})
+}
+
+// updateSnapshotsKey handles keys while the snapshot list is open.
+func (m model) updateSnapshotsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	v := m.snapshots
+	key := msg.String()
+	confirm := v.Confirm
+	v.Confirm = ""
+	if confirm != "" && key == "esc" {
+		m.status = "Cancelled"
+		return m, nil
+	}
+	switch key {
+	case "q", "Q", "ctrl+c":
+		return m.quit()
+	case "esc", "b", "left", "h", "L":
+		m.snapshots = nil
+	case "up", "k":
+		v.Selected = max(v.Selected-1, 0)
+	case "down", "j":
+		v.Selected = min(v.Selected+1, max(len(v.Snapshots)-1, 0))
+	case "r":
+		v.Scanning = true
+		return m, tea.Batch(measureLocalSnapshotsCmd(v.Volume, v.Rel, v.Live), tickCmd())
+	case "delete", "backspace":
+		if v.Scanning || v.Selected >= len(v.Snapshots) {
+			return m, nil
+		}
+		if m.safeMode {
+			m.status = "Snapshot deletion is disabled in safe mode"
+			return m, nil
+		}
+		if confirm != "delete" {
+			v.Confirm = "delete"
+			return m, nil
+		}
+		snapshot := v.Snapshots[v.Selected]
+		return m, runSnapshotCommand(snapshotDeleteCommand(v.Volume, snapshot), fmt.Sprintf("Deleted snapshot %s", snapshot.Name))
+	case "t":
+		if v.Scanning || len(v.Snapshots) == 0 {
+			return m, nil
+		}
+		if m.safeMode {
+			m.status = "Snapshot thinning is disabled in safe mode"
+			return m, nil
+		}
+		if confirm != "thin" {
+			v.Confirm = "thin"
+			return m, nil
+		}
+		var bytes int64
+		if v.Selected < len(v.Snapshots) {
+			bytes = v.Snapshots[v.Selected].Unique
+		}
+		return m, runSnapshotCommand(snapshotThinCommand(v.Volume, bytes), "Asked Time Machine to thin local snapshots")
+	}
+	return m, nil
+}
+
+// renderSnapshots ranks snapshots by the space only they keep for the analyzed folder.
+func (m model) renderSnapshots() string {
+	v := m.snapshots
+	var b strings.Builder
+	if v.Scanning {
+		fmt.Fprintf(&b, "%s%s%s%s Mounting and comparing local snapshots of %s...\n",
+			colorCyan, colorBold, spinnerFrames[m.spinner], colorReset, displayPath(v.Live))
+		return b.String()
+	}
+	if v.Err != nil {
+		fmt.Fprintf(&b, "  %sCould not list snapshots on %s: %v%s\n\n", colorRed, v.Volume, v.Err, colorReset)
+		fmt.Fprintf(&b, "%sL/← Back | Q Quit%s\n", colorGray, colorReset)
+		return b.String()
+	}
+	if len(v.Snapshots) == 0 {
+		fmt.Fprintf(&b, "  No local snapshots on %s.\n\n", v.Volume)
+		fmt.Fprintf(&b, "%sL/← Back | Q Quit%s\n", colorGray, colorReset)
+		return b.String()
+	}
+
+	var held, largest int64
+	for _, snapshot := range v.Snapshots {
+		held = max(held, snapshot.Held)
+		largest = max(largest, snapshot.Unique)
+	}
+	fmt.Fprintf(&b, "%sLocal snapshots:%s %d on %s", colorCyan, colorReset, len(v.Snapshots), v.Volume)
+	if v.Measured {
+		fmt.Fprintf(&b, ", up to %s%s%s of deleted data from %s still kept", colorYellow, humanizeBytes(held), colorReset, displayPath(v.Live))
+	}
+	fmt.Fprintln(&b)
+	if !v.Measured {
+		fmt.Fprintf(&b, "%sSnapshots could not be mounted; run with sudo to see what each one holds.%s\n", colorGray, colorReset)
+	}
+	fmt.Fprintln(&b)
+
+	viewport := calculateViewport(m.height, true)
+	start := max(0, v.Selected-viewport+1)
+	for i := start; i < min(len(v.Snapshots), start+viewport); i++ {
+		snapshot := v.Snapshots[i]
+		prefix, color := "   ", ""
+		if i == v.Selected {
+			prefix, color = fmt.Sprintf(" %s%s▶%s ", colorCyan, colorBold, colorReset), colorCyan
+		}
+		label := snapshot.Name
+		if !snapshot.Time.IsZero() {
+			label = snapshot.Time.Format("2006-01-02 15:04")
+		}
+		var notes []string
+		if !snapshot.Purgeable {
+			notes = append(notes, "not purgeable")
+		}
+		if snapshot.Limiting {
+			notes = append(notes, "limits container shrink")
+		}
+		sizes := fmt.Sprintf("%s%10s%s", colorGray, "not measured", colorReset)
+		if snapshot.Unique >= 0 {
+			percent := 0.0
+			if largest > 0 {
+				percent = float64(snapshot.Unique) / float64(largest) * 100
+			}
+			sizes = fmt.Sprintf("%s %10s unique  %s%10s held%s", coloredProgressBar(snapshot.Unique, largest, percent),
+				humanizeBytes(snapshot.Unique), colorGray, humanizeBytes(snapshot.Held), colorReset)
+		}
+		fmt.Fprintf(&b, "%s%s%-18s%s  %s  %s%s%s\n", prefix, color, label, colorReset, sizes, colorGray, strings.Join(notes, ", "), colorReset)
+	}
+
+	fmt.Fprintln(&b)
+	switch v.Confirm {
+	case "delete":
+		snapshot := v.Snapshots[min(v.Selected, len(v.Snapshots)-1)]
+		fmt.Fprintf(&b, "%sDelete snapshot:%s %s  %sPress ⌫ again  |  ESC cancel%s\n", colorRed, colorReset, snapshot.Name, colorGray, colorReset)
+	case "thin":
+		fmt.Fprintf(&b, "%sThin local snapshots%s on %s  %sPress T again  |  ESC cancel%s\n", colorRed, colorReset, v.Volume, colorGray, colorReset)
+	default:
+		fmt.Fprintf(&b, "%s↑↓ | ⌫ Delete snapshot | T Thin | R Refresh | L/← Back | Q Quit%s\n", colorGray, colorReset)
+	}
+	return b.String()
+}
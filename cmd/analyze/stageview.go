@@ -0,0 +1,258 @@
+//go:build darwin
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// stagingView is the Z review screen for items staged with z.
+type stagingView struct {
+	Items    []stagedItem
+	Missing  map[string]bool // Staged paths that no longer exist
+	Marked   map[string]bool
+	Selected int
+	Confirm  bool // ⌫ pressed once; the next ⌫ deletes
+}
+
+// stageSelection adds the marked items, or the cursor row, to the review-later list.
+func (m model) stageSelection() (tea.Model, tea.Cmd) {
+	if m.inventory != nil {
+		m.status = fmt.Sprintf("%s is a read-only listing", m.inventory.Source)
+		return m, nil
+	}
+	targets := m.actionTargets()
+	if len(targets) == 0 {
+		return m, nil
+	}
+	sizes := make(map[string]dirEntry)
+	for _, entry := range m.entries {
+		sizes[entry.Path] = entry
+	}
+	for _, file := range m.largeFiles {
+		sizes[file.Path] = dirEntry{Path: file.Path, Size: file.Size}
+	}
+	items := make([]stagedItem, 0, len(targets))
+	var total int64
+	for _, path := range targets {
+		entry := sizes[path]
+		items = append(items, stagedItem{Path: path, Size: max(entry.Size, 0), IsDir: entry.IsDir})
+		total += max(entry.Size, 0)
+	}
+	added, err := stageItems(items)
+	if err != nil {
+		m.status = fmt.Sprintf("Failed to stage: %v", err)
+		return m, nil
+	}
+	m.multiSelected = make(map[string]bool)
+	m.largeMultiSelected = make(map[string]bool)
+	if added == 0 {
+		m.status = "Already staged for review"
+	} else {
+		m.status = fmt.Sprintf("Staged %d items (%s) for review, Z to review", added, humanizeBytes(total))
+	}
+	return m, nil
+}
+
+func (m model) openStaging() (tea.Model, tea.Cmd) {
+	items := loadStaged()
+	missing := make(map[string]bool)
+	for _, item := range items {
+		if _, err := os.Lstat(item.Path); os.IsNotExist(err) {
+			missing[item.Path] = true
+		}
+	}
+	m.staging = &stagingView{Items: items, Missing: missing, Marked: make(map[string]bool)}
+	m.stagedDue, m.stagedDueSize = 0, 0
+	return m, nil
+}
+
+// targets returns the marked items, else the cursor row.
+func (s *stagingView) targets() []string {
+	var paths []string
+	for path := range s.Marked {
+		paths = append(paths, path)
+	}
+	if len(paths) == 0 && s.Selected < len(s.Items) {
+		paths = append(paths, s.Items[s.Selected].Path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func (s *stagingView) sizeOf(paths []string) int64 {
+	wanted := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		wanted[path] = true
+	}
+	var total int64
+	for _, item := range s.Items {
+		if wanted[item.Path] {
+			total += item.Size
+		}
+	}
+	return total
+}
+
+// remove drops paths from the view after they were unstaged or deleted.
+func (s *stagingView) remove(paths []string) {
+	drop := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		drop[path] = true
+		delete(s.Marked, path)
+	}
+	kept := s.Items[:0]
+	for _, item := range s.Items {
+		if !drop[item.Path] {
+			kept = append(kept, item)
+		}
+	}
+	s.Items = kept
+	s.Selected = min(s.Selected, max(len(s.Items)-1, 0))
+}
+
+// updateStagingKey handles keys while the staging review is open.
+func (m model) updateStagingKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	s := m.staging
+	key := msg.String()
+	confirm := s.Confirm
+	s.Confirm = false
+	if confirm && key == "esc" {
+		m.status = "Cancelled"
+		return m, nil
+	}
+	switch key {
+	case "q", "Q", "ctrl+c":
+		return m.quit()
+	case "esc", "b", "left", "h", "Z":
+		m.staging = nil
+	case "up", "k":
+		s.Selected = max(s.Selected-1, 0)
+	case "down", "j":
+		s.Selected = min(s.Selected+1, max(len(s.Items)-1, 0))
+	case " ":
+		if s.Selected < len(s.Items) {
+			path := s.Items[s.Selected].Path
+			if s.Marked[path] {
+				delete(s.Marked, path)
+			} else {
+				s.Marked[path] = true
+			}
+		}
+	case "a":
+		if len(s.Marked) == len(s.Items) {
+			s.Marked = make(map[string]bool)
+		} else {
+			for _, item := range s.Items {
+				s.Marked[item.Path] = true
+			}
+		}
+	case "u":
+		paths := s.targets()
+		if len(paths) == 0 {
+			return m, nil
+		}
+		if err := unstagePaths(paths); err != nil {
+			m.status = fmt.Sprintf("Failed to unstage: %v", err)
+			return m, nil
+		}
+		s.remove(paths)
+		m.status = fmt.Sprintf("Kept %d items", len(paths))
+	case "delete", "backspace", "alt+delete", "alt+backspace":
+		var paths []string
+		for _, path := range s.targets() {
+			if !s.Missing[path] {
+				paths = append(paths, path)
+			}
+		}
+		if len(paths) == 0 || m.deleting {
+			return m, nil
+		}
+		if m.safeMode {
+			for _, path := range paths {
+				if _, allowed := safeDeleteCategory(path); !allowed {
+					m.status = safeModeBlockReason(path)
+					return m, nil
+				}
+			}
+		}
+		if !confirm {
+			s.Confirm = true
+			m.deletePermanent = m.permanentDelete || strings.HasPrefix(key, "alt+")
+			return m, nil
+		}
+		permanent := m.deletePermanent
+		m.deletePermanent = false
+		m.deleting = true
+		var deleteCount int64
+		m.deleteCount = &deleteCount
+		m.deleteBatch = &deleteBatchProgress{Total: len(paths), Permanent: permanent}
+		m.status = fmt.Sprintf("Removing %d staged items...", len(paths))
+		return m, tea.Batch(deletePathCmd(paths, m.deleteCount, m.deleteBatch, permanent), tickCmd())
+	}
+	return m, nil
+}
+
+// renderStaging draws the staged items with their size and how long they have waited.
+func (m model) renderStaging() string {
+	s := m.staging
+	var b strings.Builder
+	if len(s.Items) == 0 {
+		fmt.Fprintf(&b, "  Nothing staged. Press z on any entry to set it aside for later.\n\n")
+		fmt.Fprintf(&b, "%sZ/← Back | Q Quit%s\n", colorGray, colorReset)
+		return b.String()
+	}
+
+	var total int64
+	for _, item := range s.Items {
+		total += item.Size
+	}
+	fmt.Fprintf(&b, "%sStaged for review:%s %d items, %s%s%s\n\n",
+		colorCyan, colorReset, len(s.Items), colorYellow, humanizeBytes(total), colorReset)
+
+	now := time.Now()
+	viewport := calculateViewport(m.height, true)
+	start := max(0, s.Selected-viewport+1)
+	nameWidth := calculateNameWidth(m.width)
+	for i := start; i < min(len(s.Items), start+viewport); i++ {
+		item := s.Items[i]
+		selectIcon := "○"
+		if s.Marked[item.Path] {
+			selectIcon = fmt.Sprintf("%s●%s", colorGreen, colorReset)
+		}
+		prefix := "   "
+		if i == s.Selected {
+			prefix = fmt.Sprintf(" %s%s▶%s ", colorCyan, colorBold, colorReset)
+		}
+		note := fmt.Sprintf("staged %s", formatStagedAge(now.Sub(item.StagedAt)))
+		noteColor := colorGray
+		switch {
+		case s.Missing[item.Path]:
+			note, noteColor = "already gone", colorGray
+		case item.due(now):
+			noteColor = colorYellow
+		}
+		name := padName(trimNameWithWidth(displayPath(item.Path), nameWidth+10), nameWidth+10)
+		fmt.Fprintf(&b, "%s%s %s  %10s  %s%s%s\n", prefix, selectIcon, name, humanizeBytes(item.Size), noteColor, note, colorReset)
+	}
+
+	fmt.Fprintln(&b)
+	if s.Confirm {
+		paths := s.targets()
+		verb := "Move to Trash:"
+		if m.deletePermanent {
+			verb = "Delete permanently:"
+		}
+		fmt.Fprintf(&b, "%s%s%s %d staged items (%s)  %sPress ⌫ again  |  ESC cancel%s\n",
+			colorRed, verb, colorReset, len(paths), humanizeBytes(s.sizeOf(paths)), colorGray, colorReset)
+	} else {
+		fmt.Fprintf(&b, "%s↑↓ | Space/a Select | ⌫ Delete | U Keep | Z/← Back | Q Quit%s\n", colorGray, colorReset)
+	}
+	return b.String()
+}
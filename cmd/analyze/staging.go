@@ -0,0 +1,163 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// stagingFile keeps entries set aside with z for a later delete-or-keep decision.
+	stagingFile = "analyze_staged.json"
+	// stagingReviewAfter is how long staged items wait before the reminder appears.
+	stagingReviewAfter = 7 * 24 * time.Hour
+)
+
+// stagedItem is one entry in the review-later list.
+type stagedItem struct {
+	Path     string    `json:"path"`
+	Size     int64     `json:"size"`
+	IsDir    bool      `json:"is_dir"`
+	StagedAt time.Time `json:"staged_at"`
+}
+
+// due reports whether the item has waited long enough to be revisited.
+func (s stagedItem) due(now time.Time) bool {
+	return now.Sub(s.StagedAt) >= stagingReviewAfter
+}
+
+var stagingMu sync.Mutex
+
+func getStagingPath() (string, error) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, stagingFile), nil
+}
+
+func loadStagedLocked() []stagedItem {
+	path, err := getStagingPath()
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var items []stagedItem
+	if json.Unmarshal(data, &items) != nil {
+		return nil
+	}
+	return items
+}
+
+func saveStagedLocked(items []stagedItem) error {
+	path, err := getStagingPath()
+	if err != nil {
+		return err
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Size != items[j].Size {
+			return items[i].Size > items[j].Size
+		}
+		return items[i].Path < items[j].Path
+	})
+	data, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// loadStaged returns staged items, largest first.
+func loadStaged() []stagedItem {
+	stagingMu.Lock()
+	defer stagingMu.Unlock()
+	return loadStagedLocked()
+}
+
+// stageItems adds entries to the list, refreshing size but keeping the original date.
+func stageItems(entries []stagedItem) (int, error) {
+	stagingMu.Lock()
+	defer stagingMu.Unlock()
+	items := loadStagedLocked()
+	index := make(map[string]int, len(items))
+	for i, item := range items {
+		index[item.Path] = i
+	}
+	added := 0
+	for _, entry := range entries {
+		if i, ok := index[entry.Path]; ok {
+			items[i].Size = entry.Size
+			continue
+		}
+		if entry.StagedAt.IsZero() {
+			entry.StagedAt = time.Now()
+		}
+		index[entry.Path] = len(items)
+		items = append(items, entry)
+		added++
+	}
+	return added, saveStagedLocked(items)
+}
+
+// unstagePaths drops paths from the list; unknown paths are ignored.
+func unstagePaths(paths []string) error {
+	stagingMu.Lock()
+	defer stagingMu.Unlock()
+	items := loadStagedLocked()
+	if len(items) == 0 {
+		return nil
+	}
+	drop := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		drop[path] = true
+	}
+	kept := items[:0]
+	for _, item := range items {
+		if !drop[item.Path] {
+			kept = append(kept, item)
+		}
+	}
+	if len(kept) == len(items) {
+		return nil
+	}
+	return saveStagedLocked(kept)
+}
+
+// stagingReminder summarizes items that are due for review.
+func stagingReminder(items []stagedItem, now time.Time) (int, int64) {
+	var count int
+	var size int64
+	for _, item := range items {
+		if item.due(now) {
+			count++
+			size += item.Size
+		}
+	}
+	return count, size
+}
+
+// formatStagedAge renders a duration as "today", "3 days ago" or "5 weeks ago".
+func formatStagedAge(age time.Duration) string {
+	days := int(age.Hours() / 24)
+	switch {
+	case days < 1:
+		return "today"
+	case days == 1:
+		return "yesterday"
+	case days < 14:
+		return fmt.Sprintf("%d days ago", days)
+	default:
+		return fmt.Sprintf("%d weeks ago", days/7)
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStageAndUnstageRoundTrip(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	old := time.Now().Add(-10 * 24 * time.Hour)
+	added, err := stageItems([]stagedItem{
+		{Path: "/Users/me/old.dmg", Size: 300, StagedAt: old},
+		{Path: "/Users/me/new", Size: 500, IsDir: true},
+	})
+	if err != nil || added != 2 {
+		t.Fatalf("stageItems: added=%d err=%v", added, err)
+	}
+	// Restaging refreshes the size but keeps the original date.
+	if added, err := stageItems([]stagedItem{{Path: "/Users/me/old.dmg", Size: 400}}); err != nil || added != 0 {
+		t.Fatalf("restage: added=%d err=%v", added, err)
+	}
+
+	items := loadStaged()
+	if len(items) != 2 || items[0].Path != "/Users/me/new" || items[1].Size != 400 || !items[1].due(time.Now()) {
+		t.Fatalf("unexpected staged items: %+v", items)
+	}
+	if count, size := stagingReminder(items, time.Now()); count != 1 || size != 400 {
+		t.Fatalf("stagingReminder = %d, %d; want 1, 400", count, size)
+	}
+
+	if err := unstagePaths([]string{"/Users/me/old.dmg", "/not/staged"}); err != nil {
+		t.Fatalf("unstagePaths: %v", err)
+	}
+	if items := loadStaged(); len(items) != 1 || items[0].Path != "/Users/me/new" {
+		t.Fatalf("unexpected items after unstage: %+v", items)
+	}
+}
+
+func TestFormatStagedAge(t *testing.T) {
+	cases := map[time.Duration]string{
+		time.Hour:           "today",
+		30 * time.Hour:      "yesterday",
+		5 * 24 * time.Hour:  "5 days ago",
+		40 * 24 * time.Hour: "5 weeks ago",
+	}
+	for age, want := range cases {
+		if got := formatStagedAge(age); got != want {
+			t.Fatalf("formatStagedAge(%v) = %q, want %q", age, got, want)
+		}
+	}
+}
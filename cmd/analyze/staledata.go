@@ -0,0 +1,163 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// staleBucket groups items untouched for at least Age.
+type staleBucket struct {
+	Label string
+	Age   time.Duration
+}
+
+// staleBuckets run oldest first; anything newer than the last bucket is not stale.
+var staleBuckets = []staleBucket{
+	{Label: "Over 2 years", Age: 730 * 24 * time.Hour},
+	{Label: "Over 1 year", Age: 365 * 24 * time.Hour},
+	{Label: "Over 6 months", Age: 182 * 24 * time.Hour},
+	{Label: "Over 3 months", Age: 90 * 24 * time.Hour},
+}
+
+const (
+	staleMinSize  = 1 << 20 // Smaller items would bury the ones worth a look
+	staleMaxItems = 500     // Largest kept; the rest are summarized by bucket totals
+)
+
+// staleItem is a file, or a folder where nothing inside has been touched since Touched.
+type staleItem struct {
+	Path    string
+	IsDir   bool
+	Size    int64
+	Touched time.Time
+}
+
+// staleBucketFor returns the index of the bucket age falls in, or -1 when it is recent.
+func staleBucketFor(age time.Duration) int {
+	for i, bucket := range staleBuckets {
+		if age >= bucket.Age {
+			return i
+		}
+	}
+	return -1
+}
+
+type staleItemsMsg struct {
+	Root       string
+	ByModified bool
+	Items      []staleItem
+	Totals     []int64 // Bytes per bucket, including items past staleMaxItems
+	At         time.Time
+	Err        error
+}
+
+// staleTouched is when info was last read, or last written when byModified is set.
+func staleTouched(info os.FileInfo, byModified bool) time.Time {
+	if byModified {
+		return info.ModTime()
+	}
+	if accessed := getLastAccessTimeFromInfo(info); !accessed.IsZero() {
+		return accessed
+	}
+	return info.ModTime()
+}
+
+// collectStaleItems walks root for items untouched longer than the newest bucket. A
+// folder whose every file is that old is reported whole instead of file by file.
+func collectStaleItems(root string, byModified bool, now time.Time) ([]staleItem, error) {
+	if _, err := os.Stat(root); err != nil {
+		return nil, err
+	}
+	cutoff := now.Add(-staleBuckets[len(staleBuckets)-1].Age)
+	var walk func(dir string) (int64, time.Time, []staleItem)
+	walk = func(dir string) (int64, time.Time, []staleItem) {
+		children, err := os.ReadDir(dir)
+		if err != nil {
+			// Unreadable folders count as just touched so their parent is not called stale.
+			return 0, now, nil
+		}
+		var size int64
+		var newest time.Time
+		var stale []staleItem
+		for _, child := range children {
+			path := filepath.Join(dir, child.Name())
+			if child.Type()&os.ModeSymlink != 0 || isExcludedPath(root, path) || isCloudPath(path) {
+				continue
+			}
+			if child.IsDir() {
+				if defaultSkipDirs[child.Name()] {
+					continue
+				}
+				childSize, touched, childStale := walk(path)
+				size += childSize
+				if touched.After(newest) {
+					newest = touched
+				}
+				if touched.Before(cutoff) && childSize >= staleMinSize {
+					stale = append(stale, staleItem{Path: path, IsDir: true, Size: childSize, Touched: touched})
+				} else {
+					stale = append(stale, childStale...)
+				}
+				continue
+			}
+			info, err := child.Info()
+			if err != nil {
+				continue
+			}
+			fileSize := getActualFileSize(path, info)
+			touched := staleTouched(info, byModified)
+			// A file created recently is active work, however old its dates say it is.
+			if hideRecentFiles.Load() && isRecentFile(birthTime(info), info.ModTime(), now) {
+				touched = now
+			}
+			size += fileSize
+			if touched.After(newest) {
+				newest = touched
+			}
+			if touched.Before(cutoff) && fileSize >= staleMinSize {
+				stale = append(stale, staleItem{Path: path, Size: fileSize, Touched: touched})
+			}
+		}
+		return size, newest, stale
+	}
+	_, _, items := walk(root)
+	return items, nil
+}
+
+// finishStaleItems keeps the largest staleMaxItems and sorts them oldest first,
+// totalling every bucket before anything is dropped.
+func finishStaleItems(items []staleItem, now time.Time) ([]staleItem, []int64) {
+	totals := make([]int64, len(staleBuckets))
+	for _, item := range items {
+		if bucket := staleBucketFor(now.Sub(item.Touched)); bucket >= 0 {
+			totals[bucket] += item.Size
+		}
+	}
+	if len(items) > staleMaxItems {
+		sort.Slice(items, func(a, b int) bool { return items[a].Size > items[b].Size })
+		items = items[:staleMaxItems]
+	}
+	sort.SliceStable(items, func(a, b int) bool {
+		if !items[a].Touched.Equal(items[b].Touched) {
+			return items[a].Touched.Before(items[b].Touched)
+		}
+		return items[a].Size > items[b].Size
+	})
+	return items, totals
+}
+
+func findStaleItemsCmd(root string, byModified bool) tea.Cmd {
+	return func() tea.Msg {
+		now := time.Now()
+		items, err := collectStaleItems(root, byModified, now)
+		if err != nil {
+			return staleItemsMsg{Root: root, ByModified: byModified, Err: err}
+		}
+		items, totals := finishStaleItems(items, now)
+		return staleItemsMsg{Root: root, ByModified: byModified, Items: items, Totals: totals, At: now}
+	}
+}
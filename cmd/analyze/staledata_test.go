@@ -0,0 +1,61 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCollectStaleItemsRollsUpUntouchedFolders(t *testing.T) {
+	root := t.TempDir()
+	now := time.Now()
+	old := now.Add(-400 * 24 * time.Hour)
+	ancient := now.Add(-800 * 24 * time.Hour)
+	write := func(rel string, size int, touched time.Time) {
+		path := filepath.Join(root, rel)
+		writeFileWithSize(t, path, size)
+		if err := os.Chtimes(path, touched, touched); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("archive/a.bin", 2<<20, ancient)
+	write("archive/nested/b.bin", 2<<20, old)
+	write("mixed/old.bin", 3<<20, old)
+	write("mixed/new.bin", 3<<20, now)
+	write("mixed/small.bin", 10, old)
+	write("recent.bin", 2<<20, now)
+
+	items, err := collectStaleItems(root, true, now)
+	if err != nil {
+		t.Fatalf("collectStaleItems: %v", err)
+	}
+	got := map[string]staleItem{}
+	for _, item := range items {
+		rel, _ := filepath.Rel(root, item.Path)
+		got[rel] = item
+	}
+	if len(got) != 2 || !got["archive"].IsDir || got["archive"].Size < 4<<20 || got["mixed/old.bin"].IsDir {
+		t.Fatalf("unexpected stale items: %+v", got)
+	}
+	if !got["archive"].Touched.Equal(old) {
+		t.Fatalf("a folder is as stale as its newest file, got %v", got["archive"].Touched)
+	}
+
+	sorted, totals := finishStaleItems(items, now)
+	if sorted[0].Path != got["archive"].Path {
+		t.Fatalf("equally old items should run largest first: %+v", sorted)
+	}
+	if totals[1] != got["archive"].Size+got["mixed/old.bin"].Size {
+		t.Fatalf("both items are over a year old, totals %v", totals)
+	}
+}
+
+func TestStaleBucketFor(t *testing.T) {
+	day := 24 * time.Hour
+	for age, want := range map[time.Duration]int{800 * day: 0, 400 * day: 1, 200 * day: 2, 100 * day: 3, 30 * day: -1} {
+		if got := staleBucketFor(age); got != want {
+			t.Errorf("staleBucketFor(%v) = %d, want %d", age, got, want)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+//go:build darwin
+
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// staleView is the "U" screen grouping what the current folder has not touched in ages.
+type staleView struct {
+	Root       string
+	ByModified bool // Age by last write instead of last access
+	Items      []staleItem
+	Totals     []int64
+	Scanning   bool
+	Err        error
+	Selected   int // Index into Items
+	Scanned    time.Time
+}
+
+func (m model) openStale() (tea.Model, tea.Cmd) {
+	if m.inventory != nil {
+		m.status = fmt.Sprintf("%s is a read-only listing", m.inventory.Source)
+		return m, nil
+	}
+	if m.inOverviewMode() {
+		m.status = "Open a folder first to look for stale data"
+		return m, nil
+	}
+	m.stale = &staleView{Root: m.path, Scanning: true}
+	m.status = fmt.Sprintf("Looking for stale data in %s...", displayPath(m.path))
+	return m, tea.Batch(findStaleItemsCmd(m.path, false), tickCmd())
+}
+
+// updateStaleKey handles keys while the stale data view is open.
+func (m model) updateStaleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	v := m.stale
+	switch msg.String() {
+	case "q", "Q", "ctrl+c":
+		return m.quit()
+	case "esc", "b", "left", "h", "U":
+		m.stale = nil
+	case "up", "k":
+		v.Selected = max(v.Selected-1, 0)
+	case "down", "j":
+		v.Selected = min(v.Selected+1, max(len(v.Items)-1, 0))
+	case "m":
+		if v.Scanning {
+			return m, nil
+		}
+		v.ByModified = !v.ByModified
+		v.Scanning = true
+		m.status = "Looking for stale data..."
+		return m, tea.Batch(findStaleItemsCmd(v.Root, v.ByModified), tickCmd())
+	case "n", "N":
+		if v.Scanning {
+			return m, nil
+		}
+		hideRecentFiles.Store(!hideRecentFiles.Load())
+		v.Scanning = true
+		m.status = "Looking for stale data..."
+		return m, tea.Batch(findStaleItemsCmd(v.Root, v.ByModified), tickCmd())
+	case "enter", "right", "l":
+		// Browse the folder, or the folder holding the file, with the regular scanner.
+		if v.Scanning || v.Selected >= len(v.Items) {
+			return m, nil
+		}
+		item := v.Items[v.Selected]
+		path := item.Path
+		if !item.IsDir {
+			path = filepath.Dir(path)
+		}
+		m.stale = nil
+		return m.openDir(path)
+	}
+	return m, nil
+}
+
+// renderStale lists stale items oldest first under a heading per age bucket.
+func (m model) renderStale() string {
+	v := m.stale
+	var b strings.Builder
+	basis := "opened"
+	if v.ByModified {
+		basis = "modified"
+	}
+	if v.Scanning {
+		fmt.Fprintf(&b, "%s%s%s%s Looking for data not %s in months...\n",
+			colorCyan, colorBold, spinnerFrames[m.spinner], colorReset, basis)
+		return b.String()
+	}
+	if v.Err != nil || len(v.Items) == 0 {
+		fmt.Fprintf(&b, "  %sNothing over %s in %s has gone un%s for %s%s\n\n",
+			colorGray, humanizeBytes(staleMinSize), displayPath(v.Root), basis, strings.ToLower(staleBuckets[len(staleBuckets)-1].Label), colorReset)
+		fmt.Fprintf(&b, "%sM Age by %s | U/← Back | Q Quit%s\n", colorGray, otherStaleBasis(v.ByModified), colorReset)
+		return b.String()
+	}
+
+	var total int64
+	for _, size := range v.Totals {
+		total += size
+	}
+	fmt.Fprintf(&b, "%sStale data:%s %s%s%s not %s in %s in %s%s\n",
+		colorCyan, colorReset, colorYellow, humanizeBytes(total), colorReset, basis, strings.ToLower(staleBuckets[len(staleBuckets)-1].Label), displayPath(v.Root), recentFilesTag())
+	var summary []string
+	for i, bucket := range staleBuckets {
+		if v.Totals[i] > 0 {
+			summary = append(summary, fmt.Sprintf("%s %s", strings.ToLower(bucket.Label), humanizeBytes(v.Totals[i])))
+		}
+	}
+	fmt.Fprintf(&b, "%s%s%s\n\n", colorGray, strings.Join(summary, "  |  "), colorReset)
+
+	viewport := calculateViewport(m.height, true)
+	nameWidth := calculateNameWidth(m.width) + 10
+	start := max(0, v.Selected-viewport+1)
+	lastBucket := -1
+	if start > 0 {
+		lastBucket = staleBucketFor(v.Scanned.Sub(v.Items[start-1].Touched))
+	}
+	for i := start; i < min(len(v.Items), start+viewport); i++ {
+		item := v.Items[i]
+		age := v.Scanned.Sub(item.Touched)
+		if bucket := staleBucketFor(age); bucket != lastBucket && bucket >= 0 {
+			fmt.Fprintf(&b, "   %s%s%s\n", colorBold, staleBuckets[bucket].Label, colorReset)
+			lastBucket = bucket
+		}
+		prefix, color := "   ", ""
+		if i == v.Selected {
+			prefix, color = fmt.Sprintf(" %s%s▶%s ", colorCyan, colorBold, colorReset), colorCyan
+		}
+		icon := "📄"
+		if item.IsDir {
+			icon = "📁"
+		}
+		ageLabel := formatRelativeAge(age, ageUnitAuto, false, currentMessageWords())
+		fmt.Fprintf(&b, "%s%s %s%s%s %10s  %s%s%s\n",
+			prefix, icon, color, padName(truncateMiddle(displayPath(item.Path), nameWidth), nameWidth), colorReset,
+			humanizeBytes(item.Size), colorGray, ageLabel, colorReset)
+	}
+
+	fmt.Fprintln(&b)
+	fmt.Fprintf(&b, "%s↑↓ | Enter Browse | M Age by %s | N New files %s | U/← Back | Q Quit%s\n", colorGray, otherStaleBasis(v.ByModified), recentFilesLabel(), colorReset)
+	return b.String()
+}
+
+func otherStaleBasis(byModified bool) string {
+	if byModified {
+		return "last opened"
+	}
+	return "last modified"
+}
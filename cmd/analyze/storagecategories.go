@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// systemDataCategory is what System Settings calls everything it cannot attribute.
+const systemDataCategory = "macOS + System Data"
+
+// storageCategoryRule mirrors one row of System Settings > General > Storage. Roots are
+// home-relative unless absolute; Spotlight finds matching bundles stored elsewhere.
+type storageCategoryRule struct {
+	Name      string
+	Roots     []string
+	Spotlight string
+}
+
+var storageCategoryRules = []storageCategoryRule{
+	{Name: "Applications", Roots: []string{"/Applications", "Applications"}, Spotlight: `kMDItemContentType == "com.apple.application-bundle"`},
+	{Name: "Documents", Roots: []string{"Documents", "Desktop", "Downloads"}},
+	{Name: "Developer", Roots: []string{"Library/Developer", "/Library/Developer"}},
+	{Name: "iCloud Drive", Roots: []string{"Library/Mobile Documents"}},
+	{Name: "Mail", Roots: []string{"Library/Mail", "Library/Containers/com.apple.mail"}},
+	{Name: "Messages", Roots: []string{"Library/Messages"}},
+	{Name: "Music", Roots: []string{"Music"}},
+	{Name: "Photos", Roots: []string{"Pictures"}, Spotlight: `kMDItemContentType == "com.apple.photos.library"`},
+	{Name: "Trash", Roots: []string{".Trash"}},
+}
+
+// storageCategoryPath is one concrete folder counted toward a category.
+type storageCategoryPath struct {
+	Path string
+	Size int64
+}
+
+// storageCategory is a System Settings category with the paths that make it up.
+type storageCategory struct {
+	Name  string
+	Size  int64
+	Paths []storageCategoryPath // Largest first; empty for the System Data remainder
+}
+
+type storageCategoriesMsg struct {
+	Categories []storageCategory
+	Used       int64 // Bytes in use on the startup volume
+	Err        error
+}
+
+// storageCategoryRoots resolves a rule's folders under home and adds Spotlight hits
+// not already inside one of them, so a photo library in Pictures is counted once.
+func storageCategoryRoots(rule storageCategoryRule, home string, found []string) []string {
+	var roots []string
+	for _, root := range rule.Roots {
+		if !filepath.IsAbs(root) {
+			root = filepath.Join(home, root)
+		}
+		roots = append(roots, root)
+	}
+	// Parents sort first, so helper apps nested in a found bundle are skipped too.
+	sort.Strings(found)
+	for _, path := range found {
+		covered := false
+		for _, root := range roots {
+			if path == root || strings.HasPrefix(path, root+"/") {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			roots = append(roots, path)
+		}
+	}
+	return roots
+}
+
+// spotlightMatches lists bundles in home matching query; nil when Spotlight is off.
+func spotlightMatches(home, query string) []string {
+	if query == "" {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), mdlsTimeout)
+	defer cancel()
+	output, err := exec.CommandContext(ctx, "mdfind", "-onlyin", home, query).Output()
+	if err != nil {
+		return nil
+	}
+	var paths []string
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line != "" && !isCloudPath(line) && !isExcludedPath(home, line) {
+			paths = append(paths, line)
+		}
+	}
+	return paths
+}
+
+// volumeUsedBytes is the space in use on the volume holding path, as System Settings counts it.
+func volumeUsedBytes(path string) (int64, error) {
+	var fs syscall.Statfs_t
+	if err := syscall.Statfs(path, &fs); err != nil {
+		return 0, err
+	}
+	return int64((uint64(fs.Blocks) - uint64(fs.Bavail)) * uint64(fs.Bsize)), nil
+}
+
+// finishStorageCategories sorts categories and paths largest first and appends the
+// System Data remainder: whatever the volume uses that no category accounts for.
+func finishStorageCategories(categories []storageCategory, used int64) []storageCategory {
+	var attributed int64
+	for i := range categories {
+		sort.Slice(categories[i].Paths, func(a, b int) bool {
+			return categories[i].Paths[a].Size > categories[i].Paths[b].Size
+		})
+		attributed += categories[i].Size
+	}
+	sort.SliceStable(categories, func(a, b int) bool { return categories[a].Size > categories[b].Size })
+	if used > attributed {
+		categories = append(categories, storageCategory{Name: systemDataCategory, Size: used - attributed})
+	}
+	return categories
+}
+
+// measureStorageCategoriesCmd sizes every category's folders with du, a few at a time.
+func measureStorageCategoriesCmd(home string) tea.Cmd {
+	return func() tea.Msg {
+		used, err := volumeUsedBytes("/")
+		if err != nil {
+			return storageCategoriesMsg{Err: err}
+		}
+		categories := make([]storageCategory, len(storageCategoryRules))
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, 4)
+		for i, rule := range storageCategoryRules {
+			categories[i].Name = rule.Name
+			for _, root := range storageCategoryRoots(rule, home, spotlightMatches(home, rule.Spotlight)) {
+				if _, err := os.Lstat(root); err != nil {
+					continue
+				}
+				wg.Add(1)
+				go func(i int, root string) {
+					defer wg.Done()
+					sem <- struct{}{}
+					size, err := getDirectorySizeFromDu(root)
+					<-sem
+					if err != nil || size <= 0 {
+						return
+					}
+					mu.Lock()
+					categories[i].Size += size
+					categories[i].Paths = append(categories[i].Paths, storageCategoryPath{Path: root, Size: size})
+					mu.Unlock()
+				}(i, root)
+			}
+		}
+		wg.Wait()
+		return storageCategoriesMsg{Categories: finishStorageCategories(categories, used), Used: used}
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStorageCategoryRootsSkipsCoveredSpotlightHits(t *testing.T) {
+	rule := storageCategoryRule{Name: "Photos", Roots: []string{"Pictures", "/Volumes/Shared"}}
+	found := []string{
+		"/Users/me/Desktop/Trip.photoslibrary/Helper.app",
+		"/Users/me/Pictures/Photos Library.photoslibrary",
+		"/Users/me/Desktop/Trip.photoslibrary",
+		"/Users/me/PicturesOld.photoslibrary",
+	}
+	got := storageCategoryRoots(rule, "/Users/me", found)
+	want := []string{
+		"/Users/me/Pictures",
+		"/Volumes/Shared",
+		"/Users/me/Desktop/Trip.photoslibrary",
+		"/Users/me/PicturesOld.photoslibrary",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("roots = %v, want %v", got, want)
+	}
+}
+
+func TestFinishStorageCategoriesAddsSystemDataRemainder(t *testing.T) {
+	categories := []storageCategory{
+		{Name: "Music", Size: 10, Paths: []storageCategoryPath{{Path: "/m", Size: 10}}},
+		{Name: "Documents", Size: 30, Paths: []storageCategoryPath{{Path: "/a", Size: 5}, {Path: "/b", Size: 25}}},
+	}
+	got := finishStorageCategories(categories, 100)
+	if len(got) != 3 || got[0].Name != "Documents" || got[1].Name != "Music" {
+		t.Fatalf("unexpected order: %+v", got)
+	}
+	if got[0].Paths[0].Path != "/b" {
+		t.Fatalf("paths not sorted largest first: %+v", got[0].Paths)
+	}
+	if last := got[2]; last.Name != systemDataCategory || last.Size != 60 || len(last.Paths) != 0 {
+		t.Fatalf("unexpected remainder: %+v", last)
+	}
+
+	if got := finishStorageCategories([]storageCategory{{Name: "Music", Size: 10}}, 5); len(got) != 1 {
+		t.Fatalf("remainder should be dropped when categories exceed used space: %+v", got)
+	}
+}
@@ -0,0 +1,133 @@
+//go:build darwin
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// storageView is the "C" screen lining Mole's numbers up with System Settings' storage categories.
+type storageView struct {
+	Categories []storageCategory
+	Used       int64
+	Scanning   bool
+	Err        error
+	Selected   int // Index into rows()
+}
+
+// storageRow is a category heading (Path empty) or one of its folders.
+type storageRow struct {
+	Category int
+	Path     string
+	Size     int64
+}
+
+func (v *storageView) rows() []storageRow {
+	var rows []storageRow
+	for i, category := range v.Categories {
+		rows = append(rows, storageRow{Category: i, Size: category.Size})
+		for _, path := range category.Paths {
+			rows = append(rows, storageRow{Category: i, Path: path.Path, Size: path.Size})
+		}
+	}
+	return rows
+}
+
+func (m model) openStorageCategories() (tea.Model, tea.Cmd) {
+	if m.inventory != nil {
+		m.status = fmt.Sprintf("%s is a read-only listing", m.inventory.Source)
+		return m, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		m.status = "Unable to find your home folder"
+		return m, nil
+	}
+	m.storage = &storageView{Scanning: true}
+	m.status = "Measuring storage categories..."
+	return m, tea.Batch(measureStorageCategoriesCmd(home), tickCmd())
+}
+
+// updateStorageKey handles keys while the category breakdown is open.
+func (m model) updateStorageKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	v := m.storage
+	rows := v.rows()
+	switch msg.String() {
+	case "q", "Q", "ctrl+c":
+		return m.quit()
+	case "esc", "b", "left", "h", "C":
+		m.storage = nil
+	case "up", "k":
+		v.Selected = max(v.Selected-1, 0)
+	case "down", "j":
+		v.Selected = min(v.Selected+1, max(len(rows)-1, 0))
+	case "enter", "right", "l":
+		// Browse the folder, or a category's largest folder, with the regular scanner.
+		if v.Scanning || v.Selected >= len(rows) {
+			return m, nil
+		}
+		row := rows[v.Selected]
+		path := row.Path
+		if paths := v.Categories[row.Category].Paths; path == "" && len(paths) > 0 {
+			path = paths[0].Path
+		}
+		if path == "" {
+			m.status = "System Data is what macOS cannot attribute to a folder Mole can read"
+			return m, nil
+		}
+		m.storage = nil
+		return m.openDir(path)
+	}
+	return m, nil
+}
+
+// renderStorage lists categories largest first, each followed by the folders it counts.
+func (m model) renderStorage() string {
+	v := m.storage
+	var b strings.Builder
+	if v.Scanning {
+		fmt.Fprintf(&b, "%s%s%s%s Measuring storage categories...\n",
+			colorCyan, colorBold, spinnerFrames[m.spinner], colorReset)
+		return b.String()
+	}
+	if v.Err != nil || len(v.Categories) == 0 {
+		fmt.Fprintf(&b, "  %sUnable to read the startup volume%s\n\n", colorGray, colorReset)
+		fmt.Fprintf(&b, "%sC/← Back | Q Quit%s\n", colorGray, colorReset)
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "%sStorage categories:%s %s%s%s used on the startup volume\n\n",
+		colorCyan, colorReset, colorYellow, humanizeBytes(v.Used), colorReset)
+
+	rows := v.rows()
+	viewport := calculateViewport(m.height, true)
+	nameWidth := calculateNameWidth(m.width) + 20
+	start := max(0, v.Selected-viewport+1)
+	for i := start; i < min(len(rows), start+viewport); i++ {
+		row := rows[i]
+		prefix, color := "   ", ""
+		if i == v.Selected {
+			prefix, color = fmt.Sprintf(" %s%s▶%s ", colorCyan, colorBold, colorReset), colorCyan
+		}
+		if row.Path == "" {
+			percent := 0.0
+			if v.Used > 0 {
+				percent = float64(row.Size) / float64(v.Used) * 100
+			}
+			fmt.Fprintf(&b, "%s%s %5.1f%%  |  %s%s%-22s%s %10s\n",
+				prefix, coloredProgressBar(row.Size, v.Used, percent), percent,
+				color, colorBold, v.Categories[row.Category].Name, colorReset, humanizeBytes(row.Size))
+			continue
+		}
+		fmt.Fprintf(&b, "%s      %s%s%s %s%10s%s\n",
+			prefix, color, padName(truncateMiddle(displayPath(row.Path), nameWidth), nameWidth), colorReset, colorGray, humanizeBytes(row.Size), colorReset)
+	}
+
+	fmt.Fprintln(&b)
+	fmt.Fprintf(&b, "%s↑↓ | Enter Browse | C/← Back | Q Quit%s\n", colorGray, colorReset)
+	return b.String()
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// strategiesFile maps path globs to scan strategies, one "<glob> <strategy>" per line:
+//
+//	/Volumes/NAS      estimate
+//	~/Projects/*      depth=2
+//	/Volumes/Archive  skip
+const strategiesFile = "analyze_strategies"
+
+// scanStrategy overrides how a directory is sized.
+type scanStrategy int
+
+const (
+	strategyDefault  scanStrategy = iota
+	strategyWalk                  // Always walk file by file, even folded dirs
+	strategyDu                    // Always size with du, never expand
+	strategyEstimate              // Sample a few subdirectories and extrapolate
+	strategySkip                  // Never scan
+)
+
+func (s scanStrategy) label() string {
+	switch s {
+	case strategyWalk:
+		return "walk"
+	case strategyDu:
+		return "du"
+	case strategyEstimate:
+		return "estimate"
+	case strategySkip:
+		return "skip"
+	default:
+		return ""
+	}
+}
+
+// strategyRule applies Strategy to every directory matching Pattern and its subtree.
+// Depth > 0 is a depth limit: the first Depth levels are walked, deeper ones use du.
+type strategyRule struct {
+	Pattern  string
+	Strategy scanStrategy
+	Depth    int
+}
+
+var (
+	strategiesMu        sync.Mutex
+	strategyRules       []strategyRule
+	strategiesLoaded    bool
+	configStrategyRules []strategyRule // Path excludes from config.toml and --exclude, as skip rules
+)
+
+// parseStrategyLine reads "<glob> <strategy>"; the glob may contain spaces.
+func parseStrategyLine(line, home string) (strategyRule, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return strategyRule{}, false
+	}
+	idx := strings.LastIndexAny(line, " \t")
+	if idx < 0 {
+		return strategyRule{}, false
+	}
+	pattern, value := strings.TrimSpace(line[:idx]), strings.ToLower(line[idx+1:])
+	if pattern == "~" || strings.HasPrefix(pattern, "~/") {
+		pattern = home + pattern[1:]
+	}
+	if !filepath.IsAbs(pattern) {
+		return strategyRule{}, false
+	}
+	if _, err := filepath.Match(pattern, pattern); err != nil {
+		return strategyRule{}, false
+	}
+
+	rule := strategyRule{Pattern: filepath.Clean(pattern)}
+	switch {
+	case value == "walk" || value == "deep":
+		rule.Strategy = strategyWalk
+	case value == "du":
+		rule.Strategy = strategyDu
+	case value == "estimate":
+		rule.Strategy = strategyEstimate
+	case value == "skip" || value == "never":
+		rule.Strategy = strategySkip
+	case strings.HasPrefix(value, "depth="):
+		depth, err := strconv.Atoi(strings.TrimPrefix(value, "depth="))
+		if err != nil || depth < 0 {
+			return strategyRule{}, false
+		}
+		if depth == 0 {
+			rule.Strategy = strategyDu
+		} else {
+			rule.Strategy = strategyWalk
+			rule.Depth = depth
+		}
+	default:
+		return strategyRule{}, false
+	}
+	return rule, true
+}
+
+func ensureStrategiesLoadedLocked() {
+	if strategiesLoaded {
+		return
+	}
+	strategiesLoaded = true
+	strategyRules = append([]strategyRule(nil), configStrategyRules...)
+
+	configDir, err := getConfigDir()
+	if err != nil {
+		return
+	}
+	file, err := os.Open(filepath.Join(configDir, strategiesFile))
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	home, _ := os.UserHomeDir()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if rule, ok := parseStrategyLine(scanner.Text(), home); ok {
+			strategyRules = append(strategyRules, rule)
+		}
+	}
+}
+
+// resolveStrategy finds the rule matching the closest ancestor-or-self of path.
+func resolveStrategy(rules []strategyRule, path string) scanStrategy {
+	best, bestLevel := -1, -1
+	for i, rule := range rules {
+		level := 0
+		for p := filepath.Clean(path); ; p = filepath.Dir(p) {
+			if ok, _ := filepath.Match(rule.Pattern, p); ok {
+				if best < 0 || level < bestLevel || (level == bestLevel && len(rule.Pattern) > len(rules[best].Pattern)) {
+					best, bestLevel = i, level
+				}
+				break
+			}
+			if p == filepath.Dir(p) {
+				break
+			}
+			level++
+		}
+	}
+	if best < 0 {
+		return strategyDefault
+	}
+	rule := rules[best]
+	if rule.Depth > 0 && bestLevel >= rule.Depth {
+		return strategyDu
+	}
+	return rule.Strategy
+}
+
+// scanStrategyFor returns the user's override for path, if any.
+func scanStrategyFor(path string) scanStrategy {
+	strategiesMu.Lock()
+	defer strategiesMu.Unlock()
+	ensureStrategiesLoadedLocked()
+	if len(strategyRules) == 0 {
+		return strategyDefault
+	}
+	return resolveStrategy(strategyRules, path)
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func resetStrategiesForTest() {
+	strategiesMu.Lock()
+	strategyRules = nil
+	strategiesLoaded = false
+	strategiesMu.Unlock()
+}
+
+func TestParseStrategyLine(t *testing.T) {
+	cases := []struct {
+		line string
+		want strategyRule
+		ok   bool
+	}{
+		{"/Volumes/NAS estimate", strategyRule{Pattern: "/Volumes/NAS", Strategy: strategyEstimate}, true},
+		{"~/Library/Mobile Documents   skip", strategyRule{Pattern: "/Users/me/Library/Mobile Documents", Strategy: strategySkip}, true},
+		{"~/Projects/* depth=2", strategyRule{Pattern: "/Users/me/Projects/*", Strategy: strategyWalk, Depth: 2}, true},
+		{"/opt depth=0", strategyRule{Pattern: "/opt", Strategy: strategyDu}, true},
+		{"# comment", strategyRule{}, false},
+		{"relative/path du", strategyRule{}, false},
+		{"/tmp sometimes", strategyRule{}, false},
+	}
+	for _, tc := range cases {
+		got, ok := parseStrategyLine(tc.line, "/Users/me")
+		if ok != tc.ok || got != tc.want {
+			t.Fatalf("parseStrategyLine(%q) = %+v, %v; want %+v, %v", tc.line, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestResolveStrategyPrefersClosestRule(t *testing.T) {
+	rules := []strategyRule{
+		{Pattern: "/Users/me", Strategy: strategyWalk},
+		{Pattern: "/Users/me/NAS", Strategy: strategyEstimate},
+		{Pattern: "/Users/me/src/*", Strategy: strategyWalk, Depth: 2},
+	}
+	cases := map[string]scanStrategy{
+		"/Users/me/Documents":        strategyWalk,
+		"/Users/me/NAS/photos/2020":  strategyEstimate,
+		"/Users/me/src/app":          strategyWalk,
+		"/Users/me/src/app/lib":      strategyWalk,
+		"/Users/me/src/app/lib/deep": strategyDu,
+		"/opt/homebrew":              strategyDefault,
+	}
+	for path, want := range cases {
+		if got := resolveStrategy(rules, path); got != want {
+			t.Fatalf("resolveStrategy(%s) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestScanHonorsStrategyOverrides(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	resetStrategiesForTest()
+	t.Cleanup(resetStrategiesForTest)
+
+	root := filepath.Join(home, "data")
+	writeFileWithSize(t, filepath.Join(root, "kept", "a.bin"), 4096)
+	writeFileWithSize(t, filepath.Join(root, "ignored", "b.bin"), 8192)
+
+	configDir, err := getConfigDir()
+	if err != nil {
+		t.Fatalf("config dir: %v", err)
+	}
+	rules := filepath.Join(root, "ignored") + " skip\n"
+	if err := os.WriteFile(filepath.Join(configDir, strategiesFile), []byte(rules), 0o644); err != nil {
+		t.Fatalf("write rules: %v", err)
+	}
+
+	var files, dirs, bytes int64
+	var current string
+	result, err := scanPathConcurrent(context.Background(), root, &files, &dirs, &bytes, &current, nil)
+	if err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+	for _, entry := range result.Entries {
+		if entry.Name == "ignored" && (entry.Size != 0 || entry.Strategy != strategySkip) {
+			t.Fatalf("skipped dir should be listed unsized, got %+v", entry)
+		}
+	}
+	if result.TotalSize >= 8192 {
+		t.Fatalf("skipped dir counted in total: %d", result.TotalSize)
+	}
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// backupDBName is the root of HFS+ Time Machine destinations, one folder per machine.
+	backupDBName = "Backups.backupdb"
+	// backupTimeLayout is how Time Machine names each snapshot folder.
+	backupTimeLayout = "2006-01-02-150405"
+)
+
+// backupSnapshotPattern matches "2024-05-01-120000", plus the ".backup",
+// ".inprogress", ".interrupted" and ".previous" suffixes used on APFS destinations.
+var backupSnapshotPattern = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2}-\d{6})(\.[a-z]+)?$`)
+
+// backupSnapshot is one dated backup in a set.
+type backupSnapshot struct {
+	Name        string
+	Path        string
+	Time        time.Time
+	Total       int64 // Everything the snapshot shows
+	Incremental int64 // Bytes no earlier snapshot already had
+	Files       int64
+}
+
+type backupSnapshotsMsg struct {
+	Root      string
+	Snapshots []backupSnapshot
+	Err       error
+}
+
+// backupFileKey identifies unchanged file content across snapshots. Hard-linked HFS+
+// trees share the inode; mounted APFS snapshots keep inode numbers but get their own
+// device, so the device is left out and size and mtime guard against inode reuse.
+type backupFileKey struct {
+	ino   uint64
+	size  int64
+	mtime int64
+}
+
+// parseBackupSnapshotName returns the time encoded in a snapshot folder name.
+func parseBackupSnapshotName(name string) (time.Time, bool) {
+	match := backupSnapshotPattern.FindStringSubmatch(name)
+	if match == nil {
+		return time.Time{}, false
+	}
+	t, err := time.ParseInLocation(backupTimeLayout, match[1], time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// listBackupSnapshots returns the dated snapshots directly under dir, oldest first.
+func listBackupSnapshots(dir string) []backupSnapshot {
+	children, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var snapshots []backupSnapshot
+	for _, child := range children {
+		if !child.IsDir() {
+			continue
+		}
+		if t, ok := parseBackupSnapshotName(child.Name()); ok {
+			snapshots = append(snapshots, backupSnapshot{Name: child.Name(), Path: filepath.Join(dir, child.Name()), Time: t})
+		}
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].Time.Before(snapshots[j].Time) })
+	return snapshots
+}
+
+// backupSetRoot finds the folder holding the snapshots for path: path itself, an
+// ancestor when browsing inside a snapshot, or the single machine in a Backups.backupdb.
+func backupSetRoot(path string) (string, bool) {
+	for dir := filepath.Clean(path); ; dir = filepath.Dir(dir) {
+		if len(listBackupSnapshots(dir)) > 0 {
+			return dir, true
+		}
+		if filepath.Base(dir) == backupDBName {
+			break
+		}
+		if parent := filepath.Dir(dir); parent == dir {
+			break
+		}
+	}
+	db := filepath.Join(path, backupDBName)
+	if filepath.Base(path) == backupDBName {
+		db = path
+	}
+	machines, err := os.ReadDir(db)
+	if err != nil {
+		return "", false
+	}
+	var found []string
+	for _, machine := range machines {
+		dir := filepath.Join(db, machine.Name())
+		if machine.IsDir() && len(listBackupSnapshots(dir)) > 0 {
+			found = append(found, dir)
+		}
+	}
+	if len(found) != 1 {
+		return "", false
+	}
+	return found[0], true
+}
+
+// measureBackupSnapshots walks snapshots oldest first, charging each file's bytes to
+// the first snapshot that holds it. Later snapshots only add what changed.
+func measureBackupSnapshots(snapshots []backupSnapshot, walked *int64) []backupSnapshot {
+	seen := make(map[backupFileKey]bool)
+	for i := range snapshots {
+		snapshot := &snapshots[i]
+		_ = filepath.WalkDir(snapshot.Path, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || d.Type()&fs.ModeSymlink != 0 {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			size := getActualFileSize(path, info)
+			snapshot.Total += size
+			snapshot.Files++
+			if walked != nil {
+				atomic.AddInt64(walked, 1)
+			}
+			stat, ok := info.Sys().(*syscall.Stat_t)
+			if !ok {
+				snapshot.Incremental += size
+				return nil
+			}
+			key := backupFileKey{ino: uint64(stat.Ino), size: info.Size(), mtime: info.ModTime().UnixNano()}
+			if !seen[key] {
+				seen[key] = true
+				snapshot.Incremental += size
+			}
+			return nil
+		})
+	}
+	return snapshots
+}
+
+func measureBackupSnapshotsCmd(root string, walked *int64) tea.Cmd {
+	return func() tea.Msg {
+		snapshots := listBackupSnapshots(root)
+		if len(snapshots) == 0 {
+			return backupSnapshotsMsg{Root: root, Err: os.ErrNotExist}
+		}
+		return backupSnapshotsMsg{Root: root, Snapshots: measureBackupSnapshots(snapshots, walked)}
+	}
+}
+
+// backupSnapshotLabel shows a snapshot by its date, keeping any APFS state suffix.
+func backupSnapshotLabel(snapshot backupSnapshot) string {
+	label := snapshot.Time.Format("2006-01-02 15:04")
+	if suffix := strings.TrimPrefix(filepath.Ext(snapshot.Name), "."); suffix != "" && suffix != "backup" {
+		label += " (" + suffix + ")"
+	}
+	return label
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseBackupSnapshotName(t *testing.T) {
+	for _, name := range []string{"2024-05-01-120000", "2024-05-01-120000.backup", "2024-05-01-120000.inprogress"} {
+		if ts, ok := parseBackupSnapshotName(name); !ok || ts.Hour() != 12 || ts.Day() != 1 {
+			t.Errorf("parseBackupSnapshotName(%q) = %v, %v", name, ts, ok)
+		}
+	}
+	for _, name := range []string{"Latest", "2024-05-01", "2024-13-01-120000"} {
+		if _, ok := parseBackupSnapshotName(name); ok {
+			t.Errorf("parseBackupSnapshotName(%q) should not match", name)
+		}
+	}
+}
+
+func TestBackupSnapshotsChargeSharedFilesOnce(t *testing.T) {
+	volume := t.TempDir()
+	machine := filepath.Join(volume, backupDBName, "MacBook")
+	first := filepath.Join(machine, "2024-05-01-120000", "Macintosh HD", "Users")
+	second := filepath.Join(machine, "2024-05-02-120000", "Macintosh HD", "Users")
+	writeFileWithSize(t, filepath.Join(first, "big.bin"), 64*1024)
+	writeFileWithSize(t, filepath.Join(second, "new.bin"), 16*1024)
+	if err := os.Link(filepath.Join(first, "big.bin"), filepath.Join(second, "big.bin")); err != nil {
+		t.Skipf("hard links unsupported: %v", err)
+	}
+	if err := os.Symlink("2024-05-02-120000", filepath.Join(machine, "Latest")); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, start := range []string{volume, machine, second} {
+		if root, ok := backupSetRoot(start); !ok || root != machine {
+			t.Fatalf("backupSetRoot(%s) = %q, %v; want %s", start, root, ok, machine)
+		}
+	}
+	if _, ok := backupSetRoot(t.TempDir()); ok {
+		t.Fatal("plain folder should not look like a backup set")
+	}
+
+	snapshots := listBackupSnapshots(machine)
+	if len(snapshots) != 2 || snapshots[0].Name != "2024-05-01-120000" {
+		t.Fatalf("unexpected snapshots: %+v", snapshots)
+	}
+	var walked int64
+	snapshots = measureBackupSnapshots(snapshots, &walked)
+
+	big, _ := os.Stat(filepath.Join(first, "big.bin"))
+	small, _ := os.Stat(filepath.Join(second, "new.bin"))
+	bigSize, newSize := getActualFileSize("", big), getActualFileSize("", small)
+	if snapshots[0].Incremental != bigSize || snapshots[0].Total != bigSize {
+		t.Fatalf("first snapshot = %+v; want %d added", snapshots[0], bigSize)
+	}
+	if snapshots[1].Incremental != newSize || snapshots[1].Total != bigSize+newSize {
+		t.Fatalf("second snapshot = %+v; want only %d added", snapshots[1], newSize)
+	}
+	if walked != 3 {
+		t.Fatalf("walked %d files; want 3", walked)
+	}
+}
@@ -0,0 +1,82 @@
+//go:build darwin
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const permanentDeleteEnvVar = "MO_ANALYZE_PERMANENT"
+
+// errTrashUnavailable means path cannot be moved to a Trash without copying it.
+var errTrashUnavailable = errors.New("no Trash on this volume, use permanent delete")
+
+// trashDirFor picks the Trash Finder would use: ~/.Trash on the home volume,
+// otherwise <volume>/.Trashes/<uid>.
+func trashDirFor(path string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	dev, err := deviceOf(path)
+	if err != nil {
+		return "", err
+	}
+	if homeDev, err := deviceOf(home); err == nil && homeDev == dev {
+		return filepath.Join(home, ".Trash"), nil
+	}
+	root, err := mountPointOf(path)
+	if err != nil {
+		return "", err
+	}
+	if root == "/" {
+		return "", errTrashUnavailable
+	}
+	return filepath.Join(root, ".Trashes", strconv.Itoa(os.Getuid())), nil
+}
+
+// trashDestination avoids collisions the way Finder does: "name 2.ext", "name 3.ext", ...
+func trashDestination(trashDir, name string) string {
+	dest := filepath.Join(trashDir, name)
+	if _, err := os.Lstat(dest); os.IsNotExist(err) {
+		return dest
+	}
+	ext := filepath.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+	if stem == "" {
+		stem, ext = name, ""
+	}
+	for i := 2; i < 1000; i++ {
+		dest = filepath.Join(trashDir, fmt.Sprintf("%s %d%s", stem, i, ext))
+		if _, err := os.Lstat(dest); os.IsNotExist(err) {
+			return dest
+		}
+	}
+	return filepath.Join(trashDir, fmt.Sprintf("%s %s%s", stem, time.Now().Format("15.04.05.000"), ext))
+}
+
+// moveToTrash renames path into its volume's Trash and returns the new location.
+func moveToTrash(path string) (string, error) {
+	trashDir, err := trashDirFor(path)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(trashDir, 0700); err != nil {
+		return "", err
+	}
+	dest := trashDestination(trashDir, filepath.Base(path))
+	if err := os.Rename(path, dest); err != nil {
+		if errors.Is(err, syscall.EXDEV) {
+			return "", errTrashUnavailable
+		}
+		return "", err
+	}
+	return dest, nil
+}
@@ -0,0 +1,50 @@
+//go:build darwin
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTrashDestinationAvoidsCollisions(t *testing.T) {
+	trash := t.TempDir()
+	if got := trashDestination(trash, "report.pdf"); got != filepath.Join(trash, "report.pdf") {
+		t.Fatalf("free name should be kept, got %s", got)
+	}
+	for _, name := range []string{"report.pdf", "report 2.pdf", ".env"} {
+		if err := os.WriteFile(filepath.Join(trash, name), nil, 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	if got := trashDestination(trash, "report.pdf"); got != filepath.Join(trash, "report 3.pdf") {
+		t.Fatalf("expected numbered name, got %s", got)
+	}
+	if got := trashDestination(trash, ".env"); got != filepath.Join(trash, ".env 2") {
+		t.Fatalf("dotfile should be numbered after its name, got %s", got)
+	}
+}
+
+func TestMoveToTrashUsesHomeTrash(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	target := filepath.Join(home, "Projects", "old")
+	if err := os.MkdirAll(target, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	dest, err := moveToTrash(target)
+	if err != nil {
+		t.Fatalf("moveToTrash: %v", err)
+	}
+	if dest != filepath.Join(home, ".Trash", "old") {
+		t.Fatalf("unexpected destination %s", dest)
+	}
+	if _, err := os.Stat(target); !os.IsNotExist(err) {
+		t.Fatalf("source should be gone, err=%v", err)
+	}
+	if _, err := os.Stat(dest); err != nil {
+		t.Fatalf("item should be in Trash: %v", err)
+	}
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// uninstallRoot is a Library folder whose entries are named after the app that owns them.
+type uninstallRoot struct {
+	Kind   string
+	Root   string // Home-relative
+	ByName bool   // Entries may use the app's name instead of its bundle ID
+	Group  bool   // Entries are app groups
+}
+
+// uninstallRoots are searched for an app's data when it is uninstalled.
+var uninstallRoots = []uninstallRoot{
+	{Kind: "support files", Root: "Library/Application Support", ByName: true},
+	{Kind: "caches", Root: "Library/Caches", ByName: true},
+	{Kind: "preferences", Root: "Library/Preferences"},
+	{Kind: "preferences", Root: "Library/Preferences/ByHost"},
+	{Kind: "container", Root: "Library/Containers"},
+	{Kind: "group container", Root: "Library/Group Containers", Group: true},
+	{Kind: "launch agent", Root: "Library/LaunchAgents"},
+	{Kind: "saved state", Root: "Library/Saved Application State"},
+	{Kind: "web data", Root: "Library/HTTPStorages"},
+	{Kind: "web data", Root: "Library/WebKit"},
+	{Kind: "cookies", Root: "Library/Cookies"},
+	{Kind: "logs", Root: "Library/Logs", ByName: true},
+}
+
+// uninstallItem is one bundle or data folder removed with an app.
+type uninstallItem struct {
+	Kind string
+	Path string
+	Size int64
+}
+
+// uninstallPlan is an app and everything found with it, the bundle first and its data
+// largest first.
+type uninstallPlan struct {
+	App   installedApp
+	Items []uninstallItem
+	Size  int64
+}
+
+type uninstallPlanMsg struct {
+	Plan uninstallPlan
+	Err  error
+}
+
+func (p uninstallPlan) paths() []string {
+	paths := make([]string, len(p.Items))
+	for i, item := range p.Items {
+		paths[i] = item.Path
+	}
+	return paths
+}
+
+// ownsEntry reports whether a Library entry named name belongs to the app with bundle
+// ID id: the ID itself, or the ID followed by a suffix such as ".plist" or ".helper".
+// Entries that belong to another installed app extending the ID, as
+// "com.vendor.app.pro" extends "com.vendor.app", are left alone. All names lower-case.
+func ownsEntry(name, id string, others []string) bool {
+	if name != id && !strings.HasPrefix(name, id+".") {
+		return false
+	}
+	for _, other := range others {
+		if len(other) > len(id) && (name == other || strings.HasPrefix(name, other+".")) {
+			return false
+		}
+	}
+	return true
+}
+
+// findAppLeftovers lists app's data under home, matched by bundle ID and, where apps
+// use it, by name. others are the lower-case bundle IDs of every other installed app.
+func findAppLeftovers(home string, app installedApp, others []string) []uninstallItem {
+	id, name := strings.ToLower(app.BundleID), strings.ToLower(app.Name)
+	var items []uninstallItem
+	for _, root := range uninstallRoots {
+		children, err := os.ReadDir(filepath.Join(home, root.Root))
+		if err != nil {
+			continue
+		}
+		for _, child := range children {
+			entry := strings.ToLower(child.Name())
+			match := ownsEntry(entry, id, others)
+			if !match && root.ByName && child.IsDir() {
+				match = entry == name
+			}
+			if !match && root.Group && child.IsDir() {
+				match = groupContainerOwner(child.Name(), []string{id}) == id
+			}
+			path := filepath.Join(home, root.Root, child.Name())
+			if match && !isExcludedPath(home, path) {
+				items = append(items, uninstallItem{Kind: root.Kind, Path: path})
+			}
+		}
+	}
+	return items
+}
+
+// uninstallBlocked explains why an app must not be uninstalled, or returns nil.
+func uninstallBlocked(app installedApp) error {
+	if isSIPProtected(app.Path) || strings.HasPrefix(strings.ToLower(app.BundleID), "com.apple.") {
+		return fmt.Errorf("%s is part of macOS and cannot be uninstalled", app.Name)
+	}
+	return nil
+}
+
+// appRunning reports whether a process runs from inside the bundle.
+func appRunning(appPath string) bool {
+	return exec.Command("pgrep", "-f", filepath.Join(appPath, "Contents", "MacOS")+"/").Run() == nil
+}
+
+// planUninstall finds the app at appPath and its data, sized as the cleanup preview sizes them.
+func planUninstall(home, appPath string) (uninstallPlan, error) {
+	app, ok := readInstalledApp(appPath)
+	if !ok {
+		return uninstallPlan{}, fmt.Errorf("%s has no bundle identifier to find its data by", filepath.Base(appPath))
+	}
+	if err := uninstallBlocked(app); err != nil {
+		return uninstallPlan{}, err
+	}
+	if appRunning(appPath) {
+		return uninstallPlan{}, fmt.Errorf("%s is running; quit it before uninstalling", app.Name)
+	}
+	var others []string
+	for _, other := range loadInstalledApps(home).ids {
+		if other != strings.ToLower(app.BundleID) {
+			others = append(others, other)
+		}
+	}
+	items := append([]uninstallItem{{Kind: "app", Path: appPath}}, findAppLeftovers(home, app, others)...)
+	var wg sync.WaitGroup
+	for i := range items {
+		wg.Add(1)
+		go func(item *uninstallItem) {
+			defer wg.Done()
+			item.Size = measureCleanPath(item.Path)
+		}(&items[i])
+	}
+	wg.Wait()
+	data := items[1:]
+	sort.SliceStable(data, func(a, b int) bool { return data[a].Size > data[b].Size })
+	plan := uninstallPlan{App: app, Items: items}
+	for _, item := range items {
+		plan.Size += item.Size
+	}
+	return plan, nil
+}
+
+func planUninstallCmd(home, appPath string) tea.Cmd {
+	return func() tea.Msg {
+		plan, err := planUninstall(home, appPath)
+		return uninstallPlanMsg{Plan: plan, Err: err}
+	}
+}
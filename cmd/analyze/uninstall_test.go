@@ -0,0 +1,66 @@
+package main
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestFindAppLeftovers(t *testing.T) {
+	home := t.TempDir()
+	for _, rel := range []string{
+		"Library/Application Support/com.example.Notes/db",
+		"Library/Application Support/Notes Pro/settings",
+		"Library/Caches/com.example.notes/blob",
+		"Library/Preferences/com.example.Notes.plist",
+		"Library/Preferences/ByHost/com.example.Notes.0A1B2C.plist",
+		"Library/Containers/com.example.Notes.ShareExtension/data",
+		"Library/Group Containers/ABCDE12345.com.example.Notes/shared",
+		"Library/LaunchAgents/com.example.Notes.helper.plist",
+		"Library/Saved Application State/com.example.Notes.savedState/window",
+		// Another installed app extending the ID, and unrelated data.
+		"Library/Preferences/com.example.Notes.Pro.plist",
+		"Library/Caches/com.example.NotesSync/blob",
+		"Library/Caches/com.other.app/blob",
+	} {
+		writeFileWithSize(t, filepath.Join(home, rel), 4096)
+	}
+
+	app := installedApp{BundleID: "com.example.Notes", Name: "Notes Pro", Path: "/Applications/Notes Pro.app"}
+	items := findAppLeftovers(home, app, []string{"com.example.notes.pro", "com.other.app"})
+	var got []string
+	for _, item := range items {
+		rel, _ := filepath.Rel(home, item.Path)
+		got = append(got, item.Kind+": "+rel)
+	}
+	sort.Strings(got)
+	want := []string{
+		"caches: Library/Caches/com.example.notes",
+		"container: Library/Containers/com.example.Notes.ShareExtension",
+		"group container: Library/Group Containers/ABCDE12345.com.example.Notes",
+		"launch agent: Library/LaunchAgents/com.example.Notes.helper.plist",
+		"preferences: Library/Preferences/ByHost/com.example.Notes.0A1B2C.plist",
+		"preferences: Library/Preferences/com.example.Notes.plist",
+		"saved state: Library/Saved Application State/com.example.Notes.savedState",
+		"support files: Library/Application Support/Notes Pro",
+		"support files: Library/Application Support/com.example.Notes",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("leftovers:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestUninstallBlocked(t *testing.T) {
+	for _, app := range []installedApp{
+		{BundleID: "com.apple.Safari", Name: "Safari", Path: "/Applications/Safari.app"},
+		{BundleID: "com.example.Tool", Name: "Tool", Path: "/System/Applications/Tool.app"},
+	} {
+		if err := uninstallBlocked(app); err == nil {
+			t.Errorf("%s at %s can be uninstalled", app.BundleID, app.Path)
+		}
+	}
+	if err := uninstallBlocked(installedApp{BundleID: "com.example.Notes", Name: "Notes", Path: "/Applications/Notes.app"}); err != nil {
+		t.Errorf("third-party app blocked: %v", err)
+	}
+}
@@ -0,0 +1,137 @@
+//go:build darwin
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// uninstallView is the ctrl+u screen listing an app bundle and every piece of Library data
+// found with it, removed together once confirmed.
+type uninstallView struct {
+	Plan     uninstallPlan
+	Scanning bool
+	Err      error
+	Selected int
+	Confirm  bool // ⌫ or Enter pressed once; the same key again removes everything
+}
+
+func (m model) openUninstall() (tea.Model, tea.Cmd) {
+	if m.inventory != nil {
+		m.status = fmt.Sprintf("%s is a read-only listing", m.inventory.Source)
+		return m, nil
+	}
+	entry, ok := m.selectedEntry()
+	if !ok || !strings.HasSuffix(entry.Path, ".app") {
+		m.status = "Select an app bundle to uninstall"
+		return m, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		m.status = "Unable to find your home folder"
+		return m, nil
+	}
+	m.uninstall = &uninstallView{Scanning: true}
+	m.status = fmt.Sprintf("Looking for %s's data...", displayName(entry.Path, entry.Name))
+	return m, tea.Batch(planUninstallCmd(home, entry.Path), tickCmd())
+}
+
+// updateUninstallKey handles keys while the uninstall list is open.
+func (m model) updateUninstallKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	v := m.uninstall
+	key := msg.String()
+	confirm := v.Confirm
+	v.Confirm = false
+	if confirm && key == "esc" {
+		m.status = "Cancelled"
+		return m, nil
+	}
+	switch key {
+	case "q", "Q", "ctrl+c":
+		return m.quit()
+	case "esc", "b", "left", "h", "ctrl+u":
+		m.uninstall = nil
+	case "up", "k":
+		v.Selected = max(v.Selected-1, 0)
+	case "down", "j":
+		v.Selected = min(v.Selected+1, max(len(v.Plan.Items)-1, 0))
+	case "enter", "delete", "backspace", "alt+delete", "alt+backspace":
+		if v.Scanning || v.Err != nil || m.deleting || len(v.Plan.Items) == 0 {
+			return m, nil
+		}
+		if !confirm {
+			v.Confirm = true
+			m.deletePermanent = m.permanentDelete || strings.HasPrefix(key, "alt+")
+			return m, nil
+		}
+		paths := v.Plan.paths()
+		if m.safeMode {
+			for _, path := range paths {
+				if _, allowed := safeDeleteCategory(path); !allowed {
+					m.status = safeModeBlockReason(path)
+					return m, nil
+				}
+			}
+		}
+		permanent := m.deletePermanent
+		m.deletePermanent = false
+		m.deleting = true
+		var deleteCount int64
+		m.deleteCount = &deleteCount
+		m.deleteBatch = &deleteBatchProgress{Total: len(paths), Permanent: permanent}
+		m.status = fmt.Sprintf("Uninstalling %s...", v.Plan.App.Name)
+		return m, tea.Batch(deletePathCmd(paths, m.deleteCount, m.deleteBatch, permanent), tickCmd())
+	}
+	return m, nil
+}
+
+// renderUninstall lists everything that goes with the app, so nothing is removed unseen.
+func (m model) renderUninstall() string {
+	v := m.uninstall
+	var b strings.Builder
+	if v.Scanning {
+		fmt.Fprintf(&b, "%s%s%s%s Looking for the app's data...\n", colorCyan, colorBold, spinnerFrames[m.spinner], colorReset)
+		return b.String()
+	}
+	if v.Err != nil {
+		fmt.Fprintf(&b, "  %s%s%s\n\n", colorGray, v.Err.Error(), colorReset)
+		fmt.Fprintf(&b, "%s^U/← Back | Q Quit%s\n", colorGray, colorReset)
+		return b.String()
+	}
+
+	plan := v.Plan
+	fmt.Fprintf(&b, "%sUninstall %s%s %s(%s)%s: %s%s%s in %d items\n\n",
+		colorCyan, plan.App.Name, colorReset, colorGray, plan.App.BundleID, colorReset,
+		colorYellow, humanizeBytes(plan.Size), colorReset, len(plan.Items))
+
+	viewport := calculateViewport(m.height, true)
+	nameWidth := calculateNameWidth(m.width) + 20
+	start := max(0, v.Selected-viewport+1)
+	for i := start; i < min(len(plan.Items), start+viewport); i++ {
+		item := plan.Items[i]
+		prefix, color := "   ", ""
+		if i == v.Selected {
+			prefix, color = fmt.Sprintf(" %s%s▶%s ", colorCyan, colorBold, colorReset), colorCyan
+		}
+		fmt.Fprintf(&b, "%s%s%-16s%s %s%s%s %10s\n",
+			prefix, colorGray, item.Kind, colorReset,
+			color, padName(trimNameWithWidth(displayPath(item.Path), nameWidth), nameWidth), colorReset, humanizeBytes(item.Size))
+	}
+
+	fmt.Fprintln(&b)
+	if v.Confirm {
+		verb := "Move to Trash:"
+		if m.deletePermanent {
+			verb = "Delete permanently:"
+		}
+		fmt.Fprintf(&b, "%s%s%s %s and %d data items (%s)  %sPress again  |  ESC cancel%s\n",
+			colorRed, verb, colorReset, plan.App.Name, len(plan.Items)-1, humanizeBytes(plan.Size), colorGray, colorReset)
+	} else {
+		fmt.Fprintf(&b, "%s↑↓ | Enter/⌫ Uninstall with everything listed | ^U/← Back | Q Quit%s\n", colorGray, colorReset)
+	}
+	return b.String()
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"syscall"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// verifyMinBytes is the smallest delete worth a reconciliation pass.
+	verifyMinBytes = 100 << 20
+	// verifyTolerance is the relative gap still reported as matching the plan.
+	verifyTolerance = 0.05
+)
+
+// deletionCheck is what a finished delete promised, captured before the view forgets the sizes.
+type deletionCheck struct {
+	Paths    []string
+	Expected int64            // Sum of the sizes shown for the deleted items
+	Shared   int64            // Part of Expected also referenced by hard links or clones
+	Trashed  bool             // Items went to the Trash, not away
+	Freed    int64            // Free space gained on the affected volumes, -1 if unknown
+	Roots    map[string]int64 // Parent folders and their size before the delete
+}
+
+// rootCheck compares a parent folder's size before and after the delete.
+type rootCheck struct {
+	Path   string
+	Before int64
+	After  int64
+}
+
+// verifyReport reconciles what was expected to be freed with what actually was.
+type verifyReport struct {
+	Expected     int64
+	Freed        int64
+	Leftover     []string
+	LeftoverSize int64
+	Roots        []rootCheck
+	Snapshots    int
+	Reasons      []string
+	Matched      bool
+}
+
+type verifyMsg struct {
+	Report verifyReport
+}
+
+// volumeFreeBytes returns free space per device for the folders holding paths.
+func volumeFreeBytes(paths []string) map[uint64]int64 {
+	free := make(map[uint64]int64)
+	for _, path := range paths {
+		dir := filepath.Dir(path)
+		var st syscall.Stat_t
+		if syscall.Stat(dir, &st) != nil {
+			continue
+		}
+		dev := uint64(st.Dev)
+		if _, ok := free[dev]; ok {
+			continue
+		}
+		var fs syscall.Statfs_t
+		if syscall.Statfs(dir, &fs) != nil {
+			continue
+		}
+		free[dev] = int64(uint64(fs.Bavail) * uint64(fs.Bsize))
+	}
+	return free
+}
+
+// freedBetween sums the growth in free space between two volumeFreeBytes samples.
+func freedBetween(before, after map[uint64]int64) int64 {
+	if len(before) == 0 {
+		return -1
+	}
+	var freed int64
+	for dev, was := range before {
+		if now, ok := after[dev]; ok {
+			freed += now - was
+		}
+	}
+	return freed
+}
+
+// explainGap lists the likely reasons the freed space differs from the plan.
+func explainGap(check deletionCheck, report *verifyReport) {
+	gap := check.Expected - report.Freed
+	report.Matched = report.Freed >= 0 && float64(abs64(gap)) <= float64(check.Expected)*verifyTolerance
+	if report.Matched {
+		return
+	}
+	if check.Trashed {
+		report.Reasons = append(report.Reasons, "Items are in the Trash; the space returns when it is emptied")
+	}
+	if len(report.Leftover) > 0 {
+		report.Reasons = append(report.Reasons, fmt.Sprintf("%d items are still on disk (%s)", len(report.Leftover), humanizeBytes(report.LeftoverSize)))
+	}
+	if check.Shared > 0 {
+		report.Reasons = append(report.Reasons, fmt.Sprintf("%s was shared with hard links or APFS clones and is still in use", humanizeBytes(check.Shared)))
+	}
+	if report.Snapshots > 0 && gap > 0 {
+		report.Reasons = append(report.Reasons, fmt.Sprintf("%d local snapshots may still hold the deleted data (L to review)", report.Snapshots))
+	}
+	if report.Freed < 0 {
+		report.Reasons = append(report.Reasons, "Free space could not be measured")
+	} else if gap < 0 {
+		report.Reasons = append(report.Reasons, "Other activity freed extra space at the same time")
+	}
+	if len(report.Reasons) == 0 {
+		report.Reasons = append(report.Reasons, "APFS can release space lazily; check again in a minute")
+	}
+}
+
+func abs64(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// verifyDeletion re-measures the affected roots and checks nothing was left behind.
+func verifyDeletion(check deletionCheck) verifyReport {
+	report := verifyReport{Expected: check.Expected, Freed: check.Freed}
+	for _, path := range check.Paths {
+		if _, err := os.Lstat(path); err == nil {
+			report.Leftover = append(report.Leftover, path)
+			if size, err := getDirectorySizeFromDu(path); err == nil {
+				report.LeftoverSize += size
+			}
+		}
+	}
+	for root, before := range check.Roots {
+		after, err := getDirectorySizeFromDu(root)
+		if err != nil {
+			continue
+		}
+		report.Roots = append(report.Roots, rootCheck{Path: root, Before: before, After: after})
+	}
+	sort.Slice(report.Roots, func(i, j int) bool { return report.Roots[i].Path < report.Roots[j].Path })
+	if !check.Trashed && len(check.Paths) > 0 {
+		volume := filepath.Dir(check.Paths[0])
+		if snapshots, err := listLocalSnapshots(volume); err == nil {
+			report.Snapshots = len(snapshots)
+		}
+	}
+	explainGap(check, &report)
+	return report
+}
+
+func verifyDeletionCmd(check deletionCheck) tea.Cmd {
+	return func() tea.Msg {
+		return verifyMsg{Report: verifyDeletion(check)}
+	}
+}
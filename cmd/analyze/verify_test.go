@@ -0,0 +1,68 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFreedBetween(t *testing.T) {
+	if got := freedBetween(nil, map[uint64]int64{1: 10}); got != -1 {
+		t.Fatalf("unknown before should be -1, got %d", got)
+	}
+	before := map[uint64]int64{1: 100, 2: 50}
+	after := map[uint64]int64{1: 400, 2: 40}
+	if got := freedBetween(before, after); got != 290 {
+		t.Fatalf("freedBetween = %d; want 290", got)
+	}
+}
+
+func TestExplainGap(t *testing.T) {
+	matched := verifyReport{Expected: 1000, Freed: 980}
+	explainGap(deletionCheck{Expected: 1000}, &matched)
+	if !matched.Matched || len(matched.Reasons) != 0 {
+		t.Fatalf("small gap should match the plan: %+v", matched)
+	}
+
+	trashed := verifyReport{Expected: 1000, Freed: 0}
+	explainGap(deletionCheck{Expected: 1000, Trashed: true, Shared: 200}, &trashed)
+	joined := strings.Join(trashed.Reasons, "\n")
+	if trashed.Matched || !strings.Contains(joined, "Trash") || !strings.Contains(joined, "hard links") {
+		t.Fatalf("unexpected reasons: %v", trashed.Reasons)
+	}
+
+	snapshots := verifyReport{Expected: 1000, Freed: 100, Snapshots: 3}
+	explainGap(deletionCheck{Expected: 1000}, &snapshots)
+	if len(snapshots.Reasons) != 1 || !strings.Contains(snapshots.Reasons[0], "3 local snapshots") {
+		t.Fatalf("unexpected reasons: %v", snapshots.Reasons)
+	}
+
+	extra := verifyReport{Expected: 1000, Freed: 5000}
+	explainGap(deletionCheck{Expected: 1000}, &extra)
+	if len(extra.Reasons) != 1 || !strings.Contains(extra.Reasons[0], "Other activity") {
+		t.Fatalf("unexpected reasons: %v", extra.Reasons)
+	}
+}
+
+func TestVerifyDeletionFindsLeftovers(t *testing.T) {
+	dir := t.TempDir()
+	left := filepath.Join(dir, "still-here.bin")
+	writeFileWithSize(t, left, 8192)
+
+	report := verifyDeletion(deletionCheck{
+		Paths:    []string{left, filepath.Join(dir, "gone.bin")},
+		Expected: 1 << 30,
+		Trashed:  true,
+		Freed:    0,
+		Roots:    map[string]int64{dir: 1 << 30},
+	})
+	if len(report.Leftover) != 1 || report.Leftover[0] != left {
+		t.Fatalf("unexpected leftovers: %v", report.Leftover)
+	}
+	if len(report.Roots) != 1 || report.Roots[0].Before != 1<<30 {
+		t.Fatalf("unexpected roots: %+v", report.Roots)
+	}
+	if !strings.Contains(strings.Join(report.Reasons, "\n"), "1 items are still on disk") {
+		t.Fatalf("unexpected reasons: %v", report.Reasons)
+	}
+}
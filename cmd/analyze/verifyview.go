@@ -0,0 +1,78 @@
+//go:build darwin
+
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// deletionCheck captures the sizes the view showed for a finished delete, so the
+// verification pass can compare them with what the disk reports afterwards.
+func (m model) deletionCheck(msg deleteProgressMsg) deletionCheck {
+	sizes := make(map[string]dirEntry)
+	for _, entry := range m.entries {
+		sizes[entry.Path] = entry
+	}
+	for _, file := range m.largeFiles {
+		sizes[file.Path] = dirEntry{Path: file.Path, Size: file.Size}
+	}
+	if m.dupes != nil {
+		for _, group := range m.dupes.Groups {
+			for _, path := range group.Paths {
+				sizes[path] = dirEntry{Path: path, Size: group.Size}
+			}
+		}
+	}
+	if m.versions != nil {
+		for _, family := range m.versions.Families {
+			for _, file := range family.Files {
+				sizes[file.Path] = dirEntry{Path: file.Path, Size: file.Size}
+			}
+		}
+	}
+	if m.staging != nil {
+		for _, item := range m.staging.Items {
+			sizes[item.Path] = dirEntry{Path: item.Path, Size: item.Size}
+		}
+	}
+
+	check := deletionCheck{Paths: msg.requested, Trashed: msg.trashed, Freed: msg.freed, Roots: make(map[string]int64)}
+	for _, path := range msg.requested {
+		entry := sizes[path]
+		check.Expected += max(entry.Size, 0)
+		check.Shared += entry.Shared
+		root := filepath.Dir(path)
+		if root == m.path && m.totalSize > 0 {
+			check.Roots[root] = m.totalSize
+		} else if cached, ok := m.cache[root]; ok {
+			check.Roots[root] = cached.TotalSize
+		}
+	}
+	return check
+}
+
+// renderVerify draws the reconciliation of the last large delete.
+func renderVerify(report *verifyReport) string {
+	var b strings.Builder
+	freed := "unknown"
+	if report.Freed >= 0 {
+		freed = humanizeBytes(report.Freed)
+	}
+	if report.Matched {
+		fmt.Fprintf(&b, "%s✓ Verified:%s expected %s, disk freed %s  %sEsc dismiss%s\n",
+			colorGreen, colorReset, humanizeBytes(report.Expected), freed, colorGray, colorReset)
+	} else {
+		fmt.Fprintf(&b, "%s! Verified:%s expected %s, disk freed %s  %sEsc dismiss%s\n",
+			colorYellow, colorReset, humanizeBytes(report.Expected), freed, colorGray, colorReset)
+	}
+	for _, root := range report.Roots {
+		fmt.Fprintf(&b, "  %s%s: %s → %s%s\n", colorGray, displayPath(root.Path),
+			humanizeBytes(root.Before), humanizeBytes(root.After), colorReset)
+	}
+	for _, reason := range report.Reasons {
+		fmt.Fprintf(&b, "  %s• %s%s\n", colorGray, reason, colorReset)
+	}
+	return b.String()
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// versionScanDepth bounds the walk below each root; installers rarely sit deeper.
+const versionScanDepth = 3
+
+// installerExtensions are artifacts that usually come in superseded versions.
+var installerExtensions = map[string]bool{
+	".dmg": true, ".pkg": true, ".mpkg": true, ".xip": true, ".iso": true, ".zip": true,
+}
+
+// dottedOnlyExtensions also hold user archives such as "Invoices 2024.zip", so they
+// only count with a dotted N.N version.
+var dottedOnlyExtensions = map[string]bool{".zip": true}
+
+var (
+	versionedNamePattern = regexp.MustCompile(`^(.+?)[\s._-]*[vV]?(\d+(?:[._]\d+)*)((?:[\s._-].*)?)$`)
+	copySuffixPattern    = regexp.MustCompile(`\s*\(\d+\)$`)
+)
+
+// versionedFile is one artifact in a family.
+type versionedFile struct {
+	Path    string
+	Size    int64
+	Version []int
+	ModTime time.Time
+}
+
+// installerFamily holds versions of one artifact, newest first.
+type installerFamily struct {
+	Name  string
+	Files []versionedFile
+}
+
+// older returns everything but the newest version.
+func (f installerFamily) older() []versionedFile {
+	return f.Files[1:]
+}
+
+func (f installerFamily) reclaimable() int64 {
+	var total int64
+	for _, file := range f.older() {
+		total += file.Size
+	}
+	return total
+}
+
+type installerFamiliesMsg struct {
+	Families []installerFamily
+}
+
+// parseVersionedName splits "Xcode_15.2.xip" into family key "xcode||.xip", display name
+// "Xcode" and version [15 2]. Browser copy suffixes like " (1)" are ignored.
+func parseVersionedName(name string) (key, display string, version []int, ok bool) {
+	ext := strings.ToLower(filepath.Ext(name))
+	if !installerExtensions[ext] {
+		return "", "", nil, false
+	}
+	stem := copySuffixPattern.ReplaceAllString(strings.TrimSuffix(name, filepath.Ext(name)), "")
+	match := versionedNamePattern.FindStringSubmatch(stem)
+	if match == nil {
+		return "", "", nil, false
+	}
+	prefix := strings.TrimRight(match[1], " ._-")
+	if prefix == "" || (dottedOnlyExtensions[ext] && !strings.Contains(match[2], ".")) {
+		return "", "", nil, false
+	}
+	for _, part := range strings.FieldsFunc(match[2], func(r rune) bool { return r == '.' || r == '_' }) {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return "", "", nil, false
+		}
+		version = append(version, n)
+	}
+	suffix := strings.ToLower(strings.Trim(match[3], " ._-"))
+	return strings.ToLower(prefix) + "|" + suffix + "|" + ext, prefix, version, true
+}
+
+// compareVersions orders versions numerically; missing parts count as zero.
+func compareVersions(a, b []int) int {
+	for i := 0; i < max(len(a), len(b)); i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// groupInstallerFamilies keeps families with at least two versions, largest savings first.
+func groupInstallerFamilies(files []versionedFile) []installerFamily {
+	byKey := make(map[string]*installerFamily)
+	var keys []string
+	for _, file := range files {
+		key, display, version, ok := parseVersionedName(filepath.Base(file.Path))
+		if !ok {
+			continue
+		}
+		file.Version = version
+		family, exists := byKey[key]
+		if !exists {
+			family = &installerFamily{Name: display}
+			byKey[key] = family
+			keys = append(keys, key)
+		}
+		family.Files = append(family.Files, file)
+	}
+
+	var families []installerFamily
+	for _, key := range keys {
+		family := byKey[key]
+		if len(family.Files) < 2 {
+			continue
+		}
+		sort.SliceStable(family.Files, func(i, j int) bool {
+			if c := compareVersions(family.Files[i].Version, family.Files[j].Version); c != 0 {
+				return c > 0
+			}
+			return family.Files[i].ModTime.After(family.Files[j].ModTime)
+		})
+		families = append(families, *family)
+	}
+	sort.SliceStable(families, func(i, j int) bool {
+		return families[i].reclaimable() > families[j].reclaimable()
+	})
+	return families
+}
+
+// collectInstallers walks roots a few levels deep for installer-like files.
+func collectInstallers(roots []string) []versionedFile {
+	seen := make(map[string]bool)
+	var files []versionedFile
+	for _, root := range roots {
+		if root == "" {
+			continue
+		}
+		base := strings.Count(filepath.Clean(root), string(filepath.Separator))
+		_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			if d.IsDir() {
+				if path != root && (defaultSkipDirs[d.Name()] || strings.HasSuffix(d.Name(), ".app") ||
+					strings.Count(path, string(filepath.Separator))-base >= versionScanDepth) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if seen[path] || !d.Type().IsRegular() || !installerExtensions[strings.ToLower(filepath.Ext(path))] {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			seen[path] = true
+			files = append(files, versionedFile{Path: path, Size: getActualFileSize(path, info), ModTime: info.ModTime()})
+			return nil
+		})
+	}
+	return files
+}
+
+// installerRoots is Downloads plus the folder being browsed.
+func installerRoots(current string) []string {
+	var roots []string
+	if home, err := os.UserHomeDir(); err == nil {
+		roots = append(roots, filepath.Join(home, "Downloads"))
+	}
+	if current != "" && current != "/" {
+		roots = append(roots, current)
+	}
+	return roots
+}
+
+func findInstallerFamiliesCmd(current string) tea.Cmd {
+	return func() tea.Msg {
+		return installerFamiliesMsg{Families: groupInstallerFamilies(collectInstallers(installerRoots(current)))}
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseVersionedName(t *testing.T) {
+	tests := []struct {
+		name    string
+		display string
+		version []int
+		ok      bool
+	}{
+		{"App-1.2.dmg", "App", []int{1, 2}, true},
+		{"Xcode_15.2.xip", "Xcode", []int{15, 2}, true},
+		{"Docker-4.30.0 (1).dmg", "Docker", []int{4, 30, 0}, true},
+		{"Tool v2.dmg", "Tool", []int{2}, true},
+		{"Readme.dmg", "", nil, false},
+		{"App-1.2.txt", "", nil, false},
+		{"Zed-0.140.2.zip", "Zed", []int{0, 140, 2}, true},
+		{"Invoices 2023.zip", "", nil, false},
+		{"photos_1.zip", "", nil, false},
+		{"photos_1_2.zip", "", nil, false},
+	}
+	for _, tt := range tests {
+		_, display, version, ok := parseVersionedName(tt.name)
+		if ok != tt.ok || display != tt.display || !reflect.DeepEqual(version, tt.version) {
+			t.Errorf("parseVersionedName(%q) = %q, %v, %v; want %q, %v, %v",
+				tt.name, display, version, ok, tt.display, tt.version, tt.ok)
+		}
+	}
+
+	a, _, _, _ := parseVersionedName("Xcode_15.2.xip")
+	b, _, _, _ := parseVersionedName("xcode_15.4.xip")
+	c, _, _, _ := parseVersionedName("Xcode_15.4.dmg")
+	if a != b || a == c {
+		t.Fatalf("family keys: %q, %q, %q", a, b, c)
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b []int
+		want int
+	}{
+		{[]int{1, 10}, []int{1, 9}, 1},
+		{[]int{1, 2}, []int{1, 2, 0}, 0},
+		{[]int{15}, []int{15, 1}, -1},
+	}
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%v, %v) = %d; want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestGroupInstallerFamilies(t *testing.T) {
+	files := []versionedFile{
+		{Path: "/d/App-1.2.dmg", Size: 100},
+		{Path: "/d/App-1.10.dmg", Size: 120},
+		{Path: "/d/App-1.9.dmg", Size: 110},
+		{Path: "/d/Xcode_15.2.xip", Size: 1000},
+		{Path: "/d/Xcode_15.4.xip", Size: 1100},
+		{Path: "/d/Solo-3.0.pkg", Size: 50},
+	}
+	families := groupInstallerFamilies(files)
+	if len(families) != 2 {
+		t.Fatalf("expected 2 families, got %+v", families)
+	}
+	if families[0].Name != "Xcode" || families[0].Files[0].Path != "/d/Xcode_15.4.xip" || families[0].reclaimable() != 1000 {
+		t.Fatalf("unexpected first family: %+v", families[0])
+	}
+	var order []string
+	for _, file := range families[1].Files {
+		order = append(order, filepath.Base(file.Path))
+	}
+	if strings.Join(order, ",") != "App-1.10.dmg,App-1.9.dmg,App-1.2.dmg" || families[1].reclaimable() != 210 {
+		t.Fatalf("unexpected App family order %v", order)
+	}
+}
+
+func TestRecordDeletionsAppendsToLedger(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	recordDeletions([]string{"/d/App-1.2.dmg"}, true)
+	recordDeletions([]string{"/d/App-1.9.dmg"}, false)
+
+	data, err := os.ReadFile(filepath.Join(home, ".config", "mole", ledgerFile))
+	if err != nil {
+		t.Fatalf("read ledger: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 ||
+		!strings.HasSuffix(lines[0], "SUCCESS: analyze moved to Trash /d/App-1.2.dmg") ||
+		!strings.HasSuffix(lines[1], "SUCCESS: analyze deleted /d/App-1.9.dmg") {
+		t.Fatalf("unexpected ledger:\n%s", data)
+	}
+	if _, err := time.Parse("[2006-01-02 15:04:05]", lines[0][:21]); err != nil {
+		t.Fatalf("ledger timestamp: %v", err)
+	}
+}
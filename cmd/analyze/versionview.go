@@ -0,0 +1,203 @@
+//go:build darwin
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// versionsView is the "v" screen grouping versioned installers and disk images.
+type versionsView struct {
+	Families []installerFamily
+	Scanning bool
+	Selected int  // Family under the cursor
+	Confirm  bool // ⌫ or A pressed once; the same key again deletes
+	All      bool // The pending confirm covers every family
+}
+
+// olderPaths lists the older versions in the selected family, or in every family.
+func (v *versionsView) olderPaths(all bool) ([]string, int64) {
+	families := v.Families
+	if !all {
+		if v.Selected >= len(families) {
+			return nil, 0
+		}
+		families = families[v.Selected : v.Selected+1]
+	}
+	var paths []string
+	var size int64
+	for _, family := range families {
+		for _, file := range family.older() {
+			paths = append(paths, file.Path)
+			size += file.Size
+		}
+	}
+	return paths, size
+}
+
+func (m model) openVersions() (tea.Model, tea.Cmd) {
+	if m.inventory != nil {
+		m.status = fmt.Sprintf("%s is a read-only listing", m.inventory.Source)
+		return m, nil
+	}
+	current := m.path
+	if m.inOverviewMode() {
+		current = ""
+	}
+	m.versions = &versionsView{Scanning: true}
+	m.status = "Looking for older installer versions..."
+	return m, tea.Batch(findInstallerFamiliesCmd(current), tickCmd())
+}
+
+// updateVersionsKey handles keys while the versions view is open.
+func (m model) updateVersionsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	v := m.versions
+	key := msg.String()
+	confirm, confirmAll := v.Confirm, v.All
+	v.Confirm, v.All = false, false
+	if confirm && key == "esc" {
+		m.status = "Cancelled"
+		return m, nil
+	}
+	switch key {
+	case "q", "Q", "ctrl+c":
+		return m.quit()
+	case "esc", "b", "left", "h", "v":
+		m.versions = nil
+	case "up", "k":
+		v.Selected = max(v.Selected-1, 0)
+	case "down", "j":
+		v.Selected = min(v.Selected+1, max(len(v.Families)-1, 0))
+	case "delete", "backspace", "alt+delete", "alt+backspace", "A":
+		if v.Scanning || m.deleting || len(v.Families) == 0 {
+			return m, nil
+		}
+		all := key == "A"
+		if !confirm || confirmAll != all {
+			v.Confirm, v.All = true, all
+			m.deletePermanent = m.permanentDelete || strings.HasPrefix(key, "alt+")
+			return m, nil
+		}
+		paths, _ := v.olderPaths(all)
+		if m.safeMode {
+			for _, path := range paths {
+				if _, allowed := safeDeleteCategory(path); !allowed {
+					m.status = safeModeBlockReason(path)
+					return m, nil
+				}
+			}
+		}
+		permanent := m.deletePermanent
+		m.deletePermanent = false
+		m.deleting = true
+		var deleteCount int64
+		m.deleteCount = &deleteCount
+		m.deleteBatch = &deleteBatchProgress{Total: len(paths), Permanent: permanent}
+		m.status = fmt.Sprintf("Removing %d older versions...", len(paths))
+		return m, tea.Batch(deletePathCmd(paths, m.deleteCount, m.deleteBatch, permanent), tickCmd())
+	}
+	return m, nil
+}
+
+// prune drops removed files; families left with one version disappear.
+func (v *versionsView) prune(removed []string) {
+	gone := make(map[string]bool, len(removed))
+	for _, path := range removed {
+		gone[path] = true
+	}
+	kept := v.Families[:0]
+	for _, family := range v.Families {
+		files := family.Files[:0]
+		for _, file := range family.Files {
+			if !gone[file.Path] {
+				files = append(files, file)
+			}
+		}
+		if len(files) > 1 {
+			family.Files = files
+			kept = append(kept, family)
+		}
+	}
+	v.Families = kept
+	v.Selected = min(v.Selected, max(len(v.Families)-1, 0))
+}
+
+// renderVersions draws each family with the newest version marked as kept.
+func (m model) renderVersions() string {
+	v := m.versions
+	var b strings.Builder
+	if v.Scanning {
+		fmt.Fprintf(&b, "%s%s%s%s Looking for versioned installers...\n", colorCyan, colorBold, spinnerFrames[m.spinner], colorReset)
+		return b.String()
+	}
+	if len(v.Families) == 0 {
+		fmt.Fprintf(&b, "  No superseded installers or disk images found.\n\n")
+		fmt.Fprintf(&b, "%sV/← Back | Q Quit%s\n", colorGray, colorReset)
+		return b.String()
+	}
+
+	var total int64
+	for _, family := range v.Families {
+		total += family.reclaimable()
+	}
+	fmt.Fprintf(&b, "%sOlder versions:%s %d families, %s%s%s reclaimable by keeping the newest\n\n",
+		colorCyan, colorReset, len(v.Families), colorGreen, humanizeBytes(total), colorReset)
+
+	nameWidth := calculateNameWidth(m.width) + 20
+	budget := calculateViewport(m.height, true)
+	start := 0
+	for start < v.Selected && linesForFamilies(v.Families[start:v.Selected+1]) > budget {
+		start++
+	}
+	for i := start; i < len(v.Families) && budget > 0; i++ {
+		family := v.Families[i]
+		prefix, color := "   ", ""
+		if i == v.Selected {
+			prefix, color = fmt.Sprintf(" %s%s▶%s ", colorCyan, colorBold, colorReset), colorCyan
+		}
+		fmt.Fprintf(&b, "%s%s%s%s  %s%d versions, %s reclaimable%s\n",
+			prefix, color, family.Name, colorReset, colorGray, len(family.Files), humanizeBytes(family.reclaimable()), colorReset)
+		budget--
+		for j, file := range family.Files {
+			if budget <= 0 {
+				break
+			}
+			name := trimNameWithWidth(displayPath(file.Path), nameWidth)
+			if j == 0 {
+				fmt.Fprintf(&b, "      %s  %10s  %skeep newest%s\n", name, humanizeBytes(file.Size), colorGreen, colorReset)
+			} else {
+				fmt.Fprintf(&b, "      %s%s  %10s%s\n", colorGray, name, humanizeBytes(file.Size), colorReset)
+			}
+			budget--
+		}
+	}
+
+	fmt.Fprintln(&b)
+	if v.Confirm {
+		paths, size := v.olderPaths(v.All)
+		verb := "Move to Trash:"
+		if m.deletePermanent {
+			verb = "Delete permanently:"
+		}
+		again := "⌫"
+		if v.All {
+			again = "A"
+		}
+		fmt.Fprintf(&b, "%s%s%s %d older versions (%s)  %sPress %s again  |  ESC cancel%s\n",
+			colorRed, verb, colorReset, len(paths), humanizeBytes(size), colorGray, again, colorReset)
+	} else {
+		fmt.Fprintf(&b, "%s↑↓ | ⌫ Remove older in family | A Remove all older | V/← Back | Q Quit%s\n", colorGray, colorReset)
+	}
+	return b.String()
+}
+
+func linesForFamilies(families []installerFamily) int {
+	lines := 0
+	for _, family := range families {
+		lines += 1 + len(family.Files)
+	}
+	return lines
+}
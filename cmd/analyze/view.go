@@ -4,17 +4,25 @@ package main
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
 	"sync/atomic"
 )
 
 // View renders the TUI.
 func (m model) View() string {
+	defer m.rows.endFrame()
 	var b strings.Builder
 	fmt.Fprintln(&b)
 
+	if m.quitGuard != nil {
+		fmt.Fprintf(&b, "%sAnalyze Disk%s\n\n", colorPurpleBold, colorReset)
+		b.WriteString(m.renderQuitGuard())
+		return b.String()
+	}
+
 	if m.inOverviewMode() {
-		fmt.Fprintf(&b, "%sAnalyze Disk%s\n", colorPurpleBold, colorReset)
+		fmt.Fprintf(&b, "%sAnalyze Disk%s%s%s%s\n", colorPurpleBold, colorReset, workspaceTag(), privilegedTag(), m.lowSpaceTag())
 		if m.overviewScanning {
 			allPending := true
 			for _, entry := range m.entries {
@@ -50,7 +58,33 @@ func (m model) View() string {
 			}
 		}
 	} else {
-		fmt.Fprintf(&b, "%sAnalyze Disk%s  %s%s%s", colorPurpleBold, colorReset, colorGray, displayPath(m.path), colorReset)
+		if m.inventory != nil {
+			fmt.Fprintf(&b, "%sAnalyze Disk%s  %s%s %s%s", colorPurpleBold, colorReset, colorGray, m.inventory.Source, displayPath(m.path), colorReset)
+		} else {
+			fmt.Fprintf(&b, "%sAnalyze Disk%s  %s%s%s", colorPurpleBold, colorReset, colorGray, displayPath(m.path), colorReset)
+		}
+		if m.safeMode {
+			fmt.Fprintf(&b, "  %s[Safe mode]%s", colorGreen, colorReset)
+		}
+		b.WriteString(workspaceTag())
+		if m.watch {
+			fmt.Fprintf(&b, "  %s[Watching]%s", colorCyan, colorReset)
+		}
+		if m.backgroundLimit > 0 && m.scanning {
+			fmt.Fprintf(&b, "  %s[Background, throttled]%s", colorGray, colorReset)
+		} else if niceMode {
+			fmt.Fprintf(&b, "  %s[Nice]%s", colorGray, colorReset)
+		}
+		if batteryPower.Load() {
+			fmt.Fprintf(&b, "  %s[On battery, throttled]%s", colorYellow, colorReset)
+		}
+		if m.cleanRunning {
+			fmt.Fprintf(&b, "  %s[mo clean running]%s", colorYellow, colorReset)
+		}
+		b.WriteString(m.lowSpaceTag())
+		if m.showLargeFiles {
+			b.WriteString(recentFilesTag())
+		}
 		if !m.scanning {
 			fmt.Fprintf(&b, "  |  Total: %s", humanizeBytes(m.totalSize))
 		}
@@ -63,22 +97,144 @@ func (m model) View() string {
 			count = atomic.LoadInt64(m.deleteCount)
 		}
 
-		fmt.Fprintf(&b, "%s%s%s%s Deleting: %s%s items%s removed, please wait...\n",
-			colorCyan, colorBold,
-			spinnerFrames[m.spinner],
-			colorReset,
-			colorYellow, formatNumber(count), colorReset)
+		if m.deleteBatch != nil && !m.deleteBatch.Permanent {
+			fmt.Fprintf(&b, "%s%s%s%s Moving to Trash: %s%s items%s moved, please wait...\n",
+				colorCyan, colorBold,
+				spinnerFrames[m.spinner],
+				colorReset,
+				colorYellow, formatNumber(count), colorReset)
+		} else {
+			fmt.Fprintf(&b, "%s%s%s%s Deleting: %s%s items%s removed, please wait...\n",
+				colorCyan, colorBold,
+				spinnerFrames[m.spinner],
+				colorReset,
+				colorYellow, formatNumber(count), colorReset)
+		}
+		if m.deleteBatch != nil && m.deleteBatch.Total > 1 {
+			if index, path := m.deleteBatch.position(); path != "" {
+				fmt.Fprintf(&b, "%sItem %d of %d:%s %s\n",
+					colorGray, index, m.deleteBatch.Total, colorReset, displayPath(path))
+			}
+		}
+
+		return b.String()
+	}
+
+	if m.dupes != nil {
+		b.WriteString(m.renderDuplicates())
+		return b.String()
+	}
+
+	if m.staging != nil {
+		b.WriteString(m.renderStaging())
+		return b.String()
+	}
+
+	if m.versions != nil {
+		b.WriteString(m.renderVersions())
+		return b.String()
+	}
+
+	if m.backups != nil {
+		b.WriteString(m.renderBackups())
+		return b.String()
+	}
+
+	if m.snapshots != nil {
+		b.WriteString(m.renderSnapshots())
+		return b.String()
+	}
+
+	if m.storage != nil {
+		b.WriteString(m.renderStorage())
+		return b.String()
+	}
+
+	if m.cleanPreview != nil {
+		b.WriteString(m.renderCleanPreview())
+		return b.String()
+	}
+
+	if m.homebrew != nil {
+		b.WriteString(m.renderHomebrew())
+		return b.String()
+	}
+
+	if m.migration != nil {
+		b.WriteString(m.renderMigration())
+		return b.String()
+	}
 
+	if m.packageCaches != nil {
+		b.WriteString(m.renderPackageCaches())
+		return b.String()
+	}
+
+	if m.uninstall != nil {
+		b.WriteString(m.renderUninstall())
+		return b.String()
+	}
+
+	if m.preview != nil {
+		b.WriteString(m.renderPreview())
+		return b.String()
+	}
+
+	if m.appData != nil {
+		b.WriteString(m.renderAppData())
+		return b.String()
+	}
+
+	if m.providers != nil {
+		b.WriteString(m.renderSizeProviders())
+		return b.String()
+	}
+
+	if m.stale != nil {
+		b.WriteString(m.renderStale())
+		return b.String()
+	}
+
+	if m.denied != nil {
+		b.WriteString(m.renderDenied())
+		return b.String()
+	}
+
+	if m.alerts != nil {
+		b.WriteString(m.renderAlerts())
+		return b.String()
+	}
+
+	if m.historyDiff != nil {
+		b.WriteString(m.renderHistoryDiff())
+		return b.String()
+	}
+
+	if m.searches != nil {
+		b.WriteString(m.renderSearches())
+		return b.String()
+	}
+
+	if m.organize != nil {
+		b.WriteString(m.renderOrganize())
+		if failures := m.organize.Failures; len(failures) > 0 && m.organize.Moving == nil {
+			b.WriteString(renderDeleteFailures(failures, "move"))
+		}
 		return b.String()
 	}
 
 	if m.scanning {
 		filesScanned, dirsScanned, bytesScanned := m.getScanProgress()
 
-		fmt.Fprintf(&b, "%s%s%s%s Scanning: %s%s files%s, %s%s dirs%s, %s%s%s\n",
+		frame, verb := spinnerFrames[m.spinner], "Scanning"
+		if m.scanPaused {
+			frame, verb = "⏸", "Paused"
+		}
+		fmt.Fprintf(&b, "%s%s%s%s %s: %s%s files%s, %s%s dirs%s, %s%s%s\n",
 			colorCyan, colorBold,
-			spinnerFrames[m.spinner],
+			frame,
 			colorReset,
+			verb,
 			colorYellow, formatNumber(filesScanned), colorReset,
 			colorYellow, formatNumber(dirsScanned), colorReset,
 			colorGreen, humanizeBytes(bytesScanned), colorReset)
@@ -87,26 +243,63 @@ func (m model) View() string {
 			currentPath := *m.currentPath
 			if currentPath != "" {
 				shortPath := displayPath(currentPath)
-				shortPath = truncateMiddle(shortPath, 50)
+				shortPath = truncateMiddle(shortPath, calculatePathWidth(m.width))
 				fmt.Fprintf(&b, "%s%s%s\n", colorGray, shortPath, colorReset)
 			}
 		}
 
+		if m.showPoolStats {
+			b.WriteString(renderPoolStats(scanPool.stats()))
+		}
+
+		var estimates []dirEntry
+		if len(m.estimates) > 0 && filepath.Dir(m.estimates[0].Path) == m.path {
+			estimates = m.estimates
+		}
+		exact := m.partial.snapshot(m.path)
+		if rows := mergeProgressRows(estimates, exact); len(rows) > 0 {
+			fmt.Fprintf(&b, "\n%sPartial results, %d ready, refining...%s\n", colorGray, len(exact), colorReset)
+			nameWidth := calculateNameWidth(m.width)
+			limit := min(len(rows), calculateViewport(m.height, false)-2)
+			for _, row := range rows[:max(limit, 0)] {
+				icon := "📄"
+				if row.Entry.Bundle {
+					icon = "📦"
+				} else if row.Entry.IsDir {
+					icon = "📁"
+				}
+				name := padName(trimNameWithWidth(displayName(row.Entry.Path, row.Entry.Name), nameWidth), nameWidth)
+				if row.Exact {
+					fmt.Fprintf(&b, "      %s %s   %9s\n", icon, name, humanizeBytes(row.Entry.Size))
+				} else {
+					fmt.Fprintf(&b, "      %s %s  %s~%9s%s\n", icon, name, colorGray, humanizeBytes(row.Entry.Size), colorReset)
+				}
+			}
+		}
+
+		pause := "p pause"
+		if m.scanPaused {
+			pause = "p resume"
+		}
+		fmt.Fprintf(&b, "\n%s%s  |  Esc cancel, keep finished folders%s\n", colorGray, pause, colorReset)
 		return b.String()
 	}
 
+	if m.showPoolStats && m.lastUsage != nil {
+		b.WriteString(renderRunUsage(*m.lastUsage))
+	}
+
 	if m.showLargeFiles {
 		if len(m.largeFiles) == 0 {
-			fmt.Fprintln(&b, "  No large files found (>=100MB)")
+			fmt.Fprintf(&b, "  No large files found (>=%s), press - to lower the threshold\n", humanizeBytes(minLargeFileSize))
 		} else {
-			viewport := calculateViewport(m.height, true)
-			start := m.largeOffset
-			if start < 0 {
-				start = 0
-			}
-			end := start + viewport
-			if end > len(m.largeFiles) {
-				end = len(m.largeFiles)
+			viewport := calculateViewport(m.listHeight(), true)
+			grouped := m.largeSort == largeSortPath
+			start, end := visibleLargeRange(m.largeFiles, m.largeOffset, m.largeSelected, viewport, grouped)
+			end = min(end, len(m.largeFiles))
+			var subtotals map[string]dirSubtotal
+			if grouped {
+				subtotals = largeDirSubtotals(m.largeFiles)
 			}
 			maxLargeSize := int64(1)
 			for _, file := range m.largeFiles {
@@ -117,37 +310,29 @@ func (m model) View() string {
 			nameWidth := calculateNameWidth(m.width)
 			for idx := start; idx < end; idx++ {
 				file := m.largeFiles[idx]
-				shortPath := displayPath(file.Path)
-				shortPath = truncateMiddle(shortPath, nameWidth)
-				paddedPath := padName(shortPath, nameWidth)
-				entryPrefix := "   "
-				nameColor := ""
-				sizeColor := colorGray
-				numColor := ""
-
-				isMultiSelected := m.largeMultiSelected != nil && m.largeMultiSelected[file.Path]
-				selectIcon := "○"
-				if isMultiSelected {
-					selectIcon = fmt.Sprintf("%s●%s", colorGreen, colorReset)
-					nameColor = colorGreen
+				if dir := filepath.Dir(file.Path); grouped && (idx == start || dir != filepath.Dir(m.largeFiles[idx-1].Path)) {
+					total := subtotals[dir]
+					fmt.Fprintf(&b, "   %s▸ %s  %d files, %s%s\n",
+						colorGray, displayPath(dir), total.Count, humanizeBytes(total.Size), colorReset)
 				}
-
-				if idx == m.largeSelected {
-					entryPrefix = fmt.Sprintf(" %s%s▶%s ", colorCyan, colorBold, colorReset)
-					if !isMultiSelected {
-						nameColor = colorCyan
-					}
-					sizeColor = colorCyan
-					numColor = colorCyan
+				key := largeRowKey{
+					file:      file,
+					index:     idx,
+					selected:  idx == m.largeSelected,
+					marked:    m.largeMultiSelected != nil && m.largeMultiSelected[file.Path],
+					maxSize:   maxLargeSize,
+					nameWidth: nameWidth,
+					query:     m.filterPattern(),
+					scanPath:  m.path,
+					apparent:  m.showApparent,
 				}
-				size := humanizeBytes(file.Size)
-				bar := coloredProgressBar(file.Size, maxLargeSize, 0)
-				fmt.Fprintf(&b, "%s%s %s%2d.%s %s  |  📄 %s%s%s  %s%10s%s\n",
-					entryPrefix, selectIcon, numColor, idx+1, colorReset, bar, nameColor, paddedPath, colorReset, sizeColor, size, colorReset)
+				b.WriteString(m.rows.row(key, func() string { return renderLargeRow(key) }))
 			}
 		}
 	} else {
-		if len(m.entries) == 0 {
+		if len(m.entries) == 0 && m.filter != nil {
+			fmt.Fprintln(&b, "  No matches")
+		} else if len(m.entries) == 0 {
 			fmt.Fprintln(&b, "  Empty directory")
 		} else {
 			if m.inOverviewMode() {
@@ -196,8 +381,8 @@ func (m model) View() string {
 						}
 					}
 					entryPrefix := "   "
-					name := trimNameWithWidth(entry.Name, nameWidth)
-					paddedName := padName(name, nameWidth)
+					name := trimNameWithWidth(displayName(entry.Path, entry.Name), nameWidth)
+					paddedName := highlightMatch(padName(name, nameWidth), m.filterPattern())
 					nameSegment := fmt.Sprintf("%s %s", icon, paddedName)
 					numColor := ""
 					percentColor := ""
@@ -241,7 +426,7 @@ func (m model) View() string {
 					}
 				}
 
-				viewport := calculateViewport(m.height, false)
+				viewport := calculateViewport(m.listHeight(), false)
 				nameWidth := calculateNameWidth(m.width)
 				start := m.offset
 				if start < 0 {
@@ -254,115 +439,143 @@ func (m model) View() string {
 
 				for idx := start; idx < end; idx++ {
 					entry := m.entries[idx]
-					icon := "📄"
-					if entry.IsDir {
-						icon = "📁"
-					}
-					size := humanizeBytes(entry.Size)
-					name := trimNameWithWidth(entry.Name, nameWidth)
-					paddedName := padName(name, nameWidth)
-
-					percent := float64(entry.Size) / float64(m.totalSize) * 100
-					percentStr := fmt.Sprintf("%5.1f%%", percent)
-
-					bar := coloredProgressBar(entry.Size, maxSize, percent)
-
-					var sizeColor string
-					if percent >= 50 {
-						sizeColor = colorRed
-					} else if percent >= 20 {
-						sizeColor = colorYellow
-					} else if percent >= 5 {
-						sizeColor = colorBlue
-					} else {
-						sizeColor = colorGray
-					}
-
-					isMultiSelected := m.multiSelected != nil && m.multiSelected[entry.Path]
-					selectIcon := "○"
-					nameColor := ""
-					if isMultiSelected {
-						selectIcon = fmt.Sprintf("%s●%s", colorGreen, colorReset)
-						nameColor = colorGreen
-					}
-
-					entryPrefix := "   "
-					nameSegment := fmt.Sprintf("%s %s", icon, paddedName)
-					if nameColor != "" {
-						nameSegment = fmt.Sprintf("%s%s %s%s", nameColor, icon, paddedName, colorReset)
-					}
-					numColor := ""
-					percentColor := ""
-					if idx == m.selected {
-						entryPrefix = fmt.Sprintf(" %s%s▶%s ", colorCyan, colorBold, colorReset)
-						if !isMultiSelected {
-							nameSegment = fmt.Sprintf("%s%s %s%s", colorCyan, icon, paddedName, colorReset)
-						}
-						numColor = colorCyan
-						percentColor = colorCyan
-						sizeColor = colorCyan
-					}
-
-					displayIndex := idx + 1
-
-					var hintLabel string
-					if entry.IsDir && isCleanableDir(entry.Path) {
-						hintLabel = fmt.Sprintf("%s🧹%s", colorYellow, colorReset)
-					} else {
-						lastAccess := entry.LastAccess
-						if lastAccess.IsZero() && entry.Path != "" {
-							lastAccess = getLastAccessTime(entry.Path)
-						}
-						if unusedTime := formatUnusedTime(lastAccess); unusedTime != "" {
-							hintLabel = fmt.Sprintf("%s%s%s", colorGray, unusedTime, colorReset)
-						}
-					}
-
-					if hintLabel == "" {
-						fmt.Fprintf(&b, "%s%s %s%2d.%s %s %s%s%s  |  %s %s%10s%s\n",
-							entryPrefix, selectIcon, numColor, displayIndex, colorReset, bar, percentColor, percentStr, colorReset,
-							nameSegment, sizeColor, size, colorReset)
-					} else {
-						fmt.Fprintf(&b, "%s%s %s%2d.%s %s %s%s%s  |  %s %s%10s%s  %s\n",
-							entryPrefix, selectIcon, numColor, displayIndex, colorReset, bar, percentColor, percentStr, colorReset,
-							nameSegment, sizeColor, size, colorReset, hintLabel)
+					key := entryRowKey{
+						entry:      entry,
+						index:      idx,
+						selected:   idx == m.selected,
+						marked:     m.multiSelected != nil && m.multiSelected[entry.Path],
+						pinned:     isPinned(entry.Path),
+						maxSize:    maxSize,
+						totalSize:  m.totalSize,
+						nameWidth:  nameWidth,
+						query:      m.filterPattern(),
+						showShared: m.showShared,
+						showCounts: m.showCounts,
+						apparent:   m.showApparent,
+						marker:     m.watchMarker(entry.Path),
+						trashHint:  m.volumeTrashHint(entry),
+						ownerHint:  m.appOwnerHint(entry),
 					}
+					b.WriteString(m.rows.row(key, func() string { return renderEntryRow(key) }))
 				}
 			}
 		}
 	}
 
-	fmt.Fprintln(&b)
+	if footer := m.listFooter(); footer != "" {
+		b.WriteString(footer)
+	} else {
+		fmt.Fprintln(&b)
+	}
+	if m.actionPalette != nil {
+		b.WriteString(renderActionPalette(m.actionPalette))
+	}
+	if m.palette != nil {
+		fmt.Fprintf(&b, "%s:%s%s▌  %sscript rm | script rsync <dest> | run <cmd> | set <knob> <value>  |  Esc close%s\n",
+			colorCyan, colorReset, m.palette.Input, colorGray, colorReset)
+	}
+	if m.filter != nil {
+		if m.filter.Editing {
+			fmt.Fprintf(&b, "%sFilter:%s /%s▌  %sEnter apply  |  ^A select matches  |  ^S save search  |  Esc clear%s\n", colorCyan, colorReset, m.filter.Query, colorGray, colorReset)
+		} else {
+			label, query, focusHint := "Filter:", m.filter.Query, "w focus"
+			if m.focusMode {
+				label, query, focusHint = "Focus:", strings.TrimSpace("actionable "+query), "w all items"
+			}
+			fmt.Fprintf(&b, "%s%s%s %s  %s%d of %d  |  / edit  |  %s  |  ^A select all  |  Esc clear%s\n", colorCyan, label, colorReset, query,
+				colorGray, len(m.entries), len(m.filter.entries), focusHint, colorReset)
+		}
+	}
 	if m.inOverviewMode() {
 		if len(m.history) > 0 {
-			fmt.Fprintf(&b, "%s↑↓←→ | Enter | R Refresh | O Open | F File | ← Back | Q Quit%s\n", colorGray, colorReset)
+			fmt.Fprintf(&b, "%s↑↓←→ | Enter | R Refresh | O Open | F File | ← Back | ^K Actions | Q Quit%s\n", colorGray, colorReset)
 		} else {
-			fmt.Fprintf(&b, "%s↑↓→ | Enter | R Refresh | O Open | F File | Q Quit%s\n", colorGray, colorReset)
+			fmt.Fprintf(&b, "%s↑↓→ | Enter | R Refresh | O Open | F File | ^K Actions | Q Quit%s\n", colorGray, colorReset)
 		}
 	} else if m.showLargeFiles {
 		selectCount := len(m.largeMultiSelected)
 		if selectCount > 0 {
-			fmt.Fprintf(&b, "%s↑↓← | Space Select | R Refresh | O Open | F File | ⌫ Del(%d) | ← Back | Q Quit%s\n", colorGray, selectCount, colorReset)
+			fmt.Fprintf(&b, "%s↑↓← | Space/a Select | S Sort(%s) | +/- Min(%s) | V Types(%s) | N New(%s) | R Refresh | O Open | F File | ⌫ Del(%d) | ← Back | ^K Actions | Q Quit%s\n", colorGray, m.largeSort.label(), humanizeBytes(minLargeFileSize), fileTypesLabel(), recentFilesLabel(), selectCount, colorReset)
 		} else {
-			fmt.Fprintf(&b, "%s↑↓← | Space Select | R Refresh | O Open | F File | ⌫ Del | ← Back | Q Quit%s\n", colorGray, colorReset)
+			fmt.Fprintf(&b, "%s↑↓← | Space/a Select | S Sort(%s) | +/- Min(%s) | V Types(%s) | N New(%s) | R Refresh | O Open | F File | ⌫ Del | ← Back | ^K Actions | Q Quit%s\n", colorGray, m.largeSort.label(), humanizeBytes(minLargeFileSize), fileTypesLabel(), recentFilesLabel(), colorReset)
 		}
 	} else {
 		largeFileCount := len(m.largeFiles)
 		selectCount := len(m.multiSelected)
 		if selectCount > 0 {
 			if largeFileCount > 0 {
-				fmt.Fprintf(&b, "%s↑↓←→ | Space Select | Enter | R Refresh | O Open | F File | ⌫ Del(%d) | T Top(%d) | Q Quit%s\n", colorGray, selectCount, largeFileCount, colorReset)
+				fmt.Fprintf(&b, "%s↑↓←→ | Space/a Select | Enter | S Sort(%s) | R Refresh | O Open | F File | ⌫ Del(%d) | T Top(%d) | ^K Actions | Q Quit%s\n", colorGray, m.entrySort.label(), selectCount, largeFileCount, colorReset)
 			} else {
-				fmt.Fprintf(&b, "%s↑↓←→ | Space Select | Enter | R Refresh | O Open | F File | ⌫ Del(%d) | Q Quit%s\n", colorGray, selectCount, colorReset)
+				fmt.Fprintf(&b, "%s↑↓←→ | Space/a Select | Enter | S Sort(%s) | R Refresh | O Open | F File | ⌫ Del(%d) | ^K Actions | Q Quit%s\n", colorGray, m.entrySort.label(), selectCount, colorReset)
 			}
 		} else {
 			if largeFileCount > 0 {
-				fmt.Fprintf(&b, "%s↑↓←→ | Space Select | Enter | R Refresh | O Open | F File | ⌫ Del | T Top(%d) | Q Quit%s\n", colorGray, largeFileCount, colorReset)
+				fmt.Fprintf(&b, "%s↑↓←→ | Space/a Select | Enter | S Sort(%s) | R Refresh | O Open | F File | ⌫ Del | T Top(%d) | ^K Actions | Q Quit%s\n", colorGray, m.entrySort.label(), largeFileCount, colorReset)
 			} else {
-				fmt.Fprintf(&b, "%s↑↓←→ | Space Select | Enter | R Refresh | O Open | F File | ⌫ Del | Q Quit%s\n", colorGray, colorReset)
+				fmt.Fprintf(&b, "%s↑↓←→ | Space/a Select | Enter | S Sort(%s) | R Refresh | O Open | F File | ⌫ Del | ^K Actions | Q Quit%s\n", colorGray, m.entrySort.label(), colorReset)
+			}
+		}
+	}
+	if selected, ok := m.selectedEntry(); ok && m.exactSize != nil && m.exactSize.Path == selected.Path && m.exactSize.Err == nil {
+		fmt.Fprintf(&b, "%sExact:%s %s  Logical %s bytes  |  Allocated %s bytes\n",
+			colorCyan, colorReset, displayName(selected.Path, selected.Name),
+			formatGrouped(m.exactSize.Logical), formatGrouped(m.exactSize.Allocated))
+	}
+	if selected, ok := m.selectedEntry(); ok && !m.showLargeFiles {
+		if info, known := lookupGlossary(selected.Path); known {
+			safetyColor := colorGreen
+			switch info.Safety {
+			case "caution":
+				safetyColor = colorYellow
+			case "keep":
+				safetyColor = colorRed
+			}
+			fmt.Fprintf(&b, "%sℹ %s:%s %s %s%s.%s %s\n",
+				colorCyan, info.Title, colorReset, info.About,
+				safetyColor, info.safetyLabel(), colorReset, info.Shrink)
+		}
+	}
+	if selected, ok := m.selectedEntry(); ok && m.inVolumesView() {
+		if health, known := m.volumeHealth[selected.Path]; known && health.summary() != "" {
+			healthColor := colorGreen
+			if health.failing() {
+				healthColor = colorRed
+			} else if health.SMART != "Verified" {
+				healthColor = colorGray
 			}
+			fmt.Fprintf(&b, "%sDrive:%s %s%s%s\n", colorCyan, colorReset, healthColor, health.summary(), colorReset)
 		}
 	}
+	if selected, ok := m.selectedEntry(); ok && m.explanation != nil && m.explanation.Path == selected.Path {
+		fmt.Fprintf(&b, "%sWhy:%s %s\n", colorCyan, colorReset, m.explanation.Text)
+	}
+	if m.showInfo {
+		b.WriteString(m.renderInfoPanel())
+	}
+	b.WriteString(m.deniedTag())
+	if m.stagedDue > 0 {
+		fmt.Fprintf(&b, "%s⏳ %d staged items (%s) waiting over a week, Z to review%s\n",
+			colorYellow, m.stagedDue, humanizeBytes(m.stagedDueSize), colorReset)
+	}
+	if m.evictConfirm != nil {
+		fmt.Fprintln(&b)
+		b.WriteString(m.renderEvictConfirm())
+	}
+	if m.compress != nil {
+		fmt.Fprintln(&b)
+		b.WriteString(m.renderCompress())
+	}
+	if m.relocate != nil {
+		fmt.Fprintln(&b)
+		b.WriteString(m.renderRelocate())
+	}
+	if m.trashConfirm != "" {
+		fmt.Fprintln(&b)
+		fmt.Fprintf(&b, "%sEmpty trash:%s %s (%s)  %sPress E again  |  ESC cancel%s\n",
+			colorRed, colorReset,
+			displayPath(m.trashConfirm), humanizeBytes(m.volumeTrash[m.trashConfirm]),
+			colorGray, colorReset)
+	}
 	if m.deleteConfirm && m.deleteTarget != nil {
 		fmt.Fprintln(&b)
 		var deleteCount int
@@ -389,22 +602,94 @@ func (m model) View() string {
 			}
 		}
 
+		label, hint := "Move to Trash:", "Press ⌫ again  |  ⌥⌫ delete permanently  |  ESC cancel"
+		if m.deletePermanent {
+			label, hint = "Delete permanently:", "Press ⌫ again  |  ESC cancel"
+		}
 		if deleteCount > 1 {
-			fmt.Fprintf(&b, "%sDelete:%s %d items (%s)  %sPress ⌫ again  |  ESC cancel%s\n",
-				colorRed, colorReset,
+			fmt.Fprintf(&b, "%s%s%s %d items (%s)  %s%s%s\n",
+				colorRed, label, colorReset,
 				deleteCount, humanizeBytes(totalDeleteSize),
-				colorGray, colorReset)
+				colorGray, hint, colorReset)
 		} else {
-			fmt.Fprintf(&b, "%sDelete:%s %s (%s)  %sPress ⌫ again  |  ESC cancel%s\n",
-				colorRed, colorReset,
-				m.deleteTarget.Name, humanizeBytes(m.deleteTarget.Size),
-				colorGray, colorReset)
+			fmt.Fprintf(&b, "%s%s%s %s (%s)  %s%s%s\n",
+				colorRed, label, colorReset,
+				displayName(m.deleteTarget.Path, m.deleteTarget.Name), humanizeBytes(m.deleteTarget.Size),
+				colorGray, hint, colorReset)
+		}
+		shown := 0
+		for _, path := range m.actionTargets() {
+			hint, ok := m.reinstallHints[path]
+			if !ok {
+				continue
+			}
+			if shown == 3 {
+				fmt.Fprintf(&b, "%s  …%s\n", colorGray, colorReset)
+				break
+			}
+			fmt.Fprintf(&b, "%s↺ %s:%s %s\n", colorGreen, filepath.Base(path), colorReset, hint)
+			shown++
+		}
+		for i, locked := range m.deleteLocks {
+			if i == 3 {
+				fmt.Fprintf(&b, "%s  … %d more locked%s\n", colorGray, len(m.deleteLocks)-i, colorReset)
+				break
+			}
+			switch {
+			case locked.System:
+				fmt.Fprintf(&b, "%s🔒 %s:%s system immutable flag (schg), cannot be cleared here\n", colorYellow, filepath.Base(locked.Path), colorReset)
+			case !locked.Owned:
+				fmt.Fprintf(&b, "%s🔒 %s:%s locked by another user\n", colorYellow, filepath.Base(locked.Path), colorReset)
+			default:
+				fmt.Fprintf(&b, "%s🔒 %s:%s locked (uchg)  %sU unlock and delete%s\n", colorYellow, filepath.Base(locked.Path), colorReset, colorGray, colorReset)
+			}
+		}
+		if category, ok := safeDeleteCategory(m.deleteTarget.Path); ok && m.safeMode {
+			fmt.Fprintf(&b, "%s%s:%s %s\n", colorGreen, category.Label, colorReset, category.Explain)
+		}
+	} else if m.deleteFailures != nil || m.verify != nil {
+		fmt.Fprintln(&b)
+		if m.deleteFailures != nil {
+			b.WriteString(renderDeleteFailures(m.deleteFailures, "remove"))
+		}
+		if m.verify != nil {
+			b.WriteString(renderVerify(m.verify))
 		}
 	}
 	return b.String()
 }
 
+// renderPoolStats formats the worker pool debug panel.
+func renderPoolStats(stats poolStats) string {
+	fds := "n/a"
+	if stats.OpenFDs >= 0 {
+		fds = fmt.Sprintf("%d", stats.OpenFDs)
+	}
+	return fmt.Sprintf("%sWorkers %d/%d  |  Queued %d  |  Peak %d  |  FDs %s  |  Goroutines %d  |  [ ] adjust%s\n",
+		colorGray, stats.Active, stats.Limit, stats.Waiting, stats.Peak, fds, stats.Goroutines, colorReset)
+}
+
 // calculateViewport returns visible rows for the current terminal height.
+// listFooter places the visible range and the children the listing cap dropped in the
+// blank line under the directory list.
+func (m model) listFooter() string {
+	if m.showLargeFiles || m.inOverviewMode() || m.scanning || len(m.entries) == 0 {
+		return ""
+	}
+	var parts []string
+	if viewport := calculateViewport(m.listHeight(), false); len(m.entries) > viewport {
+		end := min(m.offset+viewport, len(m.entries))
+		parts = append(parts, fmt.Sprintf("%d–%d of %s  PgUp/PgDn Home/End", m.offset+1, end, formatGrouped(int64(len(m.entries)))))
+	}
+	if m.omitted > 0 {
+		parts = append(parts, fmt.Sprintf("… %s more items, totaling %s  N List all", formatGrouped(int64(m.omitted)), humanizeBytes(m.omittedSize)))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("   %s%s%s\n", colorGray, strings.Join(parts, "  |  "), colorReset)
+}
+
 func calculateViewport(termHeight int, isLargeFiles bool) int {
 	if termHeight <= 0 {
 		return defaultViewport
@@ -415,14 +700,29 @@ func calculateViewport(termHeight int, isLargeFiles bool) int {
 		reserved = 5
 	}
 
-	available := termHeight - reserved
+	return max(termHeight-reserved, 1)
+}
 
-	if available < 1 {
-		return 1
+// workspaceTag names the workspace whose settings are in use.
+func workspaceTag() string {
+	if activeWorkspace == "" {
+		return ""
 	}
-	if available > 30 {
-		return 30
+	return fmt.Sprintf("  %s[%s]%s", colorCyan, activeWorkspace, colorReset)
+}
+
+// privilegedTag marks the header while sizes come from du running as root.
+func privilegedTag() string {
+	if !privilegedMode.Load() {
+		return ""
 	}
+	return fmt.Sprintf("  %s[sudo]%s", colorYellow, colorReset)
+}
 
-	return available
+// lowSpaceTag warns in the header while the state volume is critically low on space.
+func (m model) lowSpaceTag() string {
+	if m.lowSpace <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("  %s[Low space: %s free, caches and exports paused]%s", colorRed, humanizeBytes(m.lowSpace), colorReset)
 }
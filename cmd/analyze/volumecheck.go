@@ -0,0 +1,138 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// Exit codes of `mo analyze check`, the ones monitoring plugins use.
+const (
+	checkOK       = 0
+	checkWarning  = 1
+	checkCritical = 2
+	checkUnknown  = 3
+)
+
+var checkStatusNames = map[int]string{checkOK: "OK", checkWarning: "WARNING", checkCritical: "CRITICAL", checkUnknown: "UNKNOWN"}
+
+// volumeUsage is how full one mounted volume is.
+type volumeUsage struct {
+	Path      string
+	Total     int64
+	Available int64
+}
+
+// Percent is the share of the volume not available to this user, as Finder counts it.
+func (u volumeUsage) Percent() float64 {
+	if u.Total <= 0 {
+		return 0
+	}
+	return float64(u.Total-u.Available) * 100 / float64(u.Total)
+}
+
+func readVolumeUsage(path string) (volumeUsage, error) {
+	var fs syscall.Statfs_t
+	if err := syscall.Statfs(path, &fs); err != nil {
+		return volumeUsage{}, err
+	}
+	return volumeUsage{
+		Path:      path,
+		Total:     int64(uint64(fs.Blocks) * uint64(fs.Bsize)),
+		Available: int64(uint64(fs.Bavail) * uint64(fs.Bsize)),
+	}, nil
+}
+
+// checkedVolumes is / plus every volume mounted under /Volumes, skipping the link back
+// to the startup disk.
+func checkedVolumes() []string {
+	paths := []string{"/"}
+	seen := make(map[uint64]bool)
+	var st syscall.Stat_t
+	if syscall.Stat("/", &st) == nil {
+		seen[uint64(st.Dev)] = true
+	}
+	children, err := os.ReadDir(volumesRoot)
+	if err != nil {
+		return paths
+	}
+	for _, child := range children {
+		path := filepath.Join(volumesRoot, child.Name())
+		if child.Type()&os.ModeSymlink != 0 || syscall.Stat(path, &st) != nil || seen[uint64(st.Dev)] {
+			continue
+		}
+		seen[uint64(st.Dev)] = true
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+// classifyUsage maps a usage percentage onto the check exit codes.
+func classifyUsage(percent, warn, crit float64) int {
+	switch {
+	case percent >= crit:
+		return checkCritical
+	case percent >= warn:
+		return checkWarning
+	}
+	return checkOK
+}
+
+// writeCheckReport prints a one-line verdict naming the fullest volume, then one line
+// per volume unless quiet, and returns the exit code.
+func writeCheckReport(w io.Writer, usages []volumeUsage, warn, crit float64, quiet bool) int {
+	if len(usages) == 0 {
+		fmt.Fprintln(w, "DISK UNKNOWN: no volumes could be read")
+		return checkUnknown
+	}
+	worst, fullest := checkOK, usages[0]
+	for _, usage := range usages {
+		if usage.Percent() > fullest.Percent() {
+			fullest = usage
+		}
+		worst = max(worst, classifyUsage(usage.Percent(), warn, crit))
+	}
+	fmt.Fprintf(w, "DISK %s: %s %.0f%% used, %s free\n", checkStatusNames[worst], displayPath(fullest.Path), fullest.Percent(), humanizeBytes(fullest.Available))
+	if quiet {
+		return worst
+	}
+	for _, usage := range usages {
+		status := classifyUsage(usage.Percent(), warn, crit)
+		fmt.Fprintf(w, "  %-24s %5.1f%% used  %10s free of %10s  %s\n", displayPath(usage.Path), usage.Percent(),
+			humanizeBytes(usage.Available), humanizeBytes(usage.Total), checkStatusNames[status])
+	}
+	return worst
+}
+
+// runCheckCommand is `mo analyze check [--warn 85] [--crit 95] [--quiet] [volume...]`.
+func runCheckCommand(w io.Writer, args []string) int {
+	flags := flag.NewFlagSet("check", flag.ContinueOnError)
+	flags.SetOutput(w)
+	warn := flags.Float64("warn", 85, "exit 1 once a volume is at least `percent` full")
+	crit := flags.Float64("crit", 95, "exit 2 once a volume is at least `percent` full")
+	quiet := flags.Bool("quiet", false, "print only the summary line, for shell prompts")
+	if err := flags.Parse(args); err != nil {
+		return checkUnknown
+	}
+	if *warn <= 0 || *crit > 100 || *warn > *crit {
+		fmt.Fprintf(w, "DISK UNKNOWN: --warn %g and --crit %g must satisfy 0 < warn <= crit <= 100\n", *warn, *crit)
+		return checkUnknown
+	}
+	paths := flags.Args()
+	if len(paths) == 0 {
+		paths = checkedVolumes()
+	}
+	var usages []volumeUsage
+	for _, path := range paths {
+		usage, err := readVolumeUsage(path)
+		if err != nil {
+			fmt.Fprintf(w, "DISK UNKNOWN: %s: %v\n", displayPath(path), err)
+			return checkUnknown
+		}
+		usages = append(usages, usage)
+	}
+	return writeCheckReport(w, usages, *warn, *crit, *quiet)
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestClassifyUsage(t *testing.T) {
+	for _, tc := range []struct {
+		percent float64
+		want    int
+	}{{50, checkOK}, {85, checkWarning}, {94.9, checkWarning}, {95, checkCritical}} {
+		if got := classifyUsage(tc.percent, 85, 95); got != tc.want {
+			t.Fatalf("classifyUsage(%v) = %d, want %d", tc.percent, got, tc.want)
+		}
+	}
+}
+
+func TestWriteCheckReportNamesFullestVolume(t *testing.T) {
+	usages := []volumeUsage{
+		{Path: "/", Total: 1000, Available: 400},
+		{Path: "/Volumes/Backup", Total: 1000, Available: 30},
+	}
+	var out bytes.Buffer
+	if code := writeCheckReport(&out, usages, 85, 95, false); code != checkCritical {
+		t.Fatalf("a 97%% full volume should be critical, got %d", code)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "DISK CRITICAL: /Volumes/Backup 97% used") || !strings.HasSuffix(lines[1], "OK") {
+		t.Fatalf("unexpected report:\n%s", out.String())
+	}
+
+	out.Reset()
+	if code := writeCheckReport(&out, usages[:1], 85, 95, true); code != checkOK || strings.Count(out.String(), "\n") != 1 {
+		t.Fatalf("quiet OK report = %d %q", code, out.String())
+	}
+}
+
+func TestRunCheckCommand(t *testing.T) {
+	var out bytes.Buffer
+	if code := runCheckCommand(&out, []string{"--warn", "99", "--crit", "90"}); code != checkUnknown {
+		t.Fatalf("warn above crit should be rejected, got %d: %s", code, out.String())
+	}
+	out.Reset()
+	code := runCheckCommand(&out, []string{"--warn", "100", "--crit", "100", t.TempDir()})
+	if code != checkOK && code != checkCritical || !strings.HasPrefix(out.String(), "DISK ") {
+		t.Fatalf("checking a real volume = %d: %s", code, out.String())
+	}
+}
@@ -0,0 +1,130 @@
+//go:build darwin
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const volumesRoot = "/Volumes"
+
+// volumeTrashMsg reports the size of a volume's .Trashes folder.
+type volumeTrashMsg struct {
+	Volume string
+	Size   int64
+}
+
+// volumeTrashEmptiedMsg reports the outcome of emptying a volume's trash.
+type volumeTrashEmptiedMsg struct {
+	Volume  string
+	Count   int64
+	Skipped int
+	Err     error
+}
+
+func volumeTrashPath(volume string) string {
+	return filepath.Join(volume, ".Trashes")
+}
+
+// measureVolumeTrash walks .Trashes, skipping per-user folders we cannot read.
+func measureVolumeTrash(volume string) int64 {
+	trash := volumeTrashPath(volume)
+	if _, err := os.Stat(trash); err != nil {
+		return 0
+	}
+	size, err := getDirectoryLogicalSizeWithExclude(trash, "")
+	if err != nil {
+		return 0
+	}
+	return size
+}
+
+func volumeTrashCmd(volume string) tea.Cmd {
+	return func() tea.Msg {
+		return volumeTrashMsg{Volume: volume, Size: measureVolumeTrash(volume)}
+	}
+}
+
+// emptyVolumeTrashCmd removes trashed items; other users' folders are skipped when not writable.
+func emptyVolumeTrashCmd(volume string, counter *int64) tea.Cmd {
+	return func() tea.Msg {
+		trash := volumeTrashPath(volume)
+		userDirs, err := os.ReadDir(trash)
+		if err != nil {
+			return volumeTrashEmptiedMsg{Volume: volume, Err: err}
+		}
+
+		var total int64
+		var skipped int
+		var firstErr error
+		uid := strconv.Itoa(os.Getuid())
+		for _, userDir := range userDirs {
+			dirPath := filepath.Join(trash, userDir.Name())
+			items, err := os.ReadDir(dirPath)
+			if err != nil {
+				// Another user's trash: only root can clear it.
+				skipped++
+				continue
+			}
+			for _, item := range items {
+				count, err := deletePathWithProgress(filepath.Join(dirPath, item.Name()), counter)
+				total += count
+				if err != nil && firstErr == nil && userDir.Name() == uid {
+					firstErr = err
+				}
+			}
+		}
+		return volumeTrashEmptiedMsg{Volume: volume, Count: total, Skipped: skipped, Err: firstErr}
+	}
+}
+
+func (m model) inVolumesView() bool {
+	return !m.inOverviewMode() && m.path == volumesRoot
+}
+
+// scheduleVolumeTrashScans measures each mounted volume's trash after listing /Volumes.
+func (m *model) scheduleVolumeTrashScans() tea.Cmd {
+	if !m.inVolumesView() {
+		return nil
+	}
+	var cmds []tea.Cmd
+	for _, entry := range m.entries {
+		if entry.IsDir {
+			cmds = append(cmds, volumeTrashCmd(entry.Path))
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// scheduleDriveHealthChecks asks once per session about the drive behind each volume.
+func (m *model) scheduleDriveHealthChecks() tea.Cmd {
+	if !m.inVolumesView() {
+		return nil
+	}
+	var cmds []tea.Cmd
+	for _, entry := range m.entries {
+		if _, known := m.volumeHealth[entry.Path]; entry.IsDir && !known {
+			cmds = append(cmds, driveHealthCmd(entry.Path))
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m model) volumeTrashHint(entry dirEntry) string {
+	if !m.inVolumesView() {
+		return ""
+	}
+	if health, ok := m.volumeHealth[entry.Path]; ok && health.failing() {
+		return fmt.Sprintf("%s⚠ drive failing%s", colorRed, colorReset)
+	}
+	size := m.volumeTrash[entry.Path]
+	if size <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s🗑 %s%s", colorGray, humanizeBytes(size), colorReset)
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// warmRefreshAge is how old a stored overview size may get before --warm measures it again.
+	warmRefreshAge = 12 * time.Hour
+	// warmLockFile keeps overlapping login item and cron runs from measuring twice.
+	warmLockFile = "warm.lock"
+	warmWorkers  = 4
+)
+
+// warmOverview refreshes stale overview sizes with du, oldest first, and stops the
+// moment budget is spent: du runs still going are killed rather than waited for. It
+// prints nothing, so the next overview simply opens with fresh numbers. It returns how
+// many folders it refreshed.
+func warmOverview(paths []string, budget time.Duration) int {
+	ctx, cancel := context.WithTimeout(context.Background(), budget)
+	defer cancel()
+
+	type pending struct {
+		path string
+		age  time.Duration
+	}
+	var queue []pending
+	for _, path := range paths {
+		age, ok := overviewSnapshotAge(path)
+		if ok && age < warmRefreshAge {
+			continue
+		}
+		if !ok {
+			age = math.MaxInt64
+		}
+		queue = append(queue, pending{path: path, age: age})
+	}
+	sort.SliceStable(queue, func(a, b int) bool { return queue[a].age > queue[b].age })
+
+	var refreshed int64
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, warmWorkers)
+	for _, item := range queue {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(path string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			size, err := getDirectorySizeFromDuContext(ctx, path, overviewExcludePath(path))
+			if err == nil && size > 0 && ctx.Err() == nil && storeOverviewSize(path, size) == nil {
+				atomic.AddInt64(&refreshed, 1)
+			}
+		}(item.path)
+	}
+	wg.Wait()
+	return int(refreshed)
+}
+
+func overviewPaths() []string {
+	entries := createOverviewEntries()
+	paths := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		paths = append(paths, entry.Path)
+	}
+	return paths
+}
+
+// runWarm is --warm: refresh every overview root unless another warm-up is already at it,
+// then check the alerts.toml thresholds.
+func runWarm(budget time.Duration) {
+	release, err := acquireLock(warmLockFile)
+	if err != nil {
+		return
+	}
+	defer release()
+	deadline := time.Now().Add(budget)
+	warmOverview(overviewPaths(), budget)
+
+	// Alerts still fire once the budget is spent, from free space and stored sizes.
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+	checkAlerts(ctx)
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWarmOverviewRefreshesOnlyStaleSizes(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	resetOverviewSnapshotForTest()
+	t.Cleanup(resetOverviewSnapshotForTest)
+
+	fresh, missing := filepath.Join(home, "fresh"), filepath.Join(home, "missing")
+	for _, dir := range []string{fresh, missing} {
+		writeFileWithSize(t, filepath.Join(dir, "data.bin"), 64<<10)
+	}
+	if err := storeOverviewSize(fresh, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := warmOverview([]string{fresh, missing}, 0); got != 0 {
+		t.Fatalf("a spent budget should measure nothing, refreshed %d", got)
+	}
+	if got := warmOverview([]string{fresh, missing}, 30*time.Second); got != 1 {
+		t.Fatalf("expected only the missing size refreshed, got %d", got)
+	}
+	if size, _ := loadStoredOverviewSize(fresh); size != 1 {
+		t.Fatalf("fresh size should be left alone, got %d", size)
+	}
+	if size, err := loadStoredOverviewSize(missing); err != nil || size <= 0 {
+		t.Fatalf("missing size not stored: %d, %v", size, err)
+	}
+}
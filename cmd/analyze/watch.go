@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// watchEnvVar turns on --watch by default.
+	watchEnvVar = "MO_ANALYZE_WATCH"
+	// watchInterval is how often --watch rescans the open folder.
+	watchInterval = 3 * time.Second
+)
+
+type watchTickMsg struct{}
+
+// watchScanMsg carries a background rescan of Path for --watch.
+type watchScanMsg struct {
+	Path   string
+	Result scanResult
+	Err    error
+}
+
+func watchTickCmd() tea.Cmd {
+	return tea.Tick(watchInterval, func(time.Time) tea.Msg { return watchTickMsg{} })
+}
+
+// watchScanCmd rescans path with its own counters so the view keeps showing results
+// instead of the scan progress screen.
+func watchScanCmd(path string) tea.Cmd {
+	return func() tea.Msg {
+		var files, dirs, bytes int64
+		current := ""
+		result, err := scanPathConcurrent(context.Background(), path, &files, &dirs, &bytes, &current, nil)
+		return watchScanMsg{Path: path, Result: result, Err: err}
+	}
+}
+
+// mergeWatchedEntries applies a rescan to the listed entries without reordering them, so
+// the cursor stays put. New entries go to the end, vanished ones are dropped, and the
+// size change of every entry that moved is returned by path.
+func mergeWatchedEntries(current, fresh []dirEntry) ([]dirEntry, map[string]int64) {
+	byPath := make(map[string]dirEntry, len(fresh))
+	for _, entry := range fresh {
+		byPath[entry.Path] = entry
+	}
+	changes := make(map[string]int64)
+	merged := make([]dirEntry, 0, len(fresh))
+	for _, entry := range current {
+		next, ok := byPath[entry.Path]
+		if !ok {
+			continue
+		}
+		delete(byPath, entry.Path)
+		if delta := next.Size - entry.Size; delta != 0 {
+			changes[entry.Path] = delta
+		}
+		merged = append(merged, next)
+	}
+	for _, entry := range fresh {
+		if _, ok := byPath[entry.Path]; ok {
+			changes[entry.Path] = entry.Size
+			merged = append(merged, entry)
+		}
+	}
+	return merged, changes
+}
@@ -0,0 +1,26 @@
+package main
+
+import "testing"
+
+func TestMergeWatchedEntries(t *testing.T) {
+	current := []dirEntry{
+		{Path: "/w/logs", Size: 100},
+		{Path: "/w/cache", Size: 50},
+		{Path: "/w/gone", Size: 10},
+	}
+	fresh := []dirEntry{
+		{Path: "/w/cache", Size: 20},
+		{Path: "/w/new", Size: 5},
+		{Path: "/w/logs", Size: 400},
+	}
+	merged, changes := mergeWatchedEntries(current, fresh)
+	if len(merged) != 3 || merged[0].Path != "/w/logs" || merged[1].Path != "/w/cache" || merged[2].Path != "/w/new" {
+		t.Fatalf("unexpected order: %+v", merged)
+	}
+	if merged[0].Size != 400 || merged[1].Size != 20 {
+		t.Fatalf("sizes not updated: %+v", merged)
+	}
+	if changes["/w/logs"] != 300 || changes["/w/cache"] != -30 || changes["/w/new"] != 5 || len(changes) != 3 {
+		t.Fatalf("unexpected changes: %v", changes)
+	}
+}
@@ -0,0 +1,65 @@
+//go:build darwin
+
+package main
+
+import "fmt"
+
+// applyWatchScan folds a --watch rescan into the open listing, keeping the cursor on
+// the same entry and remembering which sizes moved.
+func (m model) applyWatchScan(result scanResult) model {
+	base := m.entries
+	if m.filter != nil {
+		base = m.filter.entries
+	}
+	fresh := make([]dirEntry, 0, len(result.Entries))
+	for _, entry := range result.Entries {
+		if entry.Size > 0 {
+			fresh = append(fresh, entry)
+		}
+	}
+	var selectedPath string
+	if m.selected >= 0 && m.selected < len(m.entries) {
+		selectedPath = m.entries[m.selected].Path
+	}
+
+	merged, changes := mergeWatchedEntries(base, fresh)
+	m.entries = merged
+	m.largeFiles = sortLargeFiles(result.LargeFiles, m.largeSort)
+	m.refilter(m.entries, m.largeFiles)
+	delta := result.TotalSize - m.totalSize
+	m.totalSize = result.TotalSize
+	m.watchChanges = changes
+	for i, entry := range m.entries {
+		if entry.Path == selectedPath {
+			m.selected = i
+			break
+		}
+	}
+	m.clampEntrySelection()
+	m.clampLargeSelection()
+	m.cache[m.path] = cacheSnapshot(m)
+	if delta != 0 {
+		m.status = fmt.Sprintf("Watching: %s since last scan, %d changed", signedBytes(delta), len(changes))
+	}
+	return m
+}
+
+// watchMarker flags an entry whose size moved in the last --watch rescan.
+func (m model) watchMarker(path string) string {
+	delta, ok := m.watchChanges[path]
+	if !ok {
+		return ""
+	}
+	if delta > 0 {
+		return fmt.Sprintf("  %s▲%s%s", colorYellow, signedBytes(delta), colorReset)
+	}
+	return fmt.Sprintf("  %s▼%s%s", colorGreen, signedBytes(delta), colorReset)
+}
+
+// signedBytes formats a size change with its sign.
+func signedBytes(delta int64) string {
+	if delta < 0 {
+		return "-" + humanizeBytes(-delta)
+	}
+	return "+" + humanizeBytes(delta)
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// workspaceEnvVar names the workspace to use when --workspace is not given.
+const workspaceEnvVar = "MO_WORKSPACE"
+
+// workspaceTable prefixes the keys of [workspace.<name>] tables in config.toml. A
+// workspace overrides any top-level setting, for a context with its own folders:
+//
+//	default_workspace = "home"   # Skips the picker at startup
+//
+//	[workspace.video]
+//	roots = ["~/Movies", "/Volumes/Footage"]
+//	large_file_threshold = "5GB"
+//	safe_mode = true
+//
+//	[workspace."work laptop"]
+//	exclude = ["~/Personal"]
+const workspaceTable = "workspace."
+
+// activeWorkspace is the workspace in use, "" for the top-level settings. Pins are
+// kept per workspace.
+var activeWorkspace string
+
+// overviewRoots replace the overview's folders when the settings list roots.
+var overviewRoots []string
+
+// splitWorkspaceKey splits "workspace.video.roots" or `workspace."work laptop".roots`
+// into the workspace name and its setting.
+func splitWorkspaceKey(key string) (name, setting string, ok bool) {
+	rest, ok := strings.CutPrefix(key, workspaceTable)
+	if !ok {
+		return "", "", false
+	}
+	if strings.HasPrefix(rest, `"`) {
+		end := strings.Index(rest[1:], `"`)
+		if end < 0 {
+			return "", "", false
+		}
+		name, rest = rest[1:end+1], rest[end+2:]
+		setting, ok = strings.CutPrefix(rest, ".")
+		return name, setting, ok && name != ""
+	}
+	name, setting, ok = strings.Cut(rest, ".")
+	return name, setting, ok && name != ""
+}
+
+// workspaceNames lists the workspaces config.toml defines, sorted.
+func workspaceNames(values map[string]configValue) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for key := range values {
+		if name, _, ok := splitWorkspaceKey(key); ok && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// defaultWorkspace is the workspace config.toml names to use without a choice.
+func defaultWorkspace(values map[string]configValue) string {
+	name, _ := values["default_workspace"].(string)
+	return name
+}
+
+// workspaceValues lays the named workspace's settings over the top-level ones and drops
+// every workspace table. "" keeps the top-level settings.
+func workspaceValues(values map[string]configValue, name string) (map[string]configValue, error) {
+	merged := make(map[string]configValue)
+	overlay := make(map[string]configValue)
+	for key, value := range values {
+		if wsName, setting, ok := splitWorkspaceKey(key); ok {
+			if wsName == name {
+				overlay[setting] = value
+			}
+			continue
+		}
+		if key != "default_workspace" {
+			merged[key] = value
+		}
+	}
+	if name != "" && len(overlay) == 0 {
+		names := workspaceNames(values)
+		if len(names) == 0 {
+			return nil, fmt.Errorf("no workspace %q: config.toml defines none", name)
+		}
+		return nil, fmt.Errorf("no workspace %q, expected one of %s", name, strings.Join(names, ", "))
+	}
+	for key, value := range overlay {
+		merged[key] = value
+	}
+	return merged, nil
+}
+
+// pickWorkspace asks which workspace to use, by number or name. An empty answer keeps
+// the top-level settings.
+func pickWorkspace(in io.Reader, out io.Writer, names []string) string {
+	fmt.Fprintln(out, "Workspaces:")
+	for i, name := range names {
+		fmt.Fprintf(out, "  %d) %s\n", i+1, name)
+	}
+	reader := bufio.NewReader(in)
+	for {
+		fmt.Fprint(out, "Workspace (Enter for none): ")
+		line, err := reader.ReadString('\n')
+		answer := strings.TrimSpace(line)
+		if answer == "" {
+			return ""
+		}
+		if n, convErr := strconv.Atoi(answer); convErr == nil && n >= 1 && n <= len(names) {
+			return names[n-1]
+		}
+		for _, name := range names {
+			if strings.EqualFold(name, answer) {
+				return name
+			}
+		}
+		if err != nil {
+			return ""
+		}
+		fmt.Fprintf(out, "No workspace %q\n", answer)
+	}
+}
+
+// setOverviewRoots expands ~ in the configured roots; relative ones are ignored.
+func setOverviewRoots(roots []string, home string) {
+	overviewRoots = nil
+	for _, root := range roots {
+		if root == "~" || strings.HasPrefix(root, "~/") {
+			root = home + root[1:]
+		}
+		if filepath.IsAbs(root) {
+			overviewRoots = append(overviewRoots, filepath.Clean(root))
+		}
+	}
+}
+
+// workspaceSlug names a workspace in file names.
+func workspaceSlug(name string) string {
+	return strings.Trim(strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			return r
+		}
+		if r >= 'A' && r <= 'Z' {
+			return r + 'a' - 'A'
+		}
+		return '-'
+	}, name), "-")
+}
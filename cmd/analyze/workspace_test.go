@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const workspaceConfig = `large_file_threshold = "500MB"
+exclude = ["~/Backups"]
+
+[workspace.video]
+roots = ["~/Movies", "/Volumes/Footage"]
+large_file_threshold = "5GB"
+safe_mode = true
+
+[workspace."work laptop"]
+exclude = ["~/Personal"]
+
+[workspace.video.keys]
+delete = "x"
+`
+
+func TestWorkspaceValuesOverlayTopLevel(t *testing.T) {
+	values, err := parseConfigTOML(workspaceConfig)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if names := workspaceNames(values); !reflect.DeepEqual(names, []string{"video", "work laptop"}) {
+		t.Fatalf("workspaceNames = %v", names)
+	}
+
+	cfg, err := loadUserConfig(values, "video")
+	if err != nil {
+		t.Fatalf("video: %v", err)
+	}
+	if cfg.LargeFileThreshold != 5<<30 || !cfg.SafeMode || len(cfg.Roots) != 2 || cfg.Workspace != "video" {
+		t.Fatalf("video settings not applied: %+v", cfg)
+	}
+	if !reflect.DeepEqual(cfg.Exclude, []string{"~/Backups"}) || cfg.KeyRemap["x"] != "delete" {
+		t.Fatalf("top-level and table settings should carry over: %+v", cfg)
+	}
+
+	cfg, err = loadUserConfig(values, "work laptop")
+	if err != nil {
+		t.Fatalf("work laptop: %v", err)
+	}
+	if cfg.LargeFileThreshold != 500<<20 || !reflect.DeepEqual(cfg.Exclude, []string{"~/Personal"}) || cfg.SafeMode {
+		t.Fatalf("work laptop settings: %+v", cfg)
+	}
+
+	if cfg, err = loadUserConfig(values, ""); err != nil || cfg.LargeFileThreshold != 500<<20 || cfg.Workspace != "" {
+		t.Fatalf("top-level settings: %+v, %v", cfg, err)
+	}
+	if _, err := loadUserConfig(values, "gaming"); err == nil || !strings.Contains(err.Error(), "video, work laptop") {
+		t.Fatalf("unknown workspace error = %v", err)
+	}
+}
+
+func TestPickWorkspace(t *testing.T) {
+	names := []string{"home", "video"}
+	var out bytes.Buffer
+	if got := pickWorkspace(strings.NewReader("3\nVIDEO\n"), &out, names); got != "video" {
+		t.Fatalf("picked %q", got)
+	}
+	if !strings.Contains(out.String(), "2) video") || !strings.Contains(out.String(), `No workspace "3"`) {
+		t.Fatalf("picker output: %q", out.String())
+	}
+	if got := pickWorkspace(strings.NewReader("\n"), &out, names); got != "" {
+		t.Fatalf("empty answer picked %q", got)
+	}
+	if got := pickWorkspace(strings.NewReader("1"), &out, names); got != "home" {
+		t.Fatalf("answer without newline picked %q", got)
+	}
+}
+
+func TestWorkspaceSlug(t *testing.T) {
+	if got := workspaceSlug("Work Laptop!"); got != "work-laptop" {
+		t.Fatalf("workspaceSlug = %q", got)
+	}
+}
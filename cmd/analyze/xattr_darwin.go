@@ -0,0 +1,35 @@
+//go:build darwin
+
+package main
+
+import (
+	"bytes"
+	"syscall"
+	"unsafe"
+)
+
+// xattrNames lists the extended attributes of path without following symlinks.
+func xattrNames(path string) ([]string, error) {
+	p, err := syscall.BytePtrFromString(path)
+	if err != nil {
+		return nil, err
+	}
+	size, _, errno := syscall.Syscall6(syscall.SYS_LISTXATTR, uintptr(unsafe.Pointer(p)), 0, 0, xattrNoFollow, 0, 0)
+	if errno != 0 {
+		return nil, errno
+	}
+	if size == 0 {
+		return nil, nil
+	}
+	buf := make([]byte, size)
+	size, _, errno = syscall.Syscall6(syscall.SYS_LISTXATTR, uintptr(unsafe.Pointer(p)),
+		uintptr(unsafe.Pointer(&buf[0])), uintptr(len(buf)), xattrNoFollow, 0, 0)
+	if errno != 0 {
+		return nil, errno
+	}
+	var names []string
+	for _, name := range bytes.Split(bytes.TrimRight(buf[:size], "\x00"), []byte{0}) {
+		names = append(names, string(name))
+	}
+	return names, nil
+}
@@ -0,0 +1,10 @@
+//go:build !darwin
+
+package main
+
+import "errors"
+
+// xattrNames is only implemented for macOS volumes.
+func xattrNames(string) ([]string, error) {
+	return nil, errors.ErrUnsupported
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bufio"
+	"os"
+
+	"github.com/charmbracelet/x/term"
+)
+
+// key is one keypress, as the prompts and the whitelist menu see it.
+type key int
+
+const (
+	keyOther key = iota
+	keyEnter
+	keySpace
+	keyQuit
+	keyUp
+	keyDown
+)
+
+// stdin is shared by every prompt, so keys typed ahead, or piped in by the tests, are
+// read in order instead of lost in a reader that was thrown away.
+var stdin = bufio.NewReader(os.Stdin)
+
+// readKey waits for one key. The terminal is raw only while it waits, so Ctrl+C
+// arrives as a key; a closed input reads as quit.
+func readKey() key {
+	if fd := os.Stdin.Fd(); term.IsTerminal(fd) {
+		if state, err := term.MakeRaw(fd); err == nil {
+			defer term.Restore(fd, state)
+		}
+	}
+	c, err := stdin.ReadByte()
+	if err != nil {
+		return keyQuit
+	}
+	switch c {
+	case '\r', '\n':
+		return keyEnter
+	case ' ':
+		return keySpace
+	case 'q', 'Q', 0x03:
+		return keyQuit
+	case 'j', 'J':
+		return keyDown
+	case 'k', 'K':
+		return keyUp
+	case 0x1b:
+		return readEscape()
+	}
+	return keyOther
+}
+
+// readEscape reads the rest of an arrow key sequence. A terminal sends the whole
+// sequence at once, so Esc with nothing after it is Esc itself.
+func readEscape() key {
+	if stdin.Buffered() == 0 {
+		return keyQuit
+	}
+	if prefix, _ := stdin.ReadByte(); prefix != '[' && prefix != 'O' {
+		return keyOther
+	}
+	if stdin.Buffered() == 0 {
+		return keyOther
+	}
+	switch c, _ := stdin.ReadByte(); c {
+	case 'A':
+		return keyUp
+	case 'B':
+		return keyDown
+	}
+	return keyOther
+}
@@ -0,0 +1,494 @@
+// Package main is mo clean. It asks for admin access, runs the cleanup categories of
+// internal/clean in order and prints each section as it finishes. --whitelist opens
+// the editor for the paths it must leave alone.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/tw93/mole/internal/clean"
+	"github.com/tw93/mole/internal/coordination"
+	"github.com/tw93/mole/internal/humanize"
+)
+
+const (
+	colorPurple     = "\033[0;35m"
+	colorPurpleBold = "\033[1;35m"
+	colorBlue       = "\033[0;34m"
+	colorGreen      = "\033[0;32m"
+	colorYellow     = "\033[0;33m"
+	colorRed        = "\033[0;31m"
+	colorCyan       = "\033[0;36m"
+	colorGray       = "\033[0;90m"
+	colorReset      = "\033[0m"
+)
+
+const (
+	analyzeWaitLimit  = 60 * time.Second
+	cleanListFile     = "clean-list.txt"
+	progressLineWidth = 60
+	dataVolume        = "/System/Volumes/Data"
+)
+
+var (
+	// permissionFlag marks that the Library folder prompts were already asked for.
+	permissionFlag = filepath.Join(".cache", "mole", "permissions_granted")
+	// permissionDirs are the folders macOS asks about the first time they are read.
+	permissionDirs = []string{"Library/Caches", "Library/Logs", "Library/Application Support", "Library/Containers", ".cache"}
+	// diskAccessProbes need Full Disk Access to read; the ones that exist tell whether
+	// the terminal has it.
+	diskAccessProbes = []string{"Library/Safari/LocalStorage", "Library/Mail/V10", "Library/Messages/chat.db"}
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "Preview what would be cleaned without removing anything")
+	flag.BoolVar(dryRun, "n", false, "Shorthand for --dry-run")
+	debug := flag.Bool("debug", false, "Log each step to ~/.config/mole/mole_debug_session.log")
+	whitelist := flag.Bool("whitelist", false, "Choose which paths are protected from cleaning")
+	jsonOutput := flag.Bool("json", false, "Print the result as JSON instead of the report")
+	receipts := flag.Bool("receipts", false, "Also remove what installer packages of deleted apps left behind, and their receipts (needs sudo)")
+	flag.Parse()
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "mo clean: %v\n", err)
+		os.Exit(1)
+	}
+	if *whitelist {
+		if err := manageWhitelist(home); err != nil {
+			if !errors.Is(err, errCanceled) {
+				fmt.Fprintf(os.Stderr, "mo clean: %v\n", err)
+			}
+			os.Exit(1)
+		}
+		return
+	}
+	if *debug || os.Getenv("MO_DEBUG") == "1" {
+		startDebugSession(home)
+	}
+
+	if *jsonOutput {
+		err = runJSON(home, *dryRun, *receipts)
+	} else {
+		err = run(home, *dryRun, *receipts)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "mo clean: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run is the interactive clean: header, sudo prompt, every section, then the summary.
+func run(home string, dryRun, receipts bool) error {
+	protection, err := clean.LoadInstalledProtection()
+	if err != nil {
+		return fmt.Errorf("cannot read protected apps, refusing to clean: %w", err)
+	}
+	configDir, err := coordination.Dir()
+	if err != nil {
+		return err
+	}
+	whitelist := clean.LoadWhitelist(home)
+
+	if isTerminal(os.Stdout) {
+		fmt.Print("\033[2J\033[H")
+	}
+	fmt.Printf("\n%sClean Your Mac%s\n\n", colorPurpleBold, colorReset)
+	sudo := false
+	if dryRun {
+		fmt.Printf("%sDry Run Mode%s - Preview only, no deletions\n\n", colorYellow, colorReset)
+	} else {
+		sudo = askForSudo()
+	}
+
+	release := func() {}
+	if !dryRun {
+		release = takeCleanLock(configDir)
+	}
+	defer release()
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		code := 130
+		if <-signals == syscall.SIGTERM {
+			code = 143
+		}
+		release()
+		fmt.Print("\r\033[K")
+		os.Exit(code)
+	}()
+
+	if os.Getenv("MOLE_TEST_MODE") == "1" {
+		// Test mode skips the scans and prints just enough for the CLI tests.
+		writeTestMode(os.Stdout, dryRun, whitelist, home)
+		printSummary(os.Stdout, home, "Test mode complete", []string{"Test mode - no actual cleanup performed"})
+		fmt.Println()
+		return nil
+	}
+
+	fmt.Printf("%s⚙%s %s | Free space: %s\n", colorBlue, colorReset, architecture(), freeSpace("-h"))
+	checkPermissions(home)
+	freeBefore := freeSpaceKB()
+	writeWhitelistSummary(os.Stdout, whitelist, home, dryRun)
+	if isTerminal(os.Stdout) && !dryRun && lacksFullDiskAccess(home) {
+		fmt.Printf("\n%s●%s %sTip: Grant Full Disk Access to your terminal in System Settings for best results%s\n", colorYellow, colorReset, colorGray, colorReset)
+	}
+
+	r := newReporter(os.Stdout, home, dryRun, whitelist)
+	opts := clean.Options{
+		Home:       home,
+		DryRun:     dryRun,
+		Whitelist:  whitelist,
+		Protection: protection,
+		Sudo:       sudo,
+		Progress:   r.progress,
+		Report:     r.report,
+	}
+	if isTerminal(os.Stdin) {
+		opts.Confirm = confirm
+	}
+	result := clean.Run(clean.Categories, opts)
+	if receipts {
+		category := cleanReceipts(opts)
+		r.report(category)
+		result.Add(category)
+	}
+
+	if dryRun {
+		if err := writeCleanList(filepath.Join(configDir, cleanListFile), result, time.Now()); err != nil {
+			fmt.Fprintf(os.Stderr, "mo clean: %v\n", err)
+		}
+	} else {
+		coordination.RecordChanges(configDir, result.Paths())
+	}
+
+	fmt.Println()
+	disk := diskCheck{Free: freeSpace("-h"), FreedKB: -1}
+	if !dryRun && result.Size > 0 && freeBefore >= 0 {
+		if freeAfter := freeSpaceKB(); freeAfter >= 0 {
+			disk.FreedKB = freeAfter - freeBefore
+			disk.Gap = cleanupGap(result.Size/1024, disk.FreedKB, localSnapshotCount)
+		}
+	}
+	heading, details := summary(result, disk)
+	printSummary(os.Stdout, home, heading, details)
+	fmt.Println()
+	return nil
+}
+
+// runJSON cleans without prompts or admin access and prints the result as JSON.
+func runJSON(home string, dryRun, receipts bool) error {
+	protection, err := clean.LoadInstalledProtection()
+	if err != nil {
+		return fmt.Errorf("cannot read protected apps, refusing to clean: %w", err)
+	}
+	configDir, err := coordination.Dir()
+	if err != nil {
+		return err
+	}
+	if !dryRun {
+		release := takeCleanLock(configDir)
+		defer release()
+	}
+
+	opts := clean.Options{
+		Home:       home,
+		DryRun:     dryRun,
+		Whitelist:  clean.LoadWhitelist(home),
+		Protection: protection,
+	}
+	if isTerminal(os.Stderr) {
+		opts.Progress = func(p clean.Progress) {
+			line := fmt.Sprintf("[%d/%d] %s: %s", p.Done+1, p.Total, p.Category, p.Target)
+			if len(line) > progressLineWidth {
+				line = line[:progressLineWidth]
+			}
+			fmt.Fprintf(os.Stderr, "\r\033[K%s", line)
+		}
+	}
+	result := clean.Run(clean.Categories, opts)
+	if receipts {
+		result.Add(cleanReceipts(opts))
+	}
+	if opts.Progress != nil {
+		fmt.Fprint(os.Stderr, "\r\033[K")
+	}
+	if dryRun {
+		if err := writeCleanList(filepath.Join(configDir, cleanListFile), result, time.Now()); err != nil {
+			fmt.Fprintf(os.Stderr, "mo clean: %v\n", err)
+		}
+	} else {
+		coordination.RecordChanges(configDir, result.Paths())
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(result)
+}
+
+// askForSudo offers system cleanup: Enter asks for admin access, Space skips it and
+// q or Esc quits. Without a terminal only user-level cleanup runs.
+func askForSudo() bool {
+	if !isTerminal(os.Stdin) {
+		fmt.Println()
+		fmt.Println("Running in non-interactive mode")
+		fmt.Println("  • System-level cleanup skipped (requires interaction)")
+		fmt.Println("  • User-level cleanup will proceed automatically")
+		fmt.Println()
+		return false
+	}
+	fmt.Printf("%s● Use --dry-run to preview, --whitelist to manage protected paths%s\n", colorGray, colorReset)
+	fmt.Printf("%s➤%s System caches need sudo — %sEnter%s continue, %sSpace%s skip: ", colorPurple, colorReset, colorGreen, colorReset, colorGray, colorReset)
+	switch readKey() {
+	case keyQuit:
+		fmt.Printf(" %sCanceled%s\n", colorGray, colorReset)
+		os.Exit(0)
+	case keyEnter:
+		fmt.Print("\r\033[K")
+		if requestSudo("System cleanup requires admin access") {
+			fmt.Printf("%s✓%s Admin access granted\n\n", colorGreen, colorReset)
+			return true
+		}
+		fmt.Printf("\n%sAuthentication failed%s, continuing with user-level cleanup\n", colorYellow, colorReset)
+		return false
+	}
+	fmt.Printf(" %sSkipped%s\n\n", colorGray, colorReset)
+	return false
+}
+
+// confirm asks a yes or no question mid-run: Enter agrees, anything else skips.
+func confirm(question string) bool {
+	fmt.Printf("  %s➤%s %s %sEnter%s continue, %sSpace%s skip: ", colorPurple, colorReset, question, colorGreen, colorReset, colorGray, colorReset)
+	if readKey() == keyEnter {
+		fmt.Print("\r\033[K")
+		return true
+	}
+	fmt.Printf(" %sSkipped%s\n", colorGray, colorReset)
+	return false
+}
+
+// writeTestMode is the stand-in run of MOLE_TEST_MODE.
+func writeTestMode(w io.Writer, dryRun bool, whitelist clean.Whitelist, home string) {
+	if dryRun {
+		fmt.Fprintf(w, "%sDry Run Mode%s - Preview only, no deletions\n\n", colorYellow, colorReset)
+	}
+	fmt.Fprintf(w, "%s•%s User app cache\n", colorGreen, colorReset)
+	if _, custom := whitelistCounts(whitelist, home); custom > 0 {
+		fmt.Fprintf(w, "%s✓%s Protected items found\n", colorGreen, colorReset)
+	}
+	if dryRun {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "Potential space: 0.00GB")
+	}
+}
+
+// whitelistCounts splits the whitelist into the default patterns and the user's own.
+func whitelistCounts(whitelist clean.Whitelist, home string) (core, custom int) {
+	defaults := make(map[string]bool)
+	for _, pattern := range clean.DefaultWhitelist(home).Patterns {
+		defaults[strings.TrimSuffix(pattern, "/")] = true
+	}
+	for _, pattern := range whitelist.Patterns {
+		if defaults[strings.TrimSuffix(pattern, "/")] {
+			core++
+		} else {
+			custom++
+		}
+	}
+	return core, custom
+}
+
+// writeWhitelistSummary says how many patterns are active, and in a dry run lists them.
+func writeWhitelistSummary(w io.Writer, whitelist clean.Whitelist, home string, dryRun bool) {
+	core, custom := whitelistCounts(whitelist, home)
+	if core == 0 && custom == 0 {
+		return
+	}
+	var parts []string
+	if core > 0 {
+		parts = append(parts, fmt.Sprintf("%d core", core))
+	}
+	if custom > 0 {
+		parts = append(parts, fmt.Sprintf("%d custom", custom))
+	}
+	fmt.Fprintf(w, "%s✓%s Whitelist: %s patterns active\n", colorBlue, colorReset, strings.Join(parts, " + "))
+	if !dryRun {
+		return
+	}
+	for _, pattern := range whitelist.Patterns {
+		if pattern != clean.FinderMetadataSentinel {
+			fmt.Fprintf(w, "  %s→ %s%s\n", colorGray, pattern, colorReset)
+		}
+	}
+}
+
+// checkPermissions lets macOS ask about the guarded Library folders up front, once,
+// instead of in the middle of a run.
+func checkPermissions(home string) {
+	flagFile := filepath.Join(home, permissionFlag)
+	if !isTerminal(os.Stdout) {
+		return
+	}
+	if _, err := os.Stat(flagFile); err == nil {
+		return
+	}
+	if _, err := os.ReadDir(filepath.Join(home, "Library", "Caches")); err != nil {
+		fmt.Printf("\n%sFirst-time setup%s\n", colorBlue, colorReset)
+		fmt.Printf("%smacOS will request permissions to access Library folders.%s\n", colorGray, colorReset)
+		fmt.Printf("%sYou may see %s%d permission dialogs%s%s - please approve them all.%s\n\n", colorGray, colorGreen, len(permissionDirs), colorReset, colorGray, colorReset)
+		fmt.Printf("%s➤%s Press %sEnter%s to continue: ", colorPurple, colorReset, colorGreen, colorReset)
+		_, _ = stdin.ReadString('\n')
+		for _, dir := range permissionDirs {
+			_, _ = os.ReadDir(filepath.Join(home, dir))
+		}
+		fmt.Println()
+	}
+	if err := os.MkdirAll(filepath.Dir(flagFile), 0755); err == nil {
+		_ = os.WriteFile(flagFile, nil, 0644)
+	}
+}
+
+// lacksFullDiskAccess reports that the terminal definitely has no Full Disk Access:
+// some guarded paths exist and none of them can be read. Without any to try it is
+// unknown, and no tip is shown.
+func lacksFullDiskAccess(home string) bool {
+	tested := false
+	for _, probe := range diskAccessProbes {
+		path := filepath.Join(home, probe)
+		if _, err := os.Lstat(path); err != nil {
+			continue
+		}
+		tested = true
+		if file, err := os.Open(path); err == nil {
+			file.Close()
+			return false
+		}
+	}
+	return tested
+}
+
+func architecture() string {
+	if runtime.GOARCH == "arm64" {
+		return "Apple Silicon"
+	}
+	return "Intel"
+}
+
+// freeSpace is the free space df reports for the data volume, the fourth column, in
+// the unit sizeFlag asks for.
+func freeSpace(sizeFlag string) string {
+	target := "/"
+	if info, err := os.Stat(dataVolume); err == nil && info.IsDir() {
+		target = dataVolume
+	}
+	output, err := exec.Command("df", sizeFlag, target).Output()
+	if err != nil {
+		return ""
+	}
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	if len(lines) < 2 {
+		return ""
+	}
+	fields := strings.Fields(lines[1])
+	if len(fields) < 4 {
+		return ""
+	}
+	return fields[3]
+}
+
+// freeSpaceKB is freeSpace in KB, or -1 when df cannot tell.
+func freeSpaceKB() int64 {
+	kb, err := strconv.ParseInt(freeSpace("-k"), 10, 64)
+	if err != nil {
+		return -1
+	}
+	return kb
+}
+
+// cleanReceipts audits installer packages of deleted apps. Their payload and receipts
+// belong to root, so anything beyond a dry run needs sudo.
+func cleanReceipts(opts clean.Options) clean.CategoryResult {
+	if !opts.DryRun && os.Geteuid() != 0 {
+		fmt.Fprintf(os.Stderr, "%s● Installer leftovers need sudo: sudo mo clean --receipts%s\n", colorGray, colorReset)
+		return clean.CategoryResult{Name: clean.ReceiptsCategory}
+	}
+	if opts.Progress != nil {
+		opts.Progress(clean.Progress{Category: clean.ReceiptsCategory, Target: "reading package receipts", Total: 1})
+	}
+	orphaned, err := clean.FindOrphanedReceipts(clean.Pkgutil, opts.Measure)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "\r\033[Kmo clean: cannot read package receipts: %v\n", err)
+	}
+	if !opts.DryRun && !confirmReceipts(os.Stderr, orphaned) {
+		return clean.CategoryResult{Name: clean.ReceiptsCategory}
+	}
+	return clean.CleanReceipts(orphaned, clean.Pkgutil, opts)
+}
+
+// confirmReceipts lists every installer leftover that would be removed, and the ones
+// kept in shared folders, then asks before anything is deleted as root.
+func confirmReceipts(out io.Writer, receipts []clean.Receipt) bool {
+	count := 0
+	for _, receipt := range receipts {
+		count += len(receipt.Leftovers)
+	}
+	if len(receipts) == 0 {
+		return true
+	}
+	if !isTerminal(os.Stdin) {
+		fmt.Fprintf(out, "\r\033[K%s● Installer leftovers are only removed after confirming in a terminal; preview them with --dry-run%s\n", colorGray, colorReset)
+		return false
+	}
+	for _, receipt := range receipts {
+		fmt.Fprintf(out, "  %s %s(%s)%s\n", receipt.ID, colorGray, receipt.Reason, colorReset)
+		for _, item := range receipt.Leftovers {
+			fmt.Fprintf(out, "    %s✗%s %s %s(%s)%s\n", colorYellow, colorReset, item.Path, colorGray, humanize.Bytes(item.Size), colorReset)
+		}
+		for _, path := range receipt.Kept {
+			fmt.Fprintf(out, "    %s● %s (shared folder, kept)%s\n", colorGray, path, colorReset)
+		}
+	}
+	fmt.Fprintf(out, "Remove %d files and forget the receipts without kept files? [y/N] ", count)
+	answer, _ := stdin.ReadString('\n')
+	return strings.EqualFold(strings.TrimSpace(answer), "y")
+}
+
+// takeCleanLock waits for an analyzer that is deleting, then records this process in
+// clean.lock so analyzers hold off while files are removed.
+func takeCleanLock(configDir string) func() {
+	waited := time.Duration(0)
+	for waited < analyzeWaitLimit {
+		if _, held := coordination.LockHolder(configDir, coordination.AnalyzeLock); !held {
+			break
+		}
+		if waited == 0 {
+			fmt.Fprintf(os.Stderr, "%s● Waiting for mo analyze to finish deleting...%s\n", colorGray, colorReset)
+		}
+		time.Sleep(time.Second)
+		waited += time.Second
+	}
+	coordination.TrimChanges(configDir)
+	release, err := coordination.AcquireLock(configDir, coordination.CleanLock)
+	if err != nil {
+		// Another mo clean holds it, which keeps analyzers waiting just the same.
+		return func() {}
+	}
+	return release
+}
+
+func isTerminal(file *os.File) bool {
+	return term.IsTerminal(file.Fd())
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tw93/mole/internal/clean"
+)
+
+func sampleResult(dryRun bool) clean.Result {
+	return clean.Result{
+		DryRun: dryRun,
+		Categories: []clean.CategoryResult{{
+			Name: "Browsers",
+			Targets: []clean.TargetResult{{
+				Label: "Chrome cache",
+				Items: []clean.Item{{Path: "/Users/me/Library/Caches/Google/Chrome/Default", Size: 2 << 20}},
+				Size:  2 << 20,
+			}},
+			Size: 2 << 20,
+		}},
+		Size:  2 << 20,
+		Items: 1,
+	}
+}
+
+func TestWriteCleanList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), cleanListFile)
+	if err := writeCleanList(path, sampleResult(true), time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"2025-01-02 03:04:05", "=== Browsers ===", "/Users/me/Library/Caches/Google/Chrome/Default  # 2.0 MB", "# Items: 1", "# Categories: 1"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("clean list missing %q:\n%s", want, data)
+		}
+	}
+}
+
+func TestWriteCleanListGroupsFolders(t *testing.T) {
+	result := sampleResult(true)
+	target := &result.Categories[0].Targets[0]
+	target.Items = append(target.Items, clean.Item{Path: "/Users/me/Library/Caches/Google/Chrome/Profile 1", Size: 1 << 20})
+	path := filepath.Join(t.TempDir(), cleanListFile)
+	if err := writeCleanList(path, result, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "/Users/me/Library/Caches/Google/Chrome  # 3.0 MB (2 items)") {
+		t.Errorf("paths in one folder not grouped:\n%s", data)
+	}
+}
+
+func TestWriteTarget(t *testing.T) {
+	target := sampleResult(true).Categories[0].Targets[0]
+	cases := []struct {
+		target clean.TargetResult
+		dryRun bool
+		want   string
+	}{
+		{target, true, "Chrome cache \033[0;33m(2.0 MB dry)"},
+		{target, false, "Chrome cache \033[0;32m(2.0 MB)"},
+		{clean.TargetResult{Label: "npm cache", Ran: true}, true, "npm cache · would clean"},
+		{clean.TargetResult{Label: "Homebrew", Ran: true, Note: "removed 2 old versions"}, false, "Homebrew · removed 2 old versions"},
+		{clean.TargetResult{Label: "Trash", Note: "whitelist protected"}, false, "Trash · whitelist protected"},
+	}
+	for _, c := range cases {
+		var out bytes.Buffer
+		writeTarget(&out, c.target, c.dryRun)
+		if !strings.Contains(out.String(), c.want) {
+			t.Errorf("writeTarget(%+v, dry %v) = %q, want %q", c.target, c.dryRun, out.String(), c.want)
+		}
+	}
+}
+
+func TestReporterShowsWarningsAfterSystemSection(t *testing.T) {
+	var out bytes.Buffer
+	r := newReporter(&out, t.TempDir(), false, clean.Whitelist{Warnings: []string{"Must be absolute path: foo"}})
+	r.report(clean.CategoryResult{Name: "Deep system"})
+	r.report(clean.CategoryResult{Name: "User essentials"})
+	text := out.String()
+	warning := strings.Index(text, "Whitelist: Must be absolute path")
+	if warning < strings.Index(text, "Deep system") || warning > strings.Index(text, "User essentials") {
+		t.Errorf("warning not between the system and user sections:\n%s", text)
+	}
+	if strings.Count(text, "Nothing to clean") != 2 {
+		t.Errorf("empty sections not reported:\n%s", text)
+	}
+}
+
+func TestSummary(t *testing.T) {
+	_, dry := summary(sampleResult(true), diskCheck{Free: "100Gi", FreedKB: -1})
+	if !strings.Contains(dry[0], "Potential space") || !strings.Contains(dry[0], "Items: 1 | Categories: 1") {
+		t.Errorf("dry run summary = %q", dry)
+	}
+	heading, real := summary(sampleResult(false), diskCheck{Free: "100Gi", FreedKB: 1024, Gap: []string{"Apps recreated some caches"}})
+	if heading != "Cleanup complete" || !strings.Contains(strings.Join(real, "\n"), "Verified: 0.00GB freed on disk (expected 0.00GB)") {
+		t.Errorf("summary = %q %q", heading, real)
+	}
+	if !strings.Contains(real[len(real)-1], "Apps recreated some caches") {
+		t.Errorf("gap reasons missing: %q", real)
+	}
+	_, empty := summary(clean.Result{}, diskCheck{Free: "100Gi"})
+	if len(empty) != 2 || empty[1] != "Free space now: 100Gi" {
+		t.Errorf("empty summary = %q", empty)
+	}
+}
+
+func TestWhitelistCounts(t *testing.T) {
+	home := "/Users/me"
+	whitelist := clean.DefaultWhitelist(home)
+	core, custom := whitelistCounts(whitelist, home)
+	if core == 0 || custom != 0 {
+		t.Errorf("defaults counted as %d core, %d custom", core, custom)
+	}
+	whitelist.Patterns = append(whitelist.Patterns, home+"/Library/Caches/Keep*")
+	if _, custom := whitelistCounts(whitelist, home); custom != 1 {
+		t.Errorf("custom patterns = %d, want 1", custom)
+	}
+}
+
+func TestReadKey(t *testing.T) {
+	defer func(saved *bufio.Reader) { stdin = saved }(stdin)
+	stdin = bufio.NewReader(strings.NewReader("\n jk\x1b[A\x1b[Bxq\x03"))
+	want := []key{keyEnter, keySpace, keyDown, keyUp, keyUp, keyDown, keyOther, keyQuit, keyQuit, keyQuit}
+	for i, w := range want {
+		if got := readKey(); got != w {
+			t.Errorf("key %d = %v, want %v", i, got, w)
+		}
+	}
+	stdin = bufio.NewReader(strings.NewReader("\x1b"))
+	if got := readKey(); got != keyQuit {
+		t.Errorf("lone Esc = %v, want quit", got)
+	}
+}
+
+func TestMultiSelect(t *testing.T) {
+	keys := func(ks ...key) func() key {
+		return func() key {
+			k := ks[0]
+			ks = ks[1:]
+			return k
+		}
+	}
+	menu := newMultiSelect(5, 2)
+	menu.rows = func() int { return 2 }
+	var out bytes.Buffer
+	// Untick the first, scroll past the page and tick the last.
+	got, err := menu.run(&out, keys(keySpace, keyDown, keyDown, keyDown, keyDown, keyDown, keySpace, keyEnter))
+	if err != nil || len(got) != 2 || got[0] != 1 || got[1] != 4 {
+		t.Fatalf("selected = %v, %v", got, err)
+	}
+	if menu.top != 3 || menu.cursor != 1 {
+		t.Errorf("page top %d, cursor %d, want 3 and 1", menu.top, menu.cursor)
+	}
+	if !strings.Contains(out.String(), "2/5 selected") {
+		t.Errorf("menu did not show the count:\n%s", out.String())
+	}
+	if _, err := newMultiSelect(1, 0).run(&out, keys(keyQuit)); err != errCanceled {
+		t.Errorf("quit = %v, want errCanceled", err)
+	}
+}
+
+func TestCleanupGap(t *testing.T) {
+	snapshots := func(n int) func() int { return func() int { return n } }
+	if got := cleanupGap(100, 97, snapshots(2)); got != nil {
+		t.Errorf("within 5%% = %q", got)
+	}
+	if got := cleanupGap(100, 150, snapshots(0)); len(got) != 1 || !strings.Contains(got[0], "extra space") {
+		t.Errorf("more freed = %q", got)
+	}
+	if got := cleanupGap(100, 10, snapshots(3)); len(got) != 2 || !strings.HasPrefix(got[0], "3 local snapshots") {
+		t.Errorf("less freed = %q", got)
+	}
+}
+
+func TestPrintSummary(t *testing.T) {
+	t.Setenv("MO_DEBUG", "1")
+	var out bytes.Buffer
+	printSummary(&out, "/Users/me", "Done", []string{"first", "", "second"})
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 6 || lines[0] != summaryDivider || !strings.Contains(lines[1], "Done") || lines[2] != "first" || lines[3] != "second" || lines[4] != summaryDivider {
+		t.Errorf("summary block = %q", lines)
+	}
+	if !strings.Contains(lines[5], "/Users/me/.config/mole/mole_debug_session.log") {
+		t.Error("debug log location missing")
+	}
+}
+
+func TestCheckPermissionsSkipsWithoutTerminal(t *testing.T) {
+	home := t.TempDir()
+	checkPermissions(home)
+	if _, err := os.Stat(filepath.Join(home, permissionFlag)); !os.IsNotExist(err) {
+		t.Errorf("permission check ran without a terminal: %v", err)
+	}
+}
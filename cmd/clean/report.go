@@ -0,0 +1,339 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tw93/mole/internal/clean"
+	"github.com/tw93/mole/internal/humanize"
+)
+
+const (
+	// movieSizeGB is the size of one 4K movie in the "Equivalent to" line.
+	movieSizeGB = 4.5
+	gigabyte    = 1 << 30
+	// summaryDivider frames the closing block, as wide as the other commands draw it.
+	summaryDivider = "======================================================================"
+	debugDivider   = "----------------------------------------------------------------------"
+)
+
+// debugLogFile is the session log the shell commands share, under home.
+var debugLogFile = filepath.Join(".config", "mole", "mole_debug_session.log")
+
+// startDebugSession starts a fresh session log with a block about the machine, and
+// says where it is.
+func startDebugSession(home string) {
+	os.Setenv("MO_DEBUG", "1")
+	path := filepath.Join(home, debugLogFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return
+	}
+	hostname, _ := os.Hostname()
+	sudo := "Required"
+	if hasSudoSession() {
+		sudo = "Active"
+	}
+	lines := []string{
+		debugDivider,
+		"Mole Debug Session - " + time.Now().Format("2006-01-02 15:04:05"),
+		debugDivider,
+		"User: " + os.Getenv("USER"),
+		"Hostname: " + hostname,
+		"Architecture: " + commandOutput("uname", "-m"),
+		"Kernel: " + commandOutput("uname", "-r"),
+	}
+	if version := commandOutput("sw_vers", "-productVersion"); version != "" {
+		lines = append(lines, fmt.Sprintf("macOS: %s (%s)", version, commandOutput("sw_vers", "-buildVersion")))
+	}
+	lines = append(lines,
+		fmt.Sprintf("Shell: %s (%s)", envOr("SHELL", "unknown"), envOr("TERM", "unknown")),
+		"Sudo Access: "+sudo,
+		debugDivider,
+	)
+	if os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644) != nil {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "%s[DEBUG] Debug logging enabled. Session log: %s%s\n", colorGray, path, colorReset)
+}
+
+func commandOutput(name string, args ...string) string {
+	output, err := exec.Command(name, args...).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+func envOr(name, fallback string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// debugLog writes a gray line on stderr and a timestamped one in the session log, only
+// with MO_DEBUG on.
+func debugLog(home, format string, args ...any) {
+	if os.Getenv("MO_DEBUG") != "1" {
+		return
+	}
+	message := fmt.Sprintf(format, args...)
+	fmt.Fprintf(os.Stderr, "%s[DEBUG]%s %s\n", colorGray, colorReset, message)
+	file, err := os.OpenFile(filepath.Join(home, debugLogFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+	fmt.Fprintf(file, "[%s] DEBUG: %s\n", time.Now().Format("2006-01-02 15:04:05"), message)
+}
+
+// reporter prints each section as the run reaches it, and "Nothing to clean" for the
+// empty ones.
+type reporter struct {
+	w      io.Writer
+	home   string
+	dryRun bool
+	// system names the sections that need admin access; whitelist warnings wait until
+	// the first section after them.
+	system   map[string]bool
+	warnings []string
+	current  string
+}
+
+func newReporter(w io.Writer, home string, dryRun bool, whitelist clean.Whitelist) *reporter {
+	r := &reporter{w: w, home: home, dryRun: dryRun, system: make(map[string]bool), warnings: whitelist.Warnings}
+	for _, category := range clean.Categories {
+		if category.System {
+			r.system[category.Name] = true
+		}
+	}
+	return r
+}
+
+func (r *reporter) progress(p clean.Progress) {
+	if p.Category != r.current {
+		r.section(p.Category)
+	}
+	debugLog(r.home, "Cleaning: %s", p.Target)
+}
+
+func (r *reporter) section(name string) {
+	if !r.system[name] && len(r.warnings) > 0 {
+		fmt.Fprintln(r.w)
+		for _, warning := range r.warnings {
+			fmt.Fprintf(r.w, "  %s●%s Whitelist: %s\n", colorYellow, colorReset, warning)
+		}
+		r.warnings = nil
+	}
+	r.current = name
+	fmt.Fprintf(r.w, "\n%s➤ %s%s\n", colorPurpleBold, name, colorReset)
+}
+
+func (r *reporter) report(category clean.CategoryResult) {
+	if category.Name != r.current {
+		r.section(category.Name)
+	}
+	if len(category.Targets) == 0 {
+		fmt.Fprintf(r.w, "  %s✓%s Nothing to clean\n", colorGreen, colorReset)
+		return
+	}
+	for _, t := range category.Targets {
+		writeTarget(r.w, t, r.dryRun)
+		if len(t.Failed) > 0 {
+			debugLog(r.home, "Skipped %d items (permission denied or in use) for: %s", len(t.Failed), t.Label)
+		}
+	}
+}
+
+// writeTarget prints one target's line: its size, what its tool did, or why it was
+// left alone.
+func writeTarget(w io.Writer, t clean.TargetResult, dryRun bool) {
+	label := t.Label
+	if len(t.Items) > 1 {
+		label = fmt.Sprintf("%s %d items", label, len(t.Items))
+	}
+	note := ""
+	if t.Note != "" {
+		note = " · " + t.Note
+	}
+	switch {
+	case len(t.Items) > 0 && dryRun:
+		fmt.Fprintf(w, "  %s→%s %s %s(%s dry)%s%s\n", colorYellow, colorReset, label, colorYellow, humanize.Bytes(t.Size), colorReset, note)
+	case len(t.Items) > 0:
+		fmt.Fprintf(w, "  %s✓%s %s %s(%s)%s%s\n", colorGreen, colorReset, label, colorGreen, humanize.Bytes(t.Size), colorReset, note)
+	case t.Ran && dryRun:
+		if note == "" {
+			note = " · would clean"
+		}
+		fmt.Fprintf(w, "  %s→%s %s%s\n", colorYellow, colorReset, label, note)
+	case t.Ran:
+		fmt.Fprintf(w, "  %s✓%s %s%s\n", colorGreen, colorReset, label, note)
+	default:
+		fmt.Fprintf(w, "  %s● %s%s%s\n", colorGray, label, note, colorReset)
+	}
+}
+
+// cleanedTargets counts the targets that removed, or would remove, something: the
+// "Categories" of the summary.
+func cleanedTargets(result clean.Result) int {
+	count := 0
+	for _, category := range result.Categories {
+		for _, t := range category.Targets {
+			if len(t.Items) > 0 {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+func gigabytes(size int64) string {
+	return fmt.Sprintf("%.2fGB", float64(size)/gigabyte)
+}
+
+// diskCheck is what df says about the run.
+type diskCheck struct {
+	Free    string   // Free space now, as df -h prints it
+	FreedKB int64    // Measured change in free space; negative when unknown
+	Gap     []string // Why the measured change differs from the plan
+}
+
+// summary builds the heading and lines of the closing block.
+func summary(result clean.Result, disk diskCheck) (string, []string) {
+	heading := "Cleanup complete"
+	if result.DryRun {
+		heading = "Dry run complete - no changes made"
+	}
+	if result.Size <= 0 {
+		var details []string
+		if result.DryRun {
+			details = append(details, "No significant reclaimable space detected (system already clean).")
+		} else {
+			details = append(details, "System was already clean; no additional space freed.")
+		}
+		return heading, append(details, "Free space now: "+disk.Free)
+	}
+
+	size := colorGreen + gigabytes(result.Size) + colorReset
+	categories := cleanedTargets(result)
+	if result.DryRun {
+		return heading, []string{
+			fmt.Sprintf("Potential space: %s | Items: %d | Categories: %d", size, result.Items, categories),
+			fmt.Sprintf("Detailed file list: %s~/.config/mole/%s%s", colorGray, cleanListFile, colorReset),
+			fmt.Sprintf("Use %smo clean --whitelist%s to add protection rules", colorGray, colorReset),
+		}
+	}
+	details := []string{fmt.Sprintf("Space freed: %s | Items cleaned: %d | Categories: %d", size, result.Items, categories)}
+	if movies := int(float64(result.Size)/gigabyte/movieSizeGB + 0.5); result.Size >= gigabyte && movies > 0 {
+		details = append(details, fmt.Sprintf("Equivalent to ~%d 4K movies of storage.", movies))
+	}
+	details = append(details, "Free space now: "+disk.Free)
+	if disk.FreedKB >= 0 {
+		details = append(details, fmt.Sprintf("Verified: %s freed on disk (expected %s)", gigabytes(disk.FreedKB*1024), gigabytes(result.Size)))
+		for _, reason := range disk.Gap {
+			details = append(details, fmt.Sprintf("%s• %s%s", colorGray, reason, colorReset))
+		}
+	}
+	return heading, details
+}
+
+// printSummary prints the closing block between dividers, and where the session log is
+// when MO_DEBUG is on.
+func printSummary(w io.Writer, home, heading string, details []string) {
+	fmt.Fprintf(w, "\n%s\n", summaryDivider)
+	if heading != "" {
+		fmt.Fprintf(w, "%s%s%s\n", colorBlue, heading, colorReset)
+	}
+	for _, detail := range details {
+		if detail != "" {
+			fmt.Fprintln(w, detail)
+		}
+	}
+	fmt.Fprintln(w, summaryDivider)
+	if os.Getenv("MO_DEBUG") == "1" {
+		fmt.Fprintf(w, "%sDebug session log saved to:%s %s\n", colorGray, colorReset, filepath.Join(home, debugLogFile))
+	}
+}
+
+// cleanupGap explains why the disk freed a different amount than planned, one reason
+// per line. Within 5% counts as matching the plan.
+func cleanupGap(expectedKB, freedKB int64, snapshots func() int) []string {
+	gap := expectedKB - freedKB
+	if max(gap, -gap) <= expectedKB/20 {
+		return nil
+	}
+	if gap < 0 {
+		return []string{"Other activity freed extra space at the same time"}
+	}
+	var reasons []string
+	if count := snapshots(); count > 0 {
+		reasons = append(reasons, fmt.Sprintf("%d local snapshots still hold deleted data until macOS thins them", count))
+	}
+	return append(reasons, "Apps recreated some caches or APFS is releasing space lazily")
+}
+
+// localSnapshotCount counts the local Time Machine snapshots of the startup disk.
+func localSnapshotCount() int {
+	return strings.Count(commandOutput("tmutil", "listlocalsnapshots", "/"), "com.apple")
+}
+
+// writeCleanList writes the dry-run preview to clean-list.txt, grouping the paths that
+// share a folder.
+func writeCleanList(path string, result clean.Result, now time.Time) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Mole Cleanup Preview - %s\n", now.Format("2006-01-02 15:04:05"))
+	b.WriteString("#\n# How to protect files:\n# 1. Copy any path below to ~/.config/mole/whitelist\n# 2. Run: mo clean --whitelist\n#\n")
+	b.WriteString("# Example:\n#   /Users/*/Library/Caches/com.example.app\n#\n\n")
+	for _, category := range result.Categories {
+		fmt.Fprintf(&b, "\n=== %s ===\n", category.Name)
+		for _, t := range category.Targets {
+			writeGroupedItems(&b, t.Items)
+		}
+	}
+	if result.Size > 0 {
+		b.WriteString("\n# ============================================\n# Summary\n# ============================================\n")
+		fmt.Fprintf(&b, "# Potential cleanup: %s\n# Items: %d\n# Categories: %d\n", gigabytes(result.Size), result.Items, cleanedTargets(result))
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(b.String()), 0644)
+}
+
+// writeGroupedItems lists items by folder: a folder holding several of them is one
+// line with their total size and count.
+func writeGroupedItems(b *strings.Builder, items []clean.Item) {
+	type group struct {
+		first string
+		size  int64
+		count int
+	}
+	groups := make(map[string]*group)
+	var parents []string
+	for _, item := range items {
+		parent := filepath.Dir(item.Path)
+		g, ok := groups[parent]
+		if !ok {
+			g = &group{first: item.Path}
+			groups[parent] = g
+			parents = append(parents, parent)
+		}
+		g.size += item.Size
+		g.count++
+	}
+	sort.Strings(parents)
+	for _, parent := range parents {
+		g := groups[parent]
+		if g.count > 1 {
+			fmt.Fprintf(b, "%s  # %s (%d items)\n", parent, humanize.Bytes(g.size), g.count)
+		} else {
+			fmt.Fprintf(b, "%s  # %s\n", g.first, humanize.Bytes(g.size))
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/x/term"
+)
+
+const (
+	// sudoKeepaliveDelay lets the sudo timestamp settle after Touch ID or a password
+	// before the first refresh, so the refresh does not bring Touch ID back.
+	sudoKeepaliveDelay    = 2 * time.Second
+	sudoKeepaliveInterval = 30 * time.Second
+	sudoRetryInterval     = 5 * time.Second
+	sudoMaxFailures       = 3
+
+	// touchIDWait is how long the Touch ID dialog gets before the password prompt.
+	touchIDWait = 5 * time.Second
+	// touchIDSettle lets macOS close the Touch ID dialog, which a password check would
+	// otherwise bring back.
+	touchIDSettle    = time.Second
+	passwordAttempts = 3
+	sudoPAMConfig    = "/etc/pam.d/sudo"
+)
+
+// requestSudo asks for admin access, with Touch ID where it is set up and the lid is
+// open, then keeps the sudo timestamp fresh for the rest of the run.
+func requestSudo(prompt string) bool {
+	if !hasSudoSession() && !authenticate(prompt) {
+		return false
+	}
+	go keepSudoAlive()
+	return true
+}
+
+func hasSudoSession() bool {
+	return exec.Command("sudo", "-n", "true").Run() == nil
+}
+
+// authenticate prompts on the terminal itself, so it works with stdin redirected.
+func authenticate(prompt string) bool {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s☻%s No interactive terminal available\n", colorRed, colorReset)
+		return false
+	}
+	defer tty.Close()
+	_ = exec.Command("sudo", "-k").Run()
+
+	if !touchIDEnabled() || lidClosed() {
+		fmt.Printf("%s➤%s %s\n", colorPurple, colorReset, prompt)
+		return requestPassword(tty, false)
+	}
+
+	fmt.Printf("%s➤%s %s %s(Touch ID or password)%s\n", colorPurple, colorReset, prompt, colorGray, colorReset)
+	ctx, cancel := context.WithTimeout(context.Background(), touchIDWait)
+	err = exec.CommandContext(ctx, "sudo", "-v").Run()
+	cancel()
+	if err == nil && hasSudoSession() {
+		clearLines(tty, 1)
+		return true
+	}
+	// Touch ID was cancelled or timed out; start the password prompt from a clean state.
+	_ = exec.Command("sudo", "-k").Run()
+	time.Sleep(touchIDSettle)
+	fmt.Fprint(tty, "\r\033[K")
+	return requestPassword(tty, true)
+}
+
+// requestPassword reads the password without echo and checks it with sudo -S, three
+// tries at most.
+func requestPassword(tty *os.File, touchID bool) bool {
+	_ = exec.Command("sudo", "-k").Run()
+	for attempt := 1; attempt <= passwordAttempts; attempt++ {
+		if touchID && attempt == 1 {
+			fmt.Fprintf(tty, "%sNote: Touch ID dialog may appear once more - just cancel it%s\n", colorGray, colorReset)
+		}
+		fmt.Fprintf(tty, "%s➤%s Password: ", colorPurple, colorReset)
+		password, err := term.ReadPassword(tty.Fd())
+		fmt.Fprintln(tty)
+		if err != nil {
+			return false
+		}
+
+		problem := "Password cannot be empty"
+		if len(password) > 0 {
+			check := exec.Command("sudo", "-S", "-p", "", "-v")
+			check.Stdin = bytes.NewReader(append(password, '\n'))
+			if check.Run() == nil {
+				clearLines(tty, 3)
+				return true
+			}
+			problem = "Incorrect password, try again"
+		}
+		if attempt < passwordAttempts {
+			fmt.Fprintf(tty, "%s●%s %s\n", colorYellow, colorReset, problem)
+		}
+	}
+	return false
+}
+
+// keepSudoAlive refreshes the sudo timestamp until it fails three times in a row. It
+// ends with the process, so nothing is left running after mo clean exits.
+func keepSudoAlive() {
+	time.Sleep(sudoKeepaliveDelay)
+	failures := 0
+	for {
+		if exec.Command("sudo", "-n", "-v").Run() != nil {
+			failures++
+			if failures >= sudoMaxFailures {
+				return
+			}
+			time.Sleep(sudoRetryInterval)
+			continue
+		}
+		failures = 0
+		time.Sleep(sudoKeepaliveInterval)
+	}
+}
+
+// touchIDEnabled reports whether sudo accepts Touch ID (pam_tid in its PAM config).
+func touchIDEnabled() bool {
+	data, err := os.ReadFile(sudoPAMConfig)
+	return err == nil && bytes.Contains(data, []byte("pam_tid.so"))
+}
+
+// lidClosed reports clamshell mode, where the Touch ID sensor cannot be reached.
+func lidClosed() bool {
+	output, err := exec.Command("ioreg", "-r", "-k", "AppleClamshellState", "-d", "4").Output()
+	return err == nil && strings.Contains(string(output), `"AppleClamshellState" = Yes`)
+}
+
+// clearLines removes the last n lines of the prompt from an ANSI terminal.
+func clearLines(tty *os.File, n int) {
+	if !ansiTerminal() {
+		return
+	}
+	for range n {
+		fmt.Fprint(tty, "\033[1A\r\033[K")
+	}
+}
+
+func ansiTerminal() bool {
+	t := os.Getenv("TERM")
+	return t != "" && t != "dumb" && t != "unknown" && isTerminal(os.Stdout)
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/tw93/mole/internal/clean"
+)
+
+const (
+	// menuReservedLines are the title, blank and footer lines around the list.
+	menuReservedLines = 6
+	menuMaxRows       = 50
+	defaultTermHeight = 24
+)
+
+var errCanceled = errors.New("canceled")
+
+// manageWhitelist lets the user tick the caches to protect, keeps the lines they wrote
+// by hand, and saves the result.
+func manageWhitelist(home string) error {
+	current := clean.ReadWhitelistLines(home)
+	var custom []string
+	for _, line := range current {
+		offered := false
+		for _, choice := range clean.WhitelistChoices {
+			if clean.SamePattern(line, choice.Pattern, home) {
+				offered = true
+				break
+			}
+		}
+		if !offered {
+			custom = append(custom, line)
+		}
+	}
+
+	// Protected caches come first, already ticked.
+	var protected, rest []clean.WhitelistChoice
+	for _, choice := range clean.WhitelistChoices {
+		if containsPattern(current, choice.Pattern, home) {
+			protected = append(protected, choice)
+		} else {
+			rest = append(rest, choice)
+		}
+	}
+	choices := append(protected, rest...)
+	menu := newMultiSelect(len(choices), len(protected))
+	for i, choice := range choices {
+		menu.labels[i] = choice.Label
+	}
+	menu.title = fmt.Sprintf("Whitelist Manager – Select caches to protect\n%sEdit: %s%s", colorGray, displayPath(clean.WhitelistFile(home), home), colorReset)
+
+	selected, err := menu.run(os.Stderr, readKey)
+	if err != nil {
+		return err
+	}
+	var patterns []string
+	for _, i := range selected {
+		patterns = append(patterns, choices[i].Pattern)
+	}
+	if err := clean.SaveWhitelist(home, append(patterns, custom...)); err != nil {
+		return err
+	}
+
+	details := []string{fmt.Sprintf("Protected %d cache(s)", len(patterns))}
+	if len(custom) > 0 {
+		details[0] = fmt.Sprintf("Protected %d predefined + %d custom patterns", len(patterns), len(custom))
+	}
+	details = append(details, fmt.Sprintf("Config: %s%s%s", colorGray, displayPath(clean.WhitelistFile(home), home), colorReset))
+	printSummary(os.Stdout, home, "Whitelist Updated", details)
+	fmt.Println()
+	return nil
+}
+
+func containsPattern(patterns []string, pattern, home string) bool {
+	for _, existing := range patterns {
+		if clean.SamePattern(existing, pattern, home) {
+			return true
+		}
+	}
+	return false
+}
+
+func displayPath(path, home string) string {
+	if rest, ok := strings.CutPrefix(path, home); ok {
+		return "~" + rest
+	}
+	return path
+}
+
+// multiSelect is a checkbox list that scrolls a page at a time: arrows or j/k move,
+// Space ticks, Enter accepts and q or Esc cancels.
+type multiSelect struct {
+	title    string
+	labels   []string
+	selected []bool
+	cursor   int // Row on the page
+	top      int // First item on the page
+	rows     func() int
+}
+
+// newMultiSelect starts with the first ticked items selected.
+func newMultiSelect(count, ticked int) *multiSelect {
+	m := &multiSelect{labels: make([]string, count), selected: make([]bool, count), rows: pageRows}
+	for i := range ticked {
+		m.selected[i] = true
+	}
+	return m
+}
+
+// pageRows is how many items fit under the title, from the terminal height.
+func pageRows() int {
+	height := defaultTermHeight
+	if _, h, err := term.GetSize(os.Stderr.Fd()); err == nil && h > 0 {
+		height = h
+	}
+	return min(max(height-menuReservedLines, 1), menuMaxRows)
+}
+
+// run draws the menu on the alternate screen until Enter or a cancel, and returns the
+// ticked items in order.
+func (m *multiSelect) run(w io.Writer, next func() key) ([]int, error) {
+	fmt.Fprint(w, "\033[?1049h\033[2J\033[H\033[?25l")
+	defer fmt.Fprint(w, "\033[?25h\033[?1049l")
+	for {
+		m.draw(w)
+		switch next() {
+		case keyQuit:
+			return nil, errCanceled
+		case keyUp:
+			m.up()
+		case keyDown:
+			m.down()
+		case keySpace:
+			if i := m.top + m.cursor; i < len(m.labels) {
+				m.selected[i] = !m.selected[i]
+			}
+		case keyEnter:
+			var ticked []int
+			for i, on := range m.selected {
+				if on {
+					ticked = append(ticked, i)
+				}
+			}
+			return ticked, nil
+		}
+	}
+}
+
+func (m *multiSelect) up() {
+	if m.cursor > 0 {
+		m.cursor--
+	} else if m.top > 0 {
+		m.top--
+	}
+}
+
+func (m *multiSelect) down() {
+	if m.top+m.cursor >= len(m.labels)-1 {
+		return
+	}
+	if m.cursor < m.rows()-1 {
+		m.cursor++
+	} else {
+		m.top++
+	}
+}
+
+func (m *multiSelect) draw(w io.Writer) {
+	const clear = "\r\033[2K"
+	count := 0
+	for _, on := range m.selected {
+		if on {
+			count++
+		}
+	}
+	fmt.Fprintf(w, "\033[H%s%s%s%s  %s%d/%d selected%s\n%s\n", clear, colorPurpleBold, m.title, colorReset, colorGray, count, len(m.labels), colorReset, clear)
+
+	rows := m.rows()
+	for row := range rows {
+		i := m.top + row
+		if i >= len(m.labels) {
+			fmt.Fprintf(w, "%s\n", clear)
+			continue
+		}
+		box := "○"
+		if m.selected[i] {
+			box = "●"
+		}
+		if row == m.cursor {
+			fmt.Fprintf(w, "%s%s➤ %s %s%s\n", clear, colorCyan, box, m.labels[i], colorReset)
+		} else {
+			fmt.Fprintf(w, "%s  %s %s\n", clear, box, m.labels[i])
+		}
+	}
+	fmt.Fprintf(w, "%s\n%s%s↑↓ | Space | Enter | Q Exit%s\n%s", clear, clear, colorGray, colorReset, clear)
+}
@@ -383,6 +383,9 @@ build_binary_from_source() {
         status)
             cmd_dir="cmd/status"
             ;;
+        clean)
+            cmd_dir="cmd/clean"
+            ;;
         *)
             return 1
             ;;
@@ -565,6 +568,9 @@ install_files() {
     if ! download_binary "status"; then
         exit 1
     fi
+    if ! download_binary "clean"; then
+        exit 1
+    fi
 }
 
 # Verification and PATH hint
@@ -0,0 +1,126 @@
+package clean
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// brewCleanupInterval is how long a Homebrew cleanup counts as recent enough.
+	brewCleanupInterval = 7 * 24 * time.Hour
+	// brewCacheThreshold is the Homebrew download cache size below which `brew
+	// cleanup` is not worth its time; autoremove still runs.
+	brewCacheThreshold = 50 << 20
+	// brewTimeout bounds `brew cleanup` and `brew autoremove` each.
+	brewTimeout = 120 * time.Second
+)
+
+var (
+	brewStampFile   = filepath.Join(".cache", "mole", "brew_last_cleanup")
+	brewCacheFolder = filepath.Join("Library", "Caches", "Homebrew")
+	brewFreedLine   = regexp.MustCompile(`[0-9.]+[KMGT]B freed`)
+)
+
+// homebrewTarget runs `brew cleanup` and `brew autoremove` side by side, at most once
+// a week. What they did ends up in the note.
+func homebrewTarget() Target {
+	return Target{Label: "Homebrew", Run: func(opts Options) TargetResult {
+		if !opts.Tools.has("brew") {
+			return TargetResult{}
+		}
+		if opts.DryRun {
+			return TargetResult{Ran: true, Note: "would cleanup and autoremove"}
+		}
+		stamp := filepath.Join(opts.Home, brewStampFile)
+		if last, ok := readBrewStamp(stamp); ok {
+			if age := time.Since(last); age < brewCleanupInterval {
+				return TargetResult{Ran: true, Note: fmt.Sprintf("cleaned %dd ago, skipped", int(age.Hours()/24))}
+			}
+		}
+
+		cacheSize := int64(-1)
+		if _, err := os.Stat(filepath.Join(opts.Home, brewCacheFolder)); err == nil {
+			cacheSize = measureWith(opts, filepath.Join(opts.Home, brewCacheFolder))
+		}
+		skipCleanup := cacheSize >= 0 && cacheSize < brewCacheThreshold
+
+		var cleanupOut, autoremoveOut []byte
+		var cleanupErr, autoremoveErr error
+		var wg sync.WaitGroup
+		if !skipCleanup {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				cleanupOut, cleanupErr = opts.Tools.run(brewTimeout, "brew", "cleanup")
+			}()
+		}
+		autoremoveOut, autoremoveErr = opts.Tools.run(brewTimeout, "brew", "autoremove")
+		wg.Wait()
+
+		var notes []string
+		switch {
+		case skipCleanup:
+			notes = append(notes, fmt.Sprintf("cleanup skipped, cache %dMB", cacheSize>>20))
+		case cleanupErr == nil:
+			if freed := brewFreedLine.FindAll(cleanupOut, -1); len(freed) > 0 {
+				notes = append(notes, "cleanup "+string(freed[len(freed)-1]))
+			} else if removed := strings.Count(string(cleanupOut), "Removing:"); removed > 0 {
+				notes = append(notes, fmt.Sprintf("cleanup removed %d items", removed))
+			}
+		case errors.Is(cleanupErr, errTimedOut):
+			notes = append(notes, "cleanup timed out, run brew cleanup by hand")
+		}
+		switch {
+		case autoremoveErr == nil:
+			if removed := countLinePrefix(autoremoveOut, "Uninstalling"); removed > 0 {
+				notes = append(notes, fmt.Sprintf("removed %d orphaned dependencies", removed))
+			}
+		case errors.Is(autoremoveErr, errTimedOut):
+			notes = append(notes, "autoremove timed out, run brew autoremove by hand")
+		}
+
+		ran := skipCleanup || cleanupErr == nil || autoremoveErr == nil
+		if ran {
+			writeBrewStamp(stamp)
+		}
+		return TargetResult{Ran: ran, Note: strings.Join(notes, ", ")}
+	}}
+}
+
+func readBrewStamp(path string) (time.Time, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return time.Time{}, false
+	}
+	seconds, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(seconds, 0), true
+}
+
+// writeBrewStamp records the cleanup in epoch seconds, the format the shell cleaner
+// wrote, so the weekly window carries over.
+func writeBrewStamp(path string) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return
+	}
+	_ = os.WriteFile(path, []byte(strconv.FormatInt(time.Now().Unix(), 10)+"\n"), 0644)
+}
+
+func countLinePrefix(output []byte, prefix string) int {
+	count := 0
+	for _, line := range strings.Split(string(output), "\n") {
+		if strings.HasPrefix(line, prefix) {
+			count++
+		}
+	}
+	return count
+}
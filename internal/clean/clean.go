@@ -0,0 +1,399 @@
+// Package clean finds and removes the caches, logs and leftovers mo clean handles. It
+// is shared by cmd/clean and mo analyze.
+package clean
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"unicode"
+)
+
+// measureWorkers bounds how many paths are sized at once.
+const measureWorkers = 8
+
+// Options controls a run.
+type Options struct {
+	Home       string
+	DryRun     bool // Measure only; the result is an estimate
+	Whitelist  Whitelist
+	Protection Protection
+	// Progress, when set, is called before each target is cleaned.
+	Progress func(Progress)
+	// Remove deletes one path; nil means os.RemoveAll.
+	Remove func(path string) error
+	// Measure sizes one path; nil counts allocated blocks the way du does.
+	Measure func(path string) int64
+	// Sudo says admin access was granted: system categories run and Sudo targets are
+	// removed as root.
+	Sudo bool
+	// Tools runs the programs some targets drive; the zero value runs the real ones.
+	Tools Tools
+	// Confirm asks before a step a dry run cannot preview, such as deleting local
+	// snapshots; nil declines.
+	Confirm func(question string) bool
+	// Report, when set, is called with each category once it is done, even an empty one.
+	Report func(CategoryResult)
+}
+
+// Progress says which target a run is on.
+type Progress struct {
+	Category string `json:"category"`
+	Target   string `json:"target"`
+	Done     int    `json:"done"` // Targets finished before this one
+	Total    int    `json:"total"`
+}
+
+// Item is one removed, or in a dry run removable, path.
+type Item struct {
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+}
+
+// TargetResult is what one target cleaned.
+type TargetResult struct {
+	Label   string   `json:"label"`
+	Items   []Item   `json:"items"`
+	Size    int64    `json:"size"`
+	Skipped int      `json:"skipped,omitempty"` // Protected or whitelisted matches
+	Failed  []string `json:"failed,omitempty"`  // Paths that could not be removed
+	Ran     bool     `json:"ran,omitempty"`     // A tool cleaned, or in a dry run would clean, its own cache
+	Note    string   `json:"note,omitempty"`    // Why the target was left alone, or what to do by hand
+}
+
+// CategoryResult is what one category cleaned. Targets with nothing to clean or say are
+// left out.
+type CategoryResult struct {
+	Name    string         `json:"name"`
+	Targets []TargetResult `json:"targets"`
+	Size    int64          `json:"size"`
+}
+
+// Result is a whole run. In a dry run the sizes are what would be freed.
+type Result struct {
+	DryRun     bool             `json:"dry_run"`
+	Categories []CategoryResult `json:"categories"`
+	Size       int64            `json:"size"`
+	Items      int              `json:"items"`
+	Skipped    int              `json:"skipped"`
+	Failed     int              `json:"failed"`
+}
+
+// Select returns the categories with the given names, in their usual order.
+func Select(categories []Category, names []string) []Category {
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+	var selected []Category
+	for _, category := range categories {
+		if wanted[category.Name] {
+			selected = append(selected, category)
+		}
+	}
+	return selected
+}
+
+// Paths lists every path in the result.
+func (r Result) Paths() []string {
+	var paths []string
+	for _, category := range r.Categories {
+		for _, t := range category.Targets {
+			for _, item := range t.Items {
+				paths = append(paths, item.Path)
+			}
+		}
+	}
+	return paths
+}
+
+// Run cleans every target of categories in order, or only measures them in a dry run.
+// System categories are skipped unless the run has admin access.
+func Run(categories []Category, opts Options) Result {
+	result := Result{DryRun: opts.DryRun}
+	total := 0
+	for _, category := range categories {
+		if !category.System || opts.Sudo {
+			total += len(category.Targets)
+		}
+	}
+	done := 0
+	for _, category := range categories {
+		if category.System && !opts.Sudo {
+			continue
+		}
+		categoryResult := CategoryResult{Name: category.Name}
+		for _, t := range category.Targets {
+			if opts.Progress != nil {
+				opts.Progress(Progress{Category: category.Name, Target: t.Label, Done: done, Total: total})
+			}
+			done++
+			targetResult := cleanTarget(t, opts)
+			result.Skipped += targetResult.Skipped
+			result.Failed += len(targetResult.Failed)
+			if len(targetResult.Items) == 0 && !targetResult.Ran && targetResult.Note == "" {
+				continue
+			}
+			categoryResult.Targets = append(categoryResult.Targets, targetResult)
+			categoryResult.Size += targetResult.Size
+			result.Items += len(targetResult.Items)
+		}
+		if opts.Report != nil {
+			opts.Report(categoryResult)
+		}
+		if len(categoryResult.Targets) > 0 {
+			result.Categories = append(result.Categories, categoryResult)
+			result.Size += categoryResult.Size
+		}
+	}
+	return result
+}
+
+// cleanTarget expands t, drops protected and whitelisted matches, sizes the rest and
+// removes them unless this is a dry run. Targets with their own Run do all of that
+// themselves.
+func cleanTarget(t Target, opts Options) TargetResult {
+	if t.Skip != nil {
+		if skip, note := t.Skip(opts); skip {
+			return TargetResult{Label: t.Label, Note: note}
+		}
+	}
+	if t.Run != nil {
+		result := t.Run(opts)
+		result.Label = t.Label
+		return result
+	}
+	result := TargetResult{Label: t.Label}
+	matches := expandTarget(t, opts.Home)
+	if t.Find != nil {
+		matches = append(matches, t.Find(opts)...)
+	}
+	var paths []string
+	for _, match := range matches {
+		if opts.Protection.Protects(match) || opts.Whitelist.Match(match) {
+			result.Skipped++
+			continue
+		}
+		paths = append(paths, match)
+	}
+	paths = normalizePaths(paths)
+	measure := opts.Measure
+	if measure == nil {
+		measure = diskUsage
+	}
+	sizes := measurePaths(paths, measure)
+
+	remove := t.remover(opts)
+	for i, path := range paths {
+		if !opts.DryRun {
+			if !removable(path, opts.Home, t.Outside) || remove(path) != nil {
+				result.Failed = append(result.Failed, path)
+				continue
+			}
+		}
+		result.Items = append(result.Items, Item{Path: path, Size: sizes[i]})
+		result.Size += sizes[i]
+	}
+	return result
+}
+
+// remover picks how t deletes a path: the run's Remove hook, sudo rm for Sudo targets
+// in a run with admin access, or os.RemoveAll.
+func (t Target) remover(opts Options) func(string) error {
+	switch {
+	case opts.Remove != nil:
+		return opts.Remove
+	case t.Sudo && opts.Sudo:
+		return func(path string) error { return sudoRemove(opts.Tools, path) }
+	default:
+		return os.RemoveAll
+	}
+}
+
+var errSymlink = errors.New("refusing to remove a symlink as root")
+
+// sudoRemove deletes path as root like safe_sudo_remove, refusing symlinks.
+func sudoRemove(tools Tools, path string) error {
+	info, err := os.Lstat(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return nil
+	case err == nil && info.Mode()&fs.ModeSymlink != 0:
+		return errSymlink
+	case err != nil:
+		// Root-only folders hide the entry from us; ask as root instead.
+		if _, err := tools.run(sudoTimeout, "sudo", "-n", "test", "-L", path); err == nil {
+			return errSymlink
+		}
+	}
+	_, err = tools.run(sudoTimeout, "sudo", "-n", "rm", "-rf", "--", path)
+	return err
+}
+
+// expandTarget globs each pattern, under home unless it is absolute. Like bash without
+// dotglob, a wildcard does not match names starting with a dot.
+func expandTarget(t Target, home string) []string {
+	var paths []string
+	for _, pattern := range t.Patterns {
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(home, pattern)
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			continue
+		}
+		for _, match := range matches {
+			if !hiddenWildcardMatch(pattern, match) {
+				paths = append(paths, match)
+			}
+		}
+	}
+	return paths
+}
+
+// hiddenWildcardMatch reports whether a wildcard element of pattern matched a dot name.
+func hiddenWildcardMatch(pattern, match string) bool {
+	patternParts := strings.Split(pattern, "/")
+	matchParts := strings.Split(match, "/")
+	for i, part := range patternParts {
+		if i < len(matchParts) && !strings.HasPrefix(part, ".") && strings.ContainsAny(part, "*?[") && strings.HasPrefix(matchParts[i], ".") {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizePaths drops duplicates and paths inside another listed path, shortest
+// first, as normalize_paths_for_cleanup does.
+func normalizePaths(paths []string) []string {
+	seen := make(map[string]bool)
+	var unique []string
+	for _, path := range paths {
+		if trimmed := strings.TrimSuffix(path, "/"); trimmed != "" {
+			path = trimmed
+		}
+		if !seen[path] {
+			seen[path] = true
+			unique = append(unique, path)
+		}
+	}
+	sort.SliceStable(unique, func(a, b int) bool {
+		if len(unique[a]) != len(unique[b]) {
+			return len(unique[a]) < len(unique[b])
+		}
+		return unique[a] < unique[b]
+	})
+	var kept []string
+	for _, path := range unique {
+		inside := false
+		for _, parent := range kept {
+			if strings.HasPrefix(path, parent+"/") {
+				inside = true
+				break
+			}
+		}
+		if !inside {
+			kept = append(kept, path)
+		}
+	}
+	return kept
+}
+
+// measureWith sizes path with the run's Measure hook, or the way du does.
+func measureWith(opts Options, path string) int64 {
+	if opts.Measure != nil {
+		return opts.Measure(path)
+	}
+	return diskUsage(path)
+}
+
+// measurePaths sizes paths in parallel, in the order given.
+func measurePaths(paths []string, measure func(string) int64) []int64 {
+	sizes := make([]int64, len(paths))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, measureWorkers)
+	for i, path := range paths {
+		wg.Add(1)
+		go func(i int, path string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			sizes[i] = measure(path)
+			<-sem
+		}(i, path)
+	}
+	wg.Wait()
+	return sizes
+}
+
+// diskUsage is the space path takes on disk, like du: allocated blocks, not following
+// symlinks, each hard-linked file counted once.
+func diskUsage(path string) int64 {
+	var total int64
+	seen := make(map[uint64]bool)
+	_ = filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+			if stat.Nlink > 1 && !info.IsDir() {
+				if seen[uint64(stat.Ino)] {
+					return nil
+				}
+				seen[uint64(stat.Ino)] = true
+			}
+			total += int64(stat.Blocks) * 512
+			return nil
+		}
+		total += info.Size()
+		return nil
+	})
+	return total
+}
+
+// removable is the last check before deleting: a clean absolute path below home, or
+// for targets allowed outside it, one deletablePath accepts.
+func removable(path, home string, outside bool) bool {
+	if !filepath.IsAbs(path) || filepath.Clean(path) != path {
+		return false
+	}
+	if home != "" && home != "/" && strings.HasPrefix(path, filepath.Clean(home)+"/") {
+		return true
+	}
+	return outside && deletablePath(path)
+}
+
+// criticalRoots are never deleted, after validate_path_for_deletion in
+// lib/core/file_ops.sh. Everything under /System is off limits too, except the
+// rebuildable symbolication cache.
+var criticalRoots = []string{"/", "/bin", "/sbin", "/usr", "/usr/bin", "/usr/sbin", "/etc", "/var", "/System", "/Library/Extensions"}
+
+const symbolicationCache = "/System/Library/Caches/com.apple.coresymbolicationd/data"
+
+func deletablePath(path string) bool {
+	if !filepath.IsAbs(path) || strings.Contains(path, "..") {
+		return false
+	}
+	for _, r := range path {
+		if unicode.IsControl(r) {
+			return false
+		}
+	}
+	if path == symbolicationCache || strings.HasPrefix(path, symbolicationCache+"/") {
+		return true
+	}
+	for _, root := range criticalRoots {
+		if path == root {
+			return false
+		}
+	}
+	return !strings.HasPrefix(path, "/System/")
+}
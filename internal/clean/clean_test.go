@@ -0,0 +1,245 @@
+package clean
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testProtectionScript = `
+readonly SYSTEM_CRITICAL_BUNDLES=(
+    "com.apple.*" # System essentials
+    "loginwindow"
+)
+
+readonly DATA_PROTECTED_BUNDLES=(
+    # Password managers
+    "com.1password.*"
+    "*Claude*"
+)
+`
+
+func testProtection(t *testing.T) Protection {
+	t.Helper()
+	p, err := ParseProtection(strings.NewReader(testProtectionScript))
+	if err != nil {
+		t.Fatalf("ParseProtection: %v", err)
+	}
+	return p
+}
+
+func writeFile(t *testing.T, path string, size int) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseWhitelist(t *testing.T) {
+	input := strings.Join([]string{
+		"# comment",
+		"  ~/Library/Caches/keep*  ",
+		"~/Library/Caches/keep*",
+		"/Users/me/../etc",
+		"relative/path",
+		"/Users/me//double",
+		"/System/Library",
+		"/tmp/$(rm)",
+		"FINDER_METADATA",
+	}, "\n")
+	w := ParseWhitelist(strings.NewReader(input), "/Users/me")
+	if len(w.Patterns) != 2 || w.Patterns[0] != "/Users/me/Library/Caches/keep*" {
+		t.Fatalf("patterns = %q", w.Patterns)
+	}
+	if !w.FinderMetadata {
+		t.Error("FINDER_METADATA not recognised")
+	}
+	if len(w.Warnings) != 5 {
+		t.Errorf("warnings = %q, want 5", w.Warnings)
+	}
+}
+
+func TestSaveAndReadWhitelistLines(t *testing.T) {
+	home := t.TempDir()
+	if got := ReadWhitelistLines(home); len(got) != len(defaultWhitelist) {
+		t.Fatalf("without a file = %q, want the defaults", got)
+	}
+	if err := SaveWhitelist(home, []string{"~/.cache/foo", home + "/.cache/foo", "~/.cache/bar", "relative/kept"}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(WhitelistFile(home))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "# Mole Whitelist") || strings.Count(string(data), ".cache/foo") != 1 {
+		t.Errorf("saved whitelist:\n%s", data)
+	}
+	if got := ReadWhitelistLines(home); strings.Join(got, ",") != "~/.cache/foo,~/.cache/bar,relative/kept" {
+		t.Errorf("read back = %q", got)
+	}
+	if !SamePattern("~/.cache/x", home+"/.cache/x", home) || SamePattern("~/.cache/x", "~/.cache/*", home) {
+		t.Error("SamePattern must expand ~ and compare globs as text")
+	}
+}
+
+func TestWhitelistMatchesLikeBash(t *testing.T) {
+	w := newWhitelist([]string{"/Users/me/Library/Caches/JetBrains*", "/Users/me/.m2/repository/*", "/Users/me/exact/"}, nil)
+	cases := map[string]bool{
+		"/Users/me/Library/Caches/JetBrains":           true,
+		"/Users/me/Library/Caches/JetBrains/IDEA/idx":  true, // * crosses "/"
+		"/Users/me/.m2/repository/org/apache":          true,
+		"/Users/me/.m2/repository":                     false,
+		"/Users/me/exact":                              true,
+		"/Users/me/exact/":                             true,
+		"/Users/me/Library/Caches/com.other.JetBrains": false,
+	}
+	for path, want := range cases {
+		if got := w.Match(path); got != want {
+			t.Errorf("Match(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestParseProtectionNeedsBothLists(t *testing.T) {
+	if _, err := ParseProtection(strings.NewReader("readonly SYSTEM_CRITICAL_BUNDLES=(\n    \"com.apple.*\"\n)\n")); err == nil {
+		t.Error("missing DATA_PROTECTED_BUNDLES accepted")
+	}
+}
+
+func TestProtects(t *testing.T) {
+	p := testProtection(t)
+	cases := map[string]bool{
+		"/Users/me/Library/Caches/com.apple.Safari":                     true, // Bundle name
+		"/Users/me/Library/Containers/com.1password.app/Data/cache":     true,
+		"/Users/me/Library/Caches/Claude/blob":                          true, // Pattern on the full path
+		"/Users/me/Library/Caches/com.apple.systempreferences.cache/db": true,
+		"/Users/me/Library/Mobile Documents/doc":                        true,
+		"/Users/me/Library/Preferences/ByHost/com.apple.wifi.x.plist":   true,
+		"/Users/me/Library/Caches/com.spotify.client":                   false,
+		"/Users/me/Library/Caches/Google/Chrome/Default":                false,
+	}
+	for path, want := range cases {
+		if got := p.Protects(path); got != want {
+			t.Errorf("Protects(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestNormalizePaths(t *testing.T) {
+	got := normalizePaths([]string{"/a/b/c", "/a/b/", "/a/bc", "/a/b", "/x"})
+	want := []string{"/x", "/a/b", "/a/bc"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("normalizePaths = %q, want %q", got, want)
+	}
+}
+
+func TestRunDryRunEstimatesWithoutRemoving(t *testing.T) {
+	home := t.TempDir()
+	writeFile(t, filepath.Join(home, "Library/Caches/com.spotify.client/data"), 8192)
+	writeFile(t, filepath.Join(home, "Library/Caches/com.apple.Safari/db"), 4096)
+	writeFile(t, filepath.Join(home, "Library/Caches/keep/data"), 4096)
+	writeFile(t, filepath.Join(home, "Library/Caches/.hidden"), 4096)
+	writeFile(t, filepath.Join(home, "Library/Logs/app.log"), 4096)
+
+	categories := []Category{{Name: "User essentials", Targets: []Target{
+		target("User app cache", "Library/Caches/*"),
+		target("User app logs", "Library/Logs/*"),
+		target("Trash", ".Trash/*"),
+	}}}
+	var progress []Progress
+	result := Run(categories, Options{
+		Home:       home,
+		DryRun:     true,
+		Whitelist:  newWhitelist([]string{filepath.Join(home, "Library/Caches/keep")}, nil),
+		Protection: testProtection(t),
+		Progress:   func(p Progress) { progress = append(progress, p) },
+		Remove:     func(string) error { t.Error("dry run removed a path"); return nil },
+	})
+
+	if len(progress) != 3 || progress[2].Done != 2 || progress[2].Total != 3 {
+		t.Errorf("progress = %+v", progress)
+	}
+	if len(result.Categories) != 1 || len(result.Categories[0].Targets) != 2 {
+		t.Fatalf("result = %+v", result)
+	}
+	caches := result.Categories[0].Targets[0]
+	if len(caches.Items) != 1 || filepath.Base(caches.Items[0].Path) != "com.spotify.client" {
+		t.Errorf("cache items = %+v, want only com.spotify.client", caches.Items)
+	}
+	if caches.Skipped != 2 {
+		t.Errorf("skipped = %d, want Safari and the whitelisted folder", caches.Skipped)
+	}
+	if caches.Size < 8192 || result.Size != caches.Size+result.Categories[0].Targets[1].Size {
+		t.Errorf("sizes = %d / %d", caches.Size, result.Size)
+	}
+	if _, err := os.Stat(filepath.Join(home, "Library/Caches/com.spotify.client")); err != nil {
+		t.Errorf("dry run touched the cache: %v", err)
+	}
+}
+
+func TestRunRemovesAndReportsFailures(t *testing.T) {
+	home := t.TempDir()
+	writeFile(t, filepath.Join(home, ".Trash/old.zip"), 4096)
+	writeFile(t, filepath.Join(home, ".Trash/locked.zip"), 4096)
+
+	result := Run([]Category{{Name: "User essentials", Targets: []Target{target("Trash", ".Trash/*")}}}, Options{
+		Home:       home,
+		Protection: testProtection(t),
+		Remove: func(path string) error {
+			if filepath.Base(path) == "locked.zip" {
+				return errors.New("operation not permitted")
+			}
+			return os.RemoveAll(path)
+		},
+	})
+
+	if result.Items != 1 || result.Failed != 1 {
+		t.Fatalf("items = %d, failed = %d", result.Items, result.Failed)
+	}
+	if _, err := os.Stat(filepath.Join(home, ".Trash/old.zip")); !os.IsNotExist(err) {
+		t.Error("old.zip was not removed")
+	}
+}
+
+func TestRemovableStaysInHome(t *testing.T) {
+	cases := map[string]bool{
+		"/Users/me/Library/Caches/x": true,
+		"/Users/me":                  false,
+		"/Users/meta/x":              false,
+		"/Users/me/a/../../x":        false,
+		"relative":                   false,
+	}
+	for path, want := range cases {
+		if got := removable(path, "/Users/me", false); got != want {
+			t.Errorf("removable(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestHandles(t *testing.T) {
+	if !Handles("/Users/test/Library/Caches/AppCache") || !Handles("/Users/test/.Trash/x") {
+		t.Error("cache and trash folders not handled")
+	}
+	if Handles("/Users/test/project/node_modules") {
+		t.Error("project folder handled")
+	}
+}
+
+func TestSelectAndPaths(t *testing.T) {
+	selected := Select(UserCategories, []string{"Browsers", "User essentials", "Unknown"})
+	if len(selected) != 2 || selected[0].Name != "User essentials" || selected[1].Name != "Browsers" {
+		t.Fatalf("Select = %+v", selected)
+	}
+	result := Result{Categories: []CategoryResult{{Targets: []TargetResult{
+		{Items: []Item{{Path: "/a"}, {Path: "/b"}}},
+		{Items: []Item{{Path: "/c"}}},
+	}}}}
+	if got := strings.Join(result.Paths(), ","); got != "/a,/b,/c" {
+		t.Errorf("Paths = %s", got)
+	}
+}
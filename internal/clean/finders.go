@@ -0,0 +1,566 @@
+package clean
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/tw93/mole/internal/humanize"
+)
+
+// Limits carried over from lib/core/base.sh and the cleanup modules.
+const (
+	day                = 24 * time.Hour
+	maxDSStoreFiles    = 500
+	homeDSStoreDepth   = 5
+	hollowDepth        = 3 // Levels of nested empty folders one run removes
+	mailAgeDays        = 30
+	mailDownloadsMinKB = 5120
+	spotifyOfflineSize = 500 << 20
+	iosBackupNoteSize  = 100 << 20
+	projectScanDepth   = 3
+	projectScanTimeout = 10 * time.Second
+	diskutilTimeout    = time.Second
+	writeOK            = 0x2 // W_OK for syscall.Access
+)
+
+var (
+	// applicationsDirs hold the apps whose bundled browser frameworks pile up; the
+	// second is relative to home.
+	applicationsDirs = []string{"/Applications", "Applications"}
+	// volumesDir is where external disks mount.
+	volumesDir = "/Volumes"
+	// brewLockDirs are Homebrew's lock folders for Apple Silicon and Intel installs.
+	brewLockDirs = []string{"/opt/homebrew/var/homebrew/locks", "/usr/local/var/homebrew/locks"}
+
+	dsStorePrunes      = []string{"/Library/Application Support/MobileSync", "/Library/Developer", "/.Trash", "/node_modules", "/.git", "/Library/Caches"}
+	networkProtocols   = []string{"SMB", "NFS", "AFP", "CIFS", "WebDAV"}
+	networkFilesystems = []string{"nfs", "smbfs", "afpfs", "cifs", "webdav"}
+	devFolders         = []string{"Code", "Projects", "workspace", "github", "dev", "work", "src", "repos", "Development", "www", "golang", "go", "rust", "python", "ruby", "java", "dotnet", "node"}
+	projectMarkers     = []string{"node_modules", ".git", "target", "go.mod", "Cargo.toml", "package.json", "pom.xml", "build.gradle"}
+	mailDownloadDirs   = []string{"Library/Mail Downloads", "Library/Containers/com.apple.mail/Data/Library/Mail Downloads"}
+	appSupportLogDirs  = []string{"log", "logs", "activitylog", "Cache/Cache_Data", "Crashpad/completed"}
+	// groupContainerLogs are the only group containers whose logs are cleaned.
+	groupContainerLogs = []string{"group.com.apple.contentdelivery"}
+	// fixedContainers have their own sandboxed cache targets.
+	fixedContainers = map[string]bool{"com.apple.wallpaper.agent": true, "com.apple.mediaanalysisd": true, "com.apple.AppStore": true}
+)
+
+// children lists the entries of dir as full paths.
+func children(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	paths := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		paths = append(paths, filepath.Join(dir, entry.Name()))
+	}
+	return paths
+}
+
+func isDir(path string) bool {
+	info, err := os.Lstat(path)
+	return err == nil && info.IsDir()
+}
+
+func pluralCount(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+func olderThan(path string, age time.Duration) bool {
+	info, err := os.Lstat(path)
+	return err == nil && time.Since(info.ModTime()) > age
+}
+
+// envNumber reads a numeric override such as MOLE_MAIL_AGE_DAYS.
+func envNumber(name string, fallback int) int {
+	if n, err := strconv.Atoi(os.Getenv(name)); err == nil && n >= 0 {
+		return n
+	}
+	return fallback
+}
+
+func skipWhitelisted(path string) func(Options) (bool, string) {
+	return func(opts Options) (bool, string) {
+		if opts.Whitelist.Match(filepath.Join(opts.Home, path)) {
+			return true, "whitelist protected"
+		}
+		return false, ""
+	}
+}
+
+func skipFinderMetadata(note string) func(Options) (bool, string) {
+	return func(opts Options) (bool, string) {
+		return opts.Whitelist.FinderMetadata, note
+	}
+}
+
+// emptyLibraryFolders finds folders directly in ~/Library with nothing inside.
+func emptyLibraryFolders(opts Options) []string {
+	var empty []string
+	for _, path := range children(filepath.Join(opts.Home, "Library")) {
+		if isDir(path) && len(children(path)) == 0 {
+			empty = append(empty, path)
+		}
+	}
+	return empty
+}
+
+// emptySubfolders finds folders below a Library folder holding nothing but empty
+// folders, at most hollowDepth levels deep. Whitelisted folders and system components
+// stay, and so do the folders around them.
+func emptySubfolders(folder string) func(Options) []string {
+	return func(opts Options) []string {
+		var found []string
+		var visit func(dir string) (int, bool)
+		visit = func(dir string) (int, bool) {
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				return 0, false
+			}
+			height, hollow := 1, true
+			for _, entry := range entries {
+				if !entry.IsDir() {
+					hollow = false
+					continue
+				}
+				childHeight, childHollow := visit(filepath.Join(dir, entry.Name()))
+				if !childHollow {
+					hollow = false
+					continue
+				}
+				height = max(height, childHeight+1)
+			}
+			if !hollow || height > hollowDepth || opts.Whitelist.Match(dir) || isCriticalSystemComponent(filepath.Base(dir)) {
+				return height, false
+			}
+			found = append(found, dir)
+			return height, true
+		}
+		for _, path := range children(filepath.Join(opts.Home, folder)) {
+			if isDir(path) {
+				visit(path)
+			}
+		}
+		return found
+	}
+}
+
+// dsStoreFiles finds Finder's .DS_Store files under root, at most maxDepth levels
+// deep when that is set, skipping the folders in dsStorePrunes.
+func dsStoreFiles(root string, maxDepth int) []string {
+	var files []string
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if path == root {
+				return nil
+			}
+			for _, prune := range dsStorePrunes {
+				if strings.HasSuffix(path, prune) {
+					return fs.SkipDir
+				}
+			}
+			if maxDepth > 0 && depthBelow(root, path) >= maxDepth {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.Name() == ".DS_Store" && d.Type().IsRegular() {
+			files = append(files, path)
+			if len(files) >= maxDSStoreFiles {
+				return fs.SkipAll
+			}
+		}
+		return nil
+	})
+	return files
+}
+
+func depthBelow(root, path string) int {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return 0
+	}
+	return strings.Count(rel, string(filepath.Separator)) + 1
+}
+
+// externalVolumes lists writable local disks under /Volumes, leaving out the startup
+// disk and network shares, as scan_external_volumes does.
+func externalVolumes(tools Tools) []string {
+	var volumes []string
+	for _, path := range children(volumesDir) {
+		if !isDir(path) || filepath.Base(path) == "Macintosh HD" || syscall.Access(path, writeOK) != nil {
+			continue
+		}
+		if networkVolume(tools, path) {
+			continue
+		}
+		volumes = append(volumes, path)
+	}
+	return volumes
+}
+
+func networkVolume(tools Tools, path string) bool {
+	if output, err := tools.run(diskutilTimeout, "diskutil", "info", path); err == nil {
+		for _, line := range strings.Split(string(output), "\n") {
+			fields := strings.Fields(line)
+			if len(fields) >= 2 && strings.EqualFold(fields[0], "Protocol:") {
+				for _, protocol := range networkProtocols {
+					if fields[1] == protocol {
+						return true
+					}
+				}
+				break
+			}
+		}
+	}
+	return networkFilesystem(volumeFilesystem(tools, path))
+}
+
+// volumeFilesystem is the type column df reports for path, or "" when it cannot tell.
+func volumeFilesystem(tools Tools, path string) string {
+	output, err := tools.run(diskutilTimeout, "df", "-T", path)
+	if err != nil {
+		return ""
+	}
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	fields := strings.Fields(lines[len(lines)-1])
+	if len(fields) < 2 {
+		return ""
+	}
+	return fields[1]
+}
+
+// volumeTrashes lists what sits in the trash of each external volume.
+func volumeTrashes(opts Options) []string {
+	var paths []string
+	for _, volume := range externalVolumes(opts.Tools) {
+		trash := filepath.Join(volume, ".Trashes")
+		if !opts.Whitelist.Match(trash) {
+			paths = append(paths, children(trash)...)
+		}
+	}
+	return paths
+}
+
+func volumeDSStores(opts Options) []string {
+	var paths []string
+	for _, volume := range externalVolumes(opts.Tools) {
+		paths = append(paths, dsStoreFiles(volume, 0)...)
+	}
+	return paths
+}
+
+func homeDSStores(opts Options) []string {
+	return dsStoreFiles(opts.Home, homeDSStoreDepth)
+}
+
+// mailAttachments finds Mail downloads older than MOLE_MAIL_AGE_DAYS, in folders that
+// have grown past MOLE_MAIL_DOWNLOADS_MIN_KB.
+func mailAttachments(opts Options) []string {
+	age := time.Duration(envNumber("MOLE_MAIL_AGE_DAYS", mailAgeDays)) * day
+	minSize := int64(envNumber("MOLE_MAIL_DOWNLOADS_MIN_KB", mailDownloadsMinKB)) << 10
+	var files []string
+	for _, dir := range mailDownloadDirs {
+		root := filepath.Join(opts.Home, dir)
+		if !isDir(root) || measureWith(opts, root) < minSize {
+			continue
+		}
+		_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err == nil && d.Type().IsRegular() && olderThan(path, age) {
+				files = append(files, path)
+			}
+			return nil
+		})
+	}
+	return files
+}
+
+// sandboxedCaches lists the cache contents of every sandboxed app that does not keep
+// its data.
+func sandboxedCaches(opts Options) []string {
+	var paths []string
+	for _, container := range children(filepath.Join(opts.Home, "Library", "Containers")) {
+		bundle := filepath.Base(container)
+		if fixedContainers[bundle] || isCriticalSystemComponent(bundle) || opts.Protection.protectsData(bundle) {
+			continue
+		}
+		paths = append(paths, children(filepath.Join(container, "Data", "Library", "Caches"))...)
+	}
+	return paths
+}
+
+// oldFrameworkVersions lists the framework versions an auto-updating browser left in
+// its app bundle, keeping Current and the version it points at.
+func oldFrameworkVersions(app, framework string) func(Options) []string {
+	return func(opts Options) []string {
+		var old []string
+		for _, dir := range applicationsDirs {
+			if !filepath.IsAbs(dir) {
+				dir = filepath.Join(opts.Home, dir)
+			}
+			versions := filepath.Join(dir, app+".app", "Contents", "Frameworks", framework+".framework", "Versions")
+			current, err := os.Readlink(filepath.Join(versions, "Current"))
+			if err != nil {
+				continue
+			}
+			current = filepath.Base(current)
+			for _, path := range children(versions) {
+				name := filepath.Base(path)
+				if name != "Current" && name != current && isDir(path) {
+					old = append(old, path)
+				}
+			}
+		}
+		return old
+	}
+}
+
+func skipRunning(app string) func(Options) (bool, string) {
+	return func(opts Options) (bool, string) {
+		if opts.Tools.running("-f", app) {
+			return true, app + " running, skipped"
+		}
+		return false, ""
+	}
+}
+
+const edgeUpdaterApps = "Library/Application Support/Microsoft/EdgeUpdater/apps/msedge-stable"
+
+// oldEdgeUpdates lists every Edge updater version but the newest.
+func oldEdgeUpdates(opts Options) []string {
+	var versions []string
+	for _, path := range children(filepath.Join(opts.Home, edgeUpdaterApps)) {
+		if isDir(path) {
+			versions = append(versions, path)
+		}
+	}
+	if len(versions) < 2 {
+		return nil
+	}
+	sort.Slice(versions, func(a, b int) bool {
+		return compareVersions(filepath.Base(versions[a]), filepath.Base(versions[b])) < 0
+	})
+	return versions[:len(versions)-1]
+}
+
+func skipEdgeUpdater(opts Options) (bool, string) {
+	if !isDir(filepath.Join(opts.Home, edgeUpdaterApps)) {
+		return true, ""
+	}
+	return skipRunning("Microsoft Edge")(opts)
+}
+
+// compareVersions orders dotted versions numerically, like sort -V.
+func compareVersions(a, b string) int {
+	partsA, partsB := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(partsA) && i < len(partsB); i++ {
+		numA, errA := strconv.Atoi(partsA[i])
+		numB, errB := strconv.Atoi(partsB[i])
+		switch {
+		case errA == nil && errB == nil && numA != numB:
+			return numA - numB
+		case (errA != nil || errB != nil) && partsA[i] != partsB[i]:
+			return strings.Compare(partsA[i], partsB[i])
+		}
+	}
+	return len(partsA) - len(partsB)
+}
+
+// hasDevProjects is the quick check before scanning home for project caches: a usual
+// code folder, or a project marker within two levels.
+func hasDevProjects(home string) bool {
+	for _, folder := range devFolders {
+		if isDir(filepath.Join(home, folder)) {
+			return true
+		}
+	}
+	markers := make(map[string]bool, len(projectMarkers))
+	for _, marker := range projectMarkers {
+		markers[marker] = true
+	}
+	found := false
+	_ = filepath.WalkDir(home, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || path == home {
+			return nil
+		}
+		if markers[d.Name()] {
+			found = true
+			return fs.SkipAll
+		}
+		if d.IsDir() && (d.Name() == "Library" || d.Name() == ".Trash" || depthBelow(home, path) >= 2) {
+			return fs.SkipDir
+		}
+		return nil
+	})
+	return found
+}
+
+// projectDirs finds folders called name in projects near home: three levels deep, on
+// the home volume, outside Library, hidden folders and node_modules, and giving up
+// after projectScanTimeout.
+func projectDirs(home, name string) []string {
+	deadline := time.Now().Add(projectScanTimeout)
+	homeDevice, ok := device(home)
+	if !ok || !hasDevProjects(home) {
+		return nil
+	}
+	var dirs []string
+	_ = filepath.WalkDir(home, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() || path == home {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fs.SkipAll
+		}
+		if d.Name() == name {
+			dirs = append(dirs, path)
+			return fs.SkipDir
+		}
+		if strings.HasPrefix(d.Name(), ".") || d.Name() == "Library" || d.Name() == "node_modules" || depthBelow(home, path) >= projectScanDepth {
+			return fs.SkipDir
+		}
+		if dev, ok := device(path); !ok || dev != homeDevice {
+			return fs.SkipDir
+		}
+		return nil
+	})
+	return dirs
+}
+
+func device(path string) (uint64, bool) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return 0, false
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, false
+	}
+	return uint64(stat.Dev), true
+}
+
+func nextBuildCaches(opts Options) []string {
+	var paths []string
+	for _, dir := range projectDirs(opts.Home, ".next") {
+		paths = append(paths, children(filepath.Join(dir, "cache"))...)
+	}
+	return paths
+}
+
+func pythonBytecode(opts Options) []string {
+	var paths []string
+	for _, dir := range projectDirs(opts.Home, "__pycache__") {
+		paths = append(paths, children(dir)...)
+	}
+	return paths
+}
+
+// brewLocks lists Homebrew lock files the user may remove without sudo.
+func brewLocks(Options) []string {
+	var paths []string
+	for _, dir := range brewLockDirs {
+		if isDir(dir) && syscall.Access(dir, writeOK) == nil {
+			paths = append(paths, children(dir)...)
+		}
+	}
+	return paths
+}
+
+// skipUnreadable leaves a target alone when folder exists but cannot be listed,
+// usually for want of Full Disk Access.
+func skipUnreadable(folder, note string) func(Options) (bool, string) {
+	return func(opts Options) (bool, string) {
+		_, err := os.ReadDir(filepath.Join(opts.Home, folder))
+		switch {
+		case err == nil:
+			return false, ""
+		case os.IsNotExist(err):
+			return true, ""
+		default:
+			return true, note
+		}
+	}
+}
+
+// appSupportLogs lists log, cache and crash report contents in each app's
+// Application Support folder, for apps that do not keep their data.
+func appSupportLogs(opts Options) []string {
+	var paths []string
+	for _, app := range children(filepath.Join(opts.Home, "Library", "Application Support")) {
+		name := filepath.Base(app)
+		if !isDir(app) || opts.Protection.protectsData(name) || isCriticalSystemComponent(name) {
+			continue
+		}
+		for _, dir := range appSupportLogDirs {
+			paths = append(paths, children(filepath.Join(app, dir))...)
+		}
+	}
+	for _, container := range groupContainerLogs {
+		base := filepath.Join(opts.Home, "Library", "Group Containers", container)
+		paths = append(paths, children(filepath.Join(base, "Logs"))...)
+		paths = append(paths, children(filepath.Join(base, "Library", "Logs"))...)
+	}
+	return paths
+}
+
+// skipSpotifyOffline keeps Spotify's cache when it holds downloaded music: an offline
+// database, downloaded tracks, or a cache too big to be streaming leftovers.
+func skipSpotifyOffline(opts Options) (bool, string) {
+	const note = "offline music detected, kept"
+	storage := filepath.Join(opts.Home, "Library", "Application Support", "Spotify", "PersistentCache", "Storage")
+	if _, err := os.Stat(filepath.Join(storage, "offline.bnk")); err == nil {
+		return true, note
+	}
+	tracks := false
+	_ = filepath.WalkDir(storage, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && d.Type().IsRegular() && strings.HasSuffix(d.Name(), ".file") {
+			tracks = true
+			return fs.SkipAll
+		}
+		return nil
+	})
+	if tracks {
+		return true, note
+	}
+	cache := filepath.Join(opts.Home, "Library", "Caches", "com.spotify.client")
+	if isDir(cache) && measureWith(opts, cache) >= spotifyOfflineSize {
+		return true, note
+	}
+	return false, ""
+}
+
+func skipXcodeRunning(opts Options) (bool, string) {
+	if opts.Tools.running("-x", "Xcode") {
+		return true, "Xcode is running, skipped"
+	}
+	return false, ""
+}
+
+// iosBackupsTarget points at large iOS backups, which only the user can judge.
+func iosBackupsTarget() Target {
+	return Target{Label: "iOS backups", Run: func(opts Options) TargetResult {
+		dir := filepath.Join(opts.Home, "Library", "Application Support", "MobileSync", "Backup")
+		if !isDir(dir) {
+			return TargetResult{}
+		}
+		size := measureWith(opts, dir)
+		if size <= iosBackupNoteSize {
+			return TargetResult{}
+		}
+		return TargetResult{Note: humanize.Bytes(size) + " found, delete them by hand in " + dir}
+	}}
+}
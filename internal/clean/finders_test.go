@@ -0,0 +1,476 @@
+package clean
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeTools answers commands by their full command line; anything unlisted fails as if
+// the program were missing.
+type fakeTools struct {
+	outputs map[string]string
+	mu      sync.Mutex // Run is called from several goroutines
+	calls   []string
+}
+
+func (f *fakeTools) tools() Tools {
+	return Tools{
+		Has: func(name string) bool {
+			for command := range f.outputs {
+				if command == name || strings.HasPrefix(command, name+" ") {
+					return true
+				}
+			}
+			return false
+		},
+		Run: func(_ time.Duration, name string, args ...string) ([]byte, error) {
+			command := strings.Join(append([]string{name}, args...), " ")
+			f.mu.Lock()
+			f.calls = append(f.calls, command)
+			f.mu.Unlock()
+			if output, ok := f.outputs[command]; ok {
+				return []byte(output), nil
+			}
+			return nil, errors.New("exit status 1")
+		},
+	}
+}
+
+func age(t *testing.T, path string, d time.Duration) {
+	t.Helper()
+	old := time.Now().Add(-d)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func mkdir(t *testing.T, path string) {
+	t.Helper()
+	if err := os.MkdirAll(path, 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEmptySubfolders(t *testing.T) {
+	home := t.TempDir()
+	support := filepath.Join(home, "Library/Application Support")
+	mkdir(t, filepath.Join(support, "Gone/a/b"))
+	mkdir(t, filepath.Join(support, "Deep/a/b/c"))
+	mkdir(t, filepath.Join(support, "Kept/empty"))
+	mkdir(t, filepath.Join(support, "com.apple.backgroundtaskmanagement"))
+	writeFile(t, filepath.Join(support, "Used/data"), 1)
+	mkdir(t, filepath.Join(support, "Used/empty"))
+
+	opts := Options{Home: home, Whitelist: newWhitelist([]string{filepath.Join(support, "Kept/empty")}, nil)}
+	got := normalizePaths(emptySubfolders("Library/Application Support")(opts))
+	want := []string{
+		filepath.Join(support, "Used/empty"),
+		filepath.Join(support, "Gone"),
+		filepath.Join(support, "Deep/a"), // Three passes reach Deep/a, not Deep
+	}
+	if strings.Join(got, ",") != strings.Join(normalizePaths(want), ",") {
+		t.Errorf("emptySubfolders = %q, want %q", got, want)
+	}
+}
+
+func TestDSStoreFiles(t *testing.T) {
+	home := t.TempDir()
+	writeFile(t, filepath.Join(home, ".DS_Store"), 1)
+	writeFile(t, filepath.Join(home, "Documents/.DS_Store"), 1)
+	writeFile(t, filepath.Join(home, "Code/app/node_modules/x/.DS_Store"), 1)
+	writeFile(t, filepath.Join(home, "Library/Caches/x/.DS_Store"), 1)
+	writeFile(t, filepath.Join(home, "a/b/c/d/e/f/.DS_Store"), 1)
+
+	got := dsStoreFiles(home, homeDSStoreDepth)
+	if len(got) != 2 {
+		t.Errorf("home .DS_Store files = %q, want the top two", got)
+	}
+	if got := dsStoreFiles(home, 0); len(got) != 3 {
+		t.Errorf("volume .DS_Store files = %q, want no depth limit", got)
+	}
+}
+
+func TestMailAttachments(t *testing.T) {
+	home := t.TempDir()
+	downloads := filepath.Join(home, "Library/Mail Downloads")
+	writeFile(t, filepath.Join(downloads, "old.pdf"), 1)
+	writeFile(t, filepath.Join(downloads, "new.pdf"), 1)
+	age(t, filepath.Join(downloads, "old.pdf"), 40*day)
+
+	sized := func(size int64) Options {
+		return Options{Home: home, Measure: func(string) int64 { return size }}
+	}
+	if got := mailAttachments(sized(1 << 20)); len(got) != 0 {
+		t.Errorf("attachments below the minimum size = %q", got)
+	}
+	got := mailAttachments(sized(10 << 20))
+	if len(got) != 1 || filepath.Base(got[0]) != "old.pdf" {
+		t.Errorf("attachments = %q, want old.pdf", got)
+	}
+	t.Setenv("MOLE_MAIL_AGE_DAYS", "60")
+	if got := mailAttachments(sized(10 << 20)); len(got) != 0 {
+		t.Errorf("attachments with a 60 day age = %q", got)
+	}
+}
+
+func TestTrashWhitelistAndFinderMetadataNotes(t *testing.T) {
+	home := t.TempDir()
+	writeFile(t, filepath.Join(home, ".Trash/old.zip"), 1)
+	writeFile(t, filepath.Join(home, "Documents/.DS_Store"), 1)
+	mkdir(t, filepath.Join(home, "Library"))
+	whitelist := newWhitelist([]string{filepath.Join(home, ".Trash"), FinderMetadataSentinel}, nil)
+
+	result := Run(Categories[1:3], Options{Home: home, DryRun: true, Whitelist: whitelist, Protection: testProtection(t), Tools: (&fakeTools{}).tools()})
+	notes := map[string]string{}
+	for _, category := range result.Categories {
+		for _, target := range category.Targets {
+			notes[target.Label] = target.Note
+			if len(target.Items) > 0 {
+				t.Errorf("%s cleaned %+v despite the whitelist", target.Label, target.Items)
+			}
+		}
+	}
+	if notes["Trash"] != "whitelist protected" || notes["Home directory (.DS_Store)"] != "whitelist protected" {
+		t.Errorf("notes = %q", notes)
+	}
+}
+
+func TestOldFrameworkVersions(t *testing.T) {
+	apps := t.TempDir()
+	applicationsDirs = []string{apps}
+	t.Cleanup(func() { applicationsDirs = []string{"/Applications", "Applications"} })
+	versions := filepath.Join(apps, "Google Chrome.app/Contents/Frameworks/Google Chrome Framework.framework/Versions")
+	for _, version := range []string{"120.0", "121.0", "122.0"} {
+		mkdir(t, filepath.Join(versions, version))
+	}
+	if err := os.Symlink("122.0", filepath.Join(versions, "Current")); err != nil {
+		t.Fatal(err)
+	}
+
+	got := oldFrameworkVersions("Google Chrome", "Google Chrome Framework")(Options{Home: t.TempDir()})
+	if len(got) != 2 || strings.Contains(strings.Join(got, ","), "122.0") {
+		t.Errorf("old versions = %q, want 120.0 and 121.0", got)
+	}
+
+	running := &fakeTools{outputs: map[string]string{"pgrep -f Google Chrome": "42"}}
+	if skip, note := skipRunning("Google Chrome")(Options{Tools: running.tools()}); !skip || note == "" {
+		t.Error("old versions cleaned while Chrome runs")
+	}
+}
+
+func TestOldEdgeUpdates(t *testing.T) {
+	home := t.TempDir()
+	for _, version := range []string{"9.0.1", "10.0.0", "10.0.2"} {
+		mkdir(t, filepath.Join(home, edgeUpdaterApps, version))
+	}
+	got := oldEdgeUpdates(Options{Home: home})
+	if len(got) != 2 || strings.Contains(strings.Join(got, ","), "10.0.2") {
+		t.Errorf("old updates = %q, want all but 10.0.2", got)
+	}
+}
+
+func TestSandboxedCachesSkipProtectedApps(t *testing.T) {
+	home := t.TempDir()
+	containers := filepath.Join(home, "Library/Containers")
+	writeFile(t, filepath.Join(containers, "com.example.app/Data/Library/Caches/blob"), 1)
+	writeFile(t, filepath.Join(containers, "com.1password.app/Data/Library/Caches/blob"), 1)
+	writeFile(t, filepath.Join(containers, "com.apple.AppStore/Data/Library/Caches/blob"), 1)
+
+	got := sandboxedCaches(Options{Home: home, Protection: testProtection(t)})
+	if len(got) != 1 || !strings.Contains(got[0], "com.example.app") {
+		t.Errorf("sandboxed caches = %q, want com.example.app only", got)
+	}
+}
+
+func TestOrphanedAppData(t *testing.T) {
+	home := t.TempDir()
+	caches := filepath.Join(home, "Library/Caches")
+	for _, bundle := range []string{"com.gone.app", "com.installed.app", "com.recent.app", "com.1password.app"} {
+		writeFile(t, filepath.Join(caches, bundle, "data"), 1)
+		if bundle != "com.recent.app" {
+			age(t, filepath.Join(caches, bundle), 90*day)
+		}
+	}
+	mkdir(t, filepath.Join(home, filepath.Dir(installedCacheFile)))
+	if err := os.WriteFile(filepath.Join(home, installedCacheFile), []byte("com.installed.app\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got := orphanedAppData(Options{Home: home, Protection: testProtection(t), Measure: func(string) int64 { return 1 }})
+	if len(got) != 1 || filepath.Base(got[0]) != "com.gone.app" {
+		t.Errorf("orphans = %q, want com.gone.app", got)
+	}
+}
+
+func TestSkipLivePnpmStore(t *testing.T) {
+	home := "/Users/me"
+	cases := map[string]struct {
+		outputs map[string]string
+		skip    bool
+	}{
+		"no pnpm":      {nil, false},
+		"default path": {map[string]string{"pnpm --version": "9", "pnpm store path": home + "/" + pnpmStore + "\n"}, true},
+		"moved store":  {map[string]string{"pnpm --version": "9", "pnpm store path": "/Volumes/dev/pnpm"}, false},
+		"path unknown": {map[string]string{"pnpm --version": "9"}, true},
+	}
+	for name, c := range cases {
+		tools := &fakeTools{outputs: c.outputs}
+		if skip, _ := skipLivePnpmStore(Options{Home: home, Tools: tools.tools()}); skip != c.skip {
+			t.Errorf("%s: skip = %v, want %v", name, skip, c.skip)
+		}
+	}
+}
+
+func TestToolTargets(t *testing.T) {
+	npm := toolTarget("npm cache", "npm", "cache", "clean", "--force")
+	tools := &fakeTools{outputs: map[string]string{"npm cache clean --force": ""}}
+	if result := npm.Run(Options{DryRun: true, Tools: tools.tools()}); !result.Ran || len(tools.calls) != 0 {
+		t.Errorf("dry run = %+v, calls = %q", result, tools.calls)
+	}
+	if result := npm.Run(Options{Tools: tools.tools()}); !result.Ran {
+		t.Errorf("npm cache not cleaned: %+v", result)
+	}
+
+	stopped := &fakeTools{outputs: map[string]string{"docker builder prune -af": ""}}
+	if result := dockerCacheTarget().Run(Options{Tools: stopped.tools()}); result.Ran {
+		t.Errorf("pruned with the daemon down: calls = %q", stopped.calls)
+	}
+}
+
+func TestHomebrewRunsWeekly(t *testing.T) {
+	home := t.TempDir()
+	tools := &fakeTools{outputs: map[string]string{
+		"brew cleanup":    "Removing: a\nRemoving: b\n==> This operation has freed approximately 120.5MB of disk space.\n",
+		"brew autoremove": "Uninstalling /opt/homebrew/Cellar/x\n",
+	}}
+	opts := Options{Home: home, Tools: tools.tools(), Measure: func(string) int64 { return 100 << 20 }}
+	mkdir(t, filepath.Join(home, brewCacheFolder))
+
+	result := homebrewTarget().Run(opts)
+	if !result.Ran || !strings.Contains(result.Note, "removed 1 orphaned dependencies") {
+		t.Errorf("first run = %+v", result)
+	}
+	tools.calls = nil
+	result = homebrewTarget().Run(opts)
+	if len(tools.calls) != 0 || !strings.Contains(result.Note, "0d ago") {
+		t.Errorf("second run = %+v, calls = %q", result, tools.calls)
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	if compareVersions("10.0.0", "9.9.9") <= 0 || compareVersions("1.2", "1.2.1") >= 0 || compareVersions("1.0", "1.0") != 0 {
+		t.Error("compareVersions does not order like sort -V")
+	}
+}
+
+func TestEmptyLibraryFolders(t *testing.T) {
+	home := t.TempDir()
+	mkdir(t, filepath.Join(home, "Library/Empty"))
+	mkdir(t, filepath.Join(home, "Library/Nested/empty"))
+	writeFile(t, filepath.Join(home, "Library/Used/data"), 1)
+
+	got := emptyLibraryFolders(Options{Home: home})
+	if len(got) != 1 || filepath.Base(got[0]) != "Empty" {
+		t.Errorf("empty Library folders = %q, want Empty only", got)
+	}
+}
+
+func TestOldFrameworkVersionsNeedCurrent(t *testing.T) {
+	apps := t.TempDir()
+	applicationsDirs = []string{apps}
+	t.Cleanup(func() { applicationsDirs = []string{"/Applications", "Applications"} })
+	versions := filepath.Join(apps, "Microsoft Edge.app/Contents/Frameworks/Microsoft Edge Framework.framework/Versions")
+	mkdir(t, filepath.Join(versions, "120.0"))
+	mkdir(t, filepath.Join(versions, "121.0"))
+
+	// Without Current there is no telling which version is live.
+	if got := oldFrameworkVersions("Microsoft Edge", "Microsoft Edge Framework")(Options{Home: t.TempDir()}); len(got) != 0 {
+		t.Errorf("old versions without Current = %q", got)
+	}
+
+	if err := os.Symlink("121.0", filepath.Join(versions, "Current")); err != nil {
+		t.Fatal(err)
+	}
+	edge := Target{Label: "Edge old versions", Find: oldFrameworkVersions("Microsoft Edge", "Microsoft Edge Framework")}
+	whitelist := newWhitelist([]string{filepath.Join(versions, "120.0")}, nil)
+	result := Run([]Category{{Name: "Browsers", Targets: []Target{edge}}}, Options{Home: t.TempDir(), Whitelist: whitelist})
+	if result.Items != 0 || result.Skipped != 1 || !isDir(filepath.Join(versions, "120.0")) {
+		t.Errorf("whitelisted version removed: %+v", result)
+	}
+}
+
+func TestOldEdgeUpdatesKeepsOnlyVersion(t *testing.T) {
+	home := t.TempDir()
+	mkdir(t, filepath.Join(home, edgeUpdaterApps, "10.0.0"))
+	if got := oldEdgeUpdates(Options{Home: home}); got != nil {
+		t.Errorf("old updates = %q, want none", got)
+	}
+	if skip, _ := skipEdgeUpdater(Options{Home: t.TempDir()}); !skip {
+		t.Error("Edge updater target ran without the updater folder")
+	}
+}
+
+func TestExternalVolumes(t *testing.T) {
+	volumes := t.TempDir()
+	volumesDir = volumes
+	t.Cleanup(func() { volumesDir = "/Volumes" })
+	tools := &fakeTools{outputs: map[string]string{
+		"diskutil info " + filepath.Join(volumes, "Share"): "   Protocol:                  SMB\n",
+		"df -T " + filepath.Join(volumes, "NFS"):           "Filesystem Type\nserver:/x nfs\n",
+	}}
+	if got := externalVolumes(tools.tools()); got != nil {
+		t.Errorf("volumes with nothing mounted = %q", got)
+	}
+
+	for _, name := range []string{"Macintosh HD", "Share", "NFS", "Backup"} {
+		mkdir(t, filepath.Join(volumes, name))
+	}
+	got := externalVolumes(tools.tools())
+	if len(got) != 1 || filepath.Base(got[0]) != "Backup" {
+		t.Errorf("external volumes = %q, want Backup only", got)
+	}
+}
+
+func TestProjectDirs(t *testing.T) {
+	home := t.TempDir()
+	mkdir(t, filepath.Join(home, "Code/site/.next/cache/webpack"))
+	mkdir(t, filepath.Join(home, "Code/tool/__pycache__"))
+	mkdir(t, filepath.Join(home, "Library/Caches/site/.next"))
+	mkdir(t, filepath.Join(home, ".Trash/site/.next"))
+	mkdir(t, filepath.Join(home, "Code/app/node_modules/pkg/.next"))
+
+	got := projectDirs(home, ".next")
+	if len(got) != 1 || got[0] != filepath.Join(home, "Code/site/.next") {
+		t.Errorf(".next folders = %q, want Code/site only", got)
+	}
+	if got := nextBuildCaches(Options{Home: home}); len(got) != 1 || filepath.Base(got[0]) != "webpack" {
+		t.Errorf("Next.js caches = %q", got)
+	}
+	if got := projectDirs(t.TempDir(), ".next"); got != nil {
+		t.Errorf("home without projects = %q", got)
+	}
+}
+
+func TestSkipSpotifyOffline(t *testing.T) {
+	storage := "Library/Application Support/Spotify/PersistentCache/Storage"
+	cases := map[string]struct {
+		file string
+		size int64
+		skip bool
+	}{
+		"streaming only":   {"", 1 << 20, false},
+		"offline database": {storage + "/offline.bnk", 1 << 20, true},
+		"downloaded track": {storage + "/ab/track.file", 1 << 20, true},
+		"large cache":      {"", spotifyOfflineSize, true},
+	}
+	for name, c := range cases {
+		home := t.TempDir()
+		mkdir(t, filepath.Join(home, "Library/Caches/com.spotify.client"))
+		if c.file != "" {
+			writeFile(t, filepath.Join(home, c.file), 1)
+		}
+		opts := Options{Home: home, Measure: func(string) int64 { return c.size }}
+		if skip, _ := skipSpotifyOffline(opts); skip != c.skip {
+			t.Errorf("%s: skip = %v, want %v", name, skip, c.skip)
+		}
+	}
+}
+
+func TestSkipXcodeRunning(t *testing.T) {
+	running := &fakeTools{outputs: map[string]string{"pgrep -x Xcode": "42"}}
+	if skip, note := skipXcodeRunning(Options{Tools: running.tools()}); !skip || note == "" {
+		t.Error("derived data cleaned while Xcode runs")
+	}
+	if skip, _ := skipXcodeRunning(Options{Tools: (&fakeTools{}).tools()}); skip {
+		t.Error("derived data skipped with Xcode closed")
+	}
+}
+
+func TestAppSupportLogs(t *testing.T) {
+	home := t.TempDir()
+	support := filepath.Join(home, "Library/Application Support")
+	writeFile(t, filepath.Join(support, "Example/logs/today.log"), 1)
+	writeFile(t, filepath.Join(support, "Example/Crashpad/completed/dump"), 1)
+	writeFile(t, filepath.Join(support, "com.1password.app/logs/today.log"), 1)
+	writeFile(t, filepath.Join(support, "com.apple.sharedfilelist/logs/x"), 1)
+	writeFile(t, filepath.Join(home, "Library/Group Containers/group.com.apple.contentdelivery/Logs/x.log"), 1)
+
+	got := appSupportLogs(Options{Home: home, Protection: testProtection(t)})
+	if len(got) != 3 || strings.Contains(strings.Join(got, ","), "1password") || strings.Contains(strings.Join(got, ","), "sharedfilelist") {
+		t.Errorf("app support logs = %q", got)
+	}
+
+	skip := skipUnreadable("Library/Application Support", "no permission")
+	if skipped, _ := skip(Options{Home: home}); skipped {
+		t.Error("readable Application Support skipped")
+	}
+	if skipped, note := skip(Options{Home: t.TempDir()}); !skipped || note != "" {
+		t.Errorf("missing folder: skip %v, note %q", skipped, note)
+	}
+	if os.Geteuid() == 0 {
+		return // Root reads any folder
+	}
+	if err := os.Chmod(support, 0); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chmod(support, 0755) })
+	if skipped, note := skip(Options{Home: home}); !skipped || note != "no permission" {
+		t.Errorf("unreadable folder: skip %v, note %q", skipped, note)
+	}
+}
+
+func TestIOSBackupsTarget(t *testing.T) {
+	home := t.TempDir()
+	backups := iosBackupsTarget()
+	if result := backups.Run(Options{Home: home}); result.Note != "" {
+		t.Errorf("no backup folder = %+v", result)
+	}
+	mkdir(t, filepath.Join(home, "Library/Application Support/MobileSync/Backup/device"))
+	result := backups.Run(Options{Home: home, Measure: func(string) int64 { return 2 << 30 }})
+	if !strings.Contains(result.Note, "delete them by hand") || len(result.Items) != 0 {
+		t.Errorf("large backups = %+v", result)
+	}
+}
+
+func TestRecentItemLists(t *testing.T) {
+	home := t.TempDir()
+	recent := target("Recent items list", recentItemLists()...)
+	opts := Options{Home: home, Measure: func(string) int64 { return 1 }}
+	if result := Run([]Category{{Name: "macOS", Targets: []Target{recent}}}, opts); result.Items != 0 || result.Failed != 0 {
+		t.Errorf("without the shared list folder = %+v", result)
+	}
+
+	shared := filepath.Join(home, "Library/Application Support/com.apple.sharedfilelist")
+	writeFile(t, filepath.Join(shared, "com.apple.LSSharedFileList.RecentDocuments.sfl2"), 1)
+	writeFile(t, filepath.Join(shared, "com.apple.LSSharedFileList.RecentApplications.sfl"), 1)
+	writeFile(t, filepath.Join(shared, "com.apple.LSSharedFileList.FavoriteItems.sfl2"), 1)
+	if result := Run([]Category{{Name: "macOS", Targets: []Target{recent}}}, opts); result.Items != 2 {
+		t.Errorf("recent lists removed = %d, want 2", result.Items)
+	}
+	if _, err := os.Stat(filepath.Join(shared, "com.apple.LSSharedFileList.FavoriteItems.sfl2")); err != nil {
+		t.Error("favorites list removed")
+	}
+}
+
+func TestInstalledBundlesUsesFreshCache(t *testing.T) {
+	home := t.TempDir()
+	writeFile(t, filepath.Join(home, installedCacheFile), 0)
+	if err := os.WriteFile(filepath.Join(home, installedCacheFile), []byte("com.cached.app\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tools := &fakeTools{}
+	if got := installedBundles(Options{Home: home, Tools: tools.tools()}); !got["com.cached.app"] || len(tools.calls) != 0 {
+		t.Errorf("installed = %v, calls = %q", got, tools.calls)
+	}
+
+	age(t, filepath.Join(home, installedCacheFile), time.Hour)
+	installedBundles(Options{Home: home, Tools: tools.tools()})
+	if len(tools.calls) == 0 {
+		t.Error("stale cache reused without scanning")
+	}
+}
@@ -0,0 +1,166 @@
+package clean
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	orphanAge         = 60 * day
+	maxOrphanMatches  = 100 // Per pattern, to bound the sweep
+	installedCacheAge = 5 * time.Minute
+	appScanDepth      = 3
+	runningAppTimeout = 5 * time.Second
+	plistBuddy        = "/usr/libexec/PlistBuddy"
+)
+
+var (
+	installedCacheFile = filepath.Join(".cache", "mole", "installed_apps_cache")
+	// appDirs hold installed apps; the last is relative to home.
+	appDirs         = []string{"/Applications", "/System/Applications", "Applications"}
+	launchAgentDirs = []string{"Library/LaunchAgents", "/Library/LaunchAgents"}
+	bundlePatterns  = []string{"com.*", "org.*", "net.*", "io.*"}
+	// orphanResources are the Library folders whose per-app entries outlive the app.
+	// LaunchAgents and LaunchDaemons must never be here: removing them breaks login items.
+	orphanResources = []struct {
+		folder   string
+		patterns []string
+	}{
+		{"Library/Caches", bundlePatterns},
+		{"Library/Logs", bundlePatterns},
+		{"Library/Saved Application State", []string{"*.savedState"}},
+		{"Library/WebKit", bundlePatterns},
+		{"Library/HTTPStorages", bundlePatterns},
+		{"Library/Cookies", []string{"*.binarycookies"}},
+	}
+	// systemBundleNames are never orphans even though no app reports them.
+	systemBundleNames = map[string]bool{"loginwindow": true, "dock": true, "systempreferences": true, "systemsettings": true, "settings": true, "controlcenter": true, "finder": true, "safari": true}
+)
+
+// orphanedAppData finds caches, logs, saved state and web data of apps no longer
+// installed, once they have been left alone for 60 days.
+func orphanedAppData(opts Options) []string {
+	installed := installedBundles(opts)
+	var orphans []string
+	for _, resource := range orphanResources {
+		base := filepath.Join(opts.Home, resource.folder)
+		if _, err := os.ReadDir(base); err != nil {
+			continue
+		}
+		for _, pattern := range resource.patterns {
+			matches, _ := filepath.Glob(filepath.Join(base, pattern))
+			if len(matches) > maxOrphanMatches {
+				matches = matches[:maxOrphanMatches]
+			}
+			for _, match := range matches {
+				bundle := strings.TrimSuffix(strings.TrimSuffix(filepath.Base(match), ".savedState"), ".binarycookies")
+				if orphaned(bundle, match, installed, opts.Protection) && measureWith(opts, match) > 0 {
+					orphans = append(orphans, match)
+				}
+			}
+		}
+	}
+	return orphans
+}
+
+func orphaned(bundle, path string, installed map[string]bool, protection Protection) bool {
+	if protection.protectsBundle(bundle) || installed[bundle] || systemBundleNames[bundle] {
+		return false
+	}
+	return olderThan(path, orphanAge)
+}
+
+// installedBundles are the bundle IDs of installed and running apps and of launch
+// agents, cached for five minutes like scan_installed_apps.
+func installedBundles(opts Options) map[string]bool {
+	cache := filepath.Join(opts.Home, installedCacheFile)
+	if info, err := os.Stat(cache); err == nil && time.Since(info.ModTime()) < installedCacheAge && info.Size() > 0 {
+		if data, err := os.ReadFile(cache); err == nil {
+			return bundleSet(strings.Split(string(data), "\n"))
+		}
+	}
+
+	var mu sync.Mutex
+	var bundles []string
+	add := func(ids []string) {
+		mu.Lock()
+		bundles = append(bundles, ids...)
+		mu.Unlock()
+	}
+	var wg sync.WaitGroup
+	for _, dir := range appDirs {
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(opts.Home, dir)
+		}
+		wg.Add(1)
+		go func(dir string) {
+			defer wg.Done()
+			add(appBundleIDs(opts.Tools, dir))
+		}(dir)
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		output, _ := opts.Tools.run(runningAppTimeout, "osascript", "-e", `tell application "System Events" to get bundle identifier of every application process`)
+		add(strings.Split(string(output), ","))
+	}()
+	wg.Wait()
+	for _, dir := range launchAgentDirs {
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(opts.Home, dir)
+		}
+		for _, path := range children(dir) {
+			if strings.HasSuffix(path, ".plist") {
+				bundles = append(bundles, strings.TrimSuffix(filepath.Base(path), ".plist"))
+			}
+		}
+	}
+
+	set := bundleSet(bundles)
+	ids := make([]string, 0, len(set))
+	for id := range set {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	if err := os.MkdirAll(filepath.Dir(cache), 0755); err == nil {
+		_ = os.WriteFile(cache, []byte(strings.Join(ids, "\n")+"\n"), 0644)
+	}
+	return set
+}
+
+// appBundleIDs reads the bundle ID of every app within three levels of dir.
+func appBundleIDs(tools Tools, dir string) []string {
+	var ids []string
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() || path == dir {
+			return nil
+		}
+		if strings.HasSuffix(d.Name(), ".app") {
+			output, err := tools.run(0, plistBuddy, "-c", "Print :CFBundleIdentifier", filepath.Join(path, "Contents", "Info.plist"))
+			if err == nil {
+				ids = append(ids, string(output))
+			}
+			return fs.SkipDir
+		}
+		if depthBelow(dir, path) >= appScanDepth {
+			return fs.SkipDir
+		}
+		return nil
+	})
+	return ids
+}
+
+func bundleSet(ids []string) map[string]bool {
+	set := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if id = strings.TrimSpace(id); id != "" {
+			set[id] = true
+		}
+	}
+	return set
+}
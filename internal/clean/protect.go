@@ -0,0 +1,158 @@
+package clean
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// protectedBundleArrays are the lists in lib/core/app_protection.sh whose bundles keep
+// their data. They are read from the script so the shell and Go cleaners share one list.
+var protectedBundleArrays = []string{"SYSTEM_CRITICAL_BUNDLES", "DATA_PROTECTED_BUNDLES"}
+
+var (
+	arrayStartPattern    = regexp.MustCompile(`^\s*(?:readonly\s+|declare\s+-a\s+)?([A-Z_]+)=\($`)
+	quotedEntryPattern   = regexp.MustCompile(`^\s*"([^"]+)"`)
+	containerPattern     = regexp.MustCompile(`/Library/(?:Group )?Containers/([^/]+)`)
+	protectedKeywords    = []string{"systemsettings", "systempreferences", "controlcenter", "com.apple.settings", "com.apple.notes"}
+	protectedSubstrings  = []string{"com.apple.Settings", "com.apple.SystemSettings", "com.apple.controlcenter", "com.apple.finder", "com.apple.dock", "/Mobile Documents"}
+	protectedByHostFiles = []string{"/ByHost/com.apple.bluetooth.", "/ByHost/com.apple.wifi."}
+	// criticalKeywords mark the system components is_critical_system_component never
+	// lets a sweep touch, by name.
+	criticalKeywords = []string{"backgroundtaskmanagement", "loginitems", "systempreferences", "systemsettings", "settings", "preferences", "controlcenter", "biometrickit", "sfl", "tcc"}
+)
+
+// Protection decides which paths are never cleaned, after should_protect_path in
+// lib/core/app_protection.sh.
+type Protection struct {
+	bundles []*regexp.Regexp
+}
+
+// protectionScript is where app_protection.sh sits relative to the bin folder holding
+// the running binary.
+const protectionScript = "../lib/core/app_protection.sh"
+
+// LoadInstalledProtection reads the protection lists of the Mole install running this
+// binary, bin/<binary> next to lib/.
+func LoadInstalledProtection() (Protection, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return Protection{}, err
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	return LoadProtection(filepath.Join(filepath.Dir(exe), protectionScript))
+}
+
+// LoadProtection reads the protected bundle lists from app_protection.sh.
+func LoadProtection(script string) (Protection, error) {
+	file, err := os.Open(script)
+	if err != nil {
+		return Protection{}, err
+	}
+	defer file.Close()
+	return ParseProtection(file)
+}
+
+// ParseProtection reads the protected bundle arrays from the script. Missing either one
+// is an error: cleaning without them could remove data the shell cleaner keeps.
+func ParseProtection(r io.Reader) (Protection, error) {
+	found := make(map[string]int)
+	var p Protection
+	current := ""
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if current == "" {
+			if m := arrayStartPattern.FindStringSubmatch(line); m != nil && isProtectedArray(m[1]) {
+				current = m[1]
+			}
+			continue
+		}
+		if strings.TrimSpace(line) == ")" {
+			current = ""
+			continue
+		}
+		if m := quotedEntryPattern.FindStringSubmatch(line); m != nil {
+			p.bundles = append(p.bundles, globRegexp(m[1]))
+			found[current]++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Protection{}, err
+	}
+	for _, name := range protectedBundleArrays {
+		if found[name] == 0 {
+			return Protection{}, fmt.Errorf("%s not found in the app protection script", name)
+		}
+	}
+	return p, nil
+}
+
+func isProtectedArray(name string) bool {
+	for _, array := range protectedBundleArrays {
+		if name == array {
+			return true
+		}
+	}
+	return false
+}
+
+func (p Protection) protectsBundle(name string) bool {
+	for _, bundle := range p.bundles {
+		if bundle.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// protectsData reports whether the app with this bundle ID or folder name keeps its
+// data, as should_protect_data does for the name and its lowercase form.
+func (p Protection) protectsData(name string) bool {
+	return p.protectsBundle(name) || p.protectsBundle(strings.ToLower(name))
+}
+
+func isCriticalSystemComponent(name string) bool {
+	lower := strings.ToLower(name)
+	for _, keyword := range criticalKeywords {
+		if strings.Contains(lower, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// Protects reports whether path belongs to a system component or an app whose data is
+// kept: System Settings, Finder, Dock, iCloud Drive, Wi-Fi and Bluetooth settings, and
+// the protected bundles by container, by full path or by name.
+func (p Protection) Protects(path string) bool {
+	if path == "" {
+		return false
+	}
+	lower := strings.ToLower(path)
+	for _, keyword := range protectedKeywords {
+		if strings.Contains(lower, keyword) {
+			return true
+		}
+	}
+	for _, part := range protectedSubstrings {
+		if strings.Contains(path, part) {
+			return true
+		}
+	}
+	for _, part := range protectedByHostFiles {
+		if strings.Contains(path, part) {
+			return true
+		}
+	}
+	if m := containerPattern.FindStringSubmatch(path); m != nil && p.protectsBundle(m[1]) {
+		return true
+	}
+	return p.protectsBundle(path) || p.protectsBundle(filepath.Base(path))
+}
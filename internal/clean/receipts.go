@@ -0,0 +1,405 @@
+package clean
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ReceiptsDir is where the installer keeps a receipt for every package it installed.
+const ReceiptsDir = "/var/db/receipts"
+
+// ReceiptsCategory names installer debris in results.
+const ReceiptsCategory = "Installer leftovers"
+
+// receiptRoots are the only folders installer leftovers are removed from; anything a
+// package put elsewhere is reported but kept.
+var receiptRoots = []string{"/Applications/", "/Library/", "/usr/local/", "/opt/", "/Users/"}
+
+// sharedFolders hold files of many vendors, relative to the volume. Payloads list them
+// as folders too, but a package never owns one, nor anything under sharedTrees.
+var sharedFolders = map[string]bool{
+	"/Applications": true, "/Applications/Utilities": true, "/Library": true, "/Users": true, "/usr": true, "/usr/local": true, "/opt": true,
+	"/Library/Application Support": true, "/Library/Audio": true, "/Library/Audio/Plug-Ins": true,
+	"/Library/Audio/Plug-Ins/Components": true, "/Library/Audio/Plug-Ins/HAL": true, "/Library/Audio/Plug-Ins/VST": true,
+	"/Library/Audio/Plug-Ins/VST3": true, "/Library/Caches": true, "/Library/ColorSync": true, "/Library/ColorSync/Profiles": true,
+	"/Library/Components": true, "/Library/Contextual Menu Items": true, "/Library/CoreMediaIO": true,
+	"/Library/CoreMediaIO/Plug-Ins": true, "/Library/CoreMediaIO/Plug-Ins/DAL": true, "/Library/Documentation": true,
+	"/Library/DriverExtensions": true, "/Library/Extensions": true, "/Library/Filesystems": true, "/Library/Fonts": true,
+	"/Library/Frameworks": true, "/Library/Image Capture": true, "/Library/Input Methods": true, "/Library/Internet Plug-Ins": true,
+	"/Library/Keychains": true, "/Library/LaunchAgents": true, "/Library/LaunchDaemons": true, "/Library/Logs": true,
+	"/Library/Preferences": true, "/Library/PreferencePanes": true, "/Library/Printers": true, "/Library/PrivilegedHelperTools": true,
+	"/Library/QuickLook": true, "/Library/Receipts": true, "/Library/Screen Savers": true, "/Library/Security": true,
+	"/Library/Services": true, "/Library/Spotlight": true, "/Library/StartupItems": true, "/Library/SystemExtensions": true,
+	"/Library/Widgets": true,
+	"/usr/local/bin":   true, "/usr/local/etc": true, "/usr/local/include": true, "/usr/local/lib": true,
+	"/usr/local/lib/pkgconfig": true, "/usr/local/libexec": true, "/usr/local/opt": true, "/usr/local/sbin": true,
+	"/usr/local/share": true, "/usr/local/share/doc": true, "/usr/local/share/info": true, "/usr/local/share/man": true,
+	"/usr/local/share/zsh": true, "/usr/local/share/zsh/site-functions": true, "/usr/local/var": true,
+}
+
+// sharedTrees are managed by other tools; no installer package owns anything in them.
+var sharedTrees = []string{"/usr/local/Cellar/", "/usr/local/Caskroom/", "/usr/local/Homebrew/", "/usr/local/share/man/", "/opt/homebrew/"}
+
+// sharedFolder reports whether rel, a folder relative to the volume, holds other
+// vendors' files. In a home folder only Library can have vendor folders, as in /Library.
+func sharedFolder(rel string) bool {
+	if sharedFolders[rel] {
+		return true
+	}
+	for _, tree := range sharedTrees {
+		if strings.HasPrefix(rel+"/", tree) {
+			return true
+		}
+	}
+	if rest, ok := strings.CutPrefix(rel, "/Users/"); ok {
+		_, inHome, nested := strings.Cut(rest, "/")
+		inLibrary, ok := strings.CutPrefix(inHome, "Library/")
+		return !nested || !ok || sharedFolders["/Library/"+inLibrary]
+	}
+	return false
+}
+
+// ReceiptInfo is what pkgutil --pkg-info says about a package.
+type ReceiptInfo struct {
+	ID          string    `json:"id"`
+	Version     string    `json:"version"`
+	Volume      string    `json:"volume"`
+	Location    string    `json:"location"`
+	InstallTime time.Time `json:"install_time"`
+}
+
+// ReceiptDB reads and forgets installer receipts. Pkgutil is the real one.
+type ReceiptDB struct {
+	Packages func() ([]string, error)
+	Info     func(id string) (ReceiptInfo, error)
+	// Files lists a package's payload files and folders, relative to its location.
+	Files  func(id string) (files, dirs []string, err error)
+	Forget func(id string) error
+}
+
+// Receipt is a package whose apps were deleted or whose payload is gone, with what it
+// left behind.
+type Receipt struct {
+	ReceiptInfo
+	Reason    string   `json:"reason"`
+	Leftovers []Item   `json:"leftovers"`      // Files inside the package's own folders that no other package installed
+	Kept      []string `json:"kept,omitempty"` // Files it left in shared folders, listed but never removed
+	Dirs      []string `json:"-"`              // The package's own folders, removed once empty
+	Size      int64    `json:"size"`
+}
+
+// Pkgutil reads the receipts of the startup volume with pkgutil(1). Forgetting a
+// receipt needs root.
+var Pkgutil = ReceiptDB{
+	Packages: func() ([]string, error) {
+		return pkgutilLines("--pkgs")
+	},
+	Info: func(id string) (ReceiptInfo, error) {
+		out, err := exec.Command("pkgutil", "--pkg-info", id).Output()
+		if err != nil {
+			return ReceiptInfo{}, err
+		}
+		return ParseReceiptInfo(string(out))
+	},
+	Files: func(id string) ([]string, []string, error) {
+		files, err := pkgutilLines("--only-files", "--files", id)
+		if err != nil {
+			return nil, nil, err
+		}
+		dirs, err := pkgutilLines("--only-dirs", "--files", id)
+		return files, dirs, err
+	},
+	Forget: func(id string) error {
+		out, err := exec.Command("pkgutil", "--forget", id).CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("pkgutil --forget %s: %s", id, strings.TrimSpace(string(out)))
+		}
+		return nil
+	},
+}
+
+func pkgutilLines(args ...string) ([]string, error) {
+	out, err := exec.Command("pkgutil", args...).Output()
+	if err != nil {
+		return nil, err
+	}
+	var lines []string
+	scanner := bufio.NewScanner(strings.NewReader(string(out)))
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// ParseReceiptInfo reads pkgutil --pkg-info output: "key: value" lines.
+func ParseReceiptInfo(out string) (ReceiptInfo, error) {
+	var info ReceiptInfo
+	for _, line := range strings.Split(out, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "package-id":
+			info.ID = value
+		case "version":
+			info.Version = value
+		case "volume":
+			info.Volume = value
+		case "location":
+			info.Location = value
+		case "install-time":
+			if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+				info.InstallTime = time.Unix(seconds, 0)
+			}
+		}
+	}
+	if info.ID == "" {
+		return info, fmt.Errorf("no package-id in pkgutil output")
+	}
+	if info.Volume == "" {
+		info.Volume = "/"
+	}
+	return info, nil
+}
+
+// payloadPath is where a payload entry of info lives on disk.
+func (info ReceiptInfo) payloadPath(rel string) string {
+	return filepath.Join(info.Volume, info.Location, rel)
+}
+
+// volumeRelative strips info's volume from path, keeping the leading slash.
+func (info ReceiptInfo) volumeRelative(path string) string {
+	if info.Volume == "/" {
+		return path
+	}
+	return strings.TrimPrefix(path, strings.TrimSuffix(info.Volume, "/"))
+}
+
+// insideAny reports whether path lies below one of dirs.
+func insideAny(path string, dirs []string) bool {
+	for _, dir := range dirs {
+		if strings.HasPrefix(path, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// receiptPackage is one package with its payload, while receipts are audited.
+type receiptPackage struct {
+	info   ReceiptInfo
+	files  []string // Absolute
+	dirs   []string // Absolute
+	reason string   // Set when orphaned
+}
+
+// topLevelApps lists the app bundles in a payload, not the ones nested inside another.
+func topLevelApps(dirs []string) []string {
+	var apps []string
+	for _, dir := range dirs {
+		if strings.HasSuffix(dir, ".app") && !strings.Contains(filepath.Dir(dir), ".app/") && !strings.HasSuffix(filepath.Dir(dir), ".app") {
+			apps = append(apps, dir)
+		}
+	}
+	return apps
+}
+
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+// orphanReason says why a package no longer has a reason to keep its receipt: every
+// app it installed was deleted, or nothing it installed is left. Packages still in
+// use return "".
+func orphanReason(pkg receiptPackage) string {
+	if apps := topLevelApps(pkg.dirs); len(apps) > 0 {
+		var names []string
+		for _, app := range apps {
+			if exists(app) {
+				return ""
+			}
+			names = append(names, filepath.Base(app))
+		}
+		return "its app was deleted: " + strings.Join(names, ", ")
+	}
+	for _, file := range pkg.files {
+		if exists(file) {
+			return ""
+		}
+	}
+	return "its payload is gone"
+}
+
+// FindOrphanedReceipts lists the packages whose apps were deleted or whose payload is
+// gone, with the files they left that no package still in use also installed. Apple's
+// own packages are part of macOS and never listed. measure sizes a file; nil counts
+// allocated blocks.
+func FindOrphanedReceipts(db ReceiptDB, measure func(string) int64) ([]Receipt, error) {
+	ids, err := db.Packages()
+	if err != nil {
+		return nil, err
+	}
+	if measure == nil {
+		measure = diskUsage
+	}
+	var packages []receiptPackage
+	for _, id := range ids {
+		if strings.HasPrefix(id, "com.apple.") {
+			continue
+		}
+		info, err := db.Info(id)
+		if err != nil {
+			continue
+		}
+		files, dirs, err := db.Files(id)
+		if err != nil {
+			continue
+		}
+		pkg := receiptPackage{info: info}
+		for _, file := range files {
+			pkg.files = append(pkg.files, info.payloadPath(file))
+		}
+		for _, dir := range dirs {
+			pkg.dirs = append(pkg.dirs, info.payloadPath(dir))
+		}
+		packages = append(packages, pkg)
+	}
+
+	inUse := make(map[string]bool)
+	for i := range packages {
+		packages[i].reason = orphanReason(packages[i])
+		if packages[i].reason == "" {
+			for _, file := range packages[i].files {
+				inUse[file] = true
+			}
+		}
+	}
+
+	// A folder listed by two packages belongs to neither.
+	dirOwners := make(map[string]int)
+	for _, pkg := range packages {
+		for _, dir := range pkg.dirs {
+			dirOwners[dir]++
+		}
+	}
+
+	var receipts []Receipt
+	for _, pkg := range packages {
+		if pkg.reason == "" {
+			continue
+		}
+		receipt := Receipt{ReceiptInfo: pkg.info, Reason: pkg.reason}
+		for _, dir := range pkg.dirs {
+			if dirOwners[dir] == 1 && !sharedFolder(pkg.info.volumeRelative(dir)) {
+				receipt.Dirs = append(receipt.Dirs, dir)
+			}
+		}
+		var leftovers []string
+		for _, file := range pkg.files {
+			if inUse[file] || !exists(file) {
+				continue
+			}
+			if insideAny(file, receipt.Dirs) {
+				leftovers = append(leftovers, file)
+			} else {
+				receipt.Kept = append(receipt.Kept, file)
+			}
+		}
+		for i, size := range measurePaths(leftovers, measure) {
+			receipt.Leftovers = append(receipt.Leftovers, Item{Path: leftovers[i], Size: size})
+			receipt.Size += size
+		}
+		receipts = append(receipts, receipt)
+	}
+	sort.SliceStable(receipts, func(a, b int) bool { return receipts[a].Size > receipts[b].Size })
+	return receipts, nil
+}
+
+// receiptRemovable is the last check before removing installer debris: a clean
+// absolute path in a folder installers write to, never a folder's top level.
+func receiptRemovable(path string) bool {
+	if !filepath.IsAbs(path) || filepath.Clean(path) != path {
+		return false
+	}
+	for _, root := range receiptRoots {
+		if rest, ok := strings.CutPrefix(path, root); ok && rest != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// CleanReceipts removes each receipt's leftovers, then the package's own folders they
+// leave empty, then the receipt itself, or only measures all of it in a dry run. Files
+// in shared folders stay, and so does the receipt that lists them. A receipt is
+// forgotten only once all of its leftovers are gone.
+func CleanReceipts(receipts []Receipt, db ReceiptDB, opts Options) CategoryResult {
+	category := CategoryResult{Name: ReceiptsCategory}
+	remove := opts.Remove
+	if remove == nil {
+		remove = os.RemoveAll
+	}
+	for _, receipt := range receipts {
+		result := TargetResult{Label: receipt.ID}
+		for _, item := range receipt.Leftovers {
+			if opts.Protection.Protects(item.Path) || opts.Whitelist.Match(item.Path) {
+				result.Skipped++
+				continue
+			}
+			if !opts.DryRun && (!receiptRemovable(item.Path) || !insideAny(item.Path, receipt.Dirs) || remove(item.Path) != nil) {
+				result.Failed = append(result.Failed, item.Path)
+				continue
+			}
+			result.Items = append(result.Items, item)
+			result.Size += item.Size
+		}
+
+		if !opts.DryRun {
+			dirs := append([]string(nil), receipt.Dirs...)
+			sort.SliceStable(dirs, func(a, b int) bool { return len(dirs[a]) > len(dirs[b]) })
+			for _, dir := range dirs {
+				if receiptRemovable(dir) {
+					_ = os.Remove(dir) // Fails, as it should, unless empty
+				}
+			}
+		}
+		bom := filepath.Join(ReceiptsDir, receipt.ID+".bom")
+		if len(result.Failed) == 0 && result.Skipped == 0 && len(receipt.Kept) == 0 {
+			if opts.DryRun || db.Forget(receipt.ID) == nil {
+				result.Items = append(result.Items, Item{Path: bom})
+			} else {
+				result.Failed = append(result.Failed, bom)
+			}
+		}
+		if len(result.Items) > 0 || len(result.Failed) > 0 {
+			category.Targets = append(category.Targets, result)
+			category.Size += result.Size
+		}
+	}
+	return category
+}
+
+// Add folds a category cleaned outside Run into the result.
+func (r *Result) Add(category CategoryResult) {
+	for _, t := range category.Targets {
+		r.Items += len(t.Items)
+		r.Skipped += t.Skipped
+		r.Failed += len(t.Failed)
+	}
+	if len(category.Targets) > 0 {
+		r.Categories = append(r.Categories, category)
+		r.Size += category.Size
+	}
+}
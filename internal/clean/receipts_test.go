@@ -0,0 +1,148 @@
+package clean
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakePackage struct {
+	files, dirs []string
+}
+
+func fakeReceiptDB(volume string, packages map[string]fakePackage, forgotten *[]string) ReceiptDB {
+	return ReceiptDB{
+		Packages: func() ([]string, error) {
+			var ids []string
+			for id := range packages {
+				ids = append(ids, id)
+			}
+			return ids, nil
+		},
+		Info: func(id string) (ReceiptInfo, error) {
+			return ReceiptInfo{ID: id, Volume: volume, Location: "/"}, nil
+		},
+		Files: func(id string) ([]string, []string, error) {
+			return packages[id].files, packages[id].dirs, nil
+		},
+		Forget: func(id string) error {
+			*forgotten = append(*forgotten, id)
+			return nil
+		},
+	}
+}
+
+func TestParseReceiptInfo(t *testing.T) {
+	info, err := ParseReceiptInfo("package-id: com.example.tool\nversion: 2.1\nvolume: /\nlocation: Applications\ninstall-time: 1700000000\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.ID != "com.example.tool" || info.Version != "2.1" || info.Location != "Applications" || !info.InstallTime.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("info = %+v", info)
+	}
+	if _, err := ParseReceiptInfo("volume: /\n"); err == nil {
+		t.Error("output without a package-id parsed")
+	}
+}
+
+func TestFindAndCleanOrphanedReceipts(t *testing.T) {
+	volume := t.TempDir()
+	old := receiptRoots
+	receiptRoots = []string{volume + "/"}
+	t.Cleanup(func() { receiptRoots = old })
+
+	writeFile(t, filepath.Join(volume, "Library/LaunchDaemons/com.example.gone.helper.plist"), 4096)
+	writeFile(t, filepath.Join(volume, "Library/Application Support/Example/shared.dat"), 4096)
+	writeFile(t, filepath.Join(volume, "Library/Application Support/Example/Gone/engine.dat"), 8192)
+	writeFile(t, filepath.Join(volume, "Applications/Kept.app/Contents/Info.plist"), 4096)
+	packages := map[string]fakePackage{
+		"com.example.gone": {
+			files: []string{"Applications/Gone.app/Contents/Info.plist", "Library/LaunchDaemons/com.example.gone.helper.plist",
+				"Library/Application Support/Example/shared.dat", "Library/Application Support/Example/Gone/engine.dat"},
+			dirs: []string{"Applications/Gone.app", "Applications/Gone.app/Contents", "Library/LaunchDaemons", "Library/Application Support/Example/Gone"},
+		},
+		"com.example.kept": {
+			files: []string{"Applications/Kept.app/Contents/Info.plist", "Library/Application Support/Example/shared.dat"},
+			dirs:  []string{"Applications/Kept.app", "Applications/Kept.app/Contents"},
+		},
+		"com.example.empty":  {files: []string{"usr/local/bin/tool"}},
+		"com.apple.pkg.Tool": {files: []string{"missing"}},
+	}
+	var forgotten []string
+	db := fakeReceiptDB(volume, packages, &forgotten)
+
+	receipts, err := FindOrphanedReceipts(db, func(string) int64 { return 100 })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(receipts) != 2 || receipts[0].ID != "com.example.gone" || receipts[1].ID != "com.example.empty" {
+		t.Fatalf("receipts = %+v", receipts)
+	}
+	gone := receipts[0]
+	if !strings.Contains(gone.Reason, "Gone.app") || len(gone.Leftovers) != 1 || gone.Size != 100 {
+		t.Errorf("gone = %+v, want only the engine in its own folder", gone)
+	}
+	if len(gone.Kept) != 1 || !strings.HasSuffix(gone.Kept[0], "com.example.gone.helper.plist") {
+		t.Errorf("kept = %v, want the helper in the shared LaunchDaemons folder", gone.Kept)
+	}
+
+	dry := CleanReceipts(receipts, db, Options{DryRun: true})
+	if len(dry.Targets) != 2 || dry.Size != 100 || len(forgotten) != 0 {
+		t.Errorf("dry run = %+v, forgotten %v", dry, forgotten)
+	}
+
+	result := CleanReceipts(receipts, db, Options{})
+	if len(result.Targets) != 2 || len(forgotten) != 1 || forgotten[0] != "com.example.empty" {
+		t.Fatalf("result = %+v, forgotten %v, want the receipt with kept files remembered", result, forgotten)
+	}
+	if _, err := os.Stat(filepath.Join(volume, "Library/LaunchDaemons/com.example.gone.helper.plist")); err != nil {
+		t.Errorf("file in a shared folder removed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(volume, "Library/Application Support/Example/Gone")); !os.IsNotExist(err) {
+		t.Errorf("emptied payload folder kept: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(volume, "Library/Application Support/Example/shared.dat")); err != nil {
+		t.Errorf("file of an installed package removed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(volume, "Library/LaunchDaemons")); err != nil {
+		t.Errorf("shared folder pruned: %v", err)
+	}
+}
+
+func TestReceiptRemovable(t *testing.T) {
+	cases := map[string]bool{
+		"/Library/LaunchDaemons/com.example.plist": true,
+		"/Applications/Example.app":                true,
+		"/Library/":                                false,
+		"/System/Library/Extensions/x.kext":        false,
+		"/usr/bin/tool":                            false,
+		"/Library/../System/x":                     false,
+	}
+	for path, want := range cases {
+		if got := receiptRemovable(path); got != want {
+			t.Errorf("receiptRemovable(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestSharedFolder(t *testing.T) {
+	cases := map[string]bool{
+		"/Library/LaunchDaemons":                          true,
+		"/Library/Application Support":                    true,
+		"/Library/Application Support/Example":            false,
+		"/usr/local/bin":                                  true,
+		"/usr/local/Cellar/tool":                          true,
+		"/opt/example":                                    false,
+		"/Users/alice":                                    true,
+		"/Users/alice/Documents":                          true,
+		"/Users/alice/Library/Preferences":                true,
+		"/Users/alice/Library/Application Support/Vendor": false,
+	}
+	for dir, want := range cases {
+		if got := sharedFolder(dir); got != want {
+			t.Errorf("sharedFolder(%q) = %v, want %v", dir, got, want)
+		}
+	}
+}
@@ -0,0 +1,307 @@
+package clean
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Retention windows from lib/core/base.sh, in days.
+const (
+	tempFileAgeDays      = 7
+	logAgeDays           = 7
+	crashReportAgeDays   = 7
+	traceLogAgeDays      = 30
+	installDataAgeDays   = 30
+	sudoFindDepth        = "5"
+	codeSignScanTimeout  = 5 * time.Second
+	tmDestinationTimeout = 2 * time.Second
+	tmScanDepth          = 3
+	// tmBackupSafeAge keeps incomplete backups this young: Time Machine may resume them.
+	tmBackupSafeAge = 48 * time.Hour
+)
+
+var (
+	systemUpdatesDir = "/Library/Updates"
+	installDataDir   = "/macOS Install Data"
+	snapshotPattern  = regexp.MustCompile(`com\.apple\.TimeMachine\.(\d{4}-\d{2}-\d{2}-\d{6})`)
+)
+
+// sudoSearch is one safe_sudo_find_delete call: files called name under base, older
+// than days.
+type sudoSearch struct {
+	base string
+	name string
+	days int
+}
+
+// sudoFindTarget removes as root what the searches find in folders only root can read.
+func sudoFindTarget(label string, searches ...sudoSearch) Target {
+	return Target{Label: label, Outside: true, Sudo: true, Find: func(opts Options) []string {
+		var paths []string
+		for _, search := range searches {
+			paths = append(paths, sudoFindOld(opts.Tools, search)...)
+		}
+		return paths
+	}}
+}
+
+// sudoFindOld lists as root the files search matches within five levels, refusing a
+// symlinked base like safe_sudo_find_delete.
+func sudoFindOld(tools Tools, search sudoSearch) []string {
+	if _, err := tools.run(sudoTimeout, "sudo", "-n", "test", "-d", search.base); err != nil {
+		return nil
+	}
+	if _, err := tools.run(sudoTimeout, "sudo", "-n", "test", "-L", search.base); err == nil {
+		return nil
+	}
+	args := []string{"-n", "find", search.base, "-maxdepth", sudoFindDepth, "-name", search.name, "-type", "f"}
+	if search.days > 0 {
+		args = append(args, "-mtime", fmt.Sprintf("+%d", search.days))
+	}
+	output, _ := tools.run(sudoTimeout, "sudo", append(args, "-print0")...)
+	return splitNul(output)
+}
+
+func splitNul(output []byte) []string {
+	var paths []string
+	for _, path := range bytes.Split(output, []byte{0}) {
+		if len(path) > 0 {
+			paths = append(paths, string(path))
+		}
+	}
+	return paths
+}
+
+// deepSystemTargets clear old caches, logs and leftovers that only root can remove,
+// then thin local snapshots.
+func deepSystemTargets() []Target {
+	return []Target{
+		sudoFindTarget("System caches",
+			sudoSearch{"/Library/Caches", "*.cache", tempFileAgeDays},
+			sudoSearch{"/Library/Caches", "*.tmp", tempFileAgeDays},
+			sudoSearch{"/Library/Caches", "*.log", logAgeDays}),
+		sudoFindTarget("System temp files",
+			sudoSearch{"/private/tmp", "*", tempFileAgeDays},
+			sudoSearch{"/private/var/tmp", "*", tempFileAgeDays}),
+		sudoFindTarget("System crash reports", sudoSearch{"/Library/Logs/DiagnosticReports", "*", crashReportAgeDays}),
+		sudoFindTarget("System logs",
+			sudoSearch{"/private/var/log", "*.log", logAgeDays},
+			sudoSearch{"/private/var/log", "*.gz", logAgeDays}),
+		{Label: "System library updates", Find: systemUpdates, Outside: true, Sudo: true},
+		{Label: "macOS Install Data", Find: oldInstallData, Outside: true, Sudo: true},
+		{Label: "Browser code signature caches", Find: codeSignClones, Outside: true},
+		sudoFindTarget("System diagnostic logs",
+			sudoSearch{"/private/var/db/diagnostics/Special", "*", logAgeDays},
+			sudoSearch{"/private/var/db/diagnostics/Persist", "*", logAgeDays},
+			sudoSearch{"/private/var/db/DiagnosticPipeline", "*", logAgeDays}),
+		sudoFindTarget("Power logs", sudoSearch{"/private/var/db/powerlog", "*", logAgeDays}),
+		sudoFindTarget("Memory exception reports", sudoSearch{"/private/var/db/reportmemoryexception/MemoryLimitViolations", "*", traceLogAgeDays}),
+		sudoFindTarget("System diagnostic trace logs",
+			sudoSearch{"/private/var/db/diagnostics/Persist", "*.tracev3", traceLogAgeDays},
+			sudoSearch{"/private/var/db/diagnostics/Special", "*.tracev3", traceLogAgeDays}),
+		localSnapshotsTarget(),
+	}
+}
+
+// sipEnabled reads csrutil like is_sip_enabled, assuming protection when it cannot tell.
+func sipEnabled(tools Tools) bool {
+	if !tools.has("csrutil") {
+		return true
+	}
+	output, _ := tools.run(0, "csrutil", "status")
+	return strings.Contains(strings.ToLower(string(output)), "enabled")
+}
+
+// systemUpdates lists downloaded macOS updates, only with System Integrity Protection
+// off and never the ones flagged restricted.
+func systemUpdates(opts Options) []string {
+	if !isDir(systemUpdatesDir) || sipEnabled(opts.Tools) {
+		return nil
+	}
+	var updates []string
+	for _, path := range children(systemUpdatesDir) {
+		flags, err := opts.Tools.run(0, "/usr/bin/stat", "-f%Sf", path)
+		if err == nil && !strings.Contains(string(flags), "restricted") {
+			updates = append(updates, path)
+		}
+	}
+	return updates
+}
+
+// oldInstallData is the leftover macOS installer, once it is a month old.
+func oldInstallData(Options) []string {
+	if isDir(installDataDir) && olderThan(installDataDir, installDataAgeDays*day) {
+		return []string{installDataDir}
+	}
+	return nil
+}
+
+// codeSignClones finds the app copies browsers leave in the per-user temp folders
+// while updating.
+func codeSignClones(opts Options) []string {
+	output, _ := opts.Tools.run(codeSignScanTimeout, "find", "/private/var/folders", "-type", "d", "-name", "*.code_sign_clone", "-path", "*/X/*", "-print0")
+	return splitNul(output)
+}
+
+// localSnapshotsTarget removes every local Time Machine snapshot but the newest, after
+// asking: macOS recreates them, but a dry run cannot show what they free.
+func localSnapshotsTarget() Target {
+	return Target{Label: "Local snapshots", Run: func(opts Options) TargetResult {
+		if !opts.Tools.has("tmutil") {
+			return TargetResult{}
+		}
+		output, err := opts.Tools.run(0, "tmutil", "listlocalsnapshots", "/")
+		if err != nil {
+			return TargetResult{}
+		}
+		var dates []string
+		for _, m := range snapshotPattern.FindAllStringSubmatch(string(output), -1) {
+			dates = append(dates, m[1])
+		}
+		if len(dates) < 2 {
+			return TargetResult{}
+		}
+		// The timestamps sort as text, so the newest is the largest.
+		newest := dates[0]
+		for _, date := range dates[1:] {
+			newest = max(newest, date)
+		}
+		old := len(dates) - 1
+		if opts.DryRun {
+			return TargetResult{Ran: true, Note: fmt.Sprintf("would remove %s, keep the newest", pluralCount(old, "snapshot"))}
+		}
+		if opts.Confirm == nil {
+			return TargetResult{Note: fmt.Sprintf("%s found, skipped without a terminal", pluralCount(len(dates), "snapshot"))}
+		}
+		if !opts.Confirm("Remove all local snapshots except the most recent one?") {
+			return TargetResult{Note: "skipped"}
+		}
+		var result TargetResult
+		removed := 0
+		for _, date := range dates {
+			if date == newest {
+				continue
+			}
+			if _, err := opts.Tools.run(sudoTimeout, "sudo", "-n", "tmutil", "deletelocalsnapshots", date); err != nil {
+				result.Failed = append(result.Failed, "com.apple.TimeMachine."+date)
+				continue
+			}
+			removed++
+		}
+		result.Ran = removed > 0
+		result.Note = fmt.Sprintf("removed %s, kept the newest", pluralCount(removed, "snapshot"))
+		return result
+	}}
+}
+
+// timeMachineTarget removes incomplete Time Machine backups older than two days from
+// local backup disks, through tmutil so the backup database stays consistent.
+func timeMachineTarget() Target {
+	return Target{Label: "Incomplete backups", Run: func(opts Options) TargetResult {
+		var result TargetResult
+		if !opts.Tools.has("tmutil") {
+			return result
+		}
+		info, err := opts.Tools.run(tmDestinationTimeout, "tmutil", "destinationinfo")
+		if err != nil || strings.Contains(string(info), "No destinations configured") {
+			return result
+		}
+		if status, _ := opts.Tools.run(0, "tmutil", "status"); strings.Contains(string(status), "Running = 1") {
+			result.Note = "backup in progress, skipped"
+			return result
+		}
+		for _, backup := range incompleteBackups(opts.Tools) {
+			size := measureWith(opts, backup)
+			if size <= 0 {
+				continue
+			}
+			if !opts.DryRun {
+				if _, err := opts.Tools.run(0, "tmutil", "delete", backup); err != nil {
+					result.Failed = append(result.Failed, backup)
+					continue
+				}
+			}
+			result.Items = append(result.Items, Item{Path: backup, Size: size})
+			result.Size += size
+		}
+		return result
+	}}
+}
+
+// incompleteBackups finds *.inProgress folders old enough to drop on local backup
+// volumes, in Backups.backupdb and in mounted APFS backup bundles.
+func incompleteBackups(tools Tools) []string {
+	var roots []string
+	for _, volume := range children(volumesDir) {
+		if !isDir(volume) || (!isDir(filepath.Join(volume, "Backups.backupdb")) && !isDir(filepath.Join(volume, ".MobileBackups"))) {
+			continue
+		}
+		fsType := volumeFilesystem(tools, volume)
+		if fsType == "" || networkFilesystem(fsType) {
+			continue
+		}
+		roots = append(roots, filepath.Join(volume, "Backups.backupdb"))
+		for _, bundle := range children(volume) {
+			if strings.HasSuffix(bundle, ".backupbundle") || strings.HasSuffix(bundle, ".sparsebundle") {
+				if mount := bundleMount(tools, filepath.Base(bundle)); mount != "" {
+					roots = append(roots, mount)
+				}
+			}
+		}
+	}
+	var backups []string
+	for _, root := range roots {
+		_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || !d.IsDir() || path == root {
+				return nil
+			}
+			if strings.HasSuffix(strings.ToLower(d.Name()), ".inprogress") {
+				if olderThan(path, tmBackupSafeAge) {
+					backups = append(backups, path)
+				}
+				return fs.SkipDir
+			}
+			if depthBelow(root, path) >= tmScanDepth {
+				return fs.SkipDir
+			}
+			return nil
+		})
+	}
+	return backups
+}
+
+func networkFilesystem(fsType string) bool {
+	for _, network := range networkFilesystems {
+		if fsType == network {
+			return true
+		}
+	}
+	return false
+}
+
+// bundleMount finds where hdiutil mounted a backup bundle, or "" when it is not.
+func bundleMount(tools Tools, bundle string) string {
+	output, err := tools.run(0, "hdiutil", "info")
+	if err != nil {
+		return ""
+	}
+	lines := strings.Split(string(output), "\n")
+	for i, line := range lines {
+		if !strings.Contains(line, "image-path") || !strings.Contains(line, bundle) {
+			continue
+		}
+		for _, next := range lines[i+1 : min(i+6, len(lines))] {
+			for _, field := range strings.Fields(next) {
+				if strings.HasPrefix(field, volumesDir+"/") && isDir(field) {
+					return field
+				}
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,195 @@
+package clean
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSystemCategoriesNeedSudo(t *testing.T) {
+	tools := &fakeTools{}
+	categories := []Category{{Name: "Deep system", System: true, Targets: []Target{{Label: "x", Run: func(Options) TargetResult {
+		return TargetResult{Ran: true}
+	}}}}}
+	var reported []string
+	opts := Options{Home: t.TempDir(), Tools: tools.tools(), Report: func(c CategoryResult) { reported = append(reported, c.Name) }}
+	if result := Run(categories, opts); len(result.Categories) != 0 || len(reported) != 0 {
+		t.Errorf("system category ran without sudo: %+v", result)
+	}
+	opts.Sudo = true
+	if result := Run(categories, opts); len(result.Categories) != 1 || len(reported) != 1 {
+		t.Errorf("system category skipped with sudo: %+v", result)
+	}
+}
+
+func TestRemovableOutsideHome(t *testing.T) {
+	cases := map[string]bool{
+		"/Library/Caches/x.cache": true,
+		"/Volumes/Disk/.DS_Store": true,
+		"/System/Library/x":       false,
+		"/usr":                    false,
+		"/private/../etc":         false,
+		"/System/Library/Caches/com.apple.coresymbolicationd/data/x": true,
+	}
+	for path, want := range cases {
+		if got := removable(path, "/Users/me", true); got != want {
+			t.Errorf("removable(%q, outside) = %v, want %v", path, got, want)
+		}
+		if removable(path, "/Users/me", false) {
+			t.Errorf("removable(%q) allowed outside home", path)
+		}
+	}
+}
+
+func TestSudoFindOld(t *testing.T) {
+	tools := &fakeTools{outputs: map[string]string{
+		"sudo -n test -d /private/var/log":                                                "",
+		"sudo -n find /private/var/log -maxdepth 5 -name *.log -type f -mtime +7 -print0": "/private/var/log/a.log\x00/private/var/log/b c.log\x00",
+	}}
+	got := sudoFindOld(tools.tools(), sudoSearch{"/private/var/log", "*.log", 7})
+	if strings.Join(got, "|") != "/private/var/log/a.log|/private/var/log/b c.log" {
+		t.Errorf("sudoFindOld = %q", got)
+	}
+
+	linked := &fakeTools{outputs: map[string]string{"sudo -n test -d /private/tmp": "", "sudo -n test -L /private/tmp": ""}}
+	if got := sudoFindOld(linked.tools(), sudoSearch{"/private/tmp", "*", 7}); got != nil {
+		t.Errorf("searched a symlinked folder: %q", got)
+	}
+}
+
+func TestSudoRemoveRefusesSymlinks(t *testing.T) {
+	dir := t.TempDir()
+	link := filepath.Join(dir, "link")
+	if err := os.Symlink(dir, link); err != nil {
+		t.Fatal(err)
+	}
+	tools := &fakeTools{}
+	if err := sudoRemove(tools.tools(), link); !errors.Is(err, errSymlink) || len(tools.calls) != 0 {
+		t.Errorf("sudoRemove(symlink) = %v, calls = %q", err, tools.calls)
+	}
+
+	file := filepath.Join(dir, "file")
+	writeFile(t, file, 1)
+	tools.outputs = map[string]string{"sudo -n rm -rf -- " + file: ""}
+	if err := sudoRemove(tools.tools(), file); err != nil || len(tools.calls) != 1 {
+		t.Errorf("sudoRemove(file) = %v, calls = %q", err, tools.calls)
+	}
+}
+
+func TestLocalSnapshots(t *testing.T) {
+	listing := "Snapshots for disk /:\ncom.apple.TimeMachine.2025-01-03-101010.local\ncom.apple.TimeMachine.2025-01-05-090000.local\ncom.apple.TimeMachine.2025-01-04-120000.local\n"
+	tools := &fakeTools{outputs: map[string]string{
+		"tmutil listlocalsnapshots /":                           listing,
+		"sudo -n tmutil deletelocalsnapshots 2025-01-03-101010": "",
+		"sudo -n tmutil deletelocalsnapshots 2025-01-04-120000": "",
+	}}
+	snapshots := localSnapshotsTarget()
+
+	if result := snapshots.Run(Options{DryRun: true, Tools: tools.tools()}); !strings.Contains(result.Note, "2 snapshots") {
+		t.Errorf("dry run = %+v", result)
+	}
+	if result := snapshots.Run(Options{Tools: tools.tools()}); result.Ran || !strings.Contains(result.Note, "without a terminal") {
+		t.Errorf("without Confirm = %+v", result)
+	}
+	tools.calls = nil
+	declined := snapshots.Run(Options{Tools: tools.tools(), Confirm: func(string) bool { return false }})
+	if declined.Ran || len(tools.calls) != 1 {
+		t.Errorf("declined = %+v, calls = %q", declined, tools.calls)
+	}
+	result := snapshots.Run(Options{Tools: tools.tools(), Confirm: func(string) bool { return true }})
+	if !result.Ran || len(result.Failed) != 0 || !strings.Contains(result.Note, "removed 2 snapshots") {
+		t.Errorf("confirmed = %+v", result)
+	}
+	for _, call := range tools.calls {
+		if strings.Contains(call, "2025-01-05") {
+			t.Errorf("newest snapshot deleted: %q", tools.calls)
+		}
+	}
+}
+
+func TestTimeMachineSkipsRunningBackup(t *testing.T) {
+	tools := &fakeTools{outputs: map[string]string{
+		"tmutil destinationinfo": "Name : Backup\n",
+		"tmutil status":          "{\n    Running = 1;\n}\n",
+	}}
+	result := timeMachineTarget().Run(Options{Tools: tools.tools()})
+	if result.Note != "backup in progress, skipped" || len(result.Items) != 0 {
+		t.Errorf("result = %+v", result)
+	}
+
+	unconfigured := &fakeTools{outputs: map[string]string{"tmutil destinationinfo": "tmutil: No destinations configured.\n"}}
+	if result := timeMachineTarget().Run(Options{Tools: unconfigured.tools()}); result.Note != "" || len(unconfigured.calls) != 1 {
+		t.Errorf("unconfigured = %+v, calls = %q", result, unconfigured.calls)
+	}
+}
+
+func TestCategoriesForArchitecture(t *testing.T) {
+	names := func(categories []Category) string {
+		var all []string
+		for _, category := range categories {
+			all = append(all, category.Name)
+		}
+		return strings.Join(all, ",")
+	}
+	if strings.Contains(names(categoriesFor(false)), "Apple Silicon") || !strings.Contains(names(categoriesFor(true)), "Apple Silicon updates") {
+		t.Error("Apple Silicon updates not limited to arm64")
+	}
+	for _, category := range UserCategories {
+		for _, target := range category.Targets {
+			if !target.pathOnly() {
+				t.Errorf("%s/%s in UserCategories needs more than its patterns", category.Name, target.Label)
+			}
+		}
+	}
+}
+
+func TestDeepSystemTargets(t *testing.T) {
+	tools := &fakeTools{outputs: map[string]string{
+		"sudo -n test -d /private/var/db/reportmemoryexception/MemoryLimitViolations":                                             "",
+		"sudo -n find /private/var/db/reportmemoryexception/MemoryLimitViolations -maxdepth 5 -name * -type f -mtime +30 -print0": "/private/var/db/reportmemoryexception/MemoryLimitViolations/a.diag\x00",
+		"sudo -n test -d /private/var/db/diagnostics/Persist":                                                                     "",
+		"sudo -n find /private/var/db/diagnostics/Persist -maxdepth 5 -name *.tracev3 -type f -mtime +30 -print0":                 "/private/var/db/diagnostics/Persist/0001.tracev3\x00",
+	}}
+	targets := map[string]Target{}
+	for _, target := range deepSystemTargets() {
+		targets[target.Label] = target
+	}
+	category := Category{Name: "Deep system", System: true, Targets: []Target{targets["Memory exception reports"], targets["System diagnostic trace logs"]}}
+	opts := Options{Home: t.TempDir(), Sudo: true, Tools: tools.tools(), Measure: func(string) int64 { return 1 }}
+
+	result := Run([]Category{category}, opts)
+	if result.Items != 2 || result.Failed != 0 {
+		t.Errorf("deep system = %+v", result)
+	}
+}
+
+func TestSystemUpdatesNeedSIPOff(t *testing.T) {
+	updates := t.TempDir()
+	systemUpdatesDir = updates
+	t.Cleanup(func() { systemUpdatesDir = "/Library/Updates" })
+	for _, name := range []string{"update", "restricted"} {
+		mkdir(t, filepath.Join(updates, name))
+	}
+	flags := map[string]string{
+		"/usr/bin/stat -f%Sf " + filepath.Join(updates, "update"):     "-\n",
+		"/usr/bin/stat -f%Sf " + filepath.Join(updates, "restricted"): "restricted\n",
+	}
+
+	sip := &fakeTools{outputs: map[string]string{"csrutil status": "System Integrity Protection status: enabled.\n"}}
+	for command, output := range flags {
+		sip.outputs[command] = output
+	}
+	if got := systemUpdates(Options{Tools: sip.tools()}); got != nil {
+		t.Errorf("updates with SIP on = %q", got)
+	}
+
+	open := &fakeTools{outputs: map[string]string{"csrutil status": "System Integrity Protection status: disabled.\n"}}
+	for command, output := range flags {
+		open.outputs[command] = output
+	}
+	if got := systemUpdates(Options{Tools: open.tools()}); len(got) != 1 || filepath.Base(got[0]) != "update" {
+		t.Errorf("updates with SIP off = %q, want update only", got)
+	}
+}
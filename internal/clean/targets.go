@@ -0,0 +1,455 @@
+package clean
+
+import (
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Target is one line of mo clean: home-relative or absolute glob patterns whose
+// matches are removed under one label. Find, Skip and Run cover the targets that
+// search, check or call other tools first.
+type Target struct {
+	Label    string
+	Patterns []string
+	// Find adds matches a glob cannot express, such as folders older than a week.
+	Find func(Options) []string
+	// Skip leaves the target alone, with a note when the user should know why.
+	Skip func(Options) (bool, string)
+	// Run replaces matching and removal for targets that drive a tool or only report.
+	Run func(Options) TargetResult
+	// Outside allows matches outside home that deletablePath accepts.
+	Outside bool
+	// Sudo removes matches as root when the run has admin access.
+	Sudo bool
+}
+
+// Category is one section of mo clean and the targets it prints.
+type Category struct {
+	Name    string
+	Targets []Target
+	System  bool // Needs admin access, so only runs with Options.Sudo
+}
+
+func target(label string, patterns ...string) Target {
+	return Target{Label: label, Patterns: patterns}
+}
+
+// pathOnly reports whether t removes nothing but its glob matches in home.
+func (t Target) pathOnly() bool {
+	return t.Find == nil && t.Run == nil && !t.Outside && !t.Sudo
+}
+
+// Categories are the sections of mo clean in the order it runs them.
+var Categories = categoriesFor(runtime.GOARCH == "arm64")
+
+// UserCategories are the sections cleaned without sudo and without running other
+// tools, reduced to their glob targets. mo analyze previews and cleans these.
+var UserCategories = userLevel(Categories)
+
+func userLevel(categories []Category) []Category {
+	var user []Category
+	for _, category := range categories {
+		if category.System {
+			continue
+		}
+		var targets []Target
+		for _, t := range category.Targets {
+			if t.pathOnly() {
+				targets = append(targets, t)
+			}
+		}
+		if len(targets) > 0 {
+			user = append(user, Category{Name: category.Name, Targets: targets})
+		}
+	}
+	return user
+}
+
+func categoriesFor(appleSilicon bool) []Category {
+	categories := []Category{
+		{Name: "Deep system", System: true, Targets: deepSystemTargets()},
+		{Name: "User essentials", Targets: []Target{
+			target("User app cache", "Library/Caches/*"),
+			{Label: "Empty Library folders", Find: emptyLibraryFolders},
+			{Label: "Empty Application Support subdirs", Find: emptySubfolders("Library/Application Support")},
+			{Label: "Empty Caches subdirs", Find: emptySubfolders("Library/Caches")},
+			target("User app logs", "Library/Logs/*"),
+			{Label: "Trash", Patterns: []string{".Trash/*"}, Skip: skipWhitelisted(".Trash")},
+			{Label: "External volume trash", Find: volumeTrashes, Outside: true},
+			{Label: "External volume .DS_Store", Find: volumeDSStores, Skip: skipFinderMetadata(""), Outside: true},
+		}},
+		{Name: "Finder metadata", Targets: []Target{
+			{Label: "Home directory (.DS_Store)", Find: homeDSStores, Skip: skipFinderMetadata("whitelist protected")},
+		}},
+		{Name: "macOS system caches", Targets: []Target{
+			target("Saved application states", "Library/Saved Application State/*"),
+			target("Photo analysis cache", "Library/Caches/com.apple.photoanalysisd"),
+			target("Apple ID cache", "Library/Caches/com.apple.akd"),
+			target("WebKit network cache", "Library/Caches/com.apple.WebKit.Networking/*"),
+			target("Diagnostic reports", "Library/DiagnosticReports/*"),
+			target("QuickLook thumbnails", "Library/Caches/com.apple.QuickLook.thumbnailcache"),
+			target("QuickLook cache", "Library/Caches/Quick Look/*"),
+			target("Icon services cache", "Library/Caches/com.apple.iconservices*"),
+			target("Safari incomplete downloads", "Downloads/*.download"),
+			target("Chrome incomplete downloads", "Downloads/*.crdownload"),
+			target("Partial incomplete downloads", "Downloads/*.part"),
+			target("Autosave information", "Library/Autosave Information/*"),
+			target("Identity caches", "Library/IdentityCaches/*"),
+			target("Siri suggestions cache", "Library/Suggestions/*"),
+			target("Calendar cache", "Library/Calendars/Calendar Cache"),
+			target("Address Book photo cache", "Library/Application Support/AddressBook/Sources/*/Photos.cache"),
+			target("Recent items list", recentItemLists()...),
+			target("Recent items preferences", "Library/Preferences/com.apple.recentitems.plist"),
+			{Label: "Mail attachments", Find: mailAttachments},
+		}},
+		{Name: "Sandboxed app caches", Targets: []Target{
+			target("Wallpaper agent cache", "Library/Containers/com.apple.wallpaper.agent/Data/Library/Caches/*"),
+			target("Media analysis cache", "Library/Containers/com.apple.mediaanalysisd/Data/Library/Caches/*"),
+			target("App Store cache", "Library/Containers/com.apple.AppStore/Data/Library/Caches/*"),
+			target("Apple Configurator temp files", "Library/Containers/com.apple.configurator.xpc.InternetService/Data/tmp/*"),
+			{Label: "Sandboxed app caches", Find: sandboxedCaches},
+		}},
+		{Name: "Browsers", Targets: []Target{
+			target("Safari cache", "Library/Caches/com.apple.Safari/*"),
+			target("Chrome cache", "Library/Caches/Google/Chrome/*"),
+			target("Chrome app cache", "Library/Application Support/Google/Chrome/*/Application Cache/*"),
+			target("Chrome GPU cache", "Library/Application Support/Google/Chrome/*/GPUCache/*"),
+			target("Chromium cache", "Library/Caches/Chromium/*"),
+			target("Edge cache", "Library/Caches/com.microsoft.edgemac/*"),
+			target("Arc cache", "Library/Caches/company.thebrowser.Browser/*"),
+			target("Dia cache", "Library/Caches/company.thebrowser.dia/*"),
+			target("Brave cache", "Library/Caches/BraveSoftware/Brave-Browser/*"),
+			target("Firefox cache", "Library/Caches/Firefox/*"),
+			target("Opera cache", "Library/Caches/com.operasoftware.Opera/*"),
+			target("Vivaldi cache", "Library/Caches/com.vivaldi.Vivaldi/*"),
+			target("Comet cache", "Library/Caches/Comet/*"),
+			target("Orion cache", "Library/Caches/com.kagi.kagimacOS/*"),
+			target("Zen cache", "Library/Caches/zen/*"),
+			target("Firefox profile cache", "Library/Application Support/Firefox/Profiles/*/cache2/*"),
+			{Label: "Chrome old versions", Find: oldFrameworkVersions("Google Chrome", "Google Chrome Framework"), Skip: skipRunning("Google Chrome"), Outside: true, Sudo: true},
+			{Label: "Edge old versions", Find: oldFrameworkVersions("Microsoft Edge", "Microsoft Edge Framework"), Skip: skipRunning("Microsoft Edge"), Outside: true, Sudo: true},
+			{Label: "Edge updater old versions", Find: oldEdgeUpdates, Skip: skipEdgeUpdater},
+		}},
+		{Name: "Cloud storage", Targets: []Target{
+			target("Dropbox cache", "Library/Caches/com.dropbox.*", "Library/Caches/com.getdropbox.dropbox"),
+			target("Google Drive cache", "Library/Caches/com.google.GoogleDrive"),
+			target("Baidu Netdisk cache", "Library/Caches/com.baidu.netdisk"),
+			target("Alibaba Cloud cache", "Library/Caches/com.alibaba.teambitiondisk"),
+			target("Box cache", "Library/Caches/com.box.desktop"),
+			target("OneDrive cache", "Library/Caches/com.microsoft.OneDrive"),
+		}},
+		{Name: "Office applications", Targets: []Target{
+			target("Microsoft Word cache", "Library/Caches/com.microsoft.Word"),
+			target("Microsoft Excel cache", "Library/Caches/com.microsoft.Excel"),
+			target("Microsoft PowerPoint cache", "Library/Caches/com.microsoft.Powerpoint"),
+			target("Microsoft Outlook cache", "Library/Caches/com.microsoft.Outlook/*"),
+			target("Apple iWork cache", "Library/Caches/com.apple.iWork.*"),
+			target("WPS Office cache", "Library/Caches/com.kingsoft.wpsoffice.mac"),
+			target("Thunderbird cache", "Library/Caches/org.mozilla.thunderbird/*"),
+			target("Apple Mail cache", "Library/Caches/com.apple.mail/*"),
+		}},
+		{Name: "Developer tools", Targets: developerToolTargets()},
+		{Name: "Development applications", Targets: developmentAppTargets()},
+		{Name: "Virtual machine tools", Targets: []Target{
+			target("VMware Fusion cache", "Library/Caches/com.vmware.fusion"),
+			target("Parallels cache", "Library/Caches/com.parallels.*"),
+			target("VirtualBox cache", "VirtualBox VMs/.cache"),
+			target("Vagrant temporary files", ".vagrant.d/tmp/*"),
+		}},
+		{Name: "Application Support", Targets: []Target{
+			{Label: "Application Support logs/caches", Find: appSupportLogs, Skip: skipUnreadable("Library/Application Support", "no permission to access Application Support")},
+		}},
+		{Name: "Uninstalled app data", Targets: []Target{
+			{Label: "Orphaned app data", Find: orphanedAppData, Skip: skipUnreadable("Library/Caches", "no permission to access Library folders")},
+		}},
+	}
+	if appleSilicon {
+		categories = append(categories, Category{Name: "Apple Silicon updates", Targets: []Target{
+			{Label: "Rosetta 2 cache", Patterns: []string{"/Library/Apple/usr/share/rosetta/rosetta_update_bundle"}, Outside: true, Sudo: true},
+			target("Rosetta 2 user cache", "Library/Caches/com.apple.rosetta.update"),
+			target("Apple Silicon media service cache", "Library/Caches/com.apple.amp.mediasevicesd"),
+		}})
+	}
+	return append(categories,
+		Category{Name: "iOS device backups", Targets: []Target{iosBackupsTarget()}},
+		Category{Name: "Time Machine incomplete backups", Targets: []Target{timeMachineTarget()}},
+	)
+}
+
+func recentItemLists() []string {
+	var lists []string
+	for _, ext := range []string{"sfl2", "sfl"} {
+		for _, kind := range []string{"RecentApplications", "RecentDocuments", "RecentServers", "RecentHosts"} {
+			lists = append(lists, "Library/Application Support/com.apple.sharedfilelist/com.apple.LSSharedFileList."+kind+"."+ext)
+		}
+	}
+	return lists
+}
+
+// developerToolTargets are the caches of package managers, compilers and SDKs.
+func developerToolTargets() []Target {
+	return []Target{
+		toolTarget("npm cache", "npm", "cache", "clean", "--force"),
+		pnpmCacheTarget(),
+		{Label: "pnpm store", Patterns: []string{pnpmStore + "/*"}, Skip: skipLivePnpmStore},
+		target("tnpm cache directory", ".tnpm/_cacache/*"),
+		target("tnpm logs", ".tnpm/_logs/*"),
+		target("Yarn cache", ".yarn/cache/*"),
+		target("Bun cache", ".bun/install/cache/*"),
+		pipCacheTarget(),
+		target("pyenv cache", ".pyenv/cache/*"),
+		target("Poetry cache", ".cache/poetry/*"),
+		target("uv cache", ".cache/uv/*"),
+		target("Ruff cache", ".cache/ruff/*"),
+		target("MyPy cache", ".cache/mypy/*"),
+		target("Pytest cache", ".pytest_cache/*"),
+		target("Jupyter runtime cache", ".jupyter/runtime/*"),
+		target("Hugging Face cache", ".cache/huggingface/*"),
+		target("PyTorch cache", ".cache/torch/*"),
+		target("TensorFlow cache", ".cache/tensorflow/*"),
+		target("Conda packages cache", ".conda/pkgs/*"),
+		target("Anaconda packages cache", "anaconda3/pkgs/*"),
+		target("Weights & Biases cache", ".cache/wandb/*"),
+		goCacheTarget(),
+		target("Rust cargo cache", ".cargo/registry/cache/*"),
+		target("Cargo git cache", ".cargo/git/*"),
+		target("Rust downloads cache", ".rustup/downloads/*"),
+		dockerCacheTarget(),
+		target("Docker BuildX cache", ".docker/buildx/cache/*"),
+		target("Kubernetes cache", ".kube/cache/*"),
+		target("Container storage temp", ".local/share/containers/storage/tmp/*"),
+		target("AWS CLI cache", ".aws/cli/cache/*"),
+		target("Google Cloud logs", ".config/gcloud/logs/*"),
+		target("Azure CLI logs", ".azure/logs/*"),
+		toolTarget("Nix garbage collection", "nix-collect-garbage", "--delete-older-than", "30d"),
+		target("Git config lock", ".gitconfig.lock"),
+		target("Git config backup", ".gitconfig.bak*"),
+		target("Oh My Zsh cache", ".oh-my-zsh/cache/*"),
+		target("Fish shell backup", ".config/fish/fish_history.bak*"),
+		target("Bash history backup", ".bash_history.bak*"),
+		target("Zsh history backup", ".zsh_history.bak*"),
+		target("pre-commit cache", ".cache/pre-commit/*"),
+		target("TypeScript cache", ".cache/typescript/*"),
+		target("Electron cache", ".cache/electron/*"),
+		target("node-gyp cache", ".cache/node-gyp/*"),
+		target("node-gyp build cache", ".node-gyp/*"),
+		target("Turbo cache", ".turbo/cache/*"),
+		target("Vite cache", ".vite/cache/*"),
+		target("Vite global cache", ".cache/vite/*"),
+		target("Webpack cache", ".cache/webpack/*"),
+		target("Parcel cache", ".parcel-cache/*"),
+		target("ESLint cache", ".cache/eslint/*"),
+		target("Prettier cache", ".cache/prettier/*"),
+		{Label: "Next.js build cache", Find: nextBuildCaches},
+		{Label: "Python bytecode cache", Find: pythonBytecode},
+		androidNDKTarget(),
+		toolTarget("Xcode unavailable simulators", "xcrun", "simctl", "delete", "unavailable"),
+		target("iOS device symbol cache", "Library/Developer/Xcode/iOS DeviceSupport/*/Symbols/System/Library/Caches/*"),
+		target("iOS device support logs", "Library/Developer/Xcode/iOS DeviceSupport/*.log"),
+		target("watchOS device symbol cache", "Library/Developer/Xcode/watchOS DeviceSupport/*/Symbols/System/Library/Caches/*"),
+		target("tvOS device symbol cache", "Library/Developer/Xcode/tvOS DeviceSupport/*/Symbols/System/Library/Caches/*"),
+		target("Simulator runtime cache", "Library/Developer/CoreSimulator/Profiles/Runtimes/*/Contents/Resources/RuntimeRoot/System/Library/Caches/*"),
+		target("Android Studio cache", "Library/Caches/Google/AndroidStudio*/*"),
+		target("CocoaPods cache", "Library/Caches/CocoaPods/*"),
+		target("Flutter cache", ".cache/flutter/*"),
+		target("Android build cache", ".android/build-cache/*"),
+		target("Android SDK cache", ".android/cache/*"),
+		target("Xcode Interface Builder cache", "Library/Developer/Xcode/UserData/IB Support/*"),
+		target("Swift package manager cache", ".cache/swift-package-manager/*"),
+		target("Gradle caches", ".gradle/caches/*"),
+		target("Gradle daemon logs", ".gradle/daemon/*"),
+		target("SBT cache", ".sbt/*"),
+		target("Ivy cache", ".ivy2/cache/*"),
+		target("Ruby Bundler cache", ".bundle/cache/*"),
+		target("PHP Composer cache", ".composer/cache/*"),
+		target("NuGet packages cache", ".nuget/packages/*"),
+		target("Dart Pub cache", ".pub-cache/*"),
+		target("Bazel cache", ".cache/bazel/*"),
+		target("Zig cache", ".cache/zig/*"),
+		target("Deno cache", "Library/Caches/deno/*"),
+		target("Terraform cache", ".cache/terraform/*"),
+		target("Grafana cache", ".grafana/cache/*"),
+		target("Prometheus WAL cache", ".prometheus/data/wal/*"),
+		target("Jenkins workspace cache", ".jenkins/workspace/*/target/*"),
+		target("GitLab Runner cache", ".cache/gitlab-runner/*"),
+		target("GitHub Actions cache", ".github/cache/*"),
+		target("CircleCI cache", ".circleci/cache/*"),
+		target("SonarQube cache", ".sonar/*"),
+		target("Sequel Ace cache", "Library/Caches/com.sequel-ace.sequel-ace/*"),
+		target("Sequel Pro cache", "Library/Caches/com.eggerapps.Sequel-Pro/*"),
+		target("Redis Desktop Manager cache", "Library/Caches/redis-desktop-manager/*"),
+		target("Navicat cache", "Library/Caches/com.navicat.*"),
+		target("DBeaver cache", "Library/Caches/com.dbeaver.*"),
+		target("Redis Insight cache", "Library/Caches/com.redis.RedisInsight"),
+		target("Postman cache", "Library/Caches/com.postmanlabs.mac/*"),
+		target("Insomnia cache", "Library/Caches/com.konghq.insomnia/*"),
+		target("TablePlus cache", "Library/Caches/com.tinyapp.TablePlus/*"),
+		target("Paw API cache", "Library/Caches/com.getpaw.Paw/*"),
+		target("Charles Proxy cache", "Library/Caches/com.charlesproxy.charles/*"),
+		target("Proxyman cache", "Library/Caches/com.proxyman.NSProxy/*"),
+		target("curl cache", ".cache/curl/*"),
+		target("wget cache", ".cache/wget/*"),
+		target("macOS curl cache", "Library/Caches/curl/*"),
+		target("macOS wget cache", "Library/Caches/wget/*"),
+		target("Unity cache", "Library/Caches/com.unity3d.*/*"),
+		target("MongoDB Compass cache", "Library/Caches/com.mongodb.compass/*"),
+		target("Figma cache", "Library/Caches/com.figma.Desktop/*"),
+		target("GitHub Desktop cache", "Library/Caches/com.github.GitHubDesktop/*"),
+		target("Sentry crash reports", "Library/Caches/SentryCrash/*"),
+		target("KSCrash reports", "Library/Caches/KSCrash/*"),
+		target("Crashlytics data", "Library/Caches/com.crashlytics.data/*"),
+		target("Homebrew cache", "Library/Caches/Homebrew/*"),
+		{Label: "Homebrew lock files", Find: brewLocks, Outside: true},
+		homebrewTarget(),
+	}
+}
+
+// developmentAppTargets are the caches of editors, design tools and chat apps.
+func developmentAppTargets() []Target {
+	return []Target{
+		target("Simulator cache", "Library/Developer/CoreSimulator/Caches/*"),
+		target("Simulator temp files", "Library/Developer/CoreSimulator/Devices/*/data/tmp/*"),
+		target("Xcode cache", "Library/Caches/com.apple.dt.Xcode/*"),
+		target("iOS device logs", "Library/Developer/Xcode/iOS Device Logs/*"),
+		target("watchOS device logs", "Library/Developer/Xcode/watchOS Device Logs/*"),
+		target("Xcode build products", "Library/Developer/Xcode/Products/*"),
+		{Label: "Xcode derived data", Patterns: []string{"Library/Developer/Xcode/DerivedData/*"}, Skip: skipXcodeRunning},
+		{Label: "Xcode archives", Patterns: []string{"Library/Developer/Xcode/Archives/*"}, Skip: skipXcodeRunning},
+		target("VS Code logs", "Library/Application Support/Code/logs/*"),
+		target("VS Code cache", "Library/Application Support/Code/Cache/*"),
+		target("VS Code extension cache", "Library/Application Support/Code/CachedExtensions/*"),
+		target("VS Code data cache", "Library/Application Support/Code/CachedData/*"),
+		target("Sublime Text cache", "Library/Caches/com.sublimetext.*/*"),
+		target("Discord cache", "Library/Application Support/discord/Cache/*"),
+		target("Legcord cache", "Library/Application Support/legcord/Cache/*"),
+		target("Slack cache", "Library/Application Support/Slack/Cache/*"),
+		target("Zoom cache", "Library/Caches/us.zoom.xos/*"),
+		target("WeChat cache", "Library/Caches/com.tencent.xinWeChat/*"),
+		target("Telegram cache", "Library/Caches/ru.keepcoder.Telegram/*"),
+		target("Microsoft Teams cache", "Library/Caches/com.microsoft.teams2/*"),
+		target("WhatsApp cache", "Library/Caches/net.whatsapp.WhatsApp/*"),
+		target("Skype cache", "Library/Caches/com.skype.skype/*"),
+		target("Tencent Meeting cache", "Library/Caches/com.tencent.meeting/*"),
+		target("WeCom cache", "Library/Caches/com.tencent.WeWorkMac/*"),
+		target("Feishu cache", "Library/Caches/com.feishu.*/*"),
+		target("DingTalk iDingTalk cache", "Library/Caches/dd.work.exclusive4aliding/*"),
+		target("AliLang security component", "Library/Caches/com.alibaba.AliLang.osx/*"),
+		target("DingTalk logs", "Library/Application Support/iDingTalk/log/*"),
+		target("DingTalk holmes logs", "Library/Application Support/iDingTalk/holmeslogs/*"),
+		target("ChatGPT cache", "Library/Caches/com.openai.chat/*"),
+		target("Claude desktop cache", "Library/Caches/com.anthropic.claudefordesktop/*"),
+		target("Claude logs", "Library/Logs/Claude/*"),
+		target("Sketch cache", "Library/Caches/com.bohemiancoding.sketch3/*"),
+		target("Sketch app cache", "Library/Application Support/com.bohemiancoding.sketch3/cache/*"),
+		target("Adobe cache", "Library/Caches/Adobe/*"),
+		target("Adobe app caches", "Library/Caches/com.adobe.*/*"),
+		target("Figma cache", "Library/Caches/com.figma.Desktop/*"),
+		target("ScreenFlow cache", "Library/Caches/net.telestream.screenflow10/*"),
+		target("Final Cut Pro cache", "Library/Caches/com.apple.FinalCut/*"),
+		target("DaVinci Resolve cache", "Library/Caches/com.blackmagic-design.DaVinciResolve/*"),
+		target("Premiere Pro cache", "Library/Caches/com.adobe.PremierePro.*/*"),
+		target("Blender cache", "Library/Caches/org.blenderfoundation.blender/*"),
+		target("Cinema 4D cache", "Library/Caches/com.maxon.cinema4d/*"),
+		target("Autodesk cache", "Library/Caches/com.autodesk.*/*"),
+		target("SketchUp cache", "Library/Caches/com.sketchup.*/*"),
+		target("MiaoYan cache", "Library/Caches/com.tw93.MiaoYan/*"),
+		target("Klee cache", "Library/Caches/com.klee.desktop/*"),
+		target("Klee desktop cache", "Library/Caches/klee_desktop/*"),
+		target("Ora browser cache", "Library/Caches/com.orabrowser.app/*"),
+		target("Filo cache", "Library/Caches/com.filo.client/*"),
+		target("Flomo cache", "Library/Caches/com.flomoapp.mac/*"),
+		{Label: "Spotify cache", Patterns: []string{"Library/Caches/com.spotify.client/*"}, Skip: skipSpotifyOffline},
+		target("Apple Music cache", "Library/Caches/com.apple.Music"),
+		target("Apple Podcasts cache", "Library/Caches/com.apple.podcasts"),
+		target("Apple TV cache", "Library/Caches/com.apple.TV/*"),
+		target("Plex cache", "Library/Caches/tv.plex.player.desktop"),
+		target("NetEase Music cache", "Library/Caches/com.netease.163music"),
+		target("QQ Music cache", "Library/Caches/com.tencent.QQMusic/*"),
+		target("Kugou Music cache", "Library/Caches/com.kugou.mac/*"),
+		target("Kuwo Music cache", "Library/Caches/com.kuwo.mac/*"),
+		target("IINA cache", "Library/Caches/com.colliderli.iina"),
+		target("VLC cache", "Library/Caches/org.videolan.vlc"),
+		target("MPV cache", "Library/Caches/io.mpv"),
+		target("iQIYI cache", "Library/Caches/com.iqiyi.player"),
+		target("Tencent Video cache", "Library/Caches/com.tencent.tenvideo"),
+		target("Bilibili cache", "Library/Caches/tv.danmaku.bili/*"),
+		target("Douyu cache", "Library/Caches/com.douyu.*/*"),
+		target("Huya cache", "Library/Caches/com.huya.*/*"),
+		target("Aria2 cache", "Library/Caches/net.xmac.aria2gui"),
+		target("Transmission cache", "Library/Caches/org.m0k.transmission"),
+		target("qBittorrent cache", "Library/Caches/com.qbittorrent.qBittorrent"),
+		target("Downie cache", "Library/Caches/com.downie.Downie-*"),
+		target("Folx cache", "Library/Caches/com.folx.*/*"),
+		target("Pacifist cache", "Library/Caches/com.charlessoft.pacifist/*"),
+		target("Steam cache", "Library/Caches/com.valvesoftware.steam/*"),
+		target("Steam web cache", "Library/Application Support/Steam/htmlcache/*"),
+		target("Epic Games cache", "Library/Caches/com.epicgames.EpicGamesLauncher/*"),
+		target("Battle.net cache", "Library/Caches/com.blizzard.Battle.net/*"),
+		target("Battle.net app cache", "Library/Application Support/Battle.net/Cache/*"),
+		target("EA Origin cache", "Library/Caches/com.ea.*/*"),
+		target("GOG Galaxy cache", "Library/Caches/com.gog.galaxy/*"),
+		target("Riot Games cache", "Library/Caches/com.riotgames.*/*"),
+		target("Youdao Dictionary cache", "Library/Caches/com.youdao.YoudaoDict"),
+		target("Eudict cache", "Library/Caches/com.eudic.*"),
+		target("Bob Translation cache", "Library/Caches/com.bob-build.Bob"),
+		target("CleanShot cache", "Library/Caches/com.cleanshot.*"),
+		target("Camo cache", "Library/Caches/com.reincubate.camo"),
+		target("Xnip cache", "Library/Caches/com.xnipapp.xnip"),
+		target("Spark cache", "Library/Caches/com.readdle.smartemail-Mac"),
+		target("Airmail cache", "Library/Caches/com.airmail.*"),
+		target("Todoist cache", "Library/Caches/com.todoist.mac.Todoist"),
+		target("Any.do cache", "Library/Caches/com.any.do.*"),
+		target("Zsh completion cache", ".zcompdump*"),
+		target("less history", ".lesshst"),
+		target("Vim temporary files", ".viminfo.tmp"),
+		target("wget HSTS cache", ".wget-hsts"),
+		target("Input Source Pro cache", "Library/Caches/com.runjuu.Input-Source-Pro/*"),
+		target("WakaTime cache", "Library/Caches/macos-wakatime.WakaTime/*"),
+		target("Notion cache", "Library/Caches/notion.id/*"),
+		target("Obsidian cache", "Library/Caches/md.obsidian/*"),
+		target("Logseq cache", "Library/Caches/com.logseq.*/*"),
+		target("Bear cache", "Library/Caches/com.bear-writer.*/*"),
+		target("Evernote cache", "Library/Caches/com.evernote.*/*"),
+		target("Yinxiang Note cache", "Library/Caches/com.yinxiang.*/*"),
+		target("Alfred cache", "Library/Caches/com.runningwithcrayons.Alfred/*"),
+		target("The Unarchiver cache", "Library/Caches/cx.c3.theunarchiver/*"),
+		target("TeamViewer cache", "Library/Caches/com.teamviewer.*/*"),
+		target("AnyDesk cache", "Library/Caches/com.anydesk.*/*"),
+		target("ToDesk cache", "Library/Caches/com.todesk.*/*"),
+		target("Sunlogin cache", "Library/Caches/com.sunlogin.*/*"),
+	}
+}
+
+// handledFolders are the home folders emptied wholesale by categories, such as
+// Library/Caches for "Library/Caches/*".
+func handledFolders(categories []Category) []string {
+	var folders []string
+	seen := make(map[string]bool)
+	for _, category := range categories {
+		for _, t := range category.Targets {
+			for _, pattern := range t.Patterns {
+				folder, ok := strings.CutSuffix(pattern, "/*")
+				if !ok || strings.ContainsAny(folder, "*?[") || seen[folder] {
+					continue
+				}
+				seen[folder] = true
+				folders = append(folders, folder)
+			}
+		}
+	}
+	return folders
+}
+
+var userHandledFolders = handledFolders(UserCategories)
+
+// Handles reports whether path lies in a folder mo clean empties, in any home.
+// mo analyze uses it to leave such paths to mo clean.
+func Handles(path string) bool {
+	for _, folder := range userHandledFolders {
+		if strings.Contains(path, "/"+filepath.ToSlash(folder)+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,49 @@
+package clean
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestAppCachesAreCleaned puts a file where each app keeps its cache and checks the
+// target of that name finds it.
+func TestAppCachesAreCleaned(t *testing.T) {
+	labels := []string{
+		"Safari cache", "Chrome cache", "Firefox profile cache", "Arc cache",
+		"Dropbox cache", "Google Drive cache", "OneDrive cache",
+		"Microsoft Word cache", "WPS Office cache", "Thunderbird cache",
+		"VMware Fusion cache", "Vagrant temporary files",
+		"Xcode cache", "VS Code logs", "Gradle caches", "Yarn cache",
+		"ChatGPT cache", "Claude desktop cache", "Sketch cache", "Adobe app caches",
+		"DingTalk logs", "DingTalk iDingTalk cache", "Aria2 cache", "Downie cache",
+		"Notion cache", "Obsidian cache", "Todoist cache", "Spark cache",
+		"CleanShot cache", "Final Cut Pro cache", "IINA cache", "Blender cache",
+		"Steam cache", "Youdao Dictionary cache", "Alfred cache", "TeamViewer cache",
+		"Zsh completion cache", "Saved application states",
+	}
+	byLabel := map[string]Target{}
+	for _, category := range categoriesFor(true) {
+		for _, target := range category.Targets {
+			byLabel[target.Label] = target
+		}
+	}
+	for _, label := range labels {
+		target, ok := byLabel[label]
+		if !ok || len(target.Patterns) == 0 {
+			t.Errorf("no %q target", label)
+			continue
+		}
+		home := t.TempDir()
+		path := strings.ReplaceAll(target.Patterns[0], "*", "x")
+		if folder, ok := strings.CutSuffix(target.Patterns[0], "/*"); ok {
+			path = filepath.Join(strings.ReplaceAll(folder, "*", "x"), "x")
+		}
+		writeFile(t, filepath.Join(home, path), 1)
+
+		result := Run([]Category{{Name: "Apps", Targets: []Target{target}}}, Options{Home: home, DryRun: true, Protection: testProtection(t), Tools: (&fakeTools{}).tools()})
+		if result.Items != 1 {
+			t.Errorf("%s did not find %s: %+v", label, path, result)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package clean
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// sudoTimeout bounds one sudo rm or test; sudo -n never waits for a password.
+	sudoTimeout = 2 * time.Minute
+	// dockerCheckTimeout is how long `docker info` may take before the daemon counts
+	// as stopped.
+	dockerCheckTimeout = 3 * time.Second
+	// pnpmStoreTimeout bounds `pnpm store path`.
+	pnpmStoreTimeout = 2 * time.Second
+)
+
+// errTimedOut is what Tools.Run returns for a program that ran past its timeout.
+var errTimedOut = errors.New("timed out")
+
+// Tools runs the programs some targets drive, such as npm, brew and tmutil. Tests
+// replace it; the zero value uses the real ones.
+type Tools struct {
+	// Has reports whether a program is on PATH.
+	Has func(name string) bool
+	// Run runs a program and returns its standard output. A zero timeout waits as
+	// long as it takes.
+	Run func(timeout time.Duration, name string, args ...string) ([]byte, error)
+}
+
+func (t Tools) has(name string) bool {
+	if t.Has != nil {
+		return t.Has(name)
+	}
+	_, err := exec.LookPath(name)
+	return err == nil
+}
+
+func (t Tools) run(timeout time.Duration, name string, args ...string) ([]byte, error) {
+	if t.Run != nil {
+		return t.Run(timeout, name, args...)
+	}
+	return runTool(timeout, name, args...)
+}
+
+func runTool(timeout time.Duration, name string, args ...string) ([]byte, error) {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	cmd := exec.CommandContext(ctx, name, args...)
+	// Corepack would otherwise stop to ask before fetching pnpm.
+	cmd.Env = append(os.Environ(), "COREPACK_ENABLE_DOWNLOAD_PROMPT=0")
+	output, err := cmd.Output()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return output, errTimedOut
+	}
+	return output, err
+}
+
+// running reports whether a process matching pgrep's arguments is up.
+func (t Tools) running(args ...string) bool {
+	_, err := t.run(5*time.Second, "pgrep", args...)
+	return err == nil
+}
+
+// toolTarget runs a program that cleans its own cache, when it is installed. A dry
+// run only says it would.
+func toolTarget(label, name string, args ...string) Target {
+	return Target{Label: label, Run: func(opts Options) TargetResult {
+		var result TargetResult
+		if !opts.Tools.has(name) {
+			return result
+		}
+		if opts.DryRun {
+			result.Ran = true
+			return result
+		}
+		_, err := opts.Tools.run(0, name, args...)
+		result.Ran = err == nil
+		return result
+	}}
+}
+
+// pipCacheTarget purges pip's cache. Like the shell cleaner it counts as done even
+// when pip has nothing to purge and exits non-zero.
+func pipCacheTarget() Target {
+	return Target{Label: "pip cache", Run: func(opts Options) TargetResult {
+		if !opts.Tools.has("pip3") {
+			return TargetResult{}
+		}
+		if !opts.DryRun {
+			_, _ = opts.Tools.run(0, "pip3", "cache", "purge")
+		}
+		return TargetResult{Ran: true}
+	}}
+}
+
+// goCacheTarget empties the module and build caches.
+func goCacheTarget() Target {
+	return Target{Label: "Go cache", Run: func(opts Options) TargetResult {
+		if !opts.Tools.has("go") {
+			return TargetResult{}
+		}
+		if !opts.DryRun {
+			_, _ = opts.Tools.run(0, "go", "clean", "-modcache")
+			_, _ = opts.Tools.run(0, "go", "clean", "-cache")
+		}
+		return TargetResult{Ran: true}
+	}}
+}
+
+// dockerCacheTarget prunes the build cache when the Docker daemon answers.
+func dockerCacheTarget() Target {
+	return Target{Label: "Docker build cache", Run: func(opts Options) TargetResult {
+		if !opts.Tools.has("docker") {
+			return TargetResult{}
+		}
+		if opts.DryRun {
+			return TargetResult{Ran: true}
+		}
+		if _, err := opts.Tools.run(dockerCheckTimeout, "docker", "info"); err != nil {
+			return TargetResult{}
+		}
+		_, err := opts.Tools.run(0, "docker", "builder", "prune", "-af")
+		return TargetResult{Ran: err == nil}
+	}}
+}
+
+// pnpmUsable reports whether pnpm runs, not just a Corepack shim that would download it.
+func pnpmUsable(tools Tools) bool {
+	if !tools.has("pnpm") {
+		return false
+	}
+	_, err := tools.run(0, "pnpm", "--version")
+	return err == nil
+}
+
+// pnpmCacheTarget prunes the store of a working pnpm.
+func pnpmCacheTarget() Target {
+	return Target{Label: "pnpm cache", Run: func(opts Options) TargetResult {
+		if !pnpmUsable(opts.Tools) {
+			return TargetResult{}
+		}
+		if opts.DryRun {
+			return TargetResult{Ran: true}
+		}
+		_, err := opts.Tools.run(0, "pnpm", "store", "prune")
+		return TargetResult{Ran: err == nil}
+	}}
+}
+
+// pnpmStore is where pnpm keeps its store by default, relative to home.
+const pnpmStore = "Library/pnpm/store"
+
+// skipLivePnpmStore keeps the default store when a working pnpm still uses it. With
+// no usable pnpm, or one pointed elsewhere, the default store is only a leftover.
+func skipLivePnpmStore(opts Options) (bool, string) {
+	if !pnpmUsable(opts.Tools) {
+		return false, ""
+	}
+	output, err := opts.Tools.run(pnpmStoreTimeout, "pnpm", "store", "path")
+	store := strings.TrimSpace(string(output))
+	if err != nil || store == "" {
+		return true, ""
+	}
+	return store == filepath.Join(opts.Home, pnpmStore), ""
+}
+
+// androidNDKTarget points at old Android NDK versions, which only the user can judge.
+func androidNDKTarget() Target {
+	return Target{Label: "Android NDK versions", Run: func(opts Options) TargetResult {
+		dir := filepath.Join(opts.Home, "Library", "Android", "sdk", "ndk")
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return TargetResult{}
+		}
+		count := 0
+		for _, entry := range entries {
+			if entry.IsDir() {
+				count++
+			}
+		}
+		if count < 2 {
+			return TargetResult{}
+		}
+		return TargetResult{Note: pluralCount(count, "version") + " found, delete unused ones by hand in " + dir}
+	}}
+}
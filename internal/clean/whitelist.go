@@ -0,0 +1,329 @@
+package clean
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// FinderMetadataSentinel is the whitelist line that keeps .DS_Store files.
+const FinderMetadataSentinel = "FINDER_METADATA"
+
+// defaultWhitelist is used when the user has no whitelist file.
+var defaultWhitelist = []string{
+	"~/Library/Caches/ms-playwright*",
+	"~/.cache/huggingface*",
+	"~/.m2/repository/*",
+	"~/.ollama/models/*",
+	"~/Library/Caches/com.nssurge.surge-mac/*",
+	"~/Library/Application Support/com.nssurge.surge-mac/*",
+	"~/Library/Caches/org.R-project.R/R/renv/*",
+	"~/Library/Caches/pypoetry/virtualenvs*",
+	"~/Library/Caches/JetBrains*",
+	"~/Library/Caches/com.jetbrains.toolbox*",
+	"~/Library/Caches/com.apple.finder",
+	"~/Library/Mobile Documents*",
+	"~/Library/Caches/com.apple.FontRegistry*",
+	"~/Library/Caches/com.apple.spotlight*",
+	"~/Library/Caches/com.apple.Spotlight*",
+	"~/Library/Caches/CloudKit*",
+	FinderMetadataSentinel,
+}
+
+// whitelistHeader opens every whitelist file the editor saves.
+const whitelistHeader = `# Mole Whitelist - Protected paths won't be deleted
+# Default protections: Playwright browsers, HuggingFace models, Maven repo, Ollama models, Surge Mac, R renv, Finder metadata
+# Add one pattern per line to keep items safe.
+`
+
+// WhitelistChoice is one cache the whitelist editor offers to protect.
+type WhitelistChoice struct {
+	Label   string
+	Pattern string // Home-relative with ~, or FinderMetadataSentinel
+}
+
+// WhitelistChoices are the caches listed by mo clean --whitelist, in menu order.
+var WhitelistChoices = []WhitelistChoice{
+	{"Apple Mail cache", "~/Library/Caches/com.apple.mail/*"},
+	{"Gradle build cache (Android Studio, Gradle projects)", "~/.gradle/caches/*"},
+	{"Gradle daemon processes cache", "~/.gradle/daemon/*"},
+	{"Xcode DerivedData (build outputs, indexes)", "~/Library/Developer/Xcode/DerivedData/*"},
+	{"Xcode archives (built app packages)", "~/Library/Developer/Xcode/Archives/*"},
+	{"Xcode internal cache files", "~/Library/Caches/com.apple.dt.Xcode/*"},
+	{"Xcode iOS device support symbols", "~/Library/Developer/Xcode/iOS DeviceSupport/*/Symbols/System/Library/Caches/*"},
+	{"Maven local repository (Java dependencies)", "~/.m2/repository/*"},
+	{"JetBrains IDEs cache (IntelliJ, PyCharm, WebStorm)", "~/Library/Caches/JetBrains/*"},
+	{"Android Studio cache and indexes", "~/Library/Caches/Google/AndroidStudio*/*"},
+	{"Android build cache", "~/.android/build-cache/*"},
+	{"VS Code runtime cache", "~/Library/Application Support/Code/Cache/*"},
+	{"VS Code extension and update cache", "~/Library/Application Support/Code/CachedData/*"},
+	{"VS Code system cache (Cursor, VSCodium)", "~/Library/Caches/com.microsoft.VSCode/*"},
+	{"Cursor editor cache", "~/Library/Caches/com.todesktop.230313mzl4w4u92/*"},
+	{"Bazel build cache", "~/.cache/bazel/*"},
+	{"Go build cache and module cache", "~/Library/Caches/go-build/*"},
+	{"Go module cache", "~/go/pkg/mod/cache/*"},
+	{"Rust Cargo registry cache", "~/.cargo/registry/cache/*"},
+	{"Rust documentation cache", "~/.rustup/toolchains/*/share/doc/*"},
+	{"Rustup toolchain downloads", "~/.rustup/downloads/*"},
+	{"ccache compiler cache", "~/.ccache/*"},
+	{"sccache distributed compiler cache", "~/.cache/sccache/*"},
+	{"SBT Scala build cache", "~/.sbt/*"},
+	{"Ivy dependency cache", "~/.ivy2/cache/*"},
+	{"Turbo monorepo build cache", "~/.turbo/*"},
+	{"Next.js build cache", "~/.next/*"},
+	{"Vite build cache", "~/.vite/*"},
+	{"Parcel bundler cache", "~/.parcel-cache/*"},
+	{"pre-commit hooks cache", "~/.cache/pre-commit/*"},
+	{"Ruff Python linter cache", "~/.cache/ruff/*"},
+	{"MyPy type checker cache", "~/.cache/mypy/*"},
+	{"Pytest test cache", "~/.pytest_cache/*"},
+	{"Flutter SDK cache", "~/.cache/flutter/*"},
+	{"Swift Package Manager cache", "~/.cache/swift-package-manager/*"},
+	{"Zig compiler cache", "~/.cache/zig/*"},
+	{"Deno cache", "~/Library/Caches/deno/*"},
+	{"CocoaPods cache (iOS dependencies)", "~/Library/Caches/CocoaPods/*"},
+	{"npm package cache", "~/.npm/_cacache/*"},
+	{"pip Python package cache", "~/.cache/pip/*"},
+	{"uv Python package cache", "~/.cache/uv/*"},
+	{"R renv global cache (virtual environments)", "~/Library/Caches/org.R-project.R/R/renv/*"},
+	{"Homebrew downloaded packages", "~/Library/Caches/Homebrew/*"},
+	{"Yarn package manager cache", "~/.cache/yarn/*"},
+	{"pnpm package store", "~/.pnpm-store/*"},
+	{"Composer PHP dependencies cache", "~/.composer/cache/*"},
+	{"RubyGems cache", "~/.gem/cache/*"},
+	{"Conda packages cache", "~/.conda/pkgs/*"},
+	{"Anaconda packages cache", "~/anaconda3/pkgs/*"},
+	{"PyTorch model cache", "~/.cache/torch/*"},
+	{"TensorFlow model and dataset cache", "~/.cache/tensorflow/*"},
+	{"HuggingFace models and datasets", "~/.cache/huggingface/*"},
+	{"Playwright browser binaries", "~/Library/Caches/ms-playwright*"},
+	{"Selenium WebDriver binaries", "~/.cache/selenium/*"},
+	{"Ollama local AI models", "~/.ollama/models/*"},
+	{"Weights & Biases ML experiments cache", "~/.cache/wandb/*"},
+	{"Safari web browser cache", "~/Library/Caches/com.apple.Safari/*"},
+	{"Chrome browser cache", "~/Library/Caches/Google/Chrome/*"},
+	{"Firefox browser cache", "~/Library/Caches/Firefox/*"},
+	{"Brave browser cache", "~/Library/Caches/BraveSoftware/Brave-Browser/*"},
+	{"Surge proxy cache", "~/Library/Caches/com.nssurge.surge-mac/*"},
+	{"Surge configuration and data", "~/Library/Application Support/com.nssurge.surge-mac/*"},
+	{"Docker Desktop image cache", "~/Library/Containers/com.docker.docker/Data/*"},
+	{"Podman container cache", "~/.local/share/containers/cache/*"},
+	{"Font cache", "~/Library/Caches/com.apple.FontRegistry/*"},
+	{"Spotlight metadata cache", "~/Library/Caches/com.apple.spotlight/*"},
+	{"CloudKit cache", "~/Library/Caches/CloudKit/*"},
+	{"Trash", "~/.Trash"},
+	{"Finder metadata (.DS_Store)", FinderMetadataSentinel},
+}
+
+var (
+	whitelistLinePattern  = regexp.MustCompile(`^[a-zA-Z0-9/_.@ *-]+$`)
+	protectedSystemRoots  = []string{"/System", "/bin", "/sbin", "/usr/bin", "/usr/sbin", "/etc", "/var/db"}
+	whitelistFileLocation = filepath.Join(".config", "mole", "whitelist")
+)
+
+// Whitelist is the set of paths the user protected from cleaning, as glob patterns
+// where * also matches "/", the way bash [[ == ]] compares them.
+type Whitelist struct {
+	Patterns       []string
+	Warnings       []string // Rejected lines and why
+	FinderMetadata bool     // Keep .DS_Store files
+	matchers       []*regexp.Regexp
+}
+
+func newWhitelist(patterns, warnings []string) Whitelist {
+	w := Whitelist{Warnings: warnings}
+	for _, pattern := range patterns {
+		if pattern == FinderMetadataSentinel {
+			w.FinderMetadata = true
+		}
+		w.Patterns = append(w.Patterns, pattern)
+		w.matchers = append(w.matchers, globRegexp(strings.TrimSuffix(pattern, "/")))
+	}
+	return w
+}
+
+// LoadWhitelist reads ~/.config/mole/whitelist, or the defaults when there is none.
+func LoadWhitelist(home string) Whitelist {
+	file, err := os.Open(WhitelistFile(home))
+	if err != nil {
+		return DefaultWhitelist(home)
+	}
+	defer file.Close()
+	return ParseWhitelist(file, home)
+}
+
+// WhitelistFile is where the whitelist of home is kept.
+func WhitelistFile(home string) string {
+	return filepath.Join(home, whitelistFileLocation)
+}
+
+// ReadWhitelistLines returns the whitelist as the user wrote it, unvalidated and
+// without repeats, or the default patterns when there is no file. The editor keeps
+// lines it does not offer as they are.
+func ReadWhitelistLines(home string) []string {
+	lines := defaultWhitelist
+	if data, err := os.ReadFile(WhitelistFile(home)); err == nil {
+		lines = nil
+		for _, line := range strings.Split(string(data), "\n") {
+			if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+				lines = append(lines, line)
+			}
+		}
+	}
+	return uniquePatterns(lines, home)
+}
+
+// SaveWhitelist writes patterns under the whitelist header, dropping repeats.
+func SaveWhitelist(home string, patterns []string) error {
+	var b strings.Builder
+	b.WriteString(whitelistHeader)
+	if unique := uniquePatterns(patterns, home); len(unique) > 0 {
+		b.WriteString("\n")
+		for _, pattern := range unique {
+			b.WriteString(pattern + "\n")
+		}
+	}
+	path := WhitelistFile(home)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(b.String()), 0644)
+}
+
+// SamePattern reports whether two whitelist lines name the same pattern once ~ is
+// expanded. Globs are compared as text, never expanded.
+func SamePattern(a, b, home string) bool {
+	return expandHome(a, home) == expandHome(b, home)
+}
+
+func uniquePatterns(patterns []string, home string) []string {
+	var unique []string
+	for _, pattern := range patterns {
+		duplicate := false
+		for _, existing := range unique {
+			if SamePattern(pattern, existing, home) {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			unique = append(unique, pattern)
+		}
+	}
+	return unique
+}
+
+// DefaultWhitelist is the whitelist used when the user has not written one.
+func DefaultWhitelist(home string) Whitelist {
+	patterns := make([]string, len(defaultWhitelist))
+	for i, pattern := range defaultWhitelist {
+		patterns[i] = expandHome(pattern, home)
+	}
+	return newWhitelist(patterns, nil)
+}
+
+// ParseWhitelist validates whitelist lines: no "..", only
+// ordinary path characters, absolute, no "//" and nothing at or under a system root.
+func ParseWhitelist(r io.Reader, home string) Whitelist {
+	var patterns, warnings []string
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = expandHome(line, home)
+		if reason := whitelistProblem(line); reason != "" {
+			warnings = append(warnings, reason+": "+line)
+			continue
+		}
+		if !seen[line] {
+			seen[line] = true
+			patterns = append(patterns, line)
+		}
+	}
+	return newWhitelist(patterns, warnings)
+}
+
+func whitelistProblem(line string) string {
+	switch {
+	case strings.Contains(line, ".."):
+		return "Path traversal not allowed"
+	case line == FinderMetadataSentinel:
+		return ""
+	case !whitelistLinePattern.MatchString(line):
+		return "Invalid path format"
+	case !strings.HasPrefix(line, "/"):
+		return "Must be absolute path"
+	case strings.Contains(line, "//"):
+		return "Consecutive slashes"
+	case line == "/":
+		return "Protected system path"
+	}
+	for _, root := range protectedSystemRoots {
+		if line == root || strings.HasPrefix(line, root+"/") {
+			return "Protected system path"
+		}
+	}
+	return ""
+}
+
+// Match reports whether path is protected by a whitelist pattern.
+func (w Whitelist) Match(path string) bool {
+	path = strings.TrimSuffix(path, "/")
+	if path == "" {
+		return false
+	}
+	for i, matcher := range w.matchers {
+		if path == strings.TrimSuffix(w.Patterns[i], "/") || matcher.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+func expandHome(path, home string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		return home + path[1:]
+	}
+	return path
+}
+
+// globRegexp compiles a bash pattern: * matches any run of characters including "/",
+// ? one character, and [...] a class.
+func globRegexp(pattern string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		case '[':
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			class := pattern[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	matcher, err := regexp.Compile(b.String())
+	if err != nil {
+		return regexp.MustCompile("^" + regexp.QuoteMeta(pattern) + "$")
+	}
+	return matcher
+}
@@ -0,0 +1,163 @@
+// Package coordination keeps mo analyze and mo clean out of each other's way through
+// files in the config dir, written by both binaries and lib/core/file_ops.sh:
+//
+//	clean.lock    PID of a mo clean that is removing files
+//	analyze.lock  PID of an analyzer that is deleting
+//	changes.log   "<pid>\t<path>" for every path either side removed
+package coordination
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	CleanLock   = "clean.lock"
+	AnalyzeLock = "analyze.lock"
+	ChangesFile = "changes.log"
+
+	// ChangesMaxSize is where mo clean starts changes.log over.
+	ChangesMaxSize = 1 << 20
+)
+
+// lockGrace covers a lock file created but not yet holding its PID.
+const lockGrace = 5 * time.Second
+
+// Dir returns ~/.config/mole, creating it when missing.
+func Dir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(home, ".config", "mole")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// ProcessAlive reports whether pid is running; EPERM means it exists under another user.
+func ProcessAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// LockHolder returns the process holding the named lock in dir. A lock whose process
+// has exited is stale and reported free.
+func LockHolder(dir, name string) (pid int, held bool) {
+	path := filepath.Join(dir, name)
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, false
+	}
+	pid, err = strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0, time.Since(info.ModTime()) < lockGrace
+	}
+	return pid, ProcessAlive(pid)
+}
+
+// AcquireLock takes the named lock in dir for this process, clearing a stale one. The
+// returned function releases it.
+func AcquireLock(dir, name string) (func(), error) {
+	path := filepath.Join(dir, name)
+	for attempt := 0; attempt < 2; attempt++ {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			fmt.Fprintf(file, "%d\n", os.Getpid())
+			file.Close()
+			return func() { _ = os.Remove(path) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+		if pid, held := LockHolder(dir, name); held {
+			return nil, fmt.Errorf("%s is held by process %d", name, pid)
+		}
+		_ = os.Remove(path)
+	}
+	return nil, fmt.Errorf("%s is busy", name)
+}
+
+// RecordChanges appends removed paths to changes.log for other Mole processes.
+func RecordChanges(dir string, paths []string) {
+	if len(paths) == 0 {
+		return
+	}
+	file, err := os.OpenFile(filepath.Join(dir, ChangesFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+	pid := os.Getpid()
+	var b strings.Builder
+	for _, changed := range paths {
+		fmt.Fprintf(&b, "%d\t%s\n", pid, changed)
+	}
+	_, _ = file.WriteString(b.String())
+}
+
+// TrimChanges starts changes.log over once it outgrows ChangesMaxSize. Readers notice
+// the shorter file and restart from the top.
+func TrimChanges(dir string) {
+	path := filepath.Join(dir, ChangesFile)
+	if info, err := os.Stat(path); err == nil && info.Size() > ChangesMaxSize {
+		_ = os.Truncate(path, 0)
+	}
+}
+
+// ChangesEnd is where following changes.log starts: only later changes matter.
+func ChangesEnd(dir string) int64 {
+	info, err := os.Stat(filepath.Join(dir, ChangesFile))
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
+// ReadChanges returns paths other processes recorded after offset and the offset to
+// resume from. A file shorter than offset was trimmed, which restarts reading.
+func ReadChanges(dir string, offset int64) ([]string, int64) {
+	file, err := os.Open(filepath.Join(dir, ChangesFile))
+	if err != nil {
+		return nil, 0
+	}
+	defer file.Close()
+	if info, err := file.Stat(); err == nil && info.Size() < offset {
+		offset = 0
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return nil, offset
+	}
+	own := strconv.Itoa(os.Getpid())
+	var paths []string
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			// A partial last line is read again once its writer finishes it.
+			break
+		}
+		offset += int64(len(line))
+		pid, changed, ok := strings.Cut(strings.TrimSuffix(line, "\n"), "\t")
+		if ok && pid != own && filepath.IsAbs(changed) {
+			paths = append(paths, filepath.Clean(changed))
+		}
+	}
+	return paths, offset
+}
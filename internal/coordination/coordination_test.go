@@ -0,0 +1,57 @@
+package coordination
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAcquireLockReplacesStaleHolder(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, CleanLock), []byte("999999\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, held := LockHolder(dir, CleanLock); held {
+		t.Fatalf("a lock left by an exited process should be stale")
+	}
+	release, err := AcquireLock(dir, CleanLock)
+	if err != nil {
+		t.Fatalf("stale lock should be replaced: %v", err)
+	}
+	if pid, held := LockHolder(dir, CleanLock); !held || pid != os.Getpid() {
+		t.Fatalf("expected this process to hold the lock, got %d %v", pid, held)
+	}
+	if _, err := AcquireLock(dir, CleanLock); err == nil {
+		t.Fatalf("a held lock should not be taken twice")
+	}
+	release()
+	if _, held := LockHolder(dir, CleanLock); held {
+		t.Fatalf("released lock still held")
+	}
+}
+
+func TestTrimChangesRestartsReaders(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ChangesFile)
+	line := "1\t/Users/me/Library/Caches/app\n"
+	if err := os.WriteFile(path, []byte(strings.Repeat(line, ChangesMaxSize/len(line)+1)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	offset := ChangesEnd(dir)
+
+	TrimChanges(dir)
+	if ChangesEnd(dir) != 0 {
+		t.Fatalf("expected an oversized changes.log to start over")
+	}
+	RecordChanges(dir, []string{"/Users/me/own"})
+	if paths, _ := ReadChanges(dir, offset); len(paths) != 0 {
+		t.Fatalf("own changes should be skipped, got %v", paths)
+	}
+	if err := os.WriteFile(path, []byte(line), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if paths, _ := ReadChanges(dir, offset); len(paths) != 1 || paths[0] != "/Users/me/Library/Caches/app" {
+		t.Fatalf("expected reading to restart after trimming, got %v", paths)
+	}
+}
@@ -0,0 +1,31 @@
+// Package humanize formats byte counts the way every Mole binary prints them.
+package humanize
+
+import "fmt"
+
+// Scale splits size into a value below 1024 and its binary unit, "B" through "EB".
+// Negative sizes count as zero.
+func Scale(size int64) (float64, string) {
+	if size < 0 {
+		return 0, "B"
+	}
+	const unit = 1024
+	if size < unit {
+		return float64(size), "B"
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return float64(size) / float64(div), string("KMGTPE"[exp]) + "B"
+}
+
+// Bytes renders size with one decimal, such as "2.0 MB", and whole bytes below 1 KB.
+func Bytes(size int64) string {
+	value, unit := Scale(size)
+	if unit == "B" {
+		return fmt.Sprintf("%d B", int64(value))
+	}
+	return fmt.Sprintf("%.1f %s", value, unit)
+}
@@ -0,0 +1,21 @@
+package humanize
+
+import "testing"
+
+func TestBytes(t *testing.T) {
+	tests := []struct {
+		size int64
+		want string
+	}{
+		{-1, "0 B"},
+		{512, "512 B"},
+		{1536, "1.5 KB"},
+		{2 << 20, "2.0 MB"},
+		{3 << 40, "3.0 TB"},
+	}
+	for _, tc := range tests {
+		if got := Bytes(tc.size); got != tc.want {
+			t.Errorf("Bytes(%d) = %q, want %q", tc.size, got, tc.want)
+		}
+	}
+}
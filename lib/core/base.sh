@@ -52,35 +52,10 @@ readonly MOLE_LOG_AGE_DAYS=7             # Log retention (days)
 readonly MOLE_CRASH_REPORT_AGE_DAYS=7    # Crash report retention (days)
 readonly MOLE_SAVED_STATE_AGE_DAYS=30    # Saved state retention (days) - increased for safety
 readonly MOLE_TM_BACKUP_SAFE_HOURS=48    # TM backup safety window (hours)
-readonly MOLE_MAX_DS_STORE_FILES=500     # Max .DS_Store files to clean per scan
-readonly MOLE_MAX_ORPHAN_ITERATIONS=100  # Max iterations for orphaned app data scan
 
 # ============================================================================
 # Whitelist Configuration
 # ============================================================================
-readonly FINDER_METADATA_SENTINEL="FINDER_METADATA"
-declare -a DEFAULT_WHITELIST_PATTERNS=(
-    "$HOME/Library/Caches/ms-playwright*"
-    "$HOME/.cache/huggingface*"
-    "$HOME/.m2/repository/*"
-    "$HOME/.ollama/models/*"
-    "$HOME/Library/Caches/com.nssurge.surge-mac/*"
-    "$HOME/Library/Application Support/com.nssurge.surge-mac/*"
-    "$HOME/Library/Caches/org.R-project.R/R/renv/*"
-    "$HOME/Library/Caches/pypoetry/virtualenvs*"
-    "$HOME/Library/Caches/JetBrains*"
-    "$HOME/Library/Caches/com.jetbrains.toolbox*"
-    "$HOME/Library/Caches/com.apple.finder"
-    "$HOME/Library/Mobile Documents*"
-    # System-critical caches that affect macOS functionality and stability
-    # CRITICAL: Removing these will cause system search and UI issues
-    "$HOME/Library/Caches/com.apple.FontRegistry*"
-    "$HOME/Library/Caches/com.apple.spotlight*"
-    "$HOME/Library/Caches/com.apple.Spotlight*"
-    "$HOME/Library/Caches/CloudKit*"
-    "$FINDER_METADATA_SENTINEL"
-)
-
 declare -a DEFAULT_OPTIMIZE_WHITELIST_PATTERNS=(
     "check_brew_health"
     "check_touchid"
@@ -6,6 +6,9 @@ MOLE_COMMANDS=(
     "uninstall:Remove apps completely"
     "optimize:Check and maintain system"
     "analyze:Explore disk usage"
+    "agent:Share disk usage with a paired Mac"
+    "cache:Encrypt or wipe cached scan results"
+    "daemon:Keep scan results fresh in the background"
     "status:Monitor system health"
     "purge:Remove old project artifacts"
     "touchid:Configure Touch ID for sudo"
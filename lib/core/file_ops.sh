@@ -75,6 +75,19 @@ validate_path_for_deletion() {
     return 0
 }
 
+# ============================================================================
+# Coordination with mo analyze
+# ============================================================================
+
+# Shared with internal/coordination/coordination.go: changes.log gets "<pid><TAB><path>"
+# for every removed path so a running analyzer refreshes.
+readonly MOLE_CHANGES_FILE="${HOME}/.config/mole/changes.log"
+
+# Record a removed path for running analyzers
+record_state_change() {
+    printf '%s\t%s\n' "$$" "$1" >> "$MOLE_CHANGES_FILE" 2> /dev/null || true
+}
+
 # ============================================================================
 # Safe Removal Operations
 # ============================================================================
@@ -109,6 +122,7 @@ safe_remove() {
     error_msg=$(rm -rf "$path" 2>&1) || rm_exit=$? # safe_remove
 
     if [[ $rm_exit -eq 0 ]]; then
+        record_state_change "$path"
         return 0
     else
         # Check if it's a permission error
@@ -155,6 +169,7 @@ safe_sudo_remove() {
 
     # Perform the deletion
     if sudo rm -rf "$path" 2> /dev/null; then # SAFE: safe_sudo_remove implementation
+        record_state_change "$path"
         return 0
     else
         log_error "Failed to remove (sudo): $path"
@@ -172,7 +172,7 @@ else
 fi
 
 ((TOTAL_CHECKS++))
-if grep -q "Consecutive slashes" bin/clean.sh; then
+if grep -q "Consecutive slashes" internal/clean/whitelist.go; then
     echo -e "${GREEN}  ${ICON_SUCCESS} Path validation enhanced${NC}"
     ((OPTIMIZATION_SCORE++))
 else
@@ -52,6 +52,10 @@ echo ""
 
 echo "2. Running unit tests..."
 if command -v bats > /dev/null 2>&1 && [ -d "tests" ]; then
+    # mo clean runs the Go cleaner, so build it for the CLI tests.
+    if command -v go > /dev/null 2>&1; then
+        go build -o bin/clean-go ./cmd/clean > /dev/null 2>&1 || true
+    fi
     if [[ -z "${TERM:-}" ]]; then
         export TERM="xterm-256color"
     fi
@@ -114,7 +118,7 @@ echo ""
 
 echo "3. Running Go tests..."
 if command -v go > /dev/null 2>&1; then
-    if go build ./... > /dev/null 2>&1 && go vet ./cmd/... > /dev/null 2>&1 && go test ./cmd/... > /dev/null 2>&1; then
+    if go build ./... > /dev/null 2>&1 && go vet ./... > /dev/null 2>&1 && go test ./... > /dev/null 2>&1; then
         printf "${GREEN}${ICON_SUCCESS} Go tests passed${NC}\n"
     else
         printf "${RED}${ICON_ERROR} Go tests failed${NC}\n"
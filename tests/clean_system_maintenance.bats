@@ -20,256 +20,6 @@ teardown_file() {
     fi
 }
 
-@test "clean_deep_system issues safe sudo deletions" {
-    run bash --noprofile --norc <<'EOF'
-set -euo pipefail
-CALL_LOG="$HOME/system_calls.log"
-> "$CALL_LOG"
-source "$PROJECT_ROOT/lib/core/common.sh"
-source "$PROJECT_ROOT/lib/clean/system.sh"
-
-sudo() { return 0; }
-safe_sudo_find_delete() {
-    echo "safe_sudo_find_delete:$1:$2" >> "$CALL_LOG"
-    return 0
-}
-safe_sudo_remove() {
-    echo "safe_sudo_remove:$1" >> "$CALL_LOG"
-    return 0
-}
-log_success() { :; }
-start_section_spinner() { :; }
-stop_section_spinner() { :; }
-is_sip_enabled() { return 1; }
-get_file_mtime() { echo 0; }
-get_path_size_kb() { echo 0; }
-find() { return 0; }
-run_with_timeout() { shift; "$@"; }
-
-clean_deep_system
-cat "$CALL_LOG"
-EOF
-
-    [ "$status" -eq 0 ]
-    [[ "$output" == *"/Library/Caches"* ]]
-    [[ "$output" == *"/private/tmp"* ]]
-    [[ "$output" == *"/private/var/log"* ]]
-}
-
-@test "clean_deep_system skips /Library/Updates when SIP enabled" {
-    run bash --noprofile --norc <<'EOF'
-set -euo pipefail
-CALL_LOG="$HOME/system_calls_skip.log"
-> "$CALL_LOG"
-source "$PROJECT_ROOT/lib/core/common.sh"
-source "$PROJECT_ROOT/lib/clean/system.sh"
-
-sudo() { return 0; }
-safe_sudo_find_delete() { return 0; }
-safe_sudo_remove() {
-    echo "REMOVE:$1" >> "$CALL_LOG"
-    return 0
-}
-log_success() { :; }
-start_section_spinner() { :; }
-stop_section_spinner() { :; }
-is_sip_enabled() { return 0; } # SIP enabled -> skip removal
-find() { return 0; }
-run_with_timeout() { shift; "$@"; }
-
-clean_deep_system
-cat "$CALL_LOG"
-EOF
-
-    [ "$status" -eq 0 ]
-    [[ "$output" != *"/Library/Updates"* ]]
-}
-
-@test "clean_time_machine_failed_backups exits when tmutil has no destinations" {
-    run bash --noprofile --norc <<'EOF'
-set -euo pipefail
-source "$PROJECT_ROOT/lib/core/common.sh"
-source "$PROJECT_ROOT/lib/clean/system.sh"
-
-tmutil() {
-    if [[ "$1" == "destinationinfo" ]]; then
-        echo "No destinations configured"
-        return 0
-    fi
-    return 0
-}
-pgrep() { return 1; }
-find() { return 0; }
-
-clean_time_machine_failed_backups
-EOF
-
-    [ "$status" -eq 0 ]
-    [[ "$output" == *"No incomplete backups found"* ]]
-}
-
-@test "clean_local_snapshots skips in non-interactive mode" {
-    run bash --noprofile --norc <<'EOF'
-set -euo pipefail
-source "$PROJECT_ROOT/lib/core/common.sh"
-source "$PROJECT_ROOT/lib/clean/system.sh"
-
-tmutil() {
-    if [[ "$1" == "listlocalsnapshots" ]]; then
-        printf '%s\n' \
-            "com.apple.TimeMachine.2023-10-25-120000" \
-            "com.apple.TimeMachine.2023-10-24-120000"
-        return 0
-    fi
-    return 0
-}
-start_section_spinner(){ :; }
-stop_section_spinner(){ :; }
-
-DRY_RUN="false"
-clean_local_snapshots
-EOF
-
-    [ "$status" -eq 0 ]
-    [[ "$output" == *"skipping non-interactive mode"* ]]
-    [[ "$output" != *"Removed snapshot"* ]]
-}
-
-@test "clean_local_snapshots keeps latest in dry-run" {
-    run bash --noprofile --norc <<'EOF'
-set -euo pipefail
-source "$PROJECT_ROOT/lib/core/common.sh"
-source "$PROJECT_ROOT/lib/clean/system.sh"
-
-tmutil() {
-    if [[ "$1" == "listlocalsnapshots" ]]; then
-        printf '%s\n' \
-            "com.apple.TimeMachine.2023-10-25-120000" \
-            "com.apple.TimeMachine.2023-10-25-130000" \
-            "com.apple.TimeMachine.2023-10-24-120000"
-        return 0
-    fi
-    return 0
-}
-start_section_spinner(){ :; }
-stop_section_spinner(){ :; }
-note_activity(){ :; }
-
-DRY_RUN="true"
-clean_local_snapshots
-EOF
-
-    [ "$status" -eq 0 ]
-    [[ "$output" == *"Local snapshot: com.apple.TimeMachine.2023-10-25-120000"* ]]
-    [[ "$output" == *"Local snapshot: com.apple.TimeMachine.2023-10-24-120000"* ]]
-    [[ "$output" != *"Local snapshot: com.apple.TimeMachine.2023-10-25-130000"* ]]
-}
-
-@test "clean_local_snapshots uses read fallback when read_key missing" {
-    if ! command -v script > /dev/null 2>&1; then
-        skip "script not available"
-    fi
-
-    local tmp_script="$BATS_TEST_TMPDIR/clean_local_snapshots_fallback.sh"
-    cat > "$tmp_script" <<'EOF'
-set -euo pipefail
-source "$PROJECT_ROOT/lib/core/common.sh"
-source "$PROJECT_ROOT/lib/clean/system.sh"
-
-tmutil() {
-    if [[ "$1" == "listlocalsnapshots" ]]; then
-        printf '%s\n' \
-            "com.apple.TimeMachine.2023-10-25-120000" \
-            "com.apple.TimeMachine.2023-10-24-120000"
-        return 0
-    fi
-    return 0
-}
-start_section_spinner(){ :; }
-stop_section_spinner(){ :; }
-note_activity(){ :; }
-
-unset -f read_key
-
-CALL_LOG="$HOME/snapshot_calls.log"
-> "$CALL_LOG"
-sudo() { echo "sudo:$*" >> "$CALL_LOG"; return 0; }
-
-DRY_RUN="false"
-clean_local_snapshots
-cat "$CALL_LOG"
-EOF
-
-    run bash --noprofile --norc -c "printf '\n' | script -q /dev/null bash \"$tmp_script\""
-
-    [ "$status" -eq 0 ]
-    [[ "$output" == *"Skipped"* ]]
-}
-
-
-@test "clean_homebrew skips when cleaned recently" {
-    run bash --noprofile --norc <<'EOF'
-set -euo pipefail
-source "$PROJECT_ROOT/lib/core/common.sh"
-source "$PROJECT_ROOT/lib/clean/brew.sh"
-
-mkdir -p "$HOME/.cache/mole"
-date +%s > "$HOME/.cache/mole/brew_last_cleanup"
-
-brew() { return 0; }
-
-clean_homebrew
-EOF
-
-    [ "$status" -eq 0 ]
-    [[ "$output" == *"cleaned"* ]]
-}
-
-@test "clean_homebrew runs cleanup with timeout stubs" {
-    run bash --noprofile --norc <<'EOF'
-set -euo pipefail
-source "$PROJECT_ROOT/lib/core/common.sh"
-source "$PROJECT_ROOT/lib/clean/brew.sh"
-
-mkdir -p "$HOME/.cache/mole"
-rm -f "$HOME/.cache/mole/brew_last_cleanup"
-
-    start_inline_spinner(){ :; }
-    stop_inline_spinner(){ :; }
-    note_activity(){ :; }
-    run_with_timeout() {
-        local duration="$1"
-        shift
-        if [[ "$1" == "du" ]]; then
-            echo "51201 $3"
-            return 0
-        fi
-        "$@"
-    }
-
-    brew() {
-        case "$1" in
-            cleanup)
-            echo "Removing: package"
-            return 0
-            ;;
-        autoremove)
-            echo "Uninstalling pkg"
-            return 0
-            ;;
-        *)
-            return 0
-            ;;
-    esac
-}
-
-    clean_homebrew
-EOF
-
-    [ "$status" -eq 0 ]
-    [[ "$output" == *"Homebrew cleanup"* ]]
-}
-
 @test "check_appstore_updates is skipped for performance" {
     run bash --noprofile --norc <<'EOF'
 set -euo pipefail
@@ -609,65 +359,6 @@ EOF
 }
 
 
-@test "clean_deep_system cleans memory exception reports" {
-    run bash --noprofile --norc <<'EOF'
-set -euo pipefail
-CALL_LOG="$HOME/memory_exception_calls.log"
-> "$CALL_LOG"
-source "$PROJECT_ROOT/lib/core/common.sh"
-source "$PROJECT_ROOT/lib/clean/system.sh"
-
-sudo() { return 0; }
-safe_sudo_find_delete() {
-    echo "safe_sudo_find_delete:$1:$2:$3:$4" >> "$CALL_LOG"
-    return 0
-}
-safe_sudo_remove() { return 0; }
-log_success() { :; }
-is_sip_enabled() { return 1; }
-find() { return 0; }
-run_with_timeout() { shift; "$@"; }
-
-clean_deep_system
-cat "$CALL_LOG"
-EOF
-
-    [ "$status" -eq 0 ]
-    [[ "$output" == *"reportmemoryexception/MemoryLimitViolations"* ]]
-    [[ "$output" == *":30:"* ]]  # 30-day retention
-}
-
-@test "clean_deep_system cleans diagnostic trace logs" {
-    run bash --noprofile --norc <<'EOF'
-set -euo pipefail
-CALL_LOG="$HOME/diag_calls.log"
-> "$CALL_LOG"
-source "$PROJECT_ROOT/lib/core/common.sh"
-source "$PROJECT_ROOT/lib/clean/system.sh"
-
-sudo() { return 0; }
-safe_sudo_find_delete() {
-    echo "safe_sudo_find_delete:$1:$2" >> "$CALL_LOG"
-    return 0
-}
-safe_sudo_remove() { return 0; }
-log_success() { :; }
-start_section_spinner() { :; }
-stop_section_spinner() { :; }
-is_sip_enabled() { return 1; }
-find() { return 0; }
-run_with_timeout() { shift; "$@"; }
-
-clean_deep_system
-cat "$CALL_LOG"
-EOF
-
-    [ "$status" -eq 0 ]
-    [[ "$output" == *"diagnostics/Persist"* ]]
-    [[ "$output" == *"diagnostics/Special"* ]]
-    [[ "$output" == *"tracev3"* ]]
-}
-
 @test "clean_deep_system validates symbolication cache size before cleaning" {
     run bash --noprofile --norc <<'EOF'
 set -euo pipefail
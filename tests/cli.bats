@@ -91,6 +91,7 @@ setup() {
 }
 
 @test "mo clean --debug creates debug log file" {
+    [[ -x "$PROJECT_ROOT/bin/clean-go" ]] || skip "clean-go binary not built"
     mkdir -p "$HOME/.config/mole"
     run env HOME="$HOME" TERM="xterm-256color" MOLE_TEST_MODE=1 MO_DEBUG=1 "$PROJECT_ROOT/mole" clean --dry-run
     [ "$status" -eq 0 ]
@@ -106,6 +107,7 @@ setup() {
 }
 
 @test "mo clean without debug does not show debug log path" {
+    [[ -x "$PROJECT_ROOT/bin/clean-go" ]] || skip "clean-go binary not built"
     mkdir -p "$HOME/.config/mole"
     run env HOME="$HOME" TERM="xterm-256color" MOLE_TEST_MODE=1 MO_DEBUG=0 "$PROJECT_ROOT/mole" clean --dry-run
     [ "$status" -eq 0 ]
@@ -114,6 +116,7 @@ setup() {
 }
 
 @test "mo clean --debug logs system info" {
+    [[ -x "$PROJECT_ROOT/bin/clean-go" ]] || skip "clean-go binary not built"
     mkdir -p "$HOME/.config/mole"
     run env HOME="$HOME" TERM="xterm-256color" MOLE_TEST_MODE=1 MO_DEBUG=1 "$PROJECT_ROOT/mole" clean --dry-run
     [ "$status" -eq 0 ]
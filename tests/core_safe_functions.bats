@@ -90,6 +90,16 @@ teardown() {
     [ "$status" -eq 0 ]
 }
 
+@test "safe_remove records removed paths for mo analyze" {
+    local test_file="$TEST_DIR/recorded.txt"
+    echo "test" > "$test_file"
+    rm -f "$HOME/.config/mole/changes.log"
+
+    run bash -c "source '$PROJECT_ROOT/lib/core/common.sh'; safe_remove '$test_file' true"
+    [ "$status" -eq 0 ]
+    grep -q "	$test_file\$" "$HOME/.config/mole/changes.log"
+}
+
 @test "safe_remove in silent mode suppresses error output" {
     run bash -c "source '$PROJECT_ROOT/lib/core/common.sh'; safe_remove '/System/test' true 2>&1"
     [ "$status" -eq 1 ]
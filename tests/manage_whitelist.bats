@@ -23,7 +23,7 @@ teardown_file() {
 setup() {
     rm -rf "$HOME/.config"
     mkdir -p "$HOME"
-    WHITELIST_PATH="$HOME/.config/mole/whitelist"
+    WHITELIST_PATH="$HOME/.config/mole/whitelist_optimize"
 }
 
 @test "patterns_equivalent treats paths with tilde expansion as equal" {
@@ -47,7 +47,7 @@ setup() {
 }
 
 @test "save_whitelist_patterns keeps unique entries and preserves header" {
-    HOME="$HOME" bash --noprofile --norc -c "source '$PROJECT_ROOT/lib/manage/whitelist.sh'; save_whitelist_patterns \"\$HOME/.cache/foo\" \"\$HOME/.cache/foo\" \"\$HOME/.cache/bar\""
+    HOME="$HOME" bash --noprofile --norc -c "source '$PROJECT_ROOT/lib/manage/whitelist.sh'; save_whitelist_patterns optimize check_foo check_foo check_bar"
 
     [[ -f "$WHITELIST_PATH" ]]
 
@@ -56,14 +56,15 @@ setup() {
         lines+=("$line")
     done < "$WHITELIST_PATH"
     [ "${#lines[@]}" -ge 4 ]
-    occurrences=$(grep -c "$HOME/.cache/foo" "$WHITELIST_PATH")
+    [[ "${lines[0]}" == "# Mole Optimization Whitelist"* ]]
+    occurrences=$(grep -c "check_foo" "$WHITELIST_PATH")
     [ "$occurrences" -eq 1 ]
 }
 
 @test "load_whitelist falls back to defaults when config missing" {
     rm -f "$WHITELIST_PATH"
-    HOME="$HOME" bash --noprofile --norc -c "source '$PROJECT_ROOT/lib/manage/whitelist.sh'; rm -f \"\$HOME/.config/mole/whitelist\"; load_whitelist; printf '%s\n' \"\${CURRENT_WHITELIST_PATTERNS[@]}\"" > "$HOME/current_whitelist.txt"
-    HOME="$HOME" bash --noprofile --norc -c "source '$PROJECT_ROOT/lib/manage/whitelist.sh'; printf '%s\n' \"\${DEFAULT_WHITELIST_PATTERNS[@]}\"" > "$HOME/default_whitelist.txt"
+    HOME="$HOME" bash --noprofile --norc -c "source '$PROJECT_ROOT/lib/manage/whitelist.sh'; load_whitelist optimize; printf '%s\n' \"\${CURRENT_WHITELIST_PATTERNS[@]}\"" > "$HOME/current_whitelist.txt"
+    HOME="$HOME" bash --noprofile --norc -c "source '$PROJECT_ROOT/lib/manage/whitelist.sh'; printf '%s\n' \"\${DEFAULT_OPTIMIZE_WHITELIST_PATTERNS[@]}\"" > "$HOME/default_whitelist.txt"
 
     current=()
     while IFS= read -r line; do
@@ -76,19 +77,19 @@ setup() {
     done < "$HOME/default_whitelist.txt"
 
     [ "${#current[@]}" -eq "${#defaults[@]}" ]
-    [ "${current[0]}" = "${defaults[0]/\$HOME/$HOME}" ]
+    [ "${current[0]}" = "${defaults[0]}" ]
 }
 
 @test "is_whitelisted matches saved patterns exactly" {
     local status
-    if HOME="$HOME" bash --noprofile --norc -c "source '$PROJECT_ROOT/lib/manage/whitelist.sh'; save_whitelist_patterns \"\$HOME/.cache/unique-pattern\"; load_whitelist; is_whitelisted \"\$HOME/.cache/unique-pattern\""; then
+    if HOME="$HOME" bash --noprofile --norc -c "source '$PROJECT_ROOT/lib/manage/whitelist.sh'; save_whitelist_patterns optimize check_unique; load_whitelist optimize; is_whitelisted check_unique"; then
         status=0
     else
         status=$?
     fi
     [ "$status" -eq 0 ]
 
-    if HOME="$HOME" bash --noprofile --norc -c "source '$PROJECT_ROOT/lib/manage/whitelist.sh'; save_whitelist_patterns \"\$HOME/.cache/unique-pattern\"; load_whitelist; is_whitelisted \"\$HOME/.cache/other-pattern\""; then
+    if HOME="$HOME" bash --noprofile --norc -c "source '$PROJECT_ROOT/lib/manage/whitelist.sh'; save_whitelist_patterns optimize check_unique; load_whitelist optimize; is_whitelisted check_other"; then
         status=0
     else
         status=$?
@@ -97,6 +98,7 @@ setup() {
 }
 
 @test "mo clean --whitelist persists selections" {
+    [[ -x "$PROJECT_ROOT/bin/clean-go" ]] || skip "clean-go binary not built"
     whitelist_file="$HOME/.config/mole/whitelist"
     mkdir -p "$(dirname "$whitelist_file")"
 